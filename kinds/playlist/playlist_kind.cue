@@ -22,6 +22,9 @@ lineage: schemas: [{
 			// The ordered list of items that the playlist will iterate over.
 			// FIXME! This should not be optional, but changing it makes the godegen awkward
 			items?: [...#PlaylistItem]
+
+			// Schedule of the playlist, the times when it is the active playlist.
+			schedule?: #PlaylistSchedule
 		} @cuetsy(kind="interface")
 
 		///////////////////////////////////////
@@ -42,6 +45,43 @@ lineage: schemas: [{
 
 			// Title is an unused property -- it will be removed in the future
 			title?: string
+
+			// Interval overrides the interval of the playlist for the dashboards of this item.
+			interval?: string
+
+			// TimeRange overrides the time range of the dashboards of this item.
+			timeRange?: #PlaylistTimeRange
+
+			// Variables overrides the values of the variables of the dashboards of this item, by variable name.
+			variables?: [string]: [...string]
+		} @cuetsy(kind="interface")
+
+		#PlaylistTimeRange: {
+			// Start of the time range, for example now-6h.
+			from: string
+			// End of the time range, for example now.
+			to: string
+		} @cuetsy(kind="interface")
+
+		// A playlist is active at the times of its cron expression, during its duration, and during its time windows.
+		#PlaylistSchedule: {
+			// Cron expression of the times the playlist becomes active, in the standard five fields format.
+			cron?: string
+			// Duration the playlist stays active after each time of the cron expression, for example 2h.
+			duration?: string
+			// Time windows during which the playlist is active.
+			timeWindows?: [...#PlaylistTimeWindow]
+			// Timezone of the cron expression and the time windows, for example Europe/Paris. Defaults to UTC.
+			timezone?: string
+		} @cuetsy(kind="interface")
+
+		#PlaylistTimeWindow: {
+			// Days of the week of the time window, for example monday. Every day when empty.
+			weekdays?: [...string]
+			// Start time of the time window, formatted as 15:04.
+			start: string
+			// End time of the time window, formatted as 15:04. A time window that ends before it starts ends on the next day.
+			end: string
 		} @cuetsy(kind="interface")
 	}
 }]
@@ -128,11 +128,18 @@ export type { LibraryPanel } from './veneer/librarypanel.types';
 // Raw generated types from Playlist kind.
 export type {
   Playlist,
-  PlaylistItem
+  PlaylistItem,
+  PlaylistTimeRange,
+  PlaylistSchedule,
+  PlaylistTimeWindow
 } from './raw/playlist/x/playlist_types.gen';
 
 // Raw generated enums and default consts from playlist kind.
-export { defaultPlaylist } from './raw/playlist/x/playlist_types.gen';
+export {
+  defaultPlaylistSchedule,
+  defaultPlaylistTimeWindow,
+  defaultPlaylist
+} from './raw/playlist/x/playlist_types.gen';
 
 // Raw generated types from Preferences kind.
 export type {
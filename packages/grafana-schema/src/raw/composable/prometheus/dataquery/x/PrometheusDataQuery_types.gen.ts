@@ -20,6 +20,25 @@ export enum QueryEditorMode {
 
 export type PromQueryFormat = ('time_series' | 'table' | 'heatmap');
 
+export enum ExemplarSamplingStrategy {
+  None = 'none',
+  Outlier = 'outlier',
+  Stddev = 'stddev',
+  Uniform = 'uniform',
+}
+
+export interface ExemplarSampling {
+  /**
+   * Maximum number of exemplars returned for the query, no limit when not set
+   */
+  maxExemplars?: number;
+  /**
+   * Strategy used to sample the exemplars, "stddev" keeps the exemplars at least two standard deviations apart,
+   * "uniform" keeps one exemplar per time interval, "outlier" keeps the exemplars furthest from the mean
+   */
+  strategy?: ExemplarSamplingStrategy;
+}
+
 export interface PrometheusDataQuery extends common.DataQuery {
   /**
    * Specifies which editor is being used to prepare the query. It can be "code" or "builder"
@@ -29,6 +48,10 @@ export interface PrometheusDataQuery extends common.DataQuery {
    * Execute an additional query to identify interesting raw samples relevant for the given expr
    */
   exemplar?: boolean;
+  /**
+   * Sampling applied to the exemplars of the query to reduce their density
+   */
+  exemplarSampling?: ExemplarSampling;
   /**
    * The actual expression/query that will be evaluated by Prometheus
    */
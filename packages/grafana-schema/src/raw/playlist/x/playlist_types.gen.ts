@@ -9,6 +9,14 @@
 // Run 'make gen-cue' from repository root to regenerate.
 
 export interface PlaylistItem {
+  /**
+   * Interval overrides the interval of the playlist for the dashboards of this item.
+   */
+  interval?: string;
+  /**
+   * TimeRange overrides the time range of the dashboards of this item.
+   */
+  timeRange?: PlaylistTimeRange;
   /**
    * Title is an unused property -- it will be removed in the future
    */
@@ -28,8 +36,68 @@ export interface PlaylistItem {
    *  - dashboard_by_uid: The value is the dashboard UID
    */
   value: string;
+  /**
+   * Variables overrides the values of the variables of the dashboards of this item, by variable name.
+   */
+  variables?: Record<string, Array<string>>;
+}
+
+export interface PlaylistTimeRange {
+  /**
+   * Start of the time range, for example now-6h.
+   */
+  from: string;
+  /**
+   * End of the time range, for example now.
+   */
+  to: string;
+}
+
+/**
+ * A playlist is active at the times of its cron expression, during its duration, and during its time windows.
+ */
+export interface PlaylistSchedule {
+  /**
+   * Cron expression of the times the playlist becomes active, in the standard five fields format.
+   */
+  cron?: string;
+  /**
+   * Duration the playlist stays active after each time of the cron expression, for example 2h.
+   */
+  duration?: string;
+  /**
+   * Time windows during which the playlist is active.
+   */
+  timeWindows?: Array<PlaylistTimeWindow>;
+  /**
+   * Timezone of the cron expression and the time windows, for example Europe/Paris. Defaults to UTC.
+   */
+  timezone?: string;
+}
+
+export const defaultPlaylistSchedule: Partial<PlaylistSchedule> = {
+  timeWindows: [],
+};
+
+export interface PlaylistTimeWindow {
+  /**
+   * End time of the time window, formatted as 15:04. A time window that ends before it starts ends on the next day.
+   */
+  end: string;
+  /**
+   * Start time of the time window, formatted as 15:04.
+   */
+  start: string;
+  /**
+   * Days of the week of the time window, for example monday. Every day when empty.
+   */
+  weekdays?: Array<string>;
 }
 
+export const defaultPlaylistTimeWindow: Partial<PlaylistTimeWindow> = {
+  weekdays: [],
+};
+
 export interface Playlist {
   /**
    * Interval sets the time between switching views in a playlist.
@@ -45,6 +113,10 @@ export interface Playlist {
    * Name of the playlist.
    */
   name: string;
+  /**
+   * Schedule of the playlist, the times when it is the active playlist.
+   */
+  schedule?: PlaylistSchedule;
   /**
    * Unique playlist identifier. Generated on creation, either by the
    * creator of the playlist of by the application.
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	skv "github.com/grafana/grafana/pkg/services/secrets/kvstore"
 )
 
@@ -25,6 +27,17 @@ func (hs *HTTPServer) AdminReEncryptEncryptionKeys(c *contextmodel.ReqContext) r
 	return response.Respond(http.StatusOK, "Data encryption keys re-encrypted successfully")
 }
 
+func (hs *HTTPServer) AdminRotateKeyEncryptionKey(c *contextmodel.ReqContext) response.Response {
+	if err := hs.SecretsService.RotateKeyEncryptionKey(c.Req.Context()); err != nil {
+		if errors.Is(err, secrets.ErrKeyRotationNotSupported) {
+			return response.Error(http.StatusBadRequest, "Current encryption provider does not support key rotation", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to rotate key encryption key", err)
+	}
+
+	return response.Respond(http.StatusOK, "Key encryption key rotated successfully")
+}
+
 func (hs *HTTPServer) AdminReEncryptSecrets(c *contextmodel.ReqContext) response.Response {
 	success, err := hs.secretsMigrator.ReEncryptSecrets(c.Req.Context())
 	if err != nil {
@@ -38,6 +51,21 @@ func (hs *HTTPServer) AdminReEncryptSecrets(c *contextmodel.ReqContext) response
 	return response.Respond(http.StatusOK, "Secrets re-encrypted successfully")
 }
 
+func (hs *HTTPServer) AdminStartSecretsReEncryption(c *contextmodel.ReqContext) response.Response {
+	if err := hs.secretsMigrator.StartReEncryption(); err != nil {
+		if errors.Is(err, secrets.ErrReEncryptionRunning) {
+			return response.Error(http.StatusConflict, "Secrets re-encryption is already running", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to start secrets re-encryption", err)
+	}
+
+	return response.Respond(http.StatusAccepted, "Secrets re-encryption started")
+}
+
+func (hs *HTTPServer) AdminGetSecretsReEncryptionStatus(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.secretsMigrator.ReEncryptionStatus())
+}
+
 func (hs *HTTPServer) AdminRollbackSecrets(c *contextmodel.ReqContext) response.Response {
 	success, err := hs.secretsMigrator.RollBackSecrets(c.Req.Context())
 	if err != nil {
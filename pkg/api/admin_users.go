@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -188,6 +189,8 @@ func (hs *HTTPServer) AdminUpdateUserPermissions(c *contextmodel.ReqContext) res
 		return response.Error(500, "Failed to update user permissions", err)
 	}
 
+	hs.logAuditEvent(c, audit.ActionSetRole, audit.Resource(0, audit.KindUser, strconv.FormatInt(userID, 10)), nil, audit.Summary{"isGrafanaAdmin": form.IsGrafanaAdmin})
+
 	return response.Success("User permissions updated")
 }
 
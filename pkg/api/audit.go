@@ -0,0 +1,24 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/infra/grn"
+	"github.com/grafana/grafana/pkg/services/audit"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+// auditEnabled returns whether events of the action are recorded.
+func (hs *HTTPServer) auditEnabled(action audit.Action) bool {
+	return hs.auditService != nil && hs.auditService.Enabled(action)
+}
+
+// logAuditEvent records an action of the signed in user, a no-op when the server is created without an audit service, e.g. in tests.
+func (hs *HTTPServer) logAuditEvent(c *contextmodel.ReqContext, action audit.Action, resource grn.GRN, before, after audit.Summary) {
+	if hs.auditService == nil {
+		return
+	}
+
+	event := audit.NewEvent(c.SignedInUser, c.Req, action, resource)
+	event.Before = before
+	event.After = after
+	hs.auditService.Log(c.Req.Context(), event)
+}
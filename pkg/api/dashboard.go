@@ -20,8 +20,12 @@ import (
 	"github.com/grafana/grafana/pkg/kinds/dashboard"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -229,6 +233,10 @@ func (hs *HTTPServer) GetDashboard(c *contextmodel.ReqContext) response.Response
 		Meta:      meta,
 	}
 
+	if hs.dashboardUsageService != nil {
+		hs.dashboardUsageService.RecordView(c.Req.Context(), &dashboardusage.RecordViewCommand{OrgID: c.OrgID, DashboardUID: dash.UID})
+	}
+
 	c.TimeRequest(metrics.MApiDashboardGet)
 	return response.JSON(http.StatusOK, dto)
 }
@@ -334,6 +342,11 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 		return response.Error(500, "Failed to delete dashboard", err)
 	}
 
+	hs.logAuditEvent(c, audit.ActionDelete, audit.Resource(c.OrgID, audit.KindDashboard, dash.UID), audit.Summary{
+		"title":   dash.Title,
+		"version": dash.Version,
+	}, nil)
+
 	if hs.Live != nil {
 		err := hs.Live.GrafanaScope.Dashboards.DashboardDeleted(c.OrgID, c.ToUserDisplayDTO(), dash.UID)
 		if err != nil {
@@ -445,6 +458,46 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		allowUiUpdate = hs.ProvisioningService.GetAllowUIUpdatesFromConfig(provisioningData.Name)
 	}
 
+	var lintProblems []dashboardlint.Problem
+	if hs.dashboardLintService != nil && !dash.IsFolder {
+		result, err := hs.dashboardLintService.Lint(ctx, &dashboardlint.LintQuery{
+			OrgID:     c.OrgID,
+			FolderID:  dash.FolderID,
+			FolderUID: cmd.FolderUID,
+			Dashboard: dash.Data,
+			User:      c.SignedInUser,
+		})
+		switch {
+		case err != nil:
+			// linting must not prevent saving dashboards
+			hs.log.Warn("Failed to lint dashboard", "uid", dash.UID, "error", err)
+		case result.Blocked:
+			return response.JSON(http.StatusPreconditionFailed, util.DynMap{
+				"status":   "lint-failed",
+				"message":  "The dashboard has lint problems that block saving it",
+				"problems": result.Problems,
+			})
+		default:
+			lintProblems = result.Problems
+		}
+	}
+
+	// changes to dashboards that require approval are saved as drafts, provisioned dashboards that cannot be
+	// changed from the UI are still refused by SaveDashboard
+	if hs.dashboardDraftService != nil && allowUiUpdate {
+		requiresApproval, err := hs.dashboardDraftService.RequiresApproval(ctx, &dashboarddraft.RequiresApprovalQuery{
+			OrgID:        c.OrgID,
+			DashboardID:  dash.ID,
+			DashboardUID: dash.UID,
+		})
+		if err != nil {
+			return response.Error(500, "Error while checking if the dashboard requires approval", err)
+		}
+		if requiresApproval {
+			return hs.postDashboardDraft(c, dash, cmd)
+		}
+	}
+
 	dashItem := &dashboards.SaveDashboardDTO{
 		Dashboard: dash,
 		Message:   cmd.Message,
@@ -495,14 +548,61 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		return response.Error(500, "Error while connecting library panels", err)
 	}
 
+	// the version of the request may be stale when overwriting, the version of the saved dashboard is the stored
+	// version plus one, and dashboards overwritten by UID are updates too
+	action, before := audit.ActionCreate, audit.Summary(nil)
+	if dashboard.Version > 1 {
+		action, before = audit.ActionUpdate, audit.Summary{"version": dashboard.Version - 1}
+	}
+	hs.logAuditEvent(c, action, audit.Resource(c.OrgID, audit.KindDashboard, dashboard.UID), before, audit.Summary{
+		"title":     dashboard.Title,
+		"version":   dashboard.Version,
+		"folderUid": cmd.FolderUID,
+	})
+
 	c.TimeRequest(metrics.MApiDashboardSave)
-	return response.JSON(http.StatusOK, util.DynMap{
+	result := util.DynMap{
 		"status":  "success",
 		"slug":    dashboard.Slug,
 		"version": dashboard.Version,
 		"id":      dashboard.ID,
 		"uid":     dashboard.UID,
 		"url":     dashboard.GetURL(),
+	}
+	if len(lintProblems) > 0 {
+		result["lint"] = lintProblems
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// postDashboardDraft saves the changes of a dashboard that requires approval as a draft. The dashboard is
+// unchanged until the draft is approved.
+func (hs *HTTPServer) postDashboardDraft(c *contextmodel.ReqContext, dash *dashboards.Dashboard, cmd dashboards.SaveDashboardCommand) response.Response {
+	draft, err := hs.dashboardDraftService.CreateDraft(c.Req.Context(), &dashboarddraft.CreateDraftCommand{
+		Dashboard: dash,
+		Message:   cmd.Message,
+		Overwrite: cmd.Overwrite,
+		User:      c.SignedInUser,
+	})
+	if err != nil {
+		var dashboardErr dashboards.DashboardErr
+		if errors.As(err, &dashboardErr) {
+			return apierrors.ToDashboardErrorResponse(c.Req.Context(), hs.pluginStore, err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save dashboard draft", err)
+	}
+
+	hs.logAuditEvent(c, audit.ActionCreate, audit.Resource(c.OrgID, audit.KindDashboardDraft, strconv.FormatInt(draft.ID, 10)), nil, audit.Summary{
+		"dashboardUid":  draft.DashboardUID,
+		"parentVersion": draft.ParentVersion,
+	})
+
+	return response.JSON(http.StatusAccepted, util.DynMap{
+		"status":  "pending",
+		"message": "The dashboard requires approval, the changes were saved as a draft",
+		"draftId": draft.ID,
+		"uid":     draft.DashboardUID,
+		"version": draft.ParentVersion,
 	})
 }
 
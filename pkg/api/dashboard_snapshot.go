@@ -190,6 +190,45 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) respon
 	return nil
 }
 
+// swagger:route POST /snapshots/capture snapshots captureDashboardSnapshot
+//
+// Create a snapshot of a dashboard with the data of its panels queried by the server.
+//
+// The queries of the panels are run with the permissions of the user. When `recaptureInterval` is set, the snapshot is captured again periodically with the permissions the user has at that time.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) CaptureDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.CaptureDashboardSnapshotCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.User = c.SignedInUser
+
+	result, err := hs.dashboardsnapshotsService.CaptureDashboardSnapshot(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to capture snapshot", err)
+	}
+
+	metrics.MApiDashboardSnapshotCreate.Inc()
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"key":       result.Key,
+		"deleteKey": result.DeleteKey,
+		"url":       setting.ToAbsUrl("dashboard/snapshot/" + result.Key),
+		"deleteUrl": setting.ToAbsUrl("api/snapshots-delete/" + result.DeleteKey),
+		"id":        result.ID,
+	})
+}
+
 // GET /api/snapshots/:key
 // swagger:route GET /snapshots/{key} snapshots getDashboardSnapshot
 //
@@ -429,16 +468,17 @@ func (hs *HTTPServer) SearchDashboardSnapshots(c *contextmodel.ReqContext) respo
 	dto := make([]*dashboardsnapshots.DashboardSnapshotDTO, len(searchQueryResult))
 	for i, snapshot := range searchQueryResult {
 		dto[i] = &dashboardsnapshots.DashboardSnapshotDTO{
-			ID:          snapshot.ID,
-			Name:        snapshot.Name,
-			Key:         snapshot.Key,
-			OrgID:       snapshot.OrgID,
-			UserID:      snapshot.UserID,
-			External:    snapshot.External,
-			ExternalURL: snapshot.ExternalURL,
-			Expires:     snapshot.Expires,
-			Created:     snapshot.Created,
-			Updated:     snapshot.Updated,
+			ID:            snapshot.ID,
+			Name:          snapshot.Name,
+			Key:           snapshot.Key,
+			OrgID:         snapshot.OrgID,
+			UserID:        snapshot.UserID,
+			External:      snapshot.External,
+			ExternalURL:   snapshot.ExternalURL,
+			Expires:       snapshot.Expires,
+			Created:       snapshot.Created,
+			Updated:       snapshot.Updated,
+			NextCaptureAt: snapshot.NextCaptureAt,
 		}
 	}
 
@@ -452,6 +492,13 @@ type CreateSnapshotParams struct {
 	Body dashboardsnapshots.CreateDashboardSnapshotCommand `json:"body"`
 }
 
+// swagger:parameters captureDashboardSnapshot
+type CaptureSnapshotParams struct {
+	// in:body
+	// required:true
+	Body dashboardsnapshots.CaptureDashboardSnapshotCommand `json:"body"`
+}
+
 // swagger:parameters searchDashboardSnapshots
 type GetSnapshotsParams struct {
 	// Search Query
@@ -371,6 +371,63 @@ func TestGetDashboardSnapshotFailure(t *testing.T) {
 		}, sqlmock)
 }
 
+func TestHTTPServer_CaptureDashboardSnapshot(t *testing.T) {
+	setup := func(t *testing.T, snapshotEnabled bool, svc dashboardsnapshots.Service) *webtest.Server {
+		t.Helper()
+
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.Cfg.SnapshotEnabled = snapshotEnabled
+			hs.dashboardsnapshotsService = svc
+		})
+	}
+
+	captureRequest := func(server *webtest.Server, body string) *http.Request {
+		req := server.NewPostRequest("/api/snapshots/capture", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1})
+	}
+
+	t.Run("should return 403 when snapshots are disabled", func(t *testing.T) {
+		server := setup(t, false, dashboardsnapshots.NewMockService(t))
+
+		res, err := server.Send(captureRequest(server, `{"dashboardUid":"dash1"}`))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should return the keys of the captured snapshot", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("CaptureDashboardSnapshot", mock.Anything, mock.MatchedBy(func(cmd *dashboardsnapshots.CaptureDashboardSnapshotCommand) bool {
+			return cmd.DashboardUID == "dash1" && cmd.RecaptureInterval == "24h" && cmd.User.UserID == 1
+		})).Return(&dashboardsnapshots.DashboardSnapshot{ID: 3, Key: "key", DeleteKey: "deleteKey"}, nil)
+		server := setup(t, true, svc)
+
+		res, err := server.Send(captureRequest(server, `{"dashboardUid":"dash1","recaptureInterval":"24h"}`))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		body, err := simplejson.NewFromReader(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "key", body.Get("key").MustString())
+		assert.Equal(t, "deleteKey", body.Get("deleteKey").MustString())
+		assert.Equal(t, int64(3), body.Get("id").MustInt64())
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should return 403 when the user cannot view the dashboard", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("CaptureDashboardSnapshot", mock.Anything, mock.Anything).Return(nil, dashboardsnapshots.ErrCaptureAccessDenied.Errorf("denied"))
+		server := setup(t, true, svc)
+
+		res, err := server.Send(captureRequest(server, `{"dashboardUid":"dash1"}`))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func buildHttpServer(d dashboardsnapshots.Service, snapshotEnabled bool) *HTTPServer {
 	hs := &HTTPServer{
 		dashboardsnapshotsService: d,
@@ -33,6 +33,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/annotations/annotationstest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboards/database"
 	"github.com/grafana/grafana/pkg/services/dashboards/service"
@@ -1156,7 +1158,7 @@ func (hs *HTTPServer) callGetDashboardVersions(sc *scenarioContext) {
 func (hs *HTTPServer) callDeleteDashboardByUID(t *testing.T,
 	sc *scenarioContext, mockDashboard *dashboards.FakeDashboardService, mockPubdashService *publicdashboards.FakePublicDashboardService) {
 	hs.DashboardService = mockDashboard
-	pubdashApi := api.ProvideApi(mockPubdashService, nil, nil, featuremgmt.WithFeatures())
+	pubdashApi := api.ProvideApi(mockPubdashService, nil, nil, featuremgmt.WithFeatures(), setting.NewCfg())
 	hs.PublicDashboardsApi = pubdashApi
 	sc.handlerFunc = hs.DeleteDashboardByUID
 	sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
@@ -1210,6 +1212,157 @@ func postDashboardScenario(t *testing.T, desc string, url string, routePattern s
 	})
 }
 
+func TestDashboardAPIEndpoint_PostDashboardRequiringApproval(t *testing.T) {
+	cmd := dashboards.SaveDashboardCommand{
+		OrgID:  1,
+		UserID: 5,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{
+			"id":      2,
+			"uid":     "abc",
+			"version": 3,
+			"title":   "Dash",
+		}),
+		Message: "msg",
+	}
+
+	postDashboard := func(t *testing.T, dashboardService dashboards.DashboardService, draftService *fakeDashboardDraftService) *scenarioContext {
+		hs := HTTPServer{
+			Cfg:                          setting.NewCfg(),
+			ProvisioningService:          provisioning.NewProvisioningServiceMock(context.Background()),
+			QuotaService:                 quotatest.New(false, nil),
+			pluginStore:                  &fakes.FakePluginStore{},
+			LibraryPanelService:          &mockLibraryPanelService{},
+			DashboardService:             dashboardService,
+			dashboardProvisioningService: mockDashboardProvisioningService{},
+			dashboardDraftService:        draftService,
+			Features:                     featuremgmt.WithFeatures(),
+			accesscontrolService:         actest.FakeService{},
+		}
+
+		sc := setupScenarioContext(t, "/api/dashboards/db")
+		sc.m.Post("/api/dashboards/db", routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			c.Req.Body = mockRequestBody(cmd)
+			c.Req.Header.Add("Content-Type", "application/json")
+			c.SignedInUser = &user.SignedInUser{OrgID: cmd.OrgID, UserID: cmd.UserID}
+			return hs.PostDashboard(c)
+		}))
+		callPostDashboard(sc)
+		return sc
+	}
+
+	t.Run("saves the changes as a draft", func(t *testing.T) {
+		draftService := &fakeDashboardDraftService{requiresApproval: true}
+		// the dashboard is not saved
+		sc := postDashboard(t, dashboards.NewFakeDashboardService(t), draftService)
+
+		require.Equal(t, http.StatusAccepted, sc.resp.Code)
+		result := sc.ToJSON()
+		assert.Equal(t, "pending", result.Get("status").MustString())
+		assert.Equal(t, int64(7), result.Get("draftId").MustInt64())
+		assert.Equal(t, "abc", result.Get("uid").MustString())
+		assert.Equal(t, 3, result.Get("version").MustInt())
+
+		require.NotNil(t, draftService.created)
+		assert.Equal(t, "abc", draftService.created.Dashboard.UID)
+		assert.Equal(t, "msg", draftService.created.Message)
+	})
+
+	t.Run("saves the dashboard when approval is not required", func(t *testing.T) {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Return(&dashboards.Dashboard{ID: 2, UID: "abc", Title: "Dash", Slug: "dash", Version: 4}, nil)
+		draftService := &fakeDashboardDraftService{}
+
+		sc := postDashboard(t, dashboardService, draftService)
+
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, "success", sc.ToJSON().Get("status").MustString())
+		assert.Nil(t, draftService.created)
+	})
+}
+
+func TestDashboardAPIEndpoint_PostDashboardWithLintProblems(t *testing.T) {
+	cmd := dashboards.SaveDashboardCommand{
+		OrgID:  1,
+		UserID: 5,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{
+			"id":    2,
+			"uid":   "abc",
+			"title": "Dash",
+		}),
+	}
+	problem := dashboardlint.Problem{Rule: "missing-description", Message: "Dashboard has no description"}
+
+	postDashboard := func(t *testing.T, dashboardService dashboards.DashboardService, lintService *fakeDashboardLintService) *scenarioContext {
+		hs := HTTPServer{
+			Cfg:                          setting.NewCfg(),
+			ProvisioningService:          provisioning.NewProvisioningServiceMock(context.Background()),
+			QuotaService:                 quotatest.New(false, nil),
+			pluginStore:                  &fakes.FakePluginStore{},
+			LibraryPanelService:          &mockLibraryPanelService{},
+			DashboardService:             dashboardService,
+			dashboardProvisioningService: mockDashboardProvisioningService{},
+			dashboardLintService:         lintService,
+			Features:                     featuremgmt.WithFeatures(),
+			accesscontrolService:         actest.FakeService{},
+			log:                          log.NewNopLogger(),
+		}
+
+		sc := setupScenarioContext(t, "/api/dashboards/db")
+		sc.m.Post("/api/dashboards/db", routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			c.Req.Body = mockRequestBody(cmd)
+			c.Req.Header.Add("Content-Type", "application/json")
+			c.SignedInUser = &user.SignedInUser{OrgID: cmd.OrgID, UserID: cmd.UserID}
+			return hs.PostDashboard(c)
+		}))
+		callPostDashboard(sc)
+		return sc
+	}
+
+	t.Run("refuses dashboards with blocking problems", func(t *testing.T) {
+		problem := problem
+		problem.Level = dashboardlint.LevelBlock
+		lintService := &fakeDashboardLintService{result: &dashboardlint.LintResult{Problems: []dashboardlint.Problem{problem}, Blocked: true}}
+		// the dashboard is not saved
+		sc := postDashboard(t, dashboards.NewFakeDashboardService(t), lintService)
+
+		require.Equal(t, http.StatusPreconditionFailed, sc.resp.Code)
+		result := sc.ToJSON()
+		assert.Equal(t, "lint-failed", result.Get("status").MustString())
+		assert.Equal(t, "missing-description", result.Get("problems").GetIndex(0).Get("rule").MustString())
+		require.NotNil(t, lintService.query)
+		assert.Equal(t, "Dash", lintService.query.Dashboard.Get("title").MustString())
+	})
+
+	t.Run("saves dashboards with warnings", func(t *testing.T) {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Return(&dashboards.Dashboard{ID: 2, UID: "abc", Title: "Dash", Slug: "dash", Version: 1}, nil)
+		problem := problem
+		problem.Level = dashboardlint.LevelWarn
+		lintService := &fakeDashboardLintService{result: &dashboardlint.LintResult{Problems: []dashboardlint.Problem{problem}}}
+
+		sc := postDashboard(t, dashboardService, lintService)
+
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		result := sc.ToJSON()
+		assert.Equal(t, "success", result.Get("status").MustString())
+		assert.Equal(t, "warn", result.Get("lint").GetIndex(0).Get("level").MustString())
+	})
+
+	t.Run("saves dashboards when linting fails", func(t *testing.T) {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).
+			Return(&dashboards.Dashboard{ID: 2, UID: "abc", Title: "Dash", Slug: "dash", Version: 1}, nil)
+
+		sc := postDashboard(t, dashboardService, &fakeDashboardLintService{err: errors.New("boom")})
+
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		_, ok := sc.ToJSON().CheckGet("lint")
+		assert.False(t, ok)
+	})
+}
+
 func postValidateScenario(t *testing.T, desc string, url string, routePattern string, cmd dashboards.ValidateDashboardCommand,
 	role org.RoleType, fn scenarioFunc, sqlmock db.DB) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
@@ -1344,6 +1497,38 @@ func (s mockDashboardProvisioningService) GetProvisionedDashboardDataByDashboard
 	return nil, nil
 }
 
+type fakeDashboardDraftService struct {
+	dashboarddraft.Service
+	requiresApproval bool
+	created          *dashboarddraft.CreateDraftCommand
+}
+
+func (s *fakeDashboardDraftService) RequiresApproval(context.Context, *dashboarddraft.RequiresApprovalQuery) (bool, error) {
+	return s.requiresApproval, nil
+}
+
+func (s *fakeDashboardDraftService) CreateDraft(_ context.Context, cmd *dashboarddraft.CreateDraftCommand) (*dashboarddraft.Draft, error) {
+	s.created = cmd
+	return &dashboarddraft.Draft{
+		ID:            7,
+		DashboardUID:  cmd.Dashboard.UID,
+		ParentVersion: cmd.Dashboard.Version,
+		Status:        dashboarddraft.StatusPending,
+	}, nil
+}
+
+type fakeDashboardLintService struct {
+	dashboardlint.Service
+	result *dashboardlint.LintResult
+	err    error
+	query  *dashboardlint.LintQuery
+}
+
+func (s *fakeDashboardLintService) Lint(_ context.Context, query *dashboardlint.LintQuery) (*dashboardlint.LintResult, error) {
+	s.query = query
+	return s.result, s.err
+}
+
 type mockLibraryPanelService struct {
 }
 
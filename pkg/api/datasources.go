@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
@@ -185,6 +186,7 @@ func (hs *HTTPServer) DeleteDataSourceById(c *contextmodel.ReqContext) response.
 	}
 
 	hs.Live.HandleDatasourceDelete(c.OrgID, ds.UID)
+	hs.logAuditEvent(c, audit.ActionDelete, audit.Resource(c.OrgID, audit.KindDatasource, ds.UID), datasourceAuditSummary(ds), nil)
 
 	return response.Success("Data source deleted")
 }
@@ -264,6 +266,7 @@ func (hs *HTTPServer) DeleteDataSourceByUID(c *contextmodel.ReqContext) response
 	}
 
 	hs.Live.HandleDatasourceDelete(c.OrgID, ds.UID)
+	hs.logAuditEvent(c, audit.ActionDelete, audit.Resource(c.OrgID, audit.KindDatasource, ds.UID), datasourceAuditSummary(ds), nil)
 
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "Data source deleted",
@@ -314,6 +317,7 @@ func (hs *HTTPServer) DeleteDataSourceByName(c *contextmodel.ReqContext) respons
 	}
 
 	hs.Live.HandleDatasourceDelete(c.OrgID, dataSource.UID)
+	hs.logAuditEvent(c, audit.ActionDelete, audit.Resource(c.OrgID, audit.KindDatasource, dataSource.UID), datasourceAuditSummary(dataSource), nil)
 
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "Data source deleted",
@@ -401,6 +405,7 @@ func (hs *HTTPServer) AddDataSource(c *contextmodel.ReqContext) response.Respons
 	// Clear permission cache for the user who's created the data source, so that new permissions are fetched for their next call
 	// Required for cases when caller wants to immediately interact with the newly created object
 	hs.accesscontrolService.ClearUserPermissionCache(c.SignedInUser)
+	hs.logAuditEvent(c, audit.ActionCreate, audit.Resource(c.OrgID, audit.KindDatasource, dataSource.UID), nil, datasourceAuditSummary(dataSource))
 
 	ds := hs.convertModelToDtos(c.Req.Context(), dataSource)
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -539,6 +544,7 @@ func (hs *HTTPServer) updateDataSourceByID(c *contextmodel.ReqContext, ds *datas
 	datasourceDTO := hs.convertModelToDtos(c.Req.Context(), dataSource)
 
 	hs.Live.HandleDatasourceUpdate(c.OrgID, datasourceDTO.UID)
+	hs.logAuditEvent(c, audit.ActionUpdate, audit.Resource(c.OrgID, audit.KindDatasource, dataSource.UID), datasourceAuditSummary(ds), datasourceAuditSummary(dataSource))
 
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message":    "Datasource updated",
@@ -548,6 +554,18 @@ func (hs *HTTPServer) updateDataSourceByID(c *contextmodel.ReqContext, ds *datas
 	})
 }
 
+// datasourceAuditSummary returns the fields of a data source recorded in audit events, the secure fields are never included.
+func datasourceAuditSummary(ds *datasources.DataSource) audit.Summary {
+	return audit.Summary{
+		"name":      ds.Name,
+		"type":      ds.Type,
+		"url":       ds.URL,
+		"access":    ds.Access,
+		"isDefault": ds.IsDefault,
+		"version":   ds.Version,
+	}
+}
+
 func (hs *HTTPServer) getRawDataSourceById(ctx context.Context, id int64, orgID int64) (*datasources.DataSource, error) {
 	query := datasources.GetDataSourceQuery{
 		ID:    id,
@@ -1,5 +1,7 @@
 package dtos
 
+import "github.com/grafana/grafana/pkg/kinds/playlist"
+
 type PlaylistDashboard struct {
 	Id    int64  `json:"id"`
 	Slug  string `json:"slug"`
@@ -7,6 +9,10 @@ type PlaylistDashboard struct {
 	Uri   string `json:"uri"`
 	Url   string `json:"url"`
 	Order int    `json:"order"`
+	// Interval is the time the dashboard is shown, the interval of its playlist item or of the playlist.
+	Interval  string              `json:"interval"`
+	TimeRange *playlist.TimeRange `json:"timeRange,omitempty"`
+	Variables map[string][]string `json:"variables,omitempty"`
 }
 
 type PlaylistDashboardsSlice []PlaylistDashboard
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
@@ -39,13 +40,17 @@ import (
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/cleanup"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -66,6 +71,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/panelexport"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/plugindashboards"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
@@ -192,19 +198,24 @@ type HTTPServer struct {
 	kvStore                      kvstore.KVStore
 	pluginsCDNService            *pluginscdn.Service
 
-	userService          user.Service
-	tempUserService      tempUser.Service
-	loginAttemptService  loginAttempt.Service
-	orgService           org.Service
-	teamService          team.Service
-	accesscontrolService accesscontrol.Service
-	annotationsRepo      annotations.Repository
-	tagService           tag.Service
-	oauthTokenService    oauthtoken.OAuthTokenService
-	statsService         stats.Service
-	authnService         authn.Service
-	starApi              *starApi.API
-	promRegister         prometheus.Registerer
+	userService           user.Service
+	tempUserService       tempUser.Service
+	loginAttemptService   loginAttempt.Service
+	orgService            org.Service
+	teamService           team.Service
+	accesscontrolService  accesscontrol.Service
+	annotationsRepo       annotations.Repository
+	auditService          audit.Service
+	panelExportService    panelexport.Service
+	dashboardDraftService dashboarddraft.Service
+	dashboardLintService  dashboardlint.Service
+	dashboardUsageService dashboardusage.Service
+	tagService            tag.Service
+	oauthTokenService     oauthtoken.OAuthTokenService
+	statsService          stats.Service
+	authnService          authn.Service
+	starApi               *starApi.API
+	promRegister          prometheus.Registerer
 }
 
 type ServerOptions struct {
@@ -246,7 +257,9 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 	accesscontrolService accesscontrol.Service, navTreeService navtree.Service,
 	annotationRepo annotations.Repository, tagService tag.Service, searchv2HTTPService searchV2.SearchHTTPService, oauthTokenService oauthtoken.OAuthTokenService,
 	statsService stats.Service, authnService authn.Service, pluginsCDNService *pluginscdn.Service,
-	starApi *starApi.API, promRegister prometheus.Registerer,
+	starApi *starApi.API, promRegister prometheus.Registerer, auditService audit.Service,
+	panelExportService panelexport.Service, dashboardDraftService dashboarddraft.Service,
+	dashboardLintService dashboardlint.Service, dashboardUsageService dashboardusage.Service,
 
 ) (*HTTPServer, error) {
 	web.Env = cfg.Env
@@ -342,6 +355,11 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 		navTreeService:               navTreeService,
 		accesscontrolService:         accesscontrolService,
 		annotationsRepo:              annotationRepo,
+		auditService:                 auditService,
+		panelExportService:           panelExportService,
+		dashboardDraftService:        dashboardDraftService,
+		dashboardLintService:         dashboardLintService,
+		dashboardUsageService:        dashboardUsageService,
 		tagService:                   tagService,
 		oauthTokenService:            oauthTokenService,
 		statsService:                 statsService,
@@ -514,6 +532,9 @@ func (hs *HTTPServer) configureHttps() error {
 		MinVersion:   minTlsVersion,
 		CipherSuites: tlsCiphers,
 	}
+	if err := hs.configureClientCertAuth(tlsCfg); err != nil {
+		return err
+	}
 
 	hs.httpSrv.TLSConfig = tlsCfg
 	hs.httpSrv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
@@ -553,12 +574,41 @@ func (hs *HTTPServer) configureHttp2() error {
 		CipherSuites: tlsCiphers,
 		NextProtos:   []string{"h2", "http/1.1"},
 	}
+	if err := hs.configureClientCertAuth(tlsCfg); err != nil {
+		return err
+	}
 
 	hs.httpSrv.TLSConfig = tlsCfg
 
 	return nil
 }
 
+// configureClientCertAuth makes the server verify the client certificates signed by the configured CAs,
+// for the client certificate authentication. Clients without a certificate can still connect.
+func (hs *HTTPServer) configureClientCertAuth(tlsCfg *tls.Config) error {
+	if !hs.Cfg.ClientCertAuthEnabled {
+		return nil
+	}
+
+	if hs.Cfg.ClientCertAuthCACertFile == "" {
+		return errors.New("ca_cert_file cannot be empty when client certificate authentication is enabled")
+	}
+
+	pem, err := os.ReadFile(hs.Cfg.ClientCertAuthCACertFile)
+	if err != nil {
+		return fmt.Errorf("cannot read client certificate ca_cert_file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("no certificate found in client certificate ca_cert_file %q", hs.Cfg.ClientCertAuthCACertFile)
+	}
+
+	tlsCfg.ClientCAs = pool
+	tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	return nil
+}
+
 func (hs *HTTPServer) applyRoutes() {
 	// start with middlewares & static routes
 	hs.addMiddlewaresAndStaticRoutes()
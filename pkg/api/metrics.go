@@ -4,15 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/expr"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -60,9 +67,55 @@ func (hs *HTTPServer) QueryMetricsV2(c *contextmodel.ReqContext) response.Respon
 	if err != nil {
 		return hs.handleQueryMetricsError(err)
 	}
+	hs.logQueryAuditEvents(c, reqDTO)
+	hs.recordDashboardQueries(c, reqDTO)
 	return hs.toJsonStreamingResponse(resp)
 }
 
+// logQueryAuditEvents records a data access event for each data source in the request.
+func (hs *HTTPServer) logQueryAuditEvents(c *contextmodel.ReqContext, reqDTO dtos.MetricRequest) {
+	counts := map[string]int{}
+	var uids []string
+	for _, query := range reqDTO.Queries {
+		uid := query.Get("datasource").Get("uid").MustString()
+		if uid == "" || expr.IsDataSource(uid) {
+			continue
+		}
+		if counts[uid] == 0 {
+			uids = append(uids, uid)
+		}
+		counts[uid]++
+	}
+
+	for _, uid := range uids {
+		hs.logAuditEvent(c, audit.ActionQuery, audit.Resource(c.OrgID, audit.KindDatasource, uid), nil, audit.Summary{
+			"queries": counts[uid],
+			"from":    reqDTO.From,
+			"to":      reqDTO.To,
+		})
+	}
+}
+
+// recordDashboardQueries counts the queries of the panel that sent them, given by the headers set by the frontend.
+// The headers are not checked against the queries, only the queries of dashboards the user can read are counted.
+func (hs *HTTPServer) recordDashboardQueries(c *contextmodel.ReqContext, reqDTO dtos.MetricRequest) {
+	dashboardUID := c.Req.Header.Get(query.HeaderDashboardUID)
+	if hs.dashboardUsageService == nil || dashboardUID == "" {
+		return
+	}
+	evaluator := ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID))
+	if canRead, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator); err != nil || !canRead {
+		return
+	}
+	panelID, _ := strconv.ParseInt(c.Req.Header.Get(query.HeaderPanelID), 10, 64)
+	hs.dashboardUsageService.RecordQueries(c.Req.Context(), &dashboardusage.RecordQueriesCommand{
+		OrgID:        c.OrgID,
+		DashboardUID: dashboardUID,
+		PanelID:      panelID,
+		Queries:      len(reqDTO.Queries),
+	})
+}
+
 func (hs *HTTPServer) toJsonStreamingResponse(qdr *backend.QueryDataResponse) response.Response {
 	statusWhenError := http.StatusBadRequest
 	if hs.Features.IsEnabled(featuremgmt.FlagDatasourceQueryMultiStatus) {
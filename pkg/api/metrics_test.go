@@ -22,6 +22,8 @@ import (
 	pluginClient "github.com/grafana/grafana/pkg/plugins/manager/client"
 	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
 	"github.com/grafana/grafana/pkg/plugins/manager/registry"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
@@ -109,6 +111,60 @@ func TestAPIEndpoint_Metrics_QueryMetricsV2(t *testing.T) {
 	})
 }
 
+type fakeDashboardUsageService struct {
+	dashboardusage.Service
+	queries []*dashboardusage.RecordQueriesCommand
+}
+
+func (f *fakeDashboardUsageService) RecordQueries(_ context.Context, cmd *dashboardusage.RecordQueriesCommand) {
+	f.queries = append(f.queries, cmd)
+}
+
+func TestAPIEndpoint_Metrics_RecordDashboardQueries(t *testing.T) {
+	qds := query.ProvideService(
+		setting.NewCfg(),
+		nil,
+		nil,
+		&fakePluginRequestValidator{},
+		&fakePluginClient{
+			QueryDataHandlerFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				return &backend.QueryDataResponse{Responses: backend.Responses{"A": backend.DataResponse{}}}, nil
+			},
+		},
+		plugincontext.ProvideService(localcache.ProvideService(), &fakes.FakePluginStore{
+			PluginList: []plugins.PluginDTO{{JSONData: plugins.JSONData{ID: "grafana"}}},
+		}, &fakeDatasources.FakeDataSourceService{}, pluginSettings.ProvideService(dbtest.NewFakeDB(),
+			secretstest.NewFakeSecretsService()),
+		),
+	)
+	usageService := &fakeDashboardUsageService{}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.queryDataService = qds
+		hs.QuotaService = quotatest.New(false, nil)
+		hs.dashboardUsageService = usageService
+	})
+	usr := &user.SignedInUser{UserID: 1, OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+		datasources.ActionQuery:         []string{datasources.ScopeAll},
+		dashboards.ActionDashboardsRead: []string{dashboards.ScopeDashboardsProvider.GetResourceScopeUID("readable")},
+	}}}
+
+	for _, dashboardUID := range []string{"readable", "hidden"} {
+		req := server.NewPostRequest("/api/ds/query", strings.NewReader(reqValid))
+		req.Header.Set(query.HeaderDashboardUID, dashboardUID)
+		req.Header.Set(query.HeaderPanelID, "2")
+		webtest.RequestWithSignedInUser(req, usr)
+		resp, err := server.SendJSON(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// the queries of dashboards the user cannot read are not counted
+	require.Len(t, usageService.queries, 1)
+	require.Equal(t, "readable", usageService.queries[0].DashboardUID)
+	require.Equal(t, int64(2), usageService.queries[0].PanelID)
+}
+
 func TestAPIEndpoint_Metrics_PluginDecryptionFailure(t *testing.T) {
 	ds := &fakeDatasources.FakeDataSourceService{SimulatePluginFailure: true}
 	db := &dbtest.FakeDB{ExpectedError: pluginsettings.ErrPluginSettingNotFound}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -10,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/login"
@@ -417,6 +417,21 @@ func (hs *HTTPServer) updateOrgUserHelper(c *contextmodel.ReqContext, cmd org.Up
 		}
 	}
 
+	// the previous role is only needed for the audit event
+	var before audit.Summary
+	if hs.auditEnabled(audit.ActionSetRole) {
+		orgUsers, err := hs.orgService.GetOrgUsers(c.Req.Context(), &org.GetOrgUsersQuery{
+			UserID:                   cmd.UserID,
+			OrgID:                    cmd.OrgID,
+			DontEnforceAccessControl: true,
+		})
+		if err != nil {
+			hs.log.Debug("Failed to get previous role of org user", "userID", cmd.UserID, "orgID", cmd.OrgID, "error", err)
+		} else if len(orgUsers) == 1 {
+			before = audit.Summary{"role": orgUsers[0].Role}
+		}
+	}
+
 	if err := hs.orgService.UpdateOrgUser(c.Req.Context(), &cmd); err != nil {
 		if errors.Is(err, org.ErrLastOrgAdmin) {
 			return response.Error(http.StatusBadRequest, "Cannot change role so that there is no organization admin left", nil)
@@ -428,6 +443,7 @@ func (hs *HTTPServer) updateOrgUserHelper(c *contextmodel.ReqContext, cmd org.Up
 		UserID: cmd.UserID,
 		OrgID:  cmd.OrgID,
 	})
+	hs.logAuditEvent(c, audit.ActionSetRole, audit.Resource(cmd.OrgID, audit.KindOrgUser, strconv.FormatInt(cmd.UserID, 10)), before, audit.Summary{"role": cmd.Role})
 
 	return response.Success("Organization user updated")
 }
@@ -451,7 +467,7 @@ func (hs *HTTPServer) RemoveOrgUserForCurrentOrg(c *contextmodel.ReqContext) res
 		return response.Error(http.StatusBadRequest, "userId is invalid", err)
 	}
 
-	return hs.removeOrgUserHelper(c.Req.Context(), &org.RemoveOrgUserCommand{
+	return hs.removeOrgUserHelper(c, &org.RemoveOrgUserCommand{
 		UserID:                   userId,
 		OrgID:                    c.SignedInUser.GetOrgID(),
 		ShouldDeleteOrphanedUser: true,
@@ -480,13 +496,14 @@ func (hs *HTTPServer) RemoveOrgUser(c *contextmodel.ReqContext) response.Respons
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
 	}
-	return hs.removeOrgUserHelper(c.Req.Context(), &org.RemoveOrgUserCommand{
+	return hs.removeOrgUserHelper(c, &org.RemoveOrgUserCommand{
 		UserID: userId,
 		OrgID:  orgId,
 	})
 }
 
-func (hs *HTTPServer) removeOrgUserHelper(ctx context.Context, cmd *org.RemoveOrgUserCommand) response.Response {
+func (hs *HTTPServer) removeOrgUserHelper(c *contextmodel.ReqContext, cmd *org.RemoveOrgUserCommand) response.Response {
+	ctx := c.Req.Context()
 	if err := hs.orgService.RemoveOrgUser(ctx, cmd); err != nil {
 		if errors.Is(err, org.ErrLastOrgAdmin) {
 			return response.Error(400, "Cannot remove last organization admin", nil)
@@ -494,6 +511,8 @@ func (hs *HTTPServer) removeOrgUserHelper(ctx context.Context, cmd *org.RemoveOr
 		return response.Error(500, "Failed to remove user from organization", err)
 	}
 
+	hs.logAuditEvent(c, audit.ActionDelete, audit.Resource(cmd.OrgID, audit.KindOrgUser, strconv.FormatInt(cmd.UserID, 10)), nil, nil)
+
 	if cmd.UserWasDeleted {
 		// This should be called from appropriate service when moved
 		if err := hs.accesscontrolService.DeleteUserPermissions(ctx, accesscontrol.GlobalOrgID, cmd.UserID); err != nil {
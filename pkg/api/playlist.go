@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
@@ -82,6 +83,27 @@ func (hs *HTTPServer) GetPlaylist(c *contextmodel.ReqContext) response.Response
 	return response.JSON(http.StatusOK, dto)
 }
 
+// swagger:route GET /playlists/active playlists getActivePlaylist
+//
+// Get the active playlist.
+//
+// Returns the playlist whose schedule is active now. When the schedules of several playlists are active, the first
+// playlist by name is returned.
+//
+// Responses:
+// 200: getPlaylistResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetActivePlaylist(c *contextmodel.ReqContext) response.Response {
+	dto, err := hs.playlistService.GetActive(c.Req.Context(), &playlist.GetActivePlaylistQuery{OrgId: c.OrgID, Time: time.Now()})
+	if err != nil {
+		return response.ErrOrFallback(500, "Failed to get the active playlist", err)
+	}
+
+	return response.JSON(http.StatusOK, dto)
+}
+
 // swagger:route GET /playlists/{uid}/items playlists getPlaylistItems
 //
 // Get playlist items.
@@ -165,7 +187,7 @@ func (hs *HTTPServer) CreatePlaylist(c *contextmodel.ReqContext) response.Respon
 
 	p, err := hs.playlistService.Create(c.Req.Context(), &cmd)
 	if err != nil {
-		return response.Error(500, "Failed to create playlist", err)
+		return response.ErrOrFallback(500, "Failed to create playlist", err)
 	}
 
 	return response.JSON(http.StatusOK, p)
@@ -191,7 +213,7 @@ func (hs *HTTPServer) UpdatePlaylist(c *contextmodel.ReqContext) response.Respon
 
 	_, err := hs.playlistService.Update(c.Req.Context(), &cmd)
 	if err != nil {
-		return response.Error(500, "Failed to save playlist", err)
+		return response.ErrOrFallback(500, "Failed to save playlist", err)
 	}
 
 	dto, err := hs.playlistService.Get(c.Req.Context(), &playlist.GetPlaylistByUidQuery{
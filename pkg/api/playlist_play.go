@@ -2,8 +2,7 @@ package api
 
 import (
 	"context"
-	"errors"
-	"sort"
+	"net/url"
 	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -14,56 +13,51 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
-func (hs *HTTPServer) populateDashboardsByID(ctx context.Context, dashboardByIDs []int64, dashboardIDOrder map[int64]int) (dtos.PlaylistDashboardsSlice, error) {
+func (hs *HTTPServer) populateDashboards(ctx context.Context, query dashboards.GetDashboardsQuery, order int) (dtos.PlaylistDashboardsSlice, error) {
 	result := make(dtos.PlaylistDashboardsSlice, 0)
 
-	if len(dashboardByIDs) > 0 {
-		dashboardQuery := dashboards.GetDashboardsQuery{DashboardIDs: dashboardByIDs}
-		dashboardQueryResult, err := hs.DashboardService.GetDashboards(ctx, &dashboardQuery)
-		if err != nil {
-			return result, err
-		}
+	dashboardQueryResult, err := hs.DashboardService.GetDashboards(ctx, &query)
+	if err != nil {
+		return result, err
+	}
 
-		for _, item := range dashboardQueryResult {
-			result = append(result, dtos.PlaylistDashboard{
-				Id:    item.ID,
-				Slug:  item.Slug,
-				Title: item.Title,
-				Uri:   "db/" + item.Slug,
-				Url:   dashboards.GetDashboardURL(item.UID, item.Slug),
-				Order: dashboardIDOrder[item.ID],
-			})
-		}
+	for _, item := range dashboardQueryResult {
+		result = append(result, dtos.PlaylistDashboard{
+			Id:    item.ID,
+			Slug:  item.Slug,
+			Title: item.Title,
+			Uri:   "db/" + item.Slug,
+			Url:   dashboards.GetDashboardURL(item.UID, item.Slug),
+			Order: order,
+		})
 	}
 
 	return result, nil
 }
 
-func (hs *HTTPServer) populateDashboardsByTag(ctx context.Context, orgID int64, signedInUser *user.SignedInUser, dashboardByTag []string, dashboardTagOrder map[string]int) dtos.PlaylistDashboardsSlice {
+func (hs *HTTPServer) populateDashboardsByTag(ctx context.Context, orgID int64, signedInUser *user.SignedInUser, tag string, order int) dtos.PlaylistDashboardsSlice {
 	result := make(dtos.PlaylistDashboardsSlice, 0)
 
-	for _, tag := range dashboardByTag {
-		searchQuery := search.Query{
-			Title:        "",
-			Tags:         []string{tag},
-			SignedInUser: signedInUser,
-			Limit:        100,
-			IsStarred:    false,
-			OrgId:        orgID,
-		}
+	searchQuery := search.Query{
+		Title:        "",
+		Tags:         []string{tag},
+		SignedInUser: signedInUser,
+		Limit:        100,
+		IsStarred:    false,
+		OrgId:        orgID,
+	}
 
-		hits, err := hs.SearchService.SearchHandler(ctx, &searchQuery)
-		if err == nil {
-			for _, item := range hits {
-				result = append(result, dtos.PlaylistDashboard{
-					Id:    item.ID,
-					Slug:  item.Slug,
-					Title: item.Title,
-					Uri:   item.URI,
-					Url:   item.URL,
-					Order: dashboardTagOrder[tag],
-				})
-			}
+	hits, err := hs.SearchService.SearchHandler(ctx, &searchQuery)
+	if err == nil {
+		for _, item := range hits {
+			result = append(result, dtos.PlaylistDashboard{
+				Id:    item.ID,
+				Slug:  item.Slug,
+				Title: item.Title,
+				Uri:   item.URI,
+				Url:   item.URL,
+				Order: order,
+			})
 		}
 	}
 
@@ -79,32 +73,51 @@ func (hs *HTTPServer) LoadPlaylistDashboards(ctx context.Context, orgID int64, s
 		return result, err
 	}
 
-	playlistItems := dto.Items
-
-	dashboardByIDs := make([]int64, 0)
-	dashboardByTag := make([]string, 0)
-	dashboardIDOrder := make(map[int64]int)
-	dashboardTagOrder := make(map[string]int)
-
-	for i, item := range playlistItems {
+	// The dashboards are loaded item by item, so that a dashboard listed by several items is shown with the
+	// settings of each of them.
+	for i, item := range dto.Items {
+		var dashboardsOfItem dtos.PlaylistDashboardsSlice
 		switch item.Type {
 		case "dashboard_by_id":
 			dashboardID, _ := strconv.ParseInt(item.Value, 10, 64)
-			dashboardByIDs = append(dashboardByIDs, dashboardID)
-			dashboardIDOrder[dashboardID] = i
-		case "dashboard_by_tag":
-			dashboardByTag = append(dashboardByTag, item.Value)
-			dashboardTagOrder[item.Value] = i
+			dashboardsOfItem, _ = hs.populateDashboards(ctx, dashboards.GetDashboardsQuery{DashboardIDs: []int64{dashboardID}, OrgID: orgID}, i)
 		case "dashboard_by_uid":
-			return nil, errors.New("dashboard_by_uid not supported by this deprecated API")
+			dashboardsOfItem, _ = hs.populateDashboards(ctx, dashboards.GetDashboardsQuery{DashboardUIDs: []string{item.Value}, OrgID: orgID}, i)
+		case "dashboard_by_tag":
+			dashboardsOfItem = hs.populateDashboardsByTag(ctx, orgID, signedInUser, item.Value, i)
 		default:
 		}
-	}
 
-	k, _ := hs.populateDashboardsByID(ctx, dashboardByIDs, dashboardIDOrder)
-	result = append(result, k...)
-	result = append(result, hs.populateDashboardsByTag(ctx, orgID, signedInUser, dashboardByTag, dashboardTagOrder)...)
+		for j := range dashboardsOfItem {
+			applyPlaylistItemSettings(&dashboardsOfItem[j], item, dto.Interval)
+		}
+		result = append(result, dashboardsOfItem...)
+	}
 
-	sort.Sort(result)
 	return result, nil
 }
+
+// applyPlaylistItemSettings sets the interval, the time range and the variables a dashboard is shown with by its
+// playlist item. The time range and the variables are also added to the URL of the dashboard.
+func applyPlaylistItemSettings(dashboard *dtos.PlaylistDashboard, item playlist.PlaylistItemDTO, interval string) {
+	dashboard.Interval = interval
+	if item.Interval != nil && *item.Interval != "" {
+		dashboard.Interval = *item.Interval
+	}
+	dashboard.TimeRange = item.TimeRange
+	dashboard.Variables = item.Variables
+
+	params := url.Values{}
+	if item.TimeRange != nil {
+		params.Set("from", item.TimeRange.From)
+		params.Set("to", item.TimeRange.To)
+	}
+	for name, values := range item.Variables {
+		for _, value := range values {
+			params.Add("var-"+name, value)
+		}
+	}
+	if len(params) > 0 {
+		dashboard.Url += "?" + params.Encode()
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/kinds/playlist"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/playlist/playlisttest"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestLoadPlaylistDashboards(t *testing.T) {
+	t.Run("a dashboard listed by several items is shown with the settings of each item", func(t *testing.T) {
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboards", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardsQuery) bool {
+			return len(q.DashboardUIDs) == 1 && q.DashboardUIDs[0] == "abc" && q.OrgID == 1
+		})).Return([]*dashboards.Dashboard{{ID: 1, UID: "abc", Slug: "overview", Title: "Overview"}}, nil)
+
+		playlistService := playlisttest.NewPlaylistServiveFake()
+		playlistService.ExpectedPlaylistDTO = &playlist.Spec{
+			Interval: "5m",
+			Items: []playlist.Item{
+				{Type: playlist.ItemTypeDashboardByUid, Value: "abc", Interval: util.Pointer("30s")},
+				{Type: playlist.ItemTypeDashboardByUid, Value: "abc", Variables: map[string][]string{"host": {"b"}}},
+			},
+		}
+
+		hs := &HTTPServer{DashboardService: dashboardService, playlistService: playlistService}
+		result, err := hs.LoadPlaylistDashboards(context.Background(), 1, nil, "xyz")
+		require.NoError(t, err)
+
+		require.Len(t, result, 2)
+		assert.Equal(t, "30s", result[0].Interval)
+		assert.Equal(t, "/d/abc/overview", result[0].Url)
+		assert.Equal(t, "5m", result[1].Interval)
+		assert.Equal(t, "/d/abc/overview?var-host=b", result[1].Url)
+		assert.Equal(t, []int{0, 1}, []int{result[0].Order, result[1].Order})
+	})
+}
+
+func TestApplyPlaylistItemSettings(t *testing.T) {
+	t.Run("without settings the playlist interval is used", func(t *testing.T) {
+		dashboard := dtos.PlaylistDashboard{Url: "/d/abc/overview"}
+		applyPlaylistItemSettings(&dashboard, playlist.Item{Type: playlist.ItemTypeDashboardById, Value: "1"}, "5m")
+
+		assert.Equal(t, "5m", dashboard.Interval)
+		assert.Equal(t, "/d/abc/overview", dashboard.Url)
+		assert.Nil(t, dashboard.TimeRange)
+	})
+
+	t.Run("the settings of the item override the playlist", func(t *testing.T) {
+		dashboard := dtos.PlaylistDashboard{Url: "/d/abc/overview"}
+		applyPlaylistItemSettings(&dashboard, playlist.Item{
+			Type:      playlist.ItemTypeDashboardByTag,
+			Value:     "noc",
+			Interval:  util.Pointer("30s"),
+			TimeRange: &playlist.TimeRange{From: "now-24h", To: "now"},
+			Variables: map[string][]string{"host": {"a", "b"}},
+		}, "5m")
+
+		assert.Equal(t, "30s", dashboard.Interval)
+		assert.Equal(t, &playlist.TimeRange{From: "now-24h", To: "now"}, dashboard.TimeRange)
+		assert.Equal(t, map[string][]string{"host": {"a", "b"}}, dashboard.Variables)
+		assert.Equal(t, "/d/abc/overview?from=now-24h&to=now&var-host=a&var-host=b", dashboard.Url)
+	})
+}
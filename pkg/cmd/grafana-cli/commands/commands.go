@@ -129,6 +129,17 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:   "reset-user-mfa",
+		Usage:  "reset-user-mfa <login or email>",
+		Action: runRunnerCommand(resetMFACommand),
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "user-id",
+				Usage: "The user's ID, when no login or email is given",
+			},
+		},
+	},
 	{
 		Name:  "data-migration",
 		Usage: "Runs a script that migrates or cleanups data in your database",
@@ -159,6 +170,11 @@ var adminCommands = []*cli.Command{
 				Usage:  "Rotates persisted data encryption keys. Returns ok unless there is an error. Safe to execute multiple times.",
 				Action: runRunnerCommand(secretsmigrations.ReEncryptDEKS),
 			},
+			{
+				Name:   "rotate-key-encryption-key",
+				Usage:  "Rotates the key encryption key of the current encryption provider and re-encrypts data encryption keys with it. Returns ok unless there is an error.",
+				Action: runRunnerCommand(secretsmigrations.RotateKEK),
+			},
 		},
 	},
 	{
@@ -220,6 +236,21 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var dashboardCommands = []*cli.Command{
+	{
+		Name:      "lint",
+		Usage:     "Lint dashboard JSON files, or the JSON files of directories",
+		ArgsUsage: "<file or directory>...",
+		Action:    runPluginCommand(lintDashboardsCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "rules",
+				Usage: "JSON file with the level of each rule, off, warn or block",
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -231,4 +262,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "dashboards",
+		Usage:       "Grafana dashboard commands",
+		Subcommands: dashboardCommands,
+	},
 }
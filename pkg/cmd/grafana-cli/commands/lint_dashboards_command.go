@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+)
+
+var errLintBlocked = errors.New("dashboards have lint problems that block saving them")
+
+// lintedFile is a dashboard file and the problems found in it.
+type lintedFile struct {
+	path     string
+	problems []dashboardlint.Problem
+}
+
+// lintDashboardsCommand lints dashboard files with the rules that do not need a Grafana server,
+// e.g. in the CI of a repository of dashboards. It fails if some problems block saving the dashboards.
+func lintDashboardsCommand(c utils.CommandLine) error {
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return errors.New("missing dashboard files or directories")
+	}
+
+	levels := dashboardlint.Levels{}
+	if rulesFile := c.String("rules"); rulesFile != "" {
+		var err error
+		if levels, err = readLintLevels(rulesFile); err != nil {
+			return err
+		}
+	}
+
+	files, err := lintDashboardFiles(context.Background(), paths, levels)
+	if err != nil {
+		return err
+	}
+
+	blocked := false
+	for _, file := range files {
+		if len(file.problems) == 0 {
+			logger.Infof("%s %s\n", color.GreenString("✔"), file.path)
+			continue
+		}
+
+		logger.Infof("%s %s\n", color.RedString("✘"), file.path)
+		for _, problem := range file.problems {
+			level := color.YellowString(string(problem.Level))
+			if problem.Level == dashboardlint.LevelBlock {
+				level = color.RedString(string(problem.Level))
+				blocked = true
+			}
+			logger.Infof("  %s %s %s (%s)\n", level, lintLocation(problem), problem.Message, problem.Rule)
+		}
+	}
+
+	if blocked {
+		return errLintBlocked
+	}
+	return nil
+}
+
+// lintDashboardFiles lints the dashboard files, and the JSON files of the directories.
+func lintDashboardFiles(ctx context.Context, paths []string, levels dashboardlint.Levels) ([]lintedFile, error) {
+	linter := dashboardlint.NewLinter(dashboardlint.OfflineRules()...)
+
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// files given explicitly are linted whatever their extension
+			if !d.IsDir() && (p == path || strings.EqualFold(filepath.Ext(p), ".json")) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]lintedFile, 0, len(files))
+	for _, file := range files {
+		// nolint:gosec
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		dashboard, err := simplejson.NewJson(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid dashboard: %w", file, err)
+		}
+		// dashboards exported for sharing externally, or returned by the API, wrap the dashboard
+		if inner, ok := dashboard.CheckGet("dashboard"); ok {
+			dashboard = inner
+		}
+
+		problems, err := linter.Lint(ctx, &dashboardlint.Target{Dashboard: dashboard}, levels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint %s: %w", file, err)
+		}
+		result = append(result, lintedFile{path: file, problems: problems})
+	}
+	return result, nil
+}
+
+// readLintLevels reads the levels of the rules from a JSON file, in the format of the folder lint rules API.
+// The levels of the rules that need a Grafana server are ignored.
+func readLintLevels(path string) (dashboardlint.Levels, error) {
+	// nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := dashboardlint.Levels{}
+	if err := json.Unmarshal(data, &levels); err != nil {
+		return nil, fmt.Errorf("%s is not a valid rules file: %w", path, err)
+	}
+	for id, level := range levels {
+		if !level.IsValid() {
+			return nil, fmt.Errorf("level %q of rule %s must be off, warn or block", level, id)
+		}
+	}
+	return levels, nil
+}
+
+func lintLocation(problem dashboardlint.Problem) string {
+	switch {
+	case problem.RefID != "":
+		return fmt.Sprintf("panel %d, query %s:", problem.PanelID, problem.RefID)
+	case problem.PanelID != 0 || problem.PanelTitle != "":
+		return fmt.Sprintf("panel %d:", problem.PanelID)
+	default:
+		return "dashboard:"
+	}
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+)
+
+func TestLintDashboardFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	valid := writeFile("valid.json", `{"description": "Requests", "panels": [{"id": 1, "description": "Requests per second"}]}`)
+	writeFile("team/duplicate.json", `{"dashboard": {"description": "Errors", "panels": [
+		{"id": 1, "description": "Errors"},
+		{"id": 1, "description": "Errors per second"}
+	]}}`)
+	writeFile("team/README.md", "not a dashboard")
+
+	t.Run("files and directories", func(t *testing.T) {
+		files, err := lintDashboardFiles(context.Background(), []string{valid, filepath.Join(dir, "team")}, dashboardlint.Levels{"duplicate-panel-id": dashboardlint.LevelBlock})
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		assert.Equal(t, valid, files[0].path)
+		assert.Empty(t, files[0].problems)
+
+		assert.Equal(t, filepath.Join(dir, "team", "duplicate.json"), files[1].path)
+		require.Len(t, files[1].problems, 1)
+		assert.Equal(t, "duplicate-panel-id", files[1].problems[0].Rule)
+		assert.Equal(t, dashboardlint.LevelBlock, files[1].problems[0].Level)
+	})
+
+	t.Run("invalid dashboard", func(t *testing.T) {
+		_, err := lintDashboardFiles(context.Background(), []string{writeFile("invalid.json", "{")}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("rules file", func(t *testing.T) {
+		levels, err := readLintLevels(writeFile("rules.txt", `{"missing-description": "off", "datasource-exists": "block"}`))
+		require.NoError(t, err)
+		assert.Equal(t, dashboardlint.Levels{"missing-description": dashboardlint.LevelOff, "datasource-exists": dashboardlint.LevelBlock}, levels)
+
+		_, err = readLintLevels(writeFile("invalid-rules.txt", `{"missing-description": "error"}`))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func resetMFACommand(c utils.CommandLine, runner server.Runner) error {
+	userID := int64(c.Int("user-id"))
+	loginOrEmail := c.Args().First()
+
+	if err := resetMFA(userID, loginOrEmail, runner.UserService, runner.MFAService); err != nil {
+		return err
+	}
+
+	logger.Infof("\n")
+	logger.Infof("Multi-factor authentication reset successfully %s", color.GreenString("✔"))
+	return nil
+}
+
+func resetMFA(userID int64, loginOrEmail string, userSvc user.Service, mfaSvc mfa.Service) error {
+	ctx := context.Background()
+	if loginOrEmail != "" {
+		usr, err := userSvc.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: loginOrEmail})
+		if err != nil {
+			return fmt.Errorf("could not read user from database. Error: %v", err)
+		}
+		userID = usr.ID
+	} else {
+		if userID <= 0 {
+			return ErrMissingUser
+		}
+		if _, err := userSvc.GetByID(ctx, &user.GetUserByIDQuery{ID: userID}); err != nil {
+			return fmt.Errorf("could not read user from database. Error: %v", err)
+		}
+	}
+
+	if err := mfaSvc.Reset(ctx, userID); err != nil {
+		return fmt.Errorf("failed to reset multi-factor authentication: %w", err)
+	}
+	return nil
+}
+
+var ErrMissingUser = fmt.Errorf("reset-user-mfa requires a login, an email or the --user-id flag")
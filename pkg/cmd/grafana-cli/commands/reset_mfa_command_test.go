@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestResetMFA(t *testing.T) {
+	t.Run("should reset the user by login", func(t *testing.T) {
+		userSvc := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 3}}
+		mfaSvc := &mfatest.FakeService{}
+		require.NoError(t, resetMFA(0, "editor", userSvc, mfaSvc))
+		require.Equal(t, []int64{3}, mfaSvc.ResetUserIDs)
+	})
+
+	t.Run("should reset the user by id", func(t *testing.T) {
+		userSvc := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 5}}
+		mfaSvc := &mfatest.FakeService{}
+		require.NoError(t, resetMFA(5, "", userSvc, mfaSvc))
+		require.Equal(t, []int64{5}, mfaSvc.ResetUserIDs)
+	})
+
+	t.Run("should fail without user", func(t *testing.T) {
+		mfaSvc := &mfatest.FakeService{}
+		require.ErrorIs(t, resetMFA(0, "", &usertest.FakeUserService{}, mfaSvc), ErrMissingUser)
+		require.Empty(t, mfaSvc.ResetUserIDs)
+	})
+}
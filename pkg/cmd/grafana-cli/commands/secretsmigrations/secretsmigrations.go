@@ -11,6 +11,10 @@ func ReEncryptDEKS(_ utils.CommandLine, runner server.Runner) error {
 	return runner.SecretsService.ReEncryptDataKeys(context.Background())
 }
 
+func RotateKEK(_ utils.CommandLine, runner server.Runner) error {
+	return runner.SecretsService.RotateKeyEncryptionKey(context.Background())
+}
+
 func ReEncryptSecrets(_ utils.CommandLine, runner server.Runner) error {
 	_, err := runner.SecretsMigrator.ReEncryptSecrets(context.Background())
 	return err
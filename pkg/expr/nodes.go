@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/util/converter"
 )
 
 // label that is used when all mathexp.Series have 0 labels to make them identifiable by labels. The value of this label is extracted from value field names
@@ -317,6 +318,11 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 		return "no-data", mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
 	}
 
+	frames, err := expandHeatmapCells(frames)
+	if err != nil {
+		return "", mathexp.Results{}, err
+	}
+
 	var dt data.FrameType
 	dt, useDataplane, _ := shouldUseDataplane(frames, logger, s.features.IsEnabled(featuremgmt.FlagDisableSSEDataplane))
 	if useDataplane {
@@ -399,6 +405,35 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 	}, nil
 }
 
+// expandHeatmapCells replaces heatmap-cells frames, e.g. Prometheus native histograms, with
+// one series per bucket labeled with the bucket upper bound, so they can be reduced.
+func expandHeatmapCells(frames data.Frames) (data.Frames, error) {
+	hasHeatmap := false
+	for _, frame := range frames {
+		if converter.IsHeatmapCellsFrame(frame) {
+			hasHeatmap = true
+			break
+		}
+	}
+	if !hasHeatmap {
+		return frames, nil
+	}
+
+	expanded := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		if !converter.IsHeatmapCellsFrame(frame) {
+			expanded = append(expanded, frame)
+			continue
+		}
+		buckets, err := converter.HeatmapCellsToBuckets(frame)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, buckets...)
+	}
+	return expanded, nil
+}
+
 func isAllFrameVectors(datasourceType string, frames data.Frames) bool {
 	if datasourceType != datasources.DS_PROMETHEUS {
 		return false
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/converter"
 )
 
 type expectedError struct{}
@@ -271,4 +272,29 @@ func TestConvertDataFramesToResults(t *testing.T) {
 			}
 		})
 	})
+	t.Run("should convert native histograms to bucket series", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("xMax", nil, []time.Time{time.Unix(1, 0), time.Unix(1, 0)}),
+			data.NewField("yMin", data.Labels{"job": "api"}, []float64{0.1, 0.2}),
+			data.NewField("yMax", nil, []float64{0.2, 0.4}),
+			data.NewField("count", nil, []float64{3, 2}),
+			data.NewField("yLayout", nil, []int8{0, 0}),
+		)
+		frame.Meta = &data.FrameMeta{Type: converter.FrameTypeHeatmapCells}
+
+		resultType, res, err := convertDataFramesToResults(context.Background(), data.Frames{frame}, datasources.DS_PROMETHEUS, s, &logtest.Fake{})
+		require.NoError(t, err)
+		assert.Equal(t, "multi frame series", resultType)
+		require.Len(t, res.Values, 3)
+
+		expected := map[string]float64{"0.2": 3, "0.4": 5, "+Inf": 5}
+		for _, value := range res.Values {
+			require.IsType(t, mathexp.Series{}, value)
+			series := value.(mathexp.Series)
+			le := series.GetLabels()["le"]
+			require.Contains(t, expected, le)
+			_, v := series.GetPoint(0)
+			require.Equal(t, expected[le], *v)
+		}
+	})
 }
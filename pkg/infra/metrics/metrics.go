@@ -109,6 +109,9 @@ var (
 
 	// MPublicDashboardDatasourceQuerySuccess is a metric counter for successful queries labelled by datasource
 	MPublicDashboardDatasourceQuerySuccess *prometheus.CounterVec
+
+	// MPublicDashboardAccessCount is a metric counter for accesses to public dashboards labelled by kind and status
+	MPublicDashboardAccessCount *prometheus.CounterVec
 )
 
 // Timers
@@ -446,6 +449,12 @@ func init() {
 		Namespace: ExporterName,
 	}, []string{"datasource", "status"}, map[string][]string{"status": pubdash.QueryResultStatuses})
 
+	MPublicDashboardAccessCount = metricutil.NewCounterVecStartingAtZero(prometheus.CounterOpts{
+		Name:      "public_dashboard_access_count",
+		Help:      "counter for accesses to public dashboards labelled by kind view/query/annotations and status success/failure/denied/rate_limited",
+		Namespace: ExporterName,
+	}, []string{"kind", "status"}, map[string][]string{"kind": pubdash.AccessKinds, "status": pubdash.AccessStatuses})
+
 	MStatTotalDashboards = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name:      "stat_totals_dashboard",
 		Help:      "total amount of dashboards",
@@ -714,6 +723,7 @@ func initMetricVars() {
 		MStatTotalPublicDashboards,
 		MPublicDashboardRequestCount,
 		MPublicDashboardDatasourceQuerySuccess,
+		MPublicDashboardAccessCount,
 		MStatTotalCorrelations,
 	)
 }
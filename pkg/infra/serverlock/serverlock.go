@@ -2,6 +2,7 @@ package serverlock
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -164,6 +165,26 @@ func (sl *ServerLockService) LockExecuteAndRelease(ctx context.Context, actionNa
 	return nil
 }
 
+// RenewLock resets the timeout of a lock acquired by LockExecuteAndRelease, so that a function running longer than
+// maxInterval keeps the lock by renewing it regularly. It fails if the lock no longer exists.
+func (sl *ServerLockService) RenewLock(ctx context.Context, actionName string) error {
+	return sl.SQLStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		affected, err := dbSession.Where("operation_uid = ?", actionName).Cols("last_execution").Update(&serverLock{LastExecution: time.Now().Unix()})
+		if err != nil || affected == 1 {
+			return err
+		}
+		// some databases don't count the rows updated with the same value
+		has, err := dbSession.Where("operation_uid = ?", actionName).Exist(&serverLock{})
+		if err != nil {
+			return err
+		}
+		if !has {
+			return fmt.Errorf("there is no lock for this actionName: %s", actionName)
+		}
+		return nil
+	})
+}
+
 // acquireForRelease will check if the lock is already on the database, if it is, will check with maxInterval if it is
 // timeouted. Returns nil error if the lock was acquired correctly
 func (sl *ServerLockService) acquireForRelease(ctx context.Context, actionName string, maxInterval time.Duration) error {
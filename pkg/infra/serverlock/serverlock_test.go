@@ -63,6 +63,26 @@ func TestServerLock(t *testing.T) {
 func TestLockAndRelease(t *testing.T) {
 	operationUID := "test-operation-release"
 
+	t.Run("renew a lock", func(t *testing.T) {
+		sl := createTestableServerLock(t)
+		require.Error(t, sl.RenewLock(context.Background(), operationUID), "a lock that doesn't exist can't be renewed")
+
+		err := sl.acquireForRelease(context.Background(), operationUID, time.Minute)
+		require.NoError(t, err)
+		err = sl.SQLStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("UPDATE server_lock SET last_execution = ? WHERE operation_uid = ?", time.Now().Add(-time.Hour).Unix(), operationUID)
+			return err
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, sl.RenewLock(context.Background(), operationUID))
+		require.NoError(t, sl.RenewLock(context.Background(), operationUID), "renewing twice in the same second")
+		err = sl.acquireForRelease(context.Background(), operationUID, time.Minute)
+		require.Error(t, err, "the renewed lock has not timed out")
+
+		require.NoError(t, sl.releaseLock(context.Background(), operationUID))
+	})
+
 	t.Run("create lock and then release it", func(t *testing.T) {
 		sl := createTestableServerLock(t)
 		duration := time.Hour * 5
@@ -18,6 +18,12 @@ const (
 
 // Item defines model for Item.
 type Item struct {
+	// Interval overrides the interval of the playlist for the dashboards of this item.
+	Interval *string `json:"interval,omitempty"`
+
+	// TimeRange overrides the time range of the dashboards of this item.
+	TimeRange *TimeRange `json:"timeRange,omitempty"`
+
 	// Title is an unused property -- it will be removed in the future
 	Title *string `json:"title,omitempty"`
 
@@ -33,11 +39,50 @@ type Item struct {
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
 	Value string `json:"value"`
+
+	// Variables overrides the values of the variables of the dashboards of this item, by variable name.
+	Variables map[string][]string `json:"variables,omitempty"`
 }
 
 // Type of the item.
 type ItemType string
 
+// A playlist is active at the times of its cron expression, during its duration, and during its time windows.
+type Schedule struct {
+	// Cron expression of the times the playlist becomes active, in the standard five fields format.
+	Cron *string `json:"cron,omitempty"`
+
+	// Duration the playlist stays active after each time of the cron expression, for example 2h.
+	Duration *string `json:"duration,omitempty"`
+
+	// Time windows during which the playlist is active.
+	TimeWindows []TimeWindow `json:"timeWindows,omitempty"`
+
+	// Timezone of the cron expression and the time windows, for example Europe/Paris. Defaults to UTC.
+	Timezone *string `json:"timezone,omitempty"`
+}
+
+// TimeRange defines model for TimeRange.
+type TimeRange struct {
+	// Start of the time range, for example now-6h.
+	From string `json:"from"`
+
+	// End of the time range, for example now.
+	To string `json:"to"`
+}
+
+// TimeWindow defines model for TimeWindow.
+type TimeWindow struct {
+	// End time of the time window, formatted as 15:04. A time window that ends before it starts ends on the next day.
+	End string `json:"end"`
+
+	// Start time of the time window, formatted as 15:04.
+	Start string `json:"start"`
+
+	// Days of the week of the time window, for example monday. Every day when empty.
+	Weekdays []string `json:"weekdays,omitempty"`
+}
+
 // Spec defines model for Spec.
 type Spec struct {
 	// Interval sets the time between switching views in a playlist.
@@ -51,6 +96,9 @@ type Spec struct {
 	// Name of the playlist.
 	Name string `json:"name"`
 
+	// A playlist is active at the times of its cron expression, during its duration, and during its time windows.
+	Schedule *Schedule `json:"schedule,omitempty"`
+
 	// Unique playlist identifier. Generated on creation, either by the
 	// creator of the playlist of by the application.
 	Uid string `json:"uid"`
@@ -47,6 +47,33 @@ type Service interface {
 	IsDisabled() bool
 }
 
+// RoleService manages custom roles and their assignments to users, service accounts and teams.
+// Custom roles are stored in the database, their names are prefixed with CustomRolePrefix.
+type RoleService interface {
+	// GetCustomRole returns a custom role of the organization or a global custom role.
+	GetCustomRole(ctx context.Context, orgID int64, uid string) (*RoleDTO, error)
+	// ListCustomRoles returns the custom roles of the organization and the global custom roles.
+	ListCustomRoles(ctx context.Context, orgID int64) ([]*RoleDTO, error)
+	// CreateCustomRole creates a custom role, in the global organization when the command org is GlobalOrgID.
+	CreateCustomRole(ctx context.Context, cmd SaveCustomRoleCommand) (*RoleDTO, error)
+	// UpdateCustomRole updates a custom role, the version of the command must be greater than the stored one.
+	UpdateCustomRole(ctx context.Context, cmd SaveCustomRoleCommand) (*RoleDTO, error)
+	// DeleteCustomRole removes a custom role and its permissions.
+	DeleteCustomRole(ctx context.Context, cmd DeleteCustomRoleCommand) error
+	// GetUserCustomRoles returns the custom roles assigned to a user or a service account in an organization.
+	GetUserCustomRoles(ctx context.Context, orgID, userID int64) ([]*RoleDTO, error)
+	// AddUserCustomRole assigns a custom role to a user or a service account in an organization.
+	AddUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error
+	// RemoveUserCustomRole removes a custom role assignment from a user or a service account.
+	RemoveUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error
+	// GetTeamCustomRoles returns the custom roles assigned to a team.
+	GetTeamCustomRoles(ctx context.Context, orgID, teamID int64) ([]*RoleDTO, error)
+	// AddTeamCustomRole assigns a custom role to a team.
+	AddTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error
+	// RemoveTeamCustomRole removes a custom role assignment from a team.
+	RemoveTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error
+}
+
 type RoleRegistry interface {
 	// RegisterFixedRoles registers all roles declared to AccessControl
 	RegisterFixedRoles(ctx context.Context) error
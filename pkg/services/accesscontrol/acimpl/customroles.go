@@ -0,0 +1,98 @@
+package acimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var _ accesscontrol.RoleService = &Service{}
+
+type roleStore interface {
+	GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error)
+	ListCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error)
+	CreateCustomRole(ctx context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error)
+	UpdateCustomRole(ctx context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error)
+	DeleteCustomRole(ctx context.Context, cmd accesscontrol.DeleteCustomRoleCommand) error
+	GetUserCustomRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error)
+	AddUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error
+	RemoveUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error
+	GetTeamCustomRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error)
+	AddTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error
+	RemoveTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error
+}
+
+func (s *Service) GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	return s.roleStore.GetCustomRole(ctx, orgID, uid)
+}
+
+func (s *Service) ListCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.roleStore.ListCustomRoles(ctx, orgID)
+}
+
+func (s *Service) CreateCustomRole(ctx context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	return s.roleStore.CreateCustomRole(ctx, cmd)
+}
+
+func (s *Service) UpdateCustomRole(ctx context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	return s.roleStore.UpdateCustomRole(ctx, cmd)
+}
+
+func (s *Service) DeleteCustomRole(ctx context.Context, cmd accesscontrol.DeleteCustomRoleCommand) error {
+	return s.roleStore.DeleteCustomRole(ctx, cmd)
+}
+
+func (s *Service) GetUserCustomRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.roleStore.GetUserCustomRoles(ctx, orgID, userID)
+}
+
+func (s *Service) AddUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	if err := s.roleStore.AddUserCustomRole(ctx, orgID, userID, roleUID); err != nil {
+		return err
+	}
+	s.clearCachedPermissions(orgID, userID)
+	return nil
+}
+
+func (s *Service) RemoveUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	if err := s.roleStore.RemoveUserCustomRole(ctx, orgID, userID, roleUID); err != nil {
+		return err
+	}
+	s.clearCachedPermissions(orgID, userID)
+	return nil
+}
+
+func (s *Service) GetTeamCustomRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.roleStore.GetTeamCustomRoles(ctx, orgID, teamID)
+}
+
+// AddTeamCustomRole assigns a custom role to a team. The cached permissions of the team
+// members are not cleared, they get the new permissions once the cache entry expires.
+func (s *Service) AddTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	return s.roleStore.AddTeamCustomRole(ctx, orgID, teamID, roleUID)
+}
+
+func (s *Service) RemoveTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	return s.roleStore.RemoveTeamCustomRole(ctx, orgID, teamID, roleUID)
+}
+
+// clearCachedPermissions removes the cached permissions of a user or a service account
+func (s *Service) clearCachedPermissions(orgID, userID int64) {
+	for _, u := range []*user.SignedInUser{
+		{UserID: userID, OrgID: orgID},
+		{UserID: userID, OrgID: orgID, IsServiceAccount: true},
+	} {
+		s.ClearUserPermissionCache(u)
+	}
+}
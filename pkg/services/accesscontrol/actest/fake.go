@@ -147,3 +147,67 @@ func (f *FakePermissionsService) DeleteResourcePermissions(ctx context.Context,
 func (f *FakePermissionsService) MapActions(permission accesscontrol.ResourcePermission) string {
 	return f.ExpectedMappedAction
 }
+
+var _ accesscontrol.RoleService = new(FakeRoleService)
+
+type FakeRoleService struct {
+	ExpectedErr   error
+	ExpectedRole  *accesscontrol.RoleDTO
+	ExpectedRoles []*accesscontrol.RoleDTO
+
+	SavedCommands   []accesscontrol.SaveCustomRoleCommand
+	DeletedCommands []accesscontrol.DeleteCustomRoleCommand
+	AssignedRoles   []string
+	RemovedRoles    []string
+}
+
+func (f *FakeRoleService) GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f *FakeRoleService) ListCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f *FakeRoleService) CreateCustomRole(ctx context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error) {
+	f.SavedCommands = append(f.SavedCommands, cmd)
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f *FakeRoleService) UpdateCustomRole(ctx context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error) {
+	f.SavedCommands = append(f.SavedCommands, cmd)
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f *FakeRoleService) DeleteCustomRole(ctx context.Context, cmd accesscontrol.DeleteCustomRoleCommand) error {
+	f.DeletedCommands = append(f.DeletedCommands, cmd)
+	return f.ExpectedErr
+}
+
+func (f *FakeRoleService) GetUserCustomRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f *FakeRoleService) AddUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	f.AssignedRoles = append(f.AssignedRoles, roleUID)
+	return f.ExpectedErr
+}
+
+func (f *FakeRoleService) RemoveUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	f.RemovedRoles = append(f.RemovedRoles, roleUID)
+	return f.ExpectedErr
+}
+
+func (f *FakeRoleService) GetTeamCustomRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f *FakeRoleService) AddTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	f.AssignedRoles = append(f.AssignedRoles, roleUID)
+	return f.ExpectedErr
+}
+
+func (f *FakeRoleService) RemoveTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	f.RemovedRoles = append(f.RemovedRoles, roleUID)
+	return f.ExpectedErr
+}
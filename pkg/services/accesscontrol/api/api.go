@@ -14,10 +14,11 @@ import (
 )
 
 func NewAccessControlAPI(router routing.RouteRegister, accesscontrol ac.AccessControl, service ac.Service,
-	features *featuremgmt.FeatureManager) *AccessControlAPI {
+	roleService ac.RoleService, features *featuremgmt.FeatureManager) *AccessControlAPI {
 	return &AccessControlAPI{
 		RouteRegister: router,
 		Service:       service,
+		RoleService:   roleService,
 		AccessControl: accesscontrol,
 		features:      features,
 	}
@@ -25,6 +26,7 @@ func NewAccessControlAPI(router routing.RouteRegister, accesscontrol ac.AccessCo
 
 type AccessControlAPI struct {
 	Service       ac.Service
+	RoleService   ac.RoleService
 	AccessControl ac.AccessControl
 	RouteRegister routing.RouteRegister
 	features      *featuremgmt.FeatureManager
@@ -41,6 +43,9 @@ func (api *AccessControlAPI) RegisterAPIEndpoints() {
 			rr.Get("/users/permissions/search", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead)), routing.Wrap(api.searchUsersPermissions))
 			rr.Get("/user/:userID/permissions/search", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead, userIDScope)), routing.Wrap(api.searchUserPermissions))
 		}
+		if api.RoleService != nil {
+			api.registerRoleEndpoints(rr)
+		}
 	})
 }
 
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (api *AccessControlAPI) registerRoleEndpoints(rr routing.RouteRegister) {
+	authorize := ac.Middleware(api.AccessControl)
+	// Managing roles and their assignments is scoped with permissions:type:delegate,
+	// the handlers make sure the signed in user holds the permissions granted by the role.
+	delegateScope := ac.ScopePermissionsDelegate
+	userIDScope := ac.Scope("users", "id", ac.Parameter(":userId"))
+
+	rr.Get("/roles", authorize(ac.EvalPermission(ac.ActionRolesRead)), routing.Wrap(api.listRoles))
+	rr.Post("/roles", authorize(ac.EvalPermission(ac.ActionRolesWrite, delegateScope)), routing.Wrap(api.createRole))
+	rr.Get("/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionRolesRead, ac.ScopeRolesUID)), routing.Wrap(api.getRole))
+	rr.Put("/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionRolesWrite, delegateScope)), routing.Wrap(api.updateRole))
+	rr.Delete("/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionRolesDelete, delegateScope)), routing.Wrap(api.deleteRole))
+
+	rr.Get("/users/:userId/roles", authorize(ac.EvalPermission(ac.ActionUsersRolesRead, userIDScope)), routing.Wrap(api.getUserRoles))
+	rr.Post("/users/:userId/roles", authorize(ac.EvalPermission(ac.ActionUsersRolesAdd, delegateScope)), routing.Wrap(api.addUserRole))
+	rr.Delete("/users/:userId/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionUsersRolesRemove, delegateScope)), routing.Wrap(api.removeUserRole))
+
+	rr.Get("/teams/:teamId/roles", authorize(ac.EvalPermission(ac.ActionTeamsRolesRead, ac.ScopeTeamsID)), routing.Wrap(api.getTeamRoles))
+	rr.Post("/teams/:teamId/roles", authorize(ac.EvalPermission(ac.ActionTeamsRolesAdd, delegateScope)), routing.Wrap(api.addTeamRole))
+	rr.Delete("/teams/:teamId/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionTeamsRolesRemove, delegateScope)), routing.Wrap(api.removeTeamRole))
+}
+
+type RoleForm struct {
+	UID         string          `json:"uid"`
+	Name        string          `json:"name"`
+	DisplayName string          `json:"displayName"`
+	Description string          `json:"description"`
+	Group       string          `json:"group"`
+	Hidden      bool            `json:"hidden"`
+	Version     int64           `json:"version"`
+	Global      bool            `json:"global"`
+	Permissions []ac.Permission `json:"permissions"`
+}
+
+type RoleAssignmentForm struct {
+	RoleUID string `json:"roleUid"`
+}
+
+// GET /api/access-control/roles
+func (api *AccessControlAPI) listRoles(c *contextmodel.ReqContext) response.Response {
+	roles, err := api.RoleService.ListCustomRoles(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list roles", err)
+	}
+	return response.JSON(http.StatusOK, filterHidden(roles, c.QueryBool("includeHidden")))
+}
+
+// GET /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) getRole(c *contextmodel.ReqContext) response.Response {
+	role, err := api.RoleService.GetCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":roleUID"])
+	if err != nil {
+		return roleErrorResponse(err, "Failed to get role")
+	}
+	return response.JSON(http.StatusOK, role)
+}
+
+// POST /api/access-control/roles
+func (api *AccessControlAPI) createRole(c *contextmodel.ReqContext) response.Response {
+	form := RoleForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	orgID := c.SignedInUser.GetOrgID()
+	if form.Global {
+		if !c.SignedInUser.GetIsGrafanaAdmin() {
+			return response.Error(http.StatusForbidden, "Only server admins can create global roles", nil)
+		}
+		orgID = ac.GlobalOrgID
+	}
+	if !api.canGrant(c, form.Permissions) {
+		return response.Error(http.StatusForbidden, "Cannot create a role with permissions you do not have", nil)
+	}
+
+	role, err := api.RoleService.CreateCustomRole(c.Req.Context(), form.command(orgID, ""))
+	if err != nil {
+		return roleErrorResponse(err, "Failed to create role")
+	}
+	return response.JSON(http.StatusCreated, role)
+}
+
+// PUT /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) updateRole(c *contextmodel.ReqContext) response.Response {
+	form := RoleForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	existing, errResp := api.getManageableRole(c, web.Params(c.Req)[":roleUID"])
+	if errResp != nil {
+		return errResp
+	}
+	if !api.canGrant(c, form.Permissions) {
+		return response.Error(http.StatusForbidden, "Cannot add permissions you do not have to a role", nil)
+	}
+
+	role, err := api.RoleService.UpdateCustomRole(c.Req.Context(), form.command(existing.OrgID, existing.UID))
+	if err != nil {
+		return roleErrorResponse(err, "Failed to update role")
+	}
+	return response.JSON(http.StatusOK, role)
+}
+
+// DELETE /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) deleteRole(c *contextmodel.ReqContext) response.Response {
+	existing, errResp := api.getManageableRole(c, web.Params(c.Req)[":roleUID"])
+	if errResp != nil {
+		return errResp
+	}
+	if !api.canGrant(c, existing.Permissions) {
+		return response.Error(http.StatusForbidden, "Cannot delete a role with permissions you do not have", nil)
+	}
+
+	err := api.RoleService.DeleteCustomRole(c.Req.Context(), ac.DeleteCustomRoleCommand{
+		OrgID: existing.OrgID,
+		UID:   existing.UID,
+		Force: c.QueryBool("force"),
+	})
+	if err != nil {
+		return roleErrorResponse(err, "Failed to delete role")
+	}
+	return response.Success("Role deleted")
+}
+
+// GET /api/access-control/users/:userId/roles
+func (api *AccessControlAPI) getUserRoles(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	roles, err := api.RoleService.GetUserCustomRoles(c.Req.Context(), c.SignedInUser.GetOrgID(), userID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user roles", err)
+	}
+	return response.JSON(http.StatusOK, filterHidden(roles, c.QueryBool("includeHidden")))
+}
+
+// POST /api/access-control/users/:userId/roles
+func (api *AccessControlAPI) addUserRole(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+	form := RoleAssignmentForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if _, errResp := api.getAssignableRole(c, form.RoleUID); errResp != nil {
+		return errResp
+	}
+	if err := api.RoleService.AddUserCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), userID, form.RoleUID); err != nil {
+		return roleErrorResponse(err, "Failed to assign role")
+	}
+	return response.Success("Role added to the user.")
+}
+
+// DELETE /api/access-control/users/:userId/roles/:roleUID
+func (api *AccessControlAPI) removeUserRole(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+	roleUID := web.Params(c.Req)[":roleUID"]
+
+	if _, errResp := api.getAssignableRole(c, roleUID); errResp != nil {
+		return errResp
+	}
+	if err := api.RoleService.RemoveUserCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), userID, roleUID); err != nil {
+		return roleErrorResponse(err, "Failed to remove role")
+	}
+	return response.Success("Role removed from user.")
+}
+
+// GET /api/access-control/teams/:teamId/roles
+func (api *AccessControlAPI) getTeamRoles(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	roles, err := api.RoleService.GetTeamCustomRoles(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get team roles", err)
+	}
+	return response.JSON(http.StatusOK, filterHidden(roles, c.QueryBool("includeHidden")))
+}
+
+// POST /api/access-control/teams/:teamId/roles
+func (api *AccessControlAPI) addTeamRole(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+	form := RoleAssignmentForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if _, errResp := api.getAssignableRole(c, form.RoleUID); errResp != nil {
+		return errResp
+	}
+	if err := api.RoleService.AddTeamCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID, form.RoleUID); err != nil {
+		return roleErrorResponse(err, "Failed to assign role")
+	}
+	return response.Success("Role added to the team.")
+}
+
+// DELETE /api/access-control/teams/:teamId/roles/:roleUID
+func (api *AccessControlAPI) removeTeamRole(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+	roleUID := web.Params(c.Req)[":roleUID"]
+
+	if _, errResp := api.getAssignableRole(c, roleUID); errResp != nil {
+		return errResp
+	}
+	if err := api.RoleService.RemoveTeamCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID, roleUID); err != nil {
+		return roleErrorResponse(err, "Failed to remove role")
+	}
+	return response.Success("Role removed from team.")
+}
+
+// getManageableRole returns the role if the signed in user can update or delete it.
+// Global roles are shared by all organizations, only server admins can change them.
+func (api *AccessControlAPI) getManageableRole(c *contextmodel.ReqContext, uid string) (*ac.RoleDTO, response.Response) {
+	role, err := api.RoleService.GetCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), uid)
+	if err != nil {
+		return nil, roleErrorResponse(err, "Failed to get role")
+	}
+	if role.Global() && !c.SignedInUser.GetIsGrafanaAdmin() {
+		return nil, response.Error(http.StatusForbidden, "Only server admins can change global roles", nil)
+	}
+	return role, nil
+}
+
+// getAssignableRole returns the role if the signed in user has all of its permissions,
+// so that nobody can grant more than what they have been granted.
+func (api *AccessControlAPI) getAssignableRole(c *contextmodel.ReqContext, uid string) (*ac.RoleDTO, response.Response) {
+	role, err := api.RoleService.GetCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), uid)
+	if err != nil {
+		return nil, roleErrorResponse(err, "Failed to get role")
+	}
+	if !api.canGrant(c, role.Permissions) {
+		return nil, response.Error(http.StatusForbidden, "Cannot assign a role with permissions you do not have", nil)
+	}
+	return role, nil
+}
+
+func (api *AccessControlAPI) canGrant(c *contextmodel.ReqContext, permissions []ac.Permission) bool {
+	hasAccess := ac.HasAccess(api.AccessControl, c)
+	for _, p := range permissions {
+		var evaluator ac.Evaluator
+		if p.Scope == "" {
+			evaluator = ac.EvalPermission(p.Action)
+		} else {
+			evaluator = ac.EvalPermission(p.Action, p.Scope)
+		}
+		if !hasAccess(evaluator) {
+			return false
+		}
+	}
+	return true
+}
+
+func filterHidden(roles []*ac.RoleDTO, includeHidden bool) []*ac.RoleDTO {
+	if includeHidden {
+		return roles
+	}
+	filtered := make([]*ac.RoleDTO, 0, len(roles))
+	for _, role := range roles {
+		if !role.Hidden {
+			filtered = append(filtered, role)
+		}
+	}
+	return filtered
+}
+
+func (f *RoleForm) command(orgID int64, uid string) ac.SaveCustomRoleCommand {
+	if uid == "" {
+		uid = f.UID
+	}
+	return ac.SaveCustomRoleCommand{
+		OrgID:       orgID,
+		UID:         uid,
+		Name:        f.Name,
+		DisplayName: f.DisplayName,
+		Description: f.Description,
+		Group:       f.Group,
+		Hidden:      f.Hidden,
+		Version:     f.Version,
+		Permissions: f.Permissions,
+	}
+}
+
+func roleErrorResponse(err error, message string) response.Response {
+	var invalidRole *ac.ErrorInvalidRole
+	switch {
+	case errors.Is(err, ac.ErrRoleNotFound):
+		return response.Error(http.StatusNotFound, err.Error(), err)
+	case errors.Is(err, ac.ErrRoleAlreadyExists), errors.Is(err, ac.ErrRoleVersionConflict):
+		return response.Error(http.StatusConflict, err.Error(), err)
+	case errors.Is(err, ac.ErrRoleAssigned):
+		return response.Error(http.StatusBadRequest, "Role is assigned to users or teams, use force=true to delete it", err)
+	case errors.Is(err, ac.ErrInvalidCustomRole), errors.As(err, &invalidRole):
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+	return response.Error(http.StatusInternalServerError, message, err)
+}
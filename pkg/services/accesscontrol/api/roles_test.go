@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_createRole(t *testing.T) {
+	type testCase struct {
+		desc         string
+		body         string
+		permissions  map[string][]string
+		serverAdmin  bool
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "should create role with permissions the user has",
+			body:         `{"name": "custom:silences", "permissions": [{"action": "alert.silences:create"}]}`,
+			permissions:  map[string][]string{ac.ActionRolesWrite: {ac.ScopePermissionsDelegate}, "alert.silences:create": {}},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			desc:         "should not create role with permissions the user does not have",
+			body:         `{"name": "custom:users", "permissions": [{"action": "users:write", "scope": "users:*"}]}`,
+			permissions:  map[string][]string{ac.ActionRolesWrite: {ac.ScopePermissionsDelegate}, "users:write": {"users:id:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not create global role when not server admin",
+			body:         `{"name": "custom:global", "global": true}`,
+			permissions:  map[string][]string{ac.ActionRolesWrite: {ac.ScopePermissionsDelegate}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should create global role when server admin",
+			body:         `{"name": "custom:global", "global": true}`,
+			permissions:  map[string][]string{ac.ActionRolesWrite: {ac.ScopePermissionsDelegate}},
+			serverAdmin:  true,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			desc:         "should not create role without roles:write",
+			body:         `{"name": "custom:empty"}`,
+			permissions:  map[string][]string{},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			roleService := &actest.FakeRoleService{ExpectedRole: &ac.RoleDTO{OrgID: 1, UID: "a"}}
+			server := setupRoleAPI(t, roleService)
+
+			req := server.NewPostRequest("/api/access-control/roles", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:          1,
+				IsGrafanaAdmin: tt.serverAdmin,
+				Permissions:    map[int64]map[string][]string{1: tt.permissions},
+			})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+
+			if tt.expectedCode == http.StatusCreated {
+				require.Len(t, roleService.SavedCommands, 1)
+			} else {
+				require.Len(t, roleService.SavedCommands, 0)
+			}
+		})
+	}
+}
+
+func TestAPI_updateRole(t *testing.T) {
+	t.Run("should not update global role when not server admin", func(t *testing.T) {
+		roleService := &actest.FakeRoleService{ExpectedRole: &ac.RoleDTO{OrgID: ac.GlobalOrgID, UID: "a", Name: "custom:a"}}
+		server := setupRoleAPI(t, roleService)
+
+		req := server.NewRequest(http.MethodPut, "/api/access-control/roles/a", strings.NewReader(`{"name": "custom:a", "version": 2}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+			OrgID:       1,
+			Permissions: map[int64]map[string][]string{1: {ac.ActionRolesWrite: {ac.ScopePermissionsDelegate}}},
+		})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.Len(t, roleService.SavedCommands, 0)
+	})
+
+	t.Run("should return conflict when version is not incremented", func(t *testing.T) {
+		roleService := &conflictRoleService{FakeRoleService: &actest.FakeRoleService{
+			ExpectedRole: &ac.RoleDTO{OrgID: 1, UID: "a", Name: "custom:a", Version: 2},
+		}}
+		server := setupRoleAPI(t, roleService)
+
+		req := server.NewRequest(http.MethodPut, "/api/access-control/roles/a", strings.NewReader(`{"name": "custom:a", "version": 2}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+			OrgID:       1,
+			Permissions: map[int64]map[string][]string{1: {ac.ActionRolesWrite: {ac.ScopePermissionsDelegate}}},
+		})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusConflict, res.StatusCode)
+	})
+}
+
+func TestAPI_deleteRole(t *testing.T) {
+	t.Run("should not delete role with permissions the user does not have", func(t *testing.T) {
+		roleService := &actest.FakeRoleService{ExpectedRole: &ac.RoleDTO{OrgID: 1, UID: "a", Name: "custom:a", Permissions: []ac.Permission{
+			{Action: "users:write", Scope: "users:*"},
+		}}}
+		server := setupRoleAPI(t, roleService)
+
+		req := server.NewRequest(http.MethodDelete, "/api/access-control/roles/a", nil)
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+			OrgID:       1,
+			Permissions: map[int64]map[string][]string{1: {ac.ActionRolesDelete: {ac.ScopePermissionsDelegate}}},
+		})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.Len(t, roleService.DeletedCommands, 0)
+	})
+}
+
+func TestAPI_listRoles(t *testing.T) {
+	roleService := &actest.FakeRoleService{ExpectedRoles: []*ac.RoleDTO{
+		{OrgID: 1, UID: "a", Name: "custom:a"},
+		{OrgID: 1, UID: "b", Name: "custom:b", Hidden: true},
+	}}
+	server := setupRoleAPI(t, roleService)
+
+	for url, expected := range map[string]int{
+		"/api/access-control/roles":                    1,
+		"/api/access-control/roles?includeHidden=true": 2,
+	} {
+		req := server.NewGetRequest(url)
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+			OrgID:       1,
+			Permissions: map[int64]map[string][]string{1: {ac.ActionRolesRead: {ac.ScopeRolesAll}}},
+		})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var roles []ac.RoleDTO
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&roles))
+		require.NoError(t, res.Body.Close())
+		require.Len(t, roles, expected, url)
+	}
+}
+
+func TestAPI_addUserRole(t *testing.T) {
+	type testCase struct {
+		desc         string
+		permissions  map[string][]string
+		expectedCode int
+	}
+
+	role := &ac.RoleDTO{OrgID: 1, UID: "a", Name: "custom:a", Permissions: []ac.Permission{
+		{Action: "alert.silences:create"},
+		{Action: "folders:read", Scope: "folders:uid:general"},
+	}}
+
+	tests := []testCase{
+		{
+			desc: "should assign role with permissions the user has",
+			permissions: map[string][]string{
+				ac.ActionUsersRolesAdd:  {ac.ScopePermissionsDelegate},
+				"alert.silences:create": {},
+				"folders:read":          {"folders:*"},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc: "should not assign role with permissions the user does not have",
+			permissions: map[string][]string{
+				ac.ActionUsersRolesAdd:  {ac.ScopePermissionsDelegate},
+				"alert.silences:create": {},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc: "should not assign role without users.roles:add",
+			permissions: map[string][]string{
+				"alert.silences:create": {},
+				"folders:read":          {"folders:*"},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			roleService := &actest.FakeRoleService{ExpectedRole: role}
+			server := setupRoleAPI(t, roleService)
+
+			req := server.NewPostRequest("/api/access-control/users/2/roles", strings.NewReader(`{"roleUid": "a"}`))
+			req.Header.Set("Content-Type", "application/json")
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:       1,
+				Permissions: map[int64]map[string][]string{1: tt.permissions},
+			})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+
+			if tt.expectedCode == http.StatusOK {
+				require.Equal(t, []string{"a"}, roleService.AssignedRoles)
+			} else {
+				require.Len(t, roleService.AssignedRoles, 0)
+			}
+		})
+	}
+}
+
+func setupRoleAPI(t *testing.T, roleService ac.RoleService) *webtest.Server {
+	t.Helper()
+	api := NewAccessControlAPI(routing.NewRouteRegister(), permissionsAccessControl{}, actest.FakeService{}, roleService, featuremgmt.WithFeatures())
+	api.RegisterAPIEndpoints()
+	return webtest.NewServer(t, api.RouteRegister)
+}
+
+// permissionsAccessControl evaluates against the permissions of the signed in user
+type permissionsAccessControl struct {
+	actest.FakeAccessControl
+}
+
+func (permissionsAccessControl) Evaluate(_ context.Context, user identity.Requester, evaluator ac.Evaluator) (bool, error) {
+	return evaluator.Evaluate(user.GetPermissions()), nil
+}
+
+type conflictRoleService struct {
+	*actest.FakeRoleService
+}
+
+func (s *conflictRoleService) UpdateCustomRole(_ context.Context, _ ac.SaveCustomRoleCommand) (*ac.RoleDTO, error) {
+	return nil, ac.ErrRoleVersionConflict
+}
@@ -0,0 +1,320 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func (s *AccessControlStore) GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	var result *accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		role, err := getCustomRole(sess, orgID, uid)
+		if err != nil {
+			return err
+		}
+		result, err = roleWithPermissions(sess, role)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) ListCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	var result []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var roles []accesscontrol.Role
+		err := sess.Where("(org_id = ? OR org_id = ?) AND name LIKE ?", orgID, accesscontrol.GlobalOrgID, accesscontrol.CustomRolePrefix+"%").
+			Asc("name").Find(&roles)
+		if err != nil {
+			return err
+		}
+		result, err = rolesWithPermissions(sess, roles)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) CreateCustomRole(ctx context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error) {
+	var result *accesscontrol.RoleDTO
+	err := s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("uid = ? OR (org_id = ? AND name = ?)", cmd.UID, cmd.OrgID, cmd.Name).Exist(&accesscontrol.Role{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return accesscontrol.ErrRoleAlreadyExists
+		}
+
+		now := time.Now()
+		role := accesscontrol.Role{
+			OrgID:       cmd.OrgID,
+			UID:         cmd.UID,
+			Name:        cmd.Name,
+			DisplayName: cmd.DisplayName,
+			Description: cmd.Description,
+			Group:       cmd.Group,
+			Hidden:      cmd.Hidden,
+			Version:     cmd.Version,
+			Created:     now,
+			Updated:     now,
+		}
+		if role.Version < 1 {
+			role.Version = 1
+		}
+		if _, err := sess.Insert(&role); err != nil {
+			return err
+		}
+
+		if err := s.savePermissions(ctx, sess, role.ID, splitScopes(cmd.Permissions)); err != nil {
+			return err
+		}
+
+		result, err = roleWithPermissions(sess, &role)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) UpdateCustomRole(ctx context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error) {
+	var result *accesscontrol.RoleDTO
+	err := s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		role, err := getCustomRole(sess, cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		if cmd.Version <= role.Version {
+			return accesscontrol.ErrRoleVersionConflict
+		}
+
+		if cmd.Name != role.Name {
+			exists, err := sess.Where("org_id = ? AND name = ?", role.OrgID, cmd.Name).Exist(&accesscontrol.Role{})
+			if err != nil {
+				return err
+			}
+			if exists {
+				return accesscontrol.ErrRoleAlreadyExists
+			}
+		}
+
+		role.Name = cmd.Name
+		role.DisplayName = cmd.DisplayName
+		role.Description = cmd.Description
+		role.Group = cmd.Group
+		role.Hidden = cmd.Hidden
+		role.Version = cmd.Version
+		role.Updated = time.Now()
+		if _, err := sess.ID(role.ID).AllCols().Update(role); err != nil {
+			return err
+		}
+
+		if err := s.savePermissions(ctx, sess, role.ID, splitScopes(cmd.Permissions)); err != nil {
+			return err
+		}
+
+		result, err = roleWithPermissions(sess, role)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) DeleteCustomRole(ctx context.Context, cmd accesscontrol.DeleteCustomRoleCommand) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		role, err := getCustomRole(sess, cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+
+		if !cmd.Force {
+			userAssigned, err := sess.Where("role_id = ?", role.ID).Exist(&accesscontrol.UserRole{})
+			if err != nil {
+				return err
+			}
+			teamAssigned, err := sess.Where("role_id = ?", role.ID).Exist(&accesscontrol.TeamRole{})
+			if err != nil {
+				return err
+			}
+			if userAssigned || teamAssigned {
+				return accesscontrol.ErrRoleAssigned
+			}
+		}
+
+		for _, q := range []string{
+			"DELETE FROM user_role WHERE role_id = ?",
+			"DELETE FROM team_role WHERE role_id = ?",
+			"DELETE FROM builtin_role WHERE role_id = ?",
+			"DELETE FROM permission WHERE role_id = ?",
+			"DELETE FROM role WHERE id = ?",
+		} {
+			if _, err := sess.Exec(q, role.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *AccessControlStore) GetUserCustomRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	var result []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var roles []accesscontrol.Role
+		err := sess.Table("role").Select("role.*").
+			Join("INNER", "user_role", "user_role.role_id = role.id").
+			Where("user_role.user_id = ? AND (user_role.org_id = ? OR user_role.org_id = ?) AND role.name LIKE ?",
+				userID, orgID, accesscontrol.GlobalOrgID, accesscontrol.CustomRolePrefix+"%").
+			Asc("role.name").Find(&roles)
+		if err != nil {
+			return err
+		}
+		result, err = rolesWithPermissions(sess, roles)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) AddUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		role, err := getCustomRole(sess, orgID, roleUID)
+		if err != nil {
+			return err
+		}
+
+		exists, err := sess.Where("org_id = ? AND user_id = ? AND role_id = ?", orgID, userID, role.ID).Exist(&accesscontrol.UserRole{})
+		if err != nil || exists {
+			return err
+		}
+
+		_, err = sess.Insert(&accesscontrol.UserRole{OrgID: orgID, UserID: userID, RoleID: role.ID, Created: time.Now()})
+		return err
+	})
+}
+
+func (s *AccessControlStore) RemoveUserCustomRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		role, err := getCustomRole(sess, orgID, roleUID)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Exec("DELETE FROM user_role WHERE org_id = ? AND user_id = ? AND role_id = ?", orgID, userID, role.ID)
+		return err
+	})
+}
+
+func (s *AccessControlStore) GetTeamCustomRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	var result []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var roles []accesscontrol.Role
+		err := sess.Table("role").Select("role.*").
+			Join("INNER", "team_role", "team_role.role_id = role.id").
+			Where("team_role.team_id = ? AND team_role.org_id = ? AND role.name LIKE ?",
+				teamID, orgID, accesscontrol.CustomRolePrefix+"%").
+			Asc("role.name").Find(&roles)
+		if err != nil {
+			return err
+		}
+		result, err = rolesWithPermissions(sess, roles)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) AddTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		role, err := getCustomRole(sess, orgID, roleUID)
+		if err != nil {
+			return err
+		}
+
+		exists, err := sess.Where("org_id = ? AND team_id = ? AND role_id = ?", orgID, teamID, role.ID).Exist(&accesscontrol.TeamRole{})
+		if err != nil || exists {
+			return err
+		}
+
+		_, err = sess.Insert(&accesscontrol.TeamRole{OrgID: orgID, TeamID: teamID, RoleID: role.ID, Created: time.Now()})
+		return err
+	})
+}
+
+func (s *AccessControlStore) RemoveTeamCustomRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		role, err := getCustomRole(sess, orgID, roleUID)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Exec("DELETE FROM team_role WHERE org_id = ? AND team_id = ? AND role_id = ?", orgID, teamID, role.ID)
+		return err
+	})
+}
+
+// getCustomRole returns a custom role of the organization or a global custom role.
+// Fixed, managed and other roles stored in the role table are never returned.
+func getCustomRole(sess *db.Session, orgID int64, uid string) (*accesscontrol.Role, error) {
+	var role accesscontrol.Role
+	has, err := sess.Where("uid = ? AND (org_id = ? OR org_id = ?) AND name LIKE ?",
+		uid, orgID, accesscontrol.GlobalOrgID, accesscontrol.CustomRolePrefix+"%").Get(&role)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, accesscontrol.ErrRoleNotFound
+	}
+	return &role, nil
+}
+
+func roleWithPermissions(sess *db.Session, role *accesscontrol.Role) (*accesscontrol.RoleDTO, error) {
+	roles, err := rolesWithPermissions(sess, []accesscontrol.Role{*role})
+	if err != nil {
+		return nil, err
+	}
+	return roles[0], nil
+}
+
+func rolesWithPermissions(sess *db.Session, roles []accesscontrol.Role) ([]*accesscontrol.RoleDTO, error) {
+	result := make([]*accesscontrol.RoleDTO, 0, len(roles))
+	if len(roles) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int64, 0, len(roles))
+	for _, r := range roles {
+		ids = append(ids, r.ID)
+	}
+	var permissions []accesscontrol.Permission
+	if err := sess.In("role_id", ids).Asc("action", "scope").Find(&permissions); err != nil {
+		return nil, err
+	}
+	byRole := make(map[int64][]accesscontrol.Permission, len(roles))
+	for _, p := range permissions {
+		byRole[p.RoleID] = append(byRole[p.RoleID], p)
+	}
+
+	for _, r := range roles {
+		result = append(result, &accesscontrol.RoleDTO{
+			ID:          r.ID,
+			OrgID:       r.OrgID,
+			UID:         r.UID,
+			Version:     r.Version,
+			Name:        r.Name,
+			DisplayName: r.DisplayName,
+			Description: r.Description,
+			Group:       r.Group,
+			Hidden:      r.Hidden,
+			Permissions: byRole[r.ID],
+			Created:     r.Created,
+			Updated:     r.Updated,
+		})
+	}
+	return result, nil
+}
+
+func splitScopes(permissions []accesscontrol.Permission) []accesscontrol.Permission {
+	result := make([]accesscontrol.Permission, 0, len(permissions))
+	for _, p := range permissions {
+		p.Kind, p.Attribute, p.Identifier = p.SplitScope()
+		result = append(result, p)
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func TestAccessControlStore_CustomRoles(t *testing.T) {
+	ctx := context.Background()
+	s := &AccessControlStore{sql: db.InitTestDB(t)}
+
+	created, err := s.CreateCustomRole(ctx, accesscontrol.SaveCustomRoleCommand{
+		OrgID:       1,
+		UID:         "silences",
+		Name:        "custom:silences:creator",
+		DisplayName: "Silence creator",
+		Permissions: []accesscontrol.Permission{
+			{Action: "alert.silences:create"},
+			{Action: "alert.silences:read"},
+			{Action: "folders:read", Scope: "folders:uid:general"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created.Version)
+	require.Len(t, created.Permissions, 3)
+
+	_, err = s.CreateCustomRole(ctx, accesscontrol.SaveCustomRoleCommand{OrgID: 2, UID: "silences", Name: "custom:other"})
+	require.ErrorIs(t, err, accesscontrol.ErrRoleAlreadyExists, "uid is unique across organizations")
+	_, err = s.CreateCustomRole(ctx, accesscontrol.SaveCustomRoleCommand{OrgID: 1, UID: "other", Name: "custom:silences:creator"})
+	require.ErrorIs(t, err, accesscontrol.ErrRoleAlreadyExists, "name is unique in an organization")
+
+	_, err = s.CreateCustomRole(ctx, accesscontrol.SaveCustomRoleCommand{OrgID: accesscontrol.GlobalOrgID, UID: "global", Name: "custom:global"})
+	require.NoError(t, err)
+
+	t.Run("should list organization and global roles", func(t *testing.T) {
+		roles, err := s.ListCustomRoles(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, roles, 2)
+
+		roles, err = s.ListCustomRoles(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		require.Equal(t, "global", roles[0].UID)
+	})
+
+	t.Run("should not get roles of other organizations", func(t *testing.T) {
+		_, err := s.GetCustomRole(ctx, 2, "silences")
+		require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+
+		role, err := s.GetCustomRole(ctx, 2, "global")
+		require.NoError(t, err)
+		require.Equal(t, "custom:global", role.Name)
+	})
+
+	t.Run("should only update with an incremented version", func(t *testing.T) {
+		cmd := accesscontrol.SaveCustomRoleCommand{
+			OrgID:       1,
+			UID:         "silences",
+			Name:        "custom:silences:creator",
+			Version:     1,
+			Permissions: []accesscontrol.Permission{{Action: "alert.silences:create"}},
+		}
+		_, err := s.UpdateCustomRole(ctx, cmd)
+		require.ErrorIs(t, err, accesscontrol.ErrRoleVersionConflict)
+
+		cmd.Version = 2
+		updated, err := s.UpdateCustomRole(ctx, cmd)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+		require.Len(t, updated.Permissions, 1)
+		require.Equal(t, "alert.silences:create", updated.Permissions[0].Action)
+	})
+
+	t.Run("should assign roles to users and teams", func(t *testing.T) {
+		require.NoError(t, s.AddUserCustomRole(ctx, 1, 10, "silences"))
+		// Assignments are idempotent
+		require.NoError(t, s.AddUserCustomRole(ctx, 1, 10, "silences"))
+		require.NoError(t, s.AddUserCustomRole(ctx, 1, 10, "global"))
+		require.NoError(t, s.AddTeamCustomRole(ctx, 1, 20, "silences"))
+		require.ErrorIs(t, s.AddTeamCustomRole(ctx, 2, 20, "silences"), accesscontrol.ErrRoleNotFound)
+
+		roles, err := s.GetUserCustomRoles(ctx, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, roles, 2)
+
+		roles, err = s.GetTeamCustomRoles(ctx, 1, 20)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+
+		require.NoError(t, s.RemoveUserCustomRole(ctx, 1, 10, "global"))
+		roles, err = s.GetUserCustomRoles(ctx, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		require.Equal(t, "silences", roles[0].UID)
+	})
+
+	t.Run("should only delete assigned roles when forced", func(t *testing.T) {
+		err := s.DeleteCustomRole(ctx, accesscontrol.DeleteCustomRoleCommand{OrgID: 1, UID: "silences"})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAssigned)
+
+		err = s.DeleteCustomRole(ctx, accesscontrol.DeleteCustomRoleCommand{OrgID: 1, UID: "silences", Force: true})
+		require.NoError(t, err)
+
+		_, err = s.GetCustomRole(ctx, 1, "silences")
+		require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+		roles, err := s.GetTeamCustomRoles(ctx, 1, 20)
+		require.NoError(t, err)
+		require.Len(t, roles, 0)
+	})
+}
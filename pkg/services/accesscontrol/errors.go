@@ -13,6 +13,10 @@ var (
 	ErrResolverNotFound       = errors.New("no resolver found")
 	ErrPluginIDRequired       = errors.New("plugin ID is required")
 	ErrRoleNotFound           = errors.New("role not found")
+	ErrRoleAlreadyExists      = errors.New("a role with the same uid or name already exists")
+	ErrRoleVersionConflict    = errors.New("role version must be greater than the stored version")
+	ErrRoleAssigned           = errors.New("role is assigned to users or teams")
+	ErrInvalidCustomRole      = errors.New("invalid custom role")
 )
 
 type ErrorInvalidRole struct{}
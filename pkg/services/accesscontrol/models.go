@@ -317,10 +317,62 @@ func (cmd *SaveExternalServiceRoleCommand) Validate() error {
 	return nil
 }
 
+// SaveCustomRoleCommand creates or updates a custom role and its permissions.
+type SaveCustomRoleCommand struct {
+	OrgID       int64
+	UID         string
+	Name        string
+	DisplayName string
+	Description string
+	Group       string
+	Hidden      bool
+	// Version of the role, it must be incremented when the role is updated.
+	Version     int64
+	Permissions []Permission
+}
+
+func (cmd *SaveCustomRoleCommand) Validate() error {
+	if !strings.HasPrefix(cmd.Name, CustomRolePrefix) {
+		return &ErrorRolePrefixMissing{Role: cmd.Name, Prefixes: []string{CustomRolePrefix}}
+	}
+	if len(cmd.Name) > 190 {
+		return fmt.Errorf("%w: name %q is longer than 190 characters", ErrInvalidCustomRole, cmd.Name)
+	}
+	if len(cmd.UID) > 40 {
+		return fmt.Errorf("%w: uid %q is longer than 40 characters", ErrInvalidCustomRole, cmd.UID)
+	}
+
+	// Check and deduplicate permissions
+	dedupMap := map[Permission]bool{}
+	dedup := make([]Permission, 0, len(cmd.Permissions))
+	for i := range cmd.Permissions {
+		p := cmd.Permissions[i].OSSPermission()
+		if len(p.Action) == 0 {
+			return fmt.Errorf("%w: role %v has a permission with no action", ErrInvalidCustomRole, cmd.Name)
+		}
+		if dedupMap[p] {
+			continue
+		}
+		dedupMap[p] = true
+		dedup = append(dedup, p)
+	}
+	cmd.Permissions = dedup
+
+	return nil
+}
+
+type DeleteCustomRoleCommand struct {
+	OrgID int64
+	UID   string
+	// Force removes the assignments of the role, otherwise an assigned role is not deleted.
+	Force bool
+}
+
 const (
 	GlobalOrgID                  = 0
 	FixedRolePrefix              = "fixed:"
 	ManagedRolePrefix            = "managed:"
+	CustomRolePrefix             = "custom:"
 	BasicRolePrefix              = "basic:"
 	PluginRolePrefix             = "plugins:"
 	ExternalServiceRolePrefix    = "externalservice:"
@@ -459,6 +511,25 @@ const (
 	ActionAlertingProvisioningReadSecrets = "alert.provisioning.secrets:read"
 	ActionAlertingProvisioningWrite       = "alert.provisioning:write"
 
+	// Custom roles actions
+	ActionRolesRead   = "roles:read"
+	ActionRolesWrite  = "roles:write"
+	ActionRolesDelete = "roles:delete"
+
+	// Role assignments actions
+	ActionUsersRolesRead   = "users.roles:read"
+	ActionUsersRolesAdd    = "users.roles:add"
+	ActionUsersRolesRemove = "users.roles:remove"
+	ActionTeamsRolesRead   = "teams.roles:read"
+	ActionTeamsRolesAdd    = "teams.roles:add"
+	ActionTeamsRolesRemove = "teams.roles:remove"
+
+	// Roles scopes
+	ScopeRolesAll = "roles:*"
+	// ScopePermissionsDelegate is used to manage roles and their assignments, the permissions granted
+	// this way have to be held by the signed in user to prevent escalation of privileges.
+	ScopePermissionsDelegate = "permissions:type:delegate"
+
 	// Feature Management actions
 	ActionFeatureManagementRead  = "featuremgmt.read"
 	ActionFeatureManagementWrite = "featuremgmt.write"
@@ -468,6 +539,9 @@ var (
 	// Team scope
 	ScopeTeamsID = Scope("teams", "id", Parameter(":teamId"))
 
+	// Roles scopes
+	ScopeRolesUID = Scope("roles", "uid", Parameter(":roleUID"))
+
 	// Annotation scopes
 	ScopeAnnotationsRoot             = "annotations"
 	ScopeAnnotationsProvider         = NewScopeProvider(ScopeAnnotationsRoot)
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/web"
@@ -160,6 +162,8 @@ func (a *api) setUserPermission(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "failed to set user permission", err)
 	}
 
+	a.logAuditEvent(c, resourceID, audit.Summary{"userId": userID, "permission": cmd.Permission})
+
 	return permissionSetResponse(cmd)
 }
 
@@ -180,6 +184,8 @@ func (a *api) setTeamPermission(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "failed to set team permission", err)
 	}
 
+	a.logAuditEvent(c, resourceID, audit.Summary{"teamId": teamID, "permission": cmd.Permission})
+
 	return permissionSetResponse(cmd)
 }
 
@@ -197,6 +203,8 @@ func (a *api) setBuiltinRolePermission(c *contextmodel.ReqContext) response.Resp
 		return response.Error(http.StatusBadRequest, "failed to set role permission", err)
 	}
 
+	a.logAuditEvent(c, resourceID, audit.Summary{"builtInRole": builtInRole, "permission": cmd.Permission})
+
 	return permissionSetResponse(cmd)
 }
 
@@ -213,9 +221,20 @@ func (a *api) setPermissions(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "failed to set permissions", err)
 	}
 
+	a.logAuditEvent(c, resourceID, audit.Summary{"permissions": cmd.Permissions})
+
 	return response.Success("Permissions updated")
 }
 
+// logAuditEvent records a permission change. The audit resource kind is the singular
+// of the access control resource, e.g. dashboard for dashboards.
+func (a *api) logAuditEvent(c *contextmodel.ReqContext, resourceID string, after audit.Summary) {
+	resource := audit.Resource(c.SignedInUser.GetOrgID(), strings.TrimSuffix(a.service.options.Resource, "s"), resourceID)
+	event := audit.NewEvent(c.SignedInUser, c.Req, audit.ActionSetPermissions, resource)
+	event.After = after
+	a.service.auditService.Log(c.Req.Context(), event)
+}
+
 func permissionSetResponse(cmd setPermissionCommand) response.Response {
 	message := "Permission updated"
 	if cmd.Permission == "" {
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/licensing"
@@ -55,7 +56,7 @@ type Store interface {
 func New(
 	options Options, features featuremgmt.FeatureToggles, router routing.RouteRegister, license licensing.Licensing,
 	ac accesscontrol.AccessControl, service accesscontrol.Service, sqlStore db.DB,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, auditService audit.Service,
 ) (*Service, error) {
 	permissions := make([]string, 0, len(options.PermissionsToActions))
 	actionSet := make(map[string]struct{})
@@ -77,16 +78,17 @@ func New(
 	}
 
 	s := &Service{
-		ac:          ac,
-		store:       NewStore(sqlStore, features),
-		options:     options,
-		license:     license,
-		permissions: permissions,
-		actions:     actions,
-		sqlStore:    sqlStore,
-		service:     service,
-		teamService: teamService,
-		userService: userService,
+		ac:           ac,
+		store:        NewStore(sqlStore, features),
+		options:      options,
+		license:      license,
+		permissions:  permissions,
+		actions:      actions,
+		sqlStore:     sqlStore,
+		service:      service,
+		teamService:  teamService,
+		userService:  userService,
+		auditService: auditService,
 	}
 
 	s.api = newApi(ac, router, s)
@@ -108,12 +110,13 @@ type Service struct {
 	api     *api
 	license licensing.Licensing
 
-	options     Options
-	permissions []string
-	actions     []string
-	sqlStore    db.DB
-	teamService team.Service
-	userService user.Service
+	options      Options
+	permissions  []string
+	actions      []string
+	sqlStore     db.DB
+	teamService  team.Service
+	userService  user.Service
+	auditService audit.Service
 }
 
 func (s *Service) GetPermissions(ctx context.Context, user identity.Requester, resourceID string) ([]accesscontrol.ResourcePermission, error) {
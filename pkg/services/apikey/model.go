@@ -1,7 +1,10 @@
 package apikey
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/auth/identity"
@@ -28,6 +31,8 @@ type APIKey struct {
 	Expires          *int64       `db:"expires"`
 	ServiceAccountId *int64       `db:"service_account_id"`
 	IsRevoked        *bool        `xorm:"is_revoked" db:"is_revoked"`
+	// Permissions restricts a service account token to a subset of the permissions of its service account.
+	Permissions Permissions `xorm:"permissions" db:"permissions"`
 }
 
 func (k APIKey) TableName() string { return "api_key" }
@@ -40,6 +45,56 @@ type AddCommand struct {
 	Key              string       `json:"-"`
 	SecondsToLive    int64        `json:"secondsToLive"`
 	ServiceAccountID *int64       `json:"-"`
+	Permissions      Permissions  `json:"-"`
+}
+
+// Permission is an action, and the scope it applies to, that a service account token is allowed to perform.
+// swagger:model TokenPermission
+type Permission struct {
+	// example: dashboards:write
+	Action string `json:"action"`
+	// example: folders:uid:ci
+	Scope string `json:"scope,omitempty"`
+}
+
+// Permissions are stored as JSON, a token without permissions has the permissions of its service account.
+type Permissions []Permission
+
+func (p *Permissions) FromDB(data []byte) error {
+	if len(data) == 0 {
+		*p = nil
+		return nil
+	}
+	return json.Unmarshal(data, p)
+}
+
+func (p Permissions) ToDB() ([]byte, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+func (p *Permissions) Scan(val interface{}) error {
+	switch v := val.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		return p.FromDB(v)
+	case string:
+		return p.FromDB([]byte(v))
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+}
+
+func (p Permissions) Value() (driver.Value, error) {
+	b, err := p.ToDB()
+	if b == nil || err != nil {
+		return nil, err
+	}
+	return string(b), nil
 }
 
 type DeleteCommand struct {
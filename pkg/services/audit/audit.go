@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/grn"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// Service records audit events for administrative and data access actions.
+type Service interface {
+	// Log records an event. Events are written to the configured sinks in the background,
+	// so a slow or failing sink never blocks or fails the action being audited.
+	Log(ctx context.Context, event Event)
+	// Enabled returns whether events of the action are recorded, so that callers can skip
+	// looking up the state of a resource only needed for the event.
+	Enabled(action Action) bool
+	// Search returns the events stored in the database that match the query.
+	Search(ctx context.Context, query *SearchQuery) (*SearchResult, error)
+}
+
+// Action is the kind of change recorded by an audit event.
+type Action string
+
+const (
+	ActionCreate         Action = "create"
+	ActionUpdate         Action = "update"
+	ActionDelete         Action = "delete"
+	ActionSetPermissions Action = "set-permissions"
+	ActionSetRole        Action = "set-role"
+	// ActionQuery is a data access event, only recorded when data access auditing is enabled.
+	ActionQuery Action = "query"
+)
+
+// Resource kinds used in the GRN of audit events.
+const (
+	KindAlertRule           = "alert-rule"
+	KindDashboard           = "dashboard"
+	KindDashboardDraft      = "dashboard-draft"
+	KindDatasource          = "datasource"
+	KindOrgUser             = "org-user"
+	KindServiceAccountToken = "service-account-token"
+	KindUser                = "user"
+)
+
+// Summary is a short description of the state of a resource before or after a change.
+// It is not meant to hold the whole resource, only the fields relevant when auditing.
+type Summary map[string]any
+
+// Event is a single audited action.
+type Event struct {
+	ID        int64
+	OrgID     int64
+	Timestamp time.Time
+	Actor     Actor
+	Action    Action
+	Resource  grn.GRN
+	Before    Summary
+	After     Summary
+	Request   RequestMeta
+}
+
+// Actor is the identity performing the action.
+type Actor struct {
+	// Namespace is one of the identity namespaces, e.g. user or service-account.
+	Namespace string `json:"namespace"`
+	ID        string `json:"id"`
+	Login     string `json:"login,omitempty"`
+}
+
+// RequestMeta holds the metadata of the HTTP request that triggered the action.
+type RequestMeta struct {
+	IPAddress string `json:"ipAddress,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path,omitempty"`
+	TraceID   string `json:"traceId,omitempty"`
+}
+
+type eventDTO struct {
+	ID        int64       `json:"id,omitempty"`
+	OrgID     int64       `json:"orgId"`
+	Timestamp time.Time   `json:"timestamp"`
+	Actor     Actor       `json:"actor"`
+	Action    Action      `json:"action"`
+	Resource  string      `json:"resource"`
+	Before    Summary     `json:"before,omitempty"`
+	After     Summary     `json:"after,omitempty"`
+	Request   RequestMeta `json:"request"`
+}
+
+// MarshalJSON encodes the resource of the event as a GRN string.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(eventDTO{
+		ID:        e.ID,
+		OrgID:     e.OrgID,
+		Timestamp: e.Timestamp,
+		Actor:     e.Actor,
+		Action:    e.Action,
+		Resource:  e.Resource.String(),
+		Before:    e.Before,
+		After:     e.After,
+		Request:   e.Request,
+	})
+}
+
+// UnmarshalJSON decodes an event encoded with MarshalJSON.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var dto eventDTO
+	if err := json.Unmarshal(b, &dto); err != nil {
+		return err
+	}
+	resource, err := grn.ParseStr(dto.Resource)
+	if err != nil {
+		return err
+	}
+	*e = Event{
+		ID:        dto.ID,
+		OrgID:     dto.OrgID,
+		Timestamp: dto.Timestamp,
+		Actor:     dto.Actor,
+		Action:    dto.Action,
+		Resource:  resource,
+		Before:    dto.Before,
+		After:     dto.After,
+		Request:   dto.Request,
+	}
+	return nil
+}
+
+// Resource returns the GRN of a resource in an organization.
+func Resource(orgID int64, kind, id string) grn.GRN {
+	return grn.GRN{TenantID: orgID, ResourceKind: kind, ResourceIdentifier: id}
+}
+
+// NewEvent returns an event for an action performed by the requester, with the metadata of the request.
+// The requester and the request can be nil for actions that are not triggered by an API call.
+func NewEvent(requester identity.Requester, req *http.Request, action Action, resource grn.GRN) Event {
+	event := Event{
+		OrgID:     resource.TenantID,
+		Timestamp: time.Now(),
+		Action:    action,
+		Resource:  resource,
+	}
+
+	if requester != nil && !requester.IsNil() {
+		namespace, id := requester.GetNamespacedID()
+		event.OrgID = requester.GetOrgID()
+		event.Actor = Actor{Namespace: namespace, ID: id, Login: requester.GetLogin()}
+	}
+
+	if req != nil {
+		event.Request = RequestMeta{
+			IPAddress: web.RemoteAddr(req),
+			UserAgent: req.UserAgent(),
+			Method:    req.Method,
+			Path:      req.URL.Path,
+			TraceID:   tracing.TraceIDFromContext(req.Context(), false),
+		}
+	}
+
+	return event
+}
+
+type SearchQuery struct {
+	// OrgID limits the search to a single organization, 0 searches all of them.
+	OrgID        int64
+	ActorLogin   string
+	Action       Action
+	ResourceKind string
+	ResourceID   string
+	From         time.Time
+	To           time.Time
+	Page         int
+	Limit        int
+}
+
+type SearchResult struct {
+	TotalCount int64   `json:"totalCount"`
+	Events     []Event `json:"events"`
+	Page       int     `json:"page"`
+	PerPage    int     `json:"perPage"`
+}
@@ -0,0 +1,106 @@
+package auditimpl
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/admin/audit", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/events", authorize(ac.EvalPermission(ActionRead)), routing.Wrap(s.handleSearch))
+	})
+}
+
+// swagger:route GET /admin/audit/events admin_audit searchAuditEvents
+//
+// Search audit events.
+//
+// Returns the audit events matching the search criteria, newest first.
+// Use the `perpage` and `page` query parameters to paginate the results, the default page size is 100.
+//
+// Responses:
+// 200: searchAuditEventsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSearch(c *contextmodel.ReqContext) response.Response {
+	query := &audit.SearchQuery{
+		OrgID:        c.QueryInt64("orgId"),
+		ActorLogin:   c.Query("actor"),
+		Action:       audit.Action(c.Query("action")),
+		ResourceKind: c.Query("kind"),
+		ResourceID:   c.Query("resource"),
+		Page:         c.QueryInt("page"),
+		Limit:        c.QueryInt("perpage"),
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.UnixMilli(from)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.UnixMilli(to)
+	}
+
+	result, err := s.Search(c.Req.Context(), query)
+	if err != nil {
+		if errors.Is(err, errDatabaseSinkDisabled) {
+			return response.Error(http.StatusNotFound, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to search audit events", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters searchAuditEvents
+type SearchAuditEventsParams struct {
+	// Limit the search to an organization
+	// in:query
+	// required:false
+	OrgID int64 `json:"orgId"`
+	// Login of the user or service account that performed the action
+	// in:query
+	// required:false
+	Actor string `json:"actor"`
+	// in:query
+	// required:false
+	Action string `json:"action"`
+	// Kind of the resource, e.g. dashboard or datasource
+	// in:query
+	// required:false
+	Kind string `json:"kind"`
+	// Identifier of the resource, e.g. the dashboard UID
+	// in:query
+	// required:false
+	Resource string `json:"resource"`
+	// From time in epoch milliseconds
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// To time in epoch milliseconds
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// in:query
+	// required:false
+	// default:100
+	PerPage int `json:"perpage"`
+	// in:query
+	// required:false
+	// default:1
+	Page int `json:"page"`
+}
+
+// swagger:response searchAuditEventsResponse
+type SearchAuditEventsResponse struct {
+	// in: body
+	Body audit.SearchResult `json:"body"`
+}
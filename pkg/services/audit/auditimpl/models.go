@@ -0,0 +1,68 @@
+package auditimpl
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	ActionRead = "audit.events:read"
+)
+
+const (
+	sinkDatabase = "database"
+	sinkFile     = "file"
+	sinkSyslog   = "syslog"
+)
+
+const (
+	defaultBufferSize = 1000
+	defaultPerPage    = 100
+	maxPerPage        = 1000
+)
+
+var errDatabaseSinkDisabled = errors.New("audit events are not stored in the database, enable the database sink to search them")
+
+var eventsReaderRole = accesscontrol.RoleDTO{
+	Name:        "fixed:audit.events:reader",
+	DisplayName: "Audit events reader",
+	Description: "Search the audit events of all organizations",
+	Group:       "Audit",
+	Permissions: []accesscontrol.Permission{
+		{Action: ActionRead},
+	},
+}
+
+func (s *Service) declareFixedRoles(ac accesscontrol.Service) error {
+	return ac.DeclareFixedRoles(accesscontrol.RoleRegistration{
+		Role:   eventsReaderRole,
+		Grants: []string{accesscontrol.RoleGrafanaAdmin},
+	})
+}
+
+// eventRecord is the database representation of an audit event.
+type eventRecord struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	OrgID          int64  `xorm:"org_id"`
+	Created        int64  `xorm:"'created'"`
+	ActorNamespace string `xorm:"actor_namespace"`
+	ActorID        string `xorm:"actor_id"`
+	ActorLogin     string `xorm:"actor_login"`
+	Action         string `xorm:"action"`
+	ResourceOrgID  int64  `xorm:"resource_org_id"`
+	ResourceKind   string `xorm:"resource_kind"`
+	ResourceID     string `xorm:"resource_id"`
+	Before         string `xorm:"summary_before"`
+	After          string `xorm:"summary_after"`
+	Request        string `xorm:"request"`
+}
+
+func (r eventRecord) TableName() string {
+	return "audit_event"
+}
+
+type DeleteOldEventsCommand struct {
+	OlderThan time.Time
+}
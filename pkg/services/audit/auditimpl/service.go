@@ -0,0 +1,200 @@
+package auditimpl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const cleanUpInterval = time.Hour
+
+var _ audit.Service = (*Service)(nil)
+
+type Service struct {
+	accessControl ac.AccessControl
+	lock          *serverlock.ServerLockService
+	log           log.Logger
+
+	// store is nil unless the database sink is enabled
+	store  store
+	sinks  []sink
+	events chan audit.Event
+
+	enabled    bool
+	dataAccess bool
+	retention  time.Duration
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	lock *serverlock.ServerLockService,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("audit")
+	s := &Service{
+		accessControl: accessControl,
+		lock:          lock,
+		log:           log.New("audit"),
+		enabled:       section.Key("enabled").MustBool(false),
+		dataAccess:    section.Key("data_access").MustBool(false),
+	}
+
+	if !s.enabled {
+		return s, nil
+	}
+
+	retention, err := gtime.ParseDuration(section.Key("retention").MustString("90d"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid [audit] retention: %w", err)
+	}
+	s.retention = retention
+
+	s.events = make(chan audit.Event, section.Key("buffer_size").MustInt(defaultBufferSize))
+
+	for _, name := range util.SplitString(section.Key("sinks").MustString(sinkDatabase)) {
+		switch name {
+		case sinkDatabase:
+			s.store = &xormStore{db: sql}
+			s.sinks = append(s.sinks, s.store)
+		case sinkFile:
+			path := section.Key("file_path").MustString(filepath.Join(cfg.LogsPath, "audit.log"))
+			fs, err := newFileSink(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open audit file %s: %w", path, err)
+			}
+			s.sinks = append(s.sinks, fs)
+		case sinkSyslog:
+			ss, err := newSyslogSink(
+				section.Key("syslog_network").MustString(""),
+				section.Key("syslog_address").MustString(""),
+				section.Key("syslog_facility").MustString("local7"),
+				section.Key("syslog_tag").MustString("grafana-audit"),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+			}
+			s.sinks = append(s.sinks, ss)
+		default:
+			return nil, fmt.Errorf("unknown [audit] sink %q, expected one of %s, %s or %s", name, sinkDatabase, sinkFile, sinkSyslog)
+		}
+	}
+
+	if s.store != nil {
+		if err := s.declareFixedRoles(accesscontrolService); err != nil {
+			return nil, err
+		}
+		s.registerAPIEndpoints(routeRegister)
+	}
+
+	return s, nil
+}
+
+// Run writes the logged events to the sinks and deletes the events stored
+// in the database once they are older than the retention.
+func (s *Service) Run(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+
+	ticker := time.NewTicker(cleanUpInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event := <-s.events:
+			s.write(ctx, event)
+		case <-ticker.C:
+			s.cleanup(ctx)
+		case <-ctx.Done():
+			s.flush()
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) Log(ctx context.Context, event audit.Event) {
+	if !s.Enabled(event.Action) {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		s.log.FromContext(ctx).Warn("Audit event buffer is full, dropping event", "action", event.Action, "resource", event.Resource.String())
+	}
+}
+
+func (s *Service) Enabled(action audit.Action) bool {
+	if action == audit.ActionQuery {
+		return s.enabled && s.dataAccess
+	}
+	return s.enabled
+}
+
+func (s *Service) Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error) {
+	if s.store == nil {
+		return nil, errDatabaseSinkDisabled
+	}
+	return s.store.Search(ctx, query)
+}
+
+func (s *Service) write(ctx context.Context, event audit.Event) {
+	for _, sink := range s.sinks {
+		if err := sink.Write(ctx, event); err != nil {
+			s.log.Error("Failed to write audit event", "sink", fmt.Sprintf("%T", sink), "action", event.Action, "resource", event.Resource.String(), "error", err)
+		}
+	}
+}
+
+// flush writes the events still in the buffer and closes the sinks.
+func (s *Service) flush() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for len(s.events) > 0 {
+		s.write(ctx, <-s.events)
+	}
+
+	for _, sink := range s.sinks {
+		if err := sink.Close(); err != nil {
+			s.log.Warn("Failed to close audit sink", "sink", fmt.Sprintf("%T", sink), "error", err)
+		}
+	}
+}
+
+func (s *Service) cleanup(ctx context.Context) {
+	if s.store == nil || s.retention <= 0 {
+		return
+	}
+
+	err := s.lock.LockAndExecute(ctx, "delete old audit events", cleanUpInterval, func(context.Context) {
+		cmd := DeleteOldEventsCommand{
+			OlderThan: time.Now().Add(-s.retention),
+		}
+		if deleted, err := s.store.DeleteOldEvents(ctx, cmd); err != nil {
+			s.log.Error("Problem deleting old audit events", "error", err)
+		} else {
+			s.log.Debug("Deleted old audit events", "rows affected", deleted)
+		}
+	})
+	if err != nil {
+		s.log.Error("Failed to lock and execute cleanup of old audit events", "error", err)
+	}
+}
@@ -0,0 +1,131 @@
+package auditimpl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+type fakeSink struct {
+	mu     sync.Mutex
+	events []audit.Event
+	closed bool
+}
+
+func (s *fakeSink) Write(_ context.Context, event audit.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestService_Log(t *testing.T) {
+	newService := func(dataAccess bool, bufferSize int) (*Service, *fakeSink) {
+		fs := &fakeSink{}
+		return &Service{
+			log:        log.NewNopLogger(),
+			sinks:      []sink{fs},
+			events:     make(chan audit.Event, bufferSize),
+			enabled:    true,
+			dataAccess: dataAccess,
+		}, fs
+	}
+	dashboard := audit.Resource(1, audit.KindDashboard, "abc")
+
+	t.Run("writes buffered events to the sinks on shutdown", func(t *testing.T) {
+		s, fs := newService(false, 10)
+		s.Log(context.Background(), audit.Event{Action: audit.ActionCreate, Resource: dashboard})
+		s.Log(context.Background(), audit.Event{Action: audit.ActionDelete, Resource: dashboard})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, s.Run(ctx), context.Canceled)
+
+		require.Len(t, fs.events, 2)
+		assert.False(t, fs.events[0].Timestamp.IsZero())
+		assert.True(t, fs.closed)
+	})
+
+	t.Run("drops data access events unless enabled", func(t *testing.T) {
+		s, _ := newService(false, 10)
+		s.Log(context.Background(), audit.Event{Action: audit.ActionQuery, Resource: dashboard})
+		assert.Len(t, s.events, 0)
+
+		s, _ = newService(true, 10)
+		s.Log(context.Background(), audit.Event{Action: audit.ActionQuery, Resource: dashboard})
+		assert.Len(t, s.events, 1)
+	})
+
+	t.Run("drops events when the buffer is full", func(t *testing.T) {
+		s, _ := newService(false, 1)
+		s.Log(context.Background(), audit.Event{Action: audit.ActionCreate, Resource: dashboard})
+		s.Log(context.Background(), audit.Event{Action: audit.ActionUpdate, Resource: dashboard})
+		require.Len(t, s.events, 1)
+		assert.Equal(t, audit.ActionCreate, (<-s.events).Action)
+	})
+
+	t.Run("does nothing when disabled", func(t *testing.T) {
+		s := &Service{log: log.NewNopLogger()}
+		s.Log(context.Background(), audit.Event{Action: audit.ActionCreate, Resource: dashboard})
+		require.NoError(t, s.Run(context.Background()))
+
+		_, err := s.Search(context.Background(), &audit.SearchQuery{})
+		require.ErrorIs(t, err, errDatabaseSinkDisabled)
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	s, err := newFileSink(path)
+	require.NoError(t, err)
+
+	event := audit.Event{
+		OrgID:    1,
+		Actor:    audit.Actor{Namespace: "user", ID: "1", Login: "admin"},
+		Action:   audit.ActionSetRole,
+		Resource: audit.Resource(1, audit.KindOrgUser, "2"),
+		Before:   audit.Summary{"role": "Viewer"},
+		After:    audit.Summary{"role": "Editor"},
+	}
+	require.NoError(t, s.Write(context.Background(), event))
+	require.NoError(t, s.Write(context.Background(), event))
+	require.NoError(t, s.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var decoded audit.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &decoded))
+		assert.Equal(t, event.Resource, decoded.Resource)
+		assert.Equal(t, event.After, decoded.After)
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, 2, lines)
+}
+
+func TestService_Enabled(t *testing.T) {
+	assert.False(t, (&Service{}).Enabled(audit.ActionSetRole))
+	assert.True(t, (&Service{enabled: true}).Enabled(audit.ActionSetRole))
+	assert.False(t, (&Service{enabled: true}).Enabled(audit.ActionQuery))
+	assert.True(t, (&Service{enabled: true, dataAccess: true}).Enabled(audit.ActionQuery))
+}
@@ -0,0 +1,48 @@
+package auditimpl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+// sink is a destination of the audit events.
+type sink interface {
+	Write(ctx context.Context, event audit.Event) error
+	Close() error
+}
+
+// fileSink appends the events to a file, one JSON object per line.
+// The file is not rotated, use an external tool like logrotate with copytruncate.
+type fileSink struct {
+	file *os.File
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Write(_ context.Context, event audit.Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(append(b, '\n'))
+	return err
+}
+
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
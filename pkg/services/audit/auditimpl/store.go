@@ -0,0 +1,200 @@
+package auditimpl
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+type store interface {
+	sink
+	Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error)
+	DeleteOldEvents(ctx context.Context, cmd DeleteOldEventsCommand) (int64, error)
+}
+
+// xormStore is the database sink, it is also used to search the events.
+type xormStore struct {
+	db db.DB
+}
+
+func (xs *xormStore) Write(ctx context.Context, event audit.Event) error {
+	record, err := toRecord(event)
+	if err != nil {
+		return err
+	}
+
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(&record)
+		return err
+	})
+}
+
+func (xs *xormStore) Close() error {
+	return nil
+}
+
+func (xs *xormStore) Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error) {
+	perPage := query.Limit
+	if perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+
+	var (
+		filters []string
+		params  []any
+	)
+	if query.OrgID != 0 {
+		filters = append(filters, "org_id = ?")
+		params = append(params, query.OrgID)
+	}
+	if query.ActorLogin != "" {
+		filters = append(filters, "actor_login = ?")
+		params = append(params, query.ActorLogin)
+	}
+	if query.Action != "" {
+		filters = append(filters, "action = ?")
+		params = append(params, string(query.Action))
+	}
+	if query.ResourceKind != "" {
+		filters = append(filters, "resource_kind = ?")
+		params = append(params, query.ResourceKind)
+	}
+	if query.ResourceID != "" {
+		filters = append(filters, "resource_id = ?")
+		params = append(params, query.ResourceID)
+	}
+	if !query.From.IsZero() {
+		filters = append(filters, "created >= ?")
+		params = append(params, query.From.UnixMilli())
+	}
+	if !query.To.IsZero() {
+		filters = append(filters, "created <= ?")
+		params = append(params, query.To.UnixMilli())
+	}
+	where := "1 = 1"
+	if len(filters) > 0 {
+		where = strings.Join(filters, " AND ")
+	}
+
+	result := &audit.SearchResult{
+		Events:  []audit.Event{},
+		Page:    page,
+		PerPage: perPage,
+	}
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		count, err := sess.Where(where, params...).Count(&eventRecord{})
+		if err != nil {
+			return err
+		}
+		result.TotalCount = count
+
+		var records []eventRecord
+		if err := sess.Where(where, params...).Desc("created", "id").Limit(perPage, (page-1)*perPage).Find(&records); err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			event, err := fromRecord(record)
+			if err != nil {
+				return err
+			}
+			result.Events = append(result.Events, event)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (xs *xormStore) DeleteOldEvents(ctx context.Context, cmd DeleteOldEventsCommand) (int64, error) {
+	var deletedRows int64
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM audit_event WHERE created < ?", cmd.OlderThan.UnixMilli())
+		if err != nil {
+			return err
+		}
+		deletedRows, err = res.RowsAffected()
+		return err
+	})
+	return deletedRows, err
+}
+
+func toRecord(event audit.Event) (eventRecord, error) {
+	before, err := marshalSummary(event.Before)
+	if err != nil {
+		return eventRecord{}, err
+	}
+	after, err := marshalSummary(event.After)
+	if err != nil {
+		return eventRecord{}, err
+	}
+	request, err := json.Marshal(event.Request)
+	if err != nil {
+		return eventRecord{}, err
+	}
+
+	return eventRecord{
+		OrgID:          event.OrgID,
+		Created:        event.Timestamp.UnixMilli(),
+		ActorNamespace: event.Actor.Namespace,
+		ActorID:        event.Actor.ID,
+		ActorLogin:     event.Actor.Login,
+		Action:         string(event.Action),
+		ResourceOrgID:  event.Resource.TenantID,
+		ResourceKind:   event.Resource.ResourceKind,
+		ResourceID:     event.Resource.ResourceIdentifier,
+		Before:         before,
+		After:          after,
+		Request:        string(request),
+	}, nil
+}
+
+func fromRecord(record eventRecord) (audit.Event, error) {
+	event := audit.Event{
+		ID:        record.ID,
+		OrgID:     record.OrgID,
+		Timestamp: time.UnixMilli(record.Created),
+		Actor: audit.Actor{
+			Namespace: record.ActorNamespace,
+			ID:        record.ActorID,
+			Login:     record.ActorLogin,
+		},
+		Action:   audit.Action(record.Action),
+		Resource: audit.Resource(record.ResourceOrgID, record.ResourceKind, record.ResourceID),
+	}
+
+	if record.Before != "" {
+		if err := json.Unmarshal([]byte(record.Before), &event.Before); err != nil {
+			return event, err
+		}
+	}
+	if record.After != "" {
+		if err := json.Unmarshal([]byte(record.After), &event.After); err != nil {
+			return event, err
+		}
+	}
+	if record.Request != "" {
+		if err := json.Unmarshal([]byte(record.Request), &event.Request); err != nil {
+			return event, err
+		}
+	}
+	return event, nil
+}
+
+func marshalSummary(summary audit.Summary) (string, error) {
+	if len(summary) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(summary)
+	return string(b), err
+}
@@ -0,0 +1,114 @@
+package auditimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+func TestIntegrationAuditEventStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := &xormStore{db: db.InitTestDB(t)}
+
+	now := time.Now().Truncate(time.Millisecond)
+	events := []audit.Event{
+		{
+			OrgID:     1,
+			Timestamp: now.Add(-2 * time.Hour),
+			Actor:     audit.Actor{Namespace: "user", ID: "1", Login: "admin"},
+			Action:    audit.ActionCreate,
+			Resource:  audit.Resource(1, audit.KindDashboard, "abc"),
+			After:     audit.Summary{"title": "Home", "version": float64(1)},
+			Request:   audit.RequestMeta{IPAddress: "127.0.0.1", Method: "POST", Path: "/api/dashboards/db"},
+		},
+		{
+			OrgID:     1,
+			Timestamp: now.Add(-time.Hour),
+			Actor:     audit.Actor{Namespace: "user", ID: "1", Login: "admin"},
+			Action:    audit.ActionUpdate,
+			Resource:  audit.Resource(1, audit.KindDashboard, "abc"),
+			Before:    audit.Summary{"version": float64(1)},
+			After:     audit.Summary{"title": "Home", "version": float64(2)},
+		},
+		{
+			OrgID:     2,
+			Timestamp: now,
+			Actor:     audit.Actor{Namespace: "service-account", ID: "3", Login: "sa-ci"},
+			Action:    audit.ActionDelete,
+			Resource:  audit.Resource(2, audit.KindDatasource, "prom"),
+		},
+	}
+	for _, event := range events {
+		require.NoError(t, s.Write(ctx, event))
+	}
+
+	t.Run("returns the newest events first", func(t *testing.T) {
+		result, err := s.Search(ctx, &audit.SearchQuery{})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), result.TotalCount)
+		require.Len(t, result.Events, 3)
+
+		assert.Equal(t, audit.ActionDelete, result.Events[0].Action)
+		assert.Equal(t, audit.ActionUpdate, result.Events[1].Action)
+
+		created := result.Events[2]
+		assert.Equal(t, events[0].Timestamp.UnixMilli(), created.Timestamp.UnixMilli())
+		assert.Equal(t, events[0].Actor, created.Actor)
+		assert.Equal(t, events[0].Resource, created.Resource)
+		assert.Equal(t, events[0].After, created.After)
+		assert.Nil(t, created.Before)
+		assert.Equal(t, events[0].Request, created.Request)
+	})
+
+	t.Run("filters events", func(t *testing.T) {
+		testCases := []struct {
+			desc     string
+			query    audit.SearchQuery
+			expected int64
+		}{
+			{desc: "by org", query: audit.SearchQuery{OrgID: 2}, expected: 1},
+			{desc: "by actor", query: audit.SearchQuery{ActorLogin: "admin"}, expected: 2},
+			{desc: "by action", query: audit.SearchQuery{Action: audit.ActionUpdate}, expected: 1},
+			{desc: "by resource", query: audit.SearchQuery{ResourceKind: audit.KindDashboard, ResourceID: "abc"}, expected: 2},
+			{desc: "by time range", query: audit.SearchQuery{From: now.Add(-90 * time.Minute), To: now.Add(-time.Minute)}, expected: 1},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				result, err := s.Search(ctx, &tc.query)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, result.TotalCount)
+				assert.Len(t, result.Events, int(tc.expected))
+			})
+		}
+	})
+
+	t.Run("paginates events", func(t *testing.T) {
+		result, err := s.Search(ctx, &audit.SearchQuery{Page: 2, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), result.TotalCount)
+		assert.Equal(t, 2, result.Page)
+		assert.Equal(t, 2, result.PerPage)
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, audit.ActionCreate, result.Events[0].Action)
+	})
+
+	t.Run("deletes events older than the given time", func(t *testing.T) {
+		deleted, err := s.DeleteOldEvents(ctx, DeleteOldEventsCommand{OlderThan: now.Add(-30 * time.Minute)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		result, err := s.Search(ctx, &audit.SearchQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.TotalCount)
+	})
+}
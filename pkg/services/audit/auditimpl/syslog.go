@@ -0,0 +1,56 @@
+//go:build !windows && !nacl && !plan9
+// +build !windows,!nacl,!plan9
+
+package auditimpl
+
+import (
+	"context"
+	"encoding/json"
+	"log/syslog"
+
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+var facilities = map[string]syslog.Priority{
+	"user":   syslog.LOG_USER,
+	"daemon": syslog.LOG_DAEMON,
+	"auth":   syslog.LOG_AUTH,
+	"local0": syslog.LOG_LOCAL0,
+	"local1": syslog.LOG_LOCAL1,
+	"local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4,
+	"local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6,
+	"local7": syslog.LOG_LOCAL7,
+}
+
+// syslogSink sends the events as JSON messages with the info severity.
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink(network, address, facility, tag string) (sink, error) {
+	prio, ok := facilities[facility]
+	if !ok {
+		prio = syslog.LOG_LOCAL7
+	}
+
+	w, err := syslog.Dial(network, address, prio|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{writer: w}, nil
+}
+
+func (s *syslogSink) Write(_ context.Context, event audit.Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.writer.Info(string(b))
+}
+
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
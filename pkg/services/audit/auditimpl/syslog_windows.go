@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package auditimpl
+
+import (
+	"errors"
+)
+
+func newSyslogSink(network, address, facility, tag string) (sink, error) {
+	return nil, errors.New("the syslog audit sink is not supported on Windows")
+}
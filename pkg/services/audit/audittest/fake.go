@@ -0,0 +1,44 @@
+package audittest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+var _ audit.Service = new(FakeService)
+
+// FakeService keeps the logged events in memory.
+type FakeService struct {
+	mu     sync.Mutex
+	events []audit.Event
+
+	ExpectedResult *audit.SearchResult
+	ExpectedErr    error
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+func (f *FakeService) Log(ctx context.Context, event audit.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+}
+
+func (f *FakeService) Enabled(action audit.Action) bool {
+	return true
+}
+
+func (f *FakeService) Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error) {
+	return f.ExpectedResult, f.ExpectedErr
+}
+
+// Events returns the logged events.
+func (f *FakeService) Events() []audit.Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]audit.Event(nil), f.events...)
+}
@@ -33,12 +33,15 @@ const (
 	ClientForm        = "auth.client.form"
 	ClientProxy       = "auth.client.proxy"
 	ClientSAML        = "auth.client.saml"
+	ClientCert        = "auth.client.cert"
 )
 
 const (
 	MetaKeyUsername   = "username"
 	MetaKeyAuthModule = "authModule"
 	MetaKeyIsLogin    = "isLogin"
+	// MetaKeyMFACode is the one-time password or recovery code sent along with a password
+	MetaKeyMFACode = "mfaCode"
 )
 
 // ClientParams are hints to the auth service about how to handle the identity management
@@ -62,6 +65,9 @@ type ClientParams struct {
 	LookUpParams login.UserLookupParams
 	// SyncPermissions ensure that permissions are loaded from DB and added to the identity
 	SyncPermissions bool
+	// RestrictPermissions limits the permissions loaded with SyncPermissions to the ones included in these,
+	// scopes grouped by action. Used for service account tokens restricted to some of the permissions of the account.
+	RestrictPermissions map[string][]string
 }
 
 type PostAuthHookFn func(ctx context.Context, identity *Identity, r *Request) error
@@ -230,6 +236,9 @@ type Identity struct {
 	ClientParams ClientParams
 	// Permissions is the list of permissions the entity has.
 	Permissions map[int64]map[string][]string
+	// MFARecoveryCodes are the recovery codes of a multi-factor authentication enrollment confirmed
+	// while logging in. They are returned once, in the login response.
+	MFARecoveryCodes []string
 }
 
 // Role returns the role of the identity in the active organization.
@@ -343,6 +352,9 @@ type RedirectValidator func(url string) error
 func HandleLoginResponse(r *http.Request, w http.ResponseWriter, cfg *setting.Cfg, identity *Identity, validator RedirectValidator) *response.NormalResponse {
 	result := map[string]interface{}{"message": "Logged in"}
 	result["redirectUrl"] = handleLogin(r, w, cfg, identity, validator)
+	if len(identity.MFARecoveryCodes) > 0 {
+		result["mfaRecoveryCodes"] = identity.MFARecoveryCodes
+	}
 	return response.JSON(http.StatusOK, result)
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ldap/service"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/mfa"
 	"github.com/grafana/grafana/pkg/services/oauthserver"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -68,6 +69,7 @@ func ProvideService(
 	socialService social.Service, cache *remotecache.RemoteCache,
 	ldapService service.LDAP, registerer prometheus.Registerer,
 	signingKeysService signingkeys.Service, oauthServer oauthserver.OAuth2Server,
+	mfaService mfa.Service,
 ) *Service {
 	s := &Service{
 		log:            log.New("authn.service"),
@@ -110,7 +112,7 @@ func ProvideService(
 
 	// if we have password clients configure check if basic auth or form auth is enabled
 	if len(passwordClients) > 0 {
-		passwordClient := clients.ProvidePassword(loginAttempts, passwordClients...)
+		passwordClient := clients.ProvidePassword(loginAttempts, mfaService, passwordClients...)
 		if s.cfg.BasicAuthEnabled {
 			s.RegisterClient(clients.ProvideBasic(passwordClient))
 		}
@@ -133,6 +135,18 @@ func ProvideService(
 		s.RegisterClient(clients.ProvideJWT(jwtService, cfg))
 	}
 
+	if s.cfg.ClientCertAuthEnabled {
+		if cfg.Protocol != setting.HTTPSScheme && cfg.Protocol != setting.HTTP2Scheme {
+			s.log.Warn("Client certificate authentication requires the https or h2 protocol")
+		}
+		clientCert, err := clients.ProvideClientCert(cfg, userService)
+		if err != nil {
+			s.log.Error("Failed to configure client certificate authentication", "err", err)
+		} else {
+			s.RegisterClient(clientCert)
+		}
+	}
+
 	if s.cfg.ExtendedJWTAuthEnabled && features.IsEnabled(featuremgmt.FlagExternalServiceAuth) {
 		s.RegisterClient(clients.ProvideExtendedJWT(userService, cfg, signingKeysService, oauthServer))
 	}
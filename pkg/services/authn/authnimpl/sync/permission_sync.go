@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
@@ -40,6 +41,28 @@ func (s *PermissionsSync) SyncPermissionsHook(ctx context.Context, identity *aut
 	if identity.Permissions == nil {
 		identity.Permissions = make(map[int64]map[string][]string)
 	}
+
+	if identity.ClientParams.RestrictPermissions != nil {
+		// The identity only gets the permissions it has that are also included in the restriction.
+		identity.Permissions[identity.OrgID] = accesscontrol.Intersect(permissions, restrictionPermissions(identity.ClientParams.RestrictPermissions))
+		// Routes that still check the organization role would otherwise grant everything the role allows.
+		if identity.OrgRoles == nil {
+			identity.OrgRoles = make(map[int64]org.RoleType)
+		}
+		identity.OrgRoles[identity.OrgID] = org.RoleNone
+		return nil
+	}
+
 	identity.Permissions[identity.OrgID] = accesscontrol.GroupScopesByAction(permissions)
 	return nil
 }
+
+func restrictionPermissions(restriction map[string][]string) []accesscontrol.Permission {
+	permissions := make([]accesscontrol.Permission, 0, len(restriction))
+	for action, scopes := range restriction {
+		for _, scope := range scopes {
+			permissions = append(permissions, accesscontrol.Permission{Action: action, Scope: scope})
+		}
+	}
+	return permissions
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,6 +50,40 @@ func TestPermissionsSync_SyncPermission(t *testing.T) {
 	}
 }
 
+func TestPermissionsSync_SyncPermissionWithRestriction(t *testing.T) {
+	s := &PermissionsSync{
+		ac: &acmock.Mock{
+			GetUserPermissionsFunc: func(ctx context.Context, siu *user.SignedInUser, o accesscontrol.Options) ([]accesscontrol.Permission, error) {
+				return []accesscontrol.Permission{
+					{Action: "dashboards:read", Scope: "dashboards:*"},
+					{Action: "dashboards:read", Scope: "folders:*"},
+					{Action: "dashboards:write", Scope: "folders:uid:ci"},
+					{Action: "dashboards:delete", Scope: "folders:*"},
+				}, nil
+			},
+		},
+		log: log.NewNopLogger(),
+	}
+
+	identity := &authn.Identity{ID: "service-account:2", OrgID: 1, OrgRoles: map[int64]org.RoleType{1: org.RoleEditor}, ClientParams: authn.ClientParams{
+		SyncPermissions: true,
+		RestrictPermissions: map[string][]string{
+			"dashboards:read":  {"folders:uid:ci"},
+			"dashboards:write": {"folders:*"},
+			"datasources:read": {"datasources:*"},
+		},
+	}}
+
+	require.NoError(t, s.SyncPermissionsHook(context.Background(), identity, &authn.Request{}))
+	assert.Equal(t, map[string][]string{
+		"dashboards:read":  {"folders:uid:ci"},
+		"dashboards:write": {"folders:uid:ci"},
+	}, identity.Permissions[1])
+	// the organization role doesn't grant more than the restricted permissions
+	assert.Equal(t, org.RoleNone, identity.Role())
+	assert.Equal(t, org.RoleNone, identity.SignedInUser().OrgRole)
+}
+
 func setupTestEnv() *PermissionsSync {
 	acMock := &acmock.Mock{
 		GetUserPermissionsFunc: func(ctx context.Context, siu *user.SignedInUser, o accesscontrol.Options) ([]accesscontrol.Permission, error) {
@@ -82,7 +82,15 @@ func (s *APIKey) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ide
 		return nil, err
 	}
 
-	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceServiceAccount, usr.UserID), usr, authn.ClientParams{SyncPermissions: true}, login.APIKeyAuthModule), nil
+	params := authn.ClientParams{SyncPermissions: true}
+	if len(apiKey.Permissions) > 0 {
+		params.RestrictPermissions = make(map[string][]string)
+		for _, p := range apiKey.Permissions {
+			params.RestrictPermissions[p.Action] = append(params.RestrictPermissions[p.Action], p.Scope)
+		}
+	}
+
+	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceServiceAccount, usr.UserID), usr, params, login.APIKeyAuthModule), nil
 }
 
 func (s *APIKey) getAPIKey(ctx context.Context, token string) (*apikey.APIKey, error) {
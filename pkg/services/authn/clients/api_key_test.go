@@ -91,6 +91,47 @@ func TestAPIKey_Authenticate(t *testing.T) {
 				AuthenticatedBy: login.APIKeyAuthModule,
 			},
 		},
+		{
+			desc: "should restrict the permissions of the identity for a token with permissions",
+			req: &authn.Request{HTTPRequest: &http.Request{
+				Header: map[string][]string{
+					"Authorization": {"Bearer " + secret},
+				},
+			}},
+			expectedKey: &apikey.APIKey{
+				ID:               1,
+				OrgID:            1,
+				Key:              hash,
+				ServiceAccountId: intPtr(1),
+				Permissions: apikey.Permissions{
+					{Action: "dashboards:write", Scope: "folders:uid:ci"},
+					{Action: "dashboards:read", Scope: "folders:uid:ci"},
+					{Action: "dashboards:read", Scope: "folders:uid:shared"},
+				},
+			},
+			expectedUser: &user.SignedInUser{
+				UserID:           1,
+				OrgID:            1,
+				IsServiceAccount: true,
+				OrgRole:          org.RoleEditor,
+				Name:             "test",
+			},
+			expectedIdentity: &authn.Identity{
+				ID:             "service-account:1",
+				OrgID:          1,
+				Name:           "test",
+				OrgRoles:       map[int64]org.RoleType{1: org.RoleEditor},
+				IsGrafanaAdmin: boolPtr(false),
+				ClientParams: authn.ClientParams{
+					SyncPermissions: true,
+					RestrictPermissions: map[string][]string{
+						"dashboards:write": {"folders:uid:ci"},
+						"dashboards:read":  {"folders:uid:ci", "folders:uid:shared"},
+					},
+				},
+				AuthenticatedBy: login.APIKeyAuthModule,
+			},
+		},
 		{
 			desc: "should fail for expired api key",
 			req:  &authn.Request{HTTPRequest: &http.Request{Header: map[string][]string{"Authorization": {"Bearer " + secret}}}},
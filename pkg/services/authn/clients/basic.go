@@ -8,6 +8,9 @@ import (
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
+// mfaCodeHeader holds the one-time password of users with multi-factor authentication
+const mfaCodeHeader = "X-Grafana-MFA-Code"
+
 var (
 	errDecodingBasicAuthHeader = errutil.BadRequest("basic-auth.invalid-header", errutil.WithPublicMessage("Invalid Basic Auth Header"))
 )
@@ -35,6 +38,9 @@ func (c *Basic) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 	if !ok {
 		return nil, errDecodingBasicAuthHeader.Errorf("failed to decode basic auth header")
 	}
+	if code := r.HTTPRequest.Header.Get(mfaCodeHeader); code != "" {
+		r.SetMeta(authn.MetaKeyMFACode, code)
+	}
 
 	return c.client.AuthenticatePassword(ctx, r, username, password)
 }
@@ -0,0 +1,278 @@
+package clients
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var _ authn.ContextAwareClient = new(ClientCert)
+
+var (
+	errClientCertNoRule = errutil.Unauthorized(
+		"client-cert.no-rule", errutil.WithPublicMessage("Client certificate is not mapped to an identity"))
+	errClientCertIdentityNotFound = errutil.Unauthorized(
+		"client-cert.identity-not-found", errutil.WithPublicMessage("Client certificate is not mapped to an identity"))
+)
+
+// Certificate fields that rules can match.
+const (
+	certFieldSubject   = "subject"
+	certFieldSubjectCN = "subject_cn"
+	certFieldDNSSAN    = "dns_san"
+	certFieldEmailSAN  = "email_san"
+	certFieldURISAN    = "uri_san"
+	certFieldSPIFFEID  = "spiffe_id"
+)
+
+// clientCertRule maps the client certificates with a field matching the pattern to a user or a service account.
+// The login, email and name of users can reference the capture groups of the pattern, e.g. $1 or ${name}.
+type clientCertRule struct {
+	Field   string `yaml:"field"`
+	Pattern string `yaml:"pattern"`
+
+	// ServiceAccount is the login of an existing service account, e.g. sa-ci
+	ServiceAccount string `yaml:"service_account"`
+
+	Login        string                 `yaml:"login"`
+	Email        string                 `yaml:"email"`
+	Name         string                 `yaml:"name"`
+	Orgs         []clientCertOrgMapping `yaml:"orgs"`
+	GrafanaAdmin *bool                  `yaml:"grafana_admin"`
+
+	regexp *regexp.Regexp
+}
+
+type clientCertOrgMapping struct {
+	OrgID int64        `yaml:"org_id"`
+	Role  org.RoleType `yaml:"role"`
+}
+
+type clientCertRules struct {
+	Rules []*clientCertRule `yaml:"rules"`
+}
+
+func ProvideClientCert(cfg *setting.Cfg, userService user.Service) (*ClientCert, error) {
+	rules, err := readClientCertRules(cfg.ClientCertAuthRulesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientCert{
+		cfg:         cfg,
+		log:         log.New(authn.ClientCert),
+		userService: userService,
+		rules:       rules,
+	}, nil
+}
+
+type ClientCert struct {
+	cfg         *setting.Cfg
+	log         log.Logger
+	userService user.Service
+	rules       []*clientCertRule
+}
+
+func (c *ClientCert) Name() string {
+	return authn.ClientCert
+}
+
+func (c *ClientCert) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	cert := verifiedClientCert(r)
+	if cert == nil {
+		return nil, errClientCertNoRule.Errorf("no verified client certificate")
+	}
+
+	rule, value, match := c.findRule(cert)
+	if rule == nil {
+		c.log.FromContext(ctx).Debug("No rule matches the client certificate", "subject", cert.Subject.String())
+		return nil, errClientCertNoRule.Errorf("no rule matches the client certificate with subject %q", cert.Subject.String())
+	}
+
+	if rule.ServiceAccount != "" {
+		return c.serviceAccountIdentity(ctx, rule.ServiceAccount)
+	}
+
+	expand := func(template string) string {
+		return string(rule.regexp.ExpandString(nil, template, value, match))
+	}
+
+	id := &authn.Identity{
+		AuthenticatedBy: login.ClientCertModule,
+		AuthID:          value,
+		Login:           expand(rule.Login),
+		Email:           expand(rule.Email),
+		Name:            expand(rule.Name),
+		OrgRoles:        map[int64]org.RoleType{},
+		IsGrafanaAdmin:  rule.GrafanaAdmin,
+		ClientParams: authn.ClientParams{
+			SyncUser:        true,
+			FetchSyncedUser: true,
+			SyncPermissions: true,
+			SyncOrgRoles:    !c.cfg.ClientCertAuthSkipOrgRoleSync && len(rule.Orgs) > 0,
+			AllowSignUp:     c.cfg.ClientCertAuthAutoSignUp,
+		},
+	}
+	for _, o := range rule.Orgs {
+		id.OrgRoles[o.OrgID] = o.Role
+	}
+	if id.Login != "" {
+		id.ClientParams.LookUpParams.Login = &id.Login
+	}
+	if id.Email != "" {
+		id.ClientParams.LookUpParams.Email = &id.Email
+	}
+	if id.Login == "" && id.Email == "" {
+		return nil, errClientCertNoRule.Errorf("rule for %q maps the client certificate to an empty login and email", rule.Pattern)
+	}
+
+	return id, nil
+}
+
+func (c *ClientCert) serviceAccountIdentity(ctx context.Context, saLogin string) (*authn.Identity, error) {
+	usr, err := c.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: saLogin})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errClientCertIdentityNotFound.Errorf("service account %s not found", saLogin)
+		}
+		return nil, err
+	}
+	if !usr.IsServiceAccount {
+		return nil, errClientCertIdentityNotFound.Errorf("%s is not a service account", saLogin)
+	}
+
+	signedInUser, err := c.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{OrgID: usr.OrgID, UserID: usr.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceServiceAccount, signedInUser.UserID), signedInUser, authn.ClientParams{SyncPermissions: true}, login.ClientCertModule), nil
+}
+
+// findRule returns the first rule matching a field of the certificate, the matched value and the submatches.
+func (c *ClientCert) findRule(cert *x509.Certificate) (*clientCertRule, string, []int) {
+	for _, rule := range c.rules {
+		for _, value := range certFieldValues(cert, rule.Field) {
+			if match := rule.regexp.FindStringSubmatchIndex(value); match != nil {
+				return rule, value, match
+			}
+		}
+	}
+	return nil, "", nil
+}
+
+func (c *ClientCert) Test(ctx context.Context, r *authn.Request) bool {
+	return verifiedClientCert(r) != nil
+}
+
+func (c *ClientCert) Priority() uint {
+	// Credentials sent with the request (JWT, API key or basic auth) take precedence over the
+	// certificate of the connection, which in turn takes precedence over the auth proxy and sessions.
+	return 45
+}
+
+// verifiedClientCert returns the leaf certificate presented by the client, if the server verified it.
+func verifiedClientCert(r *authn.Request) *x509.Certificate {
+	if r.HTTPRequest == nil || r.HTTPRequest.TLS == nil {
+		return nil
+	}
+	chains := r.HTTPRequest.TLS.VerifiedChains
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return nil
+	}
+	return chains[0][0]
+}
+
+func certFieldValues(cert *x509.Certificate, field string) []string {
+	switch field {
+	case certFieldSubject:
+		return []string{cert.Subject.String()}
+	case certFieldSubjectCN:
+		return []string{cert.Subject.CommonName}
+	case certFieldDNSSAN:
+		return cert.DNSNames
+	case certFieldEmailSAN:
+		return cert.EmailAddresses
+	case certFieldURISAN, certFieldSPIFFEID:
+		values := make([]string, 0, len(cert.URIs))
+		for _, u := range cert.URIs {
+			if field == certFieldSPIFFEID && u.Scheme != "spiffe" {
+				continue
+			}
+			values = append(values, u.String())
+		}
+		return values
+	}
+	return nil
+}
+
+func readClientCertRules(path string) ([]*clientCertRule, error) {
+	if path == "" {
+		return nil, errors.New("[auth.client_cert] rules_file is required")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client certificate rules: %w", err)
+	}
+
+	var cfg clientCertRules
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate rules %s: %w", path, err)
+	}
+
+	for i, rule := range cfg.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid client certificate rule %d in %s: %w", i+1, path, err)
+		}
+	}
+	return cfg.Rules, nil
+}
+
+func (r *clientCertRule) validate() error {
+	switch r.Field {
+	case certFieldSubject, certFieldSubjectCN, certFieldDNSSAN, certFieldEmailSAN, certFieldURISAN, certFieldSPIFFEID:
+	default:
+		return fmt.Errorf("unknown field %q", r.Field)
+	}
+
+	// patterns match the whole field, so that a pattern for a name also can't match a longer one containing it
+	re, err := regexp.Compile("^(?:" + r.Pattern + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	r.regexp = re
+
+	if r.ServiceAccount != "" {
+		if r.Login != "" || r.Email != "" || len(r.Orgs) > 0 || r.GrafanaAdmin != nil {
+			return errors.New("a rule mapping to a service account can't set a login, an email, orgs or grafana_admin")
+		}
+		return nil
+	}
+
+	if r.Login == "" && r.Email == "" {
+		return errors.New("either service_account, login or email is required")
+	}
+	for _, o := range r.Orgs {
+		if o.OrgID <= 0 {
+			return fmt.Errorf("invalid org_id %d", o.OrgID)
+		}
+		if !o.Role.IsValid() {
+			return fmt.Errorf("invalid role %q for org %d", o.Role, o.OrgID)
+		}
+	}
+	return nil
+}
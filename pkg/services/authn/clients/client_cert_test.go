@@ -0,0 +1,191 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testClientCertRules = `
+rules:
+  - field: spiffe_id
+    pattern: '^spiffe://example\.org/ns/prod/sa/ci$'
+    service_account: sa-ci
+  - field: email_san
+    pattern: '^(?P<login>[a-z]+)@example\.com$'
+    login: '${login}'
+    email: '$0'
+    orgs:
+      - org_id: 2
+        role: Editor
+  - field: subject_cn
+    pattern: 'host-([a-z]+)'
+    login: 'svc-$1'
+`
+
+func TestClientCert_Authenticate(t *testing.T) {
+	type testCase struct {
+		desc             string
+		cert             *x509.Certificate
+		user             *user.User
+		expectedErr      error
+		expectedIdentity *authn.Identity
+	}
+
+	spiffeID, err := url.Parse("spiffe://example.org/ns/prod/sa/ci")
+	require.NoError(t, err)
+
+	tests := []testCase{
+		{
+			desc: "should map a SPIFFE ID to a service account",
+			cert: &x509.Certificate{URIs: []*url.URL{spiffeID}},
+			user: &user.User{ID: 3, OrgID: 1, Login: "sa-ci", IsServiceAccount: true},
+			expectedIdentity: &authn.Identity{
+				ID:              "service-account:3",
+				OrgID:           1,
+				OrgRoles:        map[int64]org.RoleType{1: org.RoleViewer},
+				Login:           "sa-ci",
+				IsGrafanaAdmin:  boolPtr(false),
+				AuthenticatedBy: login.ClientCertModule,
+				ClientParams:    authn.ClientParams{SyncPermissions: true},
+			},
+		},
+		{
+			desc:        "should fail when the service account is a user",
+			cert:        &x509.Certificate{URIs: []*url.URL{spiffeID}},
+			user:        &user.User{ID: 3, OrgID: 1, Login: "sa-ci"},
+			expectedErr: errClientCertIdentityNotFound,
+		},
+		{
+			desc: "should map an email SAN to a user with org roles",
+			cert: &x509.Certificate{EmailAddresses: []string{"other@example.org", "jane@example.com"}},
+			expectedIdentity: &authn.Identity{
+				AuthenticatedBy: login.ClientCertModule,
+				AuthID:          "jane@example.com",
+				Login:           "jane",
+				Email:           "jane@example.com",
+				OrgRoles:        map[int64]org.RoleType{2: org.RoleEditor},
+				ClientParams: authn.ClientParams{
+					SyncUser:        true,
+					FetchSyncedUser: true,
+					SyncPermissions: true,
+					SyncOrgRoles:    true,
+					LookUpParams:    login.UserLookupParams{Login: strPtr("jane"), Email: strPtr("jane@example.com")},
+				},
+			},
+		},
+		{
+			desc: "should map a subject CN to a user without org roles",
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "host-api"}},
+			expectedIdentity: &authn.Identity{
+				AuthenticatedBy: login.ClientCertModule,
+				AuthID:          "host-api",
+				Login:           "svc-api",
+				OrgRoles:        map[int64]org.RoleType{},
+				ClientParams: authn.ClientParams{
+					SyncUser:        true,
+					FetchSyncedUser: true,
+					SyncPermissions: true,
+					LookUpParams:    login.UserLookupParams{Login: strPtr("svc-api")},
+				},
+			},
+		},
+		{
+			desc:        "should match the whole field",
+			cert:        &x509.Certificate{Subject: pkix.Name{CommonName: "evil-host-api.example.org"}},
+			expectedErr: errClientCertNoRule,
+		},
+		{
+			desc:        "should fail when no rule matches",
+			cert:        &x509.Certificate{Subject: pkix.Name{CommonName: "unknown"}},
+			expectedErr: errClientCertNoRule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.ClientCertAuthRulesFile = writeClientCertRules(t, testClientCertRules)
+			userService := &usertest.FakeUserService{ExpectedUser: tt.user}
+			if tt.user != nil {
+				userService.ExpectedSignedInUser = &user.SignedInUser{UserID: tt.user.ID, OrgID: tt.user.OrgID, OrgRole: org.RoleViewer, Login: tt.user.Login, IsServiceAccount: tt.user.IsServiceAccount}
+			}
+
+			c, err := ProvideClientCert(cfg, userService)
+			require.NoError(t, err)
+
+			req := &authn.Request{HTTPRequest: &http.Request{TLS: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{tt.cert}}}}}
+			require.True(t, c.Test(context.Background(), req))
+
+			identity, err := c.Authenticate(context.Background(), req)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, identity)
+				return
+			}
+			require.NoError(t, err)
+			assert.EqualValues(t, tt.expectedIdentity, identity)
+		})
+	}
+}
+
+func TestClientCert_Test(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.ClientCertAuthRulesFile = writeClientCertRules(t, testClientCertRules)
+	c, err := ProvideClientCert(cfg, &usertest.FakeUserService{})
+	require.NoError(t, err)
+
+	assert.False(t, c.Test(context.Background(), &authn.Request{}))
+	assert.False(t, c.Test(context.Background(), &authn.Request{HTTPRequest: &http.Request{}}))
+	// certificates that were not verified by the server are ignored
+	assert.False(t, c.Test(context.Background(), &authn.Request{HTTPRequest: &http.Request{TLS: &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "host-api"}}},
+	}}}))
+}
+
+func TestReadClientCertRules(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":          "rules:\n  - field: issuer\n    pattern: '.*'\n    login: a",
+		"invalid pattern":        "rules:\n  - field: subject_cn\n    pattern: '('\n    login: a",
+		"missing identity":       "rules:\n  - field: subject_cn\n    pattern: '.*'",
+		"service account orgs":   "rules:\n  - field: subject_cn\n    pattern: '.*'\n    service_account: sa-a\n    orgs:\n      - org_id: 1\n        role: Admin",
+		"invalid role":           "rules:\n  - field: subject_cn\n    pattern: '.*'\n    login: a\n    orgs:\n      - org_id: 1\n        role: Owner",
+		"invalid org":            "rules:\n  - field: subject_cn\n    pattern: '.*'\n    login: a\n    orgs:\n      - role: Admin",
+		"broken yaml":            "rules: [",
+		"rules file is required": "",
+	}
+
+	for desc, content := range tests {
+		t.Run(desc, func(t *testing.T) {
+			path := ""
+			if content != "" {
+				path = writeClientCertRules(t, content)
+			}
+			_, err := readClientCertRules(path)
+			require.Error(t, err)
+		})
+	}
+}
+
+func writeClientCertRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
@@ -25,6 +25,8 @@ type Form struct {
 type loginForm struct {
 	Username string `json:"user" binding:"Required"`
 	Password string `json:"password" binding:"Required"`
+	// MFACode is the one-time password, or recovery code, of users with multi-factor authentication
+	MFACode string `json:"mfaCode"`
 }
 
 func (c *Form) Name() string {
@@ -36,5 +38,8 @@ func (c *Form) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ident
 	if err := web.Bind(r.HTTPRequest, &form); err != nil {
 		return nil, errBadForm.Errorf("failed to parse request: %w", err)
 	}
+	if form.MFACode != "" {
+		r.SetMeta(authn.MetaKeyMFACode, form.MFACode)
+	}
 	return c.client.AuthenticatePassword(ctx, r, form.Username, form.Password)
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
@@ -46,3 +47,15 @@ func TestForm_Authenticate(t *testing.T) {
 		})
 	}
 }
+
+func TestForm_AuthenticateWithMFACode(t *testing.T) {
+	req := &authn.Request{HTTPRequest: &http.Request{
+		Header: map[string][]string{"Content-Type": {"application/json"}},
+		Body:   io.NopCloser(strings.NewReader(`{"user": "test", "password": "test", "mfaCode": "123456"}`)),
+	}}
+
+	c := ProvideForm(&authntest.FakePasswordClient{})
+	_, err := c.Authenticate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "123456", req.GetMeta(authn.MetaKeyMFACode))
+}
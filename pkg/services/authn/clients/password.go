@@ -6,7 +6,9 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/mfa"
 	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -16,16 +18,21 @@ var (
 	errPasswordAuthFailed  = errutil.Unauthorized("password-auth.failed", errutil.WithPublicMessage("Invalid username or password"))
 	errInvalidPassword     = errutil.Unauthorized("password-auth.invalid", errutil.WithPublicMessage("Invalid password or username"))
 	errLoginAttemptBlocked = errutil.Unauthorized("login-attempt.blocked", errutil.WithPublicMessage("Invalid username or password"))
+
+	errMFARequired           = errutil.Unauthorized("mfa.required", errutil.WithPublicMessage("Authentication code required"))
+	errMFAEnrollmentRequired = errutil.Unauthorized("mfa.enrollment-required", errutil.WithPublicMessage("Multi-factor authentication is required, set up an authenticator app"))
+	errMFAInvalidCode        = errutil.Unauthorized("mfa.invalid-code", errutil.WithPublicMessage("Invalid authentication code"))
 )
 
 var _ authn.PasswordClient = new(Password)
 
-func ProvidePassword(loginAttempts loginattempt.Service, clients ...authn.PasswordClient) *Password {
-	return &Password{loginAttempts, clients, log.New("authn.password")}
+func ProvidePassword(loginAttempts loginattempt.Service, mfaService mfa.Service, clients ...authn.PasswordClient) *Password {
+	return &Password{loginAttempts, mfaService, clients, log.New("authn.password")}
 }
 
 type Password struct {
 	loginAttempts loginattempt.Service
+	mfa           mfa.Service
 	clients       []authn.PasswordClient
 	log           log.Logger
 }
@@ -33,12 +40,14 @@ type Password struct {
 func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, username, password string) (*authn.Identity, error) {
 	r.SetMeta(authn.MetaKeyUsername, username)
 
-	ok, err := c.loginAttempts.Validate(ctx, username)
+	ipAddress := remoteAddr(r)
+	ok, err := c.loginAttempts.Validate(ctx, username, ipAddress)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, errLoginAttemptBlocked.Errorf("too many consecutive incorrect login attempts for user - login for user temporarily blocked")
+		c.log.FromContext(ctx).Warn("Login blocked after too many failed attempts", "username", username, "ip", ipAddress)
+		return nil, errLoginAttemptBlocked.Errorf("too many consecutive incorrect login attempts for user or IP address - login temporarily blocked")
 	}
 
 	if len(password) == 0 {
@@ -55,12 +64,74 @@ func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, u
 			continue
 		}
 
+		if err := c.verifySecondFactor(ctx, r, identity, username, ipAddress); err != nil {
+			return nil, err
+		}
+
 		return identity, nil
 	}
 
 	if errors.Is(clientErrs, errInvalidPassword) {
-		_ = c.loginAttempts.Add(ctx, username, web.RemoteAddr(r.HTTPRequest))
+		_ = c.loginAttempts.Add(ctx, username, ipAddress)
 	}
 
 	return nil, errPasswordAuthFailed.Errorf("failed to authenticate identity: %w", clientErrs)
 }
+
+// verifySecondFactor challenges local users who enrolled, or have to enroll, in multi-factor authentication
+// for the code sent along with the password. Users who have to enroll receive a new secret with the
+// challenge and confirm the enrollment with their next login, which returns the recovery codes with the identity.
+func (c *Password) verifySecondFactor(ctx context.Context, r *authn.Request, identity *authn.Identity, username, ipAddress string) error {
+	if c.mfa == nil || !c.mfa.IsEnabled() || identity.AuthenticatedBy != login.PasswordAuthModule {
+		return nil
+	}
+
+	namespace, userID := identity.NamespacedID()
+	if namespace != authn.NamespaceUser {
+		return nil
+	}
+
+	status, err := c.mfa.GetStatus(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !status.Enabled && !status.Enforced {
+		return nil
+	}
+
+	code := r.GetMeta(authn.MetaKeyMFACode)
+	if !status.Enabled {
+		if code == "" {
+			enrollment, err := c.mfa.PendingEnrollment(ctx, userID, identity.Login)
+			if err != nil {
+				return err
+			}
+			challenge := errMFAEnrollmentRequired.Errorf("user %d has to enroll in multi-factor authentication", userID)
+			challenge.PublicPayload = map[string]any{"enrollment": enrollment}
+			return challenge
+		}
+		identity.MFARecoveryCodes, err = c.mfa.ConfirmEnrollment(ctx, userID, code)
+	} else {
+		if code == "" {
+			return errMFARequired.Errorf("user %d has to provide an authentication code", userID)
+		}
+		err = c.mfa.Verify(ctx, userID, code)
+	}
+
+	if err != nil {
+		if errors.Is(err, mfa.ErrInvalidCode) || errors.Is(err, mfa.ErrNoPendingSecret) {
+			// codes are short, so failed codes count towards the brute force login protection
+			_ = c.loginAttempts.Add(ctx, username, ipAddress)
+			return errMFAInvalidCode.Errorf("failed to verify authentication code: %w", err)
+		}
+		return err
+	}
+	return nil
+}
+
+func remoteAddr(r *authn.Request) string {
+	if r.HTTPRequest == nil {
+		return ""
+	}
+	return web.RemoteAddr(r.HTTPRequest)
+}
@@ -5,10 +5,15 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 func TestPassword_AuthenticatePassword(t *testing.T) {
@@ -67,7 +72,7 @@ func TestPassword_AuthenticatePassword(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvidePassword(loginattempttest.FakeLoginAttemptService{ExpectedValid: !tt.blockLogin}, tt.clients...)
+			c := ProvidePassword(loginattempttest.FakeLoginAttemptService{ExpectedValid: !tt.blockLogin}, &mfatest.FakeService{}, tt.clients...)
 
 			identity, err := c.AuthenticatePassword(context.Background(), tt.req, tt.username, tt.password)
 			if tt.expectedErr != nil {
@@ -80,3 +85,104 @@ func TestPassword_AuthenticatePassword(t *testing.T) {
 		})
 	}
 }
+
+func TestPassword_SecondFactor(t *testing.T) {
+	type TestCase struct {
+		desc              string
+		code              string
+		authenticatedBy   string
+		status            *mfa.Status
+		verifyErr         error
+		expectedErr       error
+		expectedEnrolment bool
+		expectedVerified  []string
+		expectedConfirmed []string
+		expectedCodes     []string
+	}
+
+	tests := []TestCase{
+		{
+			desc:            "should not challenge users who did not enroll",
+			authenticatedBy: login.PasswordAuthModule,
+			status:          &mfa.Status{},
+		},
+		{
+			desc:            "should not challenge users authenticated by LDAP",
+			authenticatedBy: login.LDAPAuthModule,
+			status:          &mfa.Status{Enabled: true},
+		},
+		{
+			desc:            "should challenge enrolled users without code",
+			authenticatedBy: login.PasswordAuthModule,
+			status:          &mfa.Status{Enabled: true},
+			expectedErr:     errMFARequired,
+		},
+		{
+			desc:             "should verify the code of enrolled users",
+			code:             "123456",
+			authenticatedBy:  login.PasswordAuthModule,
+			status:           &mfa.Status{Enabled: true},
+			expectedVerified: []string{"123456"},
+		},
+		{
+			desc:             "should fail for invalid code",
+			code:             "123456",
+			authenticatedBy:  login.PasswordAuthModule,
+			status:           &mfa.Status{Enabled: true},
+			verifyErr:        mfa.ErrInvalidCode.Errorf("invalid"),
+			expectedErr:      errMFAInvalidCode,
+			expectedVerified: []string{"123456"},
+		},
+		{
+			desc:              "should start the enrollment when enforced",
+			authenticatedBy:   login.PasswordAuthModule,
+			status:            &mfa.Status{Enforced: true},
+			expectedErr:       errMFAEnrollmentRequired,
+			expectedEnrolment: true,
+		},
+		{
+			desc:              "should confirm the enrollment when enforced",
+			code:              "123456",
+			authenticatedBy:   login.PasswordAuthModule,
+			status:            &mfa.Status{Enforced: true},
+			expectedConfirmed: []string{"123456"},
+			expectedCodes:     []string{"abcde-fghjk"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			mfaService := &mfatest.FakeService{
+				ExpectedEnabled:       true,
+				ExpectedStatus:        tt.status,
+				ExpectedEnrollment:    &mfa.Enrollment{Secret: "secret"},
+				ExpectedRecoveryCodes: []string{"abcde-fghjk"},
+				ExpectedVerifyErr:     tt.verifyErr,
+			}
+			identity := &authn.Identity{ID: "user:1", AuthenticatedBy: tt.authenticatedBy}
+			c := ProvidePassword(loginattempttest.FakeLoginAttemptService{ExpectedValid: true}, mfaService, authntest.FakePasswordClient{ExpectedIdentity: identity})
+
+			req := &authn.Request{}
+			if tt.code != "" {
+				req.SetMeta(authn.MetaKeyMFACode, tt.code)
+			}
+			result, err := c.AuthenticatePassword(context.Background(), req, "test", "test")
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, identity, result)
+				assert.Equal(t, tt.expectedCodes, result.MFARecoveryCodes)
+			}
+
+			if tt.expectedEnrolment {
+				var grafanaErr errutil.Error
+				require.ErrorAs(t, err, &grafanaErr)
+				assert.Equal(t, mfaService.ExpectedEnrollment, grafanaErr.Public().Extra["enrollment"])
+			}
+			assert.Equal(t, tt.expectedVerified, mfaService.VerifiedCodes)
+			assert.Equal(t, tt.expectedConfirmed, mfaService.ConfirmedCodes)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package dashboardbulk
+
+import (
+	"context"
+)
+
+// Service runs operations on many dashboards as jobs. Jobs run in the background with the
+// permissions of the user who created them, each dashboard is checked separately.
+type Service interface {
+	// CreateJob selects the dashboards of the job and starts it.
+	CreateJob(ctx context.Context, cmd *CreateJobCommand) (*Job, error)
+	GetJob(ctx context.Context, query *GetJobQuery) (*Job, error)
+	SearchJobs(ctx context.Context, query *SearchJobsQuery) ([]*Job, error)
+	GetJobItems(ctx context.Context, query *GetJobItemsQuery) ([]*JobItem, error)
+	// CancelJob stops a job, the dashboards already processed keep their changes.
+	CancelJob(ctx context.Context, cmd *CancelJobCommand) error
+}
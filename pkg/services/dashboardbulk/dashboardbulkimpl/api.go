@@ -0,0 +1,234 @@
+package dashboardbulkimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboardbulk"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	// the permissions on each dashboard are checked when the job runs
+	canChange := ac.EvalAny(
+		ac.EvalPermission(dashboards.ActionDashboardsWrite),
+		ac.EvalPermission(dashboards.ActionDashboardsDelete),
+	)
+
+	routeRegister.Group("/api/dashboards/bulk", func(subrouter routing.RouteRegister) {
+		subrouter.Post("/", authorize(canChange), routing.Wrap(s.handleCreateJob))
+		subrouter.Get("/", authorize(canChange), routing.Wrap(s.handleSearchJobs))
+		subrouter.Get("/:id", authorize(canChange), routing.Wrap(s.handleGetJob))
+		subrouter.Get("/:id/items", authorize(canChange), routing.Wrap(s.handleGetJobItems))
+		subrouter.Post("/:id/cancel", authorize(canChange), routing.Wrap(s.handleCancelJob))
+	})
+}
+
+// swagger:route POST /dashboards/bulk dashboard_bulk createDashboardBulkJob
+//
+// Start a bulk operation on dashboards.
+//
+// Moves, tags, deletes or replaces a data source in the selected dashboards. The dashboards are given by UID or
+// selected by a search, the operation runs in the background and each dashboard is changed only if the user has
+// the permissions to change it. With `dryRun`, the job reports what would change without changing the dashboards.
+//
+// Responses:
+// 202: dashboardBulkJobResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleCreateJob(c *contextmodel.ReqContext) response.Response {
+	body := CreateJobBody{}
+	if err := web.Bind(c.Req, &body); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	job, err := s.CreateJob(c.Req.Context(), &dashboardbulk.CreateJobCommand{
+		Selection: dashboardbulk.Selection{DashboardUIDs: body.DashboardUIDs, Query: body.Query},
+		Operation: body.Operation,
+		DryRun:    body.DryRun,
+		User:      c.SignedInUser,
+		Request:   c.Req,
+	})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	return response.JSON(http.StatusAccepted, job)
+}
+
+// swagger:route GET /dashboards/bulk dashboard_bulk searchDashboardBulkJobs
+//
+// Get the bulk jobs created by the user.
+//
+// Returns the jobs of the last seven days, the most recent first.
+//
+// Responses:
+// 200: searchDashboardBulkJobsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSearchJobs(c *contextmodel.ReqContext) response.Response {
+	jobs, err := s.SearchJobs(c.Req.Context(), &dashboardbulk.SearchJobsQuery{
+		OrgID: c.SignedInUser.OrgID,
+		User:  c.SignedInUser,
+		Limit: c.QueryInt("limit"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the bulk jobs", err)
+	}
+	return response.JSON(http.StatusOK, jobs)
+}
+
+// swagger:route GET /dashboards/bulk/{id} dashboard_bulk getDashboardBulkJob
+//
+// Get a bulk job.
+//
+// Returns the status and the progress of the job.
+//
+// Responses:
+// 200: dashboardBulkJobResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleGetJob(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	job, err := s.GetJob(c.Req.Context(), &dashboardbulk.GetJobQuery{ID: id, OrgID: c.SignedInUser.OrgID, User: c.SignedInUser})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the bulk job", err)
+	}
+	return response.JSON(http.StatusOK, job)
+}
+
+// swagger:route GET /dashboards/bulk/{id}/items dashboard_bulk getDashboardBulkJobItems
+//
+// Get the results of a bulk job.
+//
+// Returns the result of the operation on each dashboard processed so far, optionally only those with a status.
+//
+// Responses:
+// 200: getDashboardBulkJobItemsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleGetJobItems(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+	status := dashboardbulk.ItemStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		return response.Error(http.StatusBadRequest, "status must be success, skipped or failed", nil)
+	}
+
+	items, err := s.GetJobItems(c.Req.Context(), &dashboardbulk.GetJobItemsQuery{
+		JobID:  id,
+		OrgID:  c.SignedInUser.OrgID,
+		User:   c.SignedInUser,
+		Status: status,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the results of the bulk job", err)
+	}
+	return response.JSON(http.StatusOK, items)
+}
+
+// swagger:route POST /dashboards/bulk/{id}/cancel dashboard_bulk cancelDashboardBulkJob
+//
+// Cancel a bulk job.
+//
+// Stops the job after the dashboard being processed, the dashboards already changed keep their changes.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleCancelJob(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	err = s.CancelJob(c.Req.Context(), &dashboardbulk.CancelJobCommand{ID: id, OrgID: c.SignedInUser.OrgID, User: c.SignedInUser})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to cancel the bulk job", err)
+	}
+	return response.Success("Bulk job canceled")
+}
+
+// CreateJobBody is the operation of a job and the dashboards it applies to, given either by UID or by a query.
+type CreateJobBody struct {
+	DashboardUIDs []string                   `json:"dashboardUids"`
+	Query         *dashboardbulk.SearchQuery `json:"query"`
+	Operation     dashboardbulk.Operation    `json:"operation"`
+	DryRun        bool                       `json:"dryRun"`
+}
+
+// swagger:parameters createDashboardBulkJob
+type CreateDashboardBulkJobParams struct {
+	// in:body
+	// required:true
+	Body CreateJobBody `json:"body"`
+}
+
+// swagger:parameters searchDashboardBulkJobs
+type SearchDashboardBulkJobsParams struct {
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters getDashboardBulkJob cancelDashboardBulkJob
+type DashboardBulkJobParams struct {
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+}
+
+// swagger:parameters getDashboardBulkJobItems
+type GetDashboardBulkJobItemsParams struct {
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+	// in:query
+	// required:false
+	// enum: success,skipped,failed
+	Status string `json:"status"`
+}
+
+// swagger:response dashboardBulkJobResponse
+type DashboardBulkJobResponse struct {
+	// in: body
+	Body *dashboardbulk.Job `json:"body"`
+}
+
+// swagger:response searchDashboardBulkJobsResponse
+type SearchDashboardBulkJobsResponse struct {
+	// in: body
+	Body []*dashboardbulk.Job `json:"body"`
+}
+
+// swagger:response getDashboardBulkJobItemsResponse
+type GetDashboardBulkJobItemsResponse struct {
+	// in: body
+	Body []*dashboardbulk.JobItem `json:"body"`
+}
@@ -0,0 +1,120 @@
+package dashboardbulkimpl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// datasourceReplacement replaces the references to a data source, which might no longer exist,
+// with references to another data source.
+type datasourceReplacement struct {
+	// from are the UID and the name of the data source replaced
+	from map[string]bool
+	to   *datasources.DataSource
+}
+
+func newDatasourceReplacement(fromUID string, from, to *datasources.DataSource) *datasourceReplacement {
+	r := &datasourceReplacement{from: map[string]bool{fromUID: true}, to: to}
+	if from != nil {
+		r.from[from.UID] = true
+		r.from[from.Name] = true
+	}
+	return r
+}
+
+// replace replaces the data source of a panel, a query, a variable or an annotation, if it is the one replaced.
+func (r *datasourceReplacement) replace(j *simplejson.Json) bool {
+	ds, ok := j.CheckGet("datasource")
+	if !ok {
+		return false
+	}
+
+	uid, err := ds.String()
+	if err != nil {
+		uid = ds.Get("uid").MustString()
+	}
+	if uid == "" || !r.from[uid] {
+		return false
+	}
+
+	j.Set("datasource", map[string]interface{}{"type": r.to.Type, "uid": r.to.UID})
+	return true
+}
+
+// apply replaces the data source in the panels, the queries, the variables and the annotations of a dashboard,
+// and returns the number of references replaced.
+func (r *datasourceReplacement) apply(dashboard *simplejson.Json) int {
+	replaced := 0
+	count := func(ok bool) {
+		if ok {
+			replaced++
+		}
+	}
+
+	for _, panel := range dashboards.Panels(dashboard) {
+		count(r.replace(panel))
+		for _, query := range dashboardlint.Targets(panel) {
+			count(r.replace(query))
+		}
+	}
+	for _, path := range [][]string{{"templating", "list"}, {"annotations", "list"}} {
+		for _, v := range dashboard.GetPath(path...).MustArray() {
+			count(r.replace(simplejson.NewFromAny(v)))
+		}
+	}
+	return replaced
+}
+
+// changeTags adds and removes tags, it returns a description of the changes or an empty string if the tags did not change.
+func changeTags(dashboard *simplejson.Json, add, remove []string) string {
+	tags := dashboard.Get("tags").MustStringArray()
+	has := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		has[tag] = true
+	}
+
+	var added, removed []string
+	for _, tag := range remove {
+		if has[tag] {
+			delete(has, tag)
+			removed = append(removed, tag)
+		}
+	}
+	for _, tag := range add {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !has[tag] {
+			has[tag] = true
+			added = append(added, tag)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return ""
+	}
+
+	// the tags are read with MustStringArray, which only accepts the arrays decoded from JSON
+	result := make([]interface{}, 0, len(has))
+	for _, tag := range tags {
+		if has[tag] {
+			result = append(result, tag)
+			delete(has, tag)
+		}
+	}
+	for _, tag := range added {
+		result = append(result, tag)
+	}
+	dashboard.Set("tags", result)
+
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("added %s", strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("removed %s", strings.Join(removed, ", ")))
+	}
+	return "Tags " + strings.Join(changes, " and ")
+}
@@ -0,0 +1,124 @@
+package dashboardbulkimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+func TestDatasourceReplacement(t *testing.T) {
+	from := &datasources.DataSource{UID: "old", Name: "Old Prometheus", Type: "prometheus"}
+	to := &datasources.DataSource{UID: "new", Name: "New Prometheus", Type: "prometheus"}
+	ref := map[string]interface{}{"type": "prometheus", "uid": "new"}
+
+	dashboard := simplejson.NewFromAny(map[string]interface{}{
+		"panels": []interface{}{
+			map[string]interface{}{
+				"id":         1,
+				"datasource": map[string]interface{}{"type": "prometheus", "uid": "old"},
+				"targets": []interface{}{
+					map[string]interface{}{"refId": "A", "datasource": "Old Prometheus"},
+					map[string]interface{}{"refId": "B", "datasource": map[string]interface{}{"uid": "loki"}},
+				},
+			},
+			map[string]interface{}{
+				"id":   2,
+				"type": "row",
+				"panels": []interface{}{
+					map[string]interface{}{"id": 3, "datasource": "old"},
+				},
+			},
+			map[string]interface{}{"id": 4, "datasource": "${ds}"},
+		},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{"name": "job", "type": "query", "datasource": map[string]interface{}{"uid": "old"}},
+			},
+		},
+		"annotations": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{"name": "Deployments", "datasource": map[string]interface{}{"uid": "grafana"}},
+			},
+		},
+	})
+
+	t.Run("replaces the references by UID and by name", func(t *testing.T) {
+		replaced := newDatasourceReplacement("old", from, to).apply(dashboard)
+		assert.Equal(t, 4, replaced)
+
+		panels := dashboard.Get("panels")
+		assert.Equal(t, ref, panels.GetIndex(0).Get("datasource").Interface())
+		assert.Equal(t, ref, panels.GetIndex(0).Get("targets").GetIndex(0).Get("datasource").Interface())
+		assert.Equal(t, "loki", panels.GetIndex(0).Get("targets").GetIndex(1).GetPath("datasource", "uid").MustString())
+		assert.Equal(t, ref, panels.GetIndex(1).Get("panels").GetIndex(0).Get("datasource").Interface())
+		assert.Equal(t, "${ds}", panels.GetIndex(2).Get("datasource").MustString())
+		assert.Equal(t, ref, dashboard.GetPath("templating", "list").GetIndex(0).Get("datasource").Interface())
+		assert.Equal(t, "grafana", dashboard.GetPath("annotations", "list").GetIndex(0).GetPath("datasource", "uid").MustString())
+	})
+
+	t.Run("replaces the references to a deleted data source by UID", func(t *testing.T) {
+		dashboard := simplejson.NewFromAny(map[string]interface{}{
+			"panels": []interface{}{
+				map[string]interface{}{"id": 1, "datasource": map[string]interface{}{"uid": "deleted"}},
+				map[string]interface{}{"id": 2, "datasource": "Old Prometheus"},
+			},
+		})
+		assert.Equal(t, 1, newDatasourceReplacement("deleted", nil, to).apply(dashboard))
+	})
+}
+
+func TestChangeTags(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		tags        []string
+		add         []string
+		remove      []string
+		expected    []string
+		description string
+	}{
+		{
+			desc:        "adds tags",
+			tags:        []string{"prod"},
+			add:         []string{"team-a", "prod", " "},
+			expected:    []string{"prod", "team-a"},
+			description: "Tags added team-a",
+		},
+		{
+			desc:        "removes tags",
+			tags:        []string{"prod", "legacy", "team-a"},
+			remove:      []string{"legacy", "unknown"},
+			expected:    []string{"prod", "team-a"},
+			description: "Tags removed legacy",
+		},
+		{
+			desc:        "adds and removes tags",
+			tags:        []string{"legacy"},
+			add:         []string{"current"},
+			remove:      []string{"legacy"},
+			expected:    []string{"current"},
+			description: "Tags added current and removed legacy",
+		},
+		{
+			desc:     "unchanged",
+			tags:     []string{"prod"},
+			add:      []string{"prod"},
+			remove:   []string{"dev"},
+			expected: []string{"prod"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tags := make([]interface{}, 0, len(tc.tags))
+			for _, tag := range tc.tags {
+				tags = append(tags, tag)
+			}
+			dashboard := simplejson.NewFromAny(map[string]interface{}{"tags": tags})
+			assert.Equal(t, tc.description, changeTags(dashboard, tc.add, tc.remove))
+			assert.Equal(t, tc.expected, dashboard.Get("tags").MustStringArray())
+		})
+	}
+}
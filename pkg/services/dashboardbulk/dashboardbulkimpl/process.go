@@ -0,0 +1,260 @@
+package dashboardbulkimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/services/dashboardbulk"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+)
+
+// processDashboard applies the operation of a job to a dashboard. Each dashboard is checked against
+// the permissions of the user who created the job, as if the user changed it from the UI.
+func (s *Service) processDashboard(ctx context.Context, run *jobRun, uid string) *dashboardbulk.JobItem {
+	item := &dashboardbulk.JobItem{DashboardUID: uid}
+	fail := func(err error) *dashboardbulk.JobItem {
+		item.Status = dashboardbulk.ItemStatusFailed
+		item.Message = errorMessage(err)
+		return item
+	}
+
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: uid, OrgID: run.job.OrgID})
+	if err != nil {
+		return fail(err)
+	}
+	item.Title = dash.Title
+	if dash.IsFolder {
+		return fail(dashboards.ErrDashboardNotFound)
+	}
+
+	g, err := guardian.NewByDashboard(ctx, dash, run.job.OrgID, run.user)
+	if err != nil {
+		return fail(err)
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		// do not reveal the dashboards the user cannot see
+		item.Title = ""
+		return fail(dashboards.ErrDashboardNotFound)
+	}
+
+	if run.op.Type == dashboardbulk.OperationDelete {
+		return s.deleteDashboard(ctx, run, g, dash, item, fail)
+	}
+
+	changed, description := s.applyOperation(run.op, dash)
+	if !changed {
+		item.Status = dashboardbulk.ItemStatusSkipped
+		item.Message = description
+		return item
+	}
+
+	updated := dashboards.NewDashboardFromJson(dash.Data)
+	updated.SetID(dash.ID)
+	updated.SetUID(dash.UID)
+	updated.SetVersion(dash.Version)
+	updated.OrgID = dash.OrgID
+	updated.FolderID = dash.FolderID
+	updated.UpdatedBy = run.user.UserID
+	if run.op.Type == dashboardbulk.OperationMove {
+		updated.FolderID = run.op.folder.ID
+	}
+	message := "Bulk change: " + run.op.Describe()
+
+	// same as saving the dashboard from the API, the changes cannot add problems that block saving it
+	if err := s.lintDashboard(ctx, run, updated); err != nil {
+		return fail(err)
+	}
+
+	if s.dashboardDraftService != nil {
+		requiresApproval, err := s.dashboardDraftService.RequiresApproval(ctx, &dashboarddraft.RequiresApprovalQuery{
+			OrgID:        dash.OrgID,
+			DashboardID:  dash.ID,
+			DashboardUID: dash.UID,
+		})
+		if err != nil {
+			return fail(err)
+		}
+		if requiresApproval {
+			return s.draftDashboard(ctx, run, g, updated, message, description, item, fail)
+		}
+	}
+
+	dto := &dashboards.SaveDashboardDTO{
+		Dashboard: updated,
+		Message:   message,
+		OrgID:     dash.OrgID,
+		User:      run.user,
+	}
+	if run.job.DryRun {
+		// builds the save command without saving it, which checks the permissions and whether the dashboard is provisioned
+		if _, err := s.dashboardService.BuildSaveDashboardCommand(ctx, dto, false, true); err != nil {
+			return fail(err)
+		}
+		item.Status = dashboardbulk.ItemStatusSuccess
+		item.Message = "Would be changed: " + description
+		return item
+	}
+
+	saved, err := s.dashboardService.SaveDashboard(ctx, dto, false)
+	if err != nil {
+		return fail(err)
+	}
+
+	event := s.newAuditEvent(run, audit.ActionUpdate, saved.UID)
+	event.Before = audit.Summary{"version": dash.Version}
+	event.After = audit.Summary{"title": saved.Title, "version": saved.Version, "folderUid": run.op.FolderUID}
+	if run.op.Type != dashboardbulk.OperationMove {
+		delete(event.After, "folderUid")
+	}
+	s.auditService.Log(ctx, event)
+
+	item.Status = dashboardbulk.ItemStatusSuccess
+	item.Message = description
+	item.Version = saved.Version
+	return item
+}
+
+// applyOperation changes the data of the dashboard, it returns whether the dashboard changed and a description
+// of the change or of the reason it was skipped.
+func (s *Service) applyOperation(op *preparedOperation, dash *dashboards.Dashboard) (bool, string) {
+	switch op.Type {
+	case dashboardbulk.OperationMove:
+		if dash.FolderID == op.folder.ID {
+			return false, "The dashboard is already in the folder"
+		}
+		return true, fmt.Sprintf("Moved to %s", op.folder.Title)
+	case dashboardbulk.OperationTags:
+		description := changeTags(dash.Data, op.AddTags, op.RemoveTags)
+		if description == "" {
+			return false, "The tags did not change"
+		}
+		return true, description
+	case dashboardbulk.OperationReplaceDatasource:
+		replaced := op.datasources.apply(dash.Data)
+		if replaced == 0 {
+			return false, "The dashboard does not use the data source"
+		}
+		return true, fmt.Sprintf("Replaced %d data source references", replaced)
+	}
+	return false, ""
+}
+
+// lintDashboard fails if the changed dashboard has lint problems that block saving it in its folder.
+func (s *Service) lintDashboard(ctx context.Context, run *jobRun, dash *dashboards.Dashboard) error {
+	if s.dashboardLintService == nil {
+		return nil
+	}
+	query := &dashboardlint.LintQuery{
+		OrgID:     dash.OrgID,
+		FolderID:  dash.FolderID,
+		Dashboard: dash.Data,
+		User:      run.user,
+	}
+	if run.op.Type == dashboardbulk.OperationMove {
+		query.FolderUID = run.op.FolderUID
+	}
+	result, err := s.dashboardLintService.Lint(ctx, query)
+	if err != nil {
+		// linting must not prevent saving dashboards
+		s.log.Warn("Failed to lint dashboard", "uid", dash.UID, "error", err)
+		return nil
+	}
+	if result.Blocked {
+		rules := make([]string, 0, len(result.Problems))
+		for _, problem := range result.Problems {
+			if problem.Level == dashboardlint.LevelBlock {
+				rules = append(rules, problem.Rule)
+			}
+		}
+		return dashboardbulk.ErrLintFailed.Errorf("dashboard %s is blocked by the lint rules %v", dash.UID, rules)
+	}
+	return nil
+}
+
+// draftDashboard saves the changes of a dashboard that requires approval as a draft.
+func (s *Service) draftDashboard(ctx context.Context, run *jobRun, g guardian.DashboardGuardian, dash *dashboards.Dashboard,
+	message, description string, item *dashboardbulk.JobItem, fail func(error) *dashboardbulk.JobItem) *dashboardbulk.JobItem {
+	if run.job.DryRun {
+		if canSave, err := g.CanSave(); err != nil || !canSave {
+			return fail(dashboards.ErrDashboardUpdateAccessDenied)
+		}
+		item.Status = dashboardbulk.ItemStatusSuccess
+		item.Message = "Would be saved as a draft: " + description
+		return item
+	}
+
+	draft, err := s.dashboardDraftService.CreateDraft(ctx, &dashboarddraft.CreateDraftCommand{
+		Dashboard: dash,
+		Message:   message,
+		User:      run.user,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	event := s.newAuditEvent(run, audit.ActionCreate, draft.DashboardUID)
+	event.After = audit.Summary{"draftId": draft.ID, "parentVersion": draft.ParentVersion}
+	s.auditService.Log(ctx, event)
+
+	item.Status = dashboardbulk.ItemStatusSuccess
+	item.Message = "Saved as a draft: " + description
+	item.DraftID = draft.ID
+	return item
+}
+
+func (s *Service) deleteDashboard(ctx context.Context, run *jobRun, g guardian.DashboardGuardian, dash *dashboards.Dashboard,
+	item *dashboardbulk.JobItem, fail func(error) *dashboardbulk.JobItem) *dashboardbulk.JobItem {
+	if canDelete, err := g.CanDelete(); err != nil || !canDelete {
+		return fail(dashboards.ErrDashboardUpdateAccessDenied)
+	}
+
+	provisioning, err := s.dashboardProvisioningService.GetProvisionedDashboardDataByDashboardID(ctx, dash.ID)
+	if err != nil {
+		return fail(err)
+	}
+	if provisioning != nil {
+		return fail(dashboards.ErrDashboardCannotDeleteProvisionedDashboard)
+	}
+
+	if run.job.DryRun {
+		item.Status = dashboardbulk.ItemStatusSuccess
+		item.Message = "Would be deleted"
+		return item
+	}
+
+	// same as deleting the dashboard from the API
+	if err := s.libraryElementService.DisconnectElementsFromDashboard(ctx, dash.ID); err != nil {
+		s.log.Error("Failed to disconnect library elements", "dashboard", dash.UID, "error", err)
+	}
+	if err := s.publicDashboardService.DeleteByDashboard(ctx, dash); err != nil {
+		s.log.Error("Failed to delete public dashboard", "dashboard", dash.UID, "error", err)
+	}
+	if err := s.dashboardService.DeleteDashboard(ctx, dash.ID, dash.OrgID); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			item.Status = dashboardbulk.ItemStatusSkipped
+			item.Message = "The dashboard was already deleted"
+			return item
+		}
+		return fail(err)
+	}
+
+	event := s.newAuditEvent(run, audit.ActionDelete, dash.UID)
+	event.Before = audit.Summary{"title": dash.Title, "version": dash.Version}
+	s.auditService.Log(ctx, event)
+
+	item.Status = dashboardbulk.ItemStatusSuccess
+	item.Message = "Deleted"
+	return item
+}
+
+// newAuditEvent returns an event for a change made by the job, with the request that created the job.
+func (s *Service) newAuditEvent(run *jobRun, action audit.Action, uid string) audit.Event {
+	event := audit.NewEvent(run.user, nil, action, audit.Resource(run.job.OrgID, audit.KindDashboard, uid))
+	event.Request = run.request
+	return event
+}
@@ -0,0 +1,408 @@
+package dashboardbulkimpl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/services/dashboardbulk"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	// jobTimeout is how long a job can run, the dashboards not processed by then are not changed.
+	jobTimeout = time.Hour
+	// staleJobAge is how long an unfinished job can go without progress before it is considered interrupted,
+	// e.g. because the instance running it stopped.
+	staleJobAge = 15 * time.Minute
+	// jobRetention is how long finished jobs and their results are kept.
+	jobRetention    = 7 * 24 * time.Hour
+	cleanupInterval = time.Hour
+
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
+var _ dashboardbulk.Service = (*Service)(nil)
+
+type Service struct {
+	store                        store
+	dashboardService             dashboards.DashboardService
+	dashboardProvisioningService dashboards.DashboardProvisioningService
+	dashboardDraftService        dashboarddraft.Service
+	dashboardLintService         dashboardlint.Service
+	folderService                folder.Service
+	datasourceService            datasources.DataSourceService
+	libraryElementService        libraryelements.Service
+	publicDashboardService       publicdashboards.Service
+	auditService                 audit.Service
+	accessControl                ac.AccessControl
+	log                          log.Logger
+
+	// running are the jobs started by this instance, they stop when stopJobs cancels jobsCtx
+	running  sync.WaitGroup
+	jobsCtx  context.Context
+	stopJobs context.CancelFunc
+}
+
+func ProvideService(
+	sql db.DB,
+	dashboardService dashboards.DashboardService,
+	dashboardProvisioningService dashboards.DashboardProvisioningService,
+	dashboardDraftService dashboarddraft.Service,
+	dashboardLintService dashboardlint.Service,
+	folderService folder.Service,
+	datasourceService datasources.DataSourceService,
+	libraryElementService libraryelements.Service,
+	publicDashboardService publicdashboards.Service,
+	auditService audit.Service,
+	accessControl ac.AccessControl,
+	routeRegister routing.RouteRegister,
+) *Service {
+	s := &Service{
+		store:                        &xormStore{db: sql},
+		dashboardService:             dashboardService,
+		dashboardProvisioningService: dashboardProvisioningService,
+		dashboardDraftService:        dashboardDraftService,
+		dashboardLintService:         dashboardLintService,
+		folderService:                folderService,
+		datasourceService:            datasourceService,
+		libraryElementService:        libraryElementService,
+		publicDashboardService:       publicDashboardService,
+		auditService:                 auditService,
+		accessControl:                accessControl,
+		log:                          log.New("dashboard.bulk"),
+	}
+	s.jobsCtx, s.stopJobs = context.WithCancel(context.Background())
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// Run fails the jobs interrupted by a restart and deletes the old jobs. When it stops, it stops the jobs
+// started by this instance and waits for them to be marked as failed.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.cleanup(ctx, time.Now())
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			s.stopJobs()
+			s.running.Wait()
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) cleanup(ctx context.Context, now time.Time) {
+	if failed, err := s.store.FailStale(ctx, now.Add(-staleJobAge), now); err != nil {
+		s.log.Error("Failed to fail the interrupted bulk jobs", "error", err)
+	} else if failed > 0 {
+		s.log.Warn("Failed interrupted bulk jobs", "count", failed)
+	}
+
+	if _, err := s.store.DeleteFinished(ctx, now.Add(-jobRetention)); err != nil {
+		s.log.Error("Failed to delete old bulk jobs", "error", err)
+	}
+}
+
+// CreateJob selects the dashboards and starts the job. The operation is validated first, so that
+// a job with an invalid operation does not fail on every dashboard.
+func (s *Service) CreateJob(ctx context.Context, cmd *dashboardbulk.CreateJobCommand) (*dashboardbulk.Job, error) {
+	if err := cmd.Operation.Validate(); err != nil {
+		return nil, err
+	}
+	op, err := s.prepare(ctx, cmd.Operation, cmd.User)
+	if err != nil {
+		return nil, err
+	}
+
+	uids, err := s.selectDashboards(ctx, cmd.Selection, cmd.User)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &dashboardbulk.Job{
+		OrgID:     cmd.User.OrgID,
+		Operation: cmd.Operation,
+		DryRun:    cmd.DryRun,
+		Status:    dashboardbulk.JobStatusPending,
+		Total:     len(uids),
+		CreatedBy: cmd.User.UserID,
+		Created:   now,
+		Updated:   now,
+	}
+	if err := s.store.Insert(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// the changes are audited with the request that created the job
+	event := audit.NewEvent(cmd.User, cmd.Request, "", audit.Resource(job.OrgID, audit.KindDashboard, ""))
+	run := &jobRun{job: job, op: op, user: cmd.User, request: event.Request}
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		ctx, cancel := context.WithTimeout(s.jobsCtx, jobTimeout)
+		defer func() {
+			if err := recover(); err != nil {
+				s.log.Error("Bulk job panic", "id", job.ID, "error", err)
+			}
+			cancel()
+		}()
+
+		s.runJob(ctx, run, uids)
+	}()
+
+	return job, nil
+}
+
+// preparedOperation is an operation with the folder and the data sources it uses.
+type preparedOperation struct {
+	dashboardbulk.Operation
+	folder      *folder.Folder
+	datasources *datasourceReplacement
+}
+
+func (s *Service) prepare(ctx context.Context, op dashboardbulk.Operation, usr *user.SignedInUser) (*preparedOperation, error) {
+	prepared := &preparedOperation{Operation: op}
+	switch op.Type {
+	case dashboardbulk.OperationMove:
+		if op.FolderUID == "" || op.FolderUID == ac.GeneralFolderUID {
+			prepared.FolderUID = ""
+			prepared.folder = &folder.Folder{UID: ac.GeneralFolderUID, Title: folder.GeneralFolder.Title}
+			break
+		}
+		f, err := s.folderService.Get(ctx, &folder.GetFolderQuery{UID: &op.FolderUID, OrgID: usr.OrgID, SignedInUser: usr})
+		if err != nil {
+			if errors.Is(err, dashboards.ErrFolderNotFound) {
+				return nil, dashboardbulk.ErrInvalidOperation.Errorf("folder %s not found", op.FolderUID)
+			}
+			return nil, err
+		}
+		prepared.folder = f
+
+	case dashboardbulk.OperationReplaceDatasource:
+		to, err := s.datasourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: op.ToDatasourceUID, OrgID: usr.OrgID})
+		if err != nil {
+			if errors.Is(err, datasources.ErrDataSourceNotFound) {
+				return nil, dashboardbulk.ErrInvalidOperation.Errorf("data source %s not found", op.ToDatasourceUID)
+			}
+			return nil, err
+		}
+		// the data source replaced might have been deleted already
+		from, err := s.datasourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: op.FromDatasourceUID, OrgID: usr.OrgID})
+		if err != nil && !errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return nil, err
+		}
+		prepared.datasources = newDatasourceReplacement(op.FromDatasourceUID, from, to)
+	}
+	return prepared, nil
+}
+
+// selectDashboards returns the UIDs of the dashboards of a job. The dashboards given by UID are returned
+// as they are, so that those that do not exist or cannot be changed are reported in the results of the job.
+func (s *Service) selectDashboards(ctx context.Context, selection dashboardbulk.Selection, usr *user.SignedInUser) ([]string, error) {
+	if (len(selection.DashboardUIDs) == 0) == (selection.Query == nil) {
+		return nil, dashboardbulk.ErrInvalidSelection.Errorf("either dashboardUids or query is required")
+	}
+
+	var uids []string
+	seen := make(map[string]bool)
+	add := func(uid string) {
+		if uid != "" && !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+
+	if selection.Query != nil {
+		q := selection.Query
+		if strings.TrimSpace(q.Query) == "" && len(q.Tags) == 0 && len(q.FolderUIDs) == 0 {
+			return nil, dashboardbulk.ErrInvalidSelection.Errorf("the query must have a query, tags or folders")
+		}
+		hits, err := s.dashboardService.SearchDashboards(ctx, &dashboards.FindPersistedDashboardsQuery{
+			Title:        q.Query,
+			Tags:         q.Tags,
+			FolderUIDs:   q.FolderUIDs,
+			OrgId:        usr.OrgID,
+			SignedInUser: usr,
+			Type:         string(model.DashHitDB),
+			Permission:   dashboards.PERMISSION_VIEW,
+			Limit:        dashboardbulk.MaxDashboards + 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range hits {
+			add(hit.UID)
+		}
+	} else {
+		for _, uid := range selection.DashboardUIDs {
+			add(strings.TrimSpace(uid))
+		}
+	}
+
+	if len(uids) == 0 {
+		return nil, dashboardbulk.ErrInvalidSelection.Errorf("no dashboards selected")
+	}
+	if len(uids) > dashboardbulk.MaxDashboards {
+		return nil, dashboardbulk.ErrTooManyDashboards.Errorf("a job can change up to %d dashboards", dashboardbulk.MaxDashboards)
+	}
+	return uids, nil
+}
+
+// jobRun is the state of a job run by this instance.
+type jobRun struct {
+	job     *dashboardbulk.Job
+	op      *preparedOperation
+	user    *user.SignedInUser
+	request audit.RequestMeta
+}
+
+func (s *Service) runJob(ctx context.Context, run *jobRun, uids []string) {
+	job := run.job
+	logger := s.log.New("id", job.ID, "operation", job.Operation.Type, "dryRun", job.DryRun)
+
+	if ctx.Err() != nil {
+		s.finishJob(logger, job, dashboardbulk.JobStatusFailed, stopReason(ctx))
+		return
+	}
+	job.Status = dashboardbulk.JobStatusRunning
+	job.Updated = time.Now()
+	if err := s.store.Update(ctx, job); err != nil {
+		logger.Info("Bulk job not started", "error", err)
+		return
+	}
+
+	for _, uid := range uids {
+		if ctx.Err() != nil {
+			s.finishJob(logger, job, dashboardbulk.JobStatusFailed, stopReason(ctx))
+			return
+		}
+
+		item := s.processDashboard(ctx, run, uid)
+		item.JobID = job.ID
+		item.Created = time.Now()
+		if err := s.store.InsertItem(ctx, item); err != nil {
+			logger.Error("Failed to save the result of a dashboard", "dashboard", uid, "error", err)
+		}
+
+		job.Processed++
+		switch item.Status {
+		case dashboardbulk.ItemStatusSuccess:
+			job.Succeeded++
+		case dashboardbulk.ItemStatusSkipped:
+			job.Skipped++
+		default:
+			job.Failed++
+		}
+		job.Updated = time.Now()
+		if err := s.store.Update(ctx, job); err != nil {
+			if ctx.Err() != nil {
+				s.finishJob(logger, job, dashboardbulk.JobStatusFailed, stopReason(ctx))
+				return
+			}
+			// the job was canceled
+			logger.Info("Bulk job stopped", "processed", job.Processed, "error", err)
+			return
+		}
+	}
+
+	s.finishJob(logger, job, dashboardbulk.JobStatusCompleted, "")
+}
+
+// stopReason returns why the context of a job is done, the job timed out or the service stopped.
+func stopReason(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "The job timed out"
+	}
+	return "The job was stopped because Grafana is shutting down"
+}
+
+func (s *Service) finishJob(logger log.Logger, job *dashboardbulk.Job, status dashboardbulk.JobStatus, reason string) {
+	now := time.Now()
+	job.Status = status
+	job.Error = reason
+	job.Updated = now
+	job.Finished = &now
+
+	// the job is finished even if it ran out of time or was stopped
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.store.Update(ctx, job); err != nil {
+		logger.Info("Bulk job not finished", "error", err)
+		return
+	}
+	logger.Info("Bulk job finished", "status", status, "succeeded", job.Succeeded, "skipped", job.Skipped, "failed", job.Failed)
+}
+
+func (s *Service) GetJob(ctx context.Context, query *dashboardbulk.GetJobQuery) (*dashboardbulk.Job, error) {
+	job, err := s.store.Get(ctx, query.ID, query.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	// jobs are only visible to the user who created them
+	if job.CreatedBy != query.User.UserID {
+		return nil, dashboardbulk.ErrJobNotFound.Errorf("job %d was created by another user", query.ID)
+	}
+	return job, nil
+}
+
+func (s *Service) SearchJobs(ctx context.Context, query *dashboardbulk.SearchJobsQuery) ([]*dashboardbulk.Job, error) {
+	if query.Limit <= 0 || query.Limit > maxSearchLimit {
+		query.Limit = defaultSearchLimit
+	}
+	return s.store.Search(ctx, query)
+}
+
+func (s *Service) GetJobItems(ctx context.Context, query *dashboardbulk.GetJobItemsQuery) ([]*dashboardbulk.JobItem, error) {
+	if _, err := s.GetJob(ctx, &dashboardbulk.GetJobQuery{ID: query.JobID, OrgID: query.OrgID, User: query.User}); err != nil {
+		return nil, err
+	}
+	return s.store.GetItems(ctx, query)
+}
+
+func (s *Service) CancelJob(ctx context.Context, cmd *dashboardbulk.CancelJobCommand) error {
+	if _, err := s.GetJob(ctx, &dashboardbulk.GetJobQuery{ID: cmd.ID, OrgID: cmd.OrgID, User: cmd.User}); err != nil {
+		return err
+	}
+	// the instance running the job stops when it fails to update its progress
+	return s.store.Cancel(ctx, cmd.ID, cmd.OrgID, time.Now())
+}
+
+// errorMessage returns the message of an error that can be shown to the user.
+func errorMessage(err error) string {
+	var e errutil.Error
+	if errors.As(err, &e) {
+		return e.Public().Message
+	}
+	var dashboardErr dashboards.DashboardErr
+	if errors.As(err, &dashboardErr) {
+		return dashboardErr.Error()
+	}
+	if errors.Is(err, dashboards.ErrFolderNotFound) || errors.Is(err, dashboards.ErrFolderAccessDenied) {
+		return err.Error()
+	}
+	return http.StatusText(http.StatusInternalServerError)
+}
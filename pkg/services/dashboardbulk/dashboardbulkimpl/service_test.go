@@ -0,0 +1,413 @@
+package dashboardbulkimpl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/services/audit/audittest"
+	"github.com/grafana/grafana/pkg/services/dashboardbulk"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeLibraryElementService struct {
+	libraryelements.Service
+	disconnected []int64
+}
+
+func (f *fakeLibraryElementService) DisconnectElementsFromDashboard(_ context.Context, dashboardID int64) error {
+	f.disconnected = append(f.disconnected, dashboardID)
+	return nil
+}
+
+type fakeDashboardLintService struct {
+	dashboardlint.Service
+	result  *dashboardlint.LintResult
+	queries []*dashboardlint.LintQuery
+}
+
+func (f *fakeDashboardLintService) Lint(_ context.Context, query *dashboardlint.LintQuery) (*dashboardlint.LintResult, error) {
+	f.queries = append(f.queries, query)
+	return f.result, nil
+}
+
+type testEnv struct {
+	service                *Service
+	dashboards             map[string]*dashboards.Dashboard
+	dashboardService       *dashboards.FakeDashboardService
+	provisioningService    *dashboards.FakeDashboardProvisioning
+	folderService          *foldertest.FakeService
+	lintService            *fakeDashboardLintService
+	libraryElementService  *fakeLibraryElementService
+	publicDashboardService *publicdashboards.FakePublicDashboardService
+	auditService           *audittest.FakeService
+	user                   *user.SignedInUser
+}
+
+func newTestDashboard(id int64, uid string, folderID int64, data map[string]interface{}) *dashboards.Dashboard {
+	data["id"] = id
+	data["uid"] = uid
+	data["version"] = 1
+	data["title"] = "Dashboard " + uid
+	return &dashboards.Dashboard{
+		ID:       id,
+		UID:      uid,
+		OrgID:    1,
+		FolderID: folderID,
+		Version:  1,
+		Title:    "Dashboard " + uid,
+		Data:     simplejson.NewFromAny(data),
+	}
+}
+
+func setupTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := &testEnv{
+		dashboards: map[string]*dashboards.Dashboard{
+			"a": newTestDashboard(1, "a", 0, map[string]interface{}{
+				"tags":   []interface{}{"prod"},
+				"panels": []interface{}{map[string]interface{}{"id": 1, "datasource": map[string]interface{}{"uid": "old"}}},
+			}),
+			"b": newTestDashboard(2, "b", 5, map[string]interface{}{
+				"tags": []interface{}{"prod", "team-a"},
+			}),
+		},
+		dashboardService:       dashboards.NewFakeDashboardService(t),
+		provisioningService:    dashboards.NewFakeDashboardProvisioning(t),
+		folderService:          foldertest.NewFakeService(),
+		lintService:            &fakeDashboardLintService{result: &dashboardlint.LintResult{}},
+		libraryElementService:  &fakeLibraryElementService{},
+		publicDashboardService: publicdashboards.NewFakePublicDashboardService(t),
+		auditService:           audittest.NewFakeService(),
+		user:                   &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor, Login: "editor"},
+	}
+	env.dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+			dash, ok := env.dashboards[query.UID]
+			if !ok {
+				return nil, dashboards.ErrDashboardNotFound
+			}
+			// the job changes the data of the dashboard it gets
+			copied := *dash
+			data, err := dash.Data.Encode()
+			require.NoError(t, err)
+			copied.Data, err = simplejson.NewJson(data)
+			require.NoError(t, err)
+			return &copied, nil
+		}).Maybe()
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true, CanSaveValue: true, CanEditValue: true})
+
+	env.service = &Service{
+		store:                        &xormStore{db: db.InitTestDB(t)},
+		dashboardService:             env.dashboardService,
+		dashboardProvisioningService: env.provisioningService,
+		dashboardLintService:         env.lintService,
+		folderService:                env.folderService,
+		datasourceService: &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+			{UID: "new", Name: "New Prometheus", Type: "prometheus", OrgID: 1},
+		}},
+		libraryElementService:  env.libraryElementService,
+		publicDashboardService: env.publicDashboardService,
+		auditService:           env.auditService,
+		log:                    log.NewNopLogger(),
+	}
+	env.service.jobsCtx, env.service.stopJobs = context.WithCancel(context.Background())
+	t.Cleanup(env.service.stopJobs)
+	return env
+}
+
+// run creates a job and waits for it to finish.
+func (env *testEnv) run(t *testing.T, cmd *dashboardbulk.CreateJobCommand) (*dashboardbulk.Job, []*dashboardbulk.JobItem) {
+	t.Helper()
+
+	cmd.User = env.user
+	created, err := env.service.CreateJob(context.Background(), cmd)
+	require.NoError(t, err)
+	env.service.running.Wait()
+
+	job, err := env.service.GetJob(context.Background(), &dashboardbulk.GetJobQuery{ID: created.ID, OrgID: 1, User: env.user})
+	require.NoError(t, err)
+	items, err := env.service.GetJobItems(context.Background(), &dashboardbulk.GetJobItemsQuery{JobID: created.ID, OrgID: 1, User: env.user})
+	require.NoError(t, err)
+	return job, items
+}
+
+func TestIntegrationDashboardBulkService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	t.Run("tags the dashboards given by UID", func(t *testing.T) {
+		env := setupTestEnv(t)
+		var saved []*dashboards.SaveDashboardDTO
+		env.dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, false).Return(
+			func(_ context.Context, dto *dashboards.SaveDashboardDTO, _ bool) (*dashboards.Dashboard, error) {
+				saved = append(saved, dto)
+				result := *dto.Dashboard
+				result.Version++
+				return &result, nil
+			})
+
+		job, items := env.run(t, &dashboardbulk.CreateJobCommand{
+			Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a", "b", "missing", "a"}},
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationTags, AddTags: []string{"team-a"}},
+		})
+
+		assert.Equal(t, dashboardbulk.JobStatusCompleted, job.Status)
+		assert.Equal(t, 3, job.Total)
+		assert.Equal(t, 3, job.Processed)
+		assert.Equal(t, 1, job.Succeeded)
+		assert.Equal(t, 1, job.Skipped)
+		assert.Equal(t, 1, job.Failed)
+		require.NotNil(t, job.Finished)
+
+		require.Len(t, items, 3)
+		assert.Equal(t, dashboardbulk.ItemStatusSuccess, items[0].Status)
+		assert.Equal(t, "Tags added team-a", items[0].Message)
+		assert.Equal(t, 2, items[0].Version)
+		assert.Equal(t, dashboardbulk.ItemStatusSkipped, items[1].Status)
+		assert.Equal(t, dashboardbulk.ItemStatusFailed, items[2].Status)
+		assert.Equal(t, dashboards.ErrDashboardNotFound.Error(), items[2].Message)
+
+		require.Len(t, saved, 1)
+		assert.Equal(t, "Bulk change: Change tags", saved[0].Message)
+		assert.Equal(t, []string{"prod", "team-a"}, saved[0].Dashboard.GetTags())
+		assert.Equal(t, int64(1), saved[0].Dashboard.ID)
+		assert.Equal(t, 1, saved[0].Dashboard.Version)
+
+		events := env.auditService.Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, audit.ActionUpdate, events[0].Action)
+		assert.Equal(t, audit.Resource(1, audit.KindDashboard, "a"), events[0].Resource)
+	})
+
+	t.Run("dry run of a data source replacement selected by a query", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.dashboardService.On("SearchDashboards", mock.Anything, mock.Anything).Return(
+			func(_ context.Context, query *dashboards.FindPersistedDashboardsQuery) (model.HitList, error) {
+				assert.Equal(t, []string{"prod"}, query.Tags)
+				assert.Equal(t, dashboardbulk.MaxDashboards+1, int(query.Limit))
+				// a dashboard with several tags is returned once per tag
+				return model.HitList{{UID: "a"}, {UID: "a"}, {UID: "b"}}, nil
+			})
+		env.dashboardService.On("BuildSaveDashboardCommand", mock.Anything, mock.Anything, false, true).Return(&dashboards.SaveDashboardCommand{}, nil)
+
+		job, items := env.run(t, &dashboardbulk.CreateJobCommand{
+			Selection: dashboardbulk.Selection{Query: &dashboardbulk.SearchQuery{Tags: []string{"prod"}}},
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationReplaceDatasource, FromDatasourceUID: "old", ToDatasourceUID: "new"},
+			DryRun:    true,
+		})
+
+		assert.Equal(t, dashboardbulk.JobStatusCompleted, job.Status)
+		assert.Equal(t, 2, job.Total)
+		require.Len(t, items, 2)
+		assert.Equal(t, dashboardbulk.ItemStatusSuccess, items[0].Status)
+		assert.Equal(t, "Would be changed: Replaced 1 data source references", items[0].Message)
+		assert.Equal(t, dashboardbulk.ItemStatusSkipped, items[1].Status)
+		env.dashboardService.AssertNotCalled(t, "SaveDashboard", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, env.auditService.Events())
+	})
+
+	t.Run("moves the dashboards to a folder", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.folderService.ExpectedFolder = &folder.Folder{ID: 5, UID: "team-a", Title: "Team A"}
+		env.dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, false).Return(
+			func(_ context.Context, dto *dashboards.SaveDashboardDTO, _ bool) (*dashboards.Dashboard, error) {
+				assert.Equal(t, int64(5), dto.Dashboard.FolderID)
+				return dto.Dashboard, nil
+			})
+
+		_, items := env.run(t, &dashboardbulk.CreateJobCommand{
+			Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a", "b"}},
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationMove, FolderUID: "team-a"},
+		})
+
+		require.Len(t, items, 2)
+		assert.Equal(t, dashboardbulk.ItemStatusSuccess, items[0].Status)
+		assert.Equal(t, "Moved to Team A", items[0].Message)
+		assert.Equal(t, dashboardbulk.ItemStatusSkipped, items[1].Status)
+
+		// the dashboard is linted with the levels of the folder it is moved to
+		require.Len(t, env.lintService.queries, 1)
+		assert.Equal(t, "team-a", env.lintService.queries[0].FolderUID)
+	})
+
+	t.Run("dashboards with lint problems that block saving them fail", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.lintService.result = &dashboardlint.LintResult{
+			Problems: []dashboardlint.Problem{{Rule: "panel-title", Level: dashboardlint.LevelBlock}},
+			Blocked:  true,
+		}
+
+		job, items := env.run(t, &dashboardbulk.CreateJobCommand{
+			Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a"}},
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationTags, AddTags: []string{"team-a"}},
+		})
+
+		assert.Equal(t, 1, job.Failed)
+		require.Len(t, items, 1)
+		assert.Equal(t, dashboardbulk.ItemStatusFailed, items[0].Status)
+		assert.Equal(t, "The dashboard has lint problems that block saving it", items[0].Message)
+		env.dashboardService.AssertNotCalled(t, "SaveDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("jobs fail when the service stops", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.service.stopJobs()
+
+		job, items := env.run(t, &dashboardbulk.CreateJobCommand{
+			Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a"}},
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationTags, AddTags: []string{"team-a"}},
+		})
+
+		assert.Equal(t, dashboardbulk.JobStatusFailed, job.Status)
+		assert.Equal(t, "The job was stopped because Grafana is shutting down", job.Error)
+		assert.Empty(t, items)
+	})
+
+	t.Run("deletes the dashboards that are not provisioned", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.provisioningService.On("GetProvisionedDashboardDataByDashboardID", mock.Anything, int64(1)).Return(nil, nil)
+		env.provisioningService.On("GetProvisionedDashboardDataByDashboardID", mock.Anything, int64(2)).Return(&dashboards.DashboardProvisioning{Name: "default"}, nil)
+		env.publicDashboardService.On("DeleteByDashboard", mock.Anything, mock.Anything).Return(nil)
+		env.dashboardService.On("DeleteDashboard", mock.Anything, int64(1), int64(1)).Return(nil)
+
+		job, items := env.run(t, &dashboardbulk.CreateJobCommand{
+			Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a", "b"}},
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationDelete},
+		})
+
+		assert.Equal(t, 1, job.Succeeded)
+		assert.Equal(t, 1, job.Failed)
+		require.Len(t, items, 2)
+		assert.Equal(t, "Deleted", items[0].Message)
+		assert.Equal(t, dashboards.ErrDashboardCannotDeleteProvisionedDashboard.Error(), items[1].Message)
+		assert.Equal(t, []int64{1}, env.libraryElementService.disconnected)
+
+		events := env.auditService.Events()
+		require.Len(t, events, 1)
+		assert.Equal(t, audit.ActionDelete, events[0].Action)
+	})
+
+	t.Run("dashboards the user cannot view fail without their title", func(t *testing.T) {
+		env := setupTestEnv(t)
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: false})
+
+		job, items := env.run(t, &dashboardbulk.CreateJobCommand{
+			Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a"}},
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationDelete},
+		})
+
+		assert.Equal(t, 1, job.Failed)
+		require.Len(t, items, 1)
+		assert.Empty(t, items[0].Title)
+		assert.Equal(t, dashboards.ErrDashboardNotFound.Error(), items[0].Message)
+	})
+
+	t.Run("invalid jobs are not created", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.folderService.ExpectedError = dashboards.ErrFolderNotFound
+
+		testCases := []struct {
+			desc     string
+			cmd      *dashboardbulk.CreateJobCommand
+			expected error
+		}{
+			{
+				desc:     "unknown operation",
+				cmd:      &dashboardbulk.CreateJobCommand{Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a"}}, Operation: dashboardbulk.Operation{Type: "rename"}},
+				expected: dashboardbulk.ErrInvalidOperation,
+			},
+			{
+				desc:     "unknown folder",
+				cmd:      &dashboardbulk.CreateJobCommand{Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a"}}, Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationMove, FolderUID: "unknown"}},
+				expected: dashboardbulk.ErrInvalidOperation,
+			},
+			{
+				desc:     "unknown data source",
+				cmd:      &dashboardbulk.CreateJobCommand{Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a"}}, Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationReplaceDatasource, FromDatasourceUID: "old", ToDatasourceUID: "unknown"}},
+				expected: dashboardbulk.ErrInvalidOperation,
+			},
+			{
+				desc:     "no selection",
+				cmd:      &dashboardbulk.CreateJobCommand{Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationDelete}},
+				expected: dashboardbulk.ErrInvalidSelection,
+			},
+			{
+				desc: "UIDs and query",
+				cmd: &dashboardbulk.CreateJobCommand{
+					Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a"}, Query: &dashboardbulk.SearchQuery{Query: "a"}},
+					Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationDelete},
+				},
+				expected: dashboardbulk.ErrInvalidSelection,
+			},
+			{
+				desc:     "empty query",
+				cmd:      &dashboardbulk.CreateJobCommand{Selection: dashboardbulk.Selection{Query: &dashboardbulk.SearchQuery{}}, Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationDelete}},
+				expected: dashboardbulk.ErrInvalidSelection,
+			},
+			{
+				desc:     "too many dashboards",
+				cmd:      &dashboardbulk.CreateJobCommand{Selection: dashboardbulk.Selection{DashboardUIDs: make([]string, 0)}, Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationDelete}},
+				expected: dashboardbulk.ErrInvalidSelection,
+			},
+		}
+		for i := 0; i <= dashboardbulk.MaxDashboards; i++ {
+			testCases[len(testCases)-1].cmd.Selection.DashboardUIDs = append(testCases[len(testCases)-1].cmd.Selection.DashboardUIDs, fmt.Sprintf("uid-%d", i))
+		}
+		testCases[len(testCases)-1].expected = dashboardbulk.ErrTooManyDashboards
+
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				tc.cmd.User = env.user
+				_, err := env.service.CreateJob(context.Background(), tc.cmd)
+				require.ErrorIs(t, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("jobs are only visible to the user who created them", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.provisioningService.On("GetProvisionedDashboardDataByDashboardID", mock.Anything, mock.Anything).Return(nil, nil)
+
+		job, _ := env.run(t, &dashboardbulk.CreateJobCommand{
+			Selection: dashboardbulk.Selection{DashboardUIDs: []string{"a"}},
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationDelete},
+			DryRun:    true,
+		})
+
+		other := &user.SignedInUser{UserID: 2, OrgID: 1}
+		_, err := env.service.GetJob(context.Background(), &dashboardbulk.GetJobQuery{ID: job.ID, OrgID: 1, User: other})
+		require.ErrorIs(t, err, dashboardbulk.ErrJobNotFound)
+		err = env.service.CancelJob(context.Background(), &dashboardbulk.CancelJobCommand{ID: job.ID, OrgID: 1, User: other})
+		require.ErrorIs(t, err, dashboardbulk.ErrJobNotFound)
+
+		jobs, err := env.service.SearchJobs(context.Background(), &dashboardbulk.SearchJobsQuery{OrgID: 1, User: env.user})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+
+		// the job is already finished
+		err = env.service.CancelJob(context.Background(), &dashboardbulk.CancelJobCommand{ID: job.ID, OrgID: 1, User: env.user})
+		require.ErrorIs(t, err, dashboardbulk.ErrJobFinished)
+	})
+}
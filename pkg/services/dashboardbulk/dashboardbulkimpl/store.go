@@ -0,0 +1,145 @@
+package dashboardbulkimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardbulk"
+)
+
+type store interface {
+	Insert(ctx context.Context, job *dashboardbulk.Job) error
+	Get(ctx context.Context, id, orgID int64) (*dashboardbulk.Job, error)
+	Search(ctx context.Context, query *dashboardbulk.SearchJobsQuery) ([]*dashboardbulk.Job, error)
+	// Update saves the status and the progress of a job that is still running, it fails
+	// with ErrJobFinished if the job was canceled.
+	Update(ctx context.Context, job *dashboardbulk.Job) error
+	InsertItem(ctx context.Context, item *dashboardbulk.JobItem) error
+	GetItems(ctx context.Context, query *dashboardbulk.GetJobItemsQuery) ([]*dashboardbulk.JobItem, error)
+	// Cancel cancels a job that is still running, it fails with ErrJobFinished otherwise.
+	Cancel(ctx context.Context, id, orgID int64, now time.Time) error
+	// FailStale fails the unfinished jobs that were not updated since a time, as the instance running them stopped.
+	FailStale(ctx context.Context, updatedBefore time.Time, now time.Time) (int64, error)
+	// DeleteFinished deletes the jobs that finished before a time, with their items.
+	DeleteFinished(ctx context.Context, finishedBefore time.Time) (int64, error)
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+var unfinished = []interface{}{dashboardbulk.JobStatusPending, dashboardbulk.JobStatusRunning}
+
+func (xs *xormStore) Insert(ctx context.Context, job *dashboardbulk.Job) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(job)
+		return err
+	})
+}
+
+func (xs *xormStore) Get(ctx context.Context, id, orgID int64) (*dashboardbulk.Job, error) {
+	job := &dashboardbulk.Job{}
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("id = ? AND org_id = ?", id, orgID).Get(job)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return dashboardbulk.ErrJobNotFound.Errorf("job %d not found", id)
+		}
+		return nil
+	})
+	return job, err
+}
+
+func (xs *xormStore) Search(ctx context.Context, query *dashboardbulk.SearchJobsQuery) ([]*dashboardbulk.Job, error) {
+	jobs := make([]*dashboardbulk.Job, 0)
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ? AND created_by = ?", query.OrgID, query.User.UserID).Desc("id")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit)
+		}
+		return sess.Find(&jobs)
+	})
+	return jobs, err
+}
+
+func (xs *xormStore) Update(ctx context.Context, job *dashboardbulk.Job) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(job.ID).In("status", unfinished...).
+			Cols("status", "processed", "succeeded", "skipped", "failed", "error", "updated", "finished").
+			Update(job)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboardbulk.ErrJobFinished.Errorf("job %d is finished", job.ID)
+		}
+		return nil
+	})
+}
+
+func (xs *xormStore) InsertItem(ctx context.Context, item *dashboardbulk.JobItem) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(item)
+		return err
+	})
+}
+
+func (xs *xormStore) GetItems(ctx context.Context, query *dashboardbulk.GetJobItemsQuery) ([]*dashboardbulk.JobItem, error) {
+	items := make([]*dashboardbulk.JobItem, 0)
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("job_id = ?", query.JobID)
+		if query.Status != "" {
+			sess.And("status = ?", query.Status)
+		}
+		return sess.Asc("id").Find(&items)
+	})
+	return items, err
+}
+
+func (xs *xormStore) Cancel(ctx context.Context, id, orgID int64, now time.Time) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("id = ? AND org_id = ?", id, orgID).In("status", unfinished...).
+			Cols("status", "updated", "finished").
+			Update(&dashboardbulk.Job{Status: dashboardbulk.JobStatusCanceled, Updated: now, Finished: &now})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboardbulk.ErrJobFinished.Errorf("job %d is finished", id)
+		}
+		return nil
+	})
+}
+
+func (xs *xormStore) FailStale(ctx context.Context, updatedBefore time.Time, now time.Time) (int64, error) {
+	var affected int64
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affected, err = sess.Where("updated < ?", updatedBefore).In("status", unfinished...).
+			Cols("status", "error", "updated", "finished").
+			Update(&dashboardbulk.Job{
+				Status:   dashboardbulk.JobStatusFailed,
+				Error:    "The job was interrupted",
+				Updated:  now,
+				Finished: &now,
+			})
+		return err
+	})
+	return affected, err
+}
+
+func (xs *xormStore) DeleteFinished(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	var affected int64
+	err := xs.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM dashboard_bulk_job_item WHERE job_id IN (SELECT id FROM dashboard_bulk_job WHERE finished < ?)", finishedBefore)
+		if err != nil {
+			return err
+		}
+		affected, err = sess.Where("finished < ?", finishedBefore).Delete(&dashboardbulk.Job{})
+		return err
+	})
+	return affected, err
+}
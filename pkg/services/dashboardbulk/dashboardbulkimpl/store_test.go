@@ -0,0 +1,128 @@
+package dashboardbulkimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardbulk"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationDashboardBulkStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := &xormStore{db: db.InitTestDB(t)}
+	now := time.Now().Truncate(time.Second)
+
+	insertJob := func(t *testing.T, createdBy int64) *dashboardbulk.Job {
+		t.Helper()
+		job := &dashboardbulk.Job{
+			OrgID:     1,
+			Operation: dashboardbulk.Operation{Type: dashboardbulk.OperationTags, AddTags: []string{"team-a"}},
+			Status:    dashboardbulk.JobStatusPending,
+			Total:     2,
+			CreatedBy: createdBy,
+			Created:   now,
+			Updated:   now,
+		}
+		require.NoError(t, s.Insert(ctx, job))
+		return job
+	}
+
+	t.Run("insert, update and get a job", func(t *testing.T) {
+		job := insertJob(t, 1)
+
+		job.Status = dashboardbulk.JobStatusRunning
+		job.Processed = 1
+		job.Succeeded = 1
+		require.NoError(t, s.Update(ctx, job))
+
+		saved, err := s.Get(ctx, job.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, dashboardbulk.JobStatusRunning, saved.Status)
+		assert.Equal(t, 1, saved.Processed)
+		assert.Equal(t, []string{"team-a"}, saved.Operation.AddTags)
+
+		_, err = s.Get(ctx, job.ID, 2)
+		require.ErrorIs(t, err, dashboardbulk.ErrJobNotFound)
+	})
+
+	t.Run("a canceled job cannot be updated", func(t *testing.T) {
+		job := insertJob(t, 1)
+		require.NoError(t, s.Cancel(ctx, job.ID, 1, now))
+
+		job.Processed = 1
+		require.ErrorIs(t, s.Update(ctx, job), dashboardbulk.ErrJobFinished)
+		require.ErrorIs(t, s.Cancel(ctx, job.ID, 1, now), dashboardbulk.ErrJobFinished)
+
+		saved, err := s.Get(ctx, job.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, dashboardbulk.JobStatusCanceled, saved.Status)
+		assert.Equal(t, 0, saved.Processed)
+		require.NotNil(t, saved.Finished)
+	})
+
+	t.Run("items", func(t *testing.T) {
+		job := insertJob(t, 1)
+		for _, item := range []*dashboardbulk.JobItem{
+			{JobID: job.ID, DashboardUID: "a", Status: dashboardbulk.ItemStatusSuccess, Created: now},
+			{JobID: job.ID, DashboardUID: "b", Status: dashboardbulk.ItemStatusFailed, Message: "Access denied", Created: now},
+		} {
+			require.NoError(t, s.InsertItem(ctx, item))
+		}
+
+		items, err := s.GetItems(ctx, &dashboardbulk.GetJobItemsQuery{JobID: job.ID})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "a", items[0].DashboardUID)
+
+		items, err = s.GetItems(ctx, &dashboardbulk.GetJobItemsQuery{JobID: job.ID, Status: dashboardbulk.ItemStatusFailed})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "Access denied", items[0].Message)
+	})
+
+	t.Run("search the jobs of a user", func(t *testing.T) {
+		other := insertJob(t, 5)
+		latest := insertJob(t, 4)
+		insertJob(t, 4)
+
+		jobs, err := s.Search(ctx, &dashboardbulk.SearchJobsQuery{OrgID: 1, User: &user.SignedInUser{UserID: 4}, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Greater(t, jobs[0].ID, latest.ID)
+		assert.NotEqual(t, other.ID, jobs[0].ID)
+	})
+
+	t.Run("fail stale jobs and delete old jobs", func(t *testing.T) {
+		stale := insertJob(t, 6)
+		require.NoError(t, s.InsertItem(ctx, &dashboardbulk.JobItem{JobID: stale.ID, DashboardUID: "a", Status: dashboardbulk.ItemStatusSuccess, Created: now}))
+
+		failed, err := s.FailStale(ctx, now.Add(time.Minute), now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, failed, int64(1))
+
+		saved, err := s.Get(ctx, stale.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, dashboardbulk.JobStatusFailed, saved.Status)
+		assert.Equal(t, "The job was interrupted", saved.Error)
+
+		deleted, err := s.DeleteFinished(ctx, now.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(1))
+
+		_, err = s.Get(ctx, stale.ID, 1)
+		require.ErrorIs(t, err, dashboardbulk.ErrJobNotFound)
+		items, err := s.GetItems(ctx, &dashboardbulk.GetJobItemsQuery{JobID: stale.ID})
+		require.NoError(t, err)
+		assert.Empty(t, items)
+	})
+}
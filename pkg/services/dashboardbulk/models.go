@@ -0,0 +1,213 @@
+package dashboardbulk
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrJobNotFound       = errutil.NotFound("dashboardbulk.job-not-found", errutil.WithPublicMessage("Bulk job not found"))
+	ErrInvalidOperation  = errutil.BadRequest("dashboardbulk.invalid-operation", errutil.WithPublicMessage("Invalid bulk operation"))
+	ErrInvalidSelection  = errutil.BadRequest("dashboardbulk.invalid-selection", errutil.WithPublicMessage("Invalid dashboard selection"))
+	ErrTooManyDashboards = errutil.BadRequest("dashboardbulk.too-many-dashboards", errutil.WithPublicMessage("Too many dashboards selected, a bulk job can change up to 5000 dashboards"))
+	ErrJobFinished       = errutil.BadRequest("dashboardbulk.job-finished", errutil.WithPublicMessage("The bulk job is already finished"))
+	ErrLintFailed        = errutil.BadRequest("dashboardbulk.lint-failed", errutil.WithPublicMessage("The dashboard has lint problems that block saving it"))
+)
+
+// MaxDashboards is the maximum number of dashboards of a job.
+const MaxDashboards = 5000
+
+type OperationType string
+
+const (
+	// OperationMove moves the dashboards to a folder.
+	OperationMove OperationType = "move"
+	// OperationTags adds tags to the dashboards and removes tags from them.
+	OperationTags OperationType = "tags"
+	// OperationReplaceDatasource replaces the references to a data source with references to another one.
+	OperationReplaceDatasource OperationType = "replace-datasource"
+	// OperationDelete deletes the dashboards.
+	OperationDelete OperationType = "delete"
+)
+
+// Operation is the change made to each dashboard of a job.
+type Operation struct {
+	Type OperationType `json:"type"`
+	// FolderUID is the folder dashboards are moved to, empty for the General folder.
+	FolderUID  string   `json:"folderUid,omitempty"`
+	AddTags    []string `json:"addTags,omitempty"`
+	RemoveTags []string `json:"removeTags,omitempty"`
+	// FromDatasourceUID is the data source replaced, by UID or by name for dashboards that reference data sources by name.
+	FromDatasourceUID string `json:"fromDatasourceUid,omitempty"`
+	// ToDatasourceUID is the data source replacing it, it must exist.
+	ToDatasourceUID string `json:"toDatasourceUid,omitempty"`
+}
+
+// Validate checks that the operation has the fields of its type.
+func (o Operation) Validate() error {
+	switch o.Type {
+	case OperationMove, OperationDelete:
+		return nil
+	case OperationTags:
+		if len(o.AddTags) == 0 && len(o.RemoveTags) == 0 {
+			return ErrInvalidOperation.Errorf("addTags or removeTags is required")
+		}
+		return nil
+	case OperationReplaceDatasource:
+		if o.FromDatasourceUID == "" || o.ToDatasourceUID == "" {
+			return ErrInvalidOperation.Errorf("fromDatasourceUid and toDatasourceUid are required")
+		}
+		if o.FromDatasourceUID == o.ToDatasourceUID {
+			return ErrInvalidOperation.Errorf("fromDatasourceUid and toDatasourceUid must be different")
+		}
+		return nil
+	default:
+		return ErrInvalidOperation.Errorf("type must be move, tags, replace-datasource or delete")
+	}
+}
+
+// Describe returns a short description of the operation, used as the message of the dashboard versions it saves.
+func (o Operation) Describe() string {
+	switch o.Type {
+	case OperationMove:
+		if o.FolderUID == "" {
+			return "Move to the General folder"
+		}
+		return fmt.Sprintf("Move to folder %s", o.FolderUID)
+	case OperationTags:
+		return "Change tags"
+	case OperationReplaceDatasource:
+		return fmt.Sprintf("Replace data source %s with %s", o.FromDatasourceUID, o.ToDatasourceUID)
+	case OperationDelete:
+		return "Delete"
+	}
+	return string(o.Type)
+}
+
+// Selection is the dashboards of a job, given by UID or selected by a search.
+type Selection struct {
+	DashboardUIDs []string     `json:"dashboardUids,omitempty"`
+	Query         *SearchQuery `json:"query,omitempty"`
+}
+
+// SearchQuery selects the dashboards the user can view that match all the criteria.
+type SearchQuery struct {
+	// Query matches the titles of the dashboards.
+	Query      string   `json:"query,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	FolderUIDs []string `json:"folderUids,omitempty"`
+}
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+// IsFinished returns whether the job stopped running.
+func (s JobStatus) IsFinished() bool {
+	return s == JobStatusCompleted || s == JobStatusFailed || s == JobStatusCanceled
+}
+
+// Job is an operation run on many dashboards.
+type Job struct {
+	ID        int64     `xorm:"pk autoincr 'id'" json:"id"`
+	OrgID     int64     `xorm:"org_id" json:"orgId"`
+	Operation Operation `xorm:"jsonb operation" json:"operation"`
+	// DryRun jobs check the changes and the permissions without changing the dashboards.
+	DryRun    bool      `xorm:"dry_run" json:"dryRun"`
+	Status    JobStatus `xorm:"status" json:"status"`
+	Total     int       `xorm:"total" json:"total"`
+	Processed int       `xorm:"processed" json:"processed"`
+	Succeeded int       `xorm:"succeeded" json:"succeeded"`
+	Skipped   int       `xorm:"skipped" json:"skipped"`
+	Failed    int       `xorm:"failed" json:"failed"`
+	// Error is the reason why the whole job failed, the failures of dashboards are in their items.
+	Error     string     `xorm:"error" json:"error,omitempty"`
+	CreatedBy int64      `xorm:"created_by" json:"createdBy"`
+	Created   time.Time  `xorm:"created" json:"created"`
+	Updated   time.Time  `xorm:"updated" json:"updated"`
+	Finished  *time.Time `xorm:"finished" json:"finished,omitempty"`
+}
+
+func (j Job) TableName() string {
+	return "dashboard_bulk_job"
+}
+
+type ItemStatus string
+
+const (
+	ItemStatusSuccess ItemStatus = "success"
+	// ItemStatusSkipped is the status of dashboards the operation does not change.
+	ItemStatusSkipped ItemStatus = "skipped"
+	ItemStatusFailed  ItemStatus = "failed"
+)
+
+func (s ItemStatus) IsValid() bool {
+	return s == ItemStatusSuccess || s == ItemStatusSkipped || s == ItemStatusFailed
+}
+
+// JobItem is the result of the operation of a job on a dashboard.
+type JobItem struct {
+	ID           int64      `xorm:"pk autoincr 'id'" json:"-"`
+	JobID        int64      `xorm:"job_id" json:"jobId"`
+	DashboardUID string     `xorm:"dashboard_uid" json:"dashboardUid"`
+	Title        string     `xorm:"title" json:"title,omitempty"`
+	Status       ItemStatus `xorm:"status" json:"status"`
+	Message      string     `xorm:"message" json:"message"`
+	// Version is the version of the dashboard saved by the operation.
+	Version int `xorm:"'version'" json:"version,omitempty"`
+	// DraftID is the draft created instead of saving dashboards that require approval.
+	DraftID int64     `xorm:"draft_id" json:"draftId,omitempty"`
+	Created time.Time `xorm:"created" json:"created"`
+}
+
+func (i JobItem) TableName() string {
+	return "dashboard_bulk_job_item"
+}
+
+// CreateJobCommand starts an operation on the selected dashboards.
+type CreateJobCommand struct {
+	Selection Selection
+	Operation Operation
+	DryRun    bool
+	User      *user.SignedInUser
+	// Request is the HTTP request creating the job, recorded in the audit events of the changes.
+	Request *http.Request
+}
+
+// GetJobQuery returns a job created by the user.
+type GetJobQuery struct {
+	ID    int64
+	OrgID int64
+	User  *user.SignedInUser
+}
+
+// SearchJobsQuery returns the jobs created by the user, the most recent first.
+type SearchJobsQuery struct {
+	OrgID int64
+	User  *user.SignedInUser
+	Limit int
+}
+
+type GetJobItemsQuery struct {
+	JobID int64
+	OrgID int64
+	User  *user.SignedInUser
+	// Status only returns the items with this status when set.
+	Status ItemStatus
+}
+
+type CancelJobCommand struct {
+	ID    int64
+	OrgID int64
+	User  *user.SignedInUser
+}
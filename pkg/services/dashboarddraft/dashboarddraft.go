@@ -0,0 +1,23 @@
+package dashboarddraft
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// Service manages the changes to dashboards that require approval. Saving such a dashboard creates
+// a pending draft, which only replaces the dashboard once a reviewer approves it.
+type Service interface {
+	RequiresApproval(ctx context.Context, query *RequiresApprovalQuery) (bool, error)
+	SetApprovalRequired(ctx context.Context, cmd *SetApprovalRequiredCommand) error
+	CreateDraft(ctx context.Context, cmd *CreateDraftCommand) (*Draft, error)
+	GetDraft(ctx context.Context, query *GetDraftQuery) (*Draft, error)
+	SearchDrafts(ctx context.Context, query *SearchDraftsQuery) ([]*Draft, error)
+	// DiffDraft compares the draft with the dashboard it replaces.
+	DiffDraft(ctx context.Context, query *DiffDraftQuery) (*dashdiffs.Result, error)
+	// ApproveDraft saves the draft as a new version of the dashboard.
+	ApproveDraft(ctx context.Context, cmd *ReviewDraftCommand) (*dashboards.Dashboard, error)
+	RejectDraft(ctx context.Context, cmd *ReviewDraftCommand) (*Draft, error)
+}
@@ -0,0 +1,395 @@
+package dashboarddraftimpl
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	uidScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+	canRead := ac.EvalPermission(dashboards.ActionDashboardsRead, uidScope)
+	canApprove := ac.EvalPermission(dashboards.ActionDashboardsDraftsApprove, uidScope)
+
+	routeRegister.Group("/api/dashboards/uid/:uid", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/approval", authorize(canRead), routing.Wrap(s.handleGetApproval))
+		subrouter.Put("/approval", authorize(canApprove), routing.Wrap(s.handleSetApproval))
+		subrouter.Get("/drafts", authorize(canRead), routing.Wrap(s.handleSearch))
+		subrouter.Get("/drafts/:id", authorize(canRead), routing.Wrap(s.handleGet))
+		subrouter.Get("/drafts/:id/diff", authorize(canRead), routing.Wrap(s.handleDiff))
+		subrouter.Post("/drafts/:id/approve", authorize(canApprove), routing.Wrap(s.handleApprove))
+		// authors can reject their own drafts, which is checked by the handler
+		subrouter.Post("/drafts/:id/reject", authorize(ac.EvalAny(canApprove, ac.EvalPermission(dashboards.ActionDashboardsWrite, uidScope))), routing.Wrap(s.handleReject))
+	})
+}
+
+// swagger:route GET /dashboards/uid/{uid}/approval dashboard_drafts getDashboardApproval
+//
+// Get whether the changes of a dashboard require approval.
+//
+// Responses:
+// 200: dashboardApprovalResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleGetApproval(c *contextmodel.ReqContext) response.Response {
+	required, err := s.RequiresApproval(c.Req.Context(), &dashboarddraft.RequiresApprovalQuery{
+		OrgID:        c.SignedInUser.OrgID,
+		DashboardUID: web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the approval of the dashboard", err)
+	}
+	return response.JSON(http.StatusOK, DashboardApproval{Required: required})
+}
+
+// swagger:route PUT /dashboards/uid/{uid}/approval dashboard_drafts setDashboardApproval
+//
+// Set whether the changes of a dashboard require approval.
+//
+// When approval is required, saving the dashboard creates a draft instead of a new version of the dashboard.
+//
+// Responses:
+// 200: dashboardApprovalResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleSetApproval(c *contextmodel.ReqContext) response.Response {
+	body := DashboardApproval{}
+	if err := web.Bind(c.Req, &body); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	err := s.SetApprovalRequired(c.Req.Context(), &dashboarddraft.SetApprovalRequiredCommand{
+		OrgID:        c.SignedInUser.OrgID,
+		DashboardUID: web.Params(c.Req)[":uid"],
+		Required:     body.Required,
+		User:         c.SignedInUser,
+	})
+	if err != nil {
+		return dashboardErrorResponse(err, "Failed to set the approval of the dashboard")
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+// swagger:route GET /dashboards/uid/{uid}/drafts dashboard_drafts searchDashboardDrafts
+//
+// Get the drafts of a dashboard.
+//
+// Returns the drafts without their dashboard, the most recent first.
+//
+// Responses:
+// 200: searchDashboardDraftsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSearch(c *contextmodel.ReqContext) response.Response {
+	status := dashboarddraft.Status(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		return response.Error(http.StatusBadRequest, "status must be pending, approved or rejected", nil)
+	}
+
+	result, err := s.SearchDrafts(c.Req.Context(), &dashboarddraft.SearchDraftsQuery{
+		OrgID:        c.SignedInUser.OrgID,
+		DashboardUID: web.Params(c.Req)[":uid"],
+		Status:       status,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard drafts", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /dashboards/uid/{uid}/drafts/{id} dashboard_drafts getDashboardDraft
+//
+// Get a draft of a dashboard, with its dashboard.
+//
+// Responses:
+// 200: dashboardDraftResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleGet(c *contextmodel.ReqContext) response.Response {
+	query, err := draftQuery(c)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	draft, err := s.GetDraft(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard draft", err)
+	}
+	return response.JSON(http.StatusOK, draft)
+}
+
+// swagger:route GET /dashboards/uid/{uid}/drafts/{id}/diff dashboard_drafts diffDashboardDraft
+//
+// Compare a draft with the dashboard.
+//
+// Returns the changes the draft makes to the current version of the dashboard, as HTML for the `basic` and `json`
+// diff types, or as JSON for the `delta` diff type.
+//
+// Produces:
+// - text/html
+// - application/json
+//
+// Responses:
+// 200: calculateDashboardDiffResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleDiff(c *contextmodel.ReqContext) response.Response {
+	query, err := draftQuery(c)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	diffType := dashdiffs.ParseDiffType(c.Query("diffType"))
+	result, err := s.DiffDraft(c.Req.Context(), &dashboarddraft.DiffDraftQuery{
+		ID:           query.ID,
+		OrgID:        query.OrgID,
+		DashboardUID: query.DashboardUID,
+		DiffType:     diffType,
+	})
+	if err != nil {
+		return dashboardErrorResponse(err, "Unable to compute diff")
+	}
+
+	if diffType == dashdiffs.DiffDelta {
+		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
+	}
+	return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "text/html")
+}
+
+// swagger:route POST /dashboards/uid/{uid}/drafts/{id}/approve dashboard_drafts approveDashboardDraft
+//
+// Approve a draft of a dashboard.
+//
+// Saves the draft as a new version of the dashboard. Authors cannot approve their own drafts, and drafts made
+// from an older version of the dashboard cannot be approved.
+//
+// Responses:
+// 200: approveDashboardDraftResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleApprove(c *contextmodel.ReqContext) response.Response {
+	cmd, err := reviewCommand(c)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dashboard, err := s.ApproveDraft(c.Req.Context(), cmd)
+	if err != nil {
+		return dashboardErrorResponse(err, "Failed to approve dashboard draft")
+	}
+	return response.JSON(http.StatusOK, ApproveDraftResult{
+		Status:  string(dashboarddraft.StatusApproved),
+		ID:      dashboard.ID,
+		UID:     dashboard.UID,
+		URL:     dashboard.GetURL(),
+		Version: dashboard.Version,
+	})
+}
+
+// swagger:route POST /dashboards/uid/{uid}/drafts/{id}/reject dashboard_drafts rejectDashboardDraft
+//
+// Reject a draft of a dashboard.
+//
+// Closes the draft without changing the dashboard. Authors can reject their own drafts to withdraw them.
+//
+// Responses:
+// 200: dashboardDraftResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleReject(c *contextmodel.ReqContext) response.Response {
+	cmd, err := reviewCommand(c)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	canApprove, err := s.accessControl.Evaluate(c.Req.Context(), c.SignedInUser,
+		ac.EvalPermission(dashboards.ActionDashboardsDraftsApprove, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(cmd.DashboardUID)))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+	}
+	if !canApprove {
+		draft, err := s.GetDraft(c.Req.Context(), &dashboarddraft.GetDraftQuery{ID: cmd.ID, OrgID: cmd.OrgID, DashboardUID: cmd.DashboardUID})
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard draft", err)
+		}
+		if draft.CreatedBy != c.SignedInUser.UserID {
+			return response.Error(http.StatusForbidden, "You can only reject your own drafts", nil)
+		}
+	}
+
+	draft, err := s.RejectDraft(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to reject dashboard draft", err)
+	}
+	return response.JSON(http.StatusOK, draft)
+}
+
+func draftQuery(c *contextmodel.ReqContext) (*dashboarddraft.GetDraftQuery, error) {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &dashboarddraft.GetDraftQuery{
+		ID:           id,
+		OrgID:        c.SignedInUser.OrgID,
+		DashboardUID: web.Params(c.Req)[":uid"],
+	}, nil
+}
+
+func reviewCommand(c *contextmodel.ReqContext) (*dashboarddraft.ReviewDraftCommand, error) {
+	query, err := draftQuery(c)
+	if err != nil {
+		return nil, err
+	}
+
+	body := ReviewDraftBody{}
+	if c.Req.ContentLength != 0 {
+		if err := web.Bind(c.Req, &body); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dashboarddraft.ReviewDraftCommand{
+		ID:           query.ID,
+		OrgID:        query.OrgID,
+		DashboardUID: query.DashboardUID,
+		Comment:      body.Comment,
+		User:         c.SignedInUser,
+	}, nil
+}
+
+// dashboardErrorResponse handles the errors of the dashboard service, which are not errutil errors yet.
+func dashboardErrorResponse(err error, message string) response.Response {
+	var dashboardErr dashboards.DashboardErr
+	if errors.As(err, &dashboardErr) {
+		return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
+}
+
+type DashboardApproval struct {
+	Required bool `json:"required"`
+}
+
+// ReviewDraftBody optionally explains the review to the author of the draft.
+type ReviewDraftBody struct {
+	Comment string `json:"comment"`
+}
+
+type ApproveDraftResult struct {
+	Status  string `json:"status"`
+	ID      int64  `json:"id"`
+	UID     string `json:"uid"`
+	URL     string `json:"url"`
+	Version int    `json:"version"`
+}
+
+// swagger:parameters getDashboardApproval searchDashboardDrafts
+type DashboardUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters setDashboardApproval
+type SetDashboardApprovalParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body DashboardApproval `json:"body"`
+}
+
+// swagger:parameters searchDashboardDrafts
+type SearchDashboardDraftsParams struct {
+	// Only return the drafts with this status.
+	// in:query
+	// required:false
+	// enum: pending,approved,rejected
+	Status string `json:"status"`
+}
+
+// swagger:parameters getDashboardDraft diffDashboardDraft
+type DashboardDraftParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+}
+
+// swagger:parameters diffDashboardDraft
+type DiffDashboardDraftParams struct {
+	// in:query
+	// required:false
+	// default:basic
+	// enum: json,basic,delta
+	DiffType string `json:"diffType"`
+}
+
+// swagger:parameters approveDashboardDraft rejectDashboardDraft
+type ReviewDashboardDraftParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+	// in:body
+	// required:false
+	Body ReviewDraftBody `json:"body"`
+}
+
+// swagger:response dashboardApprovalResponse
+type DashboardApprovalResponse struct {
+	// in: body
+	Body DashboardApproval `json:"body"`
+}
+
+// swagger:response searchDashboardDraftsResponse
+type SearchDashboardDraftsResponse struct {
+	// in: body
+	Body []*dashboarddraft.Draft `json:"body"`
+}
+
+// swagger:response dashboardDraftResponse
+type DashboardDraftResponse struct {
+	// in: body
+	Body *dashboarddraft.Draft `json:"body"`
+}
+
+// swagger:response approveDashboardDraftResponse
+type ApproveDashboardDraftResponse struct {
+	// in: body
+	Body ApproveDraftResult `json:"body"`
+}
@@ -0,0 +1,31 @@
+package dashboarddraftimpl
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+)
+
+// approvalRecord marks a dashboard whose changes require approval.
+type approvalRecord struct {
+	ID           int64     `xorm:"pk autoincr 'id'"`
+	OrgID        int64     `xorm:"org_id"`
+	DashboardID  int64     `xorm:"dashboard_id"`
+	DashboardUID string    `xorm:"dashboard_uid"`
+	CreatedBy    int64     `xorm:"created_by"`
+	Created      time.Time `xorm:"created"`
+}
+
+func (r approvalRecord) TableName() string {
+	return "dashboard_approval"
+}
+
+type reviewDraftCommand struct {
+	ID            int64
+	OrgID         int64
+	Status        dashboarddraft.Status
+	Version       int
+	ReviewedBy    int64
+	Reviewed      time.Time
+	ReviewComment string
+}
@@ -0,0 +1,213 @@
+package dashboarddraftimpl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+)
+
+var _ dashboarddraft.Service = (*Service)(nil)
+
+type Service struct {
+	store            store
+	dashboardService dashboards.DashboardService
+	accessControl    ac.AccessControl
+	log              log.Logger
+}
+
+func ProvideService(
+	sql db.DB,
+	dashboardService dashboards.DashboardService,
+	accessControl ac.AccessControl,
+	routeRegister routing.RouteRegister,
+) *Service {
+	s := &Service{
+		store:            &xormStore{db: sql},
+		dashboardService: dashboardService,
+		accessControl:    accessControl,
+		log:              log.New("dashboard.drafts"),
+	}
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+func (s *Service) RequiresApproval(ctx context.Context, query *dashboarddraft.RequiresApprovalQuery) (bool, error) {
+	return s.store.RequiresApproval(ctx, query)
+}
+
+func (s *Service) SetApprovalRequired(ctx context.Context, cmd *dashboarddraft.SetApprovalRequiredCommand) error {
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: cmd.DashboardUID, OrgID: cmd.OrgID})
+	if err != nil {
+		return err
+	}
+	if dashboard.IsFolder {
+		return dashboarddraft.ErrFolder.Errorf("%s is a folder", dashboard.UID)
+	}
+	return s.store.SetApprovalRequired(ctx, dashboard, cmd.Required, cmd.User.UserID)
+}
+
+// CreateDraft saves the changes of a dashboard as a pending draft. Like saving the dashboard, it fails
+// if the dashboard changed since the version the changes were made from, unless they overwrite it. Drafts
+// cannot move the dashboard, the diff reviewers approve only shows the changes of the dashboard JSON.
+func (s *Service) CreateDraft(ctx context.Context, cmd *dashboarddraft.CreateDraftCommand) (*dashboarddraft.Draft, error) {
+	dash := cmd.Dashboard
+	existing, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{ID: dash.ID, UID: dash.UID, OrgID: dash.OrgID})
+	if err != nil {
+		return nil, err
+	}
+	if existing.IsFolder {
+		return nil, dashboarddraft.ErrFolder.Errorf("%s is a folder", existing.UID)
+	}
+
+	g, err := guardian.NewByDashboard(ctx, existing, existing.OrgID, cmd.User)
+	if err != nil {
+		return nil, err
+	}
+	if canSave, err := g.CanSave(); err != nil || !canSave {
+		if err != nil {
+			return nil, err
+		}
+		return nil, dashboards.ErrDashboardUpdateAccessDenied
+	}
+
+	if dash.Version != existing.Version && !cmd.Overwrite {
+		return nil, dashboards.ErrDashboardVersionMismatch
+	}
+	if dash.FolderID != existing.FolderID {
+		return nil, dashboarddraft.ErrFolderChange.Errorf("draft moves dashboard %s from folder %d to %d", existing.UID, existing.FolderID, dash.FolderID)
+	}
+
+	// the draft replaces the dashboard it was made from, whatever the identifiers in the changes
+	data := dash.Data
+	data.Set("id", existing.ID)
+	data.Set("uid", existing.UID)
+	data.Set("version", existing.Version)
+
+	draft := &dashboarddraft.Draft{
+		OrgID:         existing.OrgID,
+		DashboardID:   existing.ID,
+		DashboardUID:  existing.UID,
+		FolderID:      existing.FolderID,
+		ParentVersion: existing.Version,
+		Title:         dash.Title,
+		Data:          data,
+		Message:       cmd.Message,
+		Status:        dashboarddraft.StatusPending,
+		CreatedBy:     cmd.User.UserID,
+		Created:       time.Now(),
+	}
+	if err := s.store.Insert(ctx, draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+func (s *Service) GetDraft(ctx context.Context, query *dashboarddraft.GetDraftQuery) (*dashboarddraft.Draft, error) {
+	return s.store.Get(ctx, query)
+}
+
+func (s *Service) SearchDrafts(ctx context.Context, query *dashboarddraft.SearchDraftsQuery) ([]*dashboarddraft.Draft, error) {
+	return s.store.Search(ctx, query)
+}
+
+func (s *Service) DiffDraft(ctx context.Context, query *dashboarddraft.DiffDraftQuery) (*dashdiffs.Result, error) {
+	draft, err := s.store.Get(ctx, &dashboarddraft.GetDraftQuery{ID: query.ID, OrgID: query.OrgID, DashboardUID: query.DashboardUID})
+	if err != nil {
+		return nil, err
+	}
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: query.DashboardUID, OrgID: query.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	options := &dashdiffs.Options{OrgId: query.OrgID, DiffType: query.DiffType}
+	result, err := dashdiffs.CalculateDiff(ctx, options, dashboard.Data, draft.Data)
+	if errors.Is(err, dashdiffs.ErrNilDiff) {
+		return &dashdiffs.Result{}, nil
+	}
+	return result, err
+}
+
+// ApproveDraft saves the draft as a new version of the dashboard, with the permissions of the reviewer.
+// Drafts made from an older version of the dashboard cannot be approved, as they would revert the changes
+// made since.
+func (s *Service) ApproveDraft(ctx context.Context, cmd *dashboarddraft.ReviewDraftCommand) (*dashboards.Dashboard, error) {
+	draft, err := s.store.Get(ctx, &dashboarddraft.GetDraftQuery{ID: cmd.ID, OrgID: cmd.OrgID, DashboardUID: cmd.DashboardUID})
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status != dashboarddraft.StatusPending {
+		return nil, dashboarddraft.ErrDraftNotPending.Errorf("draft %d is %s", draft.ID, draft.Status)
+	}
+	if draft.CreatedBy == cmd.User.UserID {
+		return nil, dashboarddraft.ErrOwnDraft.Errorf("user %d created draft %d", cmd.User.UserID, draft.ID)
+	}
+
+	dash := dashboards.NewDashboardFromJson(draft.Data)
+	dash.OrgID = draft.OrgID
+	dash.FolderID = draft.FolderID
+	dash.UpdatedBy = cmd.User.UserID
+	dash.UpdateSlug()
+
+	saved, err := s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+		OrgID:     draft.OrgID,
+		User:      cmd.User,
+		Message:   draft.Message,
+		Dashboard: dash,
+	}, true)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardVersionMismatch) {
+			return nil, dashboarddraft.ErrDraftOutdated.Errorf("dashboard %s changed since version %d: %w", draft.DashboardUID, draft.ParentVersion, err)
+		}
+		return nil, err
+	}
+
+	if err := s.store.Review(ctx, &reviewDraftCommand{
+		ID:            draft.ID,
+		OrgID:         draft.OrgID,
+		Status:        dashboarddraft.StatusApproved,
+		Version:       saved.Version,
+		ReviewedBy:    cmd.User.UserID,
+		Reviewed:      time.Now(),
+		ReviewComment: cmd.Comment,
+	}); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// RejectDraft closes the draft without changing the dashboard. Unlike approval, authors can reject their
+// own drafts to withdraw them.
+func (s *Service) RejectDraft(ctx context.Context, cmd *dashboarddraft.ReviewDraftCommand) (*dashboarddraft.Draft, error) {
+	draft, err := s.store.Get(ctx, &dashboarddraft.GetDraftQuery{ID: cmd.ID, OrgID: cmd.OrgID, DashboardUID: cmd.DashboardUID})
+	if err != nil {
+		return nil, err
+	}
+
+	review := &reviewDraftCommand{
+		ID:            draft.ID,
+		OrgID:         draft.OrgID,
+		Status:        dashboarddraft.StatusRejected,
+		ReviewedBy:    cmd.User.UserID,
+		Reviewed:      time.Now(),
+		ReviewComment: cmd.Comment,
+	}
+	if err := s.store.Review(ctx, review); err != nil {
+		return nil, err
+	}
+
+	draft.Status = review.Status
+	draft.ReviewedBy = review.ReviewedBy
+	draft.Reviewed = &review.Reviewed
+	draft.ReviewComment = review.ReviewComment
+	return draft, nil
+}
@@ -0,0 +1,230 @@
+package dashboarddraftimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type testEnv struct {
+	service          *Service
+	dashboardService *dashboards.FakeDashboardService
+	dashboard        *dashboards.Dashboard
+	author           *user.SignedInUser
+	reviewer         *user.SignedInUser
+}
+
+func setupTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := &testEnv{
+		dashboardService: dashboards.NewFakeDashboardService(t),
+		dashboard: &dashboards.Dashboard{
+			ID:       10,
+			UID:      "abc",
+			OrgID:    1,
+			FolderID: 2,
+			Version:  3,
+			Title:    "Service overview",
+			Data:     simplejson.NewFromAny(map[string]interface{}{"id": 10, "uid": "abc", "version": 3, "title": "Service overview"}),
+		},
+		author:   &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor},
+		reviewer: &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleAdmin},
+	}
+	env.dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(env.dashboard, nil).Maybe()
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+
+	env.service = &Service{
+		store:            &xormStore{db: db.InitTestDB(t)},
+		dashboardService: env.dashboardService,
+		log:              log.NewNopLogger(),
+	}
+	return env
+}
+
+// change returns the dashboard saved with a new title, as postDashboard passes it to the service.
+func (env *testEnv) change(title string, version int) *dashboards.Dashboard {
+	cmd := dashboards.SaveDashboardCommand{
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"uid": "abc", "version": version, "title": title}),
+		OrgID:     1,
+		UserID:    env.author.UserID,
+		FolderID:  2,
+	}
+	return cmd.GetDashboardModel()
+}
+
+func (env *testEnv) createDraft(t *testing.T, title string) *dashboarddraft.Draft {
+	t.Helper()
+	draft, err := env.service.CreateDraft(context.Background(), &dashboarddraft.CreateDraftCommand{
+		Dashboard: env.change(title, env.dashboard.Version),
+		Message:   "Rename",
+		User:      env.author,
+	})
+	require.NoError(t, err)
+	return draft
+}
+
+func TestIntegrationService_CreateDraft(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	t.Run("saves the changes as a pending draft of the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		draft := env.createDraft(t, "Service overview v2")
+
+		assert.Equal(t, dashboarddraft.StatusPending, draft.Status)
+		assert.Equal(t, int64(10), draft.DashboardID)
+		assert.Equal(t, 3, draft.ParentVersion)
+		assert.Equal(t, "Service overview v2", draft.Title)
+		assert.Equal(t, int64(10), draft.Data.Get("id").MustInt64(), "the draft has the ID of the dashboard")
+
+		stored, err := env.service.GetDraft(ctx, &dashboarddraft.GetDraftQuery{ID: draft.ID, OrgID: 1, DashboardUID: "abc"})
+		require.NoError(t, err)
+		assert.Equal(t, "Service overview v2", stored.Data.Get("title").MustString())
+		assert.Equal(t, "Rename", stored.Message)
+	})
+
+	t.Run("fails if the dashboard changed, unless overwriting it", func(t *testing.T) {
+		env := setupTestEnv(t)
+
+		cmd := &dashboarddraft.CreateDraftCommand{Dashboard: env.change("Old", 2), User: env.author}
+		_, err := env.service.CreateDraft(ctx, cmd)
+		require.ErrorIs(t, err, dashboards.ErrDashboardVersionMismatch)
+
+		cmd.Overwrite = true
+		draft, err := env.service.CreateDraft(ctx, cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 3, draft.ParentVersion)
+	})
+
+	t.Run("fails if the changes move the dashboard to another folder", func(t *testing.T) {
+		env := setupTestEnv(t)
+
+		dash := env.change("Moved", 3)
+		dash.FolderID = 5
+		_, err := env.service.CreateDraft(ctx, &dashboarddraft.CreateDraftCommand{Dashboard: dash, User: env.author})
+		require.ErrorIs(t, err, dashboarddraft.ErrFolderChange)
+	})
+
+	t.Run("requires the user to be able to save the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: false})
+
+		_, err := env.service.CreateDraft(ctx, &dashboarddraft.CreateDraftCommand{Dashboard: env.change("New", 3), User: env.author})
+		require.ErrorIs(t, err, dashboards.ErrDashboardUpdateAccessDenied)
+	})
+}
+
+func TestIntegrationService_ReviewDraft(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	t.Run("approving saves the draft as a new version of the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		draft := env.createDraft(t, "Service overview v2")
+
+		var saved *dashboards.SaveDashboardDTO
+		env.dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, true).Return(func(_ context.Context, dto *dashboards.SaveDashboardDTO, _ bool) (*dashboards.Dashboard, error) {
+			saved = dto
+			dash := *dto.Dashboard
+			dash.Version++
+			return &dash, nil
+		}).Once()
+
+		dashboard, err := env.service.ApproveDraft(ctx, &dashboarddraft.ReviewDraftCommand{ID: draft.ID, OrgID: 1, DashboardUID: "abc", Comment: "LGTM", User: env.reviewer})
+		require.NoError(t, err)
+		assert.Equal(t, 4, dashboard.Version)
+
+		require.NotNil(t, saved)
+		assert.Equal(t, env.reviewer, saved.User)
+		assert.Equal(t, "Rename", saved.Message)
+		assert.False(t, saved.Overwrite)
+		assert.Equal(t, int64(10), saved.Dashboard.ID)
+		assert.Equal(t, 3, saved.Dashboard.Version, "the draft is saved over the version it was made from")
+		assert.Equal(t, int64(2), saved.Dashboard.FolderID)
+		assert.Equal(t, "Service overview v2", saved.Dashboard.Title)
+		assert.Equal(t, "service-overview-v2", saved.Dashboard.Slug)
+
+		approved, err := env.service.GetDraft(ctx, &dashboarddraft.GetDraftQuery{ID: draft.ID, OrgID: 1, DashboardUID: "abc"})
+		require.NoError(t, err)
+		assert.Equal(t, dashboarddraft.StatusApproved, approved.Status)
+		assert.Equal(t, 4, approved.Version)
+		assert.Equal(t, env.reviewer.UserID, approved.ReviewedBy)
+		assert.Equal(t, "LGTM", approved.ReviewComment)
+
+		_, err = env.service.ApproveDraft(ctx, &dashboarddraft.ReviewDraftCommand{ID: draft.ID, OrgID: 1, DashboardUID: "abc", User: env.reviewer})
+		require.ErrorIs(t, err, dashboarddraft.ErrDraftNotPending)
+	})
+
+	t.Run("authors cannot approve their own drafts", func(t *testing.T) {
+		env := setupTestEnv(t)
+		draft := env.createDraft(t, "Service overview v2")
+
+		_, err := env.service.ApproveDraft(ctx, &dashboarddraft.ReviewDraftCommand{ID: draft.ID, OrgID: 1, DashboardUID: "abc", User: env.author})
+		require.ErrorIs(t, err, dashboarddraft.ErrOwnDraft)
+	})
+
+	t.Run("drafts of an older version of the dashboard cannot be approved", func(t *testing.T) {
+		env := setupTestEnv(t)
+		draft := env.createDraft(t, "Service overview v2")
+		env.dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, true).Return(nil, dashboards.ErrDashboardVersionMismatch).Once()
+
+		_, err := env.service.ApproveDraft(ctx, &dashboarddraft.ReviewDraftCommand{ID: draft.ID, OrgID: 1, DashboardUID: "abc", User: env.reviewer})
+		require.ErrorIs(t, err, dashboarddraft.ErrDraftOutdated)
+
+		pending, err := env.service.GetDraft(ctx, &dashboarddraft.GetDraftQuery{ID: draft.ID, OrgID: 1, DashboardUID: "abc"})
+		require.NoError(t, err)
+		assert.Equal(t, dashboarddraft.StatusPending, pending.Status)
+	})
+
+	t.Run("rejecting closes the draft", func(t *testing.T) {
+		env := setupTestEnv(t)
+		draft := env.createDraft(t, "Service overview v2")
+
+		rejected, err := env.service.RejectDraft(ctx, &dashboarddraft.ReviewDraftCommand{ID: draft.ID, OrgID: 1, DashboardUID: "abc", Comment: "Keep the title", User: env.reviewer})
+		require.NoError(t, err)
+		assert.Equal(t, dashboarddraft.StatusRejected, rejected.Status)
+		assert.Equal(t, "Keep the title", rejected.ReviewComment)
+
+		_, err = env.service.ApproveDraft(ctx, &dashboarddraft.ReviewDraftCommand{ID: draft.ID, OrgID: 1, DashboardUID: "abc", User: env.reviewer})
+		require.ErrorIs(t, err, dashboarddraft.ErrDraftNotPending)
+	})
+}
+
+func TestIntegrationService_DiffDraft(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	env := setupTestEnv(t)
+
+	changed := env.createDraft(t, "Service overview v2")
+	result, err := env.service.DiffDraft(ctx, &dashboarddraft.DiffDraftQuery{ID: changed.ID, OrgID: 1, DashboardUID: "abc", DiffType: dashdiffs.DiffDelta})
+	require.NoError(t, err)
+	assert.Contains(t, string(result.Delta), "Service overview v2")
+
+	unchanged := env.createDraft(t, "Service overview")
+	result, err = env.service.DiffDraft(ctx, &dashboarddraft.DiffDraftQuery{ID: unchanged.ID, OrgID: 1, DashboardUID: "abc", DiffType: dashdiffs.DiffBasic})
+	require.NoError(t, err)
+	assert.Empty(t, result.Delta)
+}
@@ -0,0 +1,124 @@
+package dashboarddraftimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+type store interface {
+	RequiresApproval(ctx context.Context, query *dashboarddraft.RequiresApprovalQuery) (bool, error)
+	SetApprovalRequired(ctx context.Context, dashboard *dashboards.Dashboard, required bool, userID int64) error
+	Insert(ctx context.Context, draft *dashboarddraft.Draft) error
+	Get(ctx context.Context, query *dashboarddraft.GetDraftQuery) (*dashboarddraft.Draft, error)
+	Search(ctx context.Context, query *dashboarddraft.SearchDraftsQuery) ([]*dashboarddraft.Draft, error)
+	// Review changes the status of a pending draft, it fails with ErrDraftNotPending if the draft has already been reviewed.
+	Review(ctx context.Context, cmd *reviewDraftCommand) error
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (xs *xormStore) RequiresApproval(ctx context.Context, query *dashboarddraft.RequiresApprovalQuery) (bool, error) {
+	if query.DashboardID == 0 && query.DashboardUID == "" {
+		return false, nil
+	}
+
+	var exists bool
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		exists, err = sess.Where("org_id = ? AND (dashboard_id = ? OR dashboard_uid = ?)", query.OrgID, query.DashboardID, query.DashboardUID).
+			Exist(&approvalRecord{})
+		return err
+	})
+	return exists, err
+}
+
+func (xs *xormStore) SetApprovalRequired(ctx context.Context, dashboard *dashboards.Dashboard, required bool, userID int64) error {
+	return xs.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND dashboard_uid = ?", dashboard.OrgID, dashboard.UID).Exist(&approvalRecord{})
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case required && !exists:
+			_, err = sess.Insert(&approvalRecord{
+				OrgID:        dashboard.OrgID,
+				DashboardID:  dashboard.ID,
+				DashboardUID: dashboard.UID,
+				CreatedBy:    userID,
+				Created:      time.Now(),
+			})
+		case !required && exists:
+			_, err = sess.Where("org_id = ? AND dashboard_uid = ?", dashboard.OrgID, dashboard.UID).Delete(&approvalRecord{})
+		}
+		return err
+	})
+}
+
+func (xs *xormStore) Insert(ctx context.Context, draft *dashboarddraft.Draft) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(draft)
+		return err
+	})
+}
+
+func (xs *xormStore) Get(ctx context.Context, query *dashboarddraft.GetDraftQuery) (*dashboarddraft.Draft, error) {
+	var draft dashboarddraft.Draft
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("id = ? AND org_id = ? AND dashboard_uid = ?", query.ID, query.OrgID, query.DashboardUID).Get(&draft)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return dashboarddraft.ErrDraftNotFound.Errorf("draft %d of dashboard %s not found", query.ID, query.DashboardUID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (xs *xormStore) Search(ctx context.Context, query *dashboarddraft.SearchDraftsQuery) ([]*dashboarddraft.Draft, error) {
+	result := make([]*dashboarddraft.Draft, 0)
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		// the dashboards of the drafts are only returned by Get
+		sess.Omit("data").Where("org_id = ?", query.OrgID)
+		if query.DashboardUID != "" {
+			sess.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		if query.Status != "" {
+			sess.And("status = ?", query.Status)
+		}
+		return sess.Desc("id").Find(&result)
+	})
+	return result, err
+}
+
+func (xs *xormStore) Review(ctx context.Context, cmd *reviewDraftCommand) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Table(&dashboarddraft.Draft{}).
+			Where("id = ? AND org_id = ? AND status = ?", cmd.ID, cmd.OrgID, dashboarddraft.StatusPending).
+			Update(map[string]interface{}{
+				"status":         cmd.Status,
+				"version":        cmd.Version,
+				"reviewed_by":    cmd.ReviewedBy,
+				"reviewed":       cmd.Reviewed,
+				"review_comment": cmd.ReviewComment,
+			})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboarddraft.ErrDraftNotPending.Errorf("draft %d is not pending", cmd.ID)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+package dashboarddraftimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestIntegrationDashboardDraftStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := &xormStore{db: db.InitTestDB(t)}
+
+	t.Run("requires approval by dashboard UID or ID", func(t *testing.T) {
+		dashboard := &dashboards.Dashboard{ID: 10, UID: "abc", OrgID: 1}
+		require.NoError(t, s.SetApprovalRequired(ctx, dashboard, true, 1))
+		require.NoError(t, s.SetApprovalRequired(ctx, dashboard, true, 1), "requiring approval twice is a no-op")
+
+		testCases := map[string]struct {
+			query    dashboarddraft.RequiresApprovalQuery
+			expected bool
+		}{
+			"uid":          {query: dashboarddraft.RequiresApprovalQuery{OrgID: 1, DashboardUID: "abc"}, expected: true},
+			"id":           {query: dashboarddraft.RequiresApprovalQuery{OrgID: 1, DashboardID: 10}, expected: true},
+			"other org":    {query: dashboarddraft.RequiresApprovalQuery{OrgID: 2, DashboardUID: "abc", DashboardID: 10}},
+			"other":        {query: dashboarddraft.RequiresApprovalQuery{OrgID: 1, DashboardUID: "def", DashboardID: 11}},
+			"no dashboard": {query: dashboarddraft.RequiresApprovalQuery{OrgID: 1}},
+		}
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				required, err := s.RequiresApproval(ctx, &tc.query)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, required)
+			})
+		}
+
+		require.NoError(t, s.SetApprovalRequired(ctx, dashboard, false, 1))
+		required, err := s.RequiresApproval(ctx, &dashboarddraft.RequiresApprovalQuery{OrgID: 1, DashboardUID: "abc"})
+		require.NoError(t, err)
+		assert.False(t, required)
+	})
+
+	t.Run("stores and reviews drafts", func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Second)
+		newDraft := func(orgID int64, dashboardUID string) *dashboarddraft.Draft {
+			return &dashboarddraft.Draft{
+				OrgID:         orgID,
+				DashboardID:   10,
+				DashboardUID:  dashboardUID,
+				ParentVersion: 3,
+				Title:         "Service overview",
+				Data:          simplejson.NewFromAny(map[string]interface{}{"title": "Service overview"}),
+				Message:       "Add latency panel",
+				Status:        dashboarddraft.StatusPending,
+				CreatedBy:     2,
+				Created:       now,
+			}
+		}
+
+		first := newDraft(1, "abc")
+		second := newDraft(1, "abc")
+		require.NoError(t, s.Insert(ctx, first))
+		require.NoError(t, s.Insert(ctx, second))
+		require.NoError(t, s.Insert(ctx, newDraft(1, "def")))
+		require.NoError(t, s.Insert(ctx, newDraft(2, "abc")))
+
+		draft, err := s.Get(ctx, &dashboarddraft.GetDraftQuery{ID: first.ID, OrgID: 1, DashboardUID: "abc"})
+		require.NoError(t, err)
+		assert.Equal(t, "Service overview", draft.Data.Get("title").MustString())
+		assert.Equal(t, 3, draft.ParentVersion)
+		assert.Zero(t, draft.Version, "pending drafts have no version")
+
+		_, err = s.Get(ctx, &dashboarddraft.GetDraftQuery{ID: first.ID, OrgID: 1, DashboardUID: "def"})
+		require.ErrorIs(t, err, dashboarddraft.ErrDraftNotFound)
+
+		require.NoError(t, s.Review(ctx, &reviewDraftCommand{
+			ID:            first.ID,
+			OrgID:         1,
+			Status:        dashboarddraft.StatusApproved,
+			Version:       4,
+			ReviewedBy:    3,
+			Reviewed:      now,
+			ReviewComment: "LGTM",
+		}))
+		err = s.Review(ctx, &reviewDraftCommand{ID: first.ID, OrgID: 1, Status: dashboarddraft.StatusRejected, ReviewedBy: 3, Reviewed: now})
+		require.ErrorIs(t, err, dashboarddraft.ErrDraftNotPending)
+
+		drafts, err := s.Search(ctx, &dashboarddraft.SearchDraftsQuery{OrgID: 1, DashboardUID: "abc"})
+		require.NoError(t, err)
+		require.Len(t, drafts, 2)
+		assert.Equal(t, second.ID, drafts[0].ID, "the most recent draft comes first")
+		assert.Nil(t, drafts[0].Data)
+		assert.Equal(t, dashboarddraft.StatusApproved, drafts[1].Status)
+		assert.Equal(t, 4, drafts[1].Version)
+		assert.Equal(t, int64(3), drafts[1].ReviewedBy)
+		assert.Equal(t, "LGTM", drafts[1].ReviewComment)
+
+		drafts, err = s.Search(ctx, &dashboarddraft.SearchDraftsQuery{OrgID: 1, DashboardUID: "abc", Status: dashboarddraft.StatusPending})
+		require.NoError(t, err)
+		require.Len(t, drafts, 1)
+		assert.Equal(t, second.ID, drafts[0].ID)
+	})
+}
@@ -0,0 +1,117 @@
+package dashboarddraft
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrDraftNotFound   = errutil.NotFound("dashboarddraft.not-found", errutil.WithPublicMessage("Dashboard draft not found"))
+	ErrDraftNotPending = errutil.ValidationFailed("dashboarddraft.not-pending", errutil.WithPublicMessage("The draft has already been reviewed"))
+	ErrDraftOutdated   = errutil.ValidationFailed("dashboarddraft.outdated", errutil.WithPublicMessage("The dashboard changed since the draft was created, reject the draft and save the changes again"))
+	ErrOwnDraft        = errutil.Forbidden("dashboarddraft.own-draft", errutil.WithPublicMessage("You cannot approve your own changes"))
+	ErrFolder          = errutil.BadRequest("dashboarddraft.folder", errutil.WithPublicMessage("Approval can only be required for dashboards"))
+	ErrFolderChange    = errutil.BadRequest("dashboarddraft.folder-change", errutil.WithPublicMessage("A dashboard that requires approval cannot be moved to another folder, stop requiring approval to move it"))
+
+	// ErrApprovalRequired is returned by the save paths that cannot create a draft, such as imports.
+	ErrApprovalRequired = dashboards.DashboardErr{
+		Reason:     "The dashboard requires approval, save the changes from the dashboard to create a draft",
+		StatusCode: http.StatusPreconditionFailed,
+		Status:     "approval-required",
+	}
+)
+
+// Status is the review status of a draft.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+func (s Status) IsValid() bool {
+	return s == StatusPending || s == StatusApproved || s == StatusRejected
+}
+
+// Draft is a pending change to a dashboard, or a change that has been reviewed.
+type Draft struct {
+	ID           int64  `xorm:"pk autoincr 'id'" json:"id"`
+	OrgID        int64  `xorm:"org_id" json:"orgId"`
+	DashboardID  int64  `xorm:"dashboard_id" json:"-"`
+	DashboardUID string `xorm:"dashboard_uid" json:"dashboardUid"`
+	FolderID     int64  `xorm:"folder_id" json:"folderId"`
+	// ParentVersion is the version of the dashboard the draft was made from. The draft can only be
+	// approved while it is still the version of the dashboard.
+	ParentVersion int `xorm:"parent_version" json:"parentVersion"`
+	// Version is the version of the dashboard saved when the draft was approved.
+	Version       int              `xorm:"'version'" json:"version,omitempty"`
+	Title         string           `xorm:"title" json:"title"`
+	Data          *simplejson.Json `xorm:"data" json:"dashboard,omitempty"`
+	Message       string           `xorm:"message" json:"message"`
+	Status        Status           `xorm:"status" json:"status"`
+	CreatedBy     int64            `xorm:"created_by" json:"createdBy"`
+	Created       time.Time        `xorm:"created" json:"created"`
+	ReviewedBy    int64            `xorm:"reviewed_by" json:"reviewedBy,omitempty"`
+	Reviewed      *time.Time       `xorm:"reviewed" json:"reviewed,omitempty"`
+	ReviewComment string           `xorm:"review_comment" json:"reviewComment,omitempty"`
+}
+
+func (d Draft) TableName() string {
+	return "dashboard_draft"
+}
+
+// RequiresApprovalQuery matches the dashboard by UID or by ID, as dashboards can be saved with either.
+type RequiresApprovalQuery struct {
+	OrgID        int64
+	DashboardID  int64
+	DashboardUID string
+}
+
+type SetApprovalRequiredCommand struct {
+	OrgID        int64
+	DashboardUID string
+	Required     bool
+	User         *user.SignedInUser
+}
+
+type CreateDraftCommand struct {
+	Dashboard *dashboards.Dashboard
+	Message   string
+	// Overwrite creates the draft even if the dashboard changed since the version it was made from.
+	Overwrite bool
+	User      *user.SignedInUser
+}
+
+type GetDraftQuery struct {
+	ID           int64
+	OrgID        int64
+	DashboardUID string
+}
+
+type SearchDraftsQuery struct {
+	OrgID        int64
+	DashboardUID string
+	Status       Status
+}
+
+type DiffDraftQuery struct {
+	ID           int64
+	OrgID        int64
+	DashboardUID string
+	DiffType     dashdiffs.DiffType
+}
+
+type ReviewDraftCommand struct {
+	ID           int64
+	OrgID        int64
+	DashboardUID string
+	Comment      string
+	User         *user.SignedInUser
+}
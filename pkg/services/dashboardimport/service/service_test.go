@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboarddraft"
 	"github.com/grafana/grafana/pkg/services/dashboardimport"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -156,6 +157,39 @@ func TestImportDashboardService(t *testing.T) {
 		panel := importDashboardArg.Dashboard.Data.Get("panels").GetIndex(0)
 		require.Equal(t, "prom", panel.Get("datasource").MustString())
 	})
+
+	t.Run("When importing a dashboard that requires approval should not save dashboard", func(t *testing.T) {
+		imported := false
+		dashboardService := &dashboardServiceMock{
+			importDashboardFunc: func(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*dashboards.Dashboard, error) {
+				imported = true
+				return nil, nil
+			},
+		}
+		var approvalQuery *dashboarddraft.RequiresApprovalQuery
+		s := &ImportDashboardService{
+			dashboardService:    dashboardService,
+			libraryPanelService: &libraryPanelServiceMock{},
+			folderService:       &foldertest.FakeService{ExpectedFolder: &folder.Folder{ID: 5, UID: "123"}},
+			dashboardDraftService: &dashboardDraftServiceMock{requiresApprovalFunc: func(ctx context.Context, query *dashboarddraft.RequiresApprovalQuery) (bool, error) {
+				approvalQuery = query
+				return true, nil
+			}},
+		}
+
+		loadResp, err := loadTestDashboard(context.Background(), &plugindashboards.LoadPluginDashboardRequest{Reference: "dashboard.json"})
+		require.NoError(t, err)
+
+		_, err = s.ImportDashboard(context.Background(), &dashboardimport.ImportDashboardRequest{
+			Dashboard: loadResp.Dashboard.Data,
+			Overwrite: true,
+			Inputs:    []dashboardimport.ImportDashboardInput{{Name: "*", Type: "datasource", Value: "prom"}},
+			User:      &user.SignedInUser{UserID: 2, OrgRole: org.RoleAdmin, OrgID: 3},
+		})
+		require.ErrorIs(t, err, dashboarddraft.ErrApprovalRequired)
+		require.False(t, imported)
+		require.Equal(t, &dashboarddraft.RequiresApprovalQuery{OrgID: 3, DashboardUID: "UDdpyzz7z"}, approvalQuery)
+	})
 }
 
 func loadTestDashboard(ctx context.Context, req *plugindashboards.LoadPluginDashboardRequest) (*plugindashboards.LoadPluginDashboardResponse, error) {
@@ -223,3 +257,12 @@ func (s *libraryPanelServiceMock) ImportLibraryPanelsForDashboard(ctx context.Co
 
 	return nil
 }
+
+type dashboardDraftServiceMock struct {
+	dashboarddraft.Service
+	requiresApprovalFunc func(ctx context.Context, query *dashboarddraft.RequiresApprovalQuery) (bool, error)
+}
+
+func (m *dashboardDraftServiceMock) RequiresApproval(ctx context.Context, query *dashboarddraft.RequiresApprovalQuery) (bool, error) {
+	return m.requiresApprovalFunc(ctx, query)
+}
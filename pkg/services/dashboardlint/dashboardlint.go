@@ -0,0 +1,93 @@
+package dashboardlint
+
+import (
+	"context"
+	"fmt"
+)
+
+// Service checks dashboards with the lint rules, at the levels configured for their folder.
+type Service interface {
+	Lint(ctx context.Context, query *LintQuery) (*LintResult, error)
+	// RegisterRule adds a rule to the rules of the service, e.g. a rule of a plugin or of Grafana Enterprise.
+	RegisterRule(rule Rule) error
+	Rules() []Rule
+	GetFolderLevels(ctx context.Context, query *GetFolderLevelsQuery) (Levels, error)
+	SetFolderLevels(ctx context.Context, cmd *SetFolderLevelsCommand) error
+}
+
+// Rule checks one aspect of a dashboard. Rules report problems without a level, the level
+// is set by the linter.
+type Rule interface {
+	// ID identifies the rule in the levels of folders and in the problems.
+	ID() string
+	Description() string
+	Check(ctx context.Context, target *Target) ([]Problem, error)
+}
+
+// Linter runs rules on dashboards.
+type Linter struct {
+	rules []Rule
+}
+
+func NewLinter(rules ...Rule) *Linter {
+	return &Linter{rules: rules}
+}
+
+// Add adds a rule to the linter, it fails if another rule has the same ID.
+func (l *Linter) Add(rule Rule) error {
+	for _, r := range l.rules {
+		if r.ID() == rule.ID() {
+			return fmt.Errorf("lint rule %s is already registered", rule.ID())
+		}
+	}
+	l.rules = append(l.rules, rule)
+	return nil
+}
+
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// Lint runs the rules that are not turned off and returns their problems, in the order of the rules.
+func (l *Linter) Lint(ctx context.Context, target *Target, levels Levels) ([]Problem, error) {
+	problems := make([]Problem, 0)
+	for _, rule := range l.rules {
+		level := levels.Get(rule.ID())
+		if level == LevelOff {
+			continue
+		}
+
+		found, err := rule.Check(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("lint rule %s failed: %w", rule.ID(), err)
+		}
+		for _, problem := range found {
+			problem.Rule = rule.ID()
+			problem.Level = level
+			problems = append(problems, problem)
+		}
+	}
+	return problems, nil
+}
+
+// ValidateLevels checks that the levels are known levels of known rules.
+func (l *Linter) ValidateLevels(levels Levels) error {
+	for id, level := range levels {
+		if !level.IsValid() {
+			return ErrInvalidLevels.Errorf("level %q of rule %s must be off, warn or block", level, id)
+		}
+		if !l.hasRule(id) {
+			return ErrInvalidLevels.Errorf("unknown lint rule %s", id)
+		}
+	}
+	return nil
+}
+
+func (l *Linter) hasRule(id string) bool {
+	for _, rule := range l.rules {
+		if rule.ID() == id {
+			return true
+		}
+	}
+	return false
+}
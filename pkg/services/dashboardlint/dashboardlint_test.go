@@ -0,0 +1,55 @@
+package dashboardlint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRule struct {
+	id       string
+	problems []Problem
+	err      error
+}
+
+func (r fakeRule) ID() string          { return r.id }
+func (r fakeRule) Description() string { return r.id }
+func (r fakeRule) Check(context.Context, *Target) ([]Problem, error) {
+	return r.problems, r.err
+}
+
+func TestLinter_Add(t *testing.T) {
+	linter := NewLinter(fakeRule{id: "a"})
+	require.NoError(t, linter.Add(fakeRule{id: "b"}))
+	require.Error(t, linter.Add(fakeRule{id: "a"}))
+	require.Len(t, linter.Rules(), 2)
+}
+
+func TestLinter_Lint(t *testing.T) {
+	linter := NewLinter(
+		fakeRule{id: "a", problems: []Problem{{Message: "a"}}},
+		fakeRule{id: "b", problems: []Problem{{Message: "b"}}},
+		fakeRule{id: "c", problems: []Problem{{Message: "c"}}},
+	)
+
+	problems, err := linter.Lint(context.Background(), &Target{}, Levels{"b": LevelOff, "c": LevelBlock})
+	require.NoError(t, err)
+	require.Equal(t, []Problem{
+		{Rule: "a", Level: LevelWarn, Message: "a"},
+		{Rule: "c", Level: LevelBlock, Message: "c"},
+	}, problems)
+	require.True(t, Blocking(problems))
+
+	linter = NewLinter(fakeRule{id: "a", err: errors.New("boom")})
+	_, err = linter.Lint(context.Background(), &Target{}, nil)
+	require.Error(t, err)
+}
+
+func TestLinter_ValidateLevels(t *testing.T) {
+	linter := NewLinter(fakeRule{id: "a"})
+	require.NoError(t, linter.ValidateLevels(Levels{"a": LevelBlock}))
+	require.ErrorIs(t, linter.ValidateLevels(Levels{"a": "error"}), ErrInvalidLevels)
+	require.ErrorIs(t, linter.ValidateLevels(Levels{"b": LevelWarn}), ErrInvalidLevels)
+}
@@ -0,0 +1,250 @@
+package dashboardlintimpl
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	dashboardScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+	folderScope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+	canEdit := ac.EvalAny(
+		ac.EvalPermission(dashboards.ActionDashboardsCreate),
+		ac.EvalPermission(dashboards.ActionDashboardsWrite),
+	)
+
+	routeRegister.Group("/api/dashboards", func(subrouter routing.RouteRegister) {
+		subrouter.Post("/lint", authorize(canEdit), routing.Wrap(s.handleLint))
+		subrouter.Get("/lint/rules", authorize(canEdit), routing.Wrap(s.handleGetRules))
+		subrouter.Get("/uid/:uid/lint", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead, dashboardScope)), routing.Wrap(s.handleLintDashboard))
+	})
+	routeRegister.Group("/api/folders/:uid", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/lint-rules", authorize(ac.EvalPermission(dashboards.ActionFoldersRead, folderScope)), routing.Wrap(s.handleGetFolderLevels))
+		subrouter.Put("/lint-rules", authorize(ac.EvalPermission(dashboards.ActionFoldersWrite, folderScope)), routing.Wrap(s.handleSetFolderLevels))
+	})
+}
+
+// swagger:route POST /dashboards/lint dashboard_lint lintDashboard
+//
+// Lint a dashboard.
+//
+// Checks the dashboard with the lint rules, at the levels configured for the folder the dashboard would be saved in.
+//
+// Responses:
+// 200: lintDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleLint(c *contextmodel.ReqContext) response.Response {
+	body := LintDashboardBody{}
+	if err := web.Bind(c.Req, &body); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if body.Dashboard == nil {
+		return response.Error(http.StatusBadRequest, "dashboard is required", nil)
+	}
+
+	result, err := s.Lint(c.Req.Context(), &dashboardlint.LintQuery{
+		OrgID:     c.SignedInUser.OrgID,
+		FolderUID: body.FolderUID,
+		Dashboard: body.Dashboard,
+		User:      c.SignedInUser,
+	})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /dashboards/uid/{uid}/lint dashboard_lint lintSavedDashboard
+//
+// Lint a saved dashboard.
+//
+// Checks the dashboard with the lint rules, at the levels configured for its folder.
+//
+// Responses:
+// 200: lintDashboardResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleLintDashboard(c *contextmodel.ReqContext) response.Response {
+	dashboard, err := s.dashboardService.GetDashboard(c.Req.Context(), &dashboards.GetDashboardQuery{
+		UID:   web.Params(c.Req)[":uid"],
+		OrgID: c.SignedInUser.OrgID,
+	})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	if dashboard.IsFolder {
+		return response.Error(http.StatusNotFound, dashboards.ErrDashboardNotFound.Error(), nil)
+	}
+
+	g, err := guardian.NewByDashboard(c.Req.Context(), dashboard, c.SignedInUser.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check dashboard permissions", err)
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return response.Error(http.StatusForbidden, "Access denied to this dashboard", err)
+	}
+
+	result, err := s.Lint(c.Req.Context(), &dashboardlint.LintQuery{
+		OrgID:     dashboard.OrgID,
+		FolderID:  dashboard.FolderID,
+		Dashboard: dashboard.Data,
+		User:      c.SignedInUser,
+	})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /dashboards/lint/rules dashboard_lint getDashboardLintRules
+//
+// Get the lint rules.
+//
+// Responses:
+// 200: getDashboardLintRulesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (s *Service) handleGetRules(c *contextmodel.ReqContext) response.Response {
+	rules := s.Rules()
+	result := make([]RuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, RuleDTO{ID: rule.ID(), Description: rule.Description()})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /folders/{folder_uid}/lint-rules dashboard_lint getFolderLintRules
+//
+// Get the levels of the lint rules for the dashboards of a folder.
+//
+// Rules without a level warn. Use the UID `general` for the dashboards that are not in a folder.
+//
+// Responses:
+// 200: folderLintRulesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleGetFolderLevels(c *contextmodel.ReqContext) response.Response {
+	levels, err := s.GetFolderLevels(c.Req.Context(), &dashboardlint.GetFolderLevelsQuery{
+		OrgID:     c.SignedInUser.OrgID,
+		FolderUID: web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the lint rules of the folder", err)
+	}
+	return response.JSON(http.StatusOK, levels)
+}
+
+// swagger:route PUT /folders/{folder_uid}/lint-rules dashboard_lint setFolderLintRules
+//
+// Set the levels of the lint rules for the dashboards of a folder.
+//
+// Replaces the levels of the folder. Each level is `off`, `warn` or `block`, rules with the level `block` prevent
+// saving dashboards with problems in the folder.
+//
+// Responses:
+// 200: folderLintRulesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleSetFolderLevels(c *contextmodel.ReqContext) response.Response {
+	levels := dashboardlint.Levels{}
+	if err := web.Bind(c.Req, &levels); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	err := s.SetFolderLevels(c.Req.Context(), &dashboardlint.SetFolderLevelsCommand{
+		OrgID:     c.SignedInUser.OrgID,
+		FolderUID: web.Params(c.Req)[":uid"],
+		Levels:    levels,
+		User:      c.SignedInUser,
+	})
+	if err != nil {
+		if errors.Is(err, dashboardlint.ErrInvalidLevels) {
+			return response.Err(err)
+		}
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, levels)
+}
+
+// LintDashboardBody is the dashboard to lint, and the folder it would be saved in.
+type LintDashboardBody struct {
+	Dashboard *simplejson.Json `json:"dashboard"`
+	// FolderUID is the UID of the folder, empty for the General folder.
+	FolderUID string `json:"folderUid"`
+}
+
+type RuleDTO struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// swagger:parameters lintDashboard
+type LintDashboardParams struct {
+	// in:body
+	// required:true
+	Body LintDashboardBody `json:"body"`
+}
+
+// swagger:parameters lintSavedDashboard
+type LintSavedDashboardParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters getFolderLintRules
+type GetFolderLintRulesParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:parameters setFolderLintRules
+type SetFolderLintRulesParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// in:body
+	// required:true
+	Body dashboardlint.Levels `json:"body"`
+}
+
+// swagger:response lintDashboardResponse
+type LintDashboardResponse struct {
+	// in: body
+	Body *dashboardlint.LintResult `json:"body"`
+}
+
+// swagger:response getDashboardLintRulesResponse
+type GetDashboardLintRulesResponse struct {
+	// in: body
+	Body []RuleDTO `json:"body"`
+}
+
+// swagger:response folderLintRulesResponse
+type FolderLintRulesResponse struct {
+	// in: body
+	Body dashboardlint.Levels `json:"body"`
+}
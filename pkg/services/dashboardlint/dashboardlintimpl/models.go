@@ -0,0 +1,19 @@
+package dashboardlintimpl
+
+import (
+	"time"
+)
+
+// folderLevels are the levels of the rules for the dashboards of a folder.
+type folderLevels struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	OrgID     int64     `xorm:"org_id"`
+	FolderUID string    `xorm:"folder_uid"`
+	Levels    string    `xorm:"levels"`
+	Updated   time.Time `xorm:"updated"`
+	UpdatedBy int64     `xorm:"updated_by"`
+}
+
+func (folderLevels) TableName() string {
+	return "dashboard_lint_rules"
+}
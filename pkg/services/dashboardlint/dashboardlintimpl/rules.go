@@ -0,0 +1,134 @@
+package dashboardlintimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+)
+
+// datasourceExistsRule reports the panels and queries using data sources that do not exist,
+// e.g. because they were deleted or the dashboard was imported from another instance.
+type datasourceExistsRule struct {
+	datasourceService datasources.DataSourceService
+}
+
+func (datasourceExistsRule) ID() string {
+	return "datasource-exists"
+}
+
+func (datasourceExistsRule) Description() string {
+	return "Panels and queries use data sources that exist."
+}
+
+func (r datasourceExistsRule) Check(ctx context.Context, target *dashboardlint.Target) ([]dashboardlint.Problem, error) {
+	exists := make(map[string]bool)
+	check := func(ref dashboardlint.DatasourceRef) (bool, error) {
+		if found, ok := exists[ref.UID]; ok {
+			return found, nil
+		}
+
+		// old dashboards reference data sources by name
+		_, err := r.datasourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: ref.UID, OrgID: target.OrgID})
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			_, err = r.datasourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{Name: ref.UID, OrgID: target.OrgID})
+		}
+		if err != nil && !errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return false, err
+		}
+		exists[ref.UID] = err == nil
+		return err == nil, nil
+	}
+
+	var problems []dashboardlint.Problem
+	for _, panel := range dashboards.Panels(target.Dashboard) {
+		if ref, ok := dashboardlint.GetDatasourceRef(panel); ok {
+			found, err := check(ref)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				problems = append(problems, dashboardlint.NewPanelProblem(panel, fmt.Sprintf("Data source %s does not exist", ref.UID)))
+			}
+		}
+		for _, query := range dashboardlint.Targets(panel) {
+			ref, ok := dashboardlint.GetDatasourceRef(query)
+			if !ok {
+				continue
+			}
+			found, err := check(ref)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				problems = append(problems, dashboardlint.NewQueryProblem(panel, query, fmt.Sprintf("Data source %s does not exist", ref.UID)))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// libraryPanelSyncRule reports the library panels of a dashboard that no longer match their library element.
+type libraryPanelSyncRule struct {
+	libraryElementService libraryelements.Service
+}
+
+func (libraryPanelSyncRule) ID() string {
+	return "library-panel-sync"
+}
+
+func (libraryPanelSyncRule) Description() string {
+	return "Library panels exist and are in sync with the library panel they use."
+}
+
+func (r libraryPanelSyncRule) Check(ctx context.Context, target *dashboardlint.Target) ([]dashboardlint.Problem, error) {
+	// library panels are checked with the permissions of the user, which offline linting has not
+	if target.User == nil {
+		return nil, nil
+	}
+
+	var problems []dashboardlint.Problem
+	for _, panel := range dashboards.Panels(target.Dashboard) {
+		libraryPanel, ok := panel.CheckGet("libraryPanel")
+		if !ok {
+			continue
+		}
+		uid := libraryPanel.Get("uid").MustString()
+		if uid == "" {
+			continue
+		}
+
+		element, err := r.libraryElementService.GetElement(ctx, target.User, model.GetLibraryElementCommand{UID: uid})
+		if errors.Is(err, model.ErrLibraryElementNotFound) {
+			problems = append(problems, dashboardlint.NewPanelProblem(panel, fmt.Sprintf("Library panel %s does not exist", uid)))
+			continue
+		}
+		if errors.Is(err, dashboards.ErrFolderAccessDenied) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if message := libraryPanelDiff(panel, libraryPanel, element); message != "" {
+			problems = append(problems, dashboardlint.NewPanelProblem(panel, message))
+		}
+	}
+	return problems, nil
+}
+
+func libraryPanelDiff(panel, libraryPanel *simplejson.Json, element model.LibraryElementDTO) string {
+	if name := libraryPanel.Get("name").MustString(); name != "" && name != element.Name {
+		return fmt.Sprintf("Library panel %s is named %q, not %q", element.UID, element.Name, name)
+	}
+	if panelType := panel.Get("type").MustString(); panelType != "" && element.Type != "" && panelType != element.Type {
+		return fmt.Sprintf("Panel is a %s panel but library panel %s is a %s panel", panelType, element.UID, element.Type)
+	}
+	return ""
+}
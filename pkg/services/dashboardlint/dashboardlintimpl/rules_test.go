@@ -0,0 +1,94 @@
+package dashboardlintimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeLibraryElementService struct {
+	libraryelements.Service
+	elements map[string]model.LibraryElementDTO
+}
+
+func (s *fakeLibraryElementService) GetElement(_ context.Context, _ *user.SignedInUser, cmd model.GetLibraryElementCommand) (model.LibraryElementDTO, error) {
+	element, ok := s.elements[cmd.UID]
+	if !ok {
+		return model.LibraryElementDTO{}, model.ErrLibraryElementNotFound
+	}
+	return element, nil
+}
+
+func newTarget(t *testing.T, data string, usr *user.SignedInUser) *dashboardlint.Target {
+	t.Helper()
+	dashboard, err := simplejson.NewJson([]byte(data))
+	require.NoError(t, err)
+	return &dashboardlint.Target{OrgID: 1, User: usr, Dashboard: dashboard}
+}
+
+func TestDatasourceExistsRule(t *testing.T) {
+	rule := datasourceExistsRule{datasourceService: &fakeDatasources.FakeDataSourceService{
+		DataSources: []*datasources.DataSource{
+			{UID: "prom", Name: "Prometheus", Type: "prometheus", OrgID: 1},
+		},
+	}}
+
+	problems, err := rule.Check(context.Background(), newTarget(t, `{
+		"panels": [
+			{"id": 1, "datasource": {"uid": "prom", "type": "prometheus"}, "targets": [
+				{"refId": "A"},
+				{"refId": "B", "datasource": {"uid": "deleted", "type": "prometheus"}}
+			]},
+			{"id": 2, "datasource": "Prometheus"},
+			{"id": 3, "datasource": "Graphite"},
+			{"id": 4, "datasource": {"uid": "${ds}"}}
+		]
+	}`, nil))
+	require.NoError(t, err)
+	require.Equal(t, []dashboardlint.Problem{
+		{PanelID: 1, RefID: "B", Message: "Data source deleted does not exist"},
+		{PanelID: 3, Message: "Data source Graphite does not exist"},
+	}, problems)
+}
+
+func TestLibraryPanelSyncRule(t *testing.T) {
+	rule := libraryPanelSyncRule{libraryElementService: &fakeLibraryElementService{
+		elements: map[string]model.LibraryElementDTO{
+			"requests": {UID: "requests", Name: "Requests", Type: "timeseries"},
+		},
+	}}
+	data := `{
+		"panels": [
+			{"id": 1, "type": "timeseries", "libraryPanel": {"uid": "requests", "name": "Requests"}},
+			{"id": 2, "libraryPanel": {"uid": "requests", "name": "Old requests"}},
+			{"id": 3, "type": "stat", "libraryPanel": {"uid": "requests"}},
+			{"id": 4, "libraryPanel": {"uid": "deleted", "name": "Deleted"}},
+			{"id": 5, "type": "stat"}
+		]
+	}`
+
+	t.Run("offline", func(t *testing.T) {
+		problems, err := rule.Check(context.Background(), newTarget(t, data, nil))
+		require.NoError(t, err)
+		require.Empty(t, problems)
+	})
+
+	t.Run("signed in user", func(t *testing.T) {
+		problems, err := rule.Check(context.Background(), newTarget(t, data, &user.SignedInUser{OrgID: 1}))
+		require.NoError(t, err)
+		require.Equal(t, []dashboardlint.Problem{
+			{PanelID: 2, Message: `Library panel requests is named "Requests", not "Old requests"`},
+			{PanelID: 3, Message: "Panel is a stat panel but library panel requests is a timeseries panel"},
+			{PanelID: 4, Message: "Library panel deleted does not exist"},
+		}, problems)
+	})
+}
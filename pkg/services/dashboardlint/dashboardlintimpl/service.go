@@ -0,0 +1,131 @@
+package dashboardlintimpl
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+)
+
+var _ dashboardlint.Service = (*Service)(nil)
+
+type Service struct {
+	store            store
+	dashboardService dashboards.DashboardService
+	folderService    folder.Service
+	accessControl    ac.AccessControl
+	log              log.Logger
+
+	mu     sync.RWMutex
+	linter *dashboardlint.Linter
+}
+
+func ProvideService(
+	sql db.DB,
+	dashboardService dashboards.DashboardService,
+	folderService folder.Service,
+	datasourceService datasources.DataSourceService,
+	libraryElementService libraryelements.Service,
+	accessControl ac.AccessControl,
+	routeRegister routing.RouteRegister,
+) *Service {
+	rules := append(dashboardlint.OfflineRules(),
+		datasourceExistsRule{datasourceService: datasourceService},
+		libraryPanelSyncRule{libraryElementService: libraryElementService},
+	)
+
+	s := &Service{
+		store:            &xormStore{db: sql},
+		dashboardService: dashboardService,
+		folderService:    folderService,
+		accessControl:    accessControl,
+		log:              log.New("dashboard.lint"),
+		linter:           dashboardlint.NewLinter(rules...),
+	}
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+func (s *Service) RegisterRule(rule dashboardlint.Rule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.linter.Add(rule)
+}
+
+func (s *Service) Rules() []dashboardlint.Rule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]dashboardlint.Rule{}, s.linter.Rules()...)
+}
+
+// Lint checks a dashboard with the levels of its folder.
+func (s *Service) Lint(ctx context.Context, query *dashboardlint.LintQuery) (*dashboardlint.LintResult, error) {
+	folderUID := query.FolderUID
+	if folderUID == "" && query.FolderID != 0 {
+		f, err := s.folderService.Get(ctx, &folder.GetFolderQuery{ID: &query.FolderID, OrgID: query.OrgID, SignedInUser: query.User})
+		if err != nil {
+			return nil, err
+		}
+		folderUID = f.UID
+	}
+
+	levels, err := s.GetFolderLevels(ctx, &dashboardlint.GetFolderLevelsQuery{OrgID: query.OrgID, FolderUID: folderUID})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	linter := dashboardlint.NewLinter(s.linter.Rules()...)
+	s.mu.RUnlock()
+
+	problems, err := linter.Lint(ctx, &dashboardlint.Target{
+		OrgID:     query.OrgID,
+		User:      query.User,
+		Dashboard: query.Dashboard,
+	}, levels)
+	if err != nil {
+		return nil, err
+	}
+	return &dashboardlint.LintResult{Problems: problems, Blocked: dashboardlint.Blocking(problems)}, nil
+}
+
+// GetFolderLevels returns the levels of the rules for the dashboards of a folder. The levels
+// of the General folder are stored with its UID, general.
+func (s *Service) GetFolderLevels(ctx context.Context, query *dashboardlint.GetFolderLevelsQuery) (dashboardlint.Levels, error) {
+	return s.store.GetLevels(ctx, query.OrgID, folderUID(query.FolderUID))
+}
+
+func (s *Service) SetFolderLevels(ctx context.Context, cmd *dashboardlint.SetFolderLevelsCommand) error {
+	s.mu.RLock()
+	err := s.linter.ValidateLevels(cmd.Levels)
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	cmd.FolderUID = folderUID(cmd.FolderUID)
+	if cmd.FolderUID != ac.GeneralFolderUID {
+		if _, err := s.folderService.Get(ctx, &folder.GetFolderQuery{UID: &cmd.FolderUID, OrgID: cmd.OrgID, SignedInUser: cmd.User}); err != nil {
+			return err
+		}
+	}
+	if cmd.Levels == nil {
+		cmd.Levels = dashboardlint.Levels{}
+	}
+	return s.store.SetLevels(ctx, cmd)
+}
+
+func folderUID(uid string) string {
+	if uid == "" {
+		return ac.GeneralFolderUID
+	}
+	return uid
+}
@@ -0,0 +1,120 @@
+package dashboardlintimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationDashboardLintService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	folderService := foldertest.NewFakeService()
+	s := &Service{
+		store:         &xormStore{db: db.InitTestDB(t)},
+		folderService: folderService,
+		log:           log.NewNopLogger(),
+		linter:        dashboardlint.NewLinter(dashboardlint.OfflineRules()...),
+	}
+	usr := &user.SignedInUser{UserID: 1, OrgID: 1}
+	dashboard := simplejson.NewFromAny(map[string]interface{}{
+		"title": "Requests",
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "type": "timeseries", "description": "Requests per second"},
+			map[string]interface{}{"id": 1, "type": "timeseries", "description": "Errors per second"},
+		},
+	})
+
+	t.Run("rules warn by default", func(t *testing.T) {
+		result, err := s.Lint(ctx, &dashboardlint.LintQuery{OrgID: 1, Dashboard: dashboard, User: usr})
+		require.NoError(t, err)
+		assert.False(t, result.Blocked)
+		assert.Equal(t, []dashboardlint.Problem{
+			{Rule: "duplicate-panel-id", Level: dashboardlint.LevelWarn, PanelID: 1, Message: "Panel ID 1 is used by several panels"},
+			{Rule: "missing-description", Level: dashboardlint.LevelWarn, Message: "Dashboard has no description"},
+		}, result.Problems)
+	})
+
+	t.Run("levels of the General folder", func(t *testing.T) {
+		err := s.SetFolderLevels(ctx, &dashboardlint.SetFolderLevelsCommand{
+			OrgID:  1,
+			Levels: dashboardlint.Levels{"duplicate-panel-id": dashboardlint.LevelBlock, "missing-description": dashboardlint.LevelOff},
+			User:   usr,
+		})
+		require.NoError(t, err)
+
+		levels, err := s.GetFolderLevels(ctx, &dashboardlint.GetFolderLevelsQuery{OrgID: 1, FolderUID: "general"})
+		require.NoError(t, err)
+		assert.Equal(t, dashboardlint.Levels{"duplicate-panel-id": dashboardlint.LevelBlock, "missing-description": dashboardlint.LevelOff}, levels)
+
+		result, err := s.Lint(ctx, &dashboardlint.LintQuery{OrgID: 1, Dashboard: dashboard, User: usr})
+		require.NoError(t, err)
+		assert.True(t, result.Blocked)
+		require.Len(t, result.Problems, 1)
+		assert.Equal(t, dashboardlint.LevelBlock, result.Problems[0].Level)
+	})
+
+	t.Run("levels of a folder", func(t *testing.T) {
+		folderService.ExpectedFolder = &folder.Folder{ID: 3, UID: "team", OrgID: 1}
+		err := s.SetFolderLevels(ctx, &dashboardlint.SetFolderLevelsCommand{
+			OrgID:     1,
+			FolderUID: "team",
+			Levels:    dashboardlint.Levels{"missing-description": dashboardlint.LevelBlock},
+			User:      usr,
+		})
+		require.NoError(t, err)
+
+		err = s.SetFolderLevels(ctx, &dashboardlint.SetFolderLevelsCommand{
+			OrgID:     1,
+			FolderUID: "team",
+			Levels:    dashboardlint.Levels{"missing-description": dashboardlint.LevelBlock, "duplicate-panel-id": dashboardlint.LevelOff},
+			User:      usr,
+		})
+		require.NoError(t, err, "levels can be replaced")
+
+		result, err := s.Lint(ctx, &dashboardlint.LintQuery{OrgID: 1, FolderID: 3, Dashboard: dashboard, User: usr})
+		require.NoError(t, err)
+		assert.True(t, result.Blocked)
+		assert.Equal(t, []dashboardlint.Problem{
+			{Rule: "missing-description", Level: dashboardlint.LevelBlock, Message: "Dashboard has no description"},
+		}, result.Problems)
+
+		levels, err := s.GetFolderLevels(ctx, &dashboardlint.GetFolderLevelsQuery{OrgID: 2, FolderUID: "team"})
+		require.NoError(t, err)
+		assert.Empty(t, levels, "levels are per organization")
+	})
+
+	t.Run("invalid levels", func(t *testing.T) {
+		err := s.SetFolderLevels(ctx, &dashboardlint.SetFolderLevelsCommand{OrgID: 1, Levels: dashboardlint.Levels{"unknown": dashboardlint.LevelBlock}, User: usr})
+		require.ErrorIs(t, err, dashboardlint.ErrInvalidLevels)
+		err = s.SetFolderLevels(ctx, &dashboardlint.SetFolderLevelsCommand{OrgID: 1, Levels: dashboardlint.Levels{"missing-description": "error"}, User: usr})
+		require.ErrorIs(t, err, dashboardlint.ErrInvalidLevels)
+	})
+
+	t.Run("folder does not exist", func(t *testing.T) {
+		folderService.ExpectedError = dashboards.ErrFolderNotFound
+		err := s.SetFolderLevels(ctx, &dashboardlint.SetFolderLevelsCommand{OrgID: 1, FolderUID: "missing", User: usr})
+		require.ErrorIs(t, err, dashboards.ErrFolderNotFound)
+	})
+}
+
+func TestService_RegisterRule(t *testing.T) {
+	s := &Service{linter: dashboardlint.NewLinter(dashboardlint.OfflineRules()...)}
+	require.NoError(t, s.RegisterRule(datasourceExistsRule{}))
+	require.Error(t, s.RegisterRule(datasourceExistsRule{}))
+	require.Len(t, s.Rules(), len(dashboardlint.OfflineRules())+1)
+}
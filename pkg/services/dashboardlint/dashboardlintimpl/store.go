@@ -0,0 +1,62 @@
+package dashboardlintimpl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+)
+
+type store interface {
+	// GetLevels returns the levels of the rules for a folder, which are empty when they were never set.
+	GetLevels(ctx context.Context, orgID int64, folderUID string) (dashboardlint.Levels, error)
+	SetLevels(ctx context.Context, cmd *dashboardlint.SetFolderLevelsCommand) error
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (xs *xormStore) GetLevels(ctx context.Context, orgID int64, folderUID string) (dashboardlint.Levels, error) {
+	levels := dashboardlint.Levels{}
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var record folderLevels
+		exists, err := sess.Where("org_id = ? AND folder_uid = ?", orgID, folderUID).Get(&record)
+		if err != nil || !exists {
+			return err
+		}
+		return json.Unmarshal([]byte(record.Levels), &levels)
+	})
+	return levels, err
+}
+
+func (xs *xormStore) SetLevels(ctx context.Context, cmd *dashboardlint.SetFolderLevelsCommand) error {
+	data, err := json.Marshal(cmd.Levels)
+	if err != nil {
+		return err
+	}
+
+	return xs.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		record := folderLevels{
+			OrgID:     cmd.OrgID,
+			FolderUID: cmd.FolderUID,
+			Levels:    string(data),
+			Updated:   time.Now(),
+			UpdatedBy: cmd.User.UserID,
+		}
+
+		exists, err := sess.Where("org_id = ? AND folder_uid = ?", cmd.OrgID, cmd.FolderUID).Exist(&folderLevels{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			_, err = sess.Where("org_id = ? AND folder_uid = ?", cmd.OrgID, cmd.FolderUID).
+				Cols("levels", "updated", "updated_by").Update(&record)
+			return err
+		}
+		_, err = sess.Insert(&record)
+		return err
+	})
+}
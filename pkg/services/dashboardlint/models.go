@@ -0,0 +1,94 @@
+package dashboardlint
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrInvalidLevels = errutil.BadRequest("dashboardlint.invalid-levels")
+	ErrBlocked       = errutil.ValidationFailed("dashboardlint.blocked", errutil.WithPublicMessage("The dashboard has lint problems that block saving it"))
+)
+
+// Level is how the problems found by a rule are handled.
+type Level string
+
+const (
+	// LevelOff turns the rule off.
+	LevelOff Level = "off"
+	// LevelWarn reports the problems, the dashboard is still saved.
+	LevelWarn Level = "warn"
+	// LevelBlock prevents saving dashboards with problems.
+	LevelBlock Level = "block"
+)
+
+func (l Level) IsValid() bool {
+	return l == LevelOff || l == LevelWarn || l == LevelBlock
+}
+
+// Levels are the levels of rules, by rule ID. The rules without a level warn.
+type Levels map[string]Level
+
+func (l Levels) Get(rule string) Level {
+	if level, ok := l[rule]; ok {
+		return level
+	}
+	return LevelWarn
+}
+
+// Problem is a problem found by a rule in a dashboard, or in one of its panels or queries.
+type Problem struct {
+	Rule       string `json:"rule"`
+	Level      Level  `json:"level"`
+	PanelID    int64  `json:"panelId,omitempty"`
+	PanelTitle string `json:"panelTitle,omitempty"`
+	RefID      string `json:"refId,omitempty"`
+	Message    string `json:"message"`
+}
+
+// Blocking returns whether some of the problems block saving the dashboard.
+func Blocking(problems []Problem) bool {
+	for _, problem := range problems {
+		if problem.Level == LevelBlock {
+			return true
+		}
+	}
+	return false
+}
+
+// Target is the dashboard checked by the rules.
+type Target struct {
+	OrgID int64
+	// User is nil when linting files offline, rules that need the user skip their checks.
+	User      *user.SignedInUser
+	Dashboard *simplejson.Json
+}
+
+// LintQuery checks a dashboard with the levels of a folder. The folder is identified
+// by its UID, or by its ID when the UID is empty. Dashboards of the General folder have no folder.
+type LintQuery struct {
+	OrgID     int64
+	FolderID  int64
+	FolderUID string
+	Dashboard *simplejson.Json
+	User      *user.SignedInUser
+}
+
+type LintResult struct {
+	Problems []Problem `json:"problems"`
+	// Blocked is true when some problems block saving the dashboard.
+	Blocked bool `json:"blocked"`
+}
+
+type GetFolderLevelsQuery struct {
+	OrgID     int64
+	FolderUID string
+}
+
+type SetFolderLevelsCommand struct {
+	OrgID     int64
+	FolderUID string
+	Levels    Levels
+	User      *user.SignedInUser
+}
@@ -0,0 +1,71 @@
+package dashboardlint
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// Targets returns the queries of a panel.
+func Targets(panel *simplejson.Json) []*simplejson.Json {
+	var targets []*simplejson.Json
+	for _, t := range panel.Get("targets").MustArray() {
+		targets = append(targets, simplejson.NewFromAny(t))
+	}
+	return targets
+}
+
+// DatasourceRef is a reference to a data source in a panel or a query.
+type DatasourceRef struct {
+	// UID is the UID of the data source, or its name in dashboards that reference data sources by name.
+	UID  string
+	Type string
+}
+
+// GetDatasourceRef returns the data source of a panel or a query. It returns false when it has none,
+// when it uses the data source of its panel, or when it uses a variable or a data source built into Grafana.
+func GetDatasourceRef(j *simplejson.Json) (DatasourceRef, bool) {
+	var ref DatasourceRef
+	ds := j.Get("datasource")
+	if name, err := ds.String(); err == nil {
+		ref.UID = name
+	} else {
+		ref.UID = ds.Get("uid").MustString()
+		ref.Type = ds.Get("type").MustString()
+	}
+
+	if ref.UID == "" || IsVariable(ref.UID) || isBuiltInDatasource(ref) {
+		return ref, false
+	}
+	return ref, true
+}
+
+// IsVariable returns whether the value is a template variable, such as $ds, ${ds} or [[ds]].
+func IsVariable(value string) bool {
+	return strings.HasPrefix(value, "$") || strings.HasPrefix(value, "[[")
+}
+
+func isBuiltInDatasource(ref DatasourceRef) bool {
+	switch ref.UID {
+	// mixed, dashboard, Grafana and expressions
+	case "-- Mixed --", "-- Dashboard --", "-- Grafana --", "grafana", "__expr__", "-100":
+		return true
+	}
+	return ref.Type == "__expr__" || ref.Type == "datasource"
+}
+
+// NewPanelProblem returns a problem of a panel.
+func NewPanelProblem(panel *simplejson.Json, message string) Problem {
+	return Problem{
+		PanelID:    panel.Get("id").MustInt64(),
+		PanelTitle: panel.Get("title").MustString(),
+		Message:    message,
+	}
+}
+
+// NewQueryProblem returns a problem of a query of a panel.
+func NewQueryProblem(panel, target *simplejson.Json, message string) Problem {
+	problem := NewPanelProblem(panel, message)
+	problem.RefID = target.Get("refId").MustString()
+	return problem
+}
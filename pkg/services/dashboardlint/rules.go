@@ -0,0 +1,173 @@
+package dashboardlint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// OfflineRules returns the rules that only need the JSON model of the dashboard.
+func OfflineRules() []Rule {
+	return []Rule{
+		duplicatePanelIDRule{},
+		rateIntervalRule{},
+		hardcodedDatasourceRule{},
+		missingDescriptionRule{},
+	}
+}
+
+type duplicatePanelIDRule struct{}
+
+func (duplicatePanelIDRule) ID() string {
+	return "duplicate-panel-id"
+}
+
+func (duplicatePanelIDRule) Description() string {
+	return "Panels have unique IDs, links to a panel and its queries depend on them."
+}
+
+func (duplicatePanelIDRule) Check(_ context.Context, target *Target) ([]Problem, error) {
+	var problems []Problem
+	seen := make(map[int64]bool)
+	for _, panel := range dashboards.Panels(target.Dashboard) {
+		id, err := panel.Get("id").Int64()
+		if err != nil {
+			continue
+		}
+		if seen[id] {
+			problems = append(problems, NewPanelProblem(panel, fmt.Sprintf("Panel ID %d is used by several panels", id)))
+		}
+		seen[id] = true
+	}
+	return problems, nil
+}
+
+var (
+	rateFunctionPattern = regexp.MustCompile(`\b(rate|irate|increase)\s*\(`)
+	rangePattern        = regexp.MustCompile(`\[([^\]:]+)(?::[^\]]*)?\]`)
+)
+
+type rateIntervalRule struct{}
+
+func (rateIntervalRule) ID() string {
+	return "rate-interval"
+}
+
+func (rateIntervalRule) Description() string {
+	return "Prometheus queries use $__rate_interval as the range of rate, irate and increase, which is never too short for the scrape interval."
+}
+
+func (rateIntervalRule) Check(_ context.Context, target *Target) ([]Problem, error) {
+	var problems []Problem
+	for _, panel := range dashboards.Panels(target.Dashboard) {
+		panelRef, _ := GetDatasourceRef(panel)
+		for _, query := range Targets(panel) {
+			ref, ok := GetDatasourceRef(query)
+			if !ok {
+				ref = panelRef
+			}
+			if ref.Type != "" && ref.Type != "prometheus" {
+				continue
+			}
+
+			expr := query.Get("expr").MustString()
+			if !rateFunctionPattern.MatchString(expr) {
+				continue
+			}
+			for _, match := range rangePattern.FindAllStringSubmatch(expr, -1) {
+				if r := strings.TrimSpace(match[1]); r != "$__rate_interval" {
+					problems = append(problems, NewQueryProblem(panel, query, fmt.Sprintf("Query uses the range [%s] instead of [$__rate_interval]", r)))
+					break
+				}
+			}
+		}
+	}
+	return problems, nil
+}
+
+type hardcodedDatasourceRule struct{}
+
+func (hardcodedDatasourceRule) ID() string {
+	return "hardcoded-datasource"
+}
+
+func (hardcodedDatasourceRule) Description() string {
+	return "Panels and queries use the data source variable of the dashboard rather than a fixed data source of the same type."
+}
+
+func (hardcodedDatasourceRule) Check(_ context.Context, target *Target) ([]Problem, error) {
+	// the data source variables of the dashboard, by data source type
+	variables := make(map[string]string)
+	for _, v := range target.Dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(v)
+		if variable.Get("type").MustString() == "datasource" {
+			variables[variable.Get("query").MustString()] = variable.Get("name").MustString()
+		}
+	}
+	if len(variables) == 0 {
+		return nil, nil
+	}
+
+	variableFor := func(ref DatasourceRef) (string, bool) {
+		if ref.Type != "" {
+			name, ok := variables[ref.Type]
+			return name, ok
+		}
+		// data sources referenced by name have no type, any variable could replace them
+		names := make([]string, 0, len(variables))
+		for _, name := range variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ", $"), true
+	}
+
+	var problems []Problem
+	for _, panel := range dashboards.Panels(target.Dashboard) {
+		if ref, ok := GetDatasourceRef(panel); ok {
+			if name, ok := variableFor(ref); ok {
+				problems = append(problems, NewPanelProblem(panel, fmt.Sprintf("Panel uses the data source %s instead of the variable $%s", ref.UID, name)))
+			}
+		}
+		for _, query := range Targets(panel) {
+			if ref, ok := GetDatasourceRef(query); ok {
+				if name, ok := variableFor(ref); ok {
+					problems = append(problems, NewQueryProblem(panel, query, fmt.Sprintf("Query uses the data source %s instead of the variable $%s", ref.UID, name)))
+				}
+			}
+		}
+	}
+	return problems, nil
+}
+
+type missingDescriptionRule struct{}
+
+func (missingDescriptionRule) ID() string {
+	return "missing-description"
+}
+
+func (missingDescriptionRule) Description() string {
+	return "The dashboard and its panels have a description."
+}
+
+func (missingDescriptionRule) Check(_ context.Context, target *Target) ([]Problem, error) {
+	var problems []Problem
+	if strings.TrimSpace(target.Dashboard.Get("description").MustString()) == "" {
+		problems = append(problems, Problem{Message: "Dashboard has no description"})
+	}
+	for _, panel := range dashboards.Panels(target.Dashboard) {
+		// the description of library panels is part of the library panel
+		if _, ok := panel.CheckGet("libraryPanel"); ok {
+			continue
+		}
+		if strings.TrimSpace(panel.Get("description").MustString()) == "" {
+			problems = append(problems, NewPanelProblem(panel, "Panel has no description"))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,121 @@
+package dashboardlint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func dashboardFromJSON(t *testing.T, data string) *simplejson.Json {
+	t.Helper()
+	dashboard, err := simplejson.NewJson([]byte(data))
+	require.NoError(t, err)
+	return dashboard
+}
+
+func check(t *testing.T, rule Rule, data string) []Problem {
+	t.Helper()
+	problems, err := rule.Check(context.Background(), &Target{OrgID: 1, Dashboard: dashboardFromJSON(t, data)})
+	require.NoError(t, err)
+	return problems
+}
+
+func TestGetDatasourceRef(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		json     string
+		expected DatasourceRef
+		ok       bool
+	}{
+		{desc: "reference", json: `{"datasource": {"uid": "abc", "type": "prometheus"}}`, expected: DatasourceRef{UID: "abc", Type: "prometheus"}, ok: true},
+		{desc: "name", json: `{"datasource": "Prometheus"}`, expected: DatasourceRef{UID: "Prometheus"}, ok: true},
+		{desc: "none", json: `{}`},
+		{desc: "variable", json: `{"datasource": {"uid": "${ds}", "type": "prometheus"}}`, expected: DatasourceRef{UID: "${ds}", Type: "prometheus"}},
+		{desc: "mixed", json: `{"datasource": "-- Mixed --"}`, expected: DatasourceRef{UID: "-- Mixed --"}},
+		{desc: "expression", json: `{"datasource": {"uid": "__expr__", "type": "__expr__"}}`, expected: DatasourceRef{UID: "__expr__", Type: "__expr__"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ref, ok := GetDatasourceRef(dashboardFromJSON(t, tc.json))
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, ref)
+		})
+	}
+}
+
+func TestDuplicatePanelIDRule(t *testing.T) {
+	problems := check(t, duplicatePanelIDRule{}, `{
+		"panels": [
+			{"id": 1, "title": "A"},
+			{"id": 2, "type": "row", "panels": [{"id": 1, "title": "B"}]}
+		]
+	}`)
+	require.Len(t, problems, 1)
+	require.Equal(t, int64(1), problems[0].PanelID)
+	require.Equal(t, "B", problems[0].PanelTitle)
+}
+
+func TestRateIntervalRule(t *testing.T) {
+	problems := check(t, rateIntervalRule{}, `{
+		"panels": [
+			{"id": 1, "datasource": {"uid": "prom", "type": "prometheus"}, "targets": [
+				{"refId": "A", "expr": "rate(http_requests_total[5m])"},
+				{"refId": "B", "expr": "sum(rate(http_requests_total[$__rate_interval]))"},
+				{"refId": "C", "expr": "increase(errors_total[$__interval])"},
+				{"refId": "D", "expr": "http_requests_total"},
+				{"refId": "E", "expr": "max_over_time(up[1h])"}
+			]},
+			{"id": 2, "datasource": {"uid": "loki", "type": "loki"}, "targets": [
+				{"refId": "A", "expr": "rate({job=\"app\"}[5m])"}
+			]}
+		]
+	}`)
+	require.Len(t, problems, 2)
+	require.Equal(t, "A", problems[0].RefID)
+	require.Equal(t, "Query uses the range [5m] instead of [$__rate_interval]", problems[0].Message)
+	require.Equal(t, "C", problems[1].RefID)
+}
+
+func TestHardcodedDatasourceRule(t *testing.T) {
+	t.Run("no data source variable", func(t *testing.T) {
+		problems := check(t, hardcodedDatasourceRule{}, `{
+			"panels": [{"id": 1, "datasource": {"uid": "prom", "type": "prometheus"}}]
+		}`)
+		require.Empty(t, problems)
+	})
+
+	t.Run("data source of the type of a variable", func(t *testing.T) {
+		problems := check(t, hardcodedDatasourceRule{}, `{
+			"templating": {"list": [{"name": "ds", "type": "datasource", "query": "prometheus"}]},
+			"panels": [
+				{"id": 1, "datasource": {"uid": "prom", "type": "prometheus"}, "targets": [
+					{"refId": "A", "datasource": {"uid": "prom", "type": "prometheus"}}
+				]},
+				{"id": 2, "datasource": {"uid": "${ds}", "type": "prometheus"}},
+				{"id": 3, "datasource": {"uid": "loki", "type": "loki"}},
+				{"id": 4, "datasource": "Prometheus"}
+			]
+		}`)
+		require.Len(t, problems, 3)
+		require.Equal(t, int64(1), problems[0].PanelID)
+		require.Equal(t, "Panel uses the data source prom instead of the variable $ds", problems[0].Message)
+		require.Equal(t, "A", problems[1].RefID)
+		require.Equal(t, int64(4), problems[2].PanelID)
+	})
+}
+
+func TestMissingDescriptionRule(t *testing.T) {
+	problems := check(t, missingDescriptionRule{}, `{
+		"panels": [
+			{"id": 1, "description": "Requests per second"},
+			{"id": 2},
+			{"id": 3, "libraryPanel": {"uid": "lib"}}
+		]
+	}`)
+	require.Len(t, problems, 2)
+	require.Equal(t, "Dashboard has no description", problems[0].Message)
+	require.Equal(t, int64(2), problems[1].PanelID)
+}
@@ -29,6 +29,7 @@ const (
 	ActionDashboardsPermissionsRead  = "dashboards.permissions:read"
 	ActionDashboardsPermissionsWrite = "dashboards.permissions:write"
 	ActionDashboardsPublicWrite      = "dashboards.public:write"
+	ActionDashboardsDraftsApprove    = "dashboards.drafts:approve"
 )
 
 var (
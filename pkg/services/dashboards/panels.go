@@ -0,0 +1,39 @@
+package dashboards
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// Panels returns the panels of the dashboard data, including the panels of collapsed rows and of the rows of
+// dashboards older than schema version 16. Rows are not returned. The panels are not copied, changing them
+// changes the dashboard data.
+func Panels(data *simplejson.Json) []*simplejson.Json {
+	var panels []*simplejson.Json
+	var add func(list []interface{})
+	add = func(list []interface{}) {
+		for _, obj := range list {
+			panel := simplejson.NewFromAny(obj)
+			if panel.Get("type").MustString() == "row" {
+				add(panel.Get("panels").MustArray())
+				continue
+			}
+			panels = append(panels, panel)
+		}
+	}
+
+	add(data.Get("panels").MustArray())
+	for _, row := range data.Get("rows").MustArray() {
+		add(simplejson.NewFromAny(row).Get("panels").MustArray())
+	}
+	return panels
+}
+
+// FindPanel returns the panel of the dashboard data with the given ID, or nil. Rows are not returned.
+func FindPanel(data *simplejson.Json, id int64) *simplejson.Json {
+	for _, panel := range Panels(data) {
+		if panel.Get("id").MustInt64() == id {
+			return panel
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestPanels(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{"id": 1, "type": "graph"},
+			{"id": 2, "type": "row", "collapsed": true, "panels": [{"id": 3, "type": "stat"}]}
+		],
+		"rows": [{"panels": [{"id": 4, "type": "table"}]}]
+	}`))
+	require.NoError(t, err)
+
+	var ids []int64
+	for _, panel := range Panels(data) {
+		ids = append(ids, panel.Get("id").MustInt64())
+	}
+	assert.Equal(t, []int64{1, 3, 4}, ids)
+
+	assert.Equal(t, "stat", FindPanel(data, 3).Get("type").MustString())
+	assert.Nil(t, FindPanel(data, 2), "rows are not panels")
+	assert.Nil(t, FindPanel(data, 5))
+
+	FindPanel(data, 3).Set("title", "Requests")
+	assert.Equal(t, "Requests", data.Get("panels").GetIndex(1).Get("panels").GetIndex(0).Get("title").MustString(), "the panels are not copied")
+}
@@ -0,0 +1,120 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// variablePattern matches the $name, ${name}, ${name:format} and [[name]] syntaxes of the template variables
+var variablePattern = regexp.MustCompile(`\$(\w+)|\$\{(\w+)(?::([^}]*))?\}|\[\[(\w+)(?::([^\]]*))?\]\]`)
+
+// InterpolateVariables returns a copy of a decoded JSON value with the template variables of its strings replaced by
+// their values, see Interpolate.
+func InterpolateVariables(value interface{}, variables map[string][]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return Interpolate(v, variables)
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i := range v {
+			c[i] = InterpolateVariables(v[i], variables)
+		}
+		return c
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key := range v {
+			c[key] = InterpolateVariables(v[key], variables)
+		}
+		return c
+	}
+	return value
+}
+
+// InterpolateQuery replaces the template variables of a panel query by their values, except in its data source.
+func InterpolateQuery(query *simplejson.Json, variables map[string][]string) {
+	if len(variables) == 0 {
+		return
+	}
+	for key, value := range query.MustMap() {
+		if key == "datasource" {
+			continue
+		}
+		query.Set(key, InterpolateVariables(value, variables))
+	}
+}
+
+// Interpolate replaces the template variables of s by their values, formatted as in the frontend. The unknown
+// variables are kept, the data sources replace some of them, e.g. $__rate_interval.
+func Interpolate(s string, variables map[string][]string) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variablePattern.FindStringSubmatch(match)
+		name, format := groups[1], ""
+		switch {
+		case groups[2] != "":
+			name, format = groups[2], groups[3]
+		case groups[4] != "":
+			name, format = groups[4], groups[5]
+		}
+
+		values, ok := variables[name]
+		if !ok {
+			return match
+		}
+		return formatValues(values, format)
+	})
+}
+
+// formatValues formats the values of a variable with one of the formats of the ${name:format} syntax. Multiple values
+// are formatted as a glob by default.
+func formatValues(values []string, format string) string {
+	switch format {
+	case "csv", "raw":
+		return strings.Join(values, ",")
+	case "text":
+		return strings.Join(values, " + ")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = regexp.QuoteMeta(v)
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "json":
+		var b []byte
+		if len(values) == 1 {
+			b, _ = json.Marshal(values[0])
+		} else {
+			b, _ = json.Marshal(values)
+		}
+		return string(b)
+	case "singlequote":
+		return quote(values, "'", `\'`)
+	case "doublequote":
+		return quote(values, `"`, `\"`)
+	case "sqlstring":
+		return quote(values, "'", "''")
+	case "percentencode":
+		return url.QueryEscape(formatValues(values, ""))
+	}
+
+	if len(values) == 1 {
+		return values[0]
+	}
+	return "{" + strings.Join(values, ",") + "}"
+}
+
+func quote(values []string, q, escaped string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = q + strings.ReplaceAll(v, q, escaped) + q
+	}
+	return strings.Join(quoted, ",")
+}
@@ -0,0 +1,66 @@
+package dashboards
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestInterpolate(t *testing.T) {
+	variables := map[string][]string{
+		"env":           {"prod"},
+		"hosts":         {"a.1", "b'2"},
+		"__interval":    {"1m"},
+		"__interval_ms": {"60000"},
+	}
+
+	testCases := map[string]string{
+		`up{env="$env"}`:                        `up{env="prod"}`,
+		`up{env="${env}", host=~"$hosts"}`:      `up{env="prod", host=~"{a.1,b'2}"}`,
+		`[[env]] [[hosts:csv]]`:                 `prod a.1,b'2`,
+		`host=~"${hosts:regex}"`:                `host=~"(a\.1|b'2)"`,
+		`host IN (${hosts:sqlstring})`:          `host IN ('a.1','b''2')`,
+		`${hosts:singlequote} ${hosts:pipe}`:    `'a.1','b\'2' a.1|b'2`,
+		`${hosts:json} ${env:json}`:             `["a.1","b'2"] "prod"`,
+		`${hosts:doublequote} ${hosts:text}`:    `"a.1","b'2" a.1 + b'2`,
+		`rate(x[$__interval]) $__interval_ms`:   `rate(x[1m]) 60000`,
+		`rate(x[$__rate_interval]) $unknown $1`: `rate(x[$__rate_interval]) $unknown $1`,
+		`${env:percentencode} ${hosts:unknown}`: `prod {a.1,b'2}`,
+	}
+	for query, expected := range testCases {
+		assert.Equal(t, expected, Interpolate(query, variables), query)
+	}
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	query := map[string]interface{}{
+		"expr":       "up{env=\"$env\"}",
+		"datasource": map[string]interface{}{"uid": "${ds}"},
+		"filters":    []interface{}{"$env", 1.0},
+	}
+
+	interpolated := InterpolateVariables(query, map[string][]string{"env": {"prod"}, "ds": {"prom"}})
+
+	assert.Equal(t, map[string]interface{}{
+		"expr":       "up{env=\"prod\"}",
+		"datasource": map[string]interface{}{"uid": "prom"},
+		"filters":    []interface{}{"prod", 1.0},
+	}, interpolated)
+	assert.Equal(t, "up{env=\"$env\"}", query["expr"], "the original query is not changed")
+	assert.Equal(t, "${ds}", query["datasource"].(map[string]interface{})["uid"])
+}
+
+func TestInterpolateQuery(t *testing.T) {
+	query := simplejson.NewFromAny(map[string]interface{}{
+		"refId":      "A",
+		"expr":       `up{env="$env", host=~"${host:regex}", other="$hostname"}`,
+		"datasource": map[string]interface{}{"uid": "$env"},
+	})
+
+	InterpolateQuery(query, map[string][]string{"env": {"staging"}, "host": {"a.1"}})
+
+	assert.Equal(t, `up{env="staging", host=~"a\.1", other="$hostname"}`, query.Get("expr").MustString())
+	assert.Equal(t, "$env", query.GetPath("datasource", "uid").MustString(), "the data source is not interpolated")
+}
@@ -65,6 +65,8 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			ExternalDeleteURL:  cmd.ExternalDeleteURL,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
+			Capture:            cmd.Capture,
+			NextCaptureAt:      cmd.NextCaptureAt,
 			Expires:            expires,
 			Created:            time.Now(),
 			Updated:            time.Now(),
@@ -144,3 +146,34 @@ func (d *DashboardSnapshotStore) SearchDashboardSnapshots(ctx context.Context, q
 	}
 	return queryResult, nil
 }
+
+// GetDashboardSnapshotsToCapture returns the snapshots whose next capture is due, the earliest first
+func (d *DashboardSnapshotStore) GetDashboardSnapshotsToCapture(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotsToCaptureQuery) ([]*dashboardsnapshots.DashboardSnapshot, error) {
+	snapshots := make([]*dashboardsnapshots.DashboardSnapshot, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("next_capture_at IS NOT NULL AND next_capture_at <= ? AND expires > ?", query.Now, query.Now).Asc("next_capture_at")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit)
+		}
+		return sess.Find(&snapshots)
+	})
+	return snapshots, err
+}
+
+func (d *DashboardSnapshotStore) UpdateDashboardSnapshotCapture(ctx context.Context, cmd *dashboardsnapshots.UpdateDashboardSnapshotCaptureCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		snapshot := &dashboardsnapshots.DashboardSnapshot{
+			DashboardEncrypted: cmd.DashboardEncrypted,
+			NextCaptureAt:      cmd.NextCaptureAt,
+			Updated:            time.Now(),
+		}
+		affected, err := sess.ID(cmd.ID).Cols("dashboard_encrypted", "next_capture_at", "updated").Update(snapshot)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot not found")
+		}
+		return nil
+	})
+}
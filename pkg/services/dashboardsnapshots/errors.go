@@ -5,3 +5,8 @@ import (
 )
 
 var ErrBaseNotFound = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+
+var (
+	ErrCaptureInvalid      = errutil.BadRequest("dashboardsnapshots.invalid-capture")
+	ErrCaptureAccessDenied = errutil.Forbidden("dashboardsnapshots.capture-access-denied", errutil.WithPublicMessage("Access denied to the dashboard"))
+)
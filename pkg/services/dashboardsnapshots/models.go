@@ -1,6 +1,7 @@
 package dashboardsnapshots
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -25,6 +26,28 @@ type DashboardSnapshot struct {
 
 	Dashboard          *simplejson.Json
 	DashboardEncrypted []byte
+
+	// Capture is set for the snapshots captured by the server that are captured again periodically.
+	Capture       *CaptureSpec `xorm:"capture"`
+	NextCaptureAt *time.Time   `xorm:"next_capture_at"`
+}
+
+// CaptureSpec is what the server needs to capture a snapshot of a dashboard again.
+type CaptureSpec struct {
+	DashboardUID string `json:"dashboardUid"`
+	// From and To are the time range of the snapshot, usually relative like now-1d/d.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Interval is the duration between two captures.
+	Interval string `json:"interval"`
+}
+
+func (c *CaptureSpec) FromDB(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+func (c *CaptureSpec) ToDB() ([]byte, error) {
+	return json.Marshal(c)
 }
 
 // DashboardSnapshotDTO without dashboard map
@@ -40,6 +63,8 @@ type DashboardSnapshotDTO struct {
 	Expires time.Time `json:"expires"`
 	Created time.Time `json:"created"`
 	Updated time.Time `json:"updated"`
+
+	NextCaptureAt *time.Time `json:"nextCaptureAt,omitempty" xorm:"next_capture_at"`
 }
 
 // -----------------
@@ -77,6 +102,47 @@ type CreateDashboardSnapshotCommand struct {
 	UserID int64 `json:"-"`
 
 	DashboardEncrypted []byte `json:"-"`
+
+	Capture       *CaptureSpec `json:"-"`
+	NextCaptureAt *time.Time   `json:"-"`
+}
+
+// swagger:model
+type CaptureDashboardSnapshotCommand struct {
+	// UID of the dashboard to capture.
+	// required:true
+	DashboardUID string `json:"dashboardUid" binding:"Required"`
+	// Snapshot name, the dashboard title by default.
+	// required:false
+	Name string `json:"name"`
+	// When the snapshot should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+	// Start of the time range of the snapshot, the dashboard time range by default.
+	// required:false
+	From string `json:"from"`
+	// End of the time range of the snapshot, the dashboard time range by default.
+	// required:false
+	To string `json:"to"`
+	// Duration between two captures of the snapshot, like 24h. The snapshot is captured once when empty.
+	// required:false
+	RecaptureInterval string `json:"recaptureInterval"`
+
+	User *user.SignedInUser `json:"-"`
+}
+
+// UpdateDashboardSnapshotCaptureCommand replaces the data of a snapshot captured again by the server.
+type UpdateDashboardSnapshotCaptureCommand struct {
+	ID                 int64
+	DashboardEncrypted []byte
+	NextCaptureAt      *time.Time
+}
+
+// GetDashboardSnapshotsToCaptureQuery returns the snapshots that are due to be captured again.
+type GetDashboardSnapshotsToCaptureQuery struct {
+	Now   time.Time
+	Limit int
 }
 
 type DeleteDashboardSnapshotCommand struct {
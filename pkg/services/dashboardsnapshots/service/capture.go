@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// defaultMaxDataPoints is used for the panels that do not set their max data points, it is about the width in pixels of
+// a panel of a dashboard
+const defaultMaxDataPoints = 1000
+
+// CaptureDashboardSnapshot creates a snapshot of a dashboard by running the queries of its panels with the
+// permissions of the user, instead of storing the data loaded by the browser.
+func (s *ServiceImpl) CaptureDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.CaptureDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
+	spec := &dashboardsnapshots.CaptureSpec{
+		DashboardUID: cmd.DashboardUID,
+		From:         cmd.From,
+		To:           cmd.To,
+		Interval:     cmd.RecaptureInterval,
+	}
+
+	var interval time.Duration
+	if spec.Interval != "" {
+		var err error
+		interval, err = gtime.ParseDuration(spec.Interval)
+		if err != nil {
+			return nil, dashboardsnapshots.ErrCaptureInvalid.Errorf("invalid recapture interval: %w", err)
+		}
+		if interval < s.cfg.SnapshotRecaptureMinInterval {
+			return nil, dashboardsnapshots.ErrCaptureInvalid.Errorf("recapture interval should be at least %s", s.cfg.SnapshotRecaptureMinInterval)
+		}
+	}
+
+	dash, err := s.captureDashboard(ctx, spec, cmd.Name, cmd.User)
+	if err != nil {
+		return nil, err
+	}
+
+	createCmd := &dashboardsnapshots.CreateDashboardSnapshotCommand{
+		Dashboard: dash,
+		Name:      dash.Get("title").MustString(),
+		Expires:   cmd.Expires,
+		OrgID:     cmd.User.OrgID,
+		UserID:    cmd.User.UserID,
+	}
+	if createCmd.Key, err = util.GetRandomString(32); err != nil {
+		return nil, err
+	}
+	if createCmd.DeleteKey, err = util.GetRandomString(32); err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		next := time.Now().Add(interval)
+		createCmd.Capture = spec
+		createCmd.NextCaptureAt = &next
+	}
+
+	return s.CreateDashboardSnapshot(ctx, createCmd)
+}
+
+// captureDashboard returns the snapshot model of a dashboard, with the data of its panels and without their queries
+func (s *ServiceImpl) captureDashboard(ctx context.Context, spec *dashboardsnapshots.CaptureSpec, name string, usr *user.SignedInUser) (*simplejson.Json, error) {
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: spec.DashboardUID, OrgID: usr.OrgID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil, dashboardsnapshots.ErrCaptureInvalid.Errorf("dashboard %s not found", spec.DashboardUID)
+		}
+		return nil, err
+	}
+
+	g, err := guardian.NewByDashboard(ctx, dash, usr.OrgID, usr)
+	if err != nil {
+		return nil, err
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return nil, dashboardsnapshots.ErrCaptureAccessDenied.Errorf("user %d cannot view dashboard %s", usr.UserID, spec.DashboardUID)
+	}
+
+	data := dash.Data
+	from, to := spec.From, spec.To
+	if from == "" || to == "" {
+		from = data.GetPath("time", "from").MustString("now-6h")
+		to = data.GetPath("time", "to").MustString("now")
+	}
+	timeRange := legacydata.NewDataTimeRange(from, to)
+	timeFrom, err := timeRange.ParseFrom()
+	if err != nil {
+		return nil, dashboardsnapshots.ErrCaptureInvalid.Errorf("invalid time range from: %w", err)
+	}
+	timeTo, err := timeRange.ParseTo()
+	if err != nil {
+		return nil, dashboardsnapshots.ErrCaptureInvalid.Errorf("invalid time range to: %w", err)
+	}
+
+	variables := scrubVariables(data)
+	for _, panel := range dashboards.Panels(data) {
+		s.capturePanel(ctx, usr, panel, timeFrom, timeTo, variables)
+	}
+
+	if name != "" {
+		data.Set("title", name)
+	}
+	data.Set("time", map[string]interface{}{"from": timeFrom.UTC().Format(time.RFC3339), "to": timeTo.UTC().Format(time.RFC3339)})
+	data.Set("links", []interface{}{})
+	// annotations are not captured by the server
+	data.SetPath([]string{"annotations", "list"}, []interface{}{})
+	data.SetPath([]string{"snapshot", "timestamp"}, time.Now().UTC().Format(time.RFC3339))
+	data.SetPath([]string{"snapshot", "originalUrl"}, fmt.Sprintf("/d/%s", dash.UID))
+
+	return data, nil
+}
+
+// capturePanel replaces the queries of the panel by the data they return
+func (s *ServiceImpl) capturePanel(ctx context.Context, usr *user.SignedInUser, panel *simplejson.Json, from, to time.Time, variables map[string][]string) {
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(defaultMaxDataPoints)
+	intervalMs := to.Sub(from).Milliseconds() / maxDataPoints
+	if intervalMs < 1 {
+		intervalMs = 1
+	}
+
+	var queries []*simplejson.Json
+	for _, obj := range panel.Get("targets").MustArray() {
+		query := simplejson.NewFromAny(obj)
+		if query.Get("hide").MustBool() {
+			continue
+		}
+		if _, ok := query.CheckGet("datasource"); !ok {
+			query.Set("datasource", panel.Get("datasource").Interface())
+		}
+		dashboards.InterpolateQuery(query, variables)
+		query.Set("intervalMs", intervalMs)
+		query.Set("maxDataPoints", maxDataPoints)
+		queries = append(queries, query)
+	}
+
+	snapshotData := make([]interface{}, 0)
+	if len(queries) > 0 {
+		res, err := s.queryService.QueryData(ctx, usr, false, dtos.MetricRequest{
+			From:    strconv.FormatInt(from.UnixMilli(), 10),
+			To:      strconv.FormatInt(to.UnixMilli(), 10),
+			Queries: queries,
+		})
+		if err != nil {
+			s.log.Warn("Failed to capture snapshot panel", "panelId", panel.Get("id").MustInt64(), "error", err)
+		} else {
+			for _, query := range queries {
+				dr, ok := res.Responses[query.Get("refId").MustString()]
+				if !ok {
+					continue
+				}
+				if dr.Error != nil {
+					s.log.Warn("Failed to capture snapshot panel query", "panelId", panel.Get("id").MustInt64(), "refId", query.Get("refId").MustString(), "error", dr.Error)
+				}
+				for _, frame := range dr.Frames {
+					// frames are stored in their JSON format, which the frontend reads as snapshot data
+					b, err := json.Marshal(frame)
+					if err != nil {
+						s.log.Warn("Failed to encode snapshot panel frame", "panelId", panel.Get("id").MustInt64(), "error", err)
+						continue
+					}
+					var v interface{}
+					if err := json.Unmarshal(b, &v); err == nil {
+						snapshotData = append(snapshotData, v)
+					}
+				}
+			}
+		}
+	}
+
+	panel.Set("snapshotData", snapshotData)
+	panel.Set("targets", []interface{}{})
+	panel.Set("links", []interface{}{})
+	panel.Set("datasource", nil)
+}
+
+// scrubVariables removes the queries of the template variables, like the frontend does for snapshots, and returns
+// their current values, the variables with several values are not returned
+func scrubVariables(data *simplejson.Json) map[string][]string {
+	values := make(map[string][]string)
+	for _, obj := range data.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(obj)
+		current := variable.Get("current")
+		if value, ok := current.Get("value").Interface().(string); ok {
+			values[variable.Get("name").MustString()] = []string{value}
+		}
+
+		if _, ok := variable.CheckGet("query"); ok {
+			variable.Set("query", "")
+		}
+		if _, ok := variable.CheckGet("options"); ok {
+			options := []interface{}{}
+			if len(current.MustMap()) > 0 {
+				options = append(options, current.Interface())
+			}
+			variable.Set("options", options)
+		}
+		if _, ok := variable.CheckGet("refresh"); ok {
+			// never refresh
+			variable.Set("refresh", 0)
+		}
+		variable.Del("definition")
+	}
+	return values
+}
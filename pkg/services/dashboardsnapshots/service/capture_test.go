@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	dashsnapdb "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const capturedDashboard = `{
+  "uid": "dash1",
+  "title": "Capture me",
+  "time": {"from": "now-1h", "to": "now"},
+  "links": [{"title": "link"}],
+  "templating": {"list": [{"name": "job", "type": "query", "query": "label_values(job)", "current": {"text": "api", "value": "api"}, "options": [{"text": "api", "value": "api"}, {"text": "db", "value": "db"}]}]},
+  "panels": [
+    {"id": 1, "type": "timeseries", "datasource": {"type": "prometheus", "uid": "ds1"}, "targets": [{"refId": "A", "expr": "up{job=\"$job\"}"}, {"refId": "B", "expr": "down", "hide": true}]},
+    {"id": 2, "type": "row", "collapsed": true, "panels": [
+      {"id": 3, "type": "stat", "datasource": {"type": "prometheus", "uid": "ds1"}, "targets": [{"refId": "A", "expr": "sum(up)"}]}
+    ]}
+  ]
+}`
+
+func setupCaptureTest(t *testing.T) (*ServiceImpl, *query.FakeQueryService) {
+	t.Helper()
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.SnapshotRecaptureMinInterval = time.Hour
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(func(_ context.Context, _ *dashboards.GetDashboardQuery) *dashboards.Dashboard {
+		data, err := simplejson.NewJson([]byte(capturedDashboard))
+		require.NoError(t, err)
+		return &dashboards.Dashboard{UID: "dash1", OrgID: 1, Data: data}
+	}, nil).Maybe()
+
+	queryService := &query.FakeQueryService{}
+	queryService.On("QueryData", mock.Anything, mock.Anything, false, mock.Anything).Return(func(_ context.Context, _ *user.SignedInUser, _ bool, req dtos.MetricRequest) *backend.QueryDataResponse {
+		res := backend.NewQueryDataResponse()
+		for _, q := range req.Queries {
+			refID := q.Get("refId").MustString()
+			frame := data.NewFrame(q.Get("expr").MustString(), data.NewField("value", nil, []float64{1, 2}))
+			frame.RefID = refID
+			res.Responses[refID] = backend.DataResponse{Frames: data.Frames{frame}}
+		}
+		return res
+	}, nil)
+
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedSignedInUser = &user.SignedInUser{UserID: 1, OrgID: 1}
+
+	s := ProvideService(
+		dashsnapdb.ProvideStore(sqlStore, cfg),
+		secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore)),
+		cfg, dashboardService, queryService, userService, actest.FakeService{}, nil,
+	)
+	return s, queryService
+}
+
+func TestCaptureDashboardSnapshot(t *testing.T) {
+	ctx := context.Background()
+	usr := &user.SignedInUser{UserID: 1, OrgID: 1}
+
+	t.Run("runs the queries of the panels and stores their data", func(t *testing.T) {
+		s, queryService := setupCaptureTest(t)
+
+		result, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{DashboardUID: "dash1", User: usr})
+		require.NoError(t, err)
+		assert.Nil(t, result.NextCaptureAt)
+		assert.NotEmpty(t, result.Key)
+		assert.NotEmpty(t, result.DeleteKey)
+
+		queryService.AssertNumberOfCalls(t, "QueryData", 2)
+		req := queryService.Calls[0].Arguments.Get(3).(dtos.MetricRequest)
+		require.Len(t, req.Queries, 1, "hidden queries are not run")
+		assert.Equal(t, `up{job="api"}`, req.Queries[0].Get("expr").MustString())
+
+		snapshot, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: result.Key})
+		require.NoError(t, err)
+		dash := snapshot.Dashboard
+
+		assert.Equal(t, "Capture me", snapshot.Name)
+		assert.Equal(t, "/d/dash1", dash.GetPath("snapshot", "originalUrl").MustString())
+		assert.Empty(t, dash.Get("links").MustArray())
+
+		panel := dash.Get("panels").GetIndex(0)
+		assert.Empty(t, panel.Get("targets").MustArray())
+		require.Len(t, panel.Get("snapshotData").MustArray(), 1)
+		assert.Equal(t, `up{job="api"}`, panel.Get("snapshotData").GetIndex(0).GetPath("schema", "name").MustString())
+
+		nested := dash.Get("panels").GetIndex(1).Get("panels").GetIndex(0)
+		require.Len(t, nested.Get("snapshotData").MustArray(), 1)
+
+		variable := dash.Get("templating").Get("list").GetIndex(0)
+		assert.Empty(t, variable.Get("query").MustString())
+		assert.Len(t, variable.Get("options").MustArray(), 1)
+	})
+
+	t.Run("requires the user to view the dashboard", func(t *testing.T) {
+		s, _ := setupCaptureTest(t)
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: false})
+
+		_, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{DashboardUID: "dash1", User: usr})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrCaptureAccessDenied)
+	})
+
+	t.Run("rejects a recapture interval shorter than the minimum", func(t *testing.T) {
+		s, _ := setupCaptureTest(t)
+
+		_, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{DashboardUID: "dash1", RecaptureInterval: "5m", User: usr})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrCaptureInvalid)
+	})
+
+	t.Run("captures the snapshot again when it is due", func(t *testing.T) {
+		s, queryService := setupCaptureTest(t)
+
+		result, err := s.CaptureDashboardSnapshot(ctx, &dashboardsnapshots.CaptureDashboardSnapshotCommand{
+			DashboardUID: "dash1", From: "now-1d/d", To: "now-1d/d", RecaptureInterval: "1d", User: usr,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, result.NextCaptureAt)
+		queryService.AssertNumberOfCalls(t, "QueryData", 2)
+
+		s.recaptureDueSnapshots(ctx, time.Now())
+		queryService.AssertNumberOfCalls(t, "QueryData", 2)
+
+		now := result.NextCaptureAt.Add(time.Minute)
+		s.recaptureDueSnapshots(ctx, now)
+		queryService.AssertNumberOfCalls(t, "QueryData", 4)
+
+		snapshots, err := s.store.GetDashboardSnapshotsToCapture(ctx, &dashboardsnapshots.GetDashboardSnapshotsToCaptureQuery{Now: now.Add(23 * time.Hour)})
+		require.NoError(t, err)
+		assert.Empty(t, snapshots, "next capture is a day later")
+
+		snapshots, err = s.store.GetDashboardSnapshotsToCapture(ctx, &dashboardsnapshots.GetDashboardSnapshotsToCaptureQuery{Now: now.Add(25 * time.Hour)})
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		assert.Equal(t, "dash1", snapshots[0].Capture.DashboardUID)
+	})
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	recaptureTickInterval = time.Minute
+	recaptureBatchSize    = 100
+	// recaptureTimeout bounds the capture of a single snapshot, so that a batch can't outlive the lock.
+	recaptureTimeout = 30 * time.Second
+	// recaptureLockTimeout is how long the lock is held, at most, when the instance capturing a batch stops.
+	recaptureLockTimeout = recaptureBatchSize * recaptureTimeout
+)
+
+// IsDisabled returns true when snapshots are disabled, the snapshots are not captured again then.
+func (s *ServiceImpl) IsDisabled() bool {
+	return !s.cfg.SnapshotEnabled
+}
+
+// Run captures again the snapshots that are due, with the permissions their creator has at that time.
+func (s *ServiceImpl) Run(ctx context.Context) error {
+	ticker := time.NewTicker(recaptureTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// Only one instance captures the snapshots again at a time, the lock is released once the batch is done.
+			err := s.serverLock.LockExecuteAndRelease(ctx, "recapture dashboard snapshots", recaptureLockTimeout, func(ctx context.Context) {
+				s.recaptureDueSnapshots(ctx, time.Now())
+			})
+			var lockExists *serverlock.ServerLockExistsError
+			if err != nil && !errors.As(err, &lockExists) {
+				s.log.Error("Failed to recapture dashboard snapshots", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *ServiceImpl) recaptureDueSnapshots(ctx context.Context, now time.Time) {
+	snapshots, err := s.store.GetDashboardSnapshotsToCapture(ctx, &dashboardsnapshots.GetDashboardSnapshotsToCaptureQuery{Now: now, Limit: recaptureBatchSize})
+	if err != nil {
+		s.log.Error("Failed to get the dashboard snapshots to recapture", "error", err)
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		if ctx.Err() != nil {
+			return
+		}
+		captureCtx, cancel := context.WithTimeout(ctx, recaptureTimeout)
+		if err := s.recaptureSnapshot(captureCtx, snapshot, now); err != nil {
+			s.log.Error("Failed to recapture dashboard snapshot", "id", snapshot.ID, "error", err)
+		}
+		cancel()
+	}
+}
+
+func (s *ServiceImpl) recaptureSnapshot(ctx context.Context, snapshot *dashboardsnapshots.DashboardSnapshot, now time.Time) error {
+	cmd := &dashboardsnapshots.UpdateDashboardSnapshotCaptureCommand{
+		ID:                 snapshot.ID,
+		DashboardEncrypted: snapshot.DashboardEncrypted,
+	}
+
+	if snapshot.Capture == nil {
+		return s.store.UpdateDashboardSnapshotCapture(ctx, cmd)
+	}
+
+	interval, err := gtime.ParseDuration(snapshot.Capture.Interval)
+	if err != nil {
+		// the snapshot is not captured again
+		return s.store.UpdateDashboardSnapshotCapture(ctx, cmd)
+	}
+	next := now.Add(interval)
+	cmd.NextCaptureAt = &next
+
+	usr, err := s.snapshotCreator(ctx, snapshot)
+	if errors.Is(err, user.ErrUserNotFound) {
+		s.log.Warn("Dashboard snapshot creator not found, the snapshot will not be captured again", "id", snapshot.ID, "userId", snapshot.UserID)
+		cmd.NextCaptureAt = nil
+		return s.store.UpdateDashboardSnapshotCapture(ctx, cmd)
+	} else if err != nil {
+		return err
+	}
+
+	dash, err := s.captureDashboard(ctx, snapshot.Capture, snapshot.Name, usr)
+	if err != nil {
+		// keep the data of the previous capture, the next capture might succeed
+		s.log.Warn("Failed to capture dashboard snapshot", "id", snapshot.ID, "error", err)
+		return s.store.UpdateDashboardSnapshotCapture(ctx, cmd)
+	}
+
+	marshalledData, err := dash.Encode()
+	if err != nil {
+		return err
+	}
+	cmd.DashboardEncrypted, err = s.secretsService.Encrypt(ctx, marshalledData, secrets.WithoutScope())
+	if err != nil {
+		return err
+	}
+
+	s.log.Debug("Dashboard snapshot captured again", "id", snapshot.ID, "nextCaptureAt", next)
+	return s.store.UpdateDashboardSnapshotCapture(ctx, cmd)
+}
+
+// snapshotCreator returns the user who created the snapshot, with their current permissions
+func (s *ServiceImpl) snapshotCreator(ctx context.Context, snapshot *dashboardsnapshots.DashboardSnapshot) (*user.SignedInUser, error) {
+	usr, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: snapshot.UserID, OrgID: snapshot.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.accessControl.GetUserPermissions(ctx, usr, accesscontrol.Options{ReloadCache: true})
+	if err != nil {
+		return nil, err
+	}
+	if usr.Permissions == nil {
+		usr.Permissions = make(map[int64]map[string][]string)
+	}
+	usr.Permissions[usr.OrgID] = accesscontrol.GroupScopesByAction(permissions)
+
+	return usr, nil
+}
@@ -4,22 +4,47 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type ServiceImpl struct {
-	store          dashboardsnapshots.Store
-	secretsService secrets.Service
+	store            dashboardsnapshots.Store
+	secretsService   secrets.Service
+	cfg              *setting.Cfg
+	dashboardService dashboards.DashboardService
+	queryService     query.Service
+	userService      user.Service
+	accessControl    accesscontrol.Service
+	serverLock       *serverlock.ServerLockService
+	log              log.Logger
 }
 
 // ServiceImpl implements the dashboardsnapshots Service interface
 var _ dashboardsnapshots.Service = (*ServiceImpl)(nil)
 
-func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service) *ServiceImpl {
+func ProvideService(
+	store dashboardsnapshots.Store, secretsService secrets.Service, cfg *setting.Cfg,
+	dashboardService dashboards.DashboardService, queryService query.Service, userService user.Service,
+	accessControl accesscontrol.Service, serverLock *serverlock.ServerLockService,
+) *ServiceImpl {
 	s := &ServiceImpl{
-		store:          store,
-		secretsService: secretsService,
+		store:            store,
+		secretsService:   secretsService,
+		cfg:              cfg,
+		dashboardService: dashboardService,
+		queryService:     queryService,
+		userService:      userService,
+		accessControl:    accessControl,
+		serverLock:       serverLock,
+		log:              log.New("dashboardsnapshots"),
 	}
 
 	return s
@@ -0,0 +1,17 @@
+package dashboardusage
+
+import (
+	"context"
+)
+
+// Service records how dashboards are used, aggregated per day, to find the dashboards nobody uses anymore.
+type Service interface {
+	// RecordView counts a view of a dashboard. Views and queries are counted in memory and saved in the background.
+	RecordView(ctx context.Context, cmd *RecordViewCommand)
+	// RecordQueries counts the queries run by a panel of a dashboard.
+	RecordQueries(ctx context.Context, cmd *RecordQueriesCommand)
+	GetDashboardUsage(ctx context.Context, query *GetDashboardUsageQuery) (*DashboardUsage, error)
+	// SearchStaleDashboards returns the dashboards the user can view that were not viewed for a number of days,
+	// those never viewed first.
+	SearchStaleDashboards(ctx context.Context, query *SearchStaleDashboardsQuery) ([]*StaleDashboard, error)
+}
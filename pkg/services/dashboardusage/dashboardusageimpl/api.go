@@ -0,0 +1,142 @@
+package dashboardusageimpl
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	dashboardScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+
+	routeRegister.Group("/api/dashboards", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/usage/stale", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), routing.Wrap(s.handleSearchStaleDashboards))
+		subrouter.Get("/uid/:uid/usage", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead, dashboardScope)), routing.Wrap(s.handleGetDashboardUsage))
+	})
+}
+
+// swagger:route GET /dashboards/uid/{uid}/usage dashboard_usage getDashboardUsage
+//
+// Get the usage of a dashboard.
+//
+// Returns the views and the queries of the dashboard per day and per panel, and the last time it was viewed.
+//
+// Responses:
+// 200: getDashboardUsageResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleGetDashboardUsage(c *contextmodel.ReqContext) response.Response {
+	dashboard, err := s.dashboardService.GetDashboard(c.Req.Context(), &dashboards.GetDashboardQuery{
+		UID:   web.Params(c.Req)[":uid"],
+		OrgID: c.SignedInUser.OrgID,
+	})
+	if err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+	if dashboard.IsFolder {
+		return response.Error(http.StatusNotFound, dashboards.ErrDashboardNotFound.Error(), nil)
+	}
+
+	g, err := guardian.NewByDashboard(c.Req.Context(), dashboard, c.SignedInUser.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check dashboard permissions", err)
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return response.Error(http.StatusForbidden, "Access denied to this dashboard", err)
+	}
+
+	usage, err := s.GetDashboardUsage(c.Req.Context(), &dashboardusage.GetDashboardUsageQuery{
+		OrgID:       dashboard.OrgID,
+		DashboardID: dashboard.ID,
+		Days:        c.QueryInt("days"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get the usage of the dashboard", err)
+	}
+	return response.JSON(http.StatusOK, usage)
+}
+
+// swagger:route GET /dashboards/usage/stale dashboard_usage searchStaleDashboards
+//
+// Search stale dashboards.
+//
+// Returns the dashboards the user can view that were not viewed for a number of days, 90 by default, those never
+// viewed first. Dashboards created more recently are not stale.
+//
+// Responses:
+// 200: searchStaleDashboardsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSearchStaleDashboards(c *contextmodel.ReqContext) response.Response {
+	result, err := s.SearchStaleDashboards(c.Req.Context(), &dashboardusage.SearchStaleDashboardsQuery{
+		OrgID:      c.SignedInUser.OrgID,
+		User:       c.SignedInUser,
+		Days:       c.QueryInt("days"),
+		FolderUIDs: c.QueryStrings("folderUIDs"),
+		Limit:      c.QueryInt64("limit"),
+		Page:       c.QueryInt64("page"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to search stale dashboards", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters getDashboardUsage
+type GetDashboardUsageParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// Number of days of usage, including today.
+	// in:query
+	// required:false
+	// default:30
+	Days int `json:"days"`
+}
+
+// swagger:parameters searchStaleDashboards
+type SearchStaleDashboardsParams struct {
+	// Number of days without views.
+	// in:query
+	// required:false
+	// default:90
+	Days int `json:"days"`
+	// Only return the dashboards of these folders.
+	// in:query
+	// required:false
+	FolderUIDs []string `json:"folderUIDs"`
+	// in:query
+	// required:false
+	// default:1000
+	Limit int64 `json:"limit"`
+	// in:query
+	// required:false
+	// default:1
+	Page int64 `json:"page"`
+}
+
+// swagger:response getDashboardUsageResponse
+type GetDashboardUsageResponse struct {
+	// in: body
+	Body *dashboardusage.DashboardUsage `json:"body"`
+}
+
+// swagger:response searchStaleDashboardsResponse
+type SearchStaleDashboardsResponse struct {
+	// in: body
+	Body []*dashboardusage.StaleDashboard `json:"body"`
+}
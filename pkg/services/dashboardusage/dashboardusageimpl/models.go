@@ -0,0 +1,22 @@
+package dashboardusageimpl
+
+// dayFormat is the format of the days usage is aggregated by, in UTC.
+const dayFormat = "2006-01-02"
+
+// dailyUsage is the usage of a dashboard, or of one of its panels, during a day. The views are counted
+// on the row of the dashboard, with the panel ID 0, and the queries on the rows of the panels.
+type dailyUsage struct {
+	ID          int64  `xorm:"pk autoincr 'id'"`
+	OrgID       int64  `xorm:"org_id"`
+	DashboardID int64  `xorm:"dashboard_id"`
+	PanelID     int64  `xorm:"panel_id"`
+	Day         string `xorm:"day"`
+	Views       int64  `xorm:"views"`
+	Queries     int64  `xorm:"queries"`
+	// LastViewed is the last view of the day in seconds since epoch, 0 without views.
+	LastViewed int64 `xorm:"last_viewed"`
+}
+
+func (u dailyUsage) TableName() string {
+	return "dashboard_usage_daily"
+}
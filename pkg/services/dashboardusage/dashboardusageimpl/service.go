@@ -0,0 +1,355 @@
+package dashboardusageimpl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/services/search"
+	searchmodel "github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	cleanUpInterval = time.Hour
+	// maxBufferedKeys bounds the usage counted between two flushes, as the dashboard UIDs of queries come from
+	// request headers. The usage of new dashboards, panels or days is dropped once it is reached.
+	maxBufferedKeys = 10000
+	flushTimeout    = 30 * time.Second
+)
+
+var _ dashboardusage.Service = (*Service)(nil)
+
+type Service struct {
+	store            store
+	dashboardService dashboards.DashboardService
+	accessControl    ac.AccessControl
+	lock             *serverlock.ServerLockService
+	log              log.Logger
+
+	enabled       bool
+	flushInterval time.Duration
+	retention     time.Duration
+
+	mu      sync.Mutex
+	buffer  map[usageKey]*usageCounts
+	dropped int
+}
+
+// usageKey identifies the usage aggregated in memory until it is flushed.
+type usageKey struct {
+	orgID        int64
+	dashboardUID string
+	panelID      int64
+	day          string
+}
+
+type usageCounts struct {
+	views      int64
+	queries    int64
+	lastViewed int64
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	dashboardService dashboards.DashboardService,
+	searchService *search.SearchService,
+	lock *serverlock.ServerLockService,
+	accessControl ac.AccessControl,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("dashboard_usage")
+	s := &Service{
+		store:            &xormStore{db: sql},
+		dashboardService: dashboardService,
+		accessControl:    accessControl,
+		lock:             lock,
+		log:              log.New("dashboard.usage"),
+		enabled:          section.Key("enabled").MustBool(false),
+		flushInterval:    section.Key("flush_interval").MustDuration(time.Minute),
+		buffer:           make(map[usageKey]*usageCounts),
+	}
+
+	if !s.enabled {
+		return s, nil
+	}
+
+	retention, err := gtime.ParseDuration(section.Key("retention").MustString("1y"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid [dashboard_usage] retention: %w", err)
+	}
+	s.retention = retention
+	if s.flushInterval <= 0 {
+		return nil, fmt.Errorf("invalid [dashboard_usage] flush_interval: %s", s.flushInterval)
+	}
+
+	for _, option := range []searchmodel.SortOption{SortLastViewedDesc, SortLastViewedAsc, SortViewsRecentDesc, SortViewsRecentAsc} {
+		searchService.RegisterSortOption(option)
+	}
+	s.registerAPIEndpoints(routeRegister)
+	return s, nil
+}
+
+// Run saves the usage counted in memory at each flush interval, and deletes the usage older than the retention.
+func (s *Service) Run(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+
+	flushTicker := time.NewTicker(s.flushInterval)
+	defer flushTicker.Stop()
+	cleanUpTicker := time.NewTicker(cleanUpInterval)
+	defer cleanUpTicker.Stop()
+
+	for {
+		select {
+		case <-flushTicker.C:
+			s.flush(ctx)
+		case <-cleanUpTicker.C:
+			s.cleanup(ctx)
+		case <-ctx.Done():
+			// save the usage counted since the last flush before stopping
+			flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			s.flush(flushCtx)
+			cancel()
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) RecordView(ctx context.Context, cmd *dashboardusage.RecordViewCommand) {
+	now := time.Now()
+	s.add(usageKey{orgID: cmd.OrgID, dashboardUID: cmd.DashboardUID}, now, func(c *usageCounts) {
+		c.views++
+		c.lastViewed = now.Unix()
+	})
+}
+
+func (s *Service) RecordQueries(ctx context.Context, cmd *dashboardusage.RecordQueriesCommand) {
+	if cmd.Queries <= 0 || cmd.PanelID < 0 {
+		return
+	}
+	s.add(usageKey{orgID: cmd.OrgID, dashboardUID: cmd.DashboardUID, panelID: cmd.PanelID}, time.Now(), func(c *usageCounts) {
+		c.queries += int64(cmd.Queries)
+	})
+}
+
+func (s *Service) add(key usageKey, now time.Time, update func(c *usageCounts)) {
+	if !s.enabled || key.dashboardUID == "" {
+		return
+	}
+	key.day = now.UTC().Format(dayFormat)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts, ok := s.buffer[key]
+	if !ok {
+		if len(s.buffer) >= maxBufferedKeys {
+			s.dropped++
+			return
+		}
+		counts = &usageCounts{}
+		s.buffer[key] = counts
+	}
+	update(counts)
+}
+
+// flush saves the usage counted in memory. The usage of dashboards that do not exist is dropped.
+func (s *Service) flush(ctx context.Context) {
+	s.mu.Lock()
+	buffer, dropped := s.buffer, s.dropped
+	s.buffer, s.dropped = make(map[usageKey]*usageCounts), 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.log.Warn("Dropped dashboard usage, too many dashboards were used since the last flush", "dropped", dropped)
+	}
+	if len(buffer) == 0 {
+		return
+	}
+
+	uids := make(map[int64][]string)
+	seen := make(map[usageKey]bool)
+	for key := range buffer {
+		dashboardKey := usageKey{orgID: key.orgID, dashboardUID: key.dashboardUID}
+		if !seen[dashboardKey] {
+			seen[dashboardKey] = true
+			uids[key.orgID] = append(uids[key.orgID], key.dashboardUID)
+		}
+	}
+
+	ids := make(map[int64]map[string]int64, len(uids))
+	for orgID, orgUIDs := range uids {
+		orgIDs, err := s.store.GetDashboardIDs(ctx, orgID, orgUIDs)
+		if err != nil {
+			s.log.Error("Failed to save dashboard usage", "error", err)
+			return
+		}
+		ids[orgID] = orgIDs
+	}
+
+	saved := 0
+	for key, counts := range buffer {
+		dashboardID, ok := ids[key.orgID][key.dashboardUID]
+		if !ok {
+			continue
+		}
+		err := s.store.Add(ctx, &dailyUsage{
+			OrgID:       key.orgID,
+			DashboardID: dashboardID,
+			PanelID:     key.panelID,
+			Day:         key.day,
+			Views:       counts.views,
+			Queries:     counts.queries,
+			LastViewed:  counts.lastViewed,
+		})
+		if err != nil {
+			s.log.Error("Failed to save dashboard usage", "dashboard", key.dashboardUID, "error", err)
+			continue
+		}
+		saved++
+	}
+	s.log.Debug("Saved dashboard usage", "rows", saved)
+}
+
+func (s *Service) cleanup(ctx context.Context) {
+	if s.retention <= 0 {
+		return
+	}
+
+	err := s.lock.LockAndExecute(ctx, "delete old dashboard usage", cleanUpInterval, func(context.Context) {
+		before := time.Now().UTC().Add(-s.retention).Format(dayFormat)
+		if deleted, err := s.store.DeleteOlderThan(ctx, before); err != nil {
+			s.log.Error("Problem deleting old dashboard usage", "error", err)
+		} else {
+			s.log.Debug("Deleted old dashboard usage", "rows affected", deleted)
+		}
+	})
+	if err != nil {
+		s.log.Error("Failed to lock and execute cleanup of old dashboard usage", "error", err)
+	}
+}
+
+func (s *Service) GetDashboardUsage(ctx context.Context, query *dashboardusage.GetDashboardUsageQuery) (*dashboardusage.DashboardUsage, error) {
+	days := query.Days
+	if days == 0 {
+		days = dashboardusage.DefaultUsageDays
+	}
+	if err := s.validateDays(days); err != nil {
+		return nil, err
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, 1-days).Format(dayFormat)
+	rows, err := s.store.GetUsage(ctx, query.DashboardID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dashboardusage.DashboardUsage{
+		Days:   make([]dashboardusage.DailyUsage, 0),
+		Panels: make([]dashboardusage.PanelUsage, 0),
+	}
+	panels := make(map[int64]int64)
+	for _, row := range rows {
+		result.Views += row.Views
+		result.Queries += row.Queries
+		if n := len(result.Days); n > 0 && result.Days[n-1].Day == row.Day {
+			result.Days[n-1].Views += row.Views
+			result.Days[n-1].Queries += row.Queries
+		} else {
+			result.Days = append(result.Days, dashboardusage.DailyUsage{Day: row.Day, Views: row.Views, Queries: row.Queries})
+		}
+		if row.PanelID != 0 {
+			panels[row.PanelID] += row.Queries
+		}
+	}
+	for panelID, queries := range panels {
+		result.Panels = append(result.Panels, dashboardusage.PanelUsage{PanelID: panelID, Queries: queries})
+	}
+	sort.Slice(result.Panels, func(i, j int) bool {
+		return result.Panels[i].Queries > result.Panels[j].Queries ||
+			(result.Panels[i].Queries == result.Panels[j].Queries && result.Panels[i].PanelID < result.Panels[j].PanelID)
+	})
+
+	lastViewed, err := s.store.GetLastViewed(ctx, query.DashboardID)
+	if err != nil {
+		return nil, err
+	}
+	result.LastViewed = unixTime(lastViewed)
+	return result, nil
+}
+
+func (s *Service) SearchStaleDashboards(ctx context.Context, query *dashboardusage.SearchStaleDashboardsQuery) ([]*dashboardusage.StaleDashboard, error) {
+	days := query.Days
+	if days == 0 {
+		days = dashboardusage.DefaultStaleDays
+	}
+	if err := s.validateDays(days); err != nil {
+		return nil, err
+	}
+
+	res, err := s.dashboardService.FindDashboards(ctx, &dashboards.FindPersistedDashboardsQuery{
+		OrgId:        query.OrgID,
+		SignedInUser: query.User,
+		Type:         string(searchmodel.DashHitDB),
+		FolderUIDs:   query.FolderUIDs,
+		Limit:        query.Limit,
+		Page:         query.Page,
+		Permission:   dashboards.PERMISSION_VIEW,
+		Sort:         SortLastViewedAsc,
+		Filters:      []interface{}{staleFilter{Before: time.Now().AddDate(0, 0, -days)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the search returns a row per tag of each dashboard
+	result := make([]*dashboardusage.StaleDashboard, 0, len(res))
+	seen := make(map[int64]bool, len(res))
+	for _, item := range res {
+		if seen[item.ID] {
+			continue
+		}
+		seen[item.ID] = true
+		result = append(result, &dashboardusage.StaleDashboard{
+			UID:         item.UID,
+			Title:       item.Title,
+			URL:         dashboards.GetDashboardFolderURL(false, item.UID, item.Slug),
+			FolderUID:   item.FolderUID,
+			FolderTitle: item.FolderTitle,
+			LastViewed:  unixTime(item.SortMeta),
+		})
+	}
+	return result, nil
+}
+
+// validateDays checks that the usage of the days is still kept.
+func (s *Service) validateDays(days int) error {
+	if days < 1 {
+		return dashboardusage.ErrInvalidDays.Errorf("days must be positive, got %d", days)
+	}
+	if s.retention > 0 && time.Duration(days)*24*time.Hour > s.retention {
+		return dashboardusage.ErrInvalidDays.Errorf("%d days is longer than the retention %s", days, s.retention)
+	}
+	return nil
+}
+
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
@@ -0,0 +1,198 @@
+package dashboardusageimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	searchmodel "github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type testEnv struct {
+	service        *Service
+	sqlStore       db.DB
+	dashboardStore dashboards.Store
+}
+
+func setupTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	dashboardStore, err := database.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("FindDashboards", mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, query *dashboards.FindPersistedDashboardsQuery) ([]dashboards.DashboardSearchProjection, error) {
+			return dashboardStore.FindDashboards(ctx, query)
+		}).Maybe()
+
+	return &testEnv{
+		service: &Service{
+			store:            &xormStore{db: sqlStore},
+			dashboardService: dashboardService,
+			log:              log.NewNopLogger(),
+			enabled:          true,
+			retention:        365 * 24 * time.Hour,
+			buffer:           make(map[usageKey]*usageCounts),
+		},
+		sqlStore:       sqlStore,
+		dashboardStore: dashboardStore,
+	}
+}
+
+func (env *testEnv) insertDashboard(t *testing.T, title string, created time.Time) *dashboards.Dashboard {
+	t.Helper()
+
+	dash, err := env.dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+		OrgID:     1,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": title, "tags": []interface{}{"a", "b"}}),
+	})
+	require.NoError(t, err)
+	err = env.sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard SET created = ? WHERE id = ?", created, dash.ID)
+		return err
+	})
+	require.NoError(t, err)
+	return dash
+}
+
+func TestIntegrationDashboardUsageService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	viewer := &user.SignedInUser{
+		UserID:  1,
+		OrgID:   1,
+		OrgRole: org.RoleViewer,
+		Permissions: map[int64]map[string][]string{
+			1: {dashboards.ActionDashboardsRead: []string{dashboards.ScopeDashboardsAll}},
+		},
+	}
+
+	t.Run("record and get the usage of a dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		dash := env.insertDashboard(t, "Used", time.Now())
+
+		env.service.RecordView(ctx, &dashboardusage.RecordViewCommand{OrgID: 1, DashboardUID: dash.UID})
+		env.service.RecordView(ctx, &dashboardusage.RecordViewCommand{OrgID: 1, DashboardUID: dash.UID})
+		env.service.RecordQueries(ctx, &dashboardusage.RecordQueriesCommand{OrgID: 1, DashboardUID: dash.UID, PanelID: 2, Queries: 3})
+		env.service.RecordQueries(ctx, &dashboardusage.RecordQueriesCommand{OrgID: 1, DashboardUID: dash.UID, PanelID: 4, Queries: 5})
+		env.service.RecordQueries(ctx, &dashboardusage.RecordQueriesCommand{OrgID: 1, DashboardUID: dash.UID, PanelID: 2, Queries: 1})
+		// the usage of unknown dashboards and of other organizations is dropped
+		env.service.RecordView(ctx, &dashboardusage.RecordViewCommand{OrgID: 1, DashboardUID: "unknown"})
+		env.service.RecordView(ctx, &dashboardusage.RecordViewCommand{OrgID: 2, DashboardUID: dash.UID})
+		env.service.flush(ctx)
+		assert.Empty(t, env.service.buffer)
+
+		env.service.RecordView(ctx, &dashboardusage.RecordViewCommand{OrgID: 1, DashboardUID: dash.UID})
+		env.service.flush(ctx)
+
+		usage, err := env.service.GetDashboardUsage(ctx, &dashboardusage.GetDashboardUsageQuery{OrgID: 1, DashboardID: dash.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), usage.Views)
+		assert.Equal(t, int64(9), usage.Queries)
+		require.NotNil(t, usage.LastViewed)
+		assert.WithinDuration(t, time.Now(), *usage.LastViewed, time.Minute)
+		require.Len(t, usage.Days, 1)
+		assert.Equal(t, time.Now().UTC().Format(dayFormat), usage.Days[0].Day)
+		assert.Equal(t, int64(3), usage.Days[0].Views)
+		assert.Equal(t, []dashboardusage.PanelUsage{{PanelID: 4, Queries: 5}, {PanelID: 2, Queries: 4}}, usage.Panels)
+	})
+
+	t.Run("the number of days must be kept", func(t *testing.T) {
+		env := setupTestEnv(t)
+
+		_, err := env.service.GetDashboardUsage(ctx, &dashboardusage.GetDashboardUsageQuery{OrgID: 1, DashboardID: 1, Days: -1})
+		require.ErrorIs(t, err, dashboardusage.ErrInvalidDays)
+		_, err = env.service.SearchStaleDashboards(ctx, &dashboardusage.SearchStaleDashboardsQuery{OrgID: 1, User: viewer, Days: 400})
+		require.ErrorIs(t, err, dashboardusage.ErrInvalidDays)
+	})
+
+	t.Run("search stale dashboards", func(t *testing.T) {
+		env := setupTestEnv(t)
+		old := time.Now().AddDate(0, 0, -200)
+		neverViewed := env.insertDashboard(t, "Never viewed", old)
+		viewedLongAgo := env.insertDashboard(t, "Viewed long ago", old)
+		viewedRecently := env.insertDashboard(t, "Viewed recently", old)
+		env.insertDashboard(t, "Created recently", time.Now())
+
+		require.NoError(t, env.service.store.Add(ctx, &dailyUsage{
+			OrgID: 1, DashboardID: viewedLongAgo.ID, Day: old.UTC().Format(dayFormat), Views: 1, LastViewed: old.Unix(),
+		}))
+		env.service.RecordView(ctx, &dashboardusage.RecordViewCommand{OrgID: 1, DashboardUID: viewedRecently.UID})
+		env.service.flush(ctx)
+
+		result, err := env.service.SearchStaleDashboards(ctx, &dashboardusage.SearchStaleDashboardsQuery{OrgID: 1, User: viewer})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, neverViewed.UID, result[0].UID)
+		assert.Nil(t, result[0].LastViewed)
+		assert.Equal(t, viewedLongAgo.UID, result[1].UID)
+		assert.Equal(t, "/d/"+viewedLongAgo.UID+"/viewed-long-ago", result[1].URL)
+		require.NotNil(t, result[1].LastViewed)
+		assert.Equal(t, old.Unix(), result[1].LastViewed.Unix())
+
+		result, err = env.service.SearchStaleDashboards(ctx, &dashboardusage.SearchStaleDashboardsQuery{OrgID: 1, User: viewer, Days: 300})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("sort dashboards by their views", func(t *testing.T) {
+		env := setupTestEnv(t)
+		a := env.insertDashboard(t, "A", time.Now())
+		b := env.insertDashboard(t, "B", time.Now())
+		c := env.insertDashboard(t, "C", time.Now())
+
+		for i := 0; i < 2; i++ {
+			env.service.RecordView(ctx, &dashboardusage.RecordViewCommand{OrgID: 1, DashboardUID: b.UID})
+		}
+		env.service.RecordView(ctx, &dashboardusage.RecordViewCommand{OrgID: 1, DashboardUID: c.UID})
+		// views older than the sorted days are not counted
+		require.NoError(t, env.service.store.Add(ctx, &dailyUsage{
+			OrgID: 1, DashboardID: a.ID, Day: time.Now().UTC().AddDate(0, 0, -recentViewsDays).Format(dayFormat), Views: 10, LastViewed: 1,
+		}))
+		env.service.flush(ctx)
+
+		search := func(sort searchmodel.SortOption) []string {
+			t.Helper()
+			res, err := env.dashboardStore.FindDashboards(ctx, &dashboards.FindPersistedDashboardsQuery{
+				OrgId:        1,
+				SignedInUser: viewer,
+				Type:         string(searchmodel.DashHitDB),
+				Permission:   dashboards.PERMISSION_VIEW,
+				Sort:         sort,
+			})
+			require.NoError(t, err)
+			uids := make([]string, 0)
+			for _, item := range res {
+				if len(uids) == 0 || uids[len(uids)-1] != item.UID {
+					uids = append(uids, item.UID)
+				}
+			}
+			return uids
+		}
+
+		assert.Equal(t, []string{b.UID, c.UID, a.UID}, search(SortViewsRecentDesc))
+		assert.Equal(t, []string{a.UID, c.UID, b.UID}, search(SortViewsRecentAsc))
+		lastViewed := search(SortLastViewedAsc)
+		assert.Equal(t, a.UID, lastViewed[0])
+	})
+}
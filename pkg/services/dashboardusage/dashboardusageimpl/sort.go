@@ -0,0 +1,102 @@
+package dashboardusageimpl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/search/model"
+)
+
+var (
+	SortLastViewedDesc = model.SortOption{
+		Name:        "last-viewed-desc",
+		DisplayName: "Last viewed (most recent first)",
+		Description: "Sort results by the last time they were viewed, the most recent first",
+		Index:       1,
+		MetaName:    "lastViewed",
+		Filter:      []model.SortOptionFilter{lastViewedSorter{Descending: true}},
+	}
+	SortLastViewedAsc = model.SortOption{
+		Name:        "last-viewed-asc",
+		DisplayName: "Last viewed (least recent first)",
+		Description: "Sort results by the last time they were viewed, those never viewed first",
+		Index:       2,
+		MetaName:    "lastViewed",
+		Filter:      []model.SortOptionFilter{lastViewedSorter{}},
+	}
+	SortViewsRecentDesc = model.SortOption{
+		Name:        "views-recent-desc",
+		DisplayName: "Views in the last 30 days (most first)",
+		Description: "Sort results by the number of views in the last 30 days, the most viewed first",
+		Index:       3,
+		MetaName:    "views",
+		Filter:      []model.SortOptionFilter{recentViewsSorter{Descending: true}},
+	}
+	SortViewsRecentAsc = model.SortOption{
+		Name:        "views-recent-asc",
+		DisplayName: "Views in the last 30 days (fewest first)",
+		Description: "Sort results by the number of views in the last 30 days, the least viewed first",
+		Index:       4,
+		MetaName:    "views",
+		Filter:      []model.SortOptionFilter{recentViewsSorter{}},
+	}
+)
+
+// recentViewsDays is the number of days, including today, of the views used to sort dashboards.
+const recentViewsDays = 30
+
+func orderBy(column string, descending bool) string {
+	if descending {
+		return column + " DESC"
+	}
+	return column + " ASC"
+}
+
+// lastViewedSorter sorts the dashboards by their last view, the sort meta is the last view in seconds since epoch.
+// The join is also used by staleFilter.
+type lastViewedSorter struct {
+	Descending bool
+}
+
+func (s lastViewedSorter) LeftJoin() string {
+	return `(SELECT org_id, dashboard_id, MAX(last_viewed) AS last_viewed FROM dashboard_usage_daily WHERE panel_id = 0 GROUP BY org_id, dashboard_id)
+		AS dashboard_last_viewed ON dashboard_last_viewed.org_id = dashboard.org_id AND dashboard_last_viewed.dashboard_id = dashboard.id`
+}
+
+func (s lastViewedSorter) OrderBy() string {
+	return orderBy("COALESCE(dashboard_last_viewed.last_viewed, 0)", s.Descending)
+}
+
+func (s lastViewedSorter) Select() string {
+	return "COALESCE(dashboard_last_viewed.last_viewed, 0) AS sort_meta"
+}
+
+// recentViewsSorter sorts the dashboards by their views in the last days, the sort meta is the number of views.
+type recentViewsSorter struct {
+	Descending bool
+}
+
+func (s recentViewsSorter) LeftJoin() string {
+	// the first day is computed for each search, it is a formatted date and not user input
+	since := time.Now().UTC().AddDate(0, 0, 1-recentViewsDays).Format(dayFormat)
+	return fmt.Sprintf(`(SELECT org_id, dashboard_id, SUM(views) AS views FROM dashboard_usage_daily WHERE panel_id = 0 AND day >= '%s' GROUP BY org_id, dashboard_id)
+		AS dashboard_recent_views ON dashboard_recent_views.org_id = dashboard.org_id AND dashboard_recent_views.dashboard_id = dashboard.id`, since)
+}
+
+func (s recentViewsSorter) OrderBy() string {
+	return orderBy("COALESCE(dashboard_recent_views.views, 0)", s.Descending)
+}
+
+func (s recentViewsSorter) Select() string {
+	return "COALESCE(dashboard_recent_views.views, 0) AS sort_meta"
+}
+
+// staleFilter keeps the dashboards created and last viewed before a time. It must be used with lastViewedSorter,
+// which joins the last views.
+type staleFilter struct {
+	Before time.Time
+}
+
+func (f staleFilter) Where() (string, []interface{}) {
+	return "dashboard.created < ? AND COALESCE(dashboard_last_viewed.last_viewed, 0) < ?", []interface{}{f.Before, f.Before.Unix()}
+}
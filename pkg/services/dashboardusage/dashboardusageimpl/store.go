@@ -0,0 +1,96 @@
+package dashboardusageimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store interface {
+	// GetDashboardIDs returns the IDs of the dashboards of an organization by UID, the unknown UIDs are left out.
+	GetDashboardIDs(ctx context.Context, orgID int64, uids []string) (map[string]int64, error)
+	// Add adds the usage to the usage already saved for the same dashboard, panel and day.
+	Add(ctx context.Context, usage *dailyUsage) error
+	GetUsage(ctx context.Context, dashboardID int64, since string) ([]*dailyUsage, error)
+	// GetLastViewed returns the last view of a dashboard in seconds since epoch, 0 if it was never viewed.
+	GetLastViewed(ctx context.Context, dashboardID int64) (int64, error)
+	DeleteOlderThan(ctx context.Context, day string) (int64, error)
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (xs *xormStore) GetDashboardIDs(ctx context.Context, orgID int64, uids []string) (map[string]int64, error) {
+	result := make(map[string]int64, len(uids))
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []struct {
+			ID  int64  `xorm:"id"`
+			UID string `xorm:"uid"`
+		}
+		err := sess.Table("dashboard").Cols("id", "uid").
+			Where("org_id = ? AND is_folder = "+xs.db.GetDialect().BooleanStr(false), orgID).
+			In("uid", uids).Find(&rows)
+		for _, row := range rows {
+			result[row.UID] = row.ID
+		}
+		return err
+	})
+	return result, err
+}
+
+func (xs *xormStore) Add(ctx context.Context, usage *dailyUsage) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		update := func() (int64, error) {
+			res, err := sess.Exec(`UPDATE dashboard_usage_daily SET views = views + ?, queries = queries + ?,
+				last_viewed = CASE WHEN last_viewed < ? THEN ? ELSE last_viewed END
+				WHERE dashboard_id = ? AND panel_id = ? AND day = ?`,
+				usage.Views, usage.Queries, usage.LastViewed, usage.LastViewed, usage.DashboardID, usage.PanelID, usage.Day)
+			if err != nil {
+				return 0, err
+			}
+			return res.RowsAffected()
+		}
+
+		affected, err := update()
+		if err != nil || affected > 0 {
+			return err
+		}
+		_, err = sess.Insert(usage)
+		if err != nil && xs.db.GetDialect().IsUniqueConstraintViolation(err) {
+			// another instance saved the usage of the same day in the meantime
+			_, err = update()
+		}
+		return err
+	})
+}
+
+func (xs *xormStore) GetUsage(ctx context.Context, dashboardID int64, since string) ([]*dailyUsage, error) {
+	result := make([]*dailyUsage, 0)
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("dashboard_id = ? AND day >= ?", dashboardID, since).Asc("day", "panel_id").Find(&result)
+	})
+	return result, err
+}
+
+func (xs *xormStore) GetLastViewed(ctx context.Context, dashboardID int64) (int64, error) {
+	var lastViewed int64
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.SQL("SELECT COALESCE(MAX(last_viewed), 0) FROM dashboard_usage_daily WHERE dashboard_id = ? AND panel_id = 0", dashboardID).Get(&lastViewed)
+		return err
+	})
+	return lastViewed, err
+}
+
+func (xs *xormStore) DeleteOlderThan(ctx context.Context, day string) (int64, error) {
+	var affected int64
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM dashboard_usage_daily WHERE day < ?", day)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
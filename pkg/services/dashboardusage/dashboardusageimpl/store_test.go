@@ -0,0 +1,61 @@
+package dashboardusageimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+func TestIntegrationDashboardUsageStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := &xormStore{db: db.InitTestDB(t)}
+
+	t.Run("add sums the usage of the same dashboard, panel and day", func(t *testing.T) {
+		require.NoError(t, s.Add(ctx, &dailyUsage{OrgID: 1, DashboardID: 1, Day: "2023-09-03", Views: 2, LastViewed: 200}))
+		require.NoError(t, s.Add(ctx, &dailyUsage{OrgID: 1, DashboardID: 1, Day: "2023-09-03", Views: 1, LastViewed: 100}))
+		require.NoError(t, s.Add(ctx, &dailyUsage{OrgID: 1, DashboardID: 1, Day: "2023-09-04", Views: 1, LastViewed: 300}))
+		require.NoError(t, s.Add(ctx, &dailyUsage{OrgID: 1, DashboardID: 1, PanelID: 2, Day: "2023-09-04", Queries: 3}))
+		require.NoError(t, s.Add(ctx, &dailyUsage{OrgID: 1, DashboardID: 1, PanelID: 2, Day: "2023-09-04", Queries: 4}))
+		require.NoError(t, s.Add(ctx, &dailyUsage{OrgID: 1, DashboardID: 2, Day: "2023-09-04", Views: 5, LastViewed: 400}))
+
+		rows, err := s.GetUsage(ctx, 1, "2023-09-01")
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, "2023-09-03", rows[0].Day)
+		assert.Equal(t, int64(3), rows[0].Views)
+		assert.Equal(t, int64(200), rows[0].LastViewed, "the last view is kept")
+		assert.Equal(t, int64(0), rows[2].Views)
+		assert.Equal(t, int64(2), rows[2].PanelID)
+		assert.Equal(t, int64(7), rows[2].Queries)
+
+		rows, err = s.GetUsage(ctx, 1, "2023-09-04")
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		lastViewed, err := s.GetLastViewed(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(300), lastViewed)
+
+		lastViewed, err = s.GetLastViewed(ctx, 3)
+		require.NoError(t, err)
+		assert.Zero(t, lastViewed)
+	})
+
+	t.Run("delete the usage older than a day", func(t *testing.T) {
+		deleted, err := s.DeleteOlderThan(ctx, "2023-09-04")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		rows, err := s.GetUsage(ctx, 1, "2023-09-01")
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+	})
+}
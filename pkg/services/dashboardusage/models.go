@@ -0,0 +1,78 @@
+package dashboardusage
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var ErrInvalidDays = errutil.BadRequest("dashboardusage.invalid-days", errutil.WithPublicMessage("The number of days is invalid or longer than the usage is kept"))
+
+const (
+	// DefaultUsageDays is the number of days of the usage of a dashboard returned by default.
+	DefaultUsageDays = 30
+	// DefaultStaleDays is the number of days without views after which a dashboard is stale by default.
+	DefaultStaleDays = 90
+)
+
+type RecordViewCommand struct {
+	OrgID        int64
+	DashboardUID string
+}
+
+type RecordQueriesCommand struct {
+	OrgID        int64
+	DashboardUID string
+	// PanelID is 0 for the queries that do not belong to a panel.
+	PanelID int64
+	Queries int
+}
+
+type GetDashboardUsageQuery struct {
+	OrgID       int64
+	DashboardID int64
+	// Days is the number of days of usage, including today.
+	Days int
+}
+
+// DashboardUsage is the usage of a dashboard over a number of days.
+type DashboardUsage struct {
+	Views   int64 `json:"views"`
+	Queries int64 `json:"queries"`
+	// LastViewed is the last time the dashboard was viewed, even before the days of the usage.
+	LastViewed *time.Time   `json:"lastViewed,omitempty"`
+	Days       []DailyUsage `json:"days"`
+	Panels     []PanelUsage `json:"panels"`
+}
+
+type DailyUsage struct {
+	// Day is the day in UTC, formatted as 2006-01-02.
+	Day     string `json:"day"`
+	Views   int64  `json:"views"`
+	Queries int64  `json:"queries"`
+}
+
+type PanelUsage struct {
+	PanelID int64 `json:"panelId"`
+	Queries int64 `json:"queries"`
+}
+
+type SearchStaleDashboardsQuery struct {
+	OrgID int64
+	User  *user.SignedInUser
+	// Days is the number of days without views, dashboards created more recently are not stale.
+	Days       int
+	FolderUIDs []string
+	Limit      int64
+	Page       int64
+}
+
+type StaleDashboard struct {
+	UID         string     `json:"uid"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	FolderUID   string     `json:"folderUid,omitempty"`
+	FolderTitle string     `json:"folderTitle,omitempty"`
+	LastViewed  *time.Time `json:"lastViewed,omitempty"`
+}
@@ -103,7 +103,12 @@ func (s *FakeDataSourceService) UpdateDataSource(ctx context.Context, cmd *datas
 }
 
 func (s *FakeDataSourceService) GetDefaultDataSource(ctx context.Context, query *datasources.GetDefaultDataSourceQuery) (*datasources.DataSource, error) {
-	return nil, nil
+	for _, dataSource := range s.DataSources {
+		if dataSource.OrgID == query.OrgID && dataSource.IsDefault {
+			return dataSource, nil
+		}
+	}
+	return nil, datasources.ErrDataSourceNotFound
 }
 
 func (s *FakeDataSourceService) GetHTTPTransport(ctx context.Context, ds *datasources.DataSource, provider httpclient.Provider, customMiddlewares ...sdkhttpclient.Middleware) (http.RoundTripper, error) {
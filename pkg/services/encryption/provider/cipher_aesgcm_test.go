@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/encryption"
+)
+
+func Test_aesGcmCipher(t *testing.T) {
+	cipher := aesGcmCipher{}
+	decipher := aesDecipher{algorithm: encryption.AesGcm}
+	ctx := context.Background()
+
+	encrypted, err := cipher.Encrypt(ctx, []byte("grafana"), "1234")
+	require.NoError(t, err)
+	assert.NotEmpty(t, encrypted)
+
+	t.Run("should be decrypted with the same secret", func(t *testing.T) {
+		decrypted, err := decipher.Decrypt(ctx, encrypted, "1234")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("grafana"), decrypted)
+	})
+
+	t.Run("should not be decrypted with another secret", func(t *testing.T) {
+		_, err := decipher.Decrypt(ctx, encrypted, "4321")
+		assert.Error(t, err)
+	})
+
+	t.Run("should not be decrypted once tampered with", func(t *testing.T) {
+		tampered := append([]byte{}, encrypted...)
+		tampered[len(tampered)-1] ^= 1
+
+		_, err := decipher.Decrypt(ctx, tampered, "1234")
+		assert.Error(t, err)
+	})
+}
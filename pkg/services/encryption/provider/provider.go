@@ -13,6 +13,7 @@ func ProvideEncryptionProvider() Provider {
 func (p Provider) ProvideCiphers() map[string]encryption.Cipher {
 	return map[string]encryption.Cipher{
 		encryption.AesCfb: aesCfbCipher{},
+		encryption.AesGcm: aesGcmCipher{},
 	}
 }
 
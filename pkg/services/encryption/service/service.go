@@ -181,6 +181,9 @@ func (s *Service) Encrypt(ctx context.Context, payload []byte, secret string) ([
 
 	var encrypted []byte
 	encrypted, err = cipher.Encrypt(ctx, payload, secret)
+	if err != nil {
+		return nil, err
+	}
 
 	prefix := make([]byte, base64.RawStdEncoding.EncodedLen(len([]byte(algorithm)))+2)
 	base64.RawStdEncoding.Encode(prefix[1:], []byte(algorithm))
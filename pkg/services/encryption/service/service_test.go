@@ -54,11 +54,22 @@ func Test_Service(t *testing.T) {
 		assert.Equal(t, []byte("grafana"), decrypted)
 	})
 
-	t.Run("encrypt with aes-gcm should fail", func(t *testing.T) {
+	t.Run("encrypt with aes-gcm should work", func(t *testing.T) {
 		settings.Raw.Section(securitySection).Key(encryptionAlgorithmKey).SetValue(encryption.AesGcm)
+		t.Cleanup(func() {
+			settings.Raw.Section(securitySection).Key(encryptionAlgorithmKey).SetValue(encryption.AesCfb)
+		})
 
-		_, err := svc.Encrypt(ctx, []byte("grafana"), "1234")
-		require.Error(t, err)
+		encrypted, err := svc.Encrypt(ctx, []byte("grafana"), "1234")
+		require.NoError(t, err)
+
+		algorithm, _, err := svc.deriveEncryptionAlgorithm(encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, encryption.AesGcm, algorithm)
+
+		decrypted, err := svc.Decrypt(ctx, encrypted, "1234")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("grafana"), decrypted)
 	})
 
 	t.Run("decrypting legacy ciphertext should work", func(t *testing.T) {
@@ -0,0 +1,178 @@
+// Package externalprovider implements a key encryption key provider backed by an external key manager.
+//
+// The key manager is reached through a Vault transit-compatible HTTP API, either on a network address or on a
+// local Unix socket. The latter allows to plug in any key manager, like an HSM through a PKCS#11 bridge, by running
+// a small process next to Grafana that exposes the encrypt, decrypt and rotate endpoints of the transit API.
+package externalprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// Kind is the kind of the external provider identifiers, like external.<KEY-NAME>.
+	Kind = "external"
+
+	unixScheme    = "unix://"
+	maxBodyLength = 1 << 20
+)
+
+type externalProvider struct {
+	client     *http.Client
+	baseURL    string
+	enginePath string
+	keyRing    string
+	token      string
+	namespace  string
+}
+
+// New returns the external provider configured by the section of the given provider identifier,
+// for example [security.encryption.external.hsm] for external.hsm.
+func New(cfg *setting.Cfg, id secrets.ProviderID) (secrets.Provider, error) {
+	section := cfg.SectionWithEnvOverrides(fmt.Sprintf("security.encryption.%s", id))
+
+	rawURL := strings.TrimSpace(section.Key("url").String())
+	if rawURL == "" {
+		return nil, fmt.Errorf("missing url for encryption provider %s", id)
+	}
+
+	p := &externalProvider{
+		enginePath: strings.Trim(section.Key("transit_engine_path").MustString("transit"), "/"),
+		keyRing:    section.Key("key_ring").String(),
+		token:      section.Key("token").String(),
+		namespace:  section.Key("namespace").String(),
+	}
+	if p.keyRing == "" {
+		return nil, fmt.Errorf("missing key_ring for encryption provider %s", id)
+	}
+
+	timeout := section.Key("timeout").MustDuration(10 * time.Second)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if strings.HasPrefix(rawURL, unixScheme) {
+		socket := strings.TrimPrefix(rawURL, unixScheme)
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		// The host is ignored when dialing the socket.
+		p.baseURL = "http://localhost"
+	} else {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid url %q for encryption provider %s: expected http(s)://<host> or unix://<path>", rawURL, id)
+		}
+		p.baseURL = strings.TrimSuffix(rawURL, "/")
+	}
+
+	p.client = &http.Client{Transport: transport, Timeout: timeout}
+
+	return p, nil
+}
+
+type transitData struct {
+	Plaintext  string `json:"plaintext,omitempty"`
+	Ciphertext string `json:"ciphertext,omitempty"`
+}
+
+type transitResponse struct {
+	Data   transitData `json:"data"`
+	Errors []string    `json:"errors"`
+}
+
+// Encrypt wraps the data key with the latest version of the key encryption key.
+func (p *externalProvider) Encrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	res, err := p.do(ctx, "encrypt/"+url.PathEscape(p.keyRing), &transitData{Plaintext: base64.StdEncoding.EncodeToString(blob)})
+	if err != nil {
+		return nil, err
+	}
+	if res.Data.Ciphertext == "" {
+		return nil, errors.New("external key manager returned an empty ciphertext")
+	}
+	return []byte(res.Data.Ciphertext), nil
+}
+
+// Decrypt unwraps a data key wrapped with any version of the key encryption key that the key manager still holds.
+func (p *externalProvider) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	res, err := p.do(ctx, "decrypt/"+url.PathEscape(p.keyRing), &transitData{Ciphertext: string(blob)})
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(res.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("external key manager returned an invalid plaintext: %w", err)
+	}
+	return plaintext, nil
+}
+
+// RotateKey asks the key manager for a new version of the key encryption key.
+// The data keys wrapped with previous versions are re-wrapped by re-encrypting them.
+func (p *externalProvider) RotateKey(ctx context.Context) error {
+	_, err := p.do(ctx, "keys/"+url.PathEscape(p.keyRing)+"/rotate", nil)
+	return err
+}
+
+func (p *externalProvider) do(ctx context.Context, path string, body *transitData) (*transitResponse, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/%s/%s", p.baseURL, p.enginePath, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("X-Vault-Token", p.token)
+	}
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach external key manager: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyLength))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read external key manager response: %w", err)
+	}
+
+	res := &transitResponse{}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// The body of an error response is only informative, and may not even come from the key manager.
+		if json.Unmarshal(raw, res) == nil && len(res.Errors) > 0 {
+			return nil, fmt.Errorf("external key manager responded with status %d: %s", resp.StatusCode, strings.Join(res.Errors, "; "))
+		}
+		return nil, fmt.Errorf("external key manager responded with status %d", resp.StatusCode)
+	}
+
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, res); err != nil {
+			return nil, fmt.Errorf("failed to decode external key manager response: %w", err)
+		}
+	}
+
+	return res, nil
+}
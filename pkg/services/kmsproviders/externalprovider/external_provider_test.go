@@ -0,0 +1,183 @@
+package externalprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeTransit is a minimal Vault transit-compatible key manager. Ciphertexts are not encrypted,
+// they only carry the version of the key they were "wrapped" with.
+type fakeTransit struct {
+	mu      sync.Mutex
+	version int
+	token   string
+	fail    bool
+}
+
+func (f *fakeTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	writeErr := func(status int, msg string) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(transitResponse{Errors: []string{msg}})
+	}
+
+	if f.fail {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("<html>bad gateway</html>"))
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != f.token {
+		writeErr(http.StatusForbidden, "permission denied")
+		return
+	}
+
+	var body transitData
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	switch r.URL.Path {
+	case "/v1/transit/encrypt/grafana":
+		_ = json.NewEncoder(w).Encode(transitResponse{Data: transitData{
+			Ciphertext: fmt.Sprintf("vault:v%d:%s", f.version, body.Plaintext),
+		}})
+	case "/v1/transit/decrypt/grafana":
+		parts := strings.SplitN(body.Ciphertext, ":", 3)
+		if len(parts) != 3 {
+			writeErr(http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		if v, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v")); err != nil || v > f.version {
+			writeErr(http.StatusBadRequest, "invalid key version")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(transitResponse{Data: transitData{Plaintext: parts[2]}})
+	case "/v1/transit/keys/grafana/rotate":
+		f.version++
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErr(http.StatusNotFound, "unsupported path")
+	}
+}
+
+func setupProvider(t *testing.T, url string) secrets.Provider {
+	t.Helper()
+
+	raw, err := ini.Load([]byte(`
+		[security.encryption.external.test]
+		url = ` + url + `
+		key_ring = grafana
+		token = s3cr3t`))
+	require.NoError(t, err)
+
+	p, err := New(&setting.Cfg{Raw: raw}, "external.test")
+	require.NoError(t, err)
+	return p
+}
+
+func TestExternalProvider(t *testing.T) {
+	ctx := context.Background()
+	transit := &fakeTransit{version: 1, token: "s3cr3t"}
+	srv := httptest.NewServer(transit)
+	t.Cleanup(srv.Close)
+
+	p := setupProvider(t, srv.URL)
+
+	t.Run("should wrap and unwrap data keys", func(t *testing.T) {
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		assert.Equal(t, "vault:v1:"+base64.StdEncoding.EncodeToString([]byte("data key")), string(encrypted))
+
+		decrypted, err := p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), decrypted)
+	})
+
+	t.Run("should wrap data keys with the latest key version after a rotation", func(t *testing.T) {
+		previous, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+
+		rotator, ok := p.(secrets.KeyRotator)
+		require.True(t, ok)
+		require.NoError(t, rotator.RotateKey(ctx))
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(encrypted), "vault:v2:"))
+
+		decrypted, err := p.Decrypt(ctx, previous)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), decrypted)
+	})
+
+	t.Run("should return the errors of the key manager", func(t *testing.T) {
+		_, err := p.Decrypt(ctx, []byte("invalid"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid ciphertext")
+
+		transit.fail = true
+		defer func() { transit.fail = false }()
+		_, err = p.Encrypt(ctx, []byte("data key"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 502")
+	})
+}
+
+func TestExternalProvider_UnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "kms.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(&fakeTransit{version: 1, token: "s3cr3t"})
+	srv.Listener = listener
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	p := setupProvider(t, "unix://"+socket)
+
+	encrypted, err := p.Encrypt(context.Background(), []byte("data key"))
+	require.NoError(t, err)
+	decrypted, err := p.Decrypt(context.Background(), encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data key"), decrypted)
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config string
+		err    string
+	}{
+		{desc: "missing url", config: "key_ring = grafana", err: "missing url"},
+		{desc: "missing key ring", config: "url = http://localhost:8200", err: "missing key_ring"},
+		{desc: "invalid url", config: "url = ftp://localhost\nkey_ring = grafana", err: "invalid url"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			raw, err := ini.Load([]byte("[security.encryption.external.test]\n" + tc.config))
+			require.NoError(t, err)
+
+			_, err = New(&setting.Cfg{Raw: raw}, "external.test")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/kmsproviders"
 	grafana "github.com/grafana/grafana/pkg/services/kmsproviders/defaultprovider"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/externalprovider"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 type Service struct {
@@ -24,7 +26,28 @@ func ProvideService(enc encryption.Internal, cfg *setting.Cfg, features featurem
 }
 
 func (s Service) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
-	return map[secrets.ProviderID]secrets.Provider{
+	providers := map[secrets.ProviderID]secrets.Provider{
 		kmsproviders.Default: grafana.New(s.cfg, s.enc),
-	}, nil
+	}
+
+	// Only the external providers are available in OSS, other kinds are ignored.
+	available := s.cfg.SectionWithEnvOverrides("security").Key("available_encryption_providers").String()
+	for _, id := range util.SplitString(available) {
+		providerID := secrets.ProviderID(id)
+		kind, err := providerID.Kind()
+		if err != nil {
+			return nil, err
+		}
+		if kind != externalprovider.Kind {
+			continue
+		}
+
+		provider, err := externalprovider.New(s.cfg, providerID)
+		if err != nil {
+			return nil, err
+		}
+		providers[providerID] = provider
+	}
+
+	return providers, nil
 }
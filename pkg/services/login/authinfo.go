@@ -44,6 +44,7 @@ const (
 	JWTModule           = "jwt"
 	ExtendedJWTModule   = "extendedjwt"
 	RenderModule        = "render"
+	ClientCertModule    = "clientcert"
 	// OAuth provider modules
 	AzureADAuthModule    = "oauth_azuread"
 	GoogleAuthModule     = "oauth_google"
@@ -55,9 +56,10 @@ const (
 	OktaAuthModule       = "oauth_okta"
 
 	// labels
-	SAMLLabel = "SAML"
-	LDAPLabel = "LDAP"
-	JWTLabel  = "JWT"
+	SAMLLabel       = "SAML"
+	LDAPLabel       = "LDAP"
+	JWTLabel        = "JWT"
+	ClientCertLabel = "Client certificate"
 	// OAuth provider labels
 	AuthProxyLabel    = "Auth Proxy"
 	AzureADLabel      = "AzureAD"
@@ -88,6 +90,8 @@ func IsExternallySynced(cfg *setting.Cfg, authModule string) bool {
 		return !cfg.LDAPSkipOrgRoleSync
 	case JWTModule:
 		return !cfg.JWTAuthSkipOrgRoleSync
+	case ClientCertModule:
+		return !cfg.ClientCertAuthSkipOrgRoleSync
 	}
 	// then check the rest of the oauth providers
 	// FIXME: remove this once we remove the setting
@@ -142,6 +146,8 @@ func IsProviderEnabled(cfg *setting.Cfg, authModule string) bool {
 		return cfg.LDAPAuthEnabled
 	case JWTModule:
 		return cfg.JWTAuthEnabled
+	case ClientCertModule:
+		return cfg.ClientCertAuthEnabled
 	case GoogleAuthModule:
 		return cfg.GoogleAuthEnabled
 	case OktaAuthModule:
@@ -181,6 +187,8 @@ func GetAuthProviderLabel(authModule string) string {
 		return LDAPLabel
 	case JWTModule:
 		return JWTLabel
+	case ClientCertModule:
+		return ClientCertLabel
 	case AuthProxyAuthModule:
 		return AuthProxyLabel
 	case GenericOAuthModule:
@@ -5,11 +5,12 @@ import (
 )
 
 type Service interface {
-	// Add adds a new login attempt record for provided username
+	// Add adds a new login attempt record for provided username and IP address
 	Add(ctx context.Context, username, IPAddress string) error
-	// Validate checks if username has to many login attempts inside a window.
-	// Will return true if provided username do not have too many attempts.
-	Validate(ctx context.Context, username string) (bool, error)
+	// Validate checks if username has too many login attempts from the IP address,
+	// or if the IP address has too many login attempts for any username.
+	// Will return true if the login should be allowed.
+	Validate(ctx context.Context, username, IPAddress string) (bool, error)
 	// Reset resets all login attempts attached to username
 	Reset(ctx context.Context, username string) error
 }
@@ -0,0 +1,80 @@
+package loginattemptimpl
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/admin/login-attempts", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/lockouts", authorize(ac.EvalPermission(ActionRead)), routing.Wrap(s.handleGetLockouts))
+		subrouter.Delete("/lockouts", authorize(ac.EvalPermission(ActionDelete)), routing.Wrap(s.handleClearLockout))
+	})
+}
+
+// swagger:route GET /admin/login-attempts/lockouts admin_login_attempts getLoginLockouts
+//
+// List login lockouts.
+//
+// Returns the username and IP address pairs, and the IP addresses, that are currently blocked
+// by the brute force login protection, the longest lockouts first.
+//
+// Responses:
+// 200: getLoginLockoutsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleGetLockouts(c *contextmodel.ReqContext) response.Response {
+	lockouts, err := s.Lockouts(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get login lockouts", err)
+	}
+	return response.JSON(http.StatusOK, lockouts)
+}
+
+// swagger:route DELETE /admin/login-attempts/lockouts admin_login_attempts clearLoginLockout
+//
+// Clear login lockouts.
+//
+// Deletes the failed login attempts of the `username` and/or the `ipAddress` query parameters,
+// at least one of them is required.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleClearLockout(c *contextmodel.ReqContext) response.Response {
+	err := s.ClearLockout(c.Req.Context(), c.Query("username"), c.Query("ipAddress"))
+	if err != nil {
+		if errors.Is(err, errMissingFilter) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to clear login lockout", err)
+	}
+	return response.Success("Login lockout cleared")
+}
+
+// swagger:response getLoginLockoutsResponse
+type GetLoginLockoutsResponse struct {
+	// in:body
+	Body []Lockout `json:"body"`
+}
+
+// swagger:parameters clearLoginLockout
+type ClearLoginLockoutParams struct {
+	// in:query
+	// required:false
+	Username string `json:"username"`
+	// in:query
+	// required:false
+	IPAddress string `json:"ipAddress"`
+}
@@ -2,33 +2,72 @@ package loginattemptimpl
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-const (
-	maxInvalidLoginAttempts int64 = 5
-	loginAttemptsWindow           = time.Minute * 5
-)
+var errMissingFilter = errors.New("a username or an IP address is required")
+
+func ProvideService(
+	db db.DB,
+	cfg *setting.Cfg,
+	lock *serverlock.ServerLockService,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	routeRegister routing.RouteRegister,
+	registerer prometheus.Registerer,
+) (*Service, error) {
+	allowedNetworks, err := parseCIDRs(cfg.BruteForceLoginProtectionAllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
 
-func ProvideService(db db.DB, cfg *setting.Cfg, lock *serverlock.ServerLockService) *Service {
-	return &Service{
-		&xormStore{db: db, now: time.Now},
-		cfg,
-		lock,
-		log.New("login_attempt"),
+	s := &Service{
+		store:           &xormStore{db: db, now: time.Now},
+		cfg:             cfg,
+		lock:            lock,
+		logger:          log.New("login_attempt"),
+		metrics:         newMetrics(registerer),
+		accessControl:   accessControl,
+		allowedNetworks: allowedNetworks,
+		now:             time.Now,
 	}
+
+	if !cfg.DisableBruteForceLoginProtection {
+		if err := declareFixedRoles(accesscontrolService); err != nil {
+			return nil, err
+		}
+		s.registerAPIEndpoints(routeRegister)
+	}
+
+	return s, nil
 }
 
 type Service struct {
-	store  store
-	cfg    *setting.Cfg
-	lock   *serverlock.ServerLockService
-	logger log.Logger
+	store   store
+	cfg     *setting.Cfg
+	lock    *serverlock.ServerLockService
+	logger  log.Logger
+	metrics *metrics
+
+	accessControl ac.AccessControl
+	// allowedNetworks are trusted networks, typically proxies or offices shared by many users,
+	// where the IP address is not throttled across usernames.
+	allowedNetworks []*net.IPNet
+	now             func() time.Time
 }
 
 func (s *Service) Run(ctx context.Context) error {
@@ -53,43 +92,192 @@ func (s *Service) Add(ctx context.Context, username, IPAddress string) error {
 		return nil
 	}
 
+	s.metrics.failedAttempts.Inc()
 	_, err := s.store.CreateLoginAttempt(ctx, CreateLoginAttemptCommand{
 		Username:  username,
-		IpAddress: IPAddress,
+		IpAddress: normalizeIPAddress(IPAddress),
 	})
 	return err
 }
 
 func (s *Service) Reset(ctx context.Context, username string) error {
-	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{username})
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{Username: username})
 }
 
-func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
+func (s *Service) Validate(ctx context.Context, username, IPAddress string) (bool, error) {
 	if s.cfg.DisableBruteForceLoginProtection {
 		return true, nil
 	}
 
-	loginAttemptCountQuery := GetUserLoginAttemptCountQuery{
-		Username: username,
-		Since:    time.Now().Add(-loginAttemptsWindow),
-	}
+	IPAddress = normalizeIPAddress(IPAddress)
+	since := s.now().Add(-s.cfg.BruteForceLoginProtectionMaxLockout)
 
-	count, err := s.store.GetUserLoginAttemptCount(ctx, loginAttemptCountQuery)
+	stats, err := s.store.GetLoginAttemptStats(ctx, GetLoginAttemptStatsQuery{
+		Username:  username,
+		IpAddress: IPAddress,
+		Since:     since,
+	})
 	if err != nil {
 		return false, err
 	}
+	if s.isLocked(stats, s.cfg.BruteForceLoginProtectionMaxAttempts) {
+		s.metrics.blockedAttempts.WithLabelValues(LockoutReasonUser).Inc()
+		return false, nil
+	}
 
-	if count >= maxInvalidLoginAttempts {
+	// a username targeted from many IP addresses is locked out whatever the IP address
+	if s.cfg.BruteForceLoginProtectionMaxAttemptsPerUser > 0 && username != "" {
+		stats, err = s.store.GetLoginAttemptStats(ctx, GetLoginAttemptStatsQuery{
+			Username: username,
+			Since:    since,
+		})
+		if err != nil {
+			return false, err
+		}
+		if s.isLocked(stats, s.cfg.BruteForceLoginProtectionMaxAttemptsPerUser) {
+			s.metrics.blockedAttempts.WithLabelValues(LockoutReasonUsername).Inc()
+			return false, nil
+		}
+	}
+
+	if s.cfg.BruteForceLoginProtectionMaxAttemptsPerIP <= 0 || IPAddress == "" || s.isAllowed(IPAddress) {
+		return true, nil
+	}
+
+	stats, err = s.store.GetLoginAttemptStats(ctx, GetLoginAttemptStatsQuery{
+		IpAddress: IPAddress,
+		Since:     since,
+	})
+	if err != nil {
+		return false, err
+	}
+	if s.isLocked(stats, s.cfg.BruteForceLoginProtectionMaxAttemptsPerIP) {
+		s.metrics.blockedAttempts.WithLabelValues(LockoutReasonIP).Inc()
 		return false, nil
 	}
 
 	return true, nil
 }
 
+// Lockouts returns the username and IP address pairs, the usernames, and the IP addresses, currently locked out.
+func (s *Service) Lockouts(ctx context.Context) ([]Lockout, error) {
+	stats, err := s.store.ListLoginAttemptStats(ctx, ListLoginAttemptStatsQuery{
+		Since: s.now().Add(-s.cfg.BruteForceLoginProtectionMaxLockout),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Lockout, 0)
+	byUsername := map[string]LoginAttemptStats{}
+	byIPAddress := map[string]LoginAttemptStats{}
+	for _, st := range stats {
+		if s.isLocked(st, s.cfg.BruteForceLoginProtectionMaxAttempts) {
+			result = append(result, Lockout{
+				Reason:      LockoutReasonUser,
+				Username:    st.Username,
+				IPAddress:   st.IpAddress,
+				Attempts:    st.Count,
+				LockedUntil: s.lockedUntil(st, s.cfg.BruteForceLoginProtectionMaxAttempts),
+			})
+		}
+
+		byUsername[st.Username] = addStats(byUsername[st.Username], st)
+		byIPAddress[st.IpAddress] = addStats(byIPAddress[st.IpAddress], st)
+	}
+
+	if s.cfg.BruteForceLoginProtectionMaxAttemptsPerUser > 0 {
+		for username, st := range byUsername {
+			if username == "" || !s.isLocked(st, s.cfg.BruteForceLoginProtectionMaxAttemptsPerUser) {
+				continue
+			}
+			result = append(result, Lockout{
+				Reason:      LockoutReasonUsername,
+				Username:    username,
+				Attempts:    st.Count,
+				LockedUntil: s.lockedUntil(st, s.cfg.BruteForceLoginProtectionMaxAttemptsPerUser),
+			})
+		}
+	}
+
+	if s.cfg.BruteForceLoginProtectionMaxAttemptsPerIP > 0 {
+		for ip, st := range byIPAddress {
+			if ip == "" || s.isAllowed(ip) || !s.isLocked(st, s.cfg.BruteForceLoginProtectionMaxAttemptsPerIP) {
+				continue
+			}
+			result = append(result, Lockout{
+				Reason:      LockoutReasonIP,
+				IPAddress:   ip,
+				Attempts:    st.Count,
+				LockedUntil: s.lockedUntil(st, s.cfg.BruteForceLoginProtectionMaxAttemptsPerIP),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LockedUntil.After(result[j].LockedUntil)
+	})
+	return result, nil
+}
+
+// ClearLockout deletes the login attempts of the username and/or the IP address.
+func (s *Service) ClearLockout(ctx context.Context, username, IPAddress string) error {
+	if username == "" && IPAddress == "" {
+		return errMissingFilter
+	}
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{
+		Username:  username,
+		IpAddress: normalizeIPAddress(IPAddress),
+	})
+}
+
+// addStats adds the login attempts of a username and IP address pair to the total of the username or the IP address.
+func addStats(total LoginAttemptStats, st LoginAttemptStats) LoginAttemptStats {
+	total.Count += st.Count
+	if st.Latest > total.Latest {
+		total.Latest = st.Latest
+	}
+	return total
+}
+
+func (s *Service) isLocked(stats LoginAttemptStats, maxAttempts int64) bool {
+	return s.now().Before(s.lockedUntil(stats, maxAttempts))
+}
+
+// lockedUntil returns when the login is allowed again. Once the maximum number of attempts is reached,
+// the lockout doubles with each new failed attempt, up to the maximum lockout.
+func (s *Service) lockedUntil(stats LoginAttemptStats, maxAttempts int64) time.Time {
+	if maxAttempts <= 0 || stats.Count < maxAttempts {
+		return time.Time{}
+	}
+
+	lockout := s.cfg.BruteForceLoginProtectionMaxLockout
+	if exceeded := stats.Count - maxAttempts; exceeded < 32 {
+		if backoff := s.cfg.BruteForceLoginProtectionLockout << exceeded; backoff > 0 && backoff < lockout {
+			lockout = backoff
+		}
+	}
+
+	return time.Unix(stats.Latest, 0).Add(lockout)
+}
+
+func (s *Service) isAllowed(IPAddress string) bool {
+	ip := net.ParseIP(IPAddress)
+	if ip == nil {
+		return false
+	}
+	for _, network := range s.allowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Service) cleanup(ctx context.Context) {
 	err := s.lock.LockAndExecute(ctx, "delete old login attempts", time.Minute*10, func(context.Context) {
 		cmd := DeleteOldLoginAttemptsCommand{
-			OlderThan: time.Now().Add(time.Minute * -10),
+			OlderThan: time.Now().Add(-s.cfg.BruteForceLoginProtectionMaxLockout),
 		}
 		if deletedLogs, err := s.store.DeleteOldLoginAttempts(ctx, cmd); err != nil {
 			s.logger.Error("Problem deleting expired login attempts", "error", err.Error())
@@ -102,3 +290,20 @@ func (s *Service) cleanup(ctx context.Context) {
 		s.logger.Error("failed to lock and execute cleanup of old login attempts", "error", err)
 	}
 }
+
+// normalizeIPAddress removes the brackets around IPv6 addresses.
+func normalizeIPAddress(IPAddress string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(IPAddress, "["), "]")
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid brute_force_login_protection_allowed_cidrs %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
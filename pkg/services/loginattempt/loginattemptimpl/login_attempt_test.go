@@ -3,17 +3,27 @@ package loginattemptimpl
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+const maxInvalidLoginAttempts int64 = 5
+
 func TestService_Validate(t *testing.T) {
+	now := time.Now()
+
 	testCases := []struct {
 		name          string
 		loginAttempts int64
+		userAttempts  int64
+		ipAttempts    int64
+		latest        time.Time
+		allowedCIDRs  []string
 		disabled      bool
 		expected      bool
 		expectedErr   error
@@ -58,37 +68,146 @@ func TestService_Validate(t *testing.T) {
 			expected:      true,
 			expectedErr:   nil,
 		},
+		{
+			name:          "When the lockout of the user has expired",
+			loginAttempts: maxInvalidLoginAttempts,
+			latest:        now.Add(-6 * time.Minute),
+			expected:      true,
+		},
+		{
+			name:          "When the lockout of the user doubled with each failed attempt past max",
+			loginAttempts: maxInvalidLoginAttempts + 2,
+			latest:        now.Add(-19 * time.Minute),
+			expected:      false,
+		},
+		{
+			name:          "When the lockout of the user is capped to the max lockout",
+			loginAttempts: maxInvalidLoginAttempts + 10,
+			latest:        now.Add(-61 * time.Minute),
+			expected:      true,
+		},
+		{
+			name:         "When username login attempt count across IP addresses is less than max",
+			userAttempts: 19,
+			expected:     true,
+		},
+		{
+			name:         "When username login attempt count across IP addresses equals max",
+			userAttempts: 20,
+			expected:     false,
+		},
+		{
+			name:         "When username login attempt count across IP addresses equals max and the IP address is allowed",
+			userAttempts: 20,
+			allowedCIDRs: []string{"10.0.0.0/8"},
+			expected:     false,
+		},
+		{
+			name:       "When IP address login attempt count equals max",
+			ipAttempts: 50,
+			expected:   false,
+		},
+		{
+			name:         "When IP address login attempt count equals max and the IP address is allowed",
+			ipAttempts:   50,
+			allowedCIDRs: []string{"10.0.0.0/8"},
+			expected:     true,
+		},
 	}
 
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := setting.NewCfg()
 			cfg.DisableBruteForceLoginProtection = tt.disabled
+			networks, err := parseCIDRs(tt.allowedCIDRs)
+			require.NoError(t, err)
+
+			latest := tt.latest
+			if latest.IsZero() {
+				latest = now
+			}
 			service := &Service{
 				store: fakeStore{
-					ExpectedCount: tt.loginAttempts,
-					ExpectedErr:   tt.expectedErr,
+					ExpectedCount:     tt.loginAttempts,
+					ExpectedUserCount: tt.userAttempts,
+					ExpectedIPCount:   tt.ipAttempts,
+					ExpectedLatest:    latest.Unix(),
+					ExpectedErr:       tt.expectedErr,
 				},
-				cfg: cfg,
+				cfg:             cfg,
+				metrics:         newMetrics(nil),
+				allowedNetworks: networks,
+				now:             func() time.Time { return now },
 			}
 
-			ok, err := service.Validate(context.Background(), "test")
+			ok, err := service.Validate(context.Background(), "test", "10.0.0.1")
 			assert.Equal(t, tt.expected, ok)
 			assert.Equal(t, tt.expectedErr, err)
 		})
 	}
 }
 
+func TestService_Lockouts(t *testing.T) {
+	now := time.Now()
+	cfg := setting.NewCfg()
+	cfg.BruteForceLoginProtectionMaxAttemptsPerUser = 8
+	cfg.BruteForceLoginProtectionMaxAttemptsPerIP = 6
+	networks, err := parseCIDRs([]string{"192.168.0.0/16"})
+	require.NoError(t, err)
+
+	service := &Service{
+		store: fakeStore{ExpectedStats: []LoginAttemptStats{
+			{Username: "user1", IpAddress: "10.0.0.1", Count: 5, Latest: now.Unix()},
+			{Username: "user2", IpAddress: "10.0.0.1", Count: 1, Latest: now.Unix()},
+			{Username: "user3", IpAddress: "10.0.0.2", Count: 4, Latest: now.Unix()},
+			{Username: "user1", IpAddress: "192.168.0.1", Count: 3, Latest: now.Unix()},
+			{Username: "user2", IpAddress: "192.168.0.1", Count: 3, Latest: now.Unix()},
+		}},
+		cfg:             cfg,
+		metrics:         newMetrics(nil),
+		allowedNetworks: networks,
+		now:             func() time.Time { return now },
+	}
+
+	lockouts, err := service.Lockouts(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []Lockout{
+		{Reason: LockoutReasonUser, Username: "user1", IPAddress: "10.0.0.1", Attempts: 5, LockedUntil: time.Unix(now.Unix(), 0).Add(5 * time.Minute)},
+		{Reason: LockoutReasonUsername, Username: "user1", Attempts: 8, LockedUntil: time.Unix(now.Unix(), 0).Add(5 * time.Minute)},
+		{Reason: LockoutReasonIP, IPAddress: "10.0.0.1", Attempts: 6, LockedUntil: time.Unix(now.Unix(), 0).Add(5 * time.Minute)},
+	}, lockouts)
+}
+
+func TestService_ClearLockout(t *testing.T) {
+	service := &Service{store: fakeStore{}}
+	require.ErrorIs(t, service.ClearLockout(context.Background(), "", ""), errMissingFilter)
+	require.NoError(t, service.ClearLockout(context.Background(), "", "[::1]"))
+}
+
 var _ store = new(fakeStore)
 
 type fakeStore struct {
 	ExpectedErr         error
 	ExpectedCount       int64
+	ExpectedUserCount   int64
+	ExpectedIPCount     int64
+	ExpectedLatest      int64
+	ExpectedStats       []LoginAttemptStats
 	ExpectedDeletedRows int64
 }
 
-func (f fakeStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error) {
-	return f.ExpectedCount, f.ExpectedErr
+func (f fakeStore) GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error) {
+	count := f.ExpectedCount
+	if query.Username == "" {
+		count = f.ExpectedIPCount
+	} else if query.IpAddress == "" {
+		count = f.ExpectedUserCount
+	}
+	return LoginAttemptStats{Username: query.Username, IpAddress: query.IpAddress, Count: count, Latest: f.ExpectedLatest}, f.ExpectedErr
+}
+
+func (f fakeStore) ListLoginAttemptStats(ctx context.Context, query ListLoginAttemptStatsQuery) ([]LoginAttemptStats, error) {
+	return f.ExpectedStats, f.ExpectedErr
 }
 
 func (f fakeStore) CreateLoginAttempt(ctx context.Context, command CreateLoginAttemptCommand) (loginattempt.LoginAttempt, error) {
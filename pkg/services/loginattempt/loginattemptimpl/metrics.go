@@ -0,0 +1,41 @@
+package loginattemptimpl
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "grafana"
+	metricsSubSystem = "login_attempt"
+)
+
+type metrics struct {
+	failedAttempts  prometheus.Counter
+	blockedAttempts *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		failedAttempts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "failed_total",
+			Help:      "Number of failed login attempts",
+		}),
+		blockedAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "blocked_total",
+			Help:      "Number of login attempts blocked by the brute force login protection",
+		}, []string{"reason"}),
+	}
+
+	if reg != nil {
+		reg.MustRegister(
+			m.failedAttempts,
+			m.blockedAttempts,
+		)
+	}
+
+	return m
+}
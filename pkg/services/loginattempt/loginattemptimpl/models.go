@@ -2,6 +2,8 @@ package loginattemptimpl
 
 import (
 	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 )
 
 type CreateLoginAttemptCommand struct {
@@ -9,15 +11,83 @@ type CreateLoginAttemptCommand struct {
 	IpAddress string
 }
 
-type GetUserLoginAttemptCountQuery struct {
-	Username string
-	Since    time.Time
+// GetLoginAttemptStatsQuery filters the login attempts on the username and/or the IP address,
+// the empty fields are ignored.
+type GetLoginAttemptStatsQuery struct {
+	Username  string
+	IpAddress string
+	Since     time.Time
+}
+
+type ListLoginAttemptStatsQuery struct {
+	Since time.Time
+}
+
+// LoginAttemptStats summarizes the failed login attempts of a username from an IP address.
+type LoginAttemptStats struct {
+	Username  string `xorm:"username"`
+	IpAddress string `xorm:"ip_address"`
+	Count     int64  `xorm:"count"`
+	// Latest is the unix timestamp of the last attempt
+	Latest int64 `xorm:"latest"`
 }
 
 type DeleteOldLoginAttemptsCommand struct {
 	OlderThan time.Time
 }
 
+// DeleteLoginAttemptsCommand deletes the login attempts matching the username and/or the IP address,
+// the empty fields are ignored.
 type DeleteLoginAttemptsCommand struct {
-	Username string
+	Username  string
+	IpAddress string
+}
+
+const (
+	LockoutReasonUser     = "user"
+	LockoutReasonUsername = "username"
+	LockoutReasonIP       = "ip"
+)
+
+// Lockout is a username and IP address pair, a username, or an IP address, that cannot log in until LockedUntil.
+type Lockout struct {
+	Reason      string    `json:"reason"`
+	Username    string    `json:"username,omitempty"`
+	IPAddress   string    `json:"ipAddress,omitempty"`
+	Attempts    int64     `json:"attempts"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+const (
+	ActionRead   = "login.attempts:read"
+	ActionDelete = "login.attempts:delete"
+)
+
+var (
+	lockoutsReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:login.attempts:reader",
+		DisplayName: "Login attempts reader",
+		Description: "List the users and IP addresses locked out after too many failed login attempts",
+		Group:       "Users",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+		},
+	}
+	lockoutsWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:login.attempts:writer",
+		DisplayName: "Login attempts writer",
+		Description: "List and clear the lockouts of users and IP addresses after too many failed login attempts",
+		Group:       "Users",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+			{Action: ActionDelete},
+		},
+	}
+)
+
+func declareFixedRoles(service accesscontrol.Service) error {
+	return service.DeclareFixedRoles(
+		accesscontrol.RoleRegistration{Role: lockoutsReaderRole, Grants: []string{accesscontrol.RoleGrafanaAdmin}},
+		accesscontrol.RoleRegistration{Role: lockoutsWriterRole, Grants: []string{accesscontrol.RoleGrafanaAdmin}},
+	)
 }
@@ -17,7 +17,8 @@ type store interface {
 	CreateLoginAttempt(ctx context.Context, cmd CreateLoginAttemptCommand) (loginattempt.LoginAttempt, error)
 	DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLoginAttemptsCommand) (int64, error)
 	DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error
-	GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error)
+	GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error)
+	ListLoginAttemptStats(ctx context.Context, query ListLoginAttemptStatsQuery) ([]LoginAttemptStats, error)
 }
 
 func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAttemptCommand) (result loginattempt.LoginAttempt, err error) {
@@ -58,27 +59,50 @@ func (xs *xormStore) DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLo
 
 func (xs *xormStore) DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error {
 	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
-		_, err := sess.Exec("DELETE FROM login_attempt WHERE username = ?", cmd.Username)
+		if cmd.Username != "" {
+			sess.Where("username = ?", cmd.Username)
+		}
+		if cmd.IpAddress != "" {
+			sess.Where("ip_address = ?", cmd.IpAddress)
+		}
+		_, err := sess.Delete(&loginattempt.LoginAttempt{})
 		return err
 	})
 }
 
-func (xs *xormStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error) {
-	var total int64
+func (xs *xormStore) GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error) {
+	result := LoginAttemptStats{Username: query.Username, IpAddress: query.IpAddress}
 	err := xs.db.WithDbSession(ctx, func(dbSession *db.Session) error {
-		var queryErr error
-		loginAttempt := new(loginattempt.LoginAttempt)
-		total, queryErr = dbSession.
-			Where("username = ?", query.Username).
-			And("created >= ?", query.Since.Unix()).
-			Count(loginAttempt)
-
-		if queryErr != nil {
-			return queryErr
+		sess := dbSession.Table("login_attempt").
+			Select("COUNT(*) AS count, COALESCE(MAX(created), 0) AS latest").
+			Where("created >= ?", query.Since.Unix())
+		if query.Username != "" {
+			sess.And("username = ?", query.Username)
+		}
+		if query.IpAddress != "" {
+			sess.And("ip_address = ?", query.IpAddress)
 		}
 
+		var stats LoginAttemptStats
+		if _, err := sess.Get(&stats); err != nil {
+			return err
+		}
+		result.Count, result.Latest = stats.Count, stats.Latest
 		return nil
 	})
 
-	return total, err
+	return result, err
+}
+
+func (xs *xormStore) ListLoginAttemptStats(ctx context.Context, query ListLoginAttemptStatsQuery) ([]LoginAttemptStats, error) {
+	result := make([]LoginAttemptStats, 0)
+	err := xs.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		return dbSession.Table("login_attempt").
+			Select("username, ip_address, COUNT(*) AS count, MAX(created) AS latest").
+			Where("created >= ?", query.Since.Unix()).
+			GroupBy("username, ip_address").
+			Find(&result)
+	})
+
+	return result, err
 }
@@ -22,29 +22,29 @@ func TestIntegrationLoginAttemptsQuery(t *testing.T) {
 
 	for _, test := range []struct {
 		Name   string
-		Query  GetUserLoginAttemptCountQuery
+		Query  GetLoginAttemptStatsQuery
 		Err    error
 		Result int64
 	}{
 		{
 			"Should return a total count of zero login attempts when comparing since beginning of time + 2min and 1s",
-			GetUserLoginAttemptCountQuery{Username: user, Since: timePlusTwoMinutes.Add(time.Second * 1)}, nil, 0,
+			GetLoginAttemptStatsQuery{Username: user, Since: timePlusTwoMinutes.Add(time.Second * 1)}, nil, 0,
 		},
 		{
 			"Should return a total count of zero login attempts when comparing since beginning of time + 2min and 1s",
-			GetUserLoginAttemptCountQuery{Username: user, Since: timePlusTwoMinutes.Add(time.Second * 1)}, nil, 0,
+			GetLoginAttemptStatsQuery{Username: user, Since: timePlusTwoMinutes.Add(time.Second * 1)}, nil, 0,
 		},
 		{
 			"Should return the total count of login attempts since beginning of time",
-			GetUserLoginAttemptCountQuery{Username: user, Since: beginningOfTime}, nil, 3,
+			GetLoginAttemptStatsQuery{Username: user, Since: beginningOfTime}, nil, 3,
 		},
 		{
 			"Should return the total count of login attempts since beginning of time + 1min",
-			GetUserLoginAttemptCountQuery{Username: user, Since: timePlusOneMinute}, nil, 2,
+			GetLoginAttemptStatsQuery{Username: user, Since: timePlusOneMinute}, nil, 2,
 		},
 		{
 			"Should return the total count of login attempts since beginning of time + 2min",
-			GetUserLoginAttemptCountQuery{Username: user, Since: timePlusTwoMinutes}, nil, 1,
+			GetLoginAttemptStatsQuery{Username: user, Since: timePlusTwoMinutes}, nil, 1,
 		},
 	} {
 		mockTime := beginningOfTime
@@ -73,9 +73,12 @@ func TestIntegrationLoginAttemptsQuery(t *testing.T) {
 		})
 		require.Nil(t, err)
 
-		count, err := s.GetUserLoginAttemptCount(context.Background(), test.Query)
+		stats, err := s.GetLoginAttemptStats(context.Background(), test.Query)
 		require.Equal(t, test.Err, err, test.Name)
-		require.Equal(t, test.Result, count, test.Name)
+		require.Equal(t, test.Result, stats.Count, test.Name)
+		if test.Result > 0 {
+			require.Equal(t, timePlusTwoMinutes.Unix(), stats.Latest, test.Name)
+		}
 	}
 }
 
@@ -143,3 +146,54 @@ func TestIntegrationLoginAttemptsDelete(t *testing.T) {
 		require.Equal(t, test.DeletedRows, deletedRows, test.Name)
 	}
 }
+
+func TestIntegrationLoginAttemptsByIPAddress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Date(2017, 10, 22, 8, 0, 0, 0, time.Local)
+	s := &xormStore{
+		db:  db.InitTestDB(t),
+		now: func() time.Time { return now },
+	}
+
+	for _, cmd := range []CreateLoginAttemptCommand{
+		{Username: "user1", IpAddress: "192.168.0.1"},
+		{Username: "user1", IpAddress: "192.168.0.1"},
+		{Username: "user2", IpAddress: "192.168.0.1"},
+		{Username: "user1", IpAddress: "2001:db8::1"},
+	} {
+		_, err := s.CreateLoginAttempt(ctx, cmd)
+		require.NoError(t, err)
+	}
+
+	t.Run("Should count the login attempts of an IP address", func(t *testing.T) {
+		stats, err := s.GetLoginAttemptStats(ctx, GetLoginAttemptStatsQuery{IpAddress: "192.168.0.1", Since: now})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), stats.Count)
+
+		stats, err = s.GetLoginAttemptStats(ctx, GetLoginAttemptStatsQuery{Username: "user1", IpAddress: "192.168.0.1", Since: now})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), stats.Count)
+	})
+
+	t.Run("Should list the login attempts by username and IP address", func(t *testing.T) {
+		stats, err := s.ListLoginAttemptStats(ctx, ListLoginAttemptStatsQuery{Since: now})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []LoginAttemptStats{
+			{Username: "user1", IpAddress: "192.168.0.1", Count: 2, Latest: now.Unix()},
+			{Username: "user2", IpAddress: "192.168.0.1", Count: 1, Latest: now.Unix()},
+			{Username: "user1", IpAddress: "2001:db8::1", Count: 1, Latest: now.Unix()},
+		}, stats)
+	})
+
+	t.Run("Should delete the login attempts of an IP address", func(t *testing.T) {
+		require.NoError(t, s.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{IpAddress: "192.168.0.1"}))
+
+		stats, err := s.ListLoginAttemptStats(ctx, ListLoginAttemptStatsQuery{Since: now})
+		require.NoError(t, err)
+		require.Equal(t, []LoginAttemptStats{{Username: "user1", IpAddress: "2001:db8::1", Count: 1, Latest: now.Unix()}}, stats)
+	})
+}
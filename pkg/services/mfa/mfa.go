@@ -0,0 +1,88 @@
+package mfa
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrDisabled         = errutil.NotFound("mfa.disabled", errutil.WithPublicMessage("Multi-factor authentication is not enabled"))
+	ErrNotEnrolled      = errutil.BadRequest("mfa.not-enrolled", errutil.WithPublicMessage("Multi-factor authentication is not enabled for the user"))
+	ErrNoPendingSecret  = errutil.BadRequest("mfa.no-pending-enrollment", errutil.WithPublicMessage("Start the enrollment before confirming it"))
+	ErrAlreadyEnrolled  = errutil.BadRequest("mfa.already-enrolled", errutil.WithPublicMessage("Multi-factor authentication is already enabled for the user"))
+	ErrInvalidCode      = errutil.Unauthorized("mfa.invalid-code", errutil.WithPublicMessage("Invalid authentication code"))
+	ErrEnforced         = errutil.Forbidden("mfa.enforced", errutil.WithPublicMessage("Multi-factor authentication is enforced by an organization of the user"))
+	ErrInvalidOrgPolicy = errutil.BadRequest("mfa.invalid-policy")
+)
+
+// Service manages the time-based one-time password (TOTP) second factor of local users.
+type Service interface {
+	// IsEnabled returns true when multi-factor authentication is enabled for the instance.
+	IsEnabled() bool
+	// GetStatus returns whether the user has enrolled, and whether an organization of the user enforces it.
+	GetStatus(ctx context.Context, userID int64) (*Status, error)
+	// Enroll generates a new secret for the user. The enrollment stays pending, and the previous secret
+	// stays in use, until it is confirmed with a code generated from the new secret.
+	Enroll(ctx context.Context, userID int64, login string) (*Enrollment, error)
+	// PendingEnrollment returns the enrollment of the user waiting for a confirmation, and only starts a new one
+	// when there is none or it expired, so that repeated logins don't replace the secret being set up.
+	PendingEnrollment(ctx context.Context, userID int64, login string) (*Enrollment, error)
+	// ConfirmEnrollment enables the pending secret of the user and returns new recovery codes.
+	// The recovery codes are only stored encrypted, they can't be read again.
+	ConfirmEnrollment(ctx context.Context, userID int64, code string) ([]string, error)
+	// Verify checks a one-time password, or consumes a recovery code, of an enrolled user.
+	Verify(ctx context.Context, userID int64, code string) error
+	// RegenerateRecoveryCodes replaces the recovery codes of an enrolled user.
+	RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error)
+	// Reset removes the second factor of the user, who has to enroll again if it is enforced.
+	Reset(ctx context.Context, userID int64) error
+	GetOrgPolicy(ctx context.Context, orgID int64) (*OrgPolicy, error)
+	SetOrgPolicy(ctx context.Context, policy OrgPolicy) error
+}
+
+type Status struct {
+	Enabled bool `json:"enabled"`
+	// Enforced is true when an organization of the user requires a second factor for the role of the user.
+	Enforced               bool `json:"enforced"`
+	RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"`
+}
+
+// Enrollment holds what an authenticator app needs to generate codes.
+type Enrollment struct {
+	// Secret is the base32 encoded secret, for entering it manually in an authenticator app.
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth:// URI to render as a QR code.
+	ProvisioningURI string `json:"provisioningUri"`
+	Issuer          string `json:"issuer"`
+	Period          int    `json:"period"`
+	Digits          int    `json:"digits"`
+	Algorithm       string `json:"algorithm"`
+}
+
+// OrgPolicy enforces multi-factor authentication for the members of an organization.
+type OrgPolicy struct {
+	OrgID    int64 `json:"orgId"`
+	Enforced bool  `json:"enforced"`
+	// Roles limits the enforcement to the members with one of these roles, all members when empty.
+	Roles   []org.RoleType `json:"roles"`
+	Updated time.Time      `json:"updated"`
+}
+
+// AppliesTo returns true if the policy enforces multi-factor authentication for the role.
+func (p *OrgPolicy) AppliesTo(role org.RoleType) bool {
+	if p == nil || !p.Enforced {
+		return false
+	}
+	if len(p.Roles) == 0 {
+		return true
+	}
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+package mfaimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	userIDScope := ac.Scope("global.users", "id", ac.Parameter(":id"))
+
+	routeRegister.Group("/api/user/mfa", func(userRoute routing.RouteRegister) {
+		userRoute.Get("/", routing.Wrap(s.handleGetStatus))
+		userRoute.Post("/enroll", routing.Wrap(s.handleEnroll))
+		userRoute.Post("/confirm", routing.Wrap(s.handleConfirm))
+		userRoute.Post("/recovery-codes", routing.Wrap(s.handleRegenerateRecoveryCodes))
+		userRoute.Post("/disable", routing.Wrap(s.handleDisable))
+	}, middleware.ReqSignedInNoAnonymous)
+
+	routeRegister.Delete("/api/admin/users/:id/mfa", authorize(ac.EvalPermission(ActionUsersReset, userIDScope)), routing.Wrap(s.handleResetUser))
+
+	routeRegister.Group("/api/org/mfa/policy", func(policyRoute routing.RouteRegister) {
+		policyRoute.Get("/", authorize(ac.EvalPermission(ActionPolicyRead)), routing.Wrap(s.handleGetOrgPolicy))
+		policyRoute.Put("/", authorize(ac.EvalPermission(ActionPolicyWrite)), routing.Wrap(s.handleSetOrgPolicy))
+	})
+}
+
+// swagger:route GET /user/mfa signed_in_user getUserMFAStatus
+//
+// Get the multi-factor authentication status of the signed in user.
+//
+// Responses:
+// 200: getUserMFAStatusResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleGetStatus(c *contextmodel.ReqContext) response.Response {
+	if !c.SignedInUser.IsRealUser() {
+		return response.Error(http.StatusForbidden, "Multi-factor authentication is only available to users", nil)
+	}
+	status, err := s.GetStatus(c.Req.Context(), c.SignedInUser.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get multi-factor authentication status", err)
+	}
+	return response.JSON(http.StatusOK, status)
+}
+
+// swagger:route POST /user/mfa/enroll signed_in_user enrollUserMFA
+//
+// Start the enrollment of a TOTP authenticator app.
+//
+// Returns a new secret and its otpauth:// provisioning URI, to render as a QR code. The enrollment is
+// confirmed with a code generated from the new secret. If the user already enrolled, a code generated from
+// the current secret, or a recovery code, is required and the current secret stays in use until the new
+// enrollment is confirmed.
+//
+// Responses:
+// 200: enrollUserMFAResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleEnroll(c *contextmodel.ReqContext) response.Response {
+	if !c.SignedInUser.IsRealUser() {
+		return response.Error(http.StatusForbidden, "Multi-factor authentication is only available to users", nil)
+	}
+
+	cmd := codeCommand{}
+	if c.Req.ContentLength > 0 {
+		if err := web.Bind(c.Req, &cmd); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	}
+
+	ctx := c.Req.Context()
+	status, err := s.GetStatus(ctx, c.SignedInUser.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get multi-factor authentication status", err)
+	}
+	if status.Enabled {
+		if err := s.Verify(ctx, c.SignedInUser.UserID, cmd.Code); err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to verify code", err)
+		}
+	}
+
+	enrollment, err := s.Enroll(ctx, c.SignedInUser.UserID, c.SignedInUser.Login)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enroll", err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+// swagger:route POST /user/mfa/confirm signed_in_user confirmUserMFA
+//
+// Confirm the enrollment of a TOTP authenticator app.
+//
+// Enables the secret returned by the enrollment and returns single-use recovery codes, which are
+// not shown again.
+//
+// Responses:
+// 200: recoveryCodesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleConfirm(c *contextmodel.ReqContext) response.Response {
+	if !c.SignedInUser.IsRealUser() {
+		return response.Error(http.StatusForbidden, "Multi-factor authentication is only available to users", nil)
+	}
+	cmd := codeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	codes, err := s.ConfirmEnrollment(c.Req.Context(), c.SignedInUser.UserID, cmd.Code)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to confirm enrollment", err)
+	}
+	return response.JSON(http.StatusOK, recoveryCodesDTO{RecoveryCodes: codes})
+}
+
+// swagger:route POST /user/mfa/recovery-codes signed_in_user regenerateUserMFARecoveryCodes
+//
+// Replace the recovery codes of the signed in user.
+//
+// Requires a code generated by the authenticator app, or a recovery code.
+//
+// Responses:
+// 200: recoveryCodesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleRegenerateRecoveryCodes(c *contextmodel.ReqContext) response.Response {
+	if !c.SignedInUser.IsRealUser() {
+		return response.Error(http.StatusForbidden, "Multi-factor authentication is only available to users", nil)
+	}
+	cmd := codeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	ctx := c.Req.Context()
+	if err := s.Verify(ctx, c.SignedInUser.UserID, cmd.Code); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to verify code", err)
+	}
+	codes, err := s.RegenerateRecoveryCodes(ctx, c.SignedInUser.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to generate recovery codes", err)
+	}
+	return response.JSON(http.StatusOK, recoveryCodesDTO{RecoveryCodes: codes})
+}
+
+// swagger:route POST /user/mfa/disable signed_in_user disableUserMFA
+//
+// Disable the multi-factor authentication of the signed in user.
+//
+// Requires a code generated by the authenticator app, or a recovery code. It fails when an
+// organization of the user enforces multi-factor authentication.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleDisable(c *contextmodel.ReqContext) response.Response {
+	if !c.SignedInUser.IsRealUser() {
+		return response.Error(http.StatusForbidden, "Multi-factor authentication is only available to users", nil)
+	}
+	cmd := codeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	ctx := c.Req.Context()
+	status, err := s.GetStatus(ctx, c.SignedInUser.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get multi-factor authentication status", err)
+	}
+	if status.Enforced {
+		return response.Err(mfa.ErrEnforced.Errorf("user %d can't disable enforced multi-factor authentication", c.SignedInUser.UserID))
+	}
+	if err := s.Verify(ctx, c.SignedInUser.UserID, cmd.Code); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to verify code", err)
+	}
+	if err := s.Reset(ctx, c.SignedInUser.UserID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to disable multi-factor authentication", err)
+	}
+	return response.Success("Multi-factor authentication disabled")
+}
+
+// swagger:route DELETE /admin/users/{user_id}/mfa admin_users resetUserMFA
+//
+// Reset the multi-factor authentication of a user.
+//
+// The user has to enroll again to log in if an organization enforces multi-factor authentication.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleResetUser(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+	if err := s.Reset(c.Req.Context(), userID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reset multi-factor authentication", err)
+	}
+	return response.Success("Multi-factor authentication reset")
+}
+
+// swagger:route GET /org/mfa/policy org getOrgMFAPolicy
+//
+// Get the multi-factor authentication policy of the current organization.
+//
+// Responses:
+// 200: getOrgMFAPolicyResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleGetOrgPolicy(c *contextmodel.ReqContext) response.Response {
+	policy, err := s.GetOrgPolicy(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get multi-factor authentication policy", err)
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route PUT /org/mfa/policy org setOrgMFAPolicy
+//
+// Update the multi-factor authentication policy of the current organization.
+//
+// When enforced, the members with one of the roles, or all members when no role is set, have to
+// log in with a second factor and enroll at their next login if they have not yet.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSetOrgPolicy(c *contextmodel.ReqContext) response.Response {
+	cmd := setOrgPolicyCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	err := s.SetOrgPolicy(c.Req.Context(), mfa.OrgPolicy{
+		OrgID:    c.SignedInUser.GetOrgID(),
+		Enforced: cmd.Enforced,
+		Roles:    cmd.Roles,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update multi-factor authentication policy", err)
+	}
+	return response.Success("Multi-factor authentication policy updated")
+}
+
+type recoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// swagger:parameters enrollUserMFA
+type EnrollUserMFAParams struct {
+	// Required when the user already enrolled
+	// in:body
+	// required:false
+	Body codeCommand `json:"body"`
+}
+
+// swagger:parameters confirmUserMFA regenerateUserMFARecoveryCodes disableUserMFA
+type UserMFACodeParams struct {
+	// in:body
+	// required:true
+	Body codeCommand `json:"body"`
+}
+
+// swagger:parameters resetUserMFA
+type ResetUserMFAParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+}
+
+// swagger:parameters setOrgMFAPolicy
+type SetOrgMFAPolicyParams struct {
+	// in:body
+	// required:true
+	Body setOrgPolicyCommand `json:"body"`
+}
+
+// swagger:response getUserMFAStatusResponse
+type GetUserMFAStatusResponse struct {
+	// in:body
+	Body mfa.Status `json:"body"`
+}
+
+// swagger:response enrollUserMFAResponse
+type EnrollUserMFAResponse struct {
+	// in:body
+	Body mfa.Enrollment `json:"body"`
+}
+
+// swagger:response recoveryCodesResponse
+type RecoveryCodesResponse struct {
+	// in:body
+	Body recoveryCodesDTO `json:"body"`
+}
+
+// swagger:response getOrgMFAPolicyResponse
+type GetOrgMFAPolicyResponse struct {
+	// in:body
+	Body mfa.OrgPolicy `json:"body"`
+}
@@ -0,0 +1,100 @@
+package mfaimpl
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	ActionUsersReset  = "users.mfa:delete"
+	ActionPolicyRead  = "org.mfa.policy:read"
+	ActionPolicyWrite = "org.mfa.policy:write"
+)
+
+const (
+	defaultIssuer      = "Grafana"
+	recoveryCodesCount = 10
+	// pendingEnrollmentTTL is how long an enrollment can be confirmed after it started.
+	pendingEnrollmentTTL = time.Hour
+)
+
+var (
+	policyWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:org.mfa.policy:writer",
+		DisplayName: "MFA policy writer",
+		Description: "Read and update the multi-factor authentication policy of the organization",
+		Group:       "Organizations",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionPolicyRead},
+			{Action: ActionPolicyWrite},
+		},
+	}
+
+	usersResetRole = accesscontrol.RoleDTO{
+		Name:        "fixed:users.mfa:resetter",
+		DisplayName: "User MFA resetter",
+		Description: "Reset the multi-factor authentication of any user",
+		Group:       "Users",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionUsersReset, Scope: accesscontrol.ScopeGlobalUsersAll},
+		},
+	}
+)
+
+func declareFixedRoles(service accesscontrol.Service) error {
+	return service.DeclareFixedRoles(
+		accesscontrol.RoleRegistration{
+			Role:   policyWriterRole,
+			Grants: []string{string(org.RoleAdmin)},
+		},
+		accesscontrol.RoleRegistration{
+			Role:   usersResetRole,
+			Grants: []string{accesscontrol.RoleGrafanaAdmin},
+		},
+	)
+}
+
+// userMFA is the database representation of the second factor of a user.
+// The secrets and the recovery codes are encrypted with the secrets service.
+type userMFA struct {
+	ID     int64 `xorm:"pk autoincr 'id'"`
+	UserID int64 `xorm:"user_id"`
+	// Secret is the confirmed secret, empty until the first enrollment is confirmed.
+	Secret string `xorm:"secret"`
+	// PendingSecret is the secret of an enrollment that is not confirmed yet.
+	PendingSecret string `xorm:"pending_secret"`
+	// PendingCreated is the unix timestamp of the start of the pending enrollment.
+	PendingCreated int64  `xorm:"pending_created"`
+	RecoveryCodes  string `xorm:"recovery_codes"`
+	// LastUsedStep is the time step of the last accepted code, codes can't be used twice.
+	LastUsedStep int64     `xorm:"last_used_step"`
+	Created      time.Time `xorm:"created"`
+	Updated      time.Time `xorm:"updated"`
+}
+
+func (userMFA) TableName() string {
+	return "user_mfa"
+}
+
+type orgMFAPolicy struct {
+	ID       int64     `xorm:"pk autoincr 'id'"`
+	OrgID    int64     `xorm:"org_id"`
+	Enforced bool      `xorm:"enforced"`
+	Roles    string    `xorm:"roles"`
+	Updated  time.Time `xorm:"updated"`
+}
+
+func (orgMFAPolicy) TableName() string {
+	return "org_mfa_policy"
+}
+
+type setOrgPolicyCommand struct {
+	Enforced bool           `json:"enforced"`
+	Roles    []org.RoleType `json:"roles"`
+}
+
+type codeCommand struct {
+	Code string `json:"code"`
+}
@@ -0,0 +1,385 @@
+package mfaimpl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// recoveryCodeAlphabet leaves out the characters that are easily confused, e.g. 0 and o.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+var _ mfa.Service = (*Service)(nil)
+
+type Service struct {
+	store         store
+	secrets       secrets.Service
+	orgService    org.Service
+	accessControl ac.AccessControl
+	log           log.Logger
+	now           func() time.Time
+
+	enabled bool
+	issuer  string
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	secretsService secrets.Service,
+	orgService org.Service,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("auth.mfa")
+	s := &Service{
+		store:         &xormStore{db: sql, now: time.Now},
+		secrets:       secretsService,
+		orgService:    orgService,
+		accessControl: accessControl,
+		log:           log.New("mfa"),
+		now:           time.Now,
+		enabled:       section.Key("enabled").MustBool(false),
+		issuer:        section.Key("issuer").MustString(defaultIssuer),
+	}
+
+	if !s.enabled {
+		return s, nil
+	}
+
+	if strings.Contains(s.issuer, ":") {
+		return nil, fmt.Errorf("invalid [auth.mfa] issuer %q: it can't contain a colon", s.issuer)
+	}
+
+	if err := declareFixedRoles(accesscontrolService); err != nil {
+		return nil, err
+	}
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) IsEnabled() bool {
+	return s.enabled
+}
+
+func (s *Service) GetStatus(ctx context.Context, userID int64) (*mfa.Status, error) {
+	status := &mfa.Status{}
+	if !s.enabled {
+		return status, nil
+	}
+
+	m, err := s.store.GetUserMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m != nil && m.Secret != "" {
+		status.Enabled = true
+		codes, err := s.decryptRecoveryCodes(ctx, m.RecoveryCodes)
+		if err != nil {
+			return nil, err
+		}
+		status.RecoveryCodesRemaining = len(codes)
+	}
+
+	status.Enforced, err = s.isEnforced(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (s *Service) Enroll(ctx context.Context, userID int64, login string) (*mfa.Enrollment, error) {
+	return s.enroll(ctx, userID, login, false)
+}
+
+func (s *Service) PendingEnrollment(ctx context.Context, userID int64, login string) (*mfa.Enrollment, error) {
+	return s.enroll(ctx, userID, login, true)
+}
+
+// enroll generates a new pending secret for the user, or returns the current one if reusePending is true and it has not expired.
+func (s *Service) enroll(ctx context.Context, userID int64, login string, reusePending bool) (*mfa.Enrollment, error) {
+	if !s.enabled {
+		return nil, mfa.ErrDisabled.Errorf("multi-factor authentication is disabled")
+	}
+
+	m, err := s.store.GetUserMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = &userMFA{UserID: userID}
+	}
+
+	if reusePending && s.hasPendingSecret(m) {
+		secret, err := s.decrypt(ctx, m.PendingSecret)
+		if err != nil {
+			return nil, err
+		}
+		return s.enrollment(login, secret), nil
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.encrypt(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	m.PendingSecret = encrypted
+	m.PendingCreated = s.now().Unix()
+	if err := s.store.SaveUserMFA(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return s.enrollment(login, secret), nil
+}
+
+func (s *Service) hasPendingSecret(m *userMFA) bool {
+	return m.PendingSecret != "" && s.now().Before(time.Unix(m.PendingCreated, 0).Add(pendingEnrollmentTTL))
+}
+
+func (s *Service) enrollment(login, secret string) *mfa.Enrollment {
+	return &mfa.Enrollment{
+		Secret:          secret,
+		ProvisioningURI: provisioningURI(s.issuer, login, secret),
+		Issuer:          s.issuer,
+		Period:          totpPeriod,
+		Digits:          totpDigits,
+		Algorithm:       totpAlgorithm,
+	}
+}
+
+func (s *Service) ConfirmEnrollment(ctx context.Context, userID int64, code string) ([]string, error) {
+	m, err := s.store.GetUserMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil || !s.hasPendingSecret(m) {
+		return nil, mfa.ErrNoPendingSecret.Errorf("user %d has no pending enrollment", userID)
+	}
+
+	secret, err := s.decrypt(ctx, m.PendingSecret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := validateCode(secret, code, s.now(), m.LastUsedStep)
+	if !ok {
+		return nil, mfa.ErrInvalidCode.Errorf("invalid code to confirm the enrollment of user %d", userID)
+	}
+
+	codes, encryptedCodes, err := s.generateRecoveryCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Secret = m.PendingSecret
+	m.PendingSecret = ""
+	m.PendingCreated = 0
+	m.LastUsedStep = step
+	m.RecoveryCodes = encryptedCodes
+	if err := s.store.SaveUserMFA(ctx, m); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *Service) Verify(ctx context.Context, userID int64, code string) error {
+	m, err := s.store.GetUserMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if m == nil || m.Secret == "" {
+		return mfa.ErrNotEnrolled.Errorf("user %d has not enrolled", userID)
+	}
+
+	secret, err := s.decrypt(ctx, m.Secret)
+	if err != nil {
+		return err
+	}
+	if step, ok := validateCode(secret, code, s.now(), m.LastUsedStep); ok {
+		updated, err := s.store.UpdateLastUsedStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return mfa.ErrInvalidCode.Errorf("code of user %d was already used", userID)
+		}
+		return nil
+	}
+
+	return s.useRecoveryCode(ctx, m, code)
+}
+
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	m, err := s.store.GetUserMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil || m.Secret == "" {
+		return nil, mfa.ErrNotEnrolled.Errorf("user %d has not enrolled", userID)
+	}
+
+	codes, encryptedCodes, err := s.generateRecoveryCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.RecoveryCodes = encryptedCodes
+	if err := s.store.SaveUserMFA(ctx, m); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *Service) Reset(ctx context.Context, userID int64) error {
+	return s.store.DeleteUserMFA(ctx, userID)
+}
+
+func (s *Service) GetOrgPolicy(ctx context.Context, orgID int64) (*mfa.OrgPolicy, error) {
+	return s.store.GetOrgPolicy(ctx, orgID)
+}
+
+func (s *Service) SetOrgPolicy(ctx context.Context, policy mfa.OrgPolicy) error {
+	roles := make([]org.RoleType, 0, len(policy.Roles))
+	seen := map[org.RoleType]bool{}
+	for _, role := range policy.Roles {
+		if !role.IsValid() {
+			return mfa.ErrInvalidOrgPolicy.Errorf("invalid role %q", role)
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	policy.Roles = roles
+	return s.store.SetOrgPolicy(ctx, policy)
+}
+
+// isEnforced returns true if any organization of the user enforces multi-factor authentication
+// for the role of the user in that organization.
+func (s *Service) isEnforced(ctx context.Context, userID int64) (bool, error) {
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range orgs {
+		policy, err := s.store.GetOrgPolicy(ctx, o.OrgID)
+		if err != nil {
+			return false, err
+		}
+		if policy.AppliesTo(o.Role) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) useRecoveryCode(ctx context.Context, m *userMFA, code string) error {
+	codes, err := s.decryptRecoveryCodes(ctx, m.RecoveryCodes)
+	if err != nil {
+		return err
+	}
+
+	code = normalizeRecoveryCode(code)
+	for i, c := range codes {
+		if code == "" || code != normalizeRecoveryCode(c) {
+			continue
+		}
+
+		remaining, err := s.encryptRecoveryCodes(ctx, append(codes[:i:i], codes[i+1:]...))
+		if err != nil {
+			return err
+		}
+		updated, err := s.store.UpdateRecoveryCodes(ctx, m.UserID, m.RecoveryCodes, remaining)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return mfa.ErrInvalidCode.Errorf("recovery code of user %d was already used", m.UserID)
+		}
+		s.log.FromContext(ctx).Info("Recovery code used", "userId", m.UserID, "remaining", len(codes)-1)
+		return nil
+	}
+
+	return mfa.ErrInvalidCode.Errorf("invalid code for user %d", m.UserID)
+}
+
+func (s *Service) generateRecoveryCodes(ctx context.Context) ([]string, string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		c, err := util.GetRandomString(10, []byte(recoveryCodeAlphabet)...)
+		if err != nil {
+			return nil, "", err
+		}
+		codes = append(codes, c[:5]+"-"+c[5:])
+	}
+
+	encrypted, err := s.encryptRecoveryCodes(ctx, codes)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, encrypted, nil
+}
+
+func (s *Service) encryptRecoveryCodes(ctx context.Context, codes []string) (string, error) {
+	b, err := json.Marshal(codes)
+	if err != nil {
+		return "", err
+	}
+	return s.encrypt(ctx, string(b))
+}
+
+func (s *Service) decryptRecoveryCodes(ctx context.Context, encrypted string) ([]string, error) {
+	if encrypted == "" {
+		return []string{}, nil
+	}
+	decrypted, err := s.decrypt(ctx, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	var codes []string
+	if err := json.Unmarshal([]byte(decrypted), &codes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *Service) encrypt(ctx context.Context, value string) (string, error) {
+	encrypted, err := s.secrets.Encrypt(ctx, []byte(value), secrets.WithoutScope())
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (s *Service) decrypt(ctx context.Context, value string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := s.secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return string(decrypted), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(code)), "-", "")
+}
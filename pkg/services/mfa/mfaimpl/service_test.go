@@ -0,0 +1,132 @@
+package mfaimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+func TestIntegrationService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	orgService := &orgtest.FakeOrgService{ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleEditor}}}
+	s := &Service{
+		store:      &xormStore{db: db.InitTestDB(t), now: time.Now},
+		secrets:    fakes.NewFakeSecretsService(),
+		orgService: orgService,
+		log:        log.NewNopLogger(),
+		now:        func() time.Time { return now },
+		enabled:    true,
+		issuer:     defaultIssuer,
+	}
+
+	codeAt := func(t *testing.T, secret string, at time.Time) string {
+		t.Helper()
+		code, err := generateCode(secret, timeStep(at))
+		require.NoError(t, err)
+		return code
+	}
+
+	var secret string
+	var recoveryCodes []string
+
+	t.Run("should enroll and confirm with a code of the new secret", func(t *testing.T) {
+		enrollment, err := s.PendingEnrollment(ctx, 1, "editor")
+		require.NoError(t, err)
+		secret = enrollment.Secret
+
+		pending, err := s.PendingEnrollment(ctx, 1, "editor")
+		require.NoError(t, err)
+		assert.Equal(t, secret, pending.Secret, "the pending enrollment is kept until it expires")
+
+		status, err := s.GetStatus(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, status.Enabled, "enrollment is pending until confirmed")
+
+		_, err = s.ConfirmEnrollment(ctx, 1, "000000")
+		require.ErrorIs(t, err, mfa.ErrInvalidCode)
+
+		recoveryCodes, err = s.ConfirmEnrollment(ctx, 1, codeAt(t, secret, now))
+		require.NoError(t, err)
+		require.Len(t, recoveryCodes, recoveryCodesCount)
+
+		status, err = s.GetStatus(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, &mfa.Status{Enabled: true, RecoveryCodesRemaining: recoveryCodesCount}, status)
+	})
+
+	t.Run("should not accept a code twice", func(t *testing.T) {
+		require.ErrorIs(t, s.Verify(ctx, 1, codeAt(t, secret, now)), mfa.ErrInvalidCode)
+
+		now = now.Add(totpPeriod * time.Second)
+		require.NoError(t, s.Verify(ctx, 1, codeAt(t, secret, now)))
+		require.ErrorIs(t, s.Verify(ctx, 1, codeAt(t, secret, now)), mfa.ErrInvalidCode)
+	})
+
+	t.Run("should consume recovery codes", func(t *testing.T) {
+		require.NoError(t, s.Verify(ctx, 1, recoveryCodes[0]))
+		require.ErrorIs(t, s.Verify(ctx, 1, recoveryCodes[0]), mfa.ErrInvalidCode)
+
+		status, err := s.GetStatus(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, recoveryCodesCount-1, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("should not confirm an expired enrollment", func(t *testing.T) {
+		expired, err := s.Enroll(ctx, 3, "viewer")
+		require.NoError(t, err)
+
+		now = now.Add(pendingEnrollmentTTL)
+		_, err = s.ConfirmEnrollment(ctx, 3, codeAt(t, expired.Secret, now))
+		require.ErrorIs(t, err, mfa.ErrNoPendingSecret)
+
+		pending, err := s.PendingEnrollment(ctx, 3, "viewer")
+		require.NoError(t, err)
+		assert.NotEqual(t, expired.Secret, pending.Secret, "a new enrollment starts once the pending one expired")
+	})
+
+	t.Run("should keep the current secret until a new enrollment is confirmed", func(t *testing.T) {
+		_, err := s.Enroll(ctx, 1, "editor")
+		require.NoError(t, err)
+
+		now = now.Add(totpPeriod * time.Second)
+		require.NoError(t, s.Verify(ctx, 1, codeAt(t, secret, now)))
+	})
+
+	t.Run("should enforce the org policy for the role of the user", func(t *testing.T) {
+		require.NoError(t, s.SetOrgPolicy(ctx, mfa.OrgPolicy{OrgID: 1, Enforced: true, Roles: []org.RoleType{org.RoleAdmin}}))
+		status, err := s.GetStatus(ctx, 2)
+		require.NoError(t, err)
+		assert.False(t, status.Enforced)
+
+		require.NoError(t, s.SetOrgPolicy(ctx, mfa.OrgPolicy{OrgID: 1, Enforced: true, Roles: []org.RoleType{org.RoleEditor, org.RoleEditor}}))
+		status, err = s.GetStatus(ctx, 2)
+		require.NoError(t, err)
+		assert.True(t, status.Enforced)
+
+		policy, err := s.GetOrgPolicy(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []org.RoleType{org.RoleEditor}, policy.Roles)
+
+		require.ErrorIs(t, s.SetOrgPolicy(ctx, mfa.OrgPolicy{OrgID: 1, Enforced: true, Roles: []org.RoleType{"Owner"}}), mfa.ErrInvalidOrgPolicy)
+	})
+
+	t.Run("should reset the user", func(t *testing.T) {
+		require.NoError(t, s.Reset(ctx, 1))
+		require.ErrorIs(t, s.Verify(ctx, 1, codeAt(t, secret, now)), mfa.ErrNotEnrolled)
+	})
+}
@@ -0,0 +1,140 @@
+package mfaimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type store interface {
+	// GetUserMFA returns nil when the user never enrolled.
+	GetUserMFA(ctx context.Context, userID int64) (*userMFA, error)
+	SaveUserMFA(ctx context.Context, m *userMFA) error
+	// UpdateLastUsedStep only updates the time step if it is greater than the stored one,
+	// so that concurrent logins can't use the same code.
+	UpdateLastUsedStep(ctx context.Context, userID, step int64) (bool, error)
+	// UpdateRecoveryCodes only replaces the recovery codes if they were not changed since they were read,
+	// so that concurrent logins can't use the same recovery code.
+	UpdateRecoveryCodes(ctx context.Context, userID int64, previous, codes string) (bool, error)
+	DeleteUserMFA(ctx context.Context, userID int64) error
+	GetOrgPolicy(ctx context.Context, orgID int64) (*mfa.OrgPolicy, error)
+	SetOrgPolicy(ctx context.Context, policy mfa.OrgPolicy) error
+}
+
+type xormStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *xormStore) GetUserMFA(ctx context.Context, userID int64) (*userMFA, error) {
+	var result *userMFA
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		m := userMFA{}
+		has, err := sess.Where("user_id = ?", userID).Get(&m)
+		if err != nil {
+			return err
+		}
+		if has {
+			result = &m
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *xormStore) SaveUserMFA(ctx context.Context, m *userMFA) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		m.Updated = s.now()
+		if m.ID == 0 {
+			m.Created = m.Updated
+			_, err := sess.Insert(m)
+			return err
+		}
+		_, err := sess.ID(m.ID).AllCols().Update(m)
+		return err
+	})
+}
+
+func (s *xormStore) UpdateLastUsedStep(ctx context.Context, userID, step int64) (bool, error) {
+	var updated bool
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_mfa SET last_used_step = ?, updated = ? WHERE user_id = ? AND last_used_step < ?", step, s.now(), userID, step)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		updated = rows > 0
+		return err
+	})
+	return updated, err
+}
+
+func (s *xormStore) UpdateRecoveryCodes(ctx context.Context, userID int64, previous, codes string) (bool, error) {
+	var updated bool
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_mfa SET recovery_codes = ?, updated = ? WHERE user_id = ? AND recovery_codes = ?", codes, s.now(), userID, previous)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		updated = rows > 0
+		return err
+	})
+	return updated, err
+}
+
+func (s *xormStore) DeleteUserMFA(ctx context.Context, userID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM user_mfa WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+func (s *xormStore) GetOrgPolicy(ctx context.Context, orgID int64) (*mfa.OrgPolicy, error) {
+	policy := &mfa.OrgPolicy{OrgID: orgID, Roles: []org.RoleType{}}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		record := orgMFAPolicy{}
+		has, err := sess.Where("org_id = ?", orgID).Get(&record)
+		if err != nil || !has {
+			return err
+		}
+		policy.Enforced = record.Enforced
+		policy.Updated = record.Updated
+		for _, role := range util.SplitString(record.Roles) {
+			policy.Roles = append(policy.Roles, org.RoleType(role))
+		}
+		return nil
+	})
+	return policy, err
+}
+
+func (s *xormStore) SetOrgPolicy(ctx context.Context, policy mfa.OrgPolicy) error {
+	roles := make([]string, 0, len(policy.Roles))
+	for _, role := range policy.Roles {
+		roles = append(roles, string(role))
+	}
+
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		record := orgMFAPolicy{}
+		has, err := sess.Where("org_id = ?", policy.OrgID).Get(&record)
+		if err != nil {
+			return err
+		}
+
+		record.OrgID = policy.OrgID
+		record.Enforced = policy.Enforced
+		record.Roles = strings.Join(roles, ",")
+		record.Updated = s.now()
+		if !has {
+			_, err = sess.Insert(&record)
+			return err
+		}
+		_, err = sess.ID(record.ID).AllCols().Update(&record)
+		return err
+	})
+}
@@ -0,0 +1,72 @@
+package mfaimpl
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the base32 encoding of the SHA1 secret of the RFC 6238 test vectors.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	for unix, expected := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		code, err := generateCode(rfcSecret, timeStep(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, unix)
+	}
+}
+
+func TestValidateCode(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := timeStep(now)
+
+	t.Run("should accept the code of the current, previous and next period", func(t *testing.T) {
+		for _, s := range []int64{step - 1, step, step + 1} {
+			code, err := generateCode(rfcSecret, s)
+			require.NoError(t, err)
+			used, ok := validateCode(rfcSecret, code, now, 0)
+			assert.True(t, ok)
+			assert.Equal(t, s, used)
+		}
+	})
+
+	t.Run("should reject codes outside of the skew", func(t *testing.T) {
+		code, err := generateCode(rfcSecret, step-2)
+		require.NoError(t, err)
+		_, ok := validateCode(rfcSecret, code, now, 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("should reject codes that were already used", func(t *testing.T) {
+		_, ok := validateCode(rfcSecret, "081804", now, step)
+		assert.False(t, ok)
+	})
+
+	t.Run("should ignore spaces", func(t *testing.T) {
+		_, ok := validateCode(rfcSecret, " 081 804", now, 0)
+		assert.True(t, ok)
+	})
+}
+
+func TestProvisioningURI(t *testing.T) {
+	secret, err := generateSecret()
+	require.NoError(t, err)
+
+	u, err := url.Parse(provisioningURI("Grafana", "admin@example.com", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Grafana:admin@example.com", u.Path)
+	assert.Equal(t, secret, u.Query().Get("secret"))
+	assert.Equal(t, "Grafana", u.Query().Get("issuer"))
+}
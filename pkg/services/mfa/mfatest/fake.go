@@ -0,0 +1,70 @@
+package mfatest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+var _ mfa.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedEnabled       bool
+	ExpectedStatus        *mfa.Status
+	ExpectedEnrollment    *mfa.Enrollment
+	ExpectedRecoveryCodes []string
+	ExpectedPolicy        *mfa.OrgPolicy
+	ExpectedErr           error
+	// ExpectedVerifyErr is returned by Verify and ConfirmEnrollment.
+	ExpectedVerifyErr error
+
+	VerifiedCodes  []string
+	ConfirmedCodes []string
+	ResetUserIDs   []int64
+}
+
+func (f *FakeService) IsEnabled() bool {
+	return f.ExpectedEnabled
+}
+
+func (f *FakeService) GetStatus(ctx context.Context, userID int64) (*mfa.Status, error) {
+	if f.ExpectedStatus == nil {
+		return &mfa.Status{}, f.ExpectedErr
+	}
+	return f.ExpectedStatus, f.ExpectedErr
+}
+
+func (f *FakeService) Enroll(ctx context.Context, userID int64, login string) (*mfa.Enrollment, error) {
+	return f.ExpectedEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) PendingEnrollment(ctx context.Context, userID int64, login string) (*mfa.Enrollment, error) {
+	return f.ExpectedEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) ConfirmEnrollment(ctx context.Context, userID int64, code string) ([]string, error) {
+	f.ConfirmedCodes = append(f.ConfirmedCodes, code)
+	return f.ExpectedRecoveryCodes, f.ExpectedVerifyErr
+}
+
+func (f *FakeService) Verify(ctx context.Context, userID int64, code string) error {
+	f.VerifiedCodes = append(f.VerifiedCodes, code)
+	return f.ExpectedVerifyErr
+}
+
+func (f *FakeService) RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	return f.ExpectedRecoveryCodes, f.ExpectedErr
+}
+
+func (f *FakeService) Reset(ctx context.Context, userID int64) error {
+	f.ResetUserIDs = append(f.ResetUserIDs, userID)
+	return f.ExpectedErr
+}
+
+func (f *FakeService) GetOrgPolicy(ctx context.Context, orgID int64) (*mfa.OrgPolicy, error) {
+	return f.ExpectedPolicy, f.ExpectedErr
+}
+
+func (f *FakeService) SetOrgPolicy(ctx context.Context, policy mfa.OrgPolicy) error {
+	return f.ExpectedErr
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -41,6 +42,7 @@ type RulerSrv struct {
 	cfg                *setting.UnifiedAlertingSettings
 	ac                 accesscontrol.AccessControl
 	conditionValidator ConditionValidator
+	audit              audit.Service
 }
 
 var (
@@ -77,6 +79,7 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceT
 	}
 
 	deletedGroups := make(map[ngmodels.AlertRuleGroupKey][]ngmodels.AlertRuleKey)
+	var deletedRules []*ngmodels.AlertRule
 	err = srv.xactManager.InTransaction(c.Req.Context(), func(ctx context.Context) error {
 		unauthz, provisioned := false, false
 		q := ngmodels.ListAlertRulesQuery{
@@ -117,6 +120,7 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceT
 			}
 			rulesToDelete = append(rulesToDelete, uid...)
 			deletedGroups[groupKey] = keys
+			deletedRules = append(deletedRules, rules...)
 		}
 		if len(rulesToDelete) > 0 {
 			return srv.store.DeleteAlertRulesByUID(ctx, c.SignedInUser.OrgID, rulesToDelete...)
@@ -144,6 +148,9 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceT
 		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to delete rule group")
 	}
+	for _, rule := range deletedRules {
+		srv.logAuditEvent(c, audit.ActionDelete, rule.UID, ruleAuditSummary(rule), nil)
+	}
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rules deleted"})
 }
 
@@ -321,6 +328,7 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *contextmodel.ReqContext, ruleGro
 // All operations are performed in a single transaction
 func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) response.Response {
 	var finalChanges *store.GroupDelta
+	var inserted map[string]int64
 	hasAccess := accesscontrol.HasAccess(srv.ac, c)
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
 		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group", groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", c.UserID)
@@ -385,7 +393,7 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			for _, rule := range finalChanges.New {
 				inserts = append(inserts, *rule)
 			}
-			_, err = srv.store.InsertAlertRules(tranCtx, inserts)
+			inserted, err = srv.store.InsertAlertRules(tranCtx, inserts)
 			if err != nil {
 				return fmt.Errorf("failed to add rules: %w", err)
 			}
@@ -425,6 +433,8 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 		return response.JSON(http.StatusAccepted, util.DynMap{"message": "no changes detected in the rule group"})
 	}
 
+	srv.logRuleChanges(c, finalChanges, inserted)
+
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "rule group updated successfully"})
 }
 
@@ -531,3 +541,47 @@ func validateQueries(ctx context.Context, groupChanges *store.GroupDelta, valida
 	}
 	return nil
 }
+
+// logRuleChanges records an audit event for each rule of the group delta. The UIDs of the created
+// rules are the keys of inserted, since they are generated when the request does not set them.
+func (srv RulerSrv) logRuleChanges(c *contextmodel.ReqContext, changes *store.GroupDelta, inserted map[string]int64) {
+	for _, rule := range changes.Delete {
+		srv.logAuditEvent(c, audit.ActionDelete, rule.UID, ruleAuditSummary(rule), nil)
+	}
+	for _, update := range changes.Update {
+		srv.logAuditEvent(c, audit.ActionUpdate, update.New.UID, ruleAuditSummary(update.Existing), ruleAuditSummary(update.New))
+	}
+
+	titles := make(map[string]string, len(changes.New))
+	for _, rule := range changes.New {
+		if rule.UID != "" {
+			titles[rule.UID] = rule.Title
+		}
+	}
+	for uid := range inserted {
+		summary := audit.Summary{
+			"namespaceUid": changes.GroupKey.NamespaceUID,
+			"ruleGroup":    changes.GroupKey.RuleGroup,
+		}
+		if title, ok := titles[uid]; ok {
+			summary["title"] = title
+		}
+		srv.logAuditEvent(c, audit.ActionCreate, uid, nil, summary)
+	}
+}
+
+func (srv RulerSrv) logAuditEvent(c *contextmodel.ReqContext, action audit.Action, uid string, before, after audit.Summary) {
+	event := audit.NewEvent(c.SignedInUser, c.Req, action, audit.Resource(c.SignedInUser.GetOrgID(), audit.KindAlertRule, uid))
+	event.Before = before
+	event.After = after
+	srv.audit.Log(c.Req.Context(), event)
+}
+
+func ruleAuditSummary(rule *ngmodels.AlertRule) audit.Summary {
+	return audit.Summary{
+		"title":        rule.Title,
+		"namespaceUid": rule.NamespaceUID,
+		"ruleGroup":    rule.RuleGroup,
+		"version":      rule.Version,
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/audit/audittest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -599,6 +600,7 @@ func createService(store *fakes.RuleStore) *RulerSrv {
 		log:             log.New("test"),
 		cfg:             nil,
 		ac:              acimpl.ProvideAccessControl(setting.NewCfg()),
+		audit:           audittest.NewFakeService(),
 	}
 }
 
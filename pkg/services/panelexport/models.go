@@ -0,0 +1,58 @@
+package panelexport
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrPanelNotFound         = errutil.NotFound("panelexport.panel-not-found", errutil.WithPublicMessage("Panel not found"))
+	ErrDashboardAccessDenied = errutil.Forbidden("panelexport.dashboard-access-denied", errutil.WithPublicMessage("You cannot view the dashboard of the panel"))
+	ErrInvalidRequest        = errutil.BadRequest("panelexport.invalid-request")
+	ErrPanelHasNoQueries     = errutil.BadRequest("panelexport.no-queries", errutil.WithPublicMessage("The panel has no queries to export"))
+	ErrFramesNotJoinable     = errutil.BadRequest("panelexport.frames-not-joinable")
+	ErrQueryFailed           = errutil.BadRequest("panelexport.query-failed").MustTemplate(
+		"query {{ .Public.refId }} failed: {{ .Error }}",
+		errutil.WithPublic("Query {{ .Public.refId }} failed: {{ .Public.error }}"),
+	)
+)
+
+// Format is the file format of the exported data.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatNDJSON  Format = "ndjson"
+	FormatParquet Format = "parquet"
+)
+
+// QueryPanelQuery selects the panel to query and overrides the time range and the variables of its dashboard.
+type QueryPanelQuery struct {
+	User         *user.SignedInUser
+	DashboardUID string
+	PanelID      int64
+	// From and To default to the time range of the dashboard.
+	From string
+	To   string
+	// Timezone defaults to the timezone of the dashboard, or UTC.
+	Timezone string
+	// Variables are the values of the template variables, the current values of the dashboard are used for the others.
+	Variables map[string][]string
+	// MaxDataPoints defaults to the max data points of the panel.
+	MaxDataPoints int64
+	// RefID only returns the frames of this query when set.
+	RefID string
+}
+
+// PanelData holds the frames returned by the queries of a panel, in the order of the queries.
+type PanelData struct {
+	DashboardSlug string
+	PanelTitle    string
+	Frames        data.Frames
+	// Location is the timezone the time range of the queries was resolved in.
+	Location *time.Location
+}
@@ -0,0 +1,11 @@
+package panelexport
+
+import (
+	"context"
+)
+
+// Service runs the queries of dashboard panels to export their data.
+type Service interface {
+	// QueryPanel runs the queries of a panel with the permissions of the user and returns the resulting frames.
+	QueryPanel(ctx context.Context, query *QueryPanelQuery) (*PanelData, error)
+}
@@ -0,0 +1,248 @@
+package panelexportimpl
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/slugify"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/panelexport"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+var contentTypes = map[panelexport.Format]string{
+	panelexport.FormatCSV:     "text/csv; charset=utf-8",
+	panelexport.FormatNDJSON:  "application/x-ndjson",
+	panelexport.FormatParquet: "application/vnd.apache.parquet",
+}
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Get("/api/dashboards/uid/:uid/panels/:panelId/export", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), routing.Wrap(s.handleExport))
+}
+
+// swagger:route GET /dashboards/uid/{uid}/panels/{panelId}/export dashboards exportPanelData
+//
+// Export the data of a panel.
+//
+// Runs the queries of the panel with the permissions of the user and returns the resulting data as CSV, JSON Lines
+// or Parquet. The frames returned by the queries are joined on time in the CSV and Parquet formats.
+//
+// Produces:
+// - text/csv
+// - application/x-ndjson
+// - application/vnd.apache.parquet
+// - application/gzip
+//
+// Responses:
+// 200: exportPanelDataResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleExport(c *contextmodel.ReqContext) response.Response {
+	panelID, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "panelId is invalid", err)
+	}
+
+	format := panelexport.Format(c.Query("format"))
+	if format == "" {
+		format = panelexport.FormatCSV
+	}
+	if _, ok := contentTypes[format]; !ok {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("format must be %s, %s or %s", panelexport.FormatCSV, panelexport.FormatNDJSON, panelexport.FormatParquet), nil)
+	}
+
+	compression := c.Query("compression")
+	if compression != "" && compression != "gzip" {
+		return response.Error(http.StatusBadRequest, "compression must be gzip", nil)
+	}
+
+	limit := s.maxRows
+	if l := c.QueryInt("limit"); l > 0 && l < limit {
+		limit = l
+	}
+
+	variables := make(map[string][]string)
+	for key, values := range c.Req.URL.Query() {
+		if name, ok := strings.CutPrefix(key, "var-"); ok {
+			variables[name] = values
+		}
+	}
+
+	panelData, err := s.QueryPanel(c.Req.Context(), &panelexport.QueryPanelQuery{
+		User:          c.SignedInUser,
+		DashboardUID:  web.Params(c.Req)[":uid"],
+		PanelID:       panelID,
+		From:          c.Query("from"),
+		To:            c.Query("to"),
+		Timezone:      c.Query("timezone"),
+		Variables:     variables,
+		MaxDataPoints: c.QueryInt64("maxDataPoints"),
+		RefID:         c.Query("refId"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to query panel", err)
+	}
+
+	res := &exportResponse{
+		format:   format,
+		frames:   panelData.Frames,
+		location: panelData.Location,
+		limit:    limit,
+		gzip:     compression == "gzip",
+		filename: exportFilename(panelData, panelID, format),
+	}
+	if res.gzip {
+		res.filename += ".gz"
+	}
+	rows := 0
+	if format == panelexport.FormatNDJSON {
+		for _, frame := range res.frames {
+			n, _ := frame.RowLen()
+			rows += n
+		}
+	} else {
+		if res.table, err = exportTable(panelData.Frames); err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to export panel", err)
+		}
+		rows, _ = res.table.RowLen()
+	}
+	res.truncated = rows > limit
+
+	return res
+}
+
+// exportResponse streams the data of a panel to the client, in the requested format.
+type exportResponse struct {
+	format    panelexport.Format
+	frames    data.Frames
+	table     *data.Frame
+	location  *time.Location
+	limit     int
+	truncated bool
+	gzip      bool
+	filename  string
+}
+
+func (r *exportResponse) Status() int {
+	return http.StatusOK
+}
+
+func (r *exportResponse) Body() []byte {
+	return nil
+}
+
+func (r *exportResponse) WriteTo(ctx *contextmodel.ReqContext) {
+	header := ctx.Resp.Header()
+	header.Set("Content-Type", contentTypes[r.format])
+	if r.gzip {
+		header.Set("Content-Type", "application/gzip")
+	}
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, r.filename))
+	if r.truncated {
+		header.Set(truncatedHeader, "true")
+	}
+	ctx.Resp.WriteHeader(http.StatusOK)
+
+	var w io.Writer = ctx.Resp
+	if r.gzip {
+		gz := gzip.NewWriter(ctx.Resp)
+		defer func() {
+			if err := gz.Close(); err != nil {
+				ctx.Logger.Error("Failed to write panel export", "error", err)
+			}
+		}()
+		w = gz
+	}
+
+	var err error
+	switch r.format {
+	case panelexport.FormatCSV:
+		err = writeCSV(w, r.table, r.location, r.limit)
+	case panelexport.FormatNDJSON:
+		err = writeNDJSON(w, r.frames, r.location, r.limit)
+	case panelexport.FormatParquet:
+		err = writeParquet(w, r.table, r.limit)
+	}
+	// the status is already sent, the client gets a truncated file
+	if err != nil {
+		ctx.Logger.Error("Failed to write panel export", "error", err)
+	}
+}
+
+func exportFilename(panelData *panelexport.PanelData, panelID int64, format panelexport.Format) string {
+	name := slugify.Slugify(panelData.PanelTitle)
+	if name == "" {
+		name = fmt.Sprintf("panel-%d", panelID)
+	}
+	if panelData.DashboardSlug != "" {
+		name = panelData.DashboardSlug + "-" + name
+	}
+	return name + "." + string(format)
+}
+
+// swagger:parameters exportPanelData
+type ExportPanelDataParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	PanelID int64 `json:"panelId"`
+	// File format of the data.
+	// in:query
+	// required:false
+	// enum: csv,ndjson,parquet
+	// default: csv
+	Format string `json:"format"`
+	// Start of the time range, defaults to the time range of the dashboard.
+	// in:query
+	// required:false
+	From string `json:"from"`
+	// End of the time range, defaults to the time range of the dashboard.
+	// in:query
+	// required:false
+	To string `json:"to"`
+	// Timezone of the time range and of the times of the data, defaults to the timezone of the dashboard, or UTC.
+	// in:query
+	// required:false
+	Timezone string `json:"timezone"`
+	// Only export the data of this query.
+	// in:query
+	// required:false
+	RefID string `json:"refId"`
+	// Maximum number of rows, defaults to and is capped by the max_rows setting.
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+	// Maximum number of data points of the queries, defaults to the max data points of the panel.
+	// in:query
+	// required:false
+	MaxDataPoints int64 `json:"maxDataPoints"`
+	// Compress the file with gzip.
+	// in:query
+	// required:false
+	// enum: gzip
+	Compression string `json:"compression"`
+}
+
+// swagger:response exportPanelDataResponse
+type ExportPanelDataResponse struct {
+	// in: body
+	Body []byte `json:"body"`
+}
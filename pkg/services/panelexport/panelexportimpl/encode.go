@@ -0,0 +1,165 @@
+package panelexportimpl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/ipc"
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/compress"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// writeCSV writes the rows of the table as CSV, after a header with the names of its fields.
+func writeCSV(w io.Writer, table *data.Frame, loc *time.Location, limit int) error {
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(table.Fields))
+	for i, field := range table.Fields {
+		record[i] = field.Name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	rows, _ := table.RowLen()
+	for row := 0; row < rows && row < limit; row++ {
+		for i, field := range table.Fields {
+			record[i] = formatCSVValue(field, row, loc)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCSVValue(field *data.Field, row int, loc *time.Location) string {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return ""
+	}
+
+	switch v := v.(type) {
+	case time.Time:
+		return v.In(loc).Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case string:
+		return v
+	case json.RawMessage:
+		return string(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// writeNDJSON writes the rows of the frames as JSON objects, one per line, with the refId of their query.
+func writeNDJSON(w io.Writer, frames data.Frames, loc *time.Location, limit int) error {
+	bw := bufio.NewWriter(w)
+	var line bytes.Buffer
+
+	for _, frame := range frames {
+		names := newColumnNames()
+		keys := make([][]byte, len(frame.Fields))
+		for i, field := range frame.Fields {
+			keys[i], _ = json.Marshal(names.add(fieldName(frame, field)))
+		}
+		refID, _ := json.Marshal(frame.RefID)
+
+		rows, _ := frame.RowLen()
+		for row := 0; row < rows && limit > 0; row++ {
+			line.Reset()
+			line.WriteString(`{"refId":`)
+			line.Write(refID)
+			for i, field := range frame.Fields {
+				value, err := json.Marshal(jsonValue(field, row, loc))
+				if err != nil {
+					return err
+				}
+				line.WriteByte(',')
+				line.Write(keys[i])
+				line.WriteByte(':')
+				line.Write(value)
+			}
+			line.WriteString("}\n")
+
+			if _, err := bw.Write(line.Bytes()); err != nil {
+				return err
+			}
+			limit--
+		}
+	}
+
+	return bw.Flush()
+}
+
+func jsonValue(field *data.Field, row int, loc *time.Location) interface{} {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case time.Time:
+		return v.In(loc).Format(time.RFC3339Nano)
+	case float64:
+		// JSON has no NaN or infinity
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return nil
+		}
+	}
+	return v
+}
+
+// writeParquet writes the rows of the table as a Parquet file compressed with Snappy.
+func writeParquet(w io.Writer, table *data.Frame, limit int) error {
+	// the SDK and the parquet writer use different versions of arrow, the frame is converted through the IPC file format they share
+	b, err := table.MarshalArrow()
+	if err != nil {
+		return err
+	}
+	reader, err := ipc.NewFileReader(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	fw, err := pqarrow.NewFileWriter(reader.Schema(), w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < reader.NumRecords() && limit > 0; i++ {
+		record, err := reader.Record(i)
+		if err != nil {
+			return err
+		}
+		if record.NumRows() > int64(limit) {
+			record = record.NewSlice(0, int64(limit))
+			defer record.Release()
+		}
+		if err := fw.Write(record); err != nil {
+			return err
+		}
+		limit -= int(record.NumRows())
+	}
+
+	return fw.Close()
+}
@@ -0,0 +1,159 @@
+package panelexportimpl
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/apache/arrow/go/v12/parquet/pqarrow"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/panelexport"
+)
+
+var (
+	t0 = time.Date(2023, 9, 12, 10, 0, 0, 0, time.UTC)
+	t1 = t0.Add(time.Minute)
+	t2 = t0.Add(2 * time.Minute)
+)
+
+func seriesFrame(refID string, labels data.Labels, times []time.Time, values []float64) *data.Frame {
+	frame := data.NewFrame("",
+		data.NewField("Time", nil, times),
+		data.NewField("Value", labels, values),
+	)
+	frame.RefID = refID
+	return frame
+}
+
+func TestExportTable(t *testing.T) {
+	t.Run("renames the fields of a single frame", func(t *testing.T) {
+		frame := seriesFrame("A", data.Labels{"job": "api"}, []time.Time{t0}, []float64{1})
+
+		table, err := exportTable(data.Frames{frame})
+		require.NoError(t, err)
+		require.Len(t, table.Fields, 2)
+		assert.Equal(t, "Time", table.Fields[0].Name)
+		assert.Equal(t, "Value {job=api}", table.Fields[1].Name)
+		assert.Equal(t, "Value", frame.Fields[1].Name, "the frame is not changed")
+	})
+
+	t.Run("joins the frames on time", func(t *testing.T) {
+		frames := data.Frames{
+			seriesFrame("A", data.Labels{"job": "api"}, []time.Time{t1, t0}, []float64{2, 1}),
+			seriesFrame("A", data.Labels{"job": "db"}, []time.Time{t1, t2}, []float64{3, 4}),
+			seriesFrame("B", nil, []time.Time{t0}, []float64{5}),
+		}
+		frames[2].Fields[1].Config = &data.FieldConfig{DisplayNameFromDS: "errors"}
+
+		table, err := exportTable(frames)
+		require.NoError(t, err)
+
+		require.Len(t, table.Fields, 4)
+		assert.Equal(t, []string{"Time", "Value {job=api}", "Value {job=db}", "errors"}, []string{table.Fields[0].Name, table.Fields[1].Name, table.Fields[2].Name, table.Fields[3].Name})
+		rows, err := table.RowLen()
+		require.NoError(t, err)
+		require.Equal(t, 3, rows)
+
+		values := func(field *data.Field) []interface{} {
+			var v []interface{}
+			for i := 0; i < field.Len(); i++ {
+				c, ok := field.ConcreteAt(i)
+				if !ok {
+					c = nil
+				}
+				v = append(v, c)
+			}
+			return v
+		}
+		assert.Equal(t, []interface{}{t0, t1, t2}, values(table.Fields[0]))
+		assert.Equal(t, []interface{}{1.0, 2.0, nil}, values(table.Fields[1]))
+		assert.Equal(t, []interface{}{nil, 3.0, 4.0}, values(table.Fields[2]))
+		assert.Equal(t, []interface{}{5.0, nil, nil}, values(table.Fields[3]))
+	})
+
+	t.Run("does not join frames without time", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("", data.NewField("host", nil, []string{"a"})),
+			data.NewFrame("", data.NewField("host", nil, []string{"b"})),
+		}
+		_, err := exportTable(frames)
+		require.ErrorIs(t, err, panelexport.ErrFramesNotJoinable)
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	table := data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{t0, t1, t2}),
+		data.NewField("Value", nil, []*float64{ptr(1.5), nil, ptr(3)}),
+		data.NewField("Host", nil, []string{"a,1", "b", `c"3`}),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, table, paris, 2))
+
+	assert.Equal(t, "Time,Value,Host\n"+
+		"2023-09-12T12:00:00+02:00,1.5,\"a,1\"\n"+
+		"2023-09-12T12:01:00+02:00,,b\n", buf.String())
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	frames := data.Frames{
+		seriesFrame("A", data.Labels{"job": "api"}, []time.Time{t0, t1}, []float64{1, 2}),
+		seriesFrame("B", nil, []time.Time{t0}, []float64{3}),
+	}
+
+	t.Run("writes the rows of all the frames", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeNDJSON(&buf, frames, time.UTC, 10))
+
+		assert.Equal(t, `{"refId":"A","Time":"2023-09-12T10:00:00Z","Value {job=api}":1}`+"\n"+
+			`{"refId":"A","Time":"2023-09-12T10:01:00Z","Value {job=api}":2}`+"\n"+
+			`{"refId":"B","Time":"2023-09-12T10:00:00Z","Value":3}`+"\n", buf.String())
+	})
+
+	t.Run("limits the rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeNDJSON(&buf, frames, time.UTC, 1))
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")))
+	})
+}
+
+func TestWriteParquet(t *testing.T) {
+	table := data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{t0, t1, t2}),
+		data.NewField("Value {job=api}", nil, []*float64{ptr(1), nil, ptr(3)}),
+		data.NewField("Host", nil, []string{"a", "b", "c"}),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeParquet(&buf, table, 2))
+
+	reader, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, reader.Close()) }()
+	assert.Equal(t, int64(2), reader.NumRows())
+
+	fr, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	read, err := fr.ReadTable(context.Background())
+	require.NoError(t, err)
+	defer read.Release()
+
+	var names []string
+	for _, field := range read.Schema().Fields() {
+		names = append(names, field.Name)
+	}
+	assert.Equal(t, []string{"Time", "Value {job=api}", "Host"}, names)
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
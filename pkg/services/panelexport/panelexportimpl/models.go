@@ -0,0 +1,18 @@
+package panelexportimpl
+
+const (
+	defaultFrom          = "now-6h"
+	defaultTo            = "now"
+	defaultMaxDataPoints = 1000
+	defaultMaxRows       = 1000000
+
+	// mixedDatasourceUID is the data source of the panels whose queries have their own data source
+	mixedDatasourceUID = "-- Mixed --"
+	// dashboardDatasourceUID is the data source of the panels reusing the results of another panel
+	dashboardDatasourceUID = "-- Dashboard --"
+
+	allValue = "$__all"
+
+	// truncatedHeader is set on the responses that do not contain all the rows of the panel, because of the row limit
+	truncatedHeader = "X-Grafana-Export-Truncated"
+)
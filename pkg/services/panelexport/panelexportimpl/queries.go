@@ -0,0 +1,123 @@
+package panelexportimpl
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/panelexport"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+)
+
+// panelQueries returns copies of the queries of the panel, with their variables interpolated and their data source resolved.
+func (s *Service) panelQueries(ctx context.Context, orgID int64, panel *simplejson.Json, variables map[string][]string) ([]*simplejson.Json, error) {
+	panelDatasource := dashboards.InterpolateVariables(panel.Get("datasource").Interface(), variables)
+	if uid := datasourceUID(panelDatasource); uid == dashboardDatasourceUID {
+		return nil, panelexport.ErrInvalidRequest.Errorf("the panel reuses the results of another panel, export the other panel instead")
+	}
+
+	targets := panel.Get("targets").MustArray()
+	hasExpression := false
+	for _, obj := range targets {
+		if expr.NodeTypeFromDatasourceUID(datasourceUID(simplejson.NewFromAny(obj).Get("datasource").Interface())) == expr.TypeCMDNode {
+			hasExpression = true
+		}
+	}
+
+	queries := make([]*simplejson.Json, 0, len(targets))
+	for i, obj := range targets {
+		original := simplejson.NewFromAny(obj)
+		// the expressions need the hidden queries they use, and drop their results
+		if !hasExpression && original.Get("hide").MustBool() {
+			continue
+		}
+
+		query := simplejson.NewFromAny(dashboards.InterpolateVariables(original.Interface(), variables))
+		if query.Get("refId").MustString() == "" {
+			query.Set("refId", refIDFromIndex(i))
+		}
+
+		ds := query.Get("datasource").Interface()
+		if ds == nil || datasourceUID(ds) == mixedDatasourceUID {
+			ds = panelDatasource
+		}
+		ref, err := s.datasourceRef(ctx, orgID, ds)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("datasource", ref)
+		queries = append(queries, query)
+	}
+
+	if len(queries) == 0 {
+		return nil, panelexport.ErrPanelHasNoQueries.Errorf("panel %d has no queries", panel.Get("id").MustInt64())
+	}
+	return queries, nil
+}
+
+// datasourceRef resolves the data source of a query, which can be a reference, the name of a data source in the
+// dashboards saved before Grafana 8.3, or null for the default data source.
+func (s *Service) datasourceRef(ctx context.Context, orgID int64, ds interface{}) (map[string]interface{}, error) {
+	uid := datasourceUID(ds)
+	if uid == grafanads.DatasourceName {
+		uid = grafanads.DatasourceUID
+	}
+	if expr.NodeTypeFromDatasourceUID(uid) != expr.TypeDatasourceNode || uid == grafanads.DatasourceUID {
+		return map[string]interface{}{"uid": uid, "type": datasourceType(ds)}, nil
+	}
+	if uid == mixedDatasourceUID {
+		return nil, panelexport.ErrInvalidRequest.Errorf("a query of the panel has no data source")
+	}
+
+	var (
+		dataSource *datasources.DataSource
+		err        error
+	)
+	if uid == "" {
+		dataSource, err = s.dataSourceService.GetDefaultDataSource(ctx, &datasources.GetDefaultDataSourceQuery{OrgID: orgID})
+	} else {
+		dataSource, err = s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: orgID})
+		// the dashboards saved before Grafana 8.3 reference the data sources by name, data source variables can hold names too
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			dataSource, err = s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{Name: uid, OrgID: orgID})
+		}
+	}
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return nil, panelexport.ErrInvalidRequest.Errorf("data source %s of the panel not found", uid)
+		}
+		return nil, err
+	}
+
+	return map[string]interface{}{"uid": dataSource.UID, "type": dataSource.Type}, nil
+}
+
+func datasourceUID(ds interface{}) string {
+	switch v := ds.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		uid, _ := v["uid"].(string)
+		return uid
+	}
+	return ""
+}
+
+func datasourceType(ds interface{}) string {
+	if v, ok := ds.(map[string]interface{}); ok {
+		t, _ := v["type"].(string)
+		return t
+	}
+	return ""
+}
+
+// refIDFromIndex returns the refId the frontend gives to the queries without one: A to Z, then AA, AB...
+func refIDFromIndex(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return refIDFromIndex(i/26-1) + string(rune('A'+i%26))
+}
@@ -0,0 +1,217 @@
+package panelexportimpl
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/panelexport"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var _ panelexport.Service = (*Service)(nil)
+
+type Service struct {
+	dashboardService  dashboards.DashboardService
+	dataSourceService datasources.DataSourceService
+	queryService      query.Service
+	accessControl     ac.AccessControl
+	log               log.Logger
+
+	enabled bool
+	maxRows int
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	dashboardService dashboards.DashboardService,
+	dataSourceService datasources.DataSourceService,
+	queryService query.Service,
+	accessControl ac.AccessControl,
+	routeRegister routing.RouteRegister,
+) *Service {
+	section := cfg.SectionWithEnvOverrides("panel_export")
+	s := &Service{
+		dashboardService:  dashboardService,
+		dataSourceService: dataSourceService,
+		queryService:      queryService,
+		accessControl:     accessControl,
+		log:               log.New("panelexport"),
+		enabled:           section.Key("enabled").MustBool(false),
+		maxRows:           section.Key("max_rows").MustInt(defaultMaxRows),
+	}
+
+	if s.enabled {
+		s.registerAPIEndpoints(routeRegister)
+	}
+
+	return s
+}
+
+func (s *Service) QueryPanel(ctx context.Context, q *panelexport.QueryPanelQuery) (*panelexport.PanelData, error) {
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: q.DashboardUID, OrgID: q.User.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := guardian.NewByDashboard(ctx, dash, q.User.OrgID, q.User)
+	if err != nil {
+		return nil, err
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return nil, panelexport.ErrDashboardAccessDenied.Errorf("user %d cannot view dashboard %s", q.User.UserID, q.DashboardUID)
+	}
+
+	panel := dashboards.FindPanel(dash.Data, q.PanelID)
+	if panel == nil {
+		return nil, panelexport.ErrPanelNotFound.Errorf("panel %d not found in dashboard %s", q.PanelID, q.DashboardUID)
+	}
+
+	loc, err := location(q.Timezone, dash.Data.Get("timezone").MustString())
+	if err != nil {
+		return nil, err
+	}
+	from, to := q.From, q.To
+	if from == "" || to == "" {
+		from = dash.Data.GetPath("time", "from").MustString(defaultFrom)
+		to = dash.Data.GetPath("time", "to").MustString(defaultTo)
+	}
+	timeRange := legacydata.NewDataTimeRange(from, to)
+	fromTime, err := timeRange.ParseFrom(legacydata.WithLocation(loc))
+	if err != nil {
+		return nil, panelexport.ErrInvalidRequest.Errorf("invalid time range from %q: %w", from, err)
+	}
+	toTime, err := timeRange.ParseTo(legacydata.WithLocation(loc))
+	if err != nil {
+		return nil, panelexport.ErrInvalidRequest.Errorf("invalid time range to %q: %w", to, err)
+	}
+
+	variables, err := templateVariables(dash.Data, q.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	maxDataPoints := q.MaxDataPoints
+	if maxDataPoints <= 0 {
+		maxDataPoints = panel.Get("maxDataPoints").MustInt64(defaultMaxDataPoints)
+	}
+	minInterval := time.Millisecond
+	if interval := dashboards.Interpolate(panel.Get("interval").MustString(), variables); interval != "" {
+		if minInterval, err = intervalv2.ParseIntervalStringToTimeDuration(interval); err != nil {
+			return nil, panelexport.ErrInvalidRequest.Errorf("invalid min interval %q of panel %d: %w", interval, q.PanelID, err)
+		}
+	}
+	interval := intervalv2.NewCalculator().Calculate(backend.TimeRange{From: fromTime, To: toTime}, minInterval, maxDataPoints)
+
+	variables["__from"] = []string{strconv.FormatInt(fromTime.UnixMilli(), 10)}
+	variables["__to"] = []string{strconv.FormatInt(toTime.UnixMilli(), 10)}
+	variables["__interval"] = []string{interval.Text}
+	variables["__interval_ms"] = []string{strconv.FormatInt(interval.Milliseconds(), 10)}
+
+	queries, err := s.panelQueries(ctx, q.User.OrgID, panel, variables)
+	if err != nil {
+		return nil, err
+	}
+	if q.RefID != "" && !hasRefID(queries, q.RefID) {
+		return nil, panelexport.ErrInvalidRequest.Errorf("query %s not found in panel %d", q.RefID, q.PanelID)
+	}
+	for _, query := range queries {
+		query.Set("maxDataPoints", maxDataPoints)
+		query.Set("intervalMs", interval.Milliseconds())
+	}
+
+	res, err := s.queryService.QueryData(ctx, q.User, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(fromTime.UnixMilli(), 10),
+		To:      strconv.FormatInt(toTime.UnixMilli(), 10),
+		Queries: queries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	frames, err := responseFrames(res, queries, q.RefID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &panelexport.PanelData{
+		DashboardSlug: dash.Slug,
+		PanelTitle:    panel.Get("title").MustString(),
+		Frames:        frames,
+		Location:      loc,
+	}, nil
+}
+
+// responseFrames returns the frames of the responses in the order of the queries, skipping the hidden queries
+// that are only used by expressions.
+func responseFrames(res *backend.QueryDataResponse, queries []*simplejson.Json, refID string) (data.Frames, error) {
+	var frames data.Frames
+	for _, query := range queries {
+		id := query.Get("refId").MustString()
+		if refID != "" && id != refID {
+			continue
+		}
+
+		dr, ok := res.Responses[id]
+		if !ok {
+			continue
+		}
+		if dr.Error != nil {
+			return nil, panelexport.ErrQueryFailed.Build(errutil.TemplateData{
+				Public: map[string]interface{}{"refId": id, "error": dr.Error.Error()},
+				Error:  dr.Error,
+			})
+		}
+
+		for _, frame := range dr.Frames {
+			if frame.RefID == "" {
+				frame.RefID = id
+			}
+			frames = append(frames, frame)
+		}
+	}
+	return frames, nil
+}
+
+func hasRefID(queries []*simplejson.Json, refID string) bool {
+	for _, query := range queries {
+		if query.Get("refId").MustString() == refID {
+			return true
+		}
+	}
+	return false
+}
+
+// location returns the timezone of the request, or of the dashboard. The browser timezone is not known on the server, UTC is used instead.
+func location(timezones ...string) (*time.Location, error) {
+	for _, tz := range timezones {
+		switch tz {
+		case "", "browser":
+			continue
+		case "utc":
+			return time.UTC, nil
+		}
+
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, panelexport.ErrInvalidRequest.Errorf("invalid timezone %q", tz)
+		}
+		return loc, nil
+	}
+	return time.UTC, nil
+}
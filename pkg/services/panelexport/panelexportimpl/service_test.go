@@ -0,0 +1,297 @@
+package panelexportimpl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/panelexport"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+const exportDashboard = `{
+  "title": "Service overview",
+  "timezone": "Europe/Paris",
+  "time": {"from": "now-1h", "to": "now"},
+  "templating": {
+    "list": [
+      {"name": "ds", "type": "datasource", "current": {"value": "prom-uid"}},
+      {"name": "env", "type": "custom", "current": {"value": "prod"}}
+    ]
+  },
+  "panels": [
+    {
+      "id": 1,
+      "title": "Requests",
+      "datasource": {"uid": "${ds}"},
+      "maxDataPoints": 500,
+      "targets": [
+        {"refId": "A", "expr": "rate(requests{env=\"$env\"}[$__interval])"},
+        {"refId": "B", "expr": "up", "hide": true},
+        {"refId": "C", "datasource": "Loki", "expr": "{env=\"$env\"}"}
+      ]
+    },
+    {
+      "id": 2,
+      "type": "row",
+      "collapsed": true,
+      "panels": [
+        {"id": 3, "title": "Errors", "targets": [{"expr": "errors"}]}
+      ]
+    },
+    {"id": 4, "title": "Reused", "datasource": {"uid": "-- Dashboard --"}, "targets": [{"panelId": 1}]}
+  ]
+}`
+
+type testEnv struct {
+	service      *Service
+	queryService *query.FakeQueryService
+	user         *user.SignedInUser
+}
+
+func setupTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(func(_ context.Context, q *dashboards.GetDashboardQuery) *dashboards.Dashboard {
+		data, err := simplejson.NewJson([]byte(exportDashboard))
+		require.NoError(t, err)
+		return &dashboards.Dashboard{UID: q.UID, OrgID: q.OrgID, Slug: "service-overview", Title: "Service overview", Data: data}
+	}, nil).Maybe()
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+	env := &testEnv{
+		queryService: query.NewFakeQueryService(t),
+		user:         &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer},
+	}
+	env.service = &Service{
+		dashboardService: dashboardService,
+		dataSourceService: &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+			{UID: "prom-uid", Name: "Prometheus", Type: "prometheus", OrgID: 1, IsDefault: true},
+			{UID: "loki-uid", Name: "Loki", Type: "loki", OrgID: 1},
+		}},
+		queryService:  env.queryService,
+		accessControl: actest.FakeAccessControl{ExpectedEvaluate: true},
+		log:           log.NewNopLogger(),
+		enabled:       true,
+		maxRows:       defaultMaxRows,
+	}
+	return env
+}
+
+func (env *testEnv) expectQuery(responses backend.Responses) *dtos.MetricRequest {
+	var req dtos.MetricRequest
+	env.queryService.On("QueryData", mock.Anything, env.user, false, mock.Anything).Run(func(args mock.Arguments) {
+		req = args.Get(3).(dtos.MetricRequest)
+	}).Return(&backend.QueryDataResponse{Responses: responses}, nil).Once()
+	return &req
+}
+
+func TestService_QueryPanel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs the queries of the panel with the variables of the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		frameA := data.NewFrame("", data.NewField("Time", nil, []time.Time{time.Now()}), data.NewField("Value", nil, []float64{1}))
+		frameC := data.NewFrame("", data.NewField("Line", nil, []string{"log"}))
+		req := env.expectQuery(backend.Responses{
+			"A": {Frames: data.Frames{frameA}},
+			"C": {Frames: data.Frames{frameC}},
+		})
+
+		result, err := env.service.QueryPanel(ctx, &panelexport.QueryPanelQuery{User: env.user, DashboardUID: "abc", PanelID: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Requests", result.PanelTitle)
+		assert.Equal(t, "service-overview", result.DashboardSlug)
+		assert.Equal(t, "Europe/Paris", result.Location.String())
+		require.Len(t, result.Frames, 2)
+		assert.Equal(t, "A", result.Frames[0].RefID)
+		assert.Equal(t, "C", result.Frames[1].RefID)
+
+		from, to := req.From, req.To
+		assert.NotEmpty(t, from)
+		assert.NotEmpty(t, to)
+		require.Len(t, req.Queries, 2, "the hidden query is skipped")
+
+		a := req.Queries[0]
+		assert.Equal(t, "rate(requests{env=\"prod\"}[5s])", a.Get("expr").MustString())
+		assert.Equal(t, "prom-uid", a.GetPath("datasource", "uid").MustString())
+		assert.Equal(t, "prometheus", a.GetPath("datasource", "type").MustString())
+		assert.Equal(t, int64(500), a.Get("maxDataPoints").MustInt64())
+		assert.Equal(t, int64(5000), a.Get("intervalMs").MustInt64())
+
+		c := req.Queries[1]
+		assert.Equal(t, "{env=\"prod\"}", c.Get("expr").MustString())
+		assert.Equal(t, "loki-uid", c.GetPath("datasource", "uid").MustString(), "the data source is referenced by name")
+	})
+
+	t.Run("overrides the time range and the variables of the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		req := env.expectQuery(backend.Responses{"A": {}})
+
+		_, err := env.service.QueryPanel(ctx, &panelexport.QueryPanelQuery{
+			User:          env.user,
+			DashboardUID:  "abc",
+			PanelID:       1,
+			From:          "1694512800000",
+			To:            "1694516400000",
+			Timezone:      "utc",
+			Variables:     map[string][]string{"env": {"dev", "staging"}},
+			MaxDataPoints: 60,
+			RefID:         "A",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "1694512800000", req.From)
+		assert.Equal(t, "1694516400000", req.To)
+		assert.Equal(t, "rate(requests{env=\"{dev,staging}\"}[1m])", req.Queries[0].Get("expr").MustString())
+	})
+
+	t.Run("uses the default data source and the panels of collapsed rows", func(t *testing.T) {
+		env := setupTestEnv(t)
+		req := env.expectQuery(backend.Responses{"A": {}})
+
+		_, err := env.service.QueryPanel(ctx, &panelexport.QueryPanelQuery{User: env.user, DashboardUID: "abc", PanelID: 3})
+		require.NoError(t, err)
+
+		require.Len(t, req.Queries, 1)
+		assert.Equal(t, "A", req.Queries[0].Get("refId").MustString())
+		assert.Equal(t, "prom-uid", req.Queries[0].GetPath("datasource", "uid").MustString())
+	})
+
+	t.Run("returns the errors of the queries", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.expectQuery(backend.Responses{"A": {Error: errors.New("bad expression")}})
+
+		_, err := env.service.QueryPanel(ctx, &panelexport.QueryPanelQuery{User: env.user, DashboardUID: "abc", PanelID: 1})
+		require.ErrorIs(t, err, panelexport.ErrQueryFailed)
+		assert.Contains(t, err.Error(), "bad expression")
+	})
+
+	t.Run("validates the request", func(t *testing.T) {
+		env := setupTestEnv(t)
+
+		testCases := map[string]struct {
+			query    panelexport.QueryPanelQuery
+			expected error
+		}{
+			"unknown panel":    {query: panelexport.QueryPanelQuery{PanelID: 10}, expected: panelexport.ErrPanelNotFound},
+			"reused panel":     {query: panelexport.QueryPanelQuery{PanelID: 4}, expected: panelexport.ErrInvalidRequest},
+			"unknown query":    {query: panelexport.QueryPanelQuery{PanelID: 1, RefID: "D"}, expected: panelexport.ErrInvalidRequest},
+			"unknown variable": {query: panelexport.QueryPanelQuery{PanelID: 1, Variables: map[string][]string{"x": {"y"}}}, expected: panelexport.ErrInvalidRequest},
+			"invalid timezone": {query: panelexport.QueryPanelQuery{PanelID: 1, Timezone: "Mars/Olympus"}, expected: panelexport.ErrInvalidRequest},
+			"invalid range":    {query: panelexport.QueryPanelQuery{PanelID: 1, From: "yesterday", To: "now"}, expected: panelexport.ErrInvalidRequest},
+		}
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				q := tc.query
+				q.User = env.user
+				q.DashboardUID = "abc"
+				_, err := env.service.QueryPanel(ctx, &q)
+				require.ErrorIs(t, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("requires the user to view the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: false})
+
+		_, err := env.service.QueryPanel(ctx, &panelexport.QueryPanelQuery{User: env.user, DashboardUID: "abc", PanelID: 1})
+		require.ErrorIs(t, err, panelexport.ErrDashboardAccessDenied)
+	})
+}
+
+func TestService_handleExport(t *testing.T) {
+	env := setupTestEnv(t)
+	routeRegister := routing.NewRouteRegister()
+	env.service.registerAPIEndpoints(routeRegister)
+	server := webtest.NewServer(t, routeRegister)
+
+	frame := data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{time.Date(2023, 9, 12, 10, 0, 0, 0, time.UTC), time.Date(2023, 9, 12, 10, 1, 0, 0, time.UTC)}),
+		data.NewField("Value", nil, []float64{1, 2}),
+	)
+
+	export := func(t *testing.T, params string) *http.Response {
+		t.Helper()
+		env.queryService.On("QueryData", mock.Anything, mock.Anything, false, mock.Anything).
+			Return(&backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}, nil).Once()
+
+		req := server.NewGetRequest("/api/dashboards/uid/abc/panels/1/export?refId=A&timezone=utc" + params)
+		webtest.RequestWithSignedInUser(req, env.user)
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+		return res
+	}
+
+	t.Run("exports the panel as CSV", func(t *testing.T) {
+		res := export(t, "")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/csv; charset=utf-8", res.Header.Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="service-overview-requests.csv"`, res.Header.Get("Content-Disposition"))
+		assert.Empty(t, res.Header.Get(truncatedHeader))
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "Time,Value\n2023-09-12T10:00:00Z,1\n2023-09-12T10:01:00Z,2\n", string(body))
+	})
+
+	t.Run("exports the panel as gzipped JSON Lines with a row limit", func(t *testing.T) {
+		res := export(t, "&format=ndjson&compression=gzip&limit=1")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/gzip", res.Header.Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="service-overview-requests.ndjson.gz"`, res.Header.Get("Content-Disposition"))
+		assert.Equal(t, "true", res.Header.Get(truncatedHeader))
+
+		gz, err := gzip.NewReader(res.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, `{"refId":"A","Time":"2023-09-12T10:00:00Z","Value":1}`+"\n", string(body))
+	})
+
+	t.Run("exports the panel as Parquet", func(t *testing.T) {
+		res := export(t, "&format=parquet")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/vnd.apache.parquet", res.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(body, []byte("PAR1")))
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		req := server.NewGetRequest("/api/dashboards/uid/abc/panels/1/export?format=xlsx")
+		webtest.RequestWithSignedInUser(req, env.user)
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
@@ -0,0 +1,147 @@
+package panelexportimpl
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/panelexport"
+)
+
+// exportTable returns the frames as a single table for the tabular formats: the frame itself when there is one, the
+// frames joined on their time field otherwise, as the "Series joined by time" transformation of the frontend. The
+// fields of the table are named after the display names of the fields of the frames.
+func exportTable(frames data.Frames) (*data.Frame, error) {
+	switch len(frames) {
+	case 0:
+		return data.NewFrame(""), nil
+	case 1:
+		return renameFields(frames[0]), nil
+	}
+
+	timeIndexes := make([]int, len(frames))
+	for i, frame := range frames {
+		timeIndexes[i] = timeFieldIndex(frame)
+		if timeIndexes[i] < 0 {
+			return nil, panelexport.ErrFramesNotJoinable.Errorf("the panel returned %d frames that cannot be joined on time, select the frames of a query with refId", len(frames))
+		}
+	}
+
+	// the rows of the table are the times of all the frames, in ascending order
+	rows := make(map[int64]int)
+	var times []time.Time
+	for i, frame := range frames {
+		field := frame.Fields[timeIndexes[i]]
+		for row := 0; row < field.Len(); row++ {
+			t, ok := timeAt(field, row)
+			if _, exists := rows[t.UnixNano()]; ok && !exists {
+				rows[t.UnixNano()] = -1
+				times = append(times, t)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	for i, t := range times {
+		rows[t.UnixNano()] = i
+	}
+
+	names := newColumnNames()
+	table := data.NewFrame("", data.NewField(names.add(fieldName(frames[0], frames[0].Fields[timeIndexes[0]])), nil, times))
+	for i, frame := range frames {
+		for j, field := range frame.Fields {
+			if j == timeIndexes[i] {
+				continue
+			}
+
+			joined := data.NewFieldFromFieldType(field.Type().NullableType(), len(times))
+			joined.Name = names.add(fieldName(frame, field))
+			joined.Config = field.Config
+			timeField := frame.Fields[timeIndexes[i]]
+			for row := 0; row < field.Len(); row++ {
+				t, ok := timeAt(timeField, row)
+				if !ok {
+					continue
+				}
+				if v, ok := field.ConcreteAt(row); ok {
+					joined.SetConcrete(rows[t.UnixNano()], v)
+				}
+			}
+			table.Fields = append(table.Fields, joined)
+		}
+	}
+	return table, nil
+}
+
+// renameFields returns a copy of the frame with its fields named after their display names.
+func renameFields(frame *data.Frame) *data.Frame {
+	names := newColumnNames()
+	renamed := data.NewFrame(frame.Name)
+	renamed.RefID = frame.RefID
+	renamed.Meta = frame.Meta
+	for _, field := range frame.Fields {
+		// the copy shares the values of the field
+		f := *field
+		f.Name = names.add(fieldName(frame, field))
+		f.Labels = nil
+		renamed.Fields = append(renamed.Fields, &f)
+	}
+	return renamed
+}
+
+// fieldName returns the display name of the field: the name set by the data source, or the name of the field followed
+// by its labels, e.g. Value {instance=localhost:9090, job=prometheus}.
+func fieldName(frame *data.Frame, field *data.Field) string {
+	if field.Config != nil && field.Config.DisplayNameFromDS != "" {
+		return field.Config.DisplayNameFromDS
+	}
+
+	name := field.Name
+	if name == "" {
+		name = frame.Name
+	}
+	if len(field.Labels) == 0 {
+		return name
+	}
+	if name == "" {
+		return "{" + field.Labels.String() + "}"
+	}
+	return name + " {" + field.Labels.String() + "}"
+}
+
+func timeAt(field *data.Field, row int) (time.Time, bool) {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return time.Time{}, false
+	}
+	return v.(time.Time), true
+}
+
+func timeFieldIndex(frame *data.Frame) int {
+	for i, field := range frame.Fields {
+		if field.Type().Time() {
+			return i
+		}
+	}
+	return -1
+}
+
+// columnNames makes the names of the columns of a table unique, by appending a number to the names already used.
+type columnNames map[string]bool
+
+func newColumnNames() columnNames {
+	return make(columnNames)
+}
+
+func (c columnNames) add(name string) string {
+	if name == "" {
+		name = "Value"
+	}
+	unique := name
+	for i := 2; c[unique]; i++ {
+		unique = fmt.Sprintf("%s %d", name, i)
+	}
+	c[unique] = true
+	return unique
+}
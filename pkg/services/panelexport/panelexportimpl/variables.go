@@ -0,0 +1,77 @@
+package panelexportimpl
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/panelexport"
+)
+
+// templateVariables returns the values of the template variables of the dashboard, selected in the request or saved
+// as the current values of the dashboard. The All value is replaced by the custom all value of the variable, or by all
+// the options of the variable.
+func templateVariables(dash *simplejson.Json, selected map[string][]string) (map[string][]string, error) {
+	values := make(map[string][]string)
+	for _, obj := range dash.Get("templating").Get("list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		name := v.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		current, ok := selected[name]
+		if !ok {
+			current = currentValues(v)
+		}
+		if !containsString(current, allValue) {
+			values[name] = current
+			continue
+		}
+
+		if custom := v.Get("allValue").MustString(); custom != "" {
+			values[name] = []string{custom}
+			continue
+		}
+		var options []string
+		for _, option := range v.Get("options").MustArray() {
+			if value, ok := simplejson.NewFromAny(option).Get("value").Interface().(string); ok && value != allValue {
+				options = append(options, value)
+			}
+		}
+		if len(options) == 0 {
+			return nil, panelexport.ErrInvalidRequest.Errorf("the options of variable %s are not saved in the dashboard, select its values with var-%s", name, name)
+		}
+		values[name] = options
+	}
+
+	for name := range selected {
+		if _, ok := values[name]; !ok {
+			return nil, panelexport.ErrInvalidRequest.Errorf("variable %s not found in dashboard", name)
+		}
+	}
+	return values, nil
+}
+
+// currentValues returns the current values saved in the dashboard, a single value or a list for multi-value variables.
+func currentValues(v *simplejson.Json) []string {
+	switch current := v.GetPath("current", "value").Interface().(type) {
+	case string:
+		return []string{current}
+	case []interface{}:
+		values := make([]string, 0, len(current))
+		for _, value := range current {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
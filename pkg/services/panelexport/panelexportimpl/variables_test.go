@@ -0,0 +1,57 @@
+package panelexportimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/panelexport"
+)
+
+func TestTemplateVariables(t *testing.T) {
+	dash, err := simplejson.NewJson([]byte(`{
+	  "templating": {
+	    "list": [
+	      {"name": "env", "type": "custom", "current": {"value": "prod"}},
+	      {"name": "hosts", "type": "query", "multi": true, "current": {"value": ["a", "b"]}},
+	      {"name": "job", "type": "query", "current": {"value": ["$__all"]}, "allValue": ".*"},
+	      {"name": "region", "type": "custom", "current": {"value": "$__all"},
+	       "options": [{"value": "$__all"}, {"value": "eu"}, {"value": "us"}]},
+	      {"name": "pod", "type": "query", "current": {"value": "$__all"}}
+	    ]
+	  }
+	}`))
+	require.NoError(t, err)
+
+	t.Run("uses the current values of the dashboard and expands All", func(t *testing.T) {
+		values, err := templateVariables(dash, map[string][]string{"pod": {"p1"}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"env":    {"prod"},
+			"hosts":  {"a", "b"},
+			"job":    {".*"},
+			"region": {"eu", "us"},
+			"pod":    {"p1"},
+		}, values)
+	})
+
+	t.Run("uses the values of the request", func(t *testing.T) {
+		values, err := templateVariables(dash, map[string][]string{"env": {"dev"}, "hosts": {"c"}, "pod": {"p1", "p2"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dev"}, values["env"])
+		assert.Equal(t, []string{"c"}, values["hosts"])
+		assert.Equal(t, []string{"p1", "p2"}, values["pod"])
+	})
+
+	t.Run("requires the values of All when the options are not saved", func(t *testing.T) {
+		_, err := templateVariables(dash, nil)
+		require.ErrorIs(t, err, panelexport.ErrInvalidRequest)
+	})
+
+	t.Run("fails on unknown variables", func(t *testing.T) {
+		_, err := templateVariables(dash, map[string][]string{"pod": {"p1"}, "cluster": {"c1"}})
+		require.ErrorIs(t, err, panelexport.ErrInvalidRequest)
+	})
+}
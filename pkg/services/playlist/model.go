@@ -1,9 +1,16 @@
 package playlist
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/kinds/playlist"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 // Typed errors
@@ -11,15 +18,25 @@ var (
 	ErrPlaylistNotFound                = errors.New("Playlist not found")
 	ErrPlaylistFailedGenerateUniqueUid = errors.New("failed to generate unique playlist UID")
 	ErrCommandValidationFailed         = errors.New("command missing required fields")
+	ErrNoActivePlaylist                = errutil.NotFound("playlist.no-active-playlist", errutil.WithPublicMessage("No playlist is active"))
+	ErrInvalidItem                     = errutil.BadRequest("playlist.invalid-item").MustTemplate(
+		"invalid playlist item: {{ .Public.reason }}",
+		errutil.WithPublic("Invalid playlist item: {{ .Public.reason }}"),
+	)
+	ErrInvalidSchedule = errutil.BadRequest("playlist.invalid-schedule").MustTemplate(
+		"invalid playlist schedule: {{ .Public.reason }}",
+		errutil.WithPublic("Invalid playlist schedule: {{ .Public.reason }}"),
+	)
 )
 
 // Playlist model
 type Playlist struct {
-	Id       int64  `json:"id,omitempty" db:"id"`
-	UID      string `json:"uid" xorm:"uid" db:"uid"`
-	Name     string `json:"name" db:"name"`
-	Interval string `json:"interval" db:"interval"`
-	OrgId    int64  `json:"-" db:"org_id"`
+	Id       int64     `json:"id,omitempty" db:"id"`
+	UID      string    `json:"uid" xorm:"uid" db:"uid"`
+	Name     string    `json:"name" db:"name"`
+	Interval string    `json:"interval" db:"interval"`
+	Schedule *Schedule `json:"schedule,omitempty" db:"schedule"`
+	OrgId    int64     `json:"-" db:"org_id"`
 }
 
 type PlaylistDTO = playlist.Spec
@@ -33,6 +50,141 @@ type PlaylistItem struct {
 	Value      string `json:"value" db:"value"`
 	Order      int    `json:"order" db:"order"`
 	Title      string `json:"title" db:"title"`
+	// Interval overrides the interval of the playlist, empty to use it.
+	Interval  string         `json:"interval,omitempty" db:"interval"`
+	TimeRange *ItemTimeRange `json:"timeRange,omitempty" xorm:"time_range" db:"time_range"`
+	Variables ItemVariables  `json:"variables,omitempty" db:"variables"`
+}
+
+// Validate checks the settings the item overrides.
+func (i PlaylistItem) Validate() error {
+	if i.Interval != "" {
+		if d, err := gtime.ParseDuration(i.Interval); err != nil || d <= 0 {
+			return invalidItem("invalid interval %q", i.Interval)
+		}
+	}
+	if i.TimeRange != nil && (i.TimeRange.From == "" || i.TimeRange.To == "") {
+		return invalidItem("the time range requires a start and an end")
+	}
+	for name := range i.Variables {
+		if name == "" {
+			return invalidItem("variables require a name")
+		}
+	}
+	return nil
+}
+
+func invalidItem(format string, args ...interface{}) error {
+	return ErrInvalidItem.Build(errutil.TemplateData{
+		Public: map[string]interface{}{"reason": fmt.Sprintf(format, args...)},
+	})
+}
+
+// ItemTimeRange is the time range the dashboards of a playlist item are shown with.
+type ItemTimeRange playlist.TimeRange
+
+func (tr *ItemTimeRange) FromDB(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, tr)
+}
+
+func (tr *ItemTimeRange) ToDB() ([]byte, error) {
+	if tr == nil {
+		return nil, nil
+	}
+	return json.Marshal(tr)
+}
+
+func (tr *ItemTimeRange) Scan(src interface{}) error {
+	return scanJSON(src, tr)
+}
+
+func (tr *ItemTimeRange) Value() (driver.Value, error) {
+	if tr == nil {
+		return nil, nil
+	}
+	return json.Marshal(tr)
+}
+
+// ItemVariables are the values of the variables the dashboards of a playlist item are shown with, by variable name.
+type ItemVariables map[string][]string
+
+func (vs *ItemVariables) FromDB(data []byte) error {
+	if len(data) == 0 {
+		*vs = nil
+		return nil
+	}
+	return json.Unmarshal(data, vs)
+}
+
+func (vs ItemVariables) ToDB() ([]byte, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(vs)
+}
+
+func (vs *ItemVariables) Scan(src interface{}) error {
+	// sqlx allocates the map before scanning
+	*vs = nil
+	return scanJSON(src, vs)
+}
+
+func (vs ItemVariables) Value() (driver.Value, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(vs)
+}
+
+// Schedule is when a playlist is the active playlist. It is evaluated by ActiveAt.
+type Schedule playlist.Schedule
+
+func (s *Schedule) FromDB(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, s)
+}
+
+func (s *Schedule) ToDB() ([]byte, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *Schedule) Scan(src interface{}) error {
+	return scanJSON(src, s)
+}
+
+func (s *Schedule) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// scanJSON scans a column holding JSON, NULL leaves v unchanged.
+func scanJSON(src interface{}, v interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(data) == 0 {
+			return nil
+		}
+		return json.Unmarshal(data, v)
+	case string:
+		if data == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return fmt.Errorf("cannot scan %T as JSON", src)
+	}
 }
 
 type Playlists []*Playlist
@@ -47,12 +199,14 @@ type UpdatePlaylistCommand struct {
 	Name     string         `json:"name" binding:"Required"`
 	Interval string         `json:"interval"`
 	Items    []PlaylistItem `json:"items"`
+	Schedule *Schedule      `json:"schedule,omitempty"`
 }
 
 type CreatePlaylistCommand struct {
 	Name     string         `json:"name" binding:"Required"`
 	Interval string         `json:"interval"`
 	Items    []PlaylistItem `json:"items"`
+	Schedule *Schedule      `json:"schedule,omitempty"`
 	OrgId    int64          `json:"-"`
 }
 
@@ -82,6 +236,12 @@ type GetPlaylistItemsByUidQuery struct {
 	OrgId       int64
 }
 
+type GetActivePlaylistQuery struct {
+	OrgId int64
+	// Time the playlist is active at.
+	Time time.Time
+}
+
 func PlaylistToResource(p PlaylistDTO) playlist.K8sResource {
 	copy := p
 	r := playlist.NewK8sResource(p.Uid, &copy)
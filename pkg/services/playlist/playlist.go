@@ -11,4 +11,6 @@ type Service interface {
 	Get(context.Context, *GetPlaylistByUidQuery) (*PlaylistDTO, error)
 	Search(context.Context, *GetPlaylistsQuery) (Playlists, error)
 	Delete(ctx context.Context, cmd *DeletePlaylistCommand) error
+	// GetActive returns the first playlist by name whose schedule is active at the time of the query.
+	GetActive(context.Context, *GetActivePlaylistQuery) (*PlaylistDTO, error)
 }
@@ -66,6 +66,9 @@ func (s *entityStoreImpl) sync() {
 }
 
 func (s *entityStoreImpl) Create(ctx context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
+	if err := validate(cmd.Items, cmd.Schedule); err != nil {
+		return nil, err
+	}
 	rsp, err := s.sqlimpl.store.Insert(ctx, cmd)
 	if err == nil && rsp != nil {
 		body, err := json.Marshal(cmd)
@@ -87,6 +90,9 @@ func (s *entityStoreImpl) Create(ctx context.Context, cmd *playlist.CreatePlayli
 }
 
 func (s *entityStoreImpl) Update(ctx context.Context, cmd *playlist.UpdatePlaylistCommand) (*playlist.PlaylistDTO, error) {
+	if err := validate(cmd.Items, cmd.Schedule); err != nil {
+		return nil, err
+	}
 	rsp, err := s.sqlimpl.store.Update(ctx, cmd)
 	if err == nil {
 		body, err := json.Marshal(cmd)
@@ -127,6 +133,11 @@ func (s *entityStoreImpl) Delete(ctx context.Context, cmd *playlist.DeletePlayli
 // Read access is managed entirely by the object store
 //------------------------------------------------------
 
+// GetActive uses the SQL store, the object store cannot search playlists by schedule
+func (s *entityStoreImpl) GetActive(ctx context.Context, q *playlist.GetActivePlaylistQuery) (*playlist.PlaylistDTO, error) {
+	return s.sqlimpl.GetActive(ctx, q)
+}
+
 func (s *entityStoreImpl) GetWithoutItems(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*playlist.Playlist, error) {
 	p, err := s.Get(ctx, q) // OrgID is actually picked from the user!
 	if err != nil {
@@ -2,8 +2,10 @@ package playlistimpl
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	kinds "github.com/grafana/grafana/pkg/kinds/playlist"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/store/entity"
@@ -45,10 +47,16 @@ func ProvideService(db db.DB, toggles featuremgmt.FeatureToggles, objserver enti
 }
 
 func (s *Service) Create(ctx context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
+	if err := validate(cmd.Items, cmd.Schedule); err != nil {
+		return nil, err
+	}
 	return s.store.Insert(ctx, cmd)
 }
 
 func (s *Service) Update(ctx context.Context, cmd *playlist.UpdatePlaylistCommand) (*playlist.PlaylistDTO, error) {
+	if err := validate(cmd.Items, cmd.Schedule); err != nil {
+		return nil, err
+	}
 	return s.store.Update(ctx, cmd)
 }
 
@@ -78,15 +86,40 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 		if title != "" {
 			items[i].Title = &title
 		}
+
+		interval := rawItems[i].Interval
+		if interval != "" {
+			items[i].Interval = &interval
+		}
+		items[i].TimeRange = (*kinds.TimeRange)(rawItems[i].TimeRange)
+		items[i].Variables = rawItems[i].Variables
 	}
 	return &playlist.PlaylistDTO{
 		Uid:      v.UID,
 		Name:     v.Name,
 		Interval: v.Interval,
 		Items:    items,
+		Schedule: (*kinds.Schedule)(v.Schedule),
 	}, nil
 }
 
+func (s *Service) GetActive(ctx context.Context, q *playlist.GetActivePlaylistQuery) (*playlist.PlaylistDTO, error) {
+	playlists, err := s.store.ListScheduled(ctx, q.OrgId)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range playlists {
+		active, err := p.Schedule.ActiveAt(q.Time)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate the schedule of playlist %s: %w", p.UID, err)
+		}
+		if active {
+			return s.Get(ctx, &playlist.GetPlaylistByUidQuery{UID: p.UID, OrgId: q.OrgId})
+		}
+	}
+	return nil, playlist.ErrNoActivePlaylist
+}
+
 func (s *Service) Search(ctx context.Context, q *playlist.GetPlaylistsQuery) (playlist.Playlists, error) {
 	return s.store.List(ctx, q)
 }
@@ -94,3 +127,13 @@ func (s *Service) Search(ctx context.Context, q *playlist.GetPlaylistsQuery) (pl
 func (s *Service) Delete(ctx context.Context, cmd *playlist.DeletePlaylistCommand) error {
 	return s.store.Delete(ctx, cmd)
 }
+
+// validate checks the settings of the items and the schedule of a playlist before it is saved.
+func validate(items []playlist.PlaylistItem, schedule *playlist.Schedule) error {
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+	return schedule.Validate()
+}
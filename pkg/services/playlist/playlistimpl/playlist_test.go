@@ -0,0 +1,93 @@
+package playlistimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	kinds "github.com/grafana/grafana/pkg/kinds/playlist"
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestIntegrationPlaylistService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := &Service{store: &sqlStore{db: db.InitTestDB(t)}}
+	items := []playlist.PlaylistItem{{Type: "dashboard_by_uid", Value: "abc"}}
+
+	create := func(t *testing.T, name string, schedule *playlist.Schedule) *playlist.Playlist {
+		t.Helper()
+		p, err := s.Create(ctx, &playlist.CreatePlaylistCommand{Name: name, Interval: "5m", OrgId: 1, Items: items, Schedule: schedule})
+		require.NoError(t, err)
+		return p
+	}
+
+	t.Run("settings are validated", func(t *testing.T) {
+		_, err := s.Create(ctx, &playlist.CreatePlaylistCommand{
+			Name:  "Invalid item",
+			OrgId: 1,
+			Items: []playlist.PlaylistItem{{Type: "dashboard_by_uid", Value: "abc", Interval: "soon"}},
+		})
+		require.ErrorIs(t, err, playlist.ErrInvalidItem)
+
+		_, err = s.Create(ctx, &playlist.CreatePlaylistCommand{Name: "Invalid schedule", OrgId: 1, Items: items, Schedule: &playlist.Schedule{}})
+		require.ErrorIs(t, err, playlist.ErrInvalidSchedule)
+	})
+
+	t.Run("get returns the settings", func(t *testing.T) {
+		p, err := s.Create(ctx, &playlist.CreatePlaylistCommand{
+			Name:  "Settings",
+			OrgId: 2,
+			Items: []playlist.PlaylistItem{{
+				Type:      "dashboard_by_uid",
+				Value:     "abc",
+				Interval:  "1m",
+				TimeRange: &playlist.ItemTimeRange{From: "now-1h", To: "now"},
+				Variables: playlist.ItemVariables{"env": {"prod"}},
+			}},
+		})
+		require.NoError(t, err)
+
+		dto, err := s.Get(ctx, &playlist.GetPlaylistByUidQuery{UID: p.UID, OrgId: 2})
+		require.NoError(t, err)
+		require.Nil(t, dto.Schedule)
+		require.Equal(t, []playlist.PlaylistItemDTO{{
+			Type:      "dashboard_by_uid",
+			Value:     "abc",
+			Interval:  util.Pointer("1m"),
+			TimeRange: &kinds.TimeRange{From: "now-1h", To: "now"},
+			Variables: map[string][]string{"env": {"prod"}},
+		}}, dto.Items)
+	})
+
+	t.Run("get the active playlist", func(t *testing.T) {
+		create(t, "Unscheduled", nil)
+		create(t, "Night", &playlist.Schedule{TimeWindows: []kinds.TimeWindow{{Start: "20:00", End: "08:00"}}})
+		day := create(t, "Day", &playlist.Schedule{TimeWindows: []kinds.TimeWindow{{Start: "08:00", End: "20:00"}}})
+		create(t, "Morning", &playlist.Schedule{Cron: util.Pointer("0 8 * * *"), Duration: util.Pointer("2h")})
+
+		active, err := s.GetActive(ctx, &playlist.GetActivePlaylistQuery{OrgId: 1, Time: time.Date(2023, 9, 4, 12, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+		require.Equal(t, day.UID, active.Uid)
+		require.Len(t, active.Items, 1)
+
+		// the first playlist by name when several are active
+		active, err = s.GetActive(ctx, &playlist.GetActivePlaylistQuery{OrgId: 1, Time: time.Date(2023, 9, 4, 9, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+		require.Equal(t, "Day", active.Name)
+
+		active, err = s.GetActive(ctx, &playlist.GetActivePlaylistQuery{OrgId: 1, Time: time.Date(2023, 9, 4, 23, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+		require.Equal(t, "Night", active.Name)
+
+		_, err = s.GetActive(ctx, &playlist.GetActivePlaylistQuery{OrgId: 3, Time: time.Now()})
+		require.ErrorIs(t, err, playlist.ErrNoActivePlaylist)
+	})
+}
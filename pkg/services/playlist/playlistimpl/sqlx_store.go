@@ -25,14 +25,15 @@ func (s *sqlxStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComm
 	p = playlist.Playlist{
 		Name:     cmd.Name,
 		Interval: cmd.Interval,
+		Schedule: cmd.Schedule,
 		OrgId:    cmd.OrgId,
 		UID:      uid,
 	}
 
 	err = s.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
-		query := `INSERT INTO playlist (name, "interval", org_id, uid) VALUES (?, ?, ?, ?)`
+		query := `INSERT INTO playlist (name, "interval", schedule, org_id, uid) VALUES (?, ?, ?, ?, ?)`
 		var err error
-		p.Id, err = tx.ExecWithReturningId(ctx, query, p.Name, p.Interval, p.OrgId, p.UID)
+		p.Id, err = tx.ExecWithReturningId(ctx, query, p.Name, p.Interval, p.Schedule, p.OrgId, p.UID)
 		if err != nil {
 			return err
 		}
@@ -46,9 +47,12 @@ func (s *sqlxStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComm
 					Value:      item.Value,
 					Order:      order + 1,
 					Title:      item.Title,
+					Interval:   item.Interval,
+					TimeRange:  item.TimeRange,
+					Variables:  item.Variables,
 				})
 			}
-			query := `INSERT INTO playlist_item (playlist_id, type, value, title, "order") VALUES (:playlist_id, :type, :value, :title, :order)`
+			query := `INSERT INTO playlist_item (playlist_id, type, value, title, "order", "interval", time_range, variables) VALUES (:playlist_id, :type, :value, :title, :order, :interval, :time_range, :variables)`
 			_, err = tx.NamedExec(ctx, query, playlistItems)
 			if err != nil {
 				return err
@@ -76,10 +80,11 @@ func (s *sqlxStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComm
 		OrgId:    cmd.OrgId,
 		Name:     cmd.Name,
 		Interval: cmd.Interval,
+		Schedule: cmd.Schedule,
 	}
 
 	err = s.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
-		query := `UPDATE playlist SET uid=:uid, org_id=:org_id, name=:name, "interval"=:interval WHERE id=:id`
+		query := `UPDATE playlist SET uid=:uid, org_id=:org_id, name=:name, "interval"=:interval, schedule=:schedule WHERE id=:id`
 		_, err = tx.NamedExec(ctx, query, p)
 		if err != nil {
 			return err
@@ -98,9 +103,12 @@ func (s *sqlxStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComm
 				Value:      item.Value,
 				Order:      index + 1,
 				Title:      item.Title,
+				Interval:   item.Interval,
+				TimeRange:  item.TimeRange,
+				Variables:  item.Variables,
 			})
 		}
-		query = `INSERT INTO playlist_item (playlist_id, type, value, title, "order") VALUES (:playlist_id, :type, :value, :title, :order)`
+		query = `INSERT INTO playlist_item (playlist_id, type, value, title, "order", "interval", time_range, variables) VALUES (:playlist_id, :type, :value, :title, :order, :interval, :time_range, :variables)`
 		_, err = tx.NamedExec(ctx, query, playlistItems)
 		return err
 	})
@@ -168,6 +176,12 @@ func (s *sqlxStore) List(ctx context.Context, query *playlist.GetPlaylistsQuery)
 	return playlists, err
 }
 
+func (s *sqlxStore) ListScheduled(ctx context.Context, orgID int64) (playlist.Playlists, error) {
+	playlists := make(playlist.Playlists, 0)
+	err := s.sess.Select(ctx, &playlists, "SELECT * FROM playlist WHERE org_id = ? AND schedule IS NOT NULL ORDER BY name", orgID)
+	return playlists, err
+}
+
 func (s *sqlxStore) GetItems(ctx context.Context, query *playlist.GetPlaylistItemsByUidQuery) ([]playlist.PlaylistItem, error) {
 	var playlistItems = make([]playlist.PlaylistItem, 0)
 	if query.PlaylistUID == "" || query.OrgId == 0 {
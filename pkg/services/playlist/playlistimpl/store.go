@@ -12,5 +12,7 @@ type store interface {
 	Get(context.Context, *playlist.GetPlaylistByUidQuery) (*playlist.Playlist, error)
 	GetItems(context.Context, *playlist.GetPlaylistItemsByUidQuery) ([]playlist.PlaylistItem, error)
 	List(context.Context, *playlist.GetPlaylistsQuery) (playlist.Playlists, error)
+	// ListScheduled returns the playlists of an organization that have a schedule, ordered by name.
+	ListScheduled(ctx context.Context, orgID int64) (playlist.Playlists, error)
 	Update(context.Context, *playlist.UpdatePlaylistCommand) (*playlist.PlaylistDTO, error)
 }
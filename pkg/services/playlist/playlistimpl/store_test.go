@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	kinds "github.com/grafana/grafana/pkg/kinds/playlist"
 	"github.com/grafana/grafana/pkg/services/playlist"
 )
 
@@ -100,6 +101,53 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 		})
 	})
 
+	t.Run("Can save item settings and schedule", func(t *testing.T) {
+		items := []playlist.PlaylistItem{
+			{
+				Value:     "abc",
+				Type:      "dashboard_by_uid",
+				Interval:  "2m",
+				TimeRange: &playlist.ItemTimeRange{From: "now-24h", To: "now"},
+				Variables: playlist.ItemVariables{"host": {"a", "b"}},
+			},
+			{Value: "def", Type: "dashboard_by_uid"},
+		}
+		schedule := &playlist.Schedule{TimeWindows: []kinds.TimeWindow{{Start: "08:00", End: "18:00"}}}
+		cmd := playlist.CreatePlaylistCommand{Name: "Night shift", Interval: "5m", OrgId: 3, Items: items, Schedule: schedule}
+		p, err := playlistStore.Insert(context.Background(), &cmd)
+		require.NoError(t, err)
+		unscheduled, err := playlistStore.Insert(context.Background(), &playlist.CreatePlaylistCommand{Name: "Unscheduled", Interval: "5m", OrgId: 3, Items: items})
+		require.NoError(t, err)
+		saved, err := playlistStore.Get(context.Background(), &playlist.GetPlaylistByUidQuery{UID: unscheduled.UID, OrgId: 3})
+		require.NoError(t, err)
+		require.Nil(t, saved.Schedule)
+
+		saved, err = playlistStore.Get(context.Background(), &playlist.GetPlaylistByUidQuery{UID: p.UID, OrgId: 3})
+		require.NoError(t, err)
+		require.Equal(t, schedule, saved.Schedule)
+
+		storedItems, err := playlistStore.GetItems(context.Background(), &playlist.GetPlaylistItemsByUidQuery{PlaylistUID: p.UID, OrgId: 3})
+		require.NoError(t, err)
+		require.Len(t, storedItems, 2)
+		require.Equal(t, "2m", storedItems[0].Interval)
+		require.Equal(t, items[0].TimeRange, storedItems[0].TimeRange)
+		require.Equal(t, items[0].Variables, storedItems[0].Variables)
+		require.Empty(t, storedItems[1].Interval)
+		require.Nil(t, storedItems[1].TimeRange)
+		require.Nil(t, storedItems[1].Variables)
+
+		scheduled, err := playlistStore.ListScheduled(context.Background(), 3)
+		require.NoError(t, err)
+		require.Len(t, scheduled, 1)
+		require.Equal(t, p.UID, scheduled[0].UID)
+
+		_, err = playlistStore.Update(context.Background(), &playlist.UpdatePlaylistCommand{UID: p.UID, Name: "Night shift", Interval: "5m", OrgId: 3, Items: items})
+		require.NoError(t, err)
+		scheduled, err = playlistStore.ListScheduled(context.Background(), 3)
+		require.NoError(t, err)
+		require.Empty(t, scheduled)
+	})
+
 	t.Run("Delete playlist that doesn't exist, should not return error", func(t *testing.T) {
 		deleteQuery := playlist.DeletePlaylistCommand{UID: "654312", OrgId: 1}
 		err := playlistStore.Delete(context.Background(), &deleteQuery)
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	kinds "github.com/grafana/grafana/pkg/kinds/playlist"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/star"
 	"github.com/grafana/grafana/pkg/util"
@@ -24,6 +25,7 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 		p = playlist.Playlist{
 			Name:     cmd.Name,
 			Interval: cmd.Interval,
+			Schedule: cmd.Schedule,
 			OrgId:    cmd.OrgId,
 			UID:      uid,
 		}
@@ -41,6 +43,9 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 				Value:      item.Value,
 				Order:      order + 1,
 				Title:      item.Title,
+				Interval:   item.Interval,
+				TimeRange:  item.TimeRange,
+				Variables:  item.Variables,
 			})
 		}
 
@@ -59,6 +64,7 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 			OrgId:    cmd.OrgId,
 			Name:     cmd.Name,
 			Interval: cmd.Interval,
+			Schedule: cmd.Schedule,
 		}
 
 		existingPlaylist := playlist.Playlist{UID: cmd.UID, OrgId: cmd.OrgId}
@@ -72,9 +78,10 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 			Uid:      p.UID,
 			Name:     p.Name,
 			Interval: p.Interval,
+			Schedule: (*kinds.Schedule)(p.Schedule),
 		}
 
-		_, err = sess.Where("id=?", p.Id).Cols("name", "interval").Update(&p)
+		_, err = sess.Where("id=?", p.Id).Cols("name", "interval", "schedule").Update(&p)
 		if err != nil {
 			return err
 		}
@@ -95,6 +102,9 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 				Value:      item.Value,
 				Order:      index + 1,
 				Title:      item.Title,
+				Interval:   item.Interval,
+				TimeRange:  item.TimeRange,
+				Variables:  item.Variables,
 			})
 		}
 
@@ -119,6 +129,7 @@ func (s *sqlStore) Get(ctx context.Context, query *playlist.GetPlaylistByUidQuer
 
 		return err
 	})
+	resetEmptySchedules(&p)
 	return &p, err
 }
 
@@ -165,6 +176,15 @@ func (s *sqlStore) List(ctx context.Context, query *playlist.GetPlaylistsQuery)
 
 		return err
 	})
+	resetEmptySchedules(playlists...)
+	return playlists, err
+}
+
+func (s *sqlStore) ListScheduled(ctx context.Context, orgID int64) (playlist.Playlists, error) {
+	playlists := make(playlist.Playlists, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND schedule IS NOT NULL AND schedule <> ''", orgID).Asc("name").Find(&playlists)
+	})
 	return playlists, err
 }
 
@@ -185,9 +205,25 @@ func (s *sqlStore) GetItems(ctx context.Context, query *playlist.GetPlaylistItem
 
 		return err
 	})
+	for i := range playlistItems {
+		// xorm saves the items without time range as an empty string, which it reads as an empty time range
+		if tr := playlistItems[i].TimeRange; tr != nil && *tr == (playlist.ItemTimeRange{}) {
+			playlistItems[i].TimeRange = nil
+		}
+	}
 	return playlistItems, err
 }
 
+// resetEmptySchedules removes the schedules of the playlists without schedule, which xorm saves as an empty string
+// and reads as an empty schedule.
+func resetEmptySchedules(playlists ...*playlist.Playlist) {
+	for _, p := range playlists {
+		if s := p.Schedule; s != nil && s.Cron == nil && s.Duration == nil && s.Timezone == nil && len(s.TimeWindows) == 0 {
+			p.Schedule = nil
+		}
+	}
+}
+
 // generateAndValidateNewPlaylistUid generates a playlistUID and verifies that
 // the uid isn't already in use. This is deliberately overly cautious, since users
 // can also specify playlist uids during provisioning.
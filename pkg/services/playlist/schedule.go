@@ -0,0 +1,173 @@
+package playlist
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/kinds/playlist"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const timeOfDayFormat = "15:04"
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+func invalidSchedule(format string, args ...interface{}) error {
+	return ErrInvalidSchedule.Build(errutil.TemplateData{
+		Public: map[string]interface{}{"reason": fmt.Sprintf(format, args...)},
+	})
+}
+
+// Validate checks that the schedule can be evaluated.
+func (s *Schedule) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if value(s.Cron) == "" && len(s.TimeWindows) == 0 {
+		return invalidSchedule("a cron expression or time windows are required")
+	}
+	if _, err := s.location(); err != nil {
+		return err
+	}
+	if value(s.Cron) != "" {
+		if _, _, err := s.cron(); err != nil {
+			return err
+		}
+	} else if value(s.Duration) != "" {
+		return invalidSchedule("a duration requires a cron expression")
+	}
+	for _, w := range s.TimeWindows {
+		if _, _, err := parseTimeWindow(w); err != nil {
+			return err
+		}
+		for _, day := range w.Weekdays {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				return invalidSchedule("invalid weekday %q", day)
+			}
+		}
+	}
+	return nil
+}
+
+// ActiveAt returns whether the playlist is active at t, that is during the duration following a time of the cron
+// expression or during one of the time windows.
+func (s *Schedule) ActiveAt(t time.Time) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+	loc, err := s.location()
+	if err != nil {
+		return false, err
+	}
+	t = t.In(loc)
+
+	if value(s.Cron) != "" {
+		schedule, duration, err := s.cron()
+		if err != nil {
+			return false, err
+		}
+		// the first time after the start of the duration is the last time before t when the playlist is active
+		if next := schedule.Next(t.Add(-duration)); !next.IsZero() && !next.After(t) {
+			return true, nil
+		}
+	}
+
+	for _, w := range s.TimeWindows {
+		active, err := timeWindowActiveAt(w, t)
+		if err != nil || active {
+			return active, err
+		}
+	}
+	return false, nil
+}
+
+func (s *Schedule) location() (*time.Location, error) {
+	timezone := value(s.Timezone)
+	if timezone == "" || timezone == "browser" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, invalidSchedule("invalid timezone %q", timezone)
+	}
+	return loc, nil
+}
+
+func (s *Schedule) cron() (cron.Schedule, time.Duration, error) {
+	schedule, err := cron.ParseStandard(*s.Cron)
+	if err != nil {
+		return nil, 0, invalidSchedule("invalid cron expression %q: %s", *s.Cron, err)
+	}
+	if value(s.Duration) == "" {
+		return nil, 0, invalidSchedule("a cron expression requires a duration")
+	}
+	duration, err := gtime.ParseDuration(*s.Duration)
+	if err != nil || duration <= 0 {
+		return nil, 0, invalidSchedule("invalid duration %q", *s.Duration)
+	}
+	return schedule, duration, nil
+}
+
+// parseTimeWindow returns the start and the end of a time window in minutes since midnight.
+func parseTimeWindow(w playlist.TimeWindow) (int, int, error) {
+	start, err := time.Parse(timeOfDayFormat, w.Start)
+	if err != nil {
+		return 0, 0, invalidSchedule("invalid start time %q", w.Start)
+	}
+	end, err := time.Parse(timeOfDayFormat, w.End)
+	if err != nil {
+		return 0, 0, invalidSchedule("invalid end time %q", w.End)
+	}
+	if start.Equal(end) {
+		return 0, 0, invalidSchedule("the time window %s-%s is empty", w.Start, w.End)
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), nil
+}
+
+func timeWindowActiveAt(w playlist.TimeWindow, t time.Time) (bool, error) {
+	start, end, err := parseTimeWindow(w)
+	if err != nil {
+		return false, err
+	}
+	minutes := t.Hour()*60 + t.Minute()
+
+	if start < end {
+		return onWeekday(w, t.Weekday()) && minutes >= start && minutes < end, nil
+	}
+	// the time window ends on the day after it starts
+	if minutes >= start {
+		return onWeekday(w, t.Weekday()), nil
+	}
+	return minutes < end && onWeekday(w, (t.Weekday()+6)%7), nil
+}
+
+func onWeekday(w playlist.TimeWindow, day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.Weekdays {
+		if wd, ok := weekdays[strings.ToLower(d)]; ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,152 @@
+package playlist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/kinds/playlist"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestScheduleActiveAt(t *testing.T) {
+	// a monday
+	at := func(clock string) time.Time {
+		t.Helper()
+		v, err := time.Parse(time.RFC3339, "2023-09-04T"+clock+":00Z")
+		require.NoError(t, err)
+		return v
+	}
+
+	testCases := []struct {
+		desc     string
+		schedule *Schedule
+		time     time.Time
+		active   bool
+	}{
+		{
+			desc:     "no schedule",
+			schedule: nil,
+			time:     at("10:00"),
+		},
+		{
+			desc:     "during the duration after the cron time",
+			schedule: &Schedule{Cron: util.Pointer("0 8 * * 1-5"), Duration: util.Pointer("4h")},
+			time:     at("11:59"),
+			active:   true,
+		},
+		{
+			desc:     "at the cron time",
+			schedule: &Schedule{Cron: util.Pointer("0 8 * * 1-5"), Duration: util.Pointer("4h")},
+			time:     at("08:00"),
+			active:   true,
+		},
+		{
+			desc:     "after the duration",
+			schedule: &Schedule{Cron: util.Pointer("0 8 * * 1-5"), Duration: util.Pointer("4h")},
+			time:     at("12:00"),
+		},
+		{
+			desc:     "on a day the cron expression does not match",
+			schedule: &Schedule{Cron: util.Pointer("0 8 * * 6"), Duration: util.Pointer("4h")},
+			time:     at("10:00"),
+		},
+		{
+			desc:     "in a time window",
+			schedule: &Schedule{TimeWindows: []playlist.TimeWindow{{Start: "08:00", End: "18:00", Weekdays: []string{"Monday"}}}},
+			time:     at("08:00"),
+			active:   true,
+		},
+		{
+			desc:     "at the end of a time window",
+			schedule: &Schedule{TimeWindows: []playlist.TimeWindow{{Start: "08:00", End: "18:00"}}},
+			time:     at("18:00"),
+		},
+		{
+			desc:     "on another weekday",
+			schedule: &Schedule{TimeWindows: []playlist.TimeWindow{{Start: "08:00", End: "18:00", Weekdays: []string{"tuesday"}}}},
+			time:     at("10:00"),
+		},
+		{
+			desc:     "in a time window ending the next day",
+			schedule: &Schedule{TimeWindows: []playlist.TimeWindow{{Start: "22:00", End: "06:00", Weekdays: []string{"sunday"}}}},
+			time:     at("05:00"),
+			active:   true,
+		},
+		{
+			desc:     "in a time window ending the next day, started on another weekday",
+			schedule: &Schedule{TimeWindows: []playlist.TimeWindow{{Start: "22:00", End: "06:00", Weekdays: []string{"monday"}}}},
+			time:     at("05:00"),
+		},
+		{
+			desc: "in the timezone of the schedule",
+			schedule: &Schedule{
+				TimeWindows: []playlist.TimeWindow{{Start: "08:00", End: "09:00"}},
+				Timezone:    util.Pointer("Europe/Paris"),
+			},
+			time:   at("06:30"),
+			active: true,
+		},
+		{
+			desc: "in one of the time windows",
+			schedule: &Schedule{TimeWindows: []playlist.TimeWindow{
+				{Start: "08:00", End: "09:00"},
+				{Start: "14:00", End: "15:00"},
+			}},
+			time:   at("14:30"),
+			active: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.NoError(t, tc.schedule.Validate())
+			active, err := tc.schedule.ActiveAt(tc.time)
+			require.NoError(t, err)
+			assert.Equal(t, tc.active, active)
+		})
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		schedule *Schedule
+	}{
+		{desc: "empty", schedule: &Schedule{}},
+		{desc: "invalid cron expression", schedule: &Schedule{Cron: util.Pointer("0 8 * *"), Duration: util.Pointer("1h")}},
+		{desc: "cron expression without duration", schedule: &Schedule{Cron: util.Pointer("0 8 * * *")}},
+		{desc: "invalid duration", schedule: &Schedule{Cron: util.Pointer("0 8 * * *"), Duration: util.Pointer("-1h")}},
+		{desc: "duration without cron expression", schedule: &Schedule{
+			Duration:    util.Pointer("1h"),
+			TimeWindows: []playlist.TimeWindow{{Start: "08:00", End: "09:00"}},
+		}},
+		{desc: "invalid timezone", schedule: &Schedule{
+			Timezone:    util.Pointer("Mars/Olympus"),
+			TimeWindows: []playlist.TimeWindow{{Start: "08:00", End: "09:00"}},
+		}},
+		{desc: "invalid time", schedule: &Schedule{TimeWindows: []playlist.TimeWindow{{Start: "8am", End: "09:00"}}}},
+		{desc: "empty time window", schedule: &Schedule{TimeWindows: []playlist.TimeWindow{{Start: "08:00", End: "08:00"}}}},
+		{desc: "invalid weekday", schedule: &Schedule{TimeWindows: []playlist.TimeWindow{{Start: "08:00", End: "09:00", Weekdays: []string{"mon"}}}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.ErrorIs(t, tc.schedule.Validate(), ErrInvalidSchedule)
+		})
+	}
+}
+
+func TestPlaylistItemValidate(t *testing.T) {
+	require.NoError(t, PlaylistItem{
+		Interval:  "2m",
+		TimeRange: &ItemTimeRange{From: "now-6h", To: "now"},
+		Variables: ItemVariables{"host": {"a", "b"}},
+	}.Validate())
+
+	require.ErrorIs(t, PlaylistItem{Interval: "soon"}.Validate(), ErrInvalidItem)
+	require.ErrorIs(t, PlaylistItem{TimeRange: &ItemTimeRange{From: "now-6h"}}.Validate(), ErrInvalidItem)
+	require.ErrorIs(t, PlaylistItem{Variables: ItemVariables{"": {"a"}}}.Validate(), ErrInvalidItem)
+}
@@ -0,0 +1,127 @@
+package accesscontrol
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+type configReader interface {
+	readConfig(path string) ([]*configs, error)
+}
+
+type configReaderImpl struct {
+	log log.Logger
+}
+
+func newConfigReader(logger log.Logger) configReader {
+	return &configReaderImpl{log: logger}
+}
+
+func (cr *configReaderImpl) readConfig(path string) ([]*configs, error) {
+	var result []*configs
+	cr.log.Debug("Looking for access control provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read access control provisioning files from directory", "path", path, "error", err)
+		return result, nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		cr.log.Debug("Parsing access control provisioning file", "path", path, "file.Name", file.Name())
+		cfg, err := cr.parseConfig(filepath.Join(path, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file.Name(), err)
+		}
+		if err := validateConfig(cfg); err != nil {
+			return nil, fmt.Errorf("invalid configuration in %s: %w", file.Name(), err)
+		}
+		result = append(result, cfg)
+	}
+
+	return result, nil
+}
+
+func (cr *configReaderImpl) parseConfig(filename string) (*configs, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *configsV2
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToConfigs(), nil
+}
+
+// validateConfig checks the required fields and sets the default organizations.
+func validateConfig(cfg *configs) error {
+	for i, role := range cfg.Roles {
+		if role.UID == "" && role.Name == "" {
+			return fmt.Errorf("role %d does not have a name or a uid", i+1)
+		}
+		if len(role.From) > 0 {
+			return fmt.Errorf("role %s: copying permissions from other roles is not supported", roleIdentifier(role.UID, role.Name))
+		}
+		orgID, err := resolveOrgID(role.Global, role.OrgID, 1)
+		if err != nil {
+			return fmt.Errorf("role %s %w", roleIdentifier(role.UID, role.Name), err)
+		}
+		role.OrgID = orgID
+	}
+
+	for i, assignment := range cfg.Assignments {
+		if assignment.Assignee == "" {
+			return fmt.Errorf("%s assignment %d does not have a name", assignment.Kind, i+1)
+		}
+		if assignment.Role.UID == "" && assignment.Role.Name == "" {
+			return fmt.Errorf("role assigned to %s %s does not have a name or a uid", assignment.Kind, assignment.Assignee)
+		}
+		if assignment.OrgID < 1 {
+			assignment.OrgID = 1
+		}
+		// Roles default to the organization of the team, user or service account they are assigned to
+		orgID, err := resolveOrgID(assignment.Role.Global, assignment.Role.OrgID, assignment.OrgID)
+		if err != nil {
+			return fmt.Errorf("role %s assigned to %s %s %w", roleIdentifier(assignment.Role.UID, assignment.Role.Name), assignment.Kind, assignment.Assignee, err)
+		}
+		assignment.Role.OrgID = orgID
+	}
+
+	return nil
+}
+
+func resolveOrgID(global bool, orgID, defaultOrgID int64) (int64, error) {
+	if global {
+		if orgID > 0 {
+			return 0, fmt.Errorf("cannot be global and have an organization")
+		}
+		return accesscontrol.GlobalOrgID, nil
+	}
+	if orgID < 1 {
+		return defaultOrgID, nil
+	}
+	return orgID, nil
+}
+
+func roleIdentifier(uid, name string) string {
+	if uid != "" {
+		return uid
+	}
+	return name
+}
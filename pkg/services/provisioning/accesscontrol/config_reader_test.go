@@ -0,0 +1,107 @@
+package accesscontrol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	brokenYaml        = "./testdata/test-configs/broken-yaml"
+	emptyFolder       = "./testdata/test-configs/empty_folder"
+	missingName       = "./testdata/test-configs/missing-name"
+	unsupportedFrom   = "./testdata/test-configs/unsupported-from"
+	correctProperties = "./testdata/test-configs/correct-properties"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Role without name or uid should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(missingName)
+		require.Error(t, err)
+		require.Equal(t, "invalid configuration in roles.yaml: role 1 does not have a name or a uid", err.Error())
+	})
+
+	t.Run("Copying permissions from other roles should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(unsupportedFrom)
+		require.Error(t, err)
+		require.Equal(t, "invalid configuration in roles.yaml: role basic_editor: copying permissions from other roles is not supported", err.Error())
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("GLOBAL_ROLE_NAME", "custom:global:users:reader")
+
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		roles := cfg[0].Roles
+		require.Len(t, roles, 3)
+		require.Equal(t, &roleFromConfig{
+			OrgID:       2,
+			UID:         "silencescreator",
+			Name:        "custom:alert-silences:creator",
+			DisplayName: "Silence creator",
+			Description: "Create alert silences",
+			Version:     1,
+			Permissions: []accesscontrol.Permission{
+				{Action: "alert.silences:create"},
+				{Action: "alert.silences:read"},
+			},
+			From: []*roleRefFromConfig{},
+		}, roles[0])
+		require.Equal(t, "custom:global:users:reader", roles[1].Name)
+		require.True(t, roles[1].Global)
+		require.Equal(t, int64(accesscontrol.GlobalOrgID), roles[1].OrgID)
+		require.Equal(t, []accesscontrol.Permission{{Action: "users:read", Scope: "global.users:*"}}, roles[1].Permissions)
+		require.Equal(t, int64(1), roles[2].OrgID)
+		require.True(t, roles[2].Delete)
+		require.True(t, roles[2].Force)
+
+		assignments := cfg[0].Assignments
+		require.Len(t, assignments, 4)
+
+		testCases := []struct {
+			kind        assigneeKind
+			assignee    string
+			orgID       int64
+			roleUID     string
+			roleName    string
+			roleOrgID   int64
+			deleteState bool
+		}{
+			{kind: assigneeTeam, assignee: "Alerting", orgID: 2, roleUID: "silencescreator", roleOrgID: 2},
+			{kind: assigneeTeam, assignee: "Alerting", orgID: 2, roleName: "custom:global:users:reader", roleOrgID: accesscontrol.GlobalOrgID, deleteState: true},
+			{kind: assigneeUser, assignee: "editor", orgID: 1, roleUID: "silencescreator", roleOrgID: 2},
+			{kind: assigneeServiceAccount, assignee: "ci", orgID: 3, roleName: "custom:global:users:reader", roleOrgID: accesscontrol.GlobalOrgID},
+		}
+		for i, tc := range testCases {
+			a := assignments[i]
+			require.Equal(t, tc.kind, a.Kind)
+			require.Equal(t, tc.assignee, a.Assignee)
+			require.Equal(t, tc.orgID, a.OrgID)
+			require.Equal(t, tc.roleUID, a.Role.UID)
+			require.Equal(t, tc.roleName, a.Role.Name)
+			require.Equal(t, tc.roleOrgID, a.Role.OrgID)
+			require.Equal(t, tc.deleteState, a.Delete)
+		}
+	})
+}
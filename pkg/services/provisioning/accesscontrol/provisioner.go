@@ -0,0 +1,213 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the custom roles and role assignments in those files.
+func Provision(ctx context.Context, configDirectory string, roleService accesscontrol.RoleService, teamService team.Service,
+	userService user.Service, serviceAccountsService serviceaccounts.Service) error {
+	logger := log.New("provisioning.accesscontrol")
+	p := Provisioner{
+		log:                    logger,
+		cfgProvider:            newConfigReader(logger),
+		roleService:            roleService,
+		teamService:            teamService,
+		userService:            userService,
+		serviceAccountsService: serviceAccountsService,
+	}
+	return p.applyChanges(ctx, configDirectory)
+}
+
+// Provisioner is responsible for provisioning custom roles and their
+// assignments based on configuration read by the `configReader`
+type Provisioner struct {
+	log                    log.Logger
+	cfgProvider            configReader
+	roleService            accesscontrol.RoleService
+	teamService            team.Service
+	userService            user.Service
+	serviceAccountsService serviceaccounts.Service
+}
+
+func (p *Provisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := p.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	// Roles are provisioned first for the assignments to reference roles from any file
+	for _, cfg := range configs {
+		for _, role := range cfg.Roles {
+			if err := p.applyRole(ctx, role); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, assignment := range cfg.Assignments {
+			if err := p.applyAssignment(ctx, assignment); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *Provisioner) applyRole(ctx context.Context, role *roleFromConfig) error {
+	existing, err := p.findRole(ctx, role.OrgID, role.UID, role.Name)
+	if err != nil {
+		return err
+	}
+
+	if role.Delete {
+		if existing == nil {
+			return nil
+		}
+		p.log.Info("Deleting role from configuration", "uid", existing.UID, "name", existing.Name, "orgId", role.OrgID)
+		return p.roleService.DeleteCustomRole(ctx, accesscontrol.DeleteCustomRoleCommand{OrgID: role.OrgID, UID: existing.UID, Force: role.Force})
+	}
+
+	if role.Name == "" {
+		return fmt.Errorf("role %s does not have a name", role.UID)
+	}
+
+	cmd := accesscontrol.SaveCustomRoleCommand{
+		OrgID:       role.OrgID,
+		UID:         role.UID,
+		Name:        role.Name,
+		DisplayName: role.DisplayName,
+		Description: role.Description,
+		Group:       role.Group,
+		Hidden:      role.Hidden,
+		Version:     role.Version,
+		Permissions: role.Permissions,
+	}
+
+	if existing == nil {
+		p.log.Info("Creating role from configuration", "uid", role.UID, "name", role.Name, "orgId", role.OrgID)
+		if _, err := p.roleService.CreateCustomRole(ctx, cmd); err != nil {
+			return fmt.Errorf("failed to create role %s: %w", role.Name, err)
+		}
+		return nil
+	}
+
+	// The version of the role has to be incremented for the provisioned role to be updated,
+	// that way restarting Grafana does not overwrite the changes made through the API.
+	if role.Version <= existing.Version {
+		p.log.Debug("Skipping role update, version not incremented", "uid", existing.UID, "version", role.Version, "storedVersion", existing.Version)
+		return nil
+	}
+
+	cmd.UID = existing.UID
+	p.log.Info("Updating role from configuration", "uid", existing.UID, "name", role.Name, "orgId", role.OrgID)
+	if _, err := p.roleService.UpdateCustomRole(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to update role %s: %w", role.Name, err)
+	}
+	return nil
+}
+
+// findRole looks up a custom role by uid, or by name when the uid is not set.
+// It returns nil when the role does not exist.
+func (p *Provisioner) findRole(ctx context.Context, orgID int64, uid, name string) (*accesscontrol.RoleDTO, error) {
+	if uid != "" {
+		role, err := p.roleService.GetCustomRole(ctx, orgID, uid)
+		if errors.Is(err, accesscontrol.ErrRoleNotFound) {
+			return nil, nil
+		}
+		return role, err
+	}
+
+	roles, err := p.roleService.ListCustomRoles(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.OrgID == orgID && role.Name == name {
+			return role, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *Provisioner) applyAssignment(ctx context.Context, assignment *assignmentFromConfig) error {
+	ref := assignment.Role
+	role, err := p.findRole(ctx, ref.OrgID, ref.UID, ref.Name)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		if assignment.Delete {
+			return nil
+		}
+		return fmt.Errorf("role %s assigned to %s %s not found", roleIdentifier(ref.UID, ref.Name), assignment.Kind, assignment.Assignee)
+	}
+
+	var (
+		add    func(ctx context.Context, orgID, id int64, roleUID string) error
+		remove func(ctx context.Context, orgID, id int64, roleUID string) error
+		id     int64
+	)
+	switch assignment.Kind {
+	case assigneeUser:
+		u, err := p.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: assignment.Assignee})
+		if err != nil {
+			return fmt.Errorf("failed to find user %s: %w", assignment.Assignee, err)
+		}
+		id, add, remove = u.ID, p.roleService.AddUserCustomRole, p.roleService.RemoveUserCustomRole
+	case assigneeServiceAccount:
+		saID, err := p.serviceAccountsService.RetrieveServiceAccountIdByName(ctx, assignment.OrgID, assignment.Assignee)
+		if err != nil {
+			return fmt.Errorf("failed to find service account %s: %w", assignment.Assignee, err)
+		}
+		id, add, remove = saID, p.roleService.AddUserCustomRole, p.roleService.RemoveUserCustomRole
+	case assigneeTeam:
+		teamID, err := p.getTeamID(ctx, assignment.OrgID, assignment.Assignee)
+		if err != nil {
+			return err
+		}
+		id, add, remove = teamID, p.roleService.AddTeamCustomRole, p.roleService.RemoveTeamCustomRole
+	}
+
+	if assignment.Delete {
+		p.log.Info("Removing role assignment from configuration", "uid", role.UID, "kind", assignment.Kind, "assignee", assignment.Assignee, "orgId", assignment.OrgID)
+		err = remove(ctx, assignment.OrgID, id, role.UID)
+	} else {
+		p.log.Debug("Adding role assignment from configuration", "uid", role.UID, "kind", assignment.Kind, "assignee", assignment.Assignee, "orgId", assignment.OrgID)
+		err = add(ctx, assignment.OrgID, id, role.UID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update assignment of role %s to %s %s: %w", role.UID, assignment.Kind, assignment.Assignee, err)
+	}
+	return nil
+}
+
+func (p *Provisioner) getTeamID(ctx context.Context, orgID int64, name string) (int64, error) {
+	result, err := p.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID: orgID,
+		Name:  name,
+		Limit: 1,
+		SignedInUser: accesscontrol.BackgroundUser("access_control_provisioning", orgID, org.RoleAdmin, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		}),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find team %s: %w", name, err)
+	}
+	if len(result.Teams) == 0 {
+		return 0, fmt.Errorf("team %s not found in organization %d", name, orgID)
+	}
+	return result.Teams[0].ID, nil
+}
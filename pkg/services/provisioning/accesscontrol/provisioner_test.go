@@ -0,0 +1,202 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestProvisioner(t *testing.T) {
+	t.Run("Should return error when config reader returns error", func(t *testing.T) {
+		expectedErr := errors.New("test")
+		p := Provisioner{log: log.New("test"), cfgProvider: &testConfigReader{err: expectedErr}}
+		err := p.applyChanges(context.Background(), "")
+		require.Equal(t, expectedErr, err)
+	})
+
+	t.Run("Should create missing roles", func(t *testing.T) {
+		roles := newFakeRoleService()
+		p := newTestProvisioner(roles, &configs{Roles: []*roleFromConfig{
+			{OrgID: 1, UID: "a", Name: "custom:a", Version: 1, Permissions: []accesscontrol.Permission{{Action: "users:read"}}},
+		}})
+
+		require.NoError(t, p.applyChanges(context.Background(), ""))
+		require.Len(t, roles.created, 1)
+		require.Len(t, roles.updated, 0)
+		require.Equal(t, "custom:a", roles.roles["a"].Name)
+	})
+
+	t.Run("Should only update roles when the version is incremented", func(t *testing.T) {
+		roles := newFakeRoleService()
+		roles.roles["a"] = &accesscontrol.RoleDTO{OrgID: 1, UID: "a", Name: "custom:a", Version: 2}
+		roles.roles["b"] = &accesscontrol.RoleDTO{OrgID: 1, UID: "b", Name: "custom:b", Version: 2}
+		p := newTestProvisioner(roles, &configs{Roles: []*roleFromConfig{
+			{OrgID: 1, UID: "a", Name: "custom:a", Version: 2},
+			{OrgID: 1, Name: "custom:b", Version: 3},
+		}})
+
+		require.NoError(t, p.applyChanges(context.Background(), ""))
+		require.Len(t, roles.created, 0)
+		require.Len(t, roles.updated, 1)
+		require.Equal(t, "b", roles.updated[0].UID)
+	})
+
+	t.Run("Should delete absent roles", func(t *testing.T) {
+		roles := newFakeRoleService()
+		roles.roles["a"] = &accesscontrol.RoleDTO{OrgID: 1, UID: "a", Name: "custom:a"}
+		p := newTestProvisioner(roles, &configs{Roles: []*roleFromConfig{
+			{OrgID: 1, Name: "custom:a", Delete: true, Force: true},
+			{OrgID: 1, Name: "custom:missing", Delete: true},
+		}})
+
+		require.NoError(t, p.applyChanges(context.Background(), ""))
+		require.Equal(t, []accesscontrol.DeleteCustomRoleCommand{{OrgID: 1, UID: "a", Force: true}}, roles.deleted)
+	})
+
+	t.Run("Should assign roles to users, service accounts and teams", func(t *testing.T) {
+		roles := newFakeRoleService()
+		roles.roles["a"] = &accesscontrol.RoleDTO{OrgID: 1, UID: "a", Name: "custom:a"}
+		p := newTestProvisioner(roles, &configs{Assignments: []*assignmentFromConfig{
+			{OrgID: 1, Kind: assigneeUser, Assignee: "editor", Role: &roleRefFromConfig{OrgID: 1, UID: "a"}},
+			{OrgID: 1, Kind: assigneeServiceAccount, Assignee: "ci", Role: &roleRefFromConfig{OrgID: 1, Name: "custom:a"}},
+			{OrgID: 1, Kind: assigneeTeam, Assignee: "Alerting", Role: &roleRefFromConfig{OrgID: 1, UID: "a"}},
+			{OrgID: 1, Kind: assigneeTeam, Assignee: "Alerting", Role: &roleRefFromConfig{OrgID: 1, UID: "a"}, Delete: true},
+		}})
+
+		require.NoError(t, p.applyChanges(context.Background(), ""))
+		require.Equal(t, []int64{10, 20}, roles.userAssignments["a"])
+		require.Equal(t, []int64{30}, roles.teamAssignments["a"])
+		require.Equal(t, []int64{30}, roles.teamRemovals["a"])
+	})
+
+	t.Run("Should return error when assigned role does not exist", func(t *testing.T) {
+		p := newTestProvisioner(newFakeRoleService(), &configs{Assignments: []*assignmentFromConfig{
+			{OrgID: 1, Kind: assigneeUser, Assignee: "editor", Role: &roleRefFromConfig{OrgID: 1, UID: "missing"}},
+		}})
+
+		err := p.applyChanges(context.Background(), "")
+		require.Error(t, err)
+		require.Equal(t, "role missing assigned to user editor not found", err.Error())
+	})
+}
+
+func newTestProvisioner(roles *fakeRoleService, cfg *configs) *Provisioner {
+	sa := &tests.MockServiceAccountService{}
+	sa.On("RetrieveServiceAccountIdByName", mock.Anything, int64(1), "ci").Return(int64(20), nil)
+	teams := teamtest.NewFakeService()
+	teams.ExpectedSearchTeams = team.SearchTeamQueryResult{Teams: []*team.TeamDTO{{ID: 30, Name: "Alerting"}}}
+	users := usertest.NewUserServiceFake()
+	users.ExpectedUser = &user.User{ID: 10, Login: "editor"}
+
+	return &Provisioner{
+		log:                    log.New("test"),
+		cfgProvider:            &testConfigReader{result: []*configs{cfg}},
+		roleService:            roles,
+		teamService:            teams,
+		userService:            users,
+		serviceAccountsService: sa,
+	}
+}
+
+type testConfigReader struct {
+	result []*configs
+	err    error
+}
+
+func (tcr *testConfigReader) readConfig(_ string) ([]*configs, error) {
+	return tcr.result, tcr.err
+}
+
+var _ accesscontrol.RoleService = new(fakeRoleService)
+
+type fakeRoleService struct {
+	roles           map[string]*accesscontrol.RoleDTO
+	created         []accesscontrol.SaveCustomRoleCommand
+	updated         []accesscontrol.SaveCustomRoleCommand
+	deleted         []accesscontrol.DeleteCustomRoleCommand
+	userAssignments map[string][]int64
+	teamAssignments map[string][]int64
+	teamRemovals    map[string][]int64
+}
+
+func newFakeRoleService() *fakeRoleService {
+	return &fakeRoleService{
+		roles:           map[string]*accesscontrol.RoleDTO{},
+		userAssignments: map[string][]int64{},
+		teamAssignments: map[string][]int64{},
+		teamRemovals:    map[string][]int64{},
+	}
+}
+
+func (f *fakeRoleService) GetCustomRole(_ context.Context, _ int64, uid string) (*accesscontrol.RoleDTO, error) {
+	if role, ok := f.roles[uid]; ok {
+		return role, nil
+	}
+	return nil, accesscontrol.ErrRoleNotFound
+}
+
+func (f *fakeRoleService) ListCustomRoles(_ context.Context, _ int64) ([]*accesscontrol.RoleDTO, error) {
+	result := make([]*accesscontrol.RoleDTO, 0, len(f.roles))
+	for _, role := range f.roles {
+		result = append(result, role)
+	}
+	return result, nil
+}
+
+func (f *fakeRoleService) CreateCustomRole(_ context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error) {
+	f.created = append(f.created, cmd)
+	role := &accesscontrol.RoleDTO{OrgID: cmd.OrgID, UID: cmd.UID, Name: cmd.Name, Version: cmd.Version, Permissions: cmd.Permissions}
+	f.roles[cmd.UID] = role
+	return role, nil
+}
+
+func (f *fakeRoleService) UpdateCustomRole(_ context.Context, cmd accesscontrol.SaveCustomRoleCommand) (*accesscontrol.RoleDTO, error) {
+	f.updated = append(f.updated, cmd)
+	role := &accesscontrol.RoleDTO{OrgID: cmd.OrgID, UID: cmd.UID, Name: cmd.Name, Version: cmd.Version, Permissions: cmd.Permissions}
+	f.roles[cmd.UID] = role
+	return role, nil
+}
+
+func (f *fakeRoleService) DeleteCustomRole(_ context.Context, cmd accesscontrol.DeleteCustomRoleCommand) error {
+	f.deleted = append(f.deleted, cmd)
+	delete(f.roles, cmd.UID)
+	return nil
+}
+
+func (f *fakeRoleService) GetUserCustomRoles(_ context.Context, _, _ int64) ([]*accesscontrol.RoleDTO, error) {
+	return nil, nil
+}
+
+func (f *fakeRoleService) AddUserCustomRole(_ context.Context, _, userID int64, roleUID string) error {
+	f.userAssignments[roleUID] = append(f.userAssignments[roleUID], userID)
+	return nil
+}
+
+func (f *fakeRoleService) RemoveUserCustomRole(_ context.Context, _, _ int64, _ string) error {
+	return nil
+}
+
+func (f *fakeRoleService) GetTeamCustomRoles(_ context.Context, _, _ int64) ([]*accesscontrol.RoleDTO, error) {
+	return nil, nil
+}
+
+func (f *fakeRoleService) AddTeamCustomRole(_ context.Context, _, teamID int64, roleUID string) error {
+	f.teamAssignments[roleUID] = append(f.teamAssignments[roleUID], teamID)
+	return nil
+}
+
+func (f *fakeRoleService) RemoveTeamCustomRole(_ context.Context, _, teamID int64, roleUID string) error {
+	f.teamRemovals[roleUID] = append(f.teamRemovals[roleUID], teamID)
+	return nil
+}
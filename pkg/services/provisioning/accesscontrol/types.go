@@ -0,0 +1,190 @@
+package accesscontrol
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+const stateAbsent = "absent"
+
+// configs is a normalized data object for access control config data. Any config version should be mappable
+// to this type.
+type configs struct {
+	Roles       []*roleFromConfig
+	Assignments []*assignmentFromConfig
+}
+
+type roleFromConfig struct {
+	OrgID       int64
+	Global      bool
+	UID         string
+	Name        string
+	DisplayName string
+	Description string
+	Group       string
+	Hidden      bool
+	Version     int64
+	Permissions []accesscontrol.Permission
+	// From is not supported by custom roles, it is only kept to reject configurations relying on it
+	From []*roleRefFromConfig
+	// Delete is set when the state of the role is absent
+	Delete bool
+	// Force revokes all the assignments of the role when it is deleted
+	Force bool
+}
+
+type roleRefFromConfig struct {
+	OrgID  int64
+	Global bool
+	UID    string
+	Name   string
+}
+
+type assigneeKind string
+
+const (
+	assigneeUser           assigneeKind = "user"
+	assigneeServiceAccount assigneeKind = "service account"
+	assigneeTeam           assigneeKind = "team"
+)
+
+type assignmentFromConfig struct {
+	// OrgID is the organization of the user, service account or team
+	OrgID int64
+	Kind  assigneeKind
+	// Assignee is the login of a user or the name of a service account or a team
+	Assignee string
+	Role     *roleRefFromConfig
+	// Delete is set when the state of the assignment is absent
+	Delete bool
+}
+
+type configsV2 struct {
+	APIVersion      values.Int64Value       `json:"apiVersion" yaml:"apiVersion"`
+	Roles           []*roleFromConfigV2     `json:"roles" yaml:"roles"`
+	Teams           []*assigneeFromConfigV2 `json:"teams" yaml:"teams"`
+	Users           []*assigneeFromConfigV2 `json:"users" yaml:"users"`
+	ServiceAccounts []*assigneeFromConfigV2 `json:"serviceAccounts" yaml:"serviceAccounts"`
+}
+
+type roleFromConfigV2 struct {
+	OrgID       values.Int64Value      `json:"orgId" yaml:"orgId"`
+	Global      values.BoolValue       `json:"global" yaml:"global"`
+	UID         values.StringValue     `json:"uid" yaml:"uid"`
+	Name        values.StringValue     `json:"name" yaml:"name"`
+	DisplayName values.StringValue     `json:"displayName" yaml:"displayName"`
+	Description values.StringValue     `json:"description" yaml:"description"`
+	Group       values.StringValue     `json:"group" yaml:"group"`
+	Hidden      values.BoolValue       `json:"hidden" yaml:"hidden"`
+	Version     values.Int64Value      `json:"version" yaml:"version"`
+	From        []*roleRefFromConfigV2 `json:"from" yaml:"from"`
+	Permissions []*permissionConfigV2  `json:"permissions" yaml:"permissions"`
+	State       values.StringValue     `json:"state" yaml:"state"`
+	Force       values.BoolValue       `json:"force" yaml:"force"`
+}
+
+type permissionConfigV2 struct {
+	Action values.StringValue `json:"action" yaml:"action"`
+	Scope  values.StringValue `json:"scope" yaml:"scope"`
+	State  values.StringValue `json:"state" yaml:"state"`
+}
+
+type roleRefFromConfigV2 struct {
+	OrgID  values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Global values.BoolValue   `json:"global" yaml:"global"`
+	UID    values.StringValue `json:"uid" yaml:"uid"`
+	Name   values.StringValue `json:"name" yaml:"name"`
+	State  values.StringValue `json:"state" yaml:"state"`
+}
+
+// assigneeFromConfigV2 is a team, user or service account with the roles assigned to it.
+// Teams and service accounts are identified by name, users by login.
+type assigneeFromConfigV2 struct {
+	Name  values.StringValue     `json:"name" yaml:"name"`
+	Login values.StringValue     `json:"login" yaml:"login"`
+	OrgID values.Int64Value      `json:"orgId" yaml:"orgId"`
+	Roles []*roleRefFromConfigV2 `json:"roles" yaml:"roles"`
+}
+
+func (cfg *configsV2) mapToConfigs() *configs {
+	r := &configs{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, role := range cfg.Roles {
+		if role == nil {
+			continue
+		}
+		permissions := make([]accesscontrol.Permission, 0, len(role.Permissions))
+		for _, p := range role.Permissions {
+			// Permissions are stored as a whole, an absent permission is a permission left out of the role
+			if p == nil || p.State.Value() == stateAbsent {
+				continue
+			}
+			permissions = append(permissions, accesscontrol.Permission{Action: p.Action.Value(), Scope: p.Scope.Value()})
+		}
+		from := make([]*roleRefFromConfig, 0, len(role.From))
+		for _, ref := range role.From {
+			if ref != nil {
+				from = append(from, ref.mapToRoleRef())
+			}
+		}
+		r.Roles = append(r.Roles, &roleFromConfig{
+			OrgID:       role.OrgID.Value(),
+			Global:      role.Global.Value(),
+			UID:         role.UID.Value(),
+			Name:        role.Name.Value(),
+			DisplayName: role.DisplayName.Value(),
+			Description: role.Description.Value(),
+			Group:       role.Group.Value(),
+			Hidden:      role.Hidden.Value(),
+			Version:     role.Version.Value(),
+			Permissions: permissions,
+			From:        from,
+			Delete:      role.State.Value() == stateAbsent,
+			Force:       role.Force.Value(),
+		})
+	}
+
+	r.Assignments = append(r.Assignments, mapAssignments(assigneeTeam, cfg.Teams)...)
+	r.Assignments = append(r.Assignments, mapAssignments(assigneeUser, cfg.Users)...)
+	r.Assignments = append(r.Assignments, mapAssignments(assigneeServiceAccount, cfg.ServiceAccounts)...)
+
+	return r
+}
+
+func (ref *roleRefFromConfigV2) mapToRoleRef() *roleRefFromConfig {
+	return &roleRefFromConfig{
+		OrgID:  ref.OrgID.Value(),
+		Global: ref.Global.Value(),
+		UID:    ref.UID.Value(),
+		Name:   ref.Name.Value(),
+	}
+}
+
+func mapAssignments(kind assigneeKind, assignees []*assigneeFromConfigV2) []*assignmentFromConfig {
+	var result []*assignmentFromConfig
+	for _, assignee := range assignees {
+		if assignee == nil {
+			continue
+		}
+		name := assignee.Name.Value()
+		if kind == assigneeUser {
+			name = assignee.Login.Value()
+		}
+		for _, role := range assignee.Roles {
+			if role == nil {
+				continue
+			}
+			result = append(result, &assignmentFromConfig{
+				OrgID:    assignee.OrgID.Value(),
+				Kind:     kind,
+				Assignee: name,
+				Role:     role.mapToRoleRef(),
+				Delete:   role.State.Value() == stateAbsent,
+			})
+		}
+	}
+	return result
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -24,6 +25,7 @@ type Api struct {
 	AccessControl          accesscontrol.AccessControl
 	Features               *featuremgmt.FeatureManager
 	Log                    log.Logger
+	Cfg                    *setting.Cfg
 }
 
 func ProvideApi(
@@ -31,6 +33,7 @@ func ProvideApi(
 	rr routing.RouteRegister,
 	ac accesscontrol.AccessControl,
 	features *featuremgmt.FeatureManager,
+	cfg *setting.Cfg,
 ) *Api {
 	api := &Api{
 		PublicDashboardService: pd,
@@ -38,6 +41,7 @@ func ProvideApi(
 		AccessControl:          ac,
 		Features:               features,
 		Log:                    log.New("publicdashboards.api"),
+		Cfg:                    cfg,
 	}
 
 	// attach api if PublicDashboards feature flag is enabled
@@ -56,7 +60,12 @@ func (api *Api) RegisterAPIEndpoints() {
 	// circular dependency
 
 	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.ViewPublicDashboard))
-	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
+	queryHandlers := []web.Handler{routing.Wrap(api.QueryPublicDashboard)}
+	if api.Cfg.PublicDashboards.QueryRateLimit > 0 {
+		limiter := newQueryRateLimiter(api.Cfg.PublicDashboards.QueryRateLimit, api.Cfg.PublicDashboards.QueryRateBurst)
+		queryHandlers = append([]web.Handler{RequiresExistingAccessToken(api.PublicDashboardService), RateLimitQueries(limiter)}, queryHandlers...)
+	}
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", queryHandlers...)
 	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.GetAnnotations))
 
 	// Auth endpoints
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -47,6 +48,7 @@ func RequiresExistingAccessToken(publicDashboardService publicdashboards.Service
 
 		if !validation.IsValidAccessToken(accessToken) {
 			c.JsonApiErr(http.StatusBadRequest, "Invalid access token", nil)
+			return
 		}
 
 		// Check that the access token references an enabled public dashboard
@@ -67,3 +69,19 @@ func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
 	}
 }
+
+// RateLimitQueries Middleware to limit the rate of the queries made with each access token, so that a shared public
+// dashboard can't overload its data sources. Queries above the limit are rejected until the rate decreases. Use after
+// RequiresExistingAccessToken, so that a limiter is only kept for the access tokens of existing public dashboards.
+func RateLimitQueries(limiter *queryRateLimiter) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if limiter.Allow(accessToken) {
+			return
+		}
+
+		metrics.MPublicDashboardAccessCount.WithLabelValues(AccessQuery, AccessRateLimited).Inc()
+		c.Logger.Warn("Public dashboard query rate limit exceeded", "panelId", web.Params(c.Req)[":panelId"])
+		c.WriteErr(ErrTooManyQueries.Errorf("RateLimitQueries: query rate limit exceeded"))
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
@@ -144,6 +146,29 @@ func TestSetPublicDashboardOrgIdOnContext(t *testing.T) {
 	}
 }
 
+func TestRateLimitQueries(t *testing.T) {
+	otherAccessToken, _ := service.GenerateAccessToken()
+	limiter := newQueryRateLimiter(1, 2)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	mw := RateLimitQueries(limiter)
+
+	query := func(accessToken string) int {
+		ctx := &contextmodel.ReqContext{Logger: log.NewNopLogger()}
+		params := map[string]string{":accessToken": accessToken, ":panelId": "1"}
+		_, resp := runMw(t, ctx, "POST", "/api/public/dashboards/myAccessToken/panels/1/query", params, mw)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusOK, query(validAccessToken))
+	assert.Equal(t, http.StatusOK, query(validAccessToken))
+	assert.Equal(t, http.StatusTooManyRequests, query(validAccessToken), "queries above the burst should be rejected")
+	assert.Equal(t, http.StatusOK, query(otherAccessToken), "each access token should have its own limit")
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, query(validAccessToken), "queries should be allowed again at the rate of the limit")
+}
+
 func TestSetPublicDashboardFlag(t *testing.T) {
 	t.Run("Adds context.PublicDashboardAccessToken to request", func(t *testing.T) {
 		ctx := &contextmodel.ReqContext{Context: &web.Context{Req: web.SetURLParams(&http.Request{}, map[string]string{":accessToken": "asdfasdfasdfsadfasdfsfd"})}}
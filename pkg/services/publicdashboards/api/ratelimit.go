@@ -0,0 +1,84 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// limiterIdleTimeout is how long the limiter of an access token is kept without queries.
+	limiterIdleTimeout = 10 * time.Minute
+	// maxLimiters bounds the memory used by the limiters, the least recently used one is dropped above it.
+	maxLimiters = 10000
+)
+
+// queryRateLimiter limits the rate of the queries made with each public dashboard access token. The limiters are
+// kept in memory, so every Grafana instance enforces the limit separately. Only the access tokens of existing public
+// dashboards should be passed to Allow, see RequiresExistingAccessToken.
+type queryRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu          sync.Mutex
+	limiters    map[string]*tokenLimiter
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+type tokenLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newQueryRateLimiter(queriesPerSecond float64, burst int) *queryRateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &queryRateLimiter{
+		limit:    rate.Limit(queriesPerSecond),
+		burst:    burst,
+		limiters: make(map[string]*tokenLimiter),
+		now:      time.Now,
+	}
+}
+
+// Allow reports whether a query can be made with the access token now.
+func (l *queryRateLimiter) Allow(accessToken string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastCleanup) > limiterIdleTimeout {
+		for token, tl := range l.limiters {
+			if now.Sub(tl.lastSeen) > limiterIdleTimeout {
+				delete(l.limiters, token)
+			}
+		}
+		l.lastCleanup = now
+	}
+
+	tl, ok := l.limiters[accessToken]
+	if !ok {
+		if len(l.limiters) >= maxLimiters {
+			l.evictLeastRecentlyUsed()
+		}
+		tl = &tokenLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[accessToken] = tl
+	}
+	tl.lastSeen = now
+
+	return tl.limiter.AllowN(now, 1)
+}
+
+func (l *queryRateLimiter) evictLeastRecentlyUsed() {
+	var oldest string
+	var oldestSeen time.Time
+	for token, tl := range l.limiters {
+		if oldest == "" || tl.lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = token, tl.lastSeen
+		}
+	}
+	delete(l.limiters, oldest)
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryRateLimiter(t *testing.T) {
+	limiter := newQueryRateLimiter(1, 1)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow("idle"))
+	now = now.Add(limiterIdleTimeout / 2)
+	assert.True(t, limiter.Allow("active"))
+	assert.Len(t, limiter.limiters, 2)
+
+	now = now.Add(limiterIdleTimeout/2 + time.Second)
+	assert.True(t, limiter.Allow("active"))
+	assert.Len(t, limiter.limiters, 1, "limiters of idle access tokens should be removed")
+	assert.Contains(t, limiter.limiters, "active")
+}
+
+func TestQueryRateLimiter_MaxLimiters(t *testing.T) {
+	limiter := newQueryRateLimiter(1, 1)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < maxLimiters; i++ {
+		now = now.Add(time.Millisecond)
+		assert.True(t, limiter.Allow(strconv.Itoa(i)))
+	}
+	now = now.Add(time.Millisecond)
+	assert.True(t, limiter.Allow("new"))
+	assert.Len(t, limiter.limiters, maxLimiters)
+	assert.NotContains(t, limiter.limiters, "0", "the least recently used limiter should be removed")
+	assert.Contains(t, limiter.limiters, "1")
+}
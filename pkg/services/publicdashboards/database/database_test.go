@@ -254,6 +254,34 @@ func TestIntegrationExistsEnabledByAccessToken(t *testing.T) {
 		require.False(t, res)
 	})
 
+	t.Run("ExistsEnabledByAccessToken will return false when the access token expired", func(t *testing.T) {
+		for _, tc := range []struct {
+			expiresAt time.Time
+			exists    bool
+		}{{time.Now().Add(-time.Minute), false}, {time.Now().Add(time.Hour), true}} {
+			setup()
+
+			_, err := publicdashboardStore.Create(context.Background(), SavePublicDashboardCommand{
+				PublicDashboard: PublicDashboard{
+					IsEnabled:    true,
+					Uid:          "abc123",
+					DashboardUid: savedDashboard.UID,
+					OrgId:        savedDashboard.OrgID,
+					CreatedAt:    time.Now(),
+					CreatedBy:    7,
+					AccessToken:  "accessToken",
+					ExpiresAt:    &tc.expiresAt,
+				},
+			})
+			require.NoError(t, err)
+
+			res, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), "accessToken")
+			require.NoError(t, err)
+
+			require.Equal(t, tc.exists, res)
+		}
+	})
+
 	t.Run("ExistsEnabledByAccessToken will return false when no public dashboard has matching access token", func(t *testing.T) {
 		setup()
 
@@ -617,6 +645,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 			TimeSelectionEnabled: true,
 			Share:                EmailShareType,
 			TimeSettings:         &TimeSettings{From: "now-8", To: "now"},
+			Variables:            PublicDashboardVariables{{Name: "env", Source: VariableSourceStatic, Values: []string{"production", "staging"}}},
 			UpdatedAt:            time.Now().UTC().Round(time.Second),
 			UpdatedBy:            8,
 		}
@@ -638,6 +667,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.Equal(t, updatedPublicDashboard.AnnotationsEnabled, pdRetrieved.AnnotationsEnabled)
 		assert.Equal(t, updatedPublicDashboard.TimeSelectionEnabled, pdRetrieved.TimeSelectionEnabled)
 		assert.Equal(t, updatedPublicDashboard.Share, pdRetrieved.Share)
+		assert.Equal(t, updatedPublicDashboard.Variables, pdRetrieved.Variables)
 
 		// not updated dashboard shouldn't have changed
 		pdNotUpdatedRetrieved, err := publicdashboardStore.FindByDashboardUid(context.Background(), anotherSavedDashboard.OrgID, anotherSavedDashboard.UID)
@@ -646,6 +676,7 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.NotEqual(t, updatedPublicDashboard.IsEnabled, pdNotUpdatedRetrieved.IsEnabled)
 		assert.NotEqual(t, updatedPublicDashboard.AnnotationsEnabled, pdNotUpdatedRetrieved.AnnotationsEnabled)
 		assert.NotEqual(t, updatedPublicDashboard.Share, pdNotUpdatedRetrieved.Share)
+		assert.Nil(t, pdNotUpdatedRetrieved.Variables)
 	})
 }
 
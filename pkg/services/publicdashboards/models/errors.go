@@ -21,6 +21,12 @@ var (
 	ErrInvalidTimeRange                    = errutil.BadRequest("publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
 	ErrInvalidShareType                    = errutil.BadRequest("publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
+	ErrInvalidSecondsToLive                = errutil.BadRequest("publicdashboards.invalidSecondsToLive", errutil.WithPublicMessage("secondsToLive should not be negative"))
+	ErrInvalidVariables                    = errutil.BadRequest("publicdashboards.invalidVariables", errutil.WithPublicMessage("Invalid template variables"))
+	ErrInvalidVariableValue                = errutil.BadRequest("publicdashboards.invalidVariableValue", errutil.WithPublicMessage("Template variable value is not allowed"))
 
 	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+	ErrPublicDashboardExpired    = errutil.Forbidden("publicdashboards.expired", errutil.WithPublicMessage("Public dashboard expired"))
+
+	ErrTooManyQueries = errutil.TooManyRequests("publicdashboards.tooManyQueries", errutil.WithPublicMessage("Too many queries, try again later"))
 )
@@ -30,13 +30,37 @@ const (
 	PublicShareType ShareType = "public"
 )
 
+// Kinds of accesses to a public dashboard and their statuses, recorded in the access log.
+const (
+	AccessView        = "view"
+	AccessQuery       = "query"
+	AccessAnnotations = "annotations"
+	AccessSuccess     = "success"
+	AccessFailure     = "failure"
+	AccessDenied      = "denied"
+	AccessRateLimited = "rate_limited"
+)
+
+// Sources of the values viewers can select for a template variable of a public dashboard.
+const (
+	// VariableSourceStatic uses the values set by the owner of the public dashboard.
+	VariableSourceStatic VariableSource = "static"
+	// VariableSourceDashboard uses the values of the dashboard variable, resolved when the public dashboard is saved.
+	VariableSourceDashboard VariableSource = "dashboard"
+)
+
 var (
-	QueryResultStatuses = []string{QuerySuccess, QueryFailure}
-	ValidShareTypes     = []ShareType{EmailShareType, PublicShareType}
+	QueryResultStatuses  = []string{QuerySuccess, QueryFailure}
+	ValidShareTypes      = []ShareType{EmailShareType, PublicShareType}
+	ValidVariableSources = []VariableSource{VariableSourceStatic, VariableSourceDashboard}
+	AccessKinds          = []string{AccessView, AccessQuery, AccessAnnotations}
+	AccessStatuses       = []string{AccessSuccess, AccessFailure, AccessDenied, AccessRateLimited}
 )
 
 type ShareType string
 
+type VariableSource string
+
 type PublicDashboard struct {
 	Uid          string    `json:"uid" xorm:"pk uid"`
 	DashboardUid string    `json:"dashboardUid" xorm:"dashboard_uid"`
@@ -52,7 +76,16 @@ type PublicDashboard struct {
 	IsEnabled            bool          `json:"isEnabled" xorm:"is_enabled"`
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
-	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	// ExpiresAt is when the access token expires, it never expires when nil.
+	ExpiresAt *time.Time `json:"expiresAt" xorm:"expires_at"`
+	// Variables are the template variables viewers can change, with the values they are allowed to select.
+	Variables  PublicDashboardVariables `json:"variables" xorm:"variables"`
+	Recipients []EmailDTO               `json:"recipients,omitempty" xorm:"-"`
+}
+
+// IsExpired returns true if the access token of the public dashboard has expired.
+func (pd PublicDashboard) IsExpired(now time.Time) bool {
+	return pd.ExpiresAt != nil && !now.Before(*pd.ExpiresAt)
 }
 
 type PublicDashboardDTO struct {
@@ -60,6 +93,57 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// SecondsToLive sets the expiration of the access token from now, 0 removes it. The expiration is unchanged when nil.
+	SecondsToLive *int64 `json:"secondsToLive"`
+	// RotateAccessToken replaces the access token with a new one, which invalidates the links that were shared.
+	RotateAccessToken bool `json:"rotateAccessToken"`
+	// Variables replaces the template variables viewers can change, they are unchanged when nil.
+	Variables []PublicDashboardVariable `json:"variables"`
+}
+
+// PublicDashboardVariable is a template variable of the dashboard that viewers of the public dashboard can change
+// to one of the allowed values, instead of the current value saved in the dashboard.
+type PublicDashboardVariable struct {
+	Name   string         `json:"name"`
+	Source VariableSource `json:"source"`
+	Values []string       `json:"values"`
+}
+
+// IsAllowed returns true if the value can be selected for the variable.
+func (v PublicDashboardVariable) IsAllowed(value string) bool {
+	for _, allowed := range v.Values {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+type PublicDashboardVariables []PublicDashboardVariable
+
+// Get returns the variable with the given name, or nil if viewers cannot change it.
+func (vs PublicDashboardVariables) Get(name string) *PublicDashboardVariable {
+	for i := range vs {
+		if vs[i].Name == name {
+			return &vs[i]
+		}
+	}
+	return nil
+}
+
+func (vs *PublicDashboardVariables) FromDB(data []byte) error {
+	if len(data) == 0 {
+		*vs = nil
+		return nil
+	}
+	return json.Unmarshal(data, vs)
+}
+
+func (vs PublicDashboardVariables) ToDB() ([]byte, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(vs)
 }
 
 type EmailDTO struct {
@@ -110,11 +194,12 @@ type PublicDashboardListResponseWithPagination struct {
 }
 
 type PublicDashboardListResponse struct {
-	Uid          string `json:"uid" xorm:"uid"`
-	AccessToken  string `json:"accessToken" xorm:"access_token"`
-	Title        string `json:"title" xorm:"title"`
-	DashboardUid string `json:"dashboardUid" xorm:"dashboard_uid"`
-	IsEnabled    bool   `json:"isEnabled" xorm:"is_enabled"`
+	Uid          string     `json:"uid" xorm:"uid"`
+	AccessToken  string     `json:"accessToken" xorm:"access_token"`
+	Title        string     `json:"title" xorm:"title"`
+	DashboardUid string     `json:"dashboardUid" xorm:"dashboard_uid"`
+	IsEnabled    bool       `json:"isEnabled" xorm:"is_enabled"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty" xorm:"expires_at"`
 }
 
 type TimeSettings struct {
@@ -150,6 +235,8 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables are the values selected by the viewer for the template variables of the public dashboard.
+	Variables map[string]string
 }
 
 type AnnotationsQueryDTO struct {
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -10,6 +12,38 @@ import (
 A place to record relevant logs and metrics within the service layer of public dashboards
 */
 
+// accessLog is the access log of public dashboards, it records every view of and query to a public dashboard.
+var accessLog = log.New("publicdashboards.access")
+
+// LogAccess records an access to a public dashboard in the access log and in the access metrics. The public
+// dashboard is nil when it can't be found.
+func LogAccess(kind string, pubdash *models.PublicDashboard, err error) {
+	status := accessStatus(err)
+	metrics.MPublicDashboardAccessCount.WithLabelValues(kind, status).Inc()
+
+	ctx := []interface{}{"kind", kind, "status", status}
+	if pubdash != nil {
+		ctx = append(ctx, "publicDashboardUid", pubdash.Uid, "dashboardUid", pubdash.DashboardUid, "orgId", pubdash.OrgId)
+	}
+	if err != nil {
+		ctx = append(ctx, "error", err)
+	}
+	accessLog.Info("Public dashboard accessed", ctx...)
+}
+
+func accessStatus(err error) string {
+	switch {
+	case err == nil:
+		return models.AccessSuccess
+	case errors.Is(err, models.ErrPublicDashboardNotFound),
+		errors.Is(err, models.ErrPublicDashboardNotEnabled),
+		errors.Is(err, models.ErrPublicDashboardExpired):
+		return models.AccessDenied
+	default:
+		return models.AccessFailure
+	}
+}
+
 func LogQuerySuccess(datasources []string, log log.Logger) {
 	log.Info("Successfully queried datasources for public dashboard", "datasources", datasources)
 	label := getLabelName(datasources)
@@ -65,7 +65,10 @@ func ProvideService(
 	}
 }
 
-func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Context, accessToken string) (*dtos.DashboardFullWithMeta, error) {
+func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Context, accessToken string) (_ *dtos.DashboardFullWithMeta, err error) {
+	var pubdash *PublicDashboard
+	defer func() { LogAccess(AccessView, pubdash, err) }()
+
 	pubdash, dash, err := pd.FindEnabledPublicDashboardAndDashboardByAccessToken(ctx, accessToken)
 	if err != nil {
 		return nil, err
@@ -89,6 +92,7 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
 	sanitizeData(dash.Data)
+	sanitizeVariables(dash.Data, pubdash.Variables)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -145,6 +149,10 @@ func (pd *PublicDashboardServiceImpl) FindEnabledPublicDashboardAndDashboardByAc
 		return nil, nil, ErrPublicDashboardNotEnabled.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard is not enabled accessToken: %s", accessToken)
 	}
 
+	if pubdash.IsExpired(time.Now()) {
+		return nil, nil, ErrPublicDashboardExpired.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard expired at %s", pubdash.ExpiresAt)
+	}
+
 	return pubdash, dash, err
 }
 
@@ -176,7 +184,12 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	}
 
 	// ensure dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dashboard, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := pd.resolveVariables(ctx, dashboard, dto.PublicDashboard.Variables)
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +208,7 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	if err != nil {
 		return nil, err
 	}
+	publicDashboard.Variables = variables
 
 	cmd := SavePublicDashboardCommand{
 		PublicDashboard: *publicDashboard,
@@ -251,6 +265,20 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 
 	publicDashboard := newUpdatePublicDashboard(dto, existingPubdash)
 
+	if dto.PublicDashboard.Variables != nil {
+		publicDashboard.Variables, err = pd.resolveVariables(ctx, dashboard, dto.PublicDashboard.Variables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dto.PublicDashboard.RotateAccessToken {
+		publicDashboard.AccessToken, err = pd.NewPublicDashboardAccessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// set values to update
 	cmd := SavePublicDashboardCommand{
 		PublicDashboard: *publicDashboard,
@@ -274,6 +302,9 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 	}
 
 	pd.logIsEnabledChanged(existingPubdash, newPubdash, u)
+	if newPubdash.AccessToken != existingPubdash.AccessToken {
+		pd.log.Info("Public dashboard access token rotated", "publicDashboardUid", newPubdash.Uid, "dashboardUid", newPubdash.DashboardUid, "user", u.Login)
+	}
 
 	return newPubdash, nil
 }
@@ -449,6 +480,12 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 
 	now := time.Now()
 
+	var expiresAt *time.Time
+	if dto.PublicDashboard.SecondsToLive != nil && *dto.PublicDashboard.SecondsToLive > 0 {
+		v := now.Add(time.Duration(*dto.PublicDashboard.SecondsToLive) * time.Second)
+		expiresAt = &v
+	}
+
 	return &PublicDashboard{
 		Uid:                  uid,
 		DashboardUid:         dto.DashboardUid,
@@ -463,6 +500,7 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            now,
 		AccessToken:          accessToken,
+		ExpiresAt:            expiresAt,
 	}, nil
 }
 
@@ -477,6 +515,16 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
+	now := time.Now()
+	expiresAt := pd.ExpiresAt
+	if pubdashDTO.SecondsToLive != nil {
+		expiresAt = nil
+		if *pubdashDTO.SecondsToLive > 0 {
+			v := now.Add(time.Duration(*pubdashDTO.SecondsToLive) * time.Second)
+			expiresAt = &v
+		}
+	}
+
 	return &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
@@ -484,8 +532,11 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
 		Share:                share,
+		AccessToken:          pd.AccessToken,
+		ExpiresAt:            expiresAt,
+		Variables:            pd.Variables,
 		UpdatedBy:            dto.UserId,
-		UpdatedAt:            time.Now(),
+		UpdatedAt:            now,
 	}
 }
 
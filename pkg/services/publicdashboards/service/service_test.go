@@ -519,6 +519,8 @@ func TestGetPublicDashboard(t *testing.T) {
 }
 
 func TestGetEnabledPublicDashboard(t *testing.T) {
+	expiredAt := time.Now().Add(-time.Minute)
+
 	type storeResp struct {
 		pd  *PublicDashboard
 		d   *dashboards.Dashboard
@@ -554,6 +556,17 @@ func TestGetEnabledPublicDashboard(t *testing.T) {
 			ErrResp:  ErrPublicDashboardNotFound,
 			DashResp: nil,
 		},
+		{
+			Name:        "returns ErrPublicDashboardExpired when the access token expired",
+			AccessToken: "abc123",
+			StoreResp: &storeResp{
+				pd:  &PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, ExpiresAt: &expiredAt},
+				d:   &dashboards.Dashboard{UID: "mydashboard"},
+				err: nil,
+			},
+			ErrResp:  ErrPublicDashboardExpired,
+			DashResp: nil,
+		},
 	}
 
 	for _, test := range testCases {
@@ -1021,6 +1034,54 @@ func TestUpdatePublicDashboard(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("Updating expiration and rotating access token", func(t *testing.T) {
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie3", 1, 0, true, []map[string]interface{}{}, nil)
+		isEnabled := true
+		secondsToLive := int64(3600)
+
+		savedPubdash, err := service.Create(context.Background(), SignedInUser, &SavePublicDashboardDTO{
+			DashboardUid: dashboard.UID,
+			UserId:       7,
+			PublicDashboard: &PublicDashboardDTO{
+				IsEnabled:     &isEnabled,
+				SecondsToLive: &secondsToLive,
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, savedPubdash.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *savedPubdash.ExpiresAt, time.Minute)
+
+		// the expiration is kept when not set
+		updatedPubdash, err := service.Update(context.Background(), SignedInUser, &SavePublicDashboardDTO{
+			Uid:          savedPubdash.Uid,
+			DashboardUid: dashboard.UID,
+			UserId:       8,
+			PublicDashboard: &PublicDashboardDTO{
+				RotateAccessToken: true,
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, updatedPubdash.ExpiresAt)
+		assert.Equal(t, savedPubdash.ExpiresAt.Unix(), updatedPubdash.ExpiresAt.Unix())
+		assert.NotEqual(t, savedPubdash.AccessToken, updatedPubdash.AccessToken)
+
+		_, err = service.FindByAccessToken(context.Background(), savedPubdash.AccessToken)
+		assert.ErrorIs(t, err, ErrPublicDashboardNotFound, "the rotated access token should not be valid anymore")
+
+		// the expiration is removed when 0
+		secondsToLive = 0
+		updatedPubdash, err = service.Update(context.Background(), SignedInUser, &SavePublicDashboardDTO{
+			Uid:          savedPubdash.Uid,
+			DashboardUid: dashboard.UID,
+			UserId:       8,
+			PublicDashboard: &PublicDashboardDTO{
+				SecondsToLive: &secondsToLive,
+			},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, updatedPubdash.ExpiresAt)
+	})
+
 	trueBooleanField := true
 	timeSettings := &TimeSettings{From: "now-8", To: "now"}
 	shareType := EmailShareType
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// variable types that cannot be changed by viewers, as they change the data sources or filters of the queries
+var unsupportedVariableTypes = map[string]bool{"datasource": true, "adhoc": true}
+
+// resolveVariables checks the template variables viewers can change exist in the dashboard, and resolves the allowed
+// values of the variables whose values come from the dashboard
+func (pd *PublicDashboardServiceImpl) resolveVariables(ctx context.Context, dash *dashboards.Dashboard, variables []PublicDashboardVariable) (PublicDashboardVariables, error) {
+	if len(variables) == 0 {
+		return nil, nil
+	}
+
+	dashVariables := make(map[string]*simplejson.Json)
+	for _, obj := range dash.Data.Get("templating").Get("list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		dashVariables[v.Get("name").MustString()] = v
+	}
+
+	resolved := make(PublicDashboardVariables, 0, len(variables))
+	for _, v := range variables {
+		dashVariable, ok := dashVariables[v.Name]
+		if !ok {
+			return nil, ErrInvalidVariables.Errorf("resolveVariables: variable %s not found in dashboard", v.Name)
+		}
+
+		if varType := dashVariable.Get("type").MustString(); unsupportedVariableTypes[varType] {
+			return nil, ErrInvalidVariables.Errorf("resolveVariables: variable %s of type %s cannot be changed by viewers", v.Name, varType)
+		}
+
+		values := v.Values
+		if v.Source == VariableSourceDashboard {
+			var err error
+			values, err = pd.dashboardVariableValues(ctx, dash, dashVariable)
+			if err != nil {
+				return nil, ErrInvalidVariables.Errorf("resolveVariables: failed to resolve the values of variable %s: %w", v.Name, err)
+			}
+		}
+		values = uniqueValues(values)
+
+		if len(values) == 0 {
+			return nil, ErrInvalidVariables.Errorf("resolveVariables: variable %s has no allowed values", v.Name)
+		}
+
+		resolved = append(resolved, PublicDashboardVariable{Name: v.Name, Source: v.Source, Values: values})
+	}
+
+	return resolved, nil
+}
+
+// dashboardVariableValues returns the values of a dashboard variable. The values of query variables are queried from
+// their data source, as the options saved in the dashboard are empty for the variables refreshed when it loads.
+func (pd *PublicDashboardServiceImpl) dashboardVariableValues(ctx context.Context, dash *dashboards.Dashboard, variable *simplejson.Json) ([]string, error) {
+	query := variable.Get("query").MustString()
+
+	switch variable.Get("type").MustString() {
+	case "constant", "textbox":
+		return []string{query}, nil
+	case "custom":
+		var values []string
+		for _, option := range splitCustomQuery(query) {
+			// custom options can be written as "text : value"
+			if _, value, ok := strings.Cut(option, " : "); ok {
+				option = value
+			}
+			values = append(values, strings.TrimSpace(option))
+		}
+		return values, nil
+	case "query":
+		return pd.queryVariableValues(ctx, dash, variable)
+	}
+
+	var values []string
+	for _, obj := range variable.Get("options").MustArray() {
+		value, ok := simplejson.NewFromAny(obj).Get("value").Interface().(string)
+		if ok && value != "$__all" {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// queryVariableValues runs the query of a query variable with the identity public dashboards query with, over the
+// time range of the dashboard, and returns the values matching the regex of the variable
+func (pd *PublicDashboardServiceImpl) queryVariableValues(ctx context.Context, dash *dashboards.Dashboard, variable *simplejson.Json) ([]string, error) {
+	query, err := variableQuery(variable)
+	if err != nil {
+		return nil, err
+	}
+
+	// the data source of the variable is not always used by the panels
+	anonymousUser := buildAnonymousUser(ctx, dash)
+	if uid := variable.GetPath("datasource", "uid").MustString(); uid != "" {
+		permissions := anonymousUser.Permissions[dash.OrgID]
+		scope := datasources.ScopeProvider.GetResourceScopeUID(uid)
+		permissions[datasources.ActionQuery] = append(permissions[datasources.ActionQuery], scope)
+		permissions[datasources.ActionRead] = append(permissions[datasources.ActionRead], scope)
+	}
+
+	res, err := pd.QueryDataService.QueryData(ctx, anonymousUser, false, dtos.MetricRequest{
+		From:    dash.Data.GetPath("time", "from").MustString("now-6h"),
+		To:      dash.Data.GetPath("time", "to").MustString("now"),
+		Queries: []*simplejson.Json{query},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, r := range res.Responses {
+		if r.Error != nil {
+			return nil, r.Error
+		}
+		for _, frame := range r.Frames {
+			values = append(values, frameValues(frame)...)
+		}
+	}
+
+	return filterVariableValues(variable.Get("regex").MustString(), values)
+}
+
+// variableQuery returns the query model of a query variable. The variables of most data sources save their query as a
+// model, the others as a string which the data sources with backend variable queries read from query or rawSql.
+func variableQuery(variable *simplejson.Json) (*simplejson.Json, error) {
+	query := simplejson.New()
+	if _, ok := variable.Get("query").Interface().(map[string]interface{}); ok {
+		// copied so that the dashboard is not changed
+		b, err := variable.Get("query").MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		if query, err = simplejson.NewJson(b); err != nil {
+			return nil, err
+		}
+	} else {
+		raw := variable.Get("query").MustString()
+		query.Set("query", raw)
+		query.Set("rawSql", raw)
+		query.Set("format", "table")
+	}
+
+	if query.Get("refId").MustString() == "" {
+		query.Set("refId", "variable-query")
+	}
+	query.Set("datasource", variable.Get("datasource").Interface())
+	return query, nil
+}
+
+// frameValues returns the values of a frame returned by a variable query: the values of its value field, or of its
+// first string field, like the frontend does
+func frameValues(frame *data.Frame) []string {
+	var field *data.Field
+	for _, f := range frame.Fields {
+		if f.Name == "__value" || f.Name == "value" {
+			field = f
+			break
+		}
+		if field == nil && (f.Type() == data.FieldTypeString || f.Type() == data.FieldTypeNullableString) {
+			field = f
+		}
+	}
+	if field == nil && len(frame.Fields) > 0 {
+		field = frame.Fields[0]
+	}
+	if field == nil {
+		return nil
+	}
+
+	values := make([]string, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		if v, ok := field.ConcreteAt(i); ok {
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	return values
+}
+
+// filterVariableValues keeps the values matching the regex of a variable, written like /pattern/flags. The value group
+// or the first group of the regex is kept when it has one, like the frontend does.
+func filterVariableValues(pattern string, values []string) ([]string, error) {
+	if pattern == "" {
+		return values, nil
+	}
+
+	if strings.HasPrefix(pattern, "/") {
+		if end := strings.LastIndex(pattern, "/"); end > 0 {
+			flags := pattern[end+1:]
+			pattern = pattern[1:end]
+			if strings.Contains(flags, "i") {
+				pattern = "(?i)" + pattern
+			}
+		}
+	}
+	// named groups are written (?<name>) in JavaScript
+	re, err := regexp.Compile(strings.ReplaceAll(pattern, "(?<", "(?P<"))
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]string, 0, len(values))
+	for _, v := range values {
+		match := re.FindStringSubmatch(v)
+		switch {
+		case match == nil:
+			continue
+		case re.SubexpIndex("value") > 0:
+			filtered = append(filtered, match[re.SubexpIndex("value")])
+		case len(match) > 1:
+			filtered = append(filtered, match[1])
+		default:
+			filtered = append(filtered, match[0])
+		}
+	}
+	return filtered, nil
+}
+
+// splitCustomQuery splits the options of a custom variable on the commas that are not escaped
+func splitCustomQuery(query string) []string {
+	var options []string
+	var option strings.Builder
+	for i := 0; i < len(query); i++ {
+		switch {
+		case query[i] == '\\' && i+1 < len(query) && query[i+1] == ',':
+			option.WriteByte(',')
+			i++
+		case query[i] == ',':
+			options = append(options, option.String())
+			option.Reset()
+		default:
+			option.WriteByte(query[i])
+		}
+	}
+	return append(options, option.String())
+}
+
+func uniqueValues(values []string) []string {
+	exists := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" || exists[v] {
+			continue
+		}
+		exists[v] = true
+		unique = append(unique, v)
+	}
+	return unique
+}
+
+// sanitizeVariables replaces the template variables viewers can change by custom variables holding the allowed values,
+// so that the viewers neither see nor run the queries of the variables
+func sanitizeVariables(data *simplejson.Json, variables PublicDashboardVariables) {
+	for _, obj := range data.Get("templating").Get("list").MustArray() {
+		dashVariable := simplejson.NewFromAny(obj)
+		v := variables.Get(dashVariable.Get("name").MustString())
+		if v == nil {
+			continue
+		}
+
+		current := defaultVariableValue(dashVariable, *v)
+		escaped := make([]string, 0, len(v.Values))
+		options := make([]interface{}, 0, len(v.Values))
+		for _, value := range v.Values {
+			escaped = append(escaped, strings.ReplaceAll(value, ",", `\,`))
+			options = append(options, map[string]interface{}{"text": value, "value": value, "selected": value == current})
+		}
+
+		dashVariable.Set("type", "custom")
+		dashVariable.Set("query", strings.Join(escaped, ","))
+		dashVariable.Set("options", options)
+		dashVariable.Set("current", map[string]interface{}{"text": current, "value": current})
+		dashVariable.Set("multi", false)
+		dashVariable.Set("includeAll", false)
+		dashVariable.Del("datasource")
+		dashVariable.Del("definition")
+		dashVariable.Del("regex")
+	}
+}
+
+// defaultVariableValue returns the current value of the dashboard variable if it is allowed, the first allowed value otherwise
+func defaultVariableValue(dashVariable *simplejson.Json, variable PublicDashboardVariable) string {
+	if current, ok := dashVariable.GetPath("current", "value").Interface().(string); ok && variable.IsAllowed(current) {
+		return current
+	}
+	return variable.Values[0]
+}
+
+// variableValues returns the values of the template variables viewers can change, selected in the request or by default
+func variableValues(dashboard *dashboards.Dashboard, publicDashboard *PublicDashboard, selected map[string]string) map[string]string {
+	values := make(map[string]string, len(publicDashboard.Variables))
+	for _, obj := range dashboard.Data.Get("templating").Get("list").MustArray() {
+		dashVariable := simplejson.NewFromAny(obj)
+		v := publicDashboard.Variables.Get(dashVariable.Get("name").MustString())
+		if v == nil {
+			continue
+		}
+
+		if value, ok := selected[v.Name]; ok && v.IsAllowed(value) {
+			values[v.Name] = value
+		} else {
+			values[v.Name] = defaultVariableValue(dashVariable, *v)
+		}
+	}
+	return values
+}
+
+// interpolateVariables replaces the template variables in the query by their values, except in its data source which
+// viewers cannot change.
+func interpolateVariables(query *simplejson.Json, values map[string]string) {
+	variables := make(map[string][]string, len(values))
+	for name, value := range values {
+		variables[name] = []string{value}
+	}
+	dashboards.InterpolateQuery(query, variables)
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const dashboardWithTemplateVariables = `
+{
+  "templating": {
+    "list": [
+      {
+        "name": "env",
+        "type": "custom",
+        "query": "production,staging,dev\\,test",
+        "current": {"text": "staging", "value": "staging"}
+      },
+      {
+        "name": "host",
+        "type": "query",
+        "query": "label_values(up, host)",
+        "definition": "label_values(up, host)",
+        "datasource": {"type": "prometheus", "uid": "ds1"},
+        "current": {"text": "a", "value": "a"},
+        "options": [
+          {"text": "All", "value": "$__all"},
+          {"text": "a", "value": "a"},
+          {"text": "b", "value": "b"}
+        ]
+      },
+      {
+        "name": "region",
+        "type": "query",
+        "query": {"refId": "A", "rawSql": "SELECT region FROM regions"},
+        "datasource": {"type": "mysql", "uid": "ds2"},
+        "regex": "/^eu-(?<value>.*)$/",
+        "refresh": 1,
+        "options": []
+      },
+      {
+        "name": "ds",
+        "type": "datasource",
+        "query": "prometheus"
+      }
+    ]
+  }
+}`
+
+func newDashboardWithTemplateVariables(t *testing.T) *dashboards.Dashboard {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	return &dashboards.Dashboard{OrgID: 1, Data: data}
+}
+
+// newVariablesService returns a service whose queries return a frame with the given values
+func newVariablesService(t *testing.T, values ...string) (*PublicDashboardServiceImpl, *query.FakeQueryService) {
+	t.Helper()
+
+	fakeQueryService := &query.FakeQueryService{}
+	fakeQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+		Responses: backend.Responses{"variable-query": backend.DataResponse{
+			Frames: data.Frames{data.NewFrame("", data.NewField("region", nil, values))},
+		}},
+	}, nil)
+	return &PublicDashboardServiceImpl{QueryDataService: fakeQueryService}, fakeQueryService
+}
+
+func TestResolveVariables(t *testing.T) {
+	t.Run("resolves the values of the dashboard variables", func(t *testing.T) {
+		pd, _ := newVariablesService(t, "a", "b", "a")
+		variables, err := pd.resolveVariables(context.Background(), newDashboardWithTemplateVariables(t), []PublicDashboardVariable{
+			{Name: "env", Source: VariableSourceDashboard},
+			{Name: "host", Source: VariableSourceDashboard},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, PublicDashboardVariables{
+			{Name: "env", Source: VariableSourceDashboard, Values: []string{"production", "staging", "dev,test"}},
+			{Name: "host", Source: VariableSourceDashboard, Values: []string{"a", "b"}},
+		}, variables)
+	})
+
+	t.Run("queries the values of a variable refreshed on load with the data source of the variable", func(t *testing.T) {
+		pd, fakeQueryService := newVariablesService(t, "eu-west", "us-east", "eu-north")
+		variables, err := pd.resolveVariables(context.Background(), newDashboardWithTemplateVariables(t), []PublicDashboardVariable{
+			{Name: "region", Source: VariableSourceDashboard},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, PublicDashboardVariables{
+			{Name: "region", Source: VariableSourceDashboard, Values: []string{"west", "north"}},
+		}, variables)
+
+		fakeQueryService.AssertCalled(t, "QueryData", mock.Anything, mock.MatchedBy(func(u *user.SignedInUser) bool {
+			return u.OrgID == 1 && u.Permissions[1][datasources.ActionQuery][0] == datasources.ScopeProvider.GetResourceScopeUID("ds2")
+		}), false, mock.MatchedBy(func(req dtos.MetricRequest) bool {
+			q := req.Queries[0]
+			return q.Get("rawSql").MustString() == "SELECT region FROM regions" && q.GetPath("datasource", "uid").MustString() == "ds2"
+		}))
+	})
+
+	t.Run("returns an error when the query of the variable fails", func(t *testing.T) {
+		fakeQueryService := &query.FakeQueryService{}
+		fakeQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{"variable-query": backend.DataResponse{Error: assert.AnError}},
+		}, nil)
+		pd := &PublicDashboardServiceImpl{QueryDataService: fakeQueryService}
+
+		_, err := pd.resolveVariables(context.Background(), newDashboardWithTemplateVariables(t), []PublicDashboardVariable{
+			{Name: "region", Source: VariableSourceDashboard},
+		})
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
+
+	t.Run("keeps static values", func(t *testing.T) {
+		pd, _ := newVariablesService(t)
+		variables, err := pd.resolveVariables(context.Background(), newDashboardWithTemplateVariables(t), []PublicDashboardVariable{
+			{Name: "host", Source: VariableSourceStatic, Values: []string{"c", "c", "d"}},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, PublicDashboardVariables{{Name: "host", Source: VariableSourceStatic, Values: []string{"c", "d"}}}, variables)
+	})
+
+	t.Run("returns an error when the variable is not in the dashboard", func(t *testing.T) {
+		pd, _ := newVariablesService(t)
+		_, err := pd.resolveVariables(context.Background(), newDashboardWithTemplateVariables(t), []PublicDashboardVariable{
+			{Name: "zone", Source: VariableSourceStatic, Values: []string{"eu"}},
+		})
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
+
+	t.Run("returns an error when the variable changes the data source", func(t *testing.T) {
+		pd, _ := newVariablesService(t)
+		_, err := pd.resolveVariables(context.Background(), newDashboardWithTemplateVariables(t), []PublicDashboardVariable{
+			{Name: "ds", Source: VariableSourceStatic, Values: []string{"ds2"}},
+		})
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
+}
+
+func TestSanitizeVariables(t *testing.T) {
+	dash := newDashboardWithTemplateVariables(t)
+	sanitizeVariables(dash.Data, PublicDashboardVariables{{Name: "host", Source: VariableSourceStatic, Values: []string{"b", "c,d"}}})
+
+	host := dash.Data.Get("templating").Get("list").GetIndex(1)
+	assert.Equal(t, "custom", host.Get("type").MustString())
+	assert.Equal(t, `b,c\,d`, host.Get("query").MustString())
+	assert.Equal(t, "b", host.GetPath("current", "value").MustString())
+	assert.Len(t, host.Get("options").MustArray(), 2)
+	_, hasDefinition := host.CheckGet("definition")
+	assert.False(t, hasDefinition)
+	_, hasDatasource := host.CheckGet("datasource")
+	assert.False(t, hasDatasource)
+
+	env := dash.Data.Get("templating").Get("list").GetIndex(0)
+	assert.Equal(t, "production,staging,dev\\,test", env.Get("query").MustString(), "variables viewers cannot change are unchanged")
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	dash := newDashboardWithTemplateVariables(t)
+	pubdash := &PublicDashboard{Variables: PublicDashboardVariables{
+		{Name: "env", Source: VariableSourceStatic, Values: []string{"production", "staging"}},
+		{Name: "host", Source: VariableSourceStatic, Values: []string{"b", "c"}},
+	}}
+
+	t.Run("uses the selected values, or the default values", func(t *testing.T) {
+		values := variableValues(dash, pubdash, map[string]string{"host": "c", "env": "not-allowed"})
+		assert.Equal(t, map[string]string{"env": "staging", "host": "c"}, values)
+	})
+
+	t.Run("replaces the variables in the query", func(t *testing.T) {
+		query := simplejson.NewFromAny(map[string]interface{}{
+			"refId":      "A",
+			"expr":       `up{env="$env", host="${host:regex}", other="$hostname"}`,
+			"rawSql":     "SELECT * FROM t WHERE env = '[[env]]'",
+			"datasource": map[string]interface{}{"uid": "$env"},
+			"filters":    []interface{}{map[string]interface{}{"value": "${env}"}},
+		})
+
+		interpolateVariables(query, map[string]string{"env": "staging", "host": "c"})
+
+		assert.Equal(t, `up{env="staging", host="c", other="$hostname"}`, query.Get("expr").MustString())
+		assert.Equal(t, "SELECT * FROM t WHERE env = 'staging'", query.Get("rawSql").MustString())
+		assert.Equal(t, "$env", query.GetPath("datasource", "uid").MustString())
+		assert.Equal(t, "staging", query.Get("filters").GetIndex(0).Get("value").MustString())
+	})
+}
@@ -13,6 +13,32 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	if dto.PublicDashboard.SecondsToLive != nil && *dto.PublicDashboard.SecondsToLive < 0 {
+		return ErrInvalidSecondsToLive.Errorf("ValidateSavePublicDashboard: secondsToLive should not be negative")
+	}
+
+	return validateVariables(dto.PublicDashboard.Variables)
+}
+
+// validateVariables checks the template variables viewers can change, the service checks they exist in the dashboard
+func validateVariables(variables []PublicDashboardVariable) error {
+	names := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		if v.Name == "" {
+			return ErrInvalidVariables.Errorf("ValidateSavePublicDashboard: variable name is empty")
+		}
+		if names[v.Name] {
+			return ErrInvalidVariables.Errorf("ValidateSavePublicDashboard: variable %s is set more than once", v.Name)
+		}
+		names[v.Name] = true
+
+		if !IsValidVariableSource(v.Source) {
+			return ErrInvalidVariables.Errorf("ValidateSavePublicDashboard: invalid source %q for variable %s", v.Source, v.Name)
+		}
+		if v.Source == VariableSourceStatic && len(v.Values) == 0 {
+			return ErrInvalidVariables.Errorf("ValidateSavePublicDashboard: variable %s has no allowed values", v.Name)
+		}
+	}
 	return nil
 }
 
@@ -25,6 +51,13 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *Public
 		return ErrInvalidMaxDataPoints.Errorf("ValidateQueryPublicDashboardRequest: maxDataPoints should be greater than 0")
 	}
 
+	for name, value := range req.Variables {
+		// values of the variables viewers cannot change are ignored
+		if v := pd.Variables.Get(name); v != nil && !v.IsAllowed(value) {
+			return ErrInvalidVariableValue.Errorf("ValidateQueryPublicDashboardRequest: value of variable %s is not allowed", name)
+		}
+	}
+
 	if pd.TimeSelectionEnabled {
 		timeRange := legacydata.NewDataTimeRange(req.TimeRange.From, req.TimeRange.To)
 
@@ -61,3 +94,12 @@ func IsValidShareType(shareType ShareType) bool {
 	}
 	return false
 }
+
+func IsValidVariableSource(source VariableSource) bool {
+	for _, s := range ValidVariableSources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
@@ -22,6 +22,32 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns error when secondsToLive is negative", func(t *testing.T) {
+		secondsToLive := int64(-1)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{Share: EmailShareType, SecondsToLive: &secondsToLive}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidSecondsToLive)
+	})
+
+	t.Run("Returns error when a variable has no allowed values", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{
+			Variables: []PublicDashboardVariable{{Name: "env", Source: VariableSourceStatic}},
+		}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
+
+	t.Run("Returns error when a variable has an invalid source", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{
+			Variables: []PublicDashboardVariable{{Name: "env", Source: "query"}},
+		}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidVariables)
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -129,6 +155,30 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Returns no error when variable value is allowed or variable cannot be changed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string]string{"env": "staging", "host": "any"},
+				},
+				pd: &PublicDashboard{
+					Variables: PublicDashboardVariables{{Name: "env", Source: VariableSourceStatic, Values: []string{"production", "staging"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Returns validation error when variable value is not allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string]string{"env": "staging\" OR 1=1"},
+				},
+				pd: &PublicDashboard{
+					Variables: PublicDashboardVariables{{Name: "env", Source: VariableSourceStatic, Values: []string{"production", "staging"}}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,123 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrReportNotFound        = errutil.NotFound("reports.not-found", errutil.WithPublicMessage("Report not found"))
+	ErrReportInvalid         = errutil.BadRequest("reports.invalid")
+	ErrDashboardAccessDenied = errutil.Forbidden("reports.dashboard-access-denied", errutil.WithPublicMessage("You cannot view the dashboard of the report"))
+	ErrRecipientsForbidden   = errutil.Forbidden("reports.recipients-forbidden", errutil.WithPublicMessage("Only the creator of the report and the users who can edit it can send it to other recipients"))
+	ErrRenderingUnavailable  = errutil.NotImplemented("reports.rendering-unavailable", errutil.WithPublicMessage("Reports need the image renderer, install the grafana-image-renderer plugin or configure a remote rendering service"))
+)
+
+// Orientation is the page orientation of the PDF.
+type Orientation string
+
+const (
+	OrientationLandscape Orientation = "landscape"
+	OrientationPortrait  Orientation = "portrait"
+)
+
+// Report is a report definition.
+type Report struct {
+	ID           int64  `xorm:"pk autoincr 'id'" json:"id"`
+	OrgID        int64  `xorm:"org_id" json:"orgId"`
+	UserID       int64  `xorm:"user_id" json:"userId"`
+	Name         string `xorm:"name" json:"name"`
+	DashboardUID string `xorm:"dashboard_uid" json:"dashboardUid"`
+	// PanelIDs are the panels rendered in the PDF, one per page. The whole dashboard is rendered when empty.
+	PanelIDs []int64 `xorm:"jsonb panel_ids" json:"panelIds"`
+	From     string  `xorm:"time_from" json:"from"`
+	To       string  `xorm:"time_to" json:"to"`
+	// Variables are the values of the template variables, the dashboard defaults are used for the others.
+	Variables   map[string][]string `xorm:"jsonb variables" json:"variables"`
+	Recipients  []string            `xorm:"jsonb recipients" json:"recipients"`
+	ReplyTo     string              `xorm:"reply_to" json:"replyTo"`
+	Message     string              `xorm:"message" json:"message"`
+	Orientation Orientation         `xorm:"orientation" json:"orientation"`
+	// Schedule is a cron expression, evaluated in the timezone of the report.
+	Schedule  string     `xorm:"schedule" json:"schedule"`
+	Timezone  string     `xorm:"timezone" json:"timezone"`
+	Enabled   bool       `xorm:"enabled" json:"enabled"`
+	NextRunAt *time.Time `xorm:"next_run_at" json:"nextRunAt,omitempty"`
+	LastRunAt *time.Time `xorm:"last_run_at" json:"lastRunAt,omitempty"`
+	// LastError is the reason why the last run failed, empty when it succeeded.
+	LastError string    `xorm:"last_error" json:"lastError,omitempty"`
+	Created   time.Time `xorm:"created" json:"created"`
+	Updated   time.Time `xorm:"updated" json:"updated"`
+}
+
+func (r Report) TableName() string {
+	return "report"
+}
+
+// ReportSpec holds the settings of a report that can be changed by its users.
+type ReportSpec struct {
+	Name         string              `json:"name" binding:"Required"`
+	DashboardUID string              `json:"dashboardUid" binding:"Required"`
+	PanelIDs     []int64             `json:"panelIds"`
+	From         string              `json:"from"`
+	To           string              `json:"to"`
+	Variables    map[string][]string `json:"variables"`
+	Recipients   []string            `json:"recipients"`
+	ReplyTo      string              `json:"replyTo"`
+	Message      string              `json:"message"`
+	Orientation  Orientation         `json:"orientation"`
+	Schedule     string              `json:"schedule" binding:"Required"`
+	Timezone     string              `json:"timezone"`
+	Enabled      bool                `json:"enabled"`
+}
+
+type CreateReportCommand struct {
+	ReportSpec
+	User *user.SignedInUser `json:"-"`
+}
+
+type UpdateReportCommand struct {
+	ReportSpec
+	ID   int64              `json:"-"`
+	User *user.SignedInUser `json:"-"`
+}
+
+type DeleteReportCommand struct {
+	ID    int64
+	OrgID int64
+}
+
+type GetReportQuery struct {
+	ID    int64
+	OrgID int64
+}
+
+type SearchReportsQuery struct {
+	OrgID        int64
+	DashboardUID string
+}
+
+type SendReportCommand struct {
+	ID    int64
+	OrgID int64
+	// Recipients replace the recipients of the report when set, e.g. to check a report before scheduling it.
+	Recipients []string
+	// User is the user who sends the report, who must be able to view its dashboard.
+	User *user.SignedInUser
+}
+
+// GetDueReportsQuery returns the enabled reports whose next run is before Now.
+type GetDueReportsQuery struct {
+	Now   time.Time
+	Limit int
+}
+
+// UpdateReportRunCommand records the result of a run and when the report runs next.
+type UpdateReportRunCommand struct {
+	ID        int64
+	LastRunAt time.Time
+	LastError string
+	NextRunAt *time.Time
+}
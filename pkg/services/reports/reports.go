@@ -0,0 +1,17 @@
+package reports
+
+import (
+	"context"
+)
+
+// Service manages report definitions. A report renders a dashboard, or some of its panels,
+// into a PDF on a schedule and emails it to its recipients.
+type Service interface {
+	Create(ctx context.Context, cmd *CreateReportCommand) (*Report, error)
+	Update(ctx context.Context, cmd *UpdateReportCommand) (*Report, error)
+	Delete(ctx context.Context, cmd *DeleteReportCommand) error
+	Get(ctx context.Context, query *GetReportQuery) (*Report, error)
+	Search(ctx context.Context, query *SearchReportsQuery) ([]*Report, error)
+	// Send renders and emails the report right away, without changing its schedule.
+	Send(ctx context.Context, cmd *SendReportCommand) error
+}
@@ -0,0 +1,265 @@
+package reportsimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/reports", func(subrouter routing.RouteRegister) {
+		subrouter.Get("/", authorize(ac.EvalPermission(ActionRead)), routing.Wrap(s.handleSearch))
+		subrouter.Post("/", authorize(ac.EvalPermission(ActionCreate)), routing.Wrap(s.handleCreate))
+		subrouter.Get("/:id", authorize(ac.EvalPermission(ActionRead, ScopeID)), routing.Wrap(s.handleGet))
+		subrouter.Put("/:id", authorize(ac.EvalPermission(ActionWrite, ScopeID)), routing.Wrap(s.handleUpdate))
+		subrouter.Delete("/:id", authorize(ac.EvalPermission(ActionDelete, ScopeID)), routing.Wrap(s.handleDelete))
+		subrouter.Post("/:id/send", authorize(ac.EvalPermission(ActionSend, ScopeID)), routing.Wrap(s.handleSend))
+	})
+}
+
+// swagger:route GET /reports reports searchReports
+//
+// Get reports.
+//
+// Returns the reports of the organization the user can read, optionally only the reports of a dashboard.
+//
+// Responses:
+// 200: searchReportsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSearch(c *contextmodel.ReqContext) response.Response {
+	result, err := s.Search(c.Req.Context(), &reports.SearchReportsQuery{
+		OrgID:        c.SignedInUser.OrgID,
+		DashboardUID: c.Query("dashboardUid"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get reports", err)
+	}
+
+	filtered := make([]*reports.Report, 0, len(result))
+	for _, report := range result {
+		ok, err := s.accessControl.Evaluate(c.Req.Context(), c.SignedInUser, ac.EvalPermission(ActionRead, ScopeProvider.GetResourceScope(strconv.FormatInt(report.ID, 10))))
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+		}
+		if ok {
+			filtered = append(filtered, report)
+		}
+	}
+
+	return response.JSON(http.StatusOK, filtered)
+}
+
+// swagger:route POST /reports reports createReport
+//
+// Create a report.
+//
+// The report is rendered with the permissions of the user who creates it, who must be able to view the dashboard.
+//
+// Responses:
+// 200: reportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleCreate(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.CreateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.User = c.SignedInUser
+
+	report, err := s.Create(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create report", err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route GET /reports/{id} reports getReport
+//
+// Get a report.
+//
+// Responses:
+// 200: reportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleGet(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	report, err := s.Get(c.Req.Context(), &reports.GetReportQuery{ID: id, OrgID: c.SignedInUser.OrgID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route PUT /reports/{id} reports updateReport
+//
+// Update a report.
+//
+// The user must be able to view the dashboard of the report.
+//
+// Responses:
+// 200: reportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleUpdate(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	cmd := reports.UpdateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.ID = id
+	cmd.User = c.SignedInUser
+
+	report, err := s.Update(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update report", err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route DELETE /reports/{id} reports deleteReport
+//
+// Delete a report.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleDelete(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := s.Delete(c.Req.Context(), &reports.DeleteReportCommand{ID: id, OrgID: c.SignedInUser.OrgID}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete report", err)
+	}
+
+	return response.Success("Report deleted")
+}
+
+// swagger:route POST /reports/{id}/send reports sendReport
+//
+// Send a report.
+//
+// Renders the report and emails it right away, to its recipients or to the recipients of the request.
+// The schedule of the report is not changed. The user must be able to view the dashboard of the report, and
+// to edit the report, or be its creator, to set the recipients.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleSend(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	body := SendReportBody{}
+	if c.Req.ContentLength > 0 {
+		if err := web.Bind(c.Req, &body); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	}
+
+	err = s.Send(c.Req.Context(), &reports.SendReportCommand{ID: id, OrgID: c.SignedInUser.OrgID, Recipients: body.Recipients, User: c.SignedInUser})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to send report", err)
+	}
+
+	return response.Success("Report sent")
+}
+
+// SendReportBody optionally replaces the recipients of the report.
+type SendReportBody struct {
+	Recipients []string `json:"recipients"`
+}
+
+// swagger:parameters searchReports
+type SearchReportsParams struct {
+	// Only return the reports of this dashboard
+	// in:query
+	// required:false
+	DashboardUID string `json:"dashboardUid"`
+}
+
+// swagger:parameters createReport
+type CreateReportParams struct {
+	// in:body
+	// required:true
+	Body reports.ReportSpec `json:"body"`
+}
+
+// swagger:parameters getReport deleteReport
+type ReportIDParams struct {
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+}
+
+// swagger:parameters updateReport
+type UpdateReportParams struct {
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+	// in:body
+	// required:true
+	Body reports.ReportSpec `json:"body"`
+}
+
+// swagger:parameters sendReport
+type SendReportParams struct {
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+	// in:body
+	// required:false
+	Body SendReportBody `json:"body"`
+}
+
+// swagger:response searchReportsResponse
+type SearchReportsResponse struct {
+	// in: body
+	Body []*reports.Report `json:"body"`
+}
+
+// swagger:response reportResponse
+type ReportResponse struct {
+	// in: body
+	Body *reports.Report `json:"body"`
+}
@@ -0,0 +1,73 @@
+package reportsimpl
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	ActionRead   = "reports:read"
+	ActionCreate = "reports:create"
+	ActionWrite  = "reports:write"
+	ActionDelete = "reports:delete"
+	ActionSend   = "reports:send"
+)
+
+var (
+	ScopeProvider = accesscontrol.NewScopeProvider("reports")
+	ScopeAll      = ScopeProvider.GetResourceAllScope()
+	ScopeID       = ScopeProvider.GetResourceScope(accesscontrol.Parameter(":id"))
+)
+
+const (
+	// schedulerInterval is how often the due reports are looked up, the cron schedules of reports
+	// cannot be more precise than a minute anyway.
+	schedulerInterval = time.Minute
+	// sendLockTimeout is how long a report is locked by the instance sending it. It must be longer
+	// than rendering and emailing the report, otherwise another instance could send it again.
+	sendLockTimeout = 30 * time.Minute
+	dueReportsLimit = 100
+
+	defaultFrom = "now-6h"
+	defaultTo   = "now"
+
+	panelWidth      = 1000
+	panelHeight     = 500
+	dashboardWidth  = 1500
+	emailTemplate   = "report"
+	maxReportPanels = 50
+)
+
+var readerRole = accesscontrol.RoleDTO{
+	Name:        "fixed:reports:reader",
+	DisplayName: "Report reader",
+	Description: "Read all reports and send them by email.",
+	Group:       "Reports",
+	Permissions: []accesscontrol.Permission{
+		{Action: ActionRead, Scope: ScopeAll},
+		{Action: ActionSend, Scope: ScopeAll},
+	},
+}
+
+var writerRole = accesscontrol.RoleDTO{
+	Name:        "fixed:reports:writer",
+	DisplayName: "Report writer",
+	Description: "Create, read, update and delete all reports and send them by email.",
+	Group:       "Reports",
+	Permissions: []accesscontrol.Permission{
+		{Action: ActionCreate},
+		{Action: ActionRead, Scope: ScopeAll},
+		{Action: ActionWrite, Scope: ScopeAll},
+		{Action: ActionDelete, Scope: ScopeAll},
+		{Action: ActionSend, Scope: ScopeAll},
+	},
+}
+
+func (s *Service) declareFixedRoles(ac accesscontrol.Service) error {
+	return ac.DeclareFixedRoles(
+		accesscontrol.RoleRegistration{Role: readerRole, Grants: []string{string(org.RoleAdmin)}},
+		accesscontrol.RoleRegistration{Role: writerRole, Grants: []string{string(org.RoleAdmin)}},
+	)
+}
@@ -0,0 +1,134 @@
+package reportsimpl
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+const (
+	pageMargin   = 10.0
+	headerHeight = 16.0
+	footerHeight = 6.0
+)
+
+// document is the content of the PDF of a report.
+type document struct {
+	Title       string
+	Subtitle    string
+	Orientation reports.Orientation
+	// Images are PNG images, each one starts on a new page.
+	Images [][]byte
+}
+
+// composePDF puts the images of the document on A4 pages below a header with the title of the report.
+// The images are scaled to the width of the page, the images taller than a page are split across pages.
+func composePDF(doc document) ([]byte, error) {
+	orientation := "L"
+	if doc.Orientation == reports.OrientationPortrait {
+		orientation = "P"
+	}
+
+	pdf := gofpdf.New(orientation, "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(false, pageMargin)
+	// the core fonts only support cp1252, other characters are replaced
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	contentWidth := pageWidth - 2*pageMargin
+	contentHeight := pageHeight - 2*pageMargin - headerHeight - footerHeight
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin - footerHeight/2)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, footerHeight/2, fmt.Sprintf("%d / {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+
+	var pages [][]byte
+	for _, img := range doc.Images {
+		split, err := splitImage(img, contentHeight/contentWidth)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, split...)
+	}
+
+	for i, img := range pages {
+		pdf.AddPage()
+
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(0, 8, tr(doc.Title), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(0, 6, tr(doc.Subtitle), "", 1, "L", false, 0, "")
+
+		name := fmt.Sprintf("page-%d", i)
+		opts := gofpdf.ImageOptions{ImageType: "PNG"}
+		info := pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(img))
+		if pdf.Err() {
+			return nil, fmt.Errorf("failed to add image to report: %w", pdf.Error())
+		}
+
+		width, height := info.Width(), info.Height()
+		scale := contentWidth / width
+		if height*scale > contentHeight {
+			scale = contentHeight / height
+		}
+		pdf.ImageOptions(name, pageMargin, pageMargin+headerHeight, width*scale, height*scale, false, opts, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// splitImage splits a PNG image in images whose height is at most ratio times their width.
+func splitImage(img []byte, ratio float64) ([][]byte, error) {
+	cfg, err := png.DecodeConfig(bytes.NewReader(img))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rendered image: %w", err)
+	}
+
+	maxHeight := int(float64(cfg.Width) * ratio)
+	if maxHeight <= 0 || cfg.Height <= maxHeight {
+		return [][]byte{img}, nil
+	}
+
+	decoded, err := png.Decode(bytes.NewReader(img))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rendered image: %w", err)
+	}
+	sub, ok := decoded.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return [][]byte{img}, nil
+	}
+
+	bounds := decoded.Bounds()
+	var parts [][]byte
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += maxHeight {
+		bottom := top + maxHeight
+		if bottom > bounds.Max.Y {
+			bottom = bounds.Max.Y
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, sub.SubImage(image.Rect(bounds.Min.X, top, bounds.Max.X, bottom))); err != nil {
+			return nil, err
+		}
+		parts = append(parts, buf.Bytes())
+	}
+	return parts, nil
+}
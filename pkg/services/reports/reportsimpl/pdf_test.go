@@ -0,0 +1,76 @@
+package reportsimpl
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestSplitImage(t *testing.T) {
+	t.Run("keeps an image that fits on a page", func(t *testing.T) {
+		img := pngImage(t, 100, 50)
+		parts, err := splitImage(img, 0.5)
+		require.NoError(t, err)
+		require.Len(t, parts, 1)
+		assert.Equal(t, img, parts[0])
+	})
+
+	t.Run("splits a tall image", func(t *testing.T) {
+		parts, err := splitImage(pngImage(t, 100, 120), 0.5)
+		require.NoError(t, err)
+		require.Len(t, parts, 3)
+
+		heights := make([]int, 0, len(parts))
+		for _, part := range parts {
+			cfg, err := png.DecodeConfig(bytes.NewReader(part))
+			require.NoError(t, err)
+			assert.Equal(t, 100, cfg.Width)
+			heights = append(heights, cfg.Height)
+		}
+		assert.Equal(t, []int{50, 50, 20}, heights)
+	})
+
+	t.Run("fails on an invalid image", func(t *testing.T) {
+		_, err := splitImage([]byte("not an image"), 0.5)
+		require.Error(t, err)
+	})
+}
+
+func TestComposePDF(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		orientation reports.Orientation
+		images      [][]byte
+		pages       int
+	}{
+		{desc: "one page per panel", orientation: reports.OrientationLandscape, images: [][]byte{pngImage(t, 1000, 500), pngImage(t, 1000, 500)}, pages: 2},
+		{desc: "a tall dashboard spans pages", orientation: reports.OrientationLandscape, images: [][]byte{pngImage(t, 1500, 3000)}, pages: 4},
+		{desc: "portrait pages fit more of a dashboard", orientation: reports.OrientationPortrait, images: [][]byte{pngImage(t, 1500, 3000)}, pages: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			pdf, err := composePDF(document{
+				Title:       "Daily overview",
+				Subtitle:    "Service overview | last 24 hours",
+				Orientation: tc.orientation,
+				Images:      tc.images,
+			})
+			require.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+			assert.Equal(t, tc.pages, bytes.Count(pdf, []byte("<</Type /Page\n")))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package reportsimpl
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+// nextRun returns when the report runs after t, or nil when the report is disabled.
+func nextRun(report *reports.Report, t time.Time) (*time.Time, error) {
+	if !report.Enabled {
+		return nil, nil
+	}
+
+	schedule, err := cron.ParseStandard(report.Schedule)
+	if err != nil {
+		return nil, reports.ErrReportInvalid.Errorf("invalid schedule %q: %w", report.Schedule, err)
+	}
+	loc, err := location(report.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	next := schedule.Next(t.In(loc))
+	if next.IsZero() {
+		// the schedule never matches, e.g. on the 30th of February
+		return nil, reports.ErrReportInvalid.Errorf("schedule %q never runs", report.Schedule)
+	}
+	next = next.UTC()
+	return &next, nil
+}
+
+func location(timezone string) (*time.Location, error) {
+	if timezone == "" || timezone == "browser" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, reports.ErrReportInvalid.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,81 @@
+package reportsimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+// IsDisabled returns true when reporting is disabled, the reports are not scheduled then.
+func (s *Service) IsDisabled() bool {
+	return !s.enabled
+}
+
+// Run sends the reports when they are due.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sendDueReports(ctx, time.Now())
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) sendDueReports(ctx context.Context, now time.Time) {
+	due, err := s.store.GetDue(ctx, &reports.GetDueReportsQuery{Now: now, Limit: dueReportsLimit})
+	if err != nil {
+		s.log.Error("Failed to get the due reports", "error", err)
+		return
+	}
+
+	for _, report := range due {
+		// every instance looks for the due reports, the lock makes sure only one of them sends each report
+		err := s.lock.LockExecuteAndRelease(ctx, fmt.Sprintf("send report %d", report.ID), sendLockTimeout, func(ctx context.Context) {
+			s.sendScheduledReport(ctx, report.ID, now)
+		})
+		var lockExists *serverlock.ServerLockExistsError
+		if err != nil && !errors.As(err, &lockExists) {
+			s.log.Error("Failed to lock report", "id", report.ID, "error", err)
+		}
+	}
+}
+
+func (s *Service) sendScheduledReport(ctx context.Context, id int64, now time.Time) {
+	// the report is loaded again once locked, another instance might have sent it in the meantime
+	report, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, reports.ErrReportNotFound) {
+			s.log.Error("Failed to get report", "id", id, "error", err)
+		}
+		return
+	}
+	if !report.Enabled || report.NextRunAt == nil || report.NextRunAt.After(now) {
+		return
+	}
+
+	cmd := &reports.UpdateReportRunCommand{ID: report.ID, LastRunAt: now}
+	if err := s.send(ctx, report); err != nil {
+		s.log.Warn("Failed to send report", "id", report.ID, "error", err)
+		cmd.LastError = err.Error()
+	} else {
+		s.log.Debug("Report sent", "id", report.ID, "recipients", len(report.Recipients))
+	}
+
+	// the runs missed while Grafana was down are skipped
+	if cmd.NextRunAt, err = nextRun(report, now); err != nil {
+		s.log.Error("Failed to schedule report, it will not be sent again", "id", report.ID, "error", err)
+	}
+
+	if err := s.store.UpdateRun(ctx, cmd); err != nil {
+		s.log.Error("Failed to update report run", "id", report.ID, "error", err)
+	}
+}
@@ -0,0 +1,182 @@
+package reportsimpl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+// send renders the report with the permissions of the user who created it and emails the PDF to its recipients.
+func (s *Service) send(ctx context.Context, report *reports.Report) error {
+	if !s.renderService.IsAvailable(ctx) {
+		return reports.ErrRenderingUnavailable.Errorf("image renderer is not available")
+	}
+
+	creator, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: report.UserID, OrgID: report.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to get the creator of the report: %w", err)
+	}
+
+	// the creator might have lost access to the dashboard since the report was created
+	dash, err := s.viewableDashboard(ctx, report.DashboardUID, creator)
+	if err != nil {
+		return fmt.Errorf("failed to get the dashboard of the report: %w", err)
+	}
+
+	loc, err := location(report.Timezone)
+	if err != nil {
+		return err
+	}
+	timeRange := legacydata.NewDataTimeRange(report.From, report.To)
+	from, err := timeRange.ParseFrom()
+	if err != nil {
+		return err
+	}
+	to, err := timeRange.ParseTo()
+	if err != nil {
+		return err
+	}
+	// the time range is resolved once, so that all the panels show the same data even if rendering takes time
+	from, to = from.In(loc), to.In(loc)
+
+	images, err := s.render(ctx, report, dash, creator, from, to)
+	if err != nil {
+		return err
+	}
+
+	formattedRange := fmt.Sprintf("%s to %s (%s)", from.Format("2006-01-02 15:04"), to.Format("2006-01-02 15:04"), loc.String())
+	pdf, err := composePDF(document{
+		Title:       report.Name,
+		Subtitle:    fmt.Sprintf("%s | %s", dash.Title, formattedRange),
+		Orientation: report.Orientation,
+		Images:      images,
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd := &notifications.SendEmailCommandSync{
+		SendEmailCommand: notifications.SendEmailCommand{
+			To:       report.Recipients,
+			Template: emailTemplate,
+			Data: map[string]interface{}{
+				"Name":           report.Name,
+				"Message":        report.Message,
+				"DashboardTitle": dash.Title,
+				"DashboardUrl":   dashboards.GetFullDashboardURL(dash.UID, dash.Slug) + "?" + dashboardParams(report, from, to).Encode(),
+				"TimeRange":      formattedRange,
+			},
+			AttachedFiles: []*notifications.SendEmailAttachFile{
+				{Name: attachmentName(report.Name), Content: pdf},
+			},
+		},
+	}
+	if report.ReplyTo != "" {
+		cmd.ReplyTo = []string{report.ReplyTo}
+	}
+
+	return s.emailSender.SendEmailCommandHandlerSync(ctx, cmd)
+}
+
+// render returns the images of the panels of the report, or of the whole dashboard when the report has no panels.
+func (s *Service) render(ctx context.Context, report *reports.Report, dash *dashboards.Dashboard, creator *user.SignedInUser, from, to time.Time) ([][]byte, error) {
+	opts := rendering.Opts{
+		TimeoutOpts: rendering.TimeoutOpts{Timeout: s.renderingTimeout},
+		AuthOpts: rendering.AuthOpts{
+			OrgID:   creator.OrgID,
+			UserID:  creator.UserID,
+			OrgRole: creator.OrgRole,
+		},
+		ErrorOpts: rendering.ErrorOpts{
+			ErrorConcurrentLimitReached: true,
+			ErrorRenderUnavailable:      true,
+		},
+		Timezone:          from.Location().String(),
+		ConcurrentLimit:   s.cfg.RendererConcurrentRequestLimit,
+		DeviceScaleFactor: 1,
+		Theme:             models.ThemeLight,
+	}
+
+	params := dashboardParams(report, from, to)
+	if len(report.PanelIDs) == 0 {
+		params.Set("kiosk", "1")
+		opts.Path = path.Join("d", dash.UID, dash.Slug) + "?" + params.Encode()
+		opts.Width = dashboardWidth
+		// the renderer takes a screenshot of the whole page when the height is -1
+		opts.Height = -1
+
+		img, err := s.renderImage(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{img}, nil
+	}
+
+	images := make([][]byte, 0, len(report.PanelIDs))
+	for _, id := range report.PanelIDs {
+		params.Set("panelId", strconv.FormatInt(id, 10))
+		opts.Path = path.Join("d-solo", dash.UID, dash.Slug) + "?" + params.Encode()
+		opts.Width = panelWidth
+		opts.Height = panelHeight
+
+		img, err := s.renderImage(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render panel %d: %w", id, err)
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+func (s *Service) renderImage(ctx context.Context, opts rendering.Opts) ([]byte, error) {
+	result, err := s.renderService.Render(ctx, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.Remove(result.FilePath); err != nil {
+			s.log.Warn("Failed to remove rendered report image", "path", result.FilePath, "error", err)
+		}
+	}()
+
+	// nolint:gosec
+	// the path is returned by the rendering service
+	return os.ReadFile(result.FilePath)
+}
+
+// dashboardParams returns the URL parameters of the dashboard for the time range and the variables of the report.
+func dashboardParams(report *reports.Report, from, to time.Time) url.Values {
+	params := url.Values{}
+	params.Set("orgId", strconv.FormatInt(report.OrgID, 10))
+	params.Set("from", strconv.FormatInt(from.UnixMilli(), 10))
+	params.Set("to", strconv.FormatInt(to.UnixMilli(), 10))
+	for name, values := range report.Variables {
+		for _, v := range values {
+			params.Add("var-"+name, v)
+		}
+	}
+	return params
+}
+
+func attachmentName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	return name + ".pdf"
+}
@@ -0,0 +1,283 @@
+package reportsimpl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var _ reports.Service = (*Service)(nil)
+
+type Service struct {
+	cfg              *setting.Cfg
+	store            store
+	lock             *serverlock.ServerLockService
+	renderService    rendering.Service
+	emailSender      notifications.EmailSender
+	dashboardService dashboards.DashboardService
+	userService      user.Service
+	accessControl    ac.AccessControl
+	log              log.Logger
+
+	enabled          bool
+	renderingTimeout time.Duration
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	lock *serverlock.ServerLockService,
+	renderService rendering.Service,
+	emailSender notifications.EmailSender,
+	dashboardService dashboards.DashboardService,
+	userService user.Service,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("reporting")
+	s := &Service{
+		cfg:              cfg,
+		store:            &xormStore{db: sql},
+		lock:             lock,
+		renderService:    renderService,
+		emailSender:      emailSender,
+		dashboardService: dashboardService,
+		userService:      userService,
+		accessControl:    accessControl,
+		log:              log.New("reports"),
+		// Grafana Enterprise has its own reporting, which replaces this one
+		enabled:          section.Key("enabled").MustBool(false) && !cfg.IsEnterprise,
+		renderingTimeout: section.Key("rendering_timeout").MustDuration(time.Minute),
+	}
+
+	if !s.enabled {
+		return s, nil
+	}
+
+	if err := s.declareFixedRoles(accesscontrolService); err != nil {
+		return nil, err
+	}
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) Create(ctx context.Context, cmd *reports.CreateReportCommand) (*reports.Report, error) {
+	now := time.Now()
+	report := &reports.Report{
+		OrgID:   cmd.User.OrgID,
+		UserID:  cmd.User.UserID,
+		Created: now,
+	}
+	if err := s.apply(ctx, report, cmd.ReportSpec, cmd.User, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Insert(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Update changes the settings of a report. The report is still rendered with the permissions of the user who created it.
+func (s *Service) Update(ctx context.Context, cmd *reports.UpdateReportCommand) (*reports.Report, error) {
+	report, err := s.store.Get(ctx, &reports.GetReportQuery{ID: cmd.ID, OrgID: cmd.User.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, report, cmd.ReportSpec, cmd.User, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Update(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Service) Delete(ctx context.Context, cmd *reports.DeleteReportCommand) error {
+	return s.store.Delete(ctx, cmd)
+}
+
+func (s *Service) Get(ctx context.Context, query *reports.GetReportQuery) (*reports.Report, error) {
+	return s.store.Get(ctx, query)
+}
+
+func (s *Service) Search(ctx context.Context, query *reports.SearchReportsQuery) ([]*reports.Report, error) {
+	return s.store.Search(ctx, query)
+}
+
+// Send renders and emails the report right away. The report is rendered with the permissions of its creator, the user
+// who sends it must be able to view the dashboard too, and to edit the report to send it to other recipients.
+func (s *Service) Send(ctx context.Context, cmd *reports.SendReportCommand) error {
+	report, err := s.store.Get(ctx, &reports.GetReportQuery{ID: cmd.ID, OrgID: cmd.OrgID})
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.viewableDashboard(ctx, report.DashboardUID, cmd.User); err != nil {
+		return err
+	}
+
+	if len(cmd.Recipients) > 0 {
+		if cmd.User.UserID != report.UserID {
+			canWrite, err := s.accessControl.Evaluate(ctx, cmd.User, ac.EvalPermission(ActionWrite, ScopeProvider.GetResourceScope(strconv.FormatInt(report.ID, 10))))
+			if err != nil {
+				return err
+			}
+			if !canWrite {
+				return reports.ErrRecipientsForbidden.Errorf("user %d cannot edit report %d", cmd.User.UserID, report.ID)
+			}
+		}
+		recipients, err := validateRecipients(cmd.Recipients)
+		if err != nil {
+			return err
+		}
+		report.Recipients = recipients
+	}
+
+	return s.send(ctx, report)
+}
+
+// apply validates the spec and sets it on the report, along with the next time the report runs.
+func (s *Service) apply(ctx context.Context, report *reports.Report, spec reports.ReportSpec, usr *user.SignedInUser, now time.Time) error {
+	name := strings.TrimSpace(spec.Name)
+	if name == "" {
+		return reports.ErrReportInvalid.Errorf("name is required")
+	}
+
+	dash, err := s.viewableDashboard(ctx, spec.DashboardUID, usr)
+	if err != nil {
+		return err
+	}
+	if len(spec.PanelIDs) > maxReportPanels {
+		return reports.ErrReportInvalid.Errorf("a report cannot have more than %d panels", maxReportPanels)
+	}
+	panels := dashboardPanelIDs(dash.Data)
+	for _, id := range spec.PanelIDs {
+		if !panels[id] {
+			return reports.ErrReportInvalid.Errorf("panel %d not found in dashboard %s", id, dash.UID)
+		}
+	}
+
+	from, to := spec.From, spec.To
+	if from == "" || to == "" {
+		from, to = defaultFrom, defaultTo
+	}
+	timeRange := legacydata.NewDataTimeRange(from, to)
+	if _, err := timeRange.ParseFrom(); err != nil {
+		return reports.ErrReportInvalid.Errorf("invalid time range from %q: %w", from, err)
+	}
+	if _, err := timeRange.ParseTo(); err != nil {
+		return reports.ErrReportInvalid.Errorf("invalid time range to %q: %w", to, err)
+	}
+
+	recipients, err := validateRecipients(spec.Recipients)
+	if err != nil {
+		return err
+	}
+	replyTo := strings.TrimSpace(spec.ReplyTo)
+	if replyTo != "" && !util.IsEmail(replyTo) {
+		return reports.ErrReportInvalid.Errorf("invalid reply to address %q", replyTo)
+	}
+
+	orientation := spec.Orientation
+	switch orientation {
+	case "":
+		orientation = reports.OrientationLandscape
+	case reports.OrientationLandscape, reports.OrientationPortrait:
+	default:
+		return reports.ErrReportInvalid.Errorf("invalid orientation %q, expected %s or %s", orientation, reports.OrientationLandscape, reports.OrientationPortrait)
+	}
+
+	if _, err := cron.ParseStandard(spec.Schedule); err != nil {
+		return reports.ErrReportInvalid.Errorf("invalid schedule %q: %w", spec.Schedule, err)
+	}
+	if _, err := location(spec.Timezone); err != nil {
+		return err
+	}
+
+	report.Name = name
+	report.DashboardUID = dash.UID
+	report.PanelIDs = spec.PanelIDs
+	report.From = from
+	report.To = to
+	report.Variables = spec.Variables
+	report.Recipients = recipients
+	report.ReplyTo = replyTo
+	report.Message = spec.Message
+	report.Orientation = orientation
+	report.Schedule = spec.Schedule
+	report.Timezone = spec.Timezone
+	report.Enabled = spec.Enabled
+	report.Updated = now
+
+	report.NextRunAt, err = nextRun(report, now)
+	return err
+}
+
+// viewableDashboard returns the dashboard if the user can view it.
+func (s *Service) viewableDashboard(ctx context.Context, uid string, usr *user.SignedInUser) (*dashboards.Dashboard, error) {
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: uid, OrgID: usr.OrgID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil, reports.ErrReportInvalid.Errorf("dashboard %s not found", uid)
+		}
+		return nil, err
+	}
+
+	g, err := guardian.NewByDashboard(ctx, dash, usr.OrgID, usr)
+	if err != nil {
+		return nil, err
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return nil, reports.ErrDashboardAccessDenied.Errorf("user %d cannot view dashboard %s", usr.UserID, uid)
+	}
+	return dash, nil
+}
+
+func validateRecipients(recipients []string) ([]string, error) {
+	result := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		r = strings.TrimSpace(r)
+		if !util.IsEmail(r) {
+			return nil, reports.ErrReportInvalid.Errorf("invalid recipient %q", r)
+		}
+		result = append(result, r)
+	}
+	if len(result) == 0 {
+		return nil, reports.ErrReportInvalid.Errorf("at least one recipient is required")
+	}
+	return result, nil
+}
+
+// dashboardPanelIDs returns the IDs of the panels of the dashboard, including the panels of collapsed rows.
+func dashboardPanelIDs(data *simplejson.Json) map[int64]bool {
+	ids := make(map[int64]bool)
+	for _, panel := range dashboards.Panels(data) {
+		ids[panel.Get("id").MustInt64()] = true
+	}
+	return ids
+}
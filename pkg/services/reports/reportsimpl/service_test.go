@@ -0,0 +1,358 @@
+package reportsimpl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const reportDashboard = `{
+  "title": "Service overview",
+  "panels": [
+    {"id": 1, "type": "timeseries"},
+    {"id": 2, "type": "row", "collapsed": true, "panels": [{"id": 3, "type": "stat"}]}
+  ]
+}`
+
+// fakeRenderer writes a small PNG image for each render request.
+type fakeRenderer struct {
+	rendering.Service
+	dir         string
+	unavailable bool
+
+	mu    sync.Mutex
+	paths []string
+}
+
+func (r *fakeRenderer) IsAvailable(context.Context) bool {
+	return !r.unavailable
+}
+
+func (r *fakeRenderer) Render(_ context.Context, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paths = append(r.paths, opts.Path)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 100, 50))); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(r.dir, "render.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+	return &rendering.RenderResult{FilePath: path}, nil
+}
+
+type testEnv struct {
+	service  *Service
+	renderer *fakeRenderer
+	emails   *notifications.NotificationServiceMock
+	sent     int
+	user     *user.SignedInUser
+}
+
+func setupTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	sqlStore := db.InitTestDB(t)
+	usr := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor}
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(func(_ context.Context, q *dashboards.GetDashboardQuery) *dashboards.Dashboard {
+		data, err := simplejson.NewJson([]byte(reportDashboard))
+		require.NoError(t, err)
+		return &dashboards.Dashboard{UID: q.UID, OrgID: q.OrgID, Slug: "service-overview", Title: "Service overview", Data: data}
+	}, nil).Maybe()
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedSignedInUser = usr
+
+	env := &testEnv{
+		renderer: &fakeRenderer{dir: t.TempDir()},
+		emails:   notifications.MockNotificationService(),
+		user:     usr,
+	}
+	env.emails.EmailHandlerSync = func(context.Context, *notifications.SendEmailCommandSync) error {
+		env.sent++
+		return nil
+	}
+	env.service = &Service{
+		cfg:              setting.NewCfg(),
+		store:            &xormStore{db: sqlStore},
+		accessControl:    actest.FakeAccessControl{},
+		lock:             serverlock.ProvideService(sqlStore, tracing.InitializeTracerForTest()),
+		renderService:    env.renderer,
+		emailSender:      env.emails,
+		dashboardService: dashboardService,
+		userService:      userService,
+		log:              log.NewNopLogger(),
+		enabled:          true,
+		renderingTimeout: time.Minute,
+	}
+	return env
+}
+
+func validSpec() reports.ReportSpec {
+	return reports.ReportSpec{
+		Name:         "Daily overview",
+		DashboardUID: "abc",
+		PanelIDs:     []int64{1, 3},
+		Variables:    map[string][]string{"env": {"prod"}},
+		Recipients:   []string{"ops@example.com", " dev@example.com "},
+		Schedule:     "0 8 * * *",
+		Timezone:     "Europe/Paris",
+		Enabled:      true,
+	}
+}
+
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates a report scheduled in its timezone", func(t *testing.T) {
+		env := setupTestEnv(t)
+
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), report.UserID)
+		assert.Equal(t, []string{"ops@example.com", "dev@example.com"}, report.Recipients)
+		assert.Equal(t, reports.OrientationLandscape, report.Orientation)
+		assert.Equal(t, defaultFrom, report.From)
+		require.NotNil(t, report.NextRunAt)
+
+		paris, err := time.LoadLocation("Europe/Paris")
+		require.NoError(t, err)
+		next := report.NextRunAt.In(paris)
+		assert.Equal(t, 8, next.Hour())
+		assert.Equal(t, 0, next.Minute())
+	})
+
+	t.Run("a disabled report is not scheduled", func(t *testing.T) {
+		env := setupTestEnv(t)
+		spec := validSpec()
+		spec.Enabled = false
+
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: spec, User: env.user})
+		require.NoError(t, err)
+		assert.Nil(t, report.NextRunAt)
+	})
+
+	t.Run("validates the report", func(t *testing.T) {
+		env := setupTestEnv(t)
+
+		testCases := map[string]func(spec *reports.ReportSpec){
+			"no name":            func(spec *reports.ReportSpec) { spec.Name = " " },
+			"no recipients":      func(spec *reports.ReportSpec) { spec.Recipients = nil },
+			"invalid recipient":  func(spec *reports.ReportSpec) { spec.Recipients = []string{"ops"} },
+			"invalid reply to":   func(spec *reports.ReportSpec) { spec.ReplyTo = "ops" },
+			"unknown panel":      func(spec *reports.ReportSpec) { spec.PanelIDs = []int64{4} },
+			"invalid schedule":   func(spec *reports.ReportSpec) { spec.Schedule = "every day" },
+			"invalid timezone":   func(spec *reports.ReportSpec) { spec.Timezone = "Mars/Olympus" },
+			"invalid time range": func(spec *reports.ReportSpec) { spec.From, spec.To = "yesterday", "now" },
+			"invalid orientation": func(spec *reports.ReportSpec) {
+				spec.Orientation = "diagonal"
+			},
+		}
+		for name, change := range testCases {
+			t.Run(name, func(t *testing.T) {
+				spec := validSpec()
+				change(&spec)
+				_, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: spec, User: env.user})
+				require.ErrorIs(t, err, reports.ErrReportInvalid)
+			})
+		}
+	})
+
+	t.Run("requires the user to view the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: false})
+
+		_, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.ErrorIs(t, err, reports.ErrDashboardAccessDenied)
+	})
+}
+
+func TestService_Send(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("renders the panels and emails the PDF", func(t *testing.T) {
+		env := setupTestEnv(t)
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.NoError(t, err)
+
+		require.NoError(t, env.service.Send(ctx, &reports.SendReportCommand{ID: report.ID, OrgID: 1, User: env.user}))
+
+		require.Len(t, env.renderer.paths, 2)
+		assert.Contains(t, env.renderer.paths[0], "d-solo/abc/service-overview?")
+		assert.Contains(t, env.renderer.paths[0], "panelId=1")
+		assert.Contains(t, env.renderer.paths[0], "var-env=prod")
+		assert.Contains(t, env.renderer.paths[1], "panelId=3")
+
+		email := env.emails.EmailSync
+		assert.Equal(t, []string{"ops@example.com", "dev@example.com"}, email.To)
+		assert.Equal(t, emailTemplate, email.Template)
+		assert.Equal(t, "Service overview", email.Data["DashboardTitle"])
+		require.Len(t, email.AttachedFiles, 1)
+		assert.Equal(t, "Daily overview.pdf", email.AttachedFiles[0].Name)
+		assert.True(t, bytes.HasPrefix(email.AttachedFiles[0].Content, []byte("%PDF")))
+	})
+
+	t.Run("renders the whole dashboard when the report has no panels", func(t *testing.T) {
+		env := setupTestEnv(t)
+		spec := validSpec()
+		spec.PanelIDs = nil
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: spec, User: env.user})
+		require.NoError(t, err)
+
+		require.NoError(t, env.service.Send(ctx, &reports.SendReportCommand{ID: report.ID, OrgID: 1, Recipients: []string{"me@example.com"}, User: env.user}))
+
+		require.Len(t, env.renderer.paths, 1)
+		assert.Contains(t, env.renderer.paths[0], "d/abc/service-overview?")
+		assert.Contains(t, env.renderer.paths[0], "kiosk=1")
+		assert.Equal(t, []string{"me@example.com"}, env.emails.EmailSync.To)
+	})
+
+	t.Run("requires the user and the creator to view the dashboard", func(t *testing.T) {
+		env := setupTestEnv(t)
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.NoError(t, err)
+		other := &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleViewer}
+
+		mockDashboardViewers(1)
+		err = env.service.Send(ctx, &reports.SendReportCommand{ID: report.ID, OrgID: 1, User: other})
+		require.ErrorIs(t, err, reports.ErrDashboardAccessDenied)
+
+		mockDashboardViewers(2)
+		err = env.service.Send(ctx, &reports.SendReportCommand{ID: report.ID, OrgID: 1, User: other})
+		require.ErrorIs(t, err, reports.ErrDashboardAccessDenied, "the report is rendered with the permissions of its creator")
+		assert.Empty(t, env.renderer.paths)
+		assert.Zero(t, env.sent)
+	})
+
+	t.Run("requires the user to edit the report to change the recipients", func(t *testing.T) {
+		env := setupTestEnv(t)
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.NoError(t, err)
+		other := &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleViewer}
+
+		err = env.service.Send(ctx, &reports.SendReportCommand{ID: report.ID, OrgID: 1, Recipients: []string{"me@example.com"}, User: other})
+		require.ErrorIs(t, err, reports.ErrRecipientsForbidden)
+		assert.Zero(t, env.sent)
+
+		require.NoError(t, env.service.Send(ctx, &reports.SendReportCommand{ID: report.ID, OrgID: 1, User: other}))
+		assert.Equal(t, []string{"ops@example.com", "dev@example.com"}, env.emails.EmailSync.To)
+
+		env.service.accessControl = actest.FakeAccessControl{ExpectedEvaluate: true}
+		require.NoError(t, env.service.Send(ctx, &reports.SendReportCommand{ID: report.ID, OrgID: 1, Recipients: []string{"me@example.com"}, User: other}))
+		assert.Equal(t, []string{"me@example.com"}, env.emails.EmailSync.To)
+	})
+
+	t.Run("fails when the renderer is not available", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.renderer.unavailable = true
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.NoError(t, err)
+
+		err = env.service.Send(ctx, &reports.SendReportCommand{ID: report.ID, OrgID: 1, User: env.user})
+		require.ErrorIs(t, err, reports.ErrRenderingUnavailable)
+		assert.Zero(t, env.sent)
+	})
+}
+
+// mockDashboardViewers only lets the given users view the dashboards.
+func mockDashboardViewers(userIDs ...int64) {
+	guardian.NewByDashboard = func(_ context.Context, _ *dashboards.Dashboard, _ int64, usr *user.SignedInUser) (guardian.DashboardGuardian, error) {
+		canView := false
+		for _, id := range userIDs {
+			canView = canView || usr.UserID == id
+		}
+		return &guardian.FakeDashboardGuardian{CanViewValue: canView}, nil
+	}
+}
+
+func TestService_sendDueReports(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends the due reports once and schedules their next run", func(t *testing.T) {
+		env := setupTestEnv(t)
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.NoError(t, err)
+
+		env.service.sendDueReports(ctx, report.NextRunAt.Add(-time.Minute))
+		assert.Zero(t, env.sent, "the report is not due yet")
+
+		now := report.NextRunAt.Add(time.Minute)
+		env.service.sendDueReports(ctx, now)
+		env.service.sendDueReports(ctx, now)
+		assert.Equal(t, 1, env.sent)
+
+		sent, err := env.service.Get(ctx, &reports.GetReportQuery{ID: report.ID, OrgID: 1})
+		require.NoError(t, err)
+		require.NotNil(t, sent.LastRunAt)
+		assert.Empty(t, sent.LastError)
+		require.NotNil(t, sent.NextRunAt)
+		assert.Equal(t, 24*time.Hour, sent.NextRunAt.Sub(*report.NextRunAt))
+	})
+
+	t.Run("records the error of a failed run and schedules the next run", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.emails.EmailHandlerSync = func(context.Context, *notifications.SendEmailCommandSync) error {
+			return errors.New("smtp unavailable")
+		}
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.NoError(t, err)
+
+		env.service.sendDueReports(ctx, report.NextRunAt.Add(time.Minute))
+
+		failed, err := env.service.Get(ctx, &reports.GetReportQuery{ID: report.ID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "smtp unavailable", failed.LastError)
+		require.NotNil(t, failed.NextRunAt)
+		assert.True(t, failed.NextRunAt.After(*report.NextRunAt))
+	})
+
+	t.Run("does not send a report locked by another instance", func(t *testing.T) {
+		env := setupTestEnv(t)
+		report, err := env.service.Create(ctx, &reports.CreateReportCommand{ReportSpec: validSpec(), User: env.user})
+		require.NoError(t, err)
+
+		now := report.NextRunAt.Add(time.Minute)
+		err = env.service.lock.LockExecuteAndRelease(ctx, "send report 1", sendLockTimeout, func(ctx context.Context) {
+			env.service.sendDueReports(ctx, now)
+		})
+		require.NoError(t, err)
+		assert.Zero(t, env.sent)
+
+		env.service.sendDueReports(ctx, now)
+		assert.Equal(t, 1, env.sent)
+	})
+}
@@ -0,0 +1,133 @@
+package reportsimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+type store interface {
+	Insert(ctx context.Context, report *reports.Report) error
+	Update(ctx context.Context, report *reports.Report) error
+	Delete(ctx context.Context, cmd *reports.DeleteReportCommand) error
+	Get(ctx context.Context, query *reports.GetReportQuery) (*reports.Report, error)
+	GetByID(ctx context.Context, id int64) (*reports.Report, error)
+	Search(ctx context.Context, query *reports.SearchReportsQuery) ([]*reports.Report, error)
+	GetDue(ctx context.Context, query *reports.GetDueReportsQuery) ([]*reports.Report, error)
+	UpdateRun(ctx context.Context, cmd *reports.UpdateReportRunCommand) error
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (xs *xormStore) Insert(ctx context.Context, report *reports.Report) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(report)
+		return err
+	})
+}
+
+func (xs *xormStore) Update(ctx context.Context, report *reports.Report) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		// the run status is only changed by UpdateRun
+		affected, err := sess.ID(report.ID).Where("org_id = ?", report.OrgID).
+			Omit("id", "org_id", "user_id", "created", "last_run_at", "last_error").
+			AllCols().
+			Update(report)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return reports.ErrReportNotFound.Errorf("report %d not found", report.ID)
+		}
+		return nil
+	})
+}
+
+func (xs *xormStore) Delete(ctx context.Context, cmd *reports.DeleteReportCommand) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("id = ? AND org_id = ?", cmd.ID, cmd.OrgID).Delete(&reports.Report{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return reports.ErrReportNotFound.Errorf("report %d not found", cmd.ID)
+		}
+		return nil
+	})
+}
+
+func (xs *xormStore) Get(ctx context.Context, query *reports.GetReportQuery) (*reports.Report, error) {
+	var report reports.Report
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("id = ? AND org_id = ?", query.ID, query.OrgID).Get(&report)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return reports.ErrReportNotFound.Errorf("report %d not found", query.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (xs *xormStore) GetByID(ctx context.Context, id int64) (*reports.Report, error) {
+	var report reports.Report
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.ID(id).Get(&report)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return reports.ErrReportNotFound.Errorf("report %d not found", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (xs *xormStore) Search(ctx context.Context, query *reports.SearchReportsQuery) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ?", query.OrgID)
+		if query.DashboardUID != "" {
+			sess.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		return sess.Asc("name").Find(&result)
+	})
+	return result, err
+}
+
+func (xs *xormStore) GetDue(ctx context.Context, query *reports.GetDueReportsQuery) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("enabled = ? AND next_run_at <= ?", true, query.Now).Asc("next_run_at")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit)
+		}
+		return sess.Find(&result)
+	})
+	return result, err
+}
+
+func (xs *xormStore) UpdateRun(ctx context.Context, cmd *reports.UpdateReportRunCommand) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(cmd.ID).Cols("last_run_at", "last_error", "next_run_at", "updated").Update(&reports.Report{
+			LastRunAt: &cmd.LastRunAt,
+			LastError: cmd.LastError,
+			NextRunAt: cmd.NextRunAt,
+			Updated:   time.Now(),
+		})
+		return err
+	})
+}
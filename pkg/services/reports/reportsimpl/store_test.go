@@ -0,0 +1,126 @@
+package reportsimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+func TestIntegrationReportStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := &xormStore{db: db.InitTestDB(t)}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	next := now.Add(time.Hour)
+	newReport := func(orgID int64, dashboardUID string, nextRunAt *time.Time) *reports.Report {
+		return &reports.Report{
+			OrgID:        orgID,
+			UserID:       1,
+			Name:         "Weekly " + dashboardUID,
+			DashboardUID: dashboardUID,
+			PanelIDs:     []int64{1, 2},
+			From:         "now-7d",
+			To:           "now",
+			Variables:    map[string][]string{"env": {"prod", "staging"}},
+			Recipients:   []string{"ops@example.com"},
+			Orientation:  reports.OrientationLandscape,
+			Schedule:     "0 8 * * 1",
+			Timezone:     "Europe/Paris",
+			Enabled:      nextRunAt != nil,
+			NextRunAt:    nextRunAt,
+			Created:      now,
+			Updated:      now,
+		}
+	}
+
+	weekly := newReport(1, "abc", &next)
+	require.NoError(t, s.Insert(ctx, weekly))
+	require.NoError(t, s.Insert(ctx, newReport(1, "def", nil)))
+	require.NoError(t, s.Insert(ctx, newReport(2, "abc", &now)))
+
+	t.Run("gets a report of an organization", func(t *testing.T) {
+		report, err := s.Get(ctx, &reports.GetReportQuery{ID: weekly.ID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, report.PanelIDs)
+		assert.Equal(t, map[string][]string{"env": {"prod", "staging"}}, report.Variables)
+		assert.Equal(t, []string{"ops@example.com"}, report.Recipients)
+		require.NotNil(t, report.NextRunAt)
+		assert.True(t, next.Equal(*report.NextRunAt))
+
+		_, err = s.Get(ctx, &reports.GetReportQuery{ID: weekly.ID, OrgID: 2})
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+	})
+
+	t.Run("searches the reports of an organization", func(t *testing.T) {
+		result, err := s.Search(ctx, &reports.SearchReportsQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+
+		result, err = s.Search(ctx, &reports.SearchReportsQuery{OrgID: 1, DashboardUID: "abc"})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, weekly.ID, result[0].ID)
+	})
+
+	t.Run("gets the enabled reports that are due", func(t *testing.T) {
+		due, err := s.GetDue(ctx, &reports.GetDueReportsQuery{Now: now})
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, int64(2), due[0].OrgID)
+
+		due, err = s.GetDue(ctx, &reports.GetDueReportsQuery{Now: next})
+		require.NoError(t, err)
+		assert.Len(t, due, 2)
+	})
+
+	t.Run("records the runs of a report", func(t *testing.T) {
+		require.NoError(t, s.UpdateRun(ctx, &reports.UpdateReportRunCommand{ID: weekly.ID, LastRunAt: next, LastError: "renderer timeout"}))
+
+		report, err := s.GetByID(ctx, weekly.ID)
+		require.NoError(t, err)
+		assert.Nil(t, report.NextRunAt)
+		require.NotNil(t, report.LastRunAt)
+		assert.True(t, next.Equal(*report.LastRunAt))
+		assert.Equal(t, "renderer timeout", report.LastError)
+	})
+
+	t.Run("updates a report without changing its last run", func(t *testing.T) {
+		report, err := s.Get(ctx, &reports.GetReportQuery{ID: weekly.ID, OrgID: 1})
+		require.NoError(t, err)
+		report.Name = "Daily"
+		report.PanelIDs = nil
+		report.Enabled = true
+		report.NextRunAt = &now
+		report.LastError = ""
+		require.NoError(t, s.Update(ctx, report))
+
+		updated, err := s.Get(ctx, &reports.GetReportQuery{ID: weekly.ID, OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "Daily", updated.Name)
+		assert.Empty(t, updated.PanelIDs)
+		assert.True(t, updated.Enabled)
+		assert.Equal(t, "renderer timeout", updated.LastError)
+		assert.Equal(t, int64(1), updated.UserID)
+
+		report.OrgID = 2
+		require.ErrorIs(t, s.Update(ctx, report), reports.ErrReportNotFound)
+	})
+
+	t.Run("deletes a report", func(t *testing.T) {
+		require.ErrorIs(t, s.Delete(ctx, &reports.DeleteReportCommand{ID: weekly.ID, OrgID: 2}), reports.ErrReportNotFound)
+		require.NoError(t, s.Delete(ctx, &reports.DeleteReportCommand{ID: weekly.ID, OrgID: 1}))
+
+		_, err := s.GetByID(ctx, weekly.ID)
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+	})
+}
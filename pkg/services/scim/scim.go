@@ -0,0 +1,164 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+const (
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+)
+
+var (
+	ErrUserNotFound  = errutil.NotFound("scim.user-not-found", errutil.WithPublicMessage("User not found"))
+	ErrGroupNotFound = errutil.NotFound("scim.group-not-found", errutil.WithPublicMessage("Group not found"))
+	ErrUniqueness    = errutil.BadRequest("scim.uniqueness", errutil.WithPublicMessage("A resource with the same identifier already exists"))
+	ErrUserNotOwned  = errutil.Forbidden("scim.user-not-owned", errutil.WithPublicMessage("The user isn't managed by SCIM, or is a server administrator or a member of another organization"))
+	ErrInvalidFilter = errutil.BadRequest("scim.invalid-filter", errutil.WithPublicMessage("Unsupported filter"))
+	ErrInvalidPath   = errutil.BadRequest("scim.invalid-path", errutil.WithPublicMessage("Unsupported patch path"))
+	ErrInvalidValue  = errutil.BadRequest("scim.invalid-value", errutil.WithPublicMessage("Invalid value"))
+)
+
+// Service provisions the users and the teams of an organization from an identity provider,
+// following the SCIM 2.0 protocol (RFC 7643 and RFC 7644). SCIM groups are Grafana teams.
+//
+// Only the users and teams created, or adopted, through SCIM are resources of the service. Existing users are only
+// adopted when a server administrator allows it.
+// Deleting a user disables it and its sessions instead of removing it.
+type Service interface {
+	GetUser(ctx context.Context, orgID int64, id string) (*User, error)
+	SearchUsers(ctx context.Context, query *SearchQuery) (*SearchUsersResult, error)
+	CreateUser(ctx context.Context, orgID int64, u *User) (*User, error)
+	ReplaceUser(ctx context.Context, orgID int64, id string, u *User) (*User, error)
+	PatchUser(ctx context.Context, orgID int64, id string, ops []PatchOperation) (*User, error)
+	DeleteUser(ctx context.Context, orgID int64, id string) error
+
+	GetGroup(ctx context.Context, orgID int64, id string) (*Group, error)
+	SearchGroups(ctx context.Context, query *SearchQuery) (*SearchGroupsResult, error)
+	CreateGroup(ctx context.Context, orgID int64, g *Group) (*Group, error)
+	ReplaceGroup(ctx context.Context, orgID int64, id string, g *Group) (*Group, error)
+	PatchGroup(ctx context.Context, orgID int64, id string, ops []PatchOperation) (*Group, error)
+	DeleteGroup(ctx context.Context, orgID int64, id string) error
+}
+
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	// Active is true when the user is not disabled, it defaults to true when a user is created.
+	Active *bool `json:"active,omitempty"`
+	Meta   *Meta `json:"meta,omitempty"`
+}
+
+// PrimaryEmail returns the primary email of the user, or the first one.
+func (u *User) PrimaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// FullName returns the display name of the user, or the name built from its components.
+func (u *User) FullName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name == nil {
+		return ""
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	if u.Name.GivenName != "" && u.Name.FamilyName != "" {
+		return u.Name.GivenName + " " + u.Name.FamilyName
+	}
+	return u.Name.GivenName + u.Name.FamilyName
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Member `json:"members"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Member is a user of a group, Value is the id of the user.
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+type SearchQuery struct {
+	OrgID int64
+	// Filter only supports the eq operator, e.g. userName eq "jane"
+	Filter string
+	// StartIndex is the 1-based index of the first result.
+	StartIndex int
+	Count      int
+}
+
+type SearchUsersResult struct {
+	TotalResults int64
+	Users        []*User
+}
+
+type SearchGroupsResult struct {
+	TotalResults int64
+	Groups       []*Group
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
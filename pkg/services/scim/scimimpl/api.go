@@ -0,0 +1,526 @@
+package scimimpl
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/scim"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const contentType = "application/scim+json"
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/scim/v2", func(scimRoute routing.RouteRegister) {
+		scimRoute.Get("/ServiceProviderConfig", routing.Wrap(s.handleGetServiceProviderConfig))
+		scimRoute.Get("/ResourceTypes", routing.Wrap(s.handleGetResourceTypes))
+
+		scimRoute.Get("/Users", routing.Wrap(s.handleSearchUsers))
+		scimRoute.Post("/Users", routing.Wrap(s.handleCreateUser))
+		scimRoute.Get("/Users/:id", routing.Wrap(s.handleGetUser))
+		scimRoute.Put("/Users/:id", routing.Wrap(s.handleReplaceUser))
+		scimRoute.Patch("/Users/:id", routing.Wrap(s.handlePatchUser))
+		scimRoute.Delete("/Users/:id", routing.Wrap(s.handleDeleteUser))
+
+		scimRoute.Get("/Groups", routing.Wrap(s.handleSearchGroups))
+		scimRoute.Post("/Groups", routing.Wrap(s.handleCreateGroup))
+		scimRoute.Get("/Groups/:id", routing.Wrap(s.handleGetGroup))
+		scimRoute.Put("/Groups/:id", routing.Wrap(s.handleReplaceGroup))
+		scimRoute.Patch("/Groups/:id", routing.Wrap(s.handlePatchGroup))
+		scimRoute.Delete("/Groups/:id", routing.Wrap(s.handleDeleteGroup))
+	}, requireServiceAccount, authorize(ac.EvalPermission(ActionProvision)))
+}
+
+// requireServiceAccount only lets service accounts, authenticated with a token, call the SCIM API.
+func requireServiceAccount(c *contextmodel.ReqContext) {
+	if !c.IsSignedIn || !c.SignedInUser.IsServiceAccount {
+		scimErrorResponse(http.StatusUnauthorized, "", "The SCIM API requires a service account token").WriteTo(c)
+	}
+}
+
+// swagger:route GET /scim/v2/ServiceProviderConfig scim getSCIMServiceProviderConfig
+//
+// Get the SCIM features that Grafana supports.
+//
+// Responses:
+// 200: scimResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (s *Service) handleGetServiceProviderConfig(c *contextmodel.ReqContext) response.Response {
+	return scimJSON(http.StatusOK, util.DynMap{
+		"schemas":          []string{scim.SchemaServiceProviderConfig},
+		"documentationUri": "https://grafana.com/docs/grafana/latest/developers/http_api/scim/",
+		"patch":            util.DynMap{"supported": true},
+		"bulk":             util.DynMap{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":           util.DynMap{"supported": true, "maxResults": maxCount},
+		"changePassword":   util.DynMap{"supported": false},
+		"sort":             util.DynMap{"supported": false},
+		"etag":             util.DynMap{"supported": false},
+		"authenticationSchemes": []util.DynMap{{
+			"type":        "oauthbearertoken",
+			"name":        "Service account token",
+			"description": "Authentication with the token of a Grafana service account",
+			"primary":     true,
+		}},
+	})
+}
+
+// swagger:route GET /scim/v2/ResourceTypes scim getSCIMResourceTypes
+//
+// Get the SCIM resource types that Grafana supports.
+//
+// Responses:
+// 200: scimResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (s *Service) handleGetResourceTypes(c *contextmodel.ReqContext) response.Response {
+	resourceTypes := []util.DynMap{
+		{
+			"schemas":  []string{scim.SchemaResourceType},
+			"id":       scim.ResourceTypeUser,
+			"name":     scim.ResourceTypeUser,
+			"endpoint": "/Users",
+			"schema":   scim.SchemaUser,
+		},
+		{
+			"schemas":  []string{scim.SchemaResourceType},
+			"id":       scim.ResourceTypeGroup,
+			"name":     scim.ResourceTypeGroup,
+			"endpoint": "/Groups",
+			"schema":   scim.SchemaGroup,
+		},
+	}
+	return scimJSON(http.StatusOK, listResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: int64(len(resourceTypes)),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+// swagger:route GET /scim/v2/Users scim searchSCIMUsers
+//
+// Search the users provisioned in the organization of the service account.
+//
+// Only the eq operator is supported in filters, with the userName, externalId and emails attributes.
+//
+// Responses:
+// 200: scimResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSearchUsers(c *contextmodel.ReqContext) response.Response {
+	query := searchQuery(c)
+	result, err := s.SearchUsers(c.Req.Context(), query)
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusOK, listResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: result.TotalResults,
+		StartIndex:   query.StartIndex,
+		ItemsPerPage: len(result.Users),
+		Resources:    result.Users,
+	})
+}
+
+// swagger:route POST /scim/v2/Users scim createSCIMUser
+//
+// Provision a user.
+//
+// An existing user with the same login or email is adopted instead of creating a new one, unless it is a
+// server administrator or a member of another organization. The user is added to the organization of the
+// service account with the configured default role.
+//
+// Responses:
+// 201: scimResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) handleCreateUser(c *contextmodel.ReqContext) response.Response {
+	u := &scim.User{}
+	if err := bind(c, u); err != nil {
+		return s.scimError(c, err)
+	}
+	created, err := s.CreateUser(c.Req.Context(), c.SignedInUser.GetOrgID(), u)
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusCreated, created).SetHeader("Location", created.Meta.Location)
+}
+
+// swagger:route GET /scim/v2/Users/{id} scim getSCIMUser
+//
+// Get a provisioned user.
+//
+// Responses:
+// 200: scimResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleGetUser(c *contextmodel.ReqContext) response.Response {
+	u, err := s.GetUser(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":id"])
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusOK, u)
+}
+
+// swagger:route PUT /scim/v2/Users/{id} scim replaceSCIMUser
+//
+// Replace a provisioned user.
+//
+// Setting active to false disables the user and revokes its sessions.
+//
+// Responses:
+// 200: scimResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) handleReplaceUser(c *contextmodel.ReqContext) response.Response {
+	u := &scim.User{}
+	if err := bind(c, u); err != nil {
+		return s.scimError(c, err)
+	}
+	replaced, err := s.ReplaceUser(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":id"], u)
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusOK, replaced)
+}
+
+// swagger:route PATCH /scim/v2/Users/{id} scim patchSCIMUser
+//
+// Update the attributes of a provisioned user.
+//
+// Responses:
+// 200: scimResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) handlePatchUser(c *contextmodel.ReqContext) response.Response {
+	req := &scim.PatchRequest{}
+	if err := bind(c, req); err != nil {
+		return s.scimError(c, err)
+	}
+	patched, err := s.PatchUser(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":id"], req.Operations)
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusOK, patched)
+}
+
+// swagger:route DELETE /scim/v2/Users/{id} scim deleteSCIMUser
+//
+// Deprovision a user.
+//
+// The user is disabled and its sessions are revoked, it is not deleted from Grafana. A user that is a server
+// administrator or a member of another organization is only removed from the organization.
+//
+// Responses:
+// 204: scimResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleDeleteUser(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteUser(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":id"]); err != nil {
+		return s.scimError(c, err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+// swagger:route GET /scim/v2/Groups scim searchSCIMGroups
+//
+// Search the teams provisioned in the organization of the service account.
+//
+// Only the eq operator is supported in filters, with the displayName and externalId attributes.
+//
+// Responses:
+// 200: scimResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) handleSearchGroups(c *contextmodel.ReqContext) response.Response {
+	query := searchQuery(c)
+	result, err := s.SearchGroups(c.Req.Context(), query)
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusOK, listResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: result.TotalResults,
+		StartIndex:   query.StartIndex,
+		ItemsPerPage: len(result.Groups),
+		Resources:    result.Groups,
+	})
+}
+
+// swagger:route POST /scim/v2/Groups scim createSCIMGroup
+//
+// Provision a team.
+//
+// An existing team with the same name is adopted instead of creating a new one. The members have to be
+// provisioned users.
+//
+// Responses:
+// 201: scimResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) handleCreateGroup(c *contextmodel.ReqContext) response.Response {
+	g := &scim.Group{}
+	if err := bind(c, g); err != nil {
+		return s.scimError(c, err)
+	}
+	created, err := s.CreateGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), g)
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusCreated, created).SetHeader("Location", created.Meta.Location)
+}
+
+// swagger:route GET /scim/v2/Groups/{id} scim getSCIMGroup
+//
+// Get a provisioned team.
+//
+// Responses:
+// 200: scimResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleGetGroup(c *contextmodel.ReqContext) response.Response {
+	g, err := s.GetGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":id"])
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusOK, g)
+}
+
+// swagger:route PUT /scim/v2/Groups/{id} scim replaceSCIMGroup
+//
+// Replace a provisioned team and its members.
+//
+// Responses:
+// 200: scimResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) handleReplaceGroup(c *contextmodel.ReqContext) response.Response {
+	g := &scim.Group{}
+	if err := bind(c, g); err != nil {
+		return s.scimError(c, err)
+	}
+	replaced, err := s.ReplaceGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":id"], g)
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusOK, replaced)
+}
+
+// swagger:route PATCH /scim/v2/Groups/{id} scim patchSCIMGroup
+//
+// Update the name or the members of a provisioned team.
+//
+// Responses:
+// 200: scimResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) handlePatchGroup(c *contextmodel.ReqContext) response.Response {
+	req := &scim.PatchRequest{}
+	if err := bind(c, req); err != nil {
+		return s.scimError(c, err)
+	}
+	patched, err := s.PatchGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":id"], req.Operations)
+	if err != nil {
+		return s.scimError(c, err)
+	}
+	return scimJSON(http.StatusOK, patched)
+}
+
+// swagger:route DELETE /scim/v2/Groups/{id} scim deleteSCIMGroup
+//
+// Delete a provisioned team.
+//
+// Responses:
+// 204: scimResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) handleDeleteGroup(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":id"]); err != nil {
+		return s.scimError(c, err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func searchQuery(c *contextmodel.ReqContext) *scim.SearchQuery {
+	query := &scim.SearchQuery{
+		OrgID:      c.SignedInUser.GetOrgID(),
+		Filter:     c.Query("filter"),
+		StartIndex: c.QueryInt("startIndex"),
+		Count:      defaultCount,
+	}
+	if query.StartIndex < 1 {
+		query.StartIndex = 1
+	}
+	if count, err := strconv.Atoi(c.Query("count")); err == nil {
+		query.Count = count
+	}
+	return query
+}
+
+var errInvalidSyntax = errutil.BadRequest("scim.invalid-syntax", errutil.WithPublicMessage("Invalid request body"))
+
+// bind decodes the SCIM or JSON body of the request.
+func bind(c *contextmodel.ReqContext, v interface{}) error {
+	m, _, err := mime.ParseMediaType(c.Req.Header.Get("Content-Type"))
+	if err != nil || (m != contentType && m != "application/json") {
+		return errInvalidSyntax.Errorf("unsupported content type %q", c.Req.Header.Get("Content-Type"))
+	}
+	if err := json.NewDecoder(c.Req.Body).Decode(v); err != nil {
+		return errInvalidSyntax.Errorf("failed to decode request body: %w", err)
+	}
+	return nil
+}
+
+func scimJSON(status int, body interface{}) *response.NormalResponse {
+	return response.JSON(status, body).SetHeader("Content-Type", contentType)
+}
+
+// scimError converts the error to the error response of RFC 7644 section 3.12.
+func (s *Service) scimError(c *contextmodel.ReqContext, err error) response.Response {
+	var grafanaErr errutil.Error
+	if !errors.As(err, &grafanaErr) {
+		s.log.FromContext(c.Req.Context()).Error("SCIM request failed", "error", err)
+		return scimErrorResponse(http.StatusInternalServerError, "", "Internal server error")
+	}
+
+	status, scimType := grafanaErr.Reason.Status().HTTPStatus(), ""
+	switch {
+	case errors.Is(err, scim.ErrUniqueness):
+		// errutil has no status for conflicts
+		status, scimType = http.StatusConflict, "uniqueness"
+	case errors.Is(err, scim.ErrInvalidFilter):
+		scimType = "invalidFilter"
+	case errors.Is(err, scim.ErrInvalidPath):
+		scimType = "invalidPath"
+	case errors.Is(err, scim.ErrInvalidValue):
+		scimType = "invalidValue"
+	case errors.Is(err, errInvalidSyntax):
+		scimType = "invalidSyntax"
+	}
+	if status >= http.StatusInternalServerError {
+		s.log.FromContext(c.Req.Context()).Error("SCIM request failed", "error", err)
+	} else {
+		s.log.FromContext(c.Req.Context()).Debug("SCIM request rejected", "error", err)
+	}
+	return scimErrorResponse(status, scimType, grafanaErr.Public().Message)
+}
+
+func scimErrorResponse(status int, scimType, detail string) *response.NormalResponse {
+	return scimJSON(status, errorDTO{
+		Schemas:  []string{scim.SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+type errorDTO struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+type listResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// swagger:parameters getSCIMUser replaceSCIMUser patchSCIMUser deleteSCIMUser getSCIMGroup replaceSCIMGroup patchSCIMGroup deleteSCIMGroup
+type SCIMResourceParams struct {
+	// in:path
+	// required:true
+	ID string `json:"id"`
+}
+
+// swagger:parameters searchSCIMUsers searchSCIMGroups
+type SearchSCIMParams struct {
+	// in:query
+	// required:false
+	Filter string `json:"filter"`
+	// in:query
+	// required:false
+	// default:1
+	StartIndex int `json:"startIndex"`
+	// in:query
+	// required:false
+	// default:100
+	Count int `json:"count"`
+}
+
+// swagger:parameters createSCIMUser replaceSCIMUser
+type SCIMUserParams struct {
+	// in:body
+	// required:true
+	Body scim.User `json:"body"`
+}
+
+// swagger:parameters createSCIMGroup replaceSCIMGroup
+type SCIMGroupParams struct {
+	// in:body
+	// required:true
+	Body scim.Group `json:"body"`
+}
+
+// swagger:parameters patchSCIMUser patchSCIMGroup
+type PatchSCIMParams struct {
+	// in:body
+	// required:true
+	Body scim.PatchRequest `json:"body"`
+}
+
+// swagger:response scimResponse
+type SCIMResponse struct {
+	// in:body
+	Body interface{} `json:"body"`
+}
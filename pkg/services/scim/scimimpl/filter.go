@@ -0,0 +1,53 @@
+package scimimpl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/scim"
+)
+
+// Attributes that filters can compare, lower cased as SCIM attribute names are case-insensitive.
+const (
+	attrUserName    = "username"
+	attrExternalID  = "externalid"
+	attrEmails      = "emails"
+	attrEmailsValue = "emails.value"
+	attrDisplayName = "displayname"
+)
+
+// filterRegexp matches the only filters identity providers use to look up resources before
+// provisioning them, an attribute equal to a string, e.g. userName eq "jane".
+var filterRegexp = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9.]*)\s+(?i:eq)\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+type filter struct {
+	attribute string
+	value     string
+}
+
+// parseFilter returns nil for an empty filter.
+func parseFilter(s string, attributes ...string) (*filter, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	m := filterRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return nil, scim.ErrInvalidFilter.Errorf("unsupported filter %q", s)
+	}
+
+	f := &filter{attribute: strings.ToLower(m[1])}
+	value, err := strconv.Unquote(m[2])
+	if err != nil {
+		return nil, scim.ErrInvalidFilter.Errorf("invalid value in filter %q: %w", s, err)
+	}
+	f.value = value
+
+	for _, a := range attributes {
+		if f.attribute == a {
+			return f, nil
+		}
+	}
+	return nil, scim.ErrInvalidFilter.Errorf("unsupported attribute %q in filter %q", m[1], s)
+}
@@ -0,0 +1,62 @@
+package scimimpl
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	ActionProvision = "scim:provision"
+)
+
+const (
+	defaultCount = 100
+	maxCount     = 1000
+)
+
+var provisionerRole = accesscontrol.RoleDTO{
+	Name:        "fixed:scim:provisioner",
+	DisplayName: "SCIM provisioner",
+	Description: "Provision the users and the teams of the organization with the SCIM API",
+	Group:       "Users",
+	Permissions: []accesscontrol.Permission{
+		{Action: ActionProvision},
+	},
+}
+
+// declareFixedRoles grants the provisioner role to server administrators only, SCIM changes users that are shared
+// by all the organizations. Server administrators assign it to the service accounts of the identity providers.
+func declareFixedRoles(service accesscontrol.Service) error {
+	return service.DeclareFixedRoles(accesscontrol.RoleRegistration{
+		Role:   provisionerRole,
+		Grants: []string{accesscontrol.RoleGrafanaAdmin},
+	})
+}
+
+// resource links a user or a team of an organization to SCIM, with the id the identity provider gives it.
+type resource struct {
+	ID           int64     `xorm:"pk autoincr 'id'"`
+	OrgID        int64     `xorm:"org_id"`
+	ResourceType string    `xorm:"resource_type"`
+	ResourceID   int64     `xorm:"resource_id"`
+	ExternalID   string    `xorm:"external_id"`
+	Created      time.Time `xorm:"created"`
+	Updated      time.Time `xorm:"updated"`
+}
+
+func (resource) TableName() string {
+	return "scim_resource"
+}
+
+// managedUser records that SCIM created a user, or linked it when linking existing users is allowed. SCIM only
+// changes the users it manages, the record is kept when a user is deprovisioned so that it can be provisioned again.
+type managedUser struct {
+	ID      int64     `xorm:"pk autoincr 'id'"`
+	UserID  int64     `xorm:"user_id"`
+	Created time.Time `xorm:"created"`
+}
+
+func (managedUser) TableName() string {
+	return "scim_managed_user"
+}
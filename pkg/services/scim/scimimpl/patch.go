@@ -0,0 +1,283 @@
+package scimimpl
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/scim"
+)
+
+const (
+	opAdd     = "add"
+	opReplace = "replace"
+	opRemove  = "remove"
+)
+
+// patchUser applies the operations of a PATCH request to the user.
+func patchUser(u *scim.User, ops []scim.PatchOperation) error {
+	displayName, name := u.DisplayName, nameOf(u)
+	for _, op := range ops {
+		opName, err := normalizeOp(op.Op)
+		if err != nil {
+			return err
+		}
+		if err := patchUserAttribute(u, opName, op.Path, op.Value); err != nil {
+			return err
+		}
+	}
+
+	// The display name and the formatted name are derived from the name of the Grafana user, so they
+	// are dropped when only the name components change, to build the new name from the components.
+	if u.DisplayName == displayName && nameOf(u) != name {
+		u.DisplayName = ""
+		if u.Name != nil && u.Name.Formatted == name.Formatted {
+			u.Name.Formatted = ""
+		}
+	}
+	return nil
+}
+
+func nameOf(u *scim.User) scim.Name {
+	if u.Name == nil {
+		return scim.Name{}
+	}
+	return *u.Name
+}
+
+func patchUserAttribute(u *scim.User, op, path string, value json.RawMessage) error {
+	attribute := strings.ToLower(path)
+	switch attribute {
+	case "":
+		return patchAttributes(op, value, func(path string, value json.RawMessage) error {
+			return patchUserAttribute(u, op, path, value)
+		})
+	case "active":
+		if op == opRemove {
+			return scim.ErrInvalidPath.Errorf("active can't be removed")
+		}
+		active, err := unmarshalBool(value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+	case attrUserName:
+		if op == opRemove {
+			return scim.ErrInvalidPath.Errorf("userName can't be removed")
+		}
+		return unmarshalString(op, value, &u.UserName)
+	case attrDisplayName:
+		return unmarshalString(op, value, &u.DisplayName)
+	case attrExternalID:
+		return unmarshalString(op, value, &u.ExternalID)
+	case "name":
+		if op == opRemove {
+			u.Name = nil
+			return nil
+		}
+		name := &scim.Name{}
+		if err := unmarshalValue(value, name); err != nil {
+			return err
+		}
+		u.Name = name
+	case "name.formatted", "name.givenname", "name.familyname":
+		if u.Name == nil {
+			u.Name = &scim.Name{}
+		}
+		switch attribute {
+		case "name.formatted":
+			return unmarshalString(op, value, &u.Name.Formatted)
+		case "name.givenname":
+			return unmarshalString(op, value, &u.Name.GivenName)
+		default:
+			return unmarshalString(op, value, &u.Name.FamilyName)
+		}
+	case attrEmails:
+		if op == opRemove {
+			u.Emails = nil
+			return nil
+		}
+		var emails []scim.Email
+		if err := unmarshalValue(value, &emails); err != nil {
+			return err
+		}
+		if op == opAdd {
+			u.Emails = append(u.Emails, emails...)
+		} else {
+			u.Emails = emails
+		}
+	default:
+		// Grafana users have a single email, so a filtered email path like emails[type eq "work"].value
+		// targets it whatever the filter is.
+		if !strings.HasPrefix(attribute, attrEmails+"[") || !strings.HasSuffix(attribute, "].value") {
+			return scim.ErrInvalidPath.Errorf("unsupported user path %q", path)
+		}
+		if op == opRemove {
+			u.Emails = nil
+			return nil
+		}
+		var email string
+		if err := unmarshalValue(value, &email); err != nil {
+			return err
+		}
+		u.Emails = []scim.Email{{Value: email, Primary: true}}
+	}
+	return nil
+}
+
+// patchGroup applies the operations of a PATCH request to the group.
+func patchGroup(g *scim.Group, ops []scim.PatchOperation) error {
+	for _, op := range ops {
+		name, err := normalizeOp(op.Op)
+		if err != nil {
+			return err
+		}
+		if err := patchGroupAttribute(g, name, op.Path, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patchGroupAttribute(g *scim.Group, op, path string, value json.RawMessage) error {
+	attribute := strings.ToLower(path)
+	switch attribute {
+	case "":
+		return patchAttributes(op, value, func(path string, value json.RawMessage) error {
+			return patchGroupAttribute(g, op, path, value)
+		})
+	case attrDisplayName:
+		if op == opRemove {
+			return scim.ErrInvalidPath.Errorf("displayName can't be removed")
+		}
+		return unmarshalString(op, value, &g.DisplayName)
+	case attrExternalID:
+		return unmarshalString(op, value, &g.ExternalID)
+	case "members":
+		var members []scim.Member
+		if len(value) > 0 {
+			if err := unmarshalValue(value, &members); err != nil {
+				return err
+			}
+		}
+		switch {
+		case op == opReplace:
+			g.Members = members
+		case op == opAdd:
+			for _, m := range members {
+				if !hasMember(g.Members, m.Value) {
+					g.Members = append(g.Members, m)
+				}
+			}
+		case len(members) == 0:
+			g.Members = nil
+		default:
+			for _, m := range members {
+				g.Members = removeMember(g.Members, m.Value)
+			}
+		}
+	default:
+		// e.g. members[value eq "2"]
+		if !strings.HasPrefix(attribute, "members[") || !strings.HasSuffix(attribute, "]") || op != opRemove {
+			return scim.ErrInvalidPath.Errorf("unsupported group path %q", path)
+		}
+		f, err := parseFilter(path[len("members["):len(path)-1], "value")
+		if err != nil || f == nil {
+			return scim.ErrInvalidPath.Errorf("unsupported group path %q", path)
+		}
+		g.Members = removeMember(g.Members, f.value)
+	}
+	return nil
+}
+
+// patchAttributes applies an operation without path, whose value holds the attributes to update.
+func patchAttributes(op string, value json.RawMessage, patch func(path string, value json.RawMessage) error) error {
+	if op == opRemove {
+		return scim.ErrInvalidPath.Errorf("remove operations require a path")
+	}
+
+	attributes := map[string]json.RawMessage{}
+	if err := unmarshalValue(value, &attributes); err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(attributes))
+	for path := range attributes {
+		switch {
+		case path == "schemas" || path == "id" || path == "meta":
+		// Extension attributes, e.g. of the enterprise user schema, are not stored.
+		case strings.HasPrefix(path, "urn:"):
+		default:
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := patch(path, attributes[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeOp(op string) (string, error) {
+	name := strings.ToLower(op)
+	switch name {
+	case opAdd, opReplace, opRemove:
+		return name, nil
+	}
+	return "", scim.ErrInvalidValue.Errorf("unsupported patch operation %q", op)
+}
+
+func unmarshalValue(value json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(value, v); err != nil {
+		return scim.ErrInvalidValue.Errorf("invalid patch value %s: %w", value, err)
+	}
+	return nil
+}
+
+func unmarshalString(op string, value json.RawMessage, s *string) error {
+	if op == opRemove {
+		*s = ""
+		return nil
+	}
+	return unmarshalValue(value, s)
+}
+
+// unmarshalBool also accepts booleans in strings, as some identity providers send "False".
+func unmarshalBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := unmarshalValue(value, &s); err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(strings.ToLower(s))
+	if err != nil {
+		return false, scim.ErrInvalidValue.Errorf("invalid boolean %q", s)
+	}
+	return b, nil
+}
+
+func hasMember(members []scim.Member, value string) bool {
+	for _, m := range members {
+		if m.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeMember(members []scim.Member, value string) []scim.Member {
+	result := make([]scim.Member, 0, len(members))
+	for _, m := range members {
+		if m.Value != value {
+			result = append(result, m)
+		}
+	}
+	return result
+}
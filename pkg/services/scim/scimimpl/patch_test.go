@@ -0,0 +1,193 @@
+package scimimpl
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/scim"
+)
+
+func TestParseFilter(t *testing.T) {
+	f, err := parseFilter(`userName Eq "jane \"jd\" doe"`, attrUserName)
+	require.NoError(t, err)
+	assert.Equal(t, &filter{attribute: attrUserName, value: `jane "jd" doe`}, f)
+
+	f, err = parseFilter(" ", attrUserName)
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	for _, s := range []string{`userName sw "j"`, `userName eq "jane" and active eq true`, `userName eq jane`, `displayName eq "admins"`} {
+		_, err := parseFilter(s, attrUserName)
+		assert.ErrorIs(t, err, scim.ErrInvalidFilter, s)
+	}
+}
+
+func TestPatchUser(t *testing.T) {
+	active := func(b bool) *bool { return &b }
+	newUser := func() *scim.User {
+		return &scim.User{
+			UserName:    "jane",
+			DisplayName: "Jane Doe",
+			Name:        &scim.Name{Formatted: "Jane Doe"},
+			Emails:      []scim.Email{{Value: "jane@example.com", Primary: true}},
+			Active:      active(true),
+		}
+	}
+
+	tests := []struct {
+		desc        string
+		ops         []scim.PatchOperation
+		expected    func(u *scim.User)
+		expectedErr error
+	}{
+		{
+			desc: "should replace attributes without path",
+			ops:  []scim.PatchOperation{{Op: "Replace", Value: json.RawMessage(`{"active": false, "userName": "janet", "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department": "R&D"}`)}},
+			expected: func(u *scim.User) {
+				u.Active = active(false)
+				u.UserName = "janet"
+			},
+		},
+		{
+			desc:     "should accept booleans in strings",
+			ops:      []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`"False"`)}},
+			expected: func(u *scim.User) { u.Active = active(false) },
+		},
+		{
+			desc:     "should replace the email",
+			ops:      []scim.PatchOperation{{Op: "replace", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"janet@example.com"`)}},
+			expected: func(u *scim.User) { u.Emails = []scim.Email{{Value: "janet@example.com", Primary: true}} },
+		},
+		{
+			desc: "should build the display name from the name components",
+			ops: []scim.PatchOperation{
+				{Op: "replace", Path: "name.givenName", Value: json.RawMessage(`"Janet"`)},
+				{Op: "replace", Path: "name.familyName", Value: json.RawMessage(`"Doe"`)},
+			},
+			expected: func(u *scim.User) {
+				u.DisplayName = ""
+				u.Name = &scim.Name{GivenName: "Janet", FamilyName: "Doe"}
+			},
+		},
+		{
+			desc: "should keep a display name set with the name components",
+			ops: []scim.PatchOperation{
+				{Op: "replace", Path: "name.givenName", Value: json.RawMessage(`"Janet"`)},
+				{Op: "replace", Path: "displayName", Value: json.RawMessage(`"JD"`)},
+			},
+			expected: func(u *scim.User) {
+				u.DisplayName = "JD"
+				u.Name.GivenName = "Janet"
+			},
+		},
+		{
+			desc:     "should remove the external id",
+			ops:      []scim.PatchOperation{{Op: "remove", Path: "externalId"}},
+			expected: func(u *scim.User) {},
+		},
+		{
+			desc:        "should not remove the user name",
+			ops:         []scim.PatchOperation{{Op: "remove", Path: "userName"}},
+			expectedErr: scim.ErrInvalidPath,
+		},
+		{
+			desc:        "should reject unknown paths",
+			ops:         []scim.PatchOperation{{Op: "replace", Path: "nickName", Value: json.RawMessage(`"jd"`)}},
+			expectedErr: scim.ErrInvalidPath,
+		},
+		{
+			desc:        "should reject unknown operations",
+			ops:         []scim.PatchOperation{{Op: "move", Path: "userName"}},
+			expectedErr: scim.ErrInvalidValue,
+		},
+		{
+			desc:        "should reject invalid values",
+			ops:         []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`"maybe"`)}},
+			expectedErr: scim.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			u := newUser()
+			err := patchUser(u, tt.ops)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			expected := newUser()
+			tt.expected(expected)
+			assert.Equal(t, expected, u)
+		})
+	}
+}
+
+func TestPatchGroup(t *testing.T) {
+	newGroup := func() *scim.Group {
+		return &scim.Group{DisplayName: "admins", Members: []scim.Member{{Value: "1"}, {Value: "2"}}}
+	}
+
+	tests := []struct {
+		desc            string
+		ops             []scim.PatchOperation
+		expectedName    string
+		expectedMembers []scim.Member
+		expectedErr     error
+	}{
+		{
+			desc:            "should add members once",
+			ops:             []scim.PatchOperation{{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "2"}, {"value": "3"}]`)}},
+			expectedMembers: []scim.Member{{Value: "1"}, {Value: "2"}, {Value: "3"}},
+		},
+		{
+			desc:            "should remove a member with a filter",
+			ops:             []scim.PatchOperation{{Op: "remove", Path: `members[value eq "1"]`}},
+			expectedMembers: []scim.Member{{Value: "2"}},
+		},
+		{
+			desc:            "should remove the listed members",
+			ops:             []scim.PatchOperation{{Op: "remove", Path: "members", Value: json.RawMessage(`[{"value": "2"}]`)}},
+			expectedMembers: []scim.Member{{Value: "1"}},
+		},
+		{
+			desc:            "should remove all members",
+			ops:             []scim.PatchOperation{{Op: "remove", Path: "members"}},
+			expectedMembers: nil,
+		},
+		{
+			desc:            "should replace the name and the members without path",
+			ops:             []scim.PatchOperation{{Op: "replace", Value: json.RawMessage(`{"id": "5", "displayName": "ops", "members": [{"value": "4"}]}`)}},
+			expectedName:    "ops",
+			expectedMembers: []scim.Member{{Value: "4"}},
+		},
+		{
+			desc:        "should only remove members with a filter",
+			ops:         []scim.PatchOperation{{Op: "add", Path: `members[value eq "1"]`}},
+			expectedErr: scim.ErrInvalidPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			g := newGroup()
+			err := patchGroup(g, tt.ops)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			expectedName := tt.expectedName
+			if expectedName == "" {
+				expectedName = "admins"
+			}
+			assert.Equal(t, expectedName, g.DisplayName)
+			assert.Equal(t, tt.expectedMembers, g.Members)
+		})
+	}
+}
@@ -0,0 +1,839 @@
+package scimimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/scim"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// teamMemberPermission is the permission of the users that SCIM adds to teams.
+const teamMemberPermission = "Member"
+
+var _ scim.Service = (*Service)(nil)
+
+type Service struct {
+	store           store
+	cfg             *setting.Cfg
+	userService     user.Service
+	orgService      org.Service
+	teamService     team.Service
+	teamPermissions ac.TeamPermissionsService
+	tokenService    auth.UserTokenService
+	accessControl   ac.AccessControl
+	log             log.Logger
+
+	enabled           bool
+	defaultOrgRole    org.RoleType
+	linkExistingUsers bool
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	userService user.Service,
+	orgService org.Service,
+	teamService team.Service,
+	teamPermissionsService ac.TeamPermissionsService,
+	tokenService auth.UserTokenService,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("auth.scim")
+	s := &Service{
+		store:           &xormStore{db: sql, now: time.Now},
+		cfg:             cfg,
+		userService:     userService,
+		orgService:      orgService,
+		teamService:     teamService,
+		teamPermissions: teamPermissionsService,
+		tokenService:    tokenService,
+		accessControl:   accessControl,
+		log:             log.New("scim"),
+		enabled:         section.Key("enabled").MustBool(false),
+		defaultOrgRole:  org.RoleType(section.Key("default_org_role").MustString(string(org.RoleViewer))),
+		// only server administrators can allow SCIM to take over users created otherwise
+		linkExistingUsers: section.Key("link_existing_users").MustBool(false),
+	}
+
+	if !s.enabled {
+		return s, nil
+	}
+
+	if !s.defaultOrgRole.IsValid() {
+		return nil, fmt.Errorf("invalid [auth.scim] default_org_role %q", s.defaultOrgRole)
+	}
+
+	if err := declareFixedRoles(accesscontrolService); err != nil {
+		return nil, err
+	}
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, orgID int64, id string) (*scim.User, error) {
+	usr, r, err := s.getUser(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toUser(usr, r), nil
+}
+
+func (s *Service) SearchUsers(ctx context.Context, query *scim.SearchQuery) (*scim.SearchUsersResult, error) {
+	f, err := parseFilter(query.Filter, attrUserName, attrExternalID, attrEmails, attrEmailsValue)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := pagination(query)
+	result := &scim.SearchUsersResult{Users: make([]*scim.User, 0)}
+
+	if f != nil {
+		usr, r, err := s.findUser(ctx, query.OrgID, f)
+		if err != nil || usr == nil {
+			return result, err
+		}
+		result.TotalResults = 1
+		if offset == 0 && limit > 0 {
+			result.Users = append(result.Users, s.toUser(usr, r))
+		}
+		return result, nil
+	}
+
+	resources, total, err := s.store.List(ctx, query.OrgID, scim.ResourceTypeUser, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	result.TotalResults = total
+	for _, r := range resources {
+		usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: r.ResourceID})
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		result.Users = append(result.Users, s.toUser(usr, r))
+	}
+	return result, nil
+}
+
+// CreateUser creates the user, or adopts the existing user with the same login or email, and adds it to the organization.
+// Existing users are adopted when SCIM created them before, or when link_existing_users allows it, and only when they
+// aren't server administrators or members of another organization.
+func (s *Service) CreateUser(ctx context.Context, orgID int64, u *scim.User) (*scim.User, error) {
+	if u.UserName == "" {
+		return nil, scim.ErrInvalidValue.Errorf("userName is required")
+	}
+	if err := s.checkExternalID(ctx, orgID, scim.ResourceTypeUser, 0, u.ExternalID); err != nil {
+		return nil, err
+	}
+
+	usr, err := s.findExistingUser(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if usr != nil {
+		if usr.IsServiceAccount {
+			return nil, scim.ErrUniqueness.Errorf("userName %q belongs to a service account", u.UserName)
+		}
+		r, err := s.store.Get(ctx, orgID, scim.ResourceTypeUser, usr.ID)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			return nil, scim.ErrUniqueness.Errorf("user %q is already provisioned", u.UserName)
+		}
+		managed, err := s.store.IsManaged(ctx, usr.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !managed && !s.linkExistingUsers {
+			return nil, scim.ErrUniqueness.Errorf("user %q already exists and isn't managed by SCIM", u.UserName)
+		}
+		exclusive, err := s.exclusive(ctx, orgID, usr)
+		if err != nil {
+			return nil, err
+		}
+		if !exclusive {
+			return nil, scim.ErrUniqueness.Errorf("user %q is a server administrator or a member of another organization", u.UserName)
+		}
+		if err := s.store.SetManaged(ctx, usr.ID); err != nil {
+			return nil, err
+		}
+		if u.Active == nil {
+			active := true
+			u.Active = &active
+		}
+		if err := s.updateUser(ctx, orgID, usr, u); err != nil {
+			return nil, err
+		}
+		s.log.FromContext(ctx).Info("Adopted existing user", "orgId", orgID, "userId", usr.ID)
+	} else {
+		usr, err = s.userService.Create(ctx, &user.CreateUserCommand{
+			Login:        u.UserName,
+			Email:        u.PrimaryEmail(),
+			Name:         u.FullName(),
+			OrgID:        orgID,
+			IsDisabled:   u.Active != nil && !*u.Active,
+			SkipOrgSetup: true,
+		})
+		if err != nil {
+			if errors.Is(err, user.ErrUserAlreadyExists) {
+				return nil, scim.ErrUniqueness.Errorf("user %q already exists", u.UserName)
+			}
+			return nil, err
+		}
+		if err := s.store.SetManaged(ctx, usr.ID); err != nil {
+			return nil, err
+		}
+		s.log.FromContext(ctx).Info("Created user", "orgId", orgID, "userId", usr.ID)
+	}
+
+	err = s.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{OrgID: orgID, UserID: usr.ID, Role: s.defaultOrgRole})
+	if err != nil && !errors.Is(err, org.ErrOrgUserAlreadyAdded) {
+		return nil, err
+	}
+
+	r := &resource{OrgID: orgID, ResourceType: scim.ResourceTypeUser, ResourceID: usr.ID, ExternalID: u.ExternalID}
+	if err := s.store.Save(ctx, r); err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, orgID, strconv.FormatInt(usr.ID, 10))
+}
+
+func (s *Service) ReplaceUser(ctx context.Context, orgID int64, id string, u *scim.User) (*scim.User, error) {
+	if u.UserName == "" {
+		return nil, scim.ErrInvalidValue.Errorf("userName is required")
+	}
+
+	usr, r, err := s.getUser(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkExternalID(ctx, orgID, scim.ResourceTypeUser, r.ResourceID, u.ExternalID); err != nil {
+		return nil, err
+	}
+	if err := s.updateUser(ctx, orgID, usr, u); err != nil {
+		return nil, err
+	}
+
+	r.ExternalID = u.ExternalID
+	if err := s.store.Save(ctx, r); err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, orgID, id)
+}
+
+func (s *Service) PatchUser(ctx context.Context, orgID int64, id string, ops []scim.PatchOperation) (*scim.User, error) {
+	u, err := s.GetUser(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := patchUser(u, ops); err != nil {
+		return nil, err
+	}
+	return s.ReplaceUser(ctx, orgID, id, u)
+}
+
+// DeleteUser disables the user and revokes its sessions, the user stays in Grafana but is no longer a SCIM resource.
+// A user that the organization doesn't own is removed from the organization instead.
+func (s *Service) DeleteUser(ctx context.Context, orgID int64, id string) error {
+	usr, r, err := s.getUser(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+	owned, err := s.owns(ctx, orgID, usr)
+	if err != nil {
+		return err
+	}
+	if owned {
+		err = s.setDisabled(ctx, usr.ID, true)
+	} else {
+		err = s.orgService.RemoveOrgUser(ctx, &org.RemoveOrgUserCommand{OrgID: orgID, UserID: usr.ID})
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, orgID, scim.ResourceTypeUser, r.ResourceID); err != nil {
+		return err
+	}
+	s.log.FromContext(ctx).Info("Deprovisioned user", "orgId", orgID, "userId", usr.ID)
+	return nil
+}
+
+func (s *Service) getUser(ctx context.Context, orgID int64, id string) (*user.User, *resource, error) {
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, nil, scim.ErrUserNotFound.Errorf("invalid user id %q", id)
+	}
+
+	r, err := s.store.Get(ctx, orgID, scim.ResourceTypeUser, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r == nil {
+		return nil, nil, scim.ErrUserNotFound.Errorf("user %d is not provisioned in org %d", userID, orgID)
+	}
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, nil, scim.ErrUserNotFound.Errorf("user %d not found", userID)
+		}
+		return nil, nil, err
+	}
+	return usr, r, nil
+}
+
+// findUser returns the provisioned user matching the filter, or nil.
+func (s *Service) findUser(ctx context.Context, orgID int64, f *filter) (*user.User, *resource, error) {
+	var userID int64
+	switch f.attribute {
+	case attrExternalID:
+		if f.value == "" {
+			return nil, nil, nil
+		}
+		r, err := s.store.GetByExternalID(ctx, orgID, scim.ResourceTypeUser, f.value)
+		if err != nil || r == nil {
+			return nil, nil, err
+		}
+		userID = r.ResourceID
+	case attrUserName:
+		usr, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: f.value})
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, nil, nil
+			}
+			return nil, nil, err
+		}
+		if !strings.EqualFold(usr.Login, f.value) {
+			return nil, nil, nil
+		}
+		userID = usr.ID
+	default:
+		usr, err := s.userService.GetByEmail(ctx, &user.GetUserByEmailQuery{Email: f.value})
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, nil, nil
+			}
+			return nil, nil, err
+		}
+		userID = usr.ID
+	}
+
+	usr, r, err := s.getUser(ctx, orgID, strconv.FormatInt(userID, 10))
+	if errors.Is(err, scim.ErrUserNotFound) {
+		return nil, nil, nil
+	}
+	return usr, r, err
+}
+
+// findExistingUser returns the Grafana user with the login or the email of the SCIM user, or nil.
+func (s *Service) findExistingUser(ctx context.Context, u *scim.User) (*user.User, error) {
+	usr, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: u.UserName})
+	if err == nil || !errors.Is(err, user.ErrUserNotFound) {
+		return usr, err
+	}
+
+	email := u.PrimaryEmail()
+	if email == "" {
+		return nil, nil
+	}
+	usr, err = s.userService.GetByEmail(ctx, &user.GetUserByEmailQuery{Email: email})
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil, nil
+	}
+	return usr, err
+}
+
+// updateUser updates the login, email, name and active state of the user. The email and the name are kept when
+// the SCIM user has none, as identity providers often leave them out. Users that the organization doesn't own
+// can't be changed, see owns.
+func (s *Service) updateUser(ctx context.Context, orgID int64, usr *user.User, u *scim.User) error {
+	cmd := &user.UpdateUserCommand{
+		UserID: usr.ID,
+		Login:  u.UserName,
+		Email:  u.PrimaryEmail(),
+		Name:   u.FullName(),
+		Theme:  usr.Theme,
+	}
+	if cmd.Email == "" {
+		cmd.Email = usr.Email
+	}
+	if cmd.Name == "" {
+		cmd.Name = usr.Name
+	}
+
+	changed := cmd.Login != usr.Login || cmd.Email != usr.Email || cmd.Name != usr.Name
+	activeChanged := u.Active != nil && *u.Active == usr.IsDisabled
+	if !changed && !activeChanged {
+		return nil
+	}
+
+	owned, err := s.owns(ctx, orgID, usr)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return scim.ErrUserNotOwned.Errorf("user %d isn't owned by org %d", usr.ID, orgID)
+	}
+
+	if !strings.EqualFold(cmd.Login, usr.Login) {
+		if err := s.checkUserConflict(ctx, usr.ID, cmd.Login); err != nil {
+			return err
+		}
+	}
+	if !strings.EqualFold(cmd.Email, usr.Email) {
+		if err := s.checkUserConflict(ctx, usr.ID, cmd.Email); err != nil {
+			return err
+		}
+	}
+
+	if changed {
+		if err := s.userService.Update(ctx, cmd); err != nil {
+			return err
+		}
+	}
+
+	if activeChanged {
+		return s.setDisabled(ctx, usr.ID, !*u.Active)
+	}
+	return nil
+}
+
+// owns returns whether the organization owns the user: the login, the email and the active state of a user are
+// shared by all the organizations, so SCIM only changes the users it manages that aren't server administrators
+// and that aren't members of another organization.
+func (s *Service) owns(ctx context.Context, orgID int64, usr *user.User) (bool, error) {
+	managed, err := s.store.IsManaged(ctx, usr.ID)
+	if err != nil || !managed {
+		return false, err
+	}
+	return s.exclusive(ctx, orgID, usr)
+}
+
+// exclusive returns whether the user isn't a server administrator and isn't a member of another organization.
+func (s *Service) exclusive(ctx context.Context, orgID int64, usr *user.User) (bool, error) {
+	if usr.IsAdmin {
+		return false, nil
+	}
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: usr.ID})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range orgs {
+		if o.OrgID != orgID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *Service) checkUserConflict(ctx context.Context, userID int64, loginOrEmail string) error {
+	other, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: loginOrEmail})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	if other.ID != userID {
+		return scim.ErrUniqueness.Errorf("%q is used by another user", loginOrEmail)
+	}
+	return nil
+}
+
+func (s *Service) setDisabled(ctx context.Context, userID int64, disabled bool) error {
+	if err := s.userService.Disable(ctx, &user.DisableUserCommand{UserID: userID, IsDisabled: disabled}); err != nil {
+		return err
+	}
+	if disabled {
+		return s.tokenService.RevokeAllUserTokens(ctx, userID)
+	}
+	return nil
+}
+
+func (s *Service) toUser(usr *user.User, r *resource) *scim.User {
+	id := strconv.FormatInt(usr.ID, 10)
+	active := !usr.IsDisabled
+	u := &scim.User{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          id,
+		ExternalID:  r.ExternalID,
+		UserName:    usr.Login,
+		DisplayName: usr.Name,
+		Active:      &active,
+		Meta: &scim.Meta{
+			ResourceType: scim.ResourceTypeUser,
+			Created:      usr.Created,
+			LastModified: usr.Updated,
+			Location:     s.location("Users", id),
+		},
+	}
+	if usr.Name != "" {
+		u.Name = &scim.Name{Formatted: usr.Name}
+	}
+	if usr.Email != "" {
+		u.Emails = []scim.Email{{Value: usr.Email, Primary: true}}
+	}
+	return u
+}
+
+func (s *Service) GetGroup(ctx context.Context, orgID int64, id string) (*scim.Group, error) {
+	t, r, err := s.getTeam(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toGroup(ctx, t, r)
+}
+
+func (s *Service) SearchGroups(ctx context.Context, query *scim.SearchQuery) (*scim.SearchGroupsResult, error) {
+	f, err := parseFilter(query.Filter, attrDisplayName, attrExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, limit := pagination(query)
+	result := &scim.SearchGroupsResult{Groups: make([]*scim.Group, 0)}
+
+	var resources []*resource
+	if f != nil {
+		r, err := s.findGroup(ctx, query.OrgID, f)
+		if err != nil || r == nil {
+			return result, err
+		}
+		result.TotalResults = 1
+		if offset == 0 && limit > 0 {
+			resources = append(resources, r)
+		}
+	} else {
+		resources, result.TotalResults, err = s.store.List(ctx, query.OrgID, scim.ResourceTypeGroup, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range resources {
+		g, err := s.GetGroup(ctx, query.OrgID, strconv.FormatInt(r.ResourceID, 10))
+		if err != nil {
+			if errors.Is(err, scim.ErrGroupNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		result.Groups = append(result.Groups, g)
+	}
+	return result, nil
+}
+
+// CreateGroup creates the team, or adopts the existing team with the same name, and sets its members.
+func (s *Service) CreateGroup(ctx context.Context, orgID int64, g *scim.Group) (*scim.Group, error) {
+	if g.DisplayName == "" {
+		return nil, scim.ErrInvalidValue.Errorf("displayName is required")
+	}
+	if err := s.checkExternalID(ctx, orgID, scim.ResourceTypeGroup, 0, g.ExternalID); err != nil {
+		return nil, err
+	}
+	memberIDs, err := s.memberIDs(ctx, orgID, g.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	teamID, err := s.teamIDByName(ctx, orgID, g.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+
+	if teamID != 0 {
+		r, err := s.store.Get(ctx, orgID, scim.ResourceTypeGroup, teamID)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			return nil, scim.ErrUniqueness.Errorf("team %q is already provisioned", g.DisplayName)
+		}
+		s.log.FromContext(ctx).Info("Adopted existing team", "orgId", orgID, "teamId", teamID)
+	} else {
+		t, err := s.teamService.CreateTeam(g.DisplayName, "", orgID)
+		if err != nil {
+			if errors.Is(err, team.ErrTeamNameTaken) {
+				return nil, scim.ErrUniqueness.Errorf("team %q already exists", g.DisplayName)
+			}
+			return nil, err
+		}
+		teamID = t.ID
+		s.log.FromContext(ctx).Info("Created team", "orgId", orgID, "teamId", teamID)
+	}
+
+	r := &resource{OrgID: orgID, ResourceType: scim.ResourceTypeGroup, ResourceID: teamID, ExternalID: g.ExternalID}
+	if err := s.store.Save(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := s.setMembers(ctx, orgID, teamID, memberIDs); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, orgID, strconv.FormatInt(teamID, 10))
+}
+
+func (s *Service) ReplaceGroup(ctx context.Context, orgID int64, id string, g *scim.Group) (*scim.Group, error) {
+	if g.DisplayName == "" {
+		return nil, scim.ErrInvalidValue.Errorf("displayName is required")
+	}
+
+	t, r, err := s.getTeam(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkExternalID(ctx, orgID, scim.ResourceTypeGroup, r.ResourceID, g.ExternalID); err != nil {
+		return nil, err
+	}
+	memberIDs, err := s.memberIDs(ctx, orgID, g.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.DisplayName != t.Name {
+		err := s.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{ID: t.ID, OrgID: orgID, Name: g.DisplayName, Email: t.Email})
+		if err != nil {
+			if errors.Is(err, team.ErrTeamNameTaken) {
+				return nil, scim.ErrUniqueness.Errorf("team %q already exists", g.DisplayName)
+			}
+			return nil, err
+		}
+	}
+
+	r.ExternalID = g.ExternalID
+	if err := s.store.Save(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := s.setMembers(ctx, orgID, t.ID, memberIDs); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, orgID, id)
+}
+
+func (s *Service) PatchGroup(ctx context.Context, orgID int64, id string, ops []scim.PatchOperation) (*scim.Group, error) {
+	g, err := s.GetGroup(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := patchGroup(g, ops); err != nil {
+		return nil, err
+	}
+	return s.ReplaceGroup(ctx, orgID, id, g)
+}
+
+// DeleteGroup deletes the team.
+func (s *Service) DeleteGroup(ctx context.Context, orgID int64, id string) error {
+	t, r, err := s.getTeam(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: orgID, ID: t.ID}); err != nil && !errors.Is(err, team.ErrTeamNotFound) {
+		return err
+	}
+	if err := s.store.Delete(ctx, orgID, scim.ResourceTypeGroup, r.ResourceID); err != nil {
+		return err
+	}
+	s.log.FromContext(ctx).Info("Deleted team", "orgId", orgID, "teamId", t.ID)
+	return nil
+}
+
+func (s *Service) getTeam(ctx context.Context, orgID int64, id string) (*team.TeamDTO, *resource, error) {
+	teamID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, nil, scim.ErrGroupNotFound.Errorf("invalid group id %q", id)
+	}
+
+	r, err := s.store.Get(ctx, orgID, scim.ResourceTypeGroup, teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r == nil {
+		return nil, nil, scim.ErrGroupNotFound.Errorf("team %d is not provisioned in org %d", teamID, orgID)
+	}
+
+	t, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{OrgID: orgID, ID: teamID, SignedInUser: s.backgroundUser(orgID)})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return nil, nil, scim.ErrGroupNotFound.Errorf("team %d not found", teamID)
+		}
+		return nil, nil, err
+	}
+	return t, r, nil
+}
+
+// findGroup returns the resource of the provisioned team matching the filter, or nil.
+func (s *Service) findGroup(ctx context.Context, orgID int64, f *filter) (*resource, error) {
+	if f.value == "" {
+		return nil, nil
+	}
+	if f.attribute == attrExternalID {
+		return s.store.GetByExternalID(ctx, orgID, scim.ResourceTypeGroup, f.value)
+	}
+
+	teamID, err := s.teamIDByName(ctx, orgID, f.value)
+	if err != nil || teamID == 0 {
+		return nil, err
+	}
+	return s.store.Get(ctx, orgID, scim.ResourceTypeGroup, teamID)
+}
+
+// teamIDByName returns 0 when the organization has no team with the name.
+func (s *Service) teamIDByName(ctx context.Context, orgID int64, name string) (int64, error) {
+	result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        orgID,
+		Name:         name,
+		Limit:        1,
+		Page:         1,
+		SignedInUser: s.backgroundUser(orgID),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Teams) == 0 {
+		return 0, nil
+	}
+	return result.Teams[0].ID, nil
+}
+
+// memberIDs returns the ids of the members, which have to be provisioned users of the organization.
+func (s *Service) memberIDs(ctx context.Context, orgID int64, members []scim.Member) ([]int64, error) {
+	ids := make([]int64, 0, len(members))
+	for _, m := range members {
+		if _, _, err := s.getUser(ctx, orgID, m.Value); err != nil {
+			if errors.Is(err, scim.ErrUserNotFound) {
+				return nil, scim.ErrInvalidValue.Errorf("member %q is not a provisioned user", m.Value)
+			}
+			return nil, err
+		}
+		id, _ := strconv.ParseInt(m.Value, 10, 64)
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// setMembers adds and removes the members of the team, so that the users are its only members.
+func (s *Service) setMembers(ctx context.Context, orgID, teamID int64, userIDs []int64) error {
+	current, err := s.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{OrgID: orgID, TeamID: teamID, SignedInUser: s.backgroundUser(orgID)})
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+	existing := make(map[int64]bool, len(current))
+	for _, m := range current {
+		existing[m.UserID] = true
+	}
+
+	teamIDString := strconv.FormatInt(teamID, 10)
+	for _, id := range userIDs {
+		if existing[id] {
+			continue
+		}
+		existing[id] = true
+		if _, err := s.teamPermissions.SetUserPermission(ctx, orgID, ac.User{ID: id}, teamIDString, teamMemberPermission); err != nil {
+			return fmt.Errorf("failed to add user %d to team %d: %w", id, teamID, err)
+		}
+	}
+	for _, m := range current {
+		if wanted[m.UserID] {
+			continue
+		}
+		if _, err := s.teamPermissions.SetUserPermission(ctx, orgID, ac.User{ID: m.UserID}, teamIDString, ""); err != nil {
+			return fmt.Errorf("failed to remove user %d from team %d: %w", m.UserID, teamID, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) toGroup(ctx context.Context, t *team.TeamDTO, r *resource) (*scim.Group, error) {
+	members, err := s.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{OrgID: t.OrgID, TeamID: t.ID, SignedInUser: s.backgroundUser(t.OrgID)})
+	if err != nil {
+		return nil, err
+	}
+
+	id := strconv.FormatInt(t.ID, 10)
+	g := &scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          id,
+		ExternalID:  r.ExternalID,
+		DisplayName: t.Name,
+		Members:     make([]scim.Member, 0, len(members)),
+		Meta: &scim.Meta{
+			ResourceType: scim.ResourceTypeGroup,
+			Created:      r.Created,
+			LastModified: r.Updated,
+			Location:     s.location("Groups", id),
+		},
+	}
+	for _, m := range members {
+		userID := strconv.FormatInt(m.UserID, 10)
+		g.Members = append(g.Members, scim.Member{Value: userID, Display: m.Login, Ref: s.location("Users", userID)})
+	}
+	return g, nil
+}
+
+// checkExternalID returns an error when another resource of the organization has the external id.
+func (s *Service) checkExternalID(ctx context.Context, orgID int64, resourceType string, resourceID int64, externalID string) error {
+	if externalID == "" {
+		return nil
+	}
+	r, err := s.store.GetByExternalID(ctx, orgID, resourceType, externalID)
+	if err != nil {
+		return err
+	}
+	if r != nil && r.ResourceID != resourceID {
+		return scim.ErrUniqueness.Errorf("externalId %q is used by another %s", externalID, strings.ToLower(resourceType))
+	}
+	return nil
+}
+
+// backgroundUser can read the teams and the users of the organization, which the service account
+// calling the SCIM API might not be allowed to.
+func (s *Service) backgroundUser(orgID int64) *user.SignedInUser {
+	return ac.BackgroundUser("scim", orgID, org.RoleAdmin, []ac.Permission{
+		{Action: ac.ActionTeamsRead, Scope: ac.ScopeTeamsAll},
+		{Action: ac.ActionOrgUsersRead, Scope: ac.ScopeUsersAll},
+	})
+}
+
+func (s *Service) location(endpoint, id string) string {
+	return fmt.Sprintf("%sapi/scim/v2/%s/%s", s.cfg.AppURL, endpoint, id)
+}
+
+// pagination converts the 1-based start index and the count of a query to an offset and a limit.
+func pagination(query *scim.SearchQuery) (int, int) {
+	offset := query.StartIndex - 1
+	if offset < 0 {
+		offset = 0
+	}
+	limit := query.Count
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > maxCount {
+		limit = maxCount
+	}
+	return offset, limit
+}
@@ -0,0 +1,324 @@
+package scimimpl
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgimpl"
+	"github.com/grafana/grafana/pkg/services/quota/quotaimpl"
+	"github.com/grafana/grafana/pkg/services/scim"
+	"github.com/grafana/grafana/pkg/services/supportbundles/supportbundlestest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamimpl"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/userimpl"
+)
+
+func TestIntegrationService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	sqlStore.Cfg.AppURL = "http://localhost:3000/"
+	quotaService := quotaimpl.ProvideService(sqlStore, sqlStore.Cfg)
+	orgService, err := orgimpl.ProvideService(sqlStore, sqlStore.Cfg, quotaService)
+	require.NoError(t, err)
+	teamService := teamimpl.ProvideService(sqlStore, sqlStore.Cfg)
+	userService, err := userimpl.ProvideService(sqlStore, orgService, sqlStore.Cfg, teamService, nil, quotaService, supportbundlestest.NewFakeBundleService())
+	require.NoError(t, err)
+
+	orgID, err := orgService.GetOrCreate(ctx, "idp")
+	require.NoError(t, err)
+
+	var revoked []int64
+	tokenService := authtest.NewFakeUserAuthTokenService()
+	tokenService.RevokeAllUserTokensProvider = func(ctx context.Context, userID int64) error {
+		revoked = append(revoked, userID)
+		return nil
+	}
+
+	s := &Service{
+		store:           &xormStore{db: sqlStore, now: time.Now},
+		cfg:             sqlStore.Cfg,
+		userService:     userService,
+		orgService:      orgService,
+		teamService:     teamService,
+		teamPermissions: &fakeTeamPermissions{teamService: teamService},
+		tokenService:    tokenService,
+		log:             log.NewNopLogger(),
+		enabled:         true,
+		defaultOrgRole:  org.RoleEditor,
+	}
+
+	active := func(b bool) *bool { return &b }
+	var jane *scim.User
+
+	t.Run("should create a user in the organization", func(t *testing.T) {
+		jane, err = s.CreateUser(ctx, orgID, &scim.User{
+			ExternalID: "00u1",
+			UserName:   "jane",
+			Name:       &scim.Name{GivenName: "Jane", FamilyName: "Doe"},
+			Emails:     []scim.Email{{Value: "jane@example.com", Primary: true}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "00u1", jane.ExternalID)
+		assert.Equal(t, "Jane Doe", jane.DisplayName)
+		assert.Equal(t, active(true), jane.Active)
+		assert.Equal(t, "http://localhost:3000/api/scim/v2/Users/"+jane.ID, jane.Meta.Location)
+
+		orgUsers, err := orgService.GetOrgUsers(ctx, &org.GetOrgUsersQuery{OrgID: orgID, User: &user.SignedInUser{OrgID: orgID}, DontEnforceAccessControl: true})
+		require.NoError(t, err)
+		require.Len(t, orgUsers, 1)
+		assert.Equal(t, "Editor", orgUsers[0].Role)
+
+		_, err = s.CreateUser(ctx, orgID, &scim.User{UserName: "jane"})
+		assert.ErrorIs(t, err, scim.ErrUniqueness)
+		_, err = s.CreateUser(ctx, orgID, &scim.User{UserName: "john", ExternalID: "00u1"})
+		assert.ErrorIs(t, err, scim.ErrUniqueness)
+	})
+
+	t.Run("should not adopt an existing user that SCIM didn't create", func(t *testing.T) {
+		existing, err := userService.Create(ctx, &user.CreateUserCommand{Login: "erin", Email: "erin@example.com", SkipOrgSetup: true})
+		require.NoError(t, err)
+
+		_, err = s.CreateUser(ctx, orgID, &scim.User{UserName: "erin", Emails: []scim.Email{{Value: "attacker@example.com", Primary: true}}, Active: active(false)})
+		assert.ErrorIs(t, err, scim.ErrUniqueness)
+		_, err = s.CreateUser(ctx, orgID, &scim.User{UserName: "someone", Emails: []scim.Email{{Value: "erin@example.com", Primary: true}}})
+		assert.ErrorIs(t, err, scim.ErrUniqueness)
+
+		usr, err := userService.GetByID(ctx, &user.GetUserByIDQuery{ID: existing.ID})
+		require.NoError(t, err)
+		assert.Equal(t, "erin@example.com", usr.Email)
+		assert.False(t, usr.IsDisabled)
+	})
+
+	t.Run("should not change an existing user that SCIM didn't create", func(t *testing.T) {
+		existing, err := userService.Create(ctx, &user.CreateUserCommand{Login: "frank", Email: "frank@example.com", SkipOrgSetup: true})
+		require.NoError(t, err)
+		// e.g. a user linked to SCIM by an older version
+		require.NoError(t, s.store.Save(ctx, &resource{OrgID: orgID, ResourceType: scim.ResourceTypeUser, ResourceID: existing.ID}))
+		id := strconv.FormatInt(existing.ID, 10)
+
+		_, err = s.ReplaceUser(ctx, orgID, id, &scim.User{UserName: "frank", Emails: []scim.Email{{Value: "attacker@example.com", Primary: true}}})
+		assert.ErrorIs(t, err, scim.ErrUserNotOwned)
+		_, err = s.PatchUser(ctx, orgID, id, []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage(`false`)}})
+		assert.ErrorIs(t, err, scim.ErrUserNotOwned)
+
+		usr, err := userService.GetByID(ctx, &user.GetUserByIDQuery{ID: existing.ID})
+		require.NoError(t, err)
+		assert.Equal(t, "frank@example.com", usr.Email)
+		assert.False(t, usr.IsDisabled)
+		require.NoError(t, s.store.Delete(ctx, orgID, scim.ResourceTypeUser, existing.ID))
+	})
+
+	t.Run("should adopt an existing user when linking existing users is allowed", func(t *testing.T) {
+		s.linkExistingUsers = true
+		t.Cleanup(func() { s.linkExistingUsers = false })
+		existing, err := userService.Create(ctx, &user.CreateUserCommand{Login: "bob", Email: "bob@example.com", SkipOrgSetup: true})
+		require.NoError(t, err)
+
+		bob, err := s.CreateUser(ctx, orgID, &scim.User{UserName: "bob", DisplayName: "Bob"})
+		require.NoError(t, err)
+		assert.Equal(t, strconv.FormatInt(existing.ID, 10), bob.ID)
+		assert.Equal(t, "Bob", bob.DisplayName)
+		assert.Equal(t, []scim.Email{{Value: "bob@example.com", Primary: true}}, bob.Emails)
+	})
+
+	t.Run("should search users", func(t *testing.T) {
+		result, err := s.SearchUsers(ctx, &scim.SearchQuery{OrgID: orgID, StartIndex: 1, Count: 10})
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, result.TotalResults)
+		assert.Len(t, result.Users, 2)
+
+		result, err = s.SearchUsers(ctx, &scim.SearchQuery{OrgID: orgID, Filter: `userName eq "jane"`, StartIndex: 1, Count: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Users, 1)
+		assert.Equal(t, jane.ID, result.Users[0].ID)
+
+		result, err = s.SearchUsers(ctx, &scim.SearchQuery{OrgID: orgID, Filter: `emails.value eq "jane@example.com"`, StartIndex: 1, Count: 10})
+		require.NoError(t, err)
+		assert.Len(t, result.Users, 1)
+
+		result, err = s.SearchUsers(ctx, &scim.SearchQuery{OrgID: orgID, Filter: `externalId eq "unknown"`, StartIndex: 1, Count: 10})
+		require.NoError(t, err)
+		assert.Empty(t, result.Users)
+
+		// users are only resources of the organization that provisioned them
+		result, err = s.SearchUsers(ctx, &scim.SearchQuery{OrgID: orgID + 1, Filter: `userName eq "jane"`, StartIndex: 1, Count: 10})
+		require.NoError(t, err)
+		assert.Empty(t, result.Users)
+	})
+
+	t.Run("should patch a user", func(t *testing.T) {
+		patched, err := s.PatchUser(ctx, orgID, jane.ID, []scim.PatchOperation{
+			{Op: "Replace", Path: "name.givenName", Value: json.RawMessage(`"Janet"`)},
+			{Op: "Replace", Path: "name.familyName", Value: json.RawMessage(`"Doe"`)},
+			{Op: "replace", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"janet@example.com"`)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Janet Doe", patched.DisplayName)
+		assert.Equal(t, "janet@example.com", patched.PrimaryEmail())
+
+		_, err = s.PatchUser(ctx, orgID, jane.ID, []scim.PatchOperation{{Op: "replace", Path: "userName", Value: json.RawMessage(`"bob"`)}})
+		assert.ErrorIs(t, err, scim.ErrUniqueness)
+	})
+
+	t.Run("should disable a user that is no longer active", func(t *testing.T) {
+		patched, err := s.PatchUser(ctx, orgID, jane.ID, []scim.PatchOperation{{Op: "replace", Value: json.RawMessage(`{"active": "False"}`)}})
+		require.NoError(t, err)
+		assert.Equal(t, active(false), patched.Active)
+		assert.Equal(t, []int64{userID(t, jane.ID)}, revoked)
+
+		replaced, err := s.ReplaceUser(ctx, orgID, jane.ID, &scim.User{UserName: "jane", Active: active(true)})
+		require.NoError(t, err)
+		assert.Equal(t, active(true), replaced.Active)
+		assert.Equal(t, "janet@example.com", replaced.PrimaryEmail(), "the email is kept when none is sent")
+	})
+
+	var admins *scim.Group
+
+	t.Run("should create a group with members", func(t *testing.T) {
+		admins, err = s.CreateGroup(ctx, orgID, &scim.Group{DisplayName: "admins", ExternalID: "00g1", Members: []scim.Member{{Value: jane.ID}}})
+		require.NoError(t, err)
+		assert.Equal(t, "admins", admins.DisplayName)
+		require.Len(t, admins.Members, 1)
+		assert.Equal(t, scim.Member{Value: jane.ID, Display: "jane", Ref: jane.Meta.Location}, admins.Members[0])
+
+		_, err = s.CreateGroup(ctx, orgID, &scim.Group{DisplayName: "admins"})
+		assert.ErrorIs(t, err, scim.ErrUniqueness)
+		_, err = s.CreateGroup(ctx, orgID, &scim.Group{DisplayName: "others", Members: []scim.Member{{Value: "999"}}})
+		assert.ErrorIs(t, err, scim.ErrInvalidValue)
+	})
+
+	t.Run("should patch the members of a group", func(t *testing.T) {
+		bob, err := s.SearchUsers(ctx, &scim.SearchQuery{OrgID: orgID, Filter: `userName eq "bob"`, StartIndex: 1, Count: 1})
+		require.NoError(t, err)
+		bobID := bob.Users[0].ID
+
+		patched, err := s.PatchGroup(ctx, orgID, admins.ID, []scim.PatchOperation{
+			{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "` + bobID + `"}]`)},
+			{Op: "remove", Path: `members[value eq "` + jane.ID + `"]`},
+			{Op: "replace", Path: "displayName", Value: json.RawMessage(`"administrators"`)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "administrators", patched.DisplayName)
+		require.Len(t, patched.Members, 1)
+		assert.Equal(t, bobID, patched.Members[0].Value)
+
+		result, err := s.SearchGroups(ctx, &scim.SearchQuery{OrgID: orgID, Filter: `displayName eq "administrators"`, StartIndex: 1, Count: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Groups, 1)
+		assert.Equal(t, "00g1", result.Groups[0].ExternalID)
+	})
+
+	t.Run("should delete a group", func(t *testing.T) {
+		require.NoError(t, s.DeleteGroup(ctx, orgID, admins.ID))
+		_, err := s.GetGroup(ctx, orgID, admins.ID)
+		assert.ErrorIs(t, err, scim.ErrGroupNotFound)
+	})
+
+	t.Run("should disable a deleted user", func(t *testing.T) {
+		require.NoError(t, s.DeleteUser(ctx, orgID, jane.ID))
+		_, err := s.GetUser(ctx, orgID, jane.ID)
+		assert.ErrorIs(t, err, scim.ErrUserNotFound)
+
+		usr, err := userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID(t, jane.ID)})
+		require.NoError(t, err)
+		assert.True(t, usr.IsDisabled)
+
+		// provisioning the user again enables it
+		reprovisioned, err := s.CreateUser(ctx, orgID, &scim.User{UserName: "jane"})
+		require.NoError(t, err)
+		assert.Equal(t, jane.ID, reprovisioned.ID)
+		assert.Equal(t, active(true), reprovisioned.Active)
+	})
+
+	otherOrgID, err := orgService.GetOrCreate(ctx, "other")
+	require.NoError(t, err)
+
+	t.Run("should not adopt a server administrator or a member of another organization", func(t *testing.T) {
+		s.linkExistingUsers = true
+		t.Cleanup(func() { s.linkExistingUsers = false })
+		admin, err := userService.Create(ctx, &user.CreateUserCommand{Login: "root", Email: "root@example.com", IsAdmin: true, SkipOrgSetup: true})
+		require.NoError(t, err)
+		_, err = s.CreateUser(ctx, orgID, &scim.User{UserName: "root", Emails: []scim.Email{{Value: "attacker@example.com", Primary: true}}})
+		assert.ErrorIs(t, err, scim.ErrUniqueness)
+
+		member, err := userService.Create(ctx, &user.CreateUserCommand{Login: "carol", Email: "carol@example.com", SkipOrgSetup: true})
+		require.NoError(t, err)
+		require.NoError(t, orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{OrgID: otherOrgID, UserID: member.ID, Role: org.RoleViewer}))
+		_, err = s.CreateUser(ctx, orgID, &scim.User{UserName: "someone", Emails: []scim.Email{{Value: "carol@example.com", Primary: true}}, Active: active(false)})
+		assert.ErrorIs(t, err, scim.ErrUniqueness)
+
+		for _, id := range []int64{admin.ID, member.ID} {
+			usr, err := userService.GetByID(ctx, &user.GetUserByIDQuery{ID: id})
+			require.NoError(t, err)
+			assert.Contains(t, []string{"root", "carol"}, usr.Login)
+			assert.NotEqual(t, "attacker@example.com", usr.Email)
+			assert.False(t, usr.IsDisabled)
+		}
+	})
+
+	t.Run("should not change a user that joined another organization", func(t *testing.T) {
+		dave, err := s.CreateUser(ctx, orgID, &scim.User{UserName: "dave", Emails: []scim.Email{{Value: "dave@example.com", Primary: true}}})
+		require.NoError(t, err)
+		require.NoError(t, orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{OrgID: otherOrgID, UserID: userID(t, dave.ID), Role: org.RoleViewer}))
+
+		_, err = s.PatchUser(ctx, orgID, dave.ID, []scim.PatchOperation{{Op: "replace", Path: "userName", Value: json.RawMessage(`"david"`)}})
+		assert.ErrorIs(t, err, scim.ErrUserNotOwned)
+		_, err = s.ReplaceUser(ctx, orgID, dave.ID, &scim.User{UserName: "dave", Active: active(false)})
+		assert.ErrorIs(t, err, scim.ErrUserNotOwned)
+		_, err = s.ReplaceUser(ctx, orgID, dave.ID, &scim.User{UserName: "dave", ExternalID: "00u2"})
+		require.NoError(t, err, "unchanged attributes are accepted")
+
+		// deleting the user removes it from the organization, which must keep an admin
+		owner, err := userService.Create(ctx, &user.CreateUserCommand{Login: "owner", SkipOrgSetup: true})
+		require.NoError(t, err)
+		require.NoError(t, orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{OrgID: orgID, UserID: owner.ID, Role: org.RoleAdmin}))
+		require.NoError(t, s.DeleteUser(ctx, orgID, dave.ID))
+		usr, err := userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID(t, dave.ID)})
+		require.NoError(t, err)
+		assert.False(t, usr.IsDisabled)
+		orgs, err := orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: usr.ID})
+		require.NoError(t, err)
+		require.Len(t, orgs, 1)
+		assert.Equal(t, otherOrgID, orgs[0].OrgID)
+	})
+}
+
+func userID(t *testing.T, id string) int64 {
+	t.Helper()
+	userID, err := strconv.ParseInt(id, 10, 64)
+	require.NoError(t, err)
+	return userID
+}
+
+// fakeTeamPermissions manages the team members like the team permissions service does.
+type fakeTeamPermissions struct {
+	ac.TeamPermissionsService
+	teamService team.Service
+}
+
+func (f *fakeTeamPermissions) SetUserPermission(ctx context.Context, orgID int64, u ac.User, resourceID, permission string) (*ac.ResourcePermission, error) {
+	teamID, err := strconv.ParseInt(resourceID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if permission == "" {
+		return nil, f.teamService.RemoveTeamMember(ctx, &team.RemoveTeamMemberCommand{OrgID: orgID, TeamID: teamID, UserID: u.ID})
+	}
+	return &ac.ResourcePermission{}, f.teamService.AddTeamMember(u.ID, orgID, teamID, false, 0)
+}
@@ -0,0 +1,114 @@
+package scimimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store interface {
+	// Get returns nil when the user or the team is not a SCIM resource of the organization.
+	Get(ctx context.Context, orgID int64, resourceType string, resourceID int64) (*resource, error)
+	// GetByExternalID returns nil when no resource of the organization has the external id.
+	GetByExternalID(ctx context.Context, orgID int64, resourceType, externalID string) (*resource, error)
+	List(ctx context.Context, orgID int64, resourceType string, offset, limit int) ([]*resource, int64, error)
+	Save(ctx context.Context, r *resource) error
+	Delete(ctx context.Context, orgID int64, resourceType string, resourceID int64) error
+	// SetManaged records that SCIM manages the user, it does nothing when SCIM already manages it.
+	SetManaged(ctx context.Context, userID int64) error
+	IsManaged(ctx context.Context, userID int64) (bool, error)
+}
+
+type xormStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *xormStore) Get(ctx context.Context, orgID int64, resourceType string, resourceID int64) (*resource, error) {
+	var result *resource
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		r := resource{}
+		has, err := sess.Where("org_id = ? AND resource_type = ? AND resource_id = ?", orgID, resourceType, resourceID).Get(&r)
+		if err != nil {
+			return err
+		}
+		if has {
+			result = &r
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *xormStore) GetByExternalID(ctx context.Context, orgID int64, resourceType, externalID string) (*resource, error) {
+	var result *resource
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		r := resource{}
+		has, err := sess.Where("org_id = ? AND resource_type = ? AND external_id = ?", orgID, resourceType, externalID).Get(&r)
+		if err != nil {
+			return err
+		}
+		if has {
+			result = &r
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *xormStore) List(ctx context.Context, orgID int64, resourceType string, offset, limit int) ([]*resource, int64, error) {
+	result := make([]*resource, 0)
+	var total int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		total, err = sess.Where("org_id = ? AND resource_type = ?", orgID, resourceType).Count(&resource{})
+		if err != nil {
+			return err
+		}
+		return sess.Where("org_id = ? AND resource_type = ?", orgID, resourceType).
+			OrderBy("resource_id").Limit(limit, offset).Find(&result)
+	})
+	return result, total, err
+}
+
+func (s *xormStore) Save(ctx context.Context, r *resource) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		r.Updated = s.now()
+		if r.ID == 0 {
+			r.Created = r.Updated
+			_, err := sess.Insert(r)
+			return err
+		}
+		_, err := sess.ID(r.ID).AllCols().Update(r)
+		return err
+	})
+}
+
+func (s *xormStore) Delete(ctx context.Context, orgID int64, resourceType string, resourceID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM scim_resource WHERE org_id = ? AND resource_type = ? AND resource_id = ?", orgID, resourceType, resourceID)
+		return err
+	})
+}
+
+func (s *xormStore) SetManaged(ctx context.Context, userID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("user_id = ?", userID).Exist(&managedUser{})
+		if err != nil || has {
+			return err
+		}
+		_, err = sess.Insert(&managedUser{UserID: userID, Created: s.now()})
+		return err
+	})
+}
+
+func (s *xormStore) IsManaged(ctx context.Context, userID int64) (bool, error) {
+	var has bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		has, err = sess.Where("user_id = ?", userID).Exist(&managedUser{})
+		return err
+	})
+	return has, err
+}
@@ -48,6 +48,10 @@ func (f FakeSecretsService) ReEncryptDataKeys(_ context.Context) error {
 	return nil
 }
 
+func (f FakeSecretsService) RotateKeyEncryptionKey(_ context.Context) error {
+	return nil
+}
+
 func (f FakeSecretsService) CurrentProviderID() string {
 	return "fakeProvider"
 }
@@ -489,6 +489,29 @@ func (s *SecretsService) ReEncryptDataKeys(ctx context.Context) error {
 	return nil
 }
 
+func (s *SecretsService) RotateKeyEncryptionKey(ctx context.Context) error {
+	s.log.Info("Key encryption key rotation triggered", "provider", s.currentProviderID)
+
+	if err := s.InitProviders(); err != nil {
+		s.log.Error("Envelope encryption providers initialization failed", "error", err)
+		return err
+	}
+
+	rotator, ok := s.providers[s.currentProviderID].(secrets.KeyRotator)
+	if !ok {
+		return fmt.Errorf("%w: %s", secrets.ErrKeyRotationNotSupported, s.currentProviderID)
+	}
+
+	if err := rotator.RotateKey(ctx); err != nil {
+		s.log.Error("Key encryption key rotation failed", "provider", s.currentProviderID, "error", err)
+		return err
+	}
+
+	// Data keys are still encrypted with the previous version of the key encryption key,
+	// which the provider keeps to decrypt them, until they get re-encrypted.
+	return s.ReEncryptDataKeys(ctx)
+}
+
 func (s *SecretsService) Run(ctx context.Context) error {
 	gc := time.NewTicker(
 		s.cfg.SectionWithEnvOverrides("security.encryption").Key("data_keys_cache_cleanup_interval").
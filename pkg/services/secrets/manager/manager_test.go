@@ -362,6 +362,81 @@ func TestSecretsService_ReEncryptDataKeys(t *testing.T) {
 	})
 }
 
+type fakeRotatingProvider struct {
+	version byte
+}
+
+func (p *fakeRotatingProvider) Encrypt(_ context.Context, blob []byte) ([]byte, error) {
+	return append([]byte{p.version}, blob...), nil
+}
+
+func (p *fakeRotatingProvider) Decrypt(_ context.Context, blob []byte) ([]byte, error) {
+	return blob[1:], nil
+}
+
+func (p *fakeRotatingProvider) RotateKey(_ context.Context) error {
+	p.version++
+	return nil
+}
+
+type fakeRotatingKMS struct {
+	kms  osskmsproviders.Service
+	fake *fakeRotatingProvider
+}
+
+func (f *fakeRotatingKMS) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
+	providers, err := f.kms.Provide()
+	if err != nil {
+		return providers, err
+	}
+
+	providers["fakeRotating.v1"] = f.fake
+	return providers, nil
+}
+
+func TestSecretsService_RotateKeyEncryptionKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should fail when the current provider does not support key rotation", func(t *testing.T) {
+		svc := SetupTestService(t, database.ProvideSecretsStore(db.InitTestDB(t)))
+
+		err := svc.RotateKeyEncryptionKey(ctx)
+		assert.ErrorIs(t, err, secrets.ErrKeyRotationNotSupported)
+	})
+
+	t.Run("should rotate the key encryption key and re-encrypt data keys", func(t *testing.T) {
+		raw, err := ini.Load([]byte(`
+		[security]
+		secret_key = sdDkslslld
+		encryption_provider = fakeRotating.v1`))
+		require.NoError(t, err)
+		cfg := &setting.Cfg{Raw: raw}
+
+		encryptionService, err := encryptionservice.ProvideEncryptionService(encryptionprovider.Provider{}, &usagestats.UsageStatsMock{}, cfg)
+		require.NoError(t, err)
+
+		features := featuremgmt.WithFeatures()
+		kms := &fakeRotatingKMS{kms: osskmsproviders.ProvideService(encryptionService, cfg, features), fake: &fakeRotatingProvider{version: 1}}
+		store := database.ProvideSecretsStore(db.InitTestDB(t))
+		svc, err := ProvideSecretsService(store, kms, encryptionService, cfg, features, &usagestats.UsageStatsMock{T: t})
+		require.NoError(t, err)
+
+		encrypted, err := svc.Encrypt(ctx, []byte("grafana"), secrets.WithoutScope())
+		require.NoError(t, err)
+
+		require.NoError(t, svc.RotateKeyEncryptionKey(ctx))
+
+		dataKeys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, dataKeys, 1)
+		assert.Equal(t, byte(2), dataKeys[0].EncryptedData[0], "data key should be encrypted with the new key version")
+
+		decrypted, err := svc.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("grafana"), decrypted)
+	})
+}
+
 func TestSecretsService_Decrypt(t *testing.T) {
 	ctx := context.Background()
 	testDB := db.InitTestDB(t)
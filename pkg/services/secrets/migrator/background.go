@@ -0,0 +1,239 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+const (
+	defaultBatchSize = 100
+
+	reEncryptionLockName = "secrets re-encryption"
+	// reEncryptionLockTimeout is long enough to re-encrypt a batch of secrets, the lock is renewed after each batch,
+	// and short enough to start a new re-encryption soon after an instance died while re-encrypting secrets.
+	// The interval between the batches is added to it.
+	reEncryptionLockTimeout = 5 * time.Minute
+)
+
+// batchSecret is implemented by the secrets that can be re-encrypted by batches of rows while Grafana is running.
+// The rows that get updated while being re-encrypted are left untouched.
+type batchSecret interface {
+	secretColumn() (table string, column string)
+	reEncryptRow(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB, row secretRow) error
+}
+
+// Run re-encrypts secrets in the background, each time a re-encryption is started.
+func (m *SecretsMigrator) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-m.reEncryptTrigger:
+			m.runReEncryption(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (m *SecretsMigrator) StartReEncryption() error {
+	m.statusMtx.Lock()
+	defer m.statusMtx.Unlock()
+
+	if m.status.Running {
+		return secrets.ErrReEncryptionRunning
+	}
+
+	select {
+	case m.reEncryptTrigger <- struct{}{}:
+	default:
+		return secrets.ErrReEncryptionRunning
+	}
+
+	startedAt := time.Now()
+	m.status = secrets.ReEncryptionStatus{Running: true, StartedAt: &startedAt, Secrets: []secrets.ReEncryptionProgress{}}
+	return nil
+}
+
+func (m *SecretsMigrator) ReEncryptionStatus() secrets.ReEncryptionStatus {
+	m.statusMtx.Lock()
+	defer m.statusMtx.Unlock()
+
+	status := m.status
+	status.Secrets = append([]secrets.ReEncryptionProgress{}, m.status.Secrets...)
+	return status
+}
+
+func (m *SecretsMigrator) runReEncryption(ctx context.Context) {
+	logger.Info("Background secrets re-encryption started")
+
+	err := m.initProvidersIfNeeded()
+	if err == nil {
+		// Only one instance re-encrypts secrets at a time.
+		lockErr := m.serverLock.LockExecuteAndRelease(ctx, reEncryptionLockName, reEncryptionLockTimeout+m.batchInterval, func(ctx context.Context) {
+			err = m.reEncryptInBatches(ctx)
+		})
+		var lockExists *serverlock.ServerLockExistsError
+		if errors.As(lockErr, &lockExists) {
+			err = errors.New("secrets are being re-encrypted by another instance")
+		} else if lockErr != nil {
+			err = lockErr
+		}
+	}
+
+	m.statusMtx.Lock()
+	defer m.statusMtx.Unlock()
+
+	finishedAt := time.Now()
+	m.status.Running = false
+	m.status.FinishedAt = &finishedAt
+	if err != nil {
+		m.status.Error = err.Error()
+		logger.Error("Background secrets re-encryption failed", "error", err)
+		return
+	}
+
+	var failed int64
+	for _, progress := range m.status.Secrets {
+		failed += progress.Failed
+	}
+	if failed > 0 {
+		logger.Warn("Background secrets re-encryption finished with errors", "failed", failed)
+		return
+	}
+	logger.Info("Background secrets re-encryption finished successfully", "duration", finishedAt.Sub(*m.status.StartedAt))
+}
+
+// reEncryptInBatches re-encrypts the secrets of every rotator, in order. The secrets of the rotators that can't
+// re-encrypt them by batches, such as the data keys, are re-encrypted at once and count as one secret.
+func (m *SecretsMigrator) reEncryptInBatches(ctx context.Context) error {
+	progress := make([]secrets.ReEncryptionProgress, 0, len(m.rotators))
+
+	for _, r := range m.rotators {
+		bs, ok := r.(batchSecret)
+		if !ok {
+			progress = append(progress, secrets.ReEncryptionProgress{Name: rotatorName(r), Total: 1})
+			continue
+		}
+
+		table, column := bs.secretColumn()
+		var total int64
+		if err := m.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			total, err = sess.Table(table).Count()
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to count the secrets of %s: %w", table, err)
+		}
+
+		progress = append(progress, secrets.ReEncryptionProgress{Name: fmt.Sprintf("%s.%s", table, column), Total: total})
+	}
+
+	m.statusMtx.Lock()
+	m.status.Secrets = progress
+	m.statusMtx.Unlock()
+
+	for i, r := range m.rotators {
+		var err error
+		if bs, ok := r.(batchSecret); ok {
+			err = m.reEncryptBatches(ctx, i, bs)
+		} else {
+			err = m.reEncryptAtOnce(ctx, i, r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reEncryptAtOnce re-encrypts the secrets of a rotator that can't re-encrypt them by batches.
+func (m *SecretsMigrator) reEncryptAtOnce(ctx context.Context, idx int, r SecretsRotator) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	success := r.ReEncrypt(ctx, m.secretsSrv, m.sqlStore)
+
+	m.statusMtx.Lock()
+	progress := &m.status.Secrets[idx]
+	progress.Processed = 1
+	if !success {
+		progress.Failed = 1
+	}
+	logger.Info("Re-encrypting secrets", "secrets", progress.Name, "processed", progress.Processed, "total", progress.Total, "failed", progress.Failed)
+	m.statusMtx.Unlock()
+
+	return m.renewLock(ctx)
+}
+
+// renewLock keeps the re-encryption lock of this instance, it fails if another instance may have taken it.
+func (m *SecretsMigrator) renewLock(ctx context.Context) error {
+	if err := m.serverLock.RenewLock(ctx, reEncryptionLockName); err != nil {
+		return fmt.Errorf("failed to renew the re-encryption lock: %w", err)
+	}
+	return nil
+}
+
+// rotatorName returns the name of the secrets of a rotator that can't re-encrypt them by batches.
+func rotatorName(r SecretsRotator) string {
+	if _, ok := r.(dataKeys); ok {
+		return "data_keys"
+	}
+	return fmt.Sprintf("%T", r)
+}
+
+// reEncryptBatches re-encrypts the secrets of a table column by batches of rows, ordered by id.
+func (m *SecretsMigrator) reEncryptBatches(ctx context.Context, idx int, bs batchSecret) error {
+	table, column := bs.secretColumn()
+	var afterID int64
+
+	for {
+		var rows []secretRow
+		if err := m.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.Table(table).Select(fmt.Sprintf("id, %s as secret", column)).
+				Where("id > ?", afterID).OrderBy("id").Limit(m.batchSize).Find(&rows)
+		}); err != nil {
+			return fmt.Errorf("failed to find the secrets of %s to re-encrypt: %w", table, err)
+		}
+
+		var failed int64
+		for _, row := range rows {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if len(row.Secret) == 0 {
+				continue
+			}
+			if err := bs.reEncryptRow(ctx, m.secretsSrv, m.sqlStore, row); err != nil {
+				failed++
+			}
+		}
+
+		m.statusMtx.Lock()
+		progress := &m.status.Secrets[idx]
+		progress.Processed += int64(len(rows))
+		progress.Failed += failed
+		logger.Info("Re-encrypting secrets", "secrets", progress.Name, "processed", progress.Processed, "total", progress.Total, "failed", progress.Failed)
+		m.statusMtx.Unlock()
+
+		if err := m.renewLock(ctx); err != nil {
+			return err
+		}
+		if len(rows) < m.batchSize {
+			return nil
+		}
+		afterID = rows[len(rows)-1].Id
+
+		select {
+		case <-time.After(m.batchInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
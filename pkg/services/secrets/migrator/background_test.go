@@ -0,0 +1,129 @@
+package migrator
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	encryptionservice "github.com/grafana/grafana/pkg/services/encryption/service"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationSecretsMigrator_BackgroundReEncryption(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	secretsSrv := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	cfg := setting.NewCfg()
+	cfg.Raw.Section("security.encryption").Key("reencryption_batch_size").SetValue("2")
+	cfg.Raw.Section("security.encryption").Key("reencryption_batch_interval").SetValue("1ms")
+
+	m := ProvideSecretsMigrator(
+		encryptionservice.SetupTestService(t),
+		secretsSrv,
+		sqlStore,
+		setting.ProvideProvider(cfg),
+		featuremgmt.WithFeatures(),
+		serverlock.ProvideService(sqlStore, tracing.InitializeTracerForTest()),
+	)
+
+	previous := make(map[int64][]byte)
+	for i := 0; i < 3; i++ {
+		encrypted, err := secretsSrv.EncryptJsonData(ctx, map[string]string{"password": fmt.Sprintf("secret-%d", i)}, secrets.WithoutScope())
+		require.NoError(t, err)
+
+		ds := &datasources.DataSource{
+			OrgID: 1, Name: fmt.Sprintf("ds-%d", i), UID: fmt.Sprintf("ds-%d", i), Type: "prometheus", Access: datasources.DS_ACCESS_PROXY,
+			SecureJsonData: encrypted, Created: time.Now(), Updated: time.Now(),
+		}
+		require.NoError(t, sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Insert(ds)
+			return err
+		}))
+		previous[ds.ID] = encrypted["password"]
+	}
+
+	encrypted, err := secretsSrv.Encrypt(ctx, []byte("kvstore-secret"), secrets.WithoutScope())
+	require.NoError(t, err)
+	require.NoError(t, sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("INSERT INTO secrets (org_id, namespace, type, value, created, updated) VALUES (?, ?, ?, ?, ?, ?)",
+			1, "ns", "type", base64.RawStdEncoding.EncodeToString(encrypted), time.Now(), time.Now())
+		return err
+	}))
+
+	snapshot, err := secretsSrv.Encrypt(ctx, []byte(`{"title": "snapshot"}`), secrets.WithoutScope())
+	require.NoError(t, err)
+	require.NoError(t, sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("INSERT INTO dashboard_snapshot (name, "+sqlStore.GetDialect().Quote("key")+", delete_key, org_id, user_id, external, external_url, dashboard, dashboard_encrypted, expires, created, updated) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			"snapshot", "key", "delete-key", 1, 1, false, "", "", snapshot, time.Now().Add(time.Hour), time.Now(), time.Now())
+		return err
+	}))
+
+	require.NoError(t, m.StartReEncryption())
+	assert.ErrorIs(t, m.StartReEncryption(), secrets.ErrReEncryptionRunning)
+	assert.True(t, m.ReEncryptionStatus().Running)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+	go func() { _ = m.Run(runCtx) }()
+
+	require.Eventually(t, func() bool { return !m.ReEncryptionStatus().Running }, 10*time.Second, 10*time.Millisecond)
+
+	status := m.ReEncryptionStatus()
+	assert.Empty(t, status.Error)
+	assert.NotNil(t, status.FinishedAt)
+
+	progress := make(map[string]secrets.ReEncryptionProgress)
+	for _, p := range status.Secrets {
+		progress[p.Name] = p
+	}
+	assert.Equal(t, secrets.ReEncryptionProgress{Name: "data_source.secure_json_data", Total: 3, Processed: 3}, progress["data_source.secure_json_data"])
+	assert.Equal(t, secrets.ReEncryptionProgress{Name: "secrets.value", Total: 1, Processed: 1}, progress["secrets.value"])
+	assert.Contains(t, progress, "alert_configuration.alertmanager_configuration")
+	// every rotator is re-encrypted, the data keys at once
+	require.Len(t, status.Secrets, len(m.rotators))
+	assert.Equal(t, secrets.ReEncryptionProgress{Name: "data_keys", Total: 1, Processed: 1}, progress["data_keys"])
+	assert.Equal(t, secrets.ReEncryptionProgress{Name: "dashboard_snapshot.dashboard_encrypted", Total: 1, Processed: 1}, progress["dashboard_snapshot.dashboard_encrypted"])
+
+	var snapshots []secretRow
+	require.NoError(t, sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("dashboard_snapshot").Select("id, dashboard_encrypted as secret").Find(&snapshots)
+	}))
+	require.Len(t, snapshots, 1)
+	assert.NotEqual(t, string(snapshot), snapshots[0].Secret)
+	decrypted, err := secretsSrv.Decrypt(ctx, []byte(snapshots[0].Secret))
+	require.NoError(t, err)
+	assert.Equal(t, `{"title": "snapshot"}`, string(decrypted))
+
+	var dataSources []*datasources.DataSource
+	require.NoError(t, sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Find(&dataSources)
+	}))
+	require.Len(t, dataSources, 3)
+	for _, ds := range dataSources {
+		assert.NotEqual(t, previous[ds.ID], ds.SecureJsonData["password"])
+		decrypted, err := secretsSrv.DecryptJsonData(ctx, ds.SecureJsonData)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("secret-%d", ds.ID-1), decrypted["password"])
+	}
+
+	require.NoError(t, m.StartReEncryption(), "should start again once finished")
+	require.Eventually(t, func() bool { return !m.ReEncryptionStatus().Running }, 10*time.Second, 10*time.Millisecond)
+}
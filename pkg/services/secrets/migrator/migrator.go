@@ -3,12 +3,16 @@ package migrator
 import (
 	"context"
 	"encoding/base64"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/manager"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -24,7 +28,14 @@ type SecretsMigrator struct {
 	sqlStore      db.DB
 	settings      setting.Provider
 	features      featuremgmt.FeatureToggles
+	serverLock    *serverlock.ServerLockService
 	rotators      []SecretsRotator
+
+	batchSize        int
+	batchInterval    time.Duration
+	reEncryptTrigger chan struct{}
+	statusMtx        sync.Mutex
+	status           secrets.ReEncryptionStatus
 }
 
 func ProvideSecretsMigrator(
@@ -33,8 +44,11 @@ func ProvideSecretsMigrator(
 	sqlStore db.DB,
 	settings setting.Provider,
 	features featuremgmt.FeatureToggles,
+	serverLock *serverlock.ServerLockService,
 ) *SecretsMigrator {
 	rotators := []SecretsRotator{
+		// Data keys go first, so that secrets are re-encrypted with data keys protected by the current provider.
+		dataKeys{},
 		simpleSecret{tableName: "dashboard_snapshot", columnName: "dashboard_encrypted"},
 		b64Secret{simpleSecret: simpleSecret{tableName: "user_auth", columnName: "o_auth_access_token"}, encoding: base64.StdEncoding},
 		b64Secret{simpleSecret: simpleSecret{tableName: "user_auth", columnName: "o_auth_refresh_token"}, encoding: base64.StdEncoding},
@@ -46,13 +60,22 @@ func ProvideSecretsMigrator(
 		alertingSecret{},
 	}
 
+	batchSize, err := strconv.Atoi(settings.KeyValue("security.encryption", "reencryption_batch_size").MustString("100"))
+	if err != nil || batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
 	return &SecretsMigrator{
-		encryptionSrv: encryptionSrv,
-		secretsSrv:    service,
-		sqlStore:      sqlStore,
-		settings:      settings,
-		features:      features,
-		rotators:      rotators,
+		encryptionSrv:    encryptionSrv,
+		secretsSrv:       service,
+		sqlStore:         sqlStore,
+		settings:         settings,
+		features:         features,
+		serverLock:       serverLock,
+		rotators:         rotators,
+		batchSize:        batchSize,
+		batchInterval:    settings.KeyValue("security.encryption", "reencryption_batch_interval").MustDuration(time.Second),
+		reEncryptTrigger: make(chan struct{}, 1),
 	}
 }
 
@@ -156,6 +179,13 @@ type jsonSecret struct {
 
 type alertingSecret struct{}
 
+type dataKeys struct{}
+
+type secretRow struct {
+	Id     int64
+	Secret string
+}
+
 func nowInUTC() string {
 	return time.Now().UTC().Format("2006-01-02 15:04:05")
 }
@@ -2,6 +2,7 @@ package migrator
 
 import (
 	"context"
+	"database/sql"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -72,11 +73,45 @@ func (s simpleSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.Secrets
 	return !anyFailure
 }
 
+func (s simpleSecret) secretColumn() (string, string) {
+	return s.tableName, s.columnName
+}
+
+func (s simpleSecret) reEncryptRow(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB, row secretRow) error {
+	err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		decrypted, err := secretsSrv.Decrypt(ctx, []byte(row.Secret))
+		if err != nil {
+			logger.Warn("Could not decrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		encrypted, err := secretsSrv.Encrypt(ctx, decrypted, secrets.WithoutScope())
+		if err != nil {
+			logger.Warn("Could not encrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		updateSQL := fmt.Sprintf("UPDATE %s SET %s = ?, updated = ? WHERE id = ? AND %s = ?", s.tableName, s.columnName, s.columnName)
+		if err = sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			res, err := sess.Exec(updateSQL, encrypted, nowInUTC(), row.Id, []byte(row.Secret))
+			if err != nil {
+				return err
+			}
+			logIfUpdatedMeanwhile(res, s.tableName, row.Id)
+			return nil
+		}); err != nil {
+			logger.Warn("Could not update secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		return nil
+	})
+
+	return err
+}
+
 func (s b64Secret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB) bool {
-	var rows []struct {
-		Id     int
-		Secret string
-	}
+	var rows []secretRow
 
 	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		return sess.Table(s.tableName).Select(fmt.Sprintf("id, %s as secret", s.columnName)).Find(&rows)
@@ -92,44 +127,7 @@ func (s b64Secret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsSer
 			continue
 		}
 
-		err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
-			decoded, err := s.encoding.DecodeString(row.Secret)
-			if err != nil {
-				logger.Warn("Could not decode base64-encoded secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
-				return err
-			}
-
-			decrypted, err := secretsSrv.Decrypt(ctx, decoded)
-			if err != nil {
-				logger.Warn("Could not decrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
-				return err
-			}
-
-			encrypted, err := secretsSrv.Encrypt(ctx, decrypted, secrets.WithoutScope())
-			if err != nil {
-				logger.Warn("Could not encrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
-				return err
-			}
-
-			if err = sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) (err error) {
-				encoded := s.encoding.EncodeToString(encrypted)
-				if s.hasUpdatedColumn {
-					updateSQL := fmt.Sprintf("UPDATE %s SET %s = ?, updated = ? WHERE id = ?", s.tableName, s.columnName)
-					_, err = sess.Exec(updateSQL, encoded, nowInUTC(), row.Id)
-				} else {
-					updateSQL := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?", s.tableName, s.columnName)
-					_, err = sess.Exec(updateSQL, encoded, row.Id)
-				}
-				return
-			}); err != nil {
-				logger.Warn("Could not update secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
-				return err
-			}
-
-			return nil
-		})
-
-		if err != nil {
+		if err := s.reEncryptRow(ctx, secretsSrv, sqlStore, row); err != nil {
 			anyFailure = true
 		}
 	}
@@ -143,14 +141,61 @@ func (s b64Secret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsSer
 	return !anyFailure
 }
 
+func (s b64Secret) secretColumn() (string, string) {
+	return s.tableName, s.columnName
+}
+
+func (s b64Secret) reEncryptRow(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB, row secretRow) error {
+	err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		decoded, err := s.encoding.DecodeString(row.Secret)
+		if err != nil {
+			logger.Warn("Could not decode base64-encoded secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		decrypted, err := secretsSrv.Decrypt(ctx, decoded)
+		if err != nil {
+			logger.Warn("Could not decrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		encrypted, err := secretsSrv.Encrypt(ctx, decrypted, secrets.WithoutScope())
+		if err != nil {
+			logger.Warn("Could not encrypt secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		if err = sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) (err error) {
+			encoded := s.encoding.EncodeToString(encrypted)
+			var res sql.Result
+			if s.hasUpdatedColumn {
+				updateSQL := fmt.Sprintf("UPDATE %s SET %s = ?, updated = ? WHERE id = ? AND %s = ?", s.tableName, s.columnName, s.columnName)
+				res, err = sess.Exec(updateSQL, encoded, nowInUTC(), row.Id, row.Secret)
+			} else {
+				updateSQL := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ? AND %s = ?", s.tableName, s.columnName, s.columnName)
+				res, err = sess.Exec(updateSQL, encoded, row.Id, row.Secret)
+			}
+			if err != nil {
+				return err
+			}
+			logIfUpdatedMeanwhile(res, s.tableName, row.Id)
+			return nil
+		}); err != nil {
+			logger.Warn("Could not update secret while re-encrypting it", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		return nil
+	})
+
+	return err
+}
+
 func (s jsonSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB) bool {
-	var rows []struct {
-		Id             int
-		SecureJsonData map[string][]byte
-	}
+	var rows []secretRow
 
 	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
-		return sess.Table(s.tableName).Cols("id", "secure_json_data").Find(&rows)
+		return sess.Table(s.tableName).Select("id, secure_json_data as secret").Find(&rows)
 	}); err != nil {
 		logger.Warn("Could not find any secret to re-encrypt", "table", s.tableName)
 		return false
@@ -159,40 +204,11 @@ func (s jsonSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsSe
 	var anyFailure bool
 
 	for _, row := range rows {
-		if len(row.SecureJsonData) == 0 {
+		if len(row.Secret) == 0 {
 			continue
 		}
 
-		err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
-			decrypted, err := secretsSrv.DecryptJsonData(ctx, row.SecureJsonData)
-			if err != nil {
-				logger.Warn("Could not decrypt secrets while re-encrypting them", "table", s.tableName, "id", row.Id, "error", err)
-				return err
-			}
-
-			toUpdate := struct {
-				SecureJsonData map[string][]byte
-				Updated        string
-			}{Updated: nowInUTC()}
-
-			toUpdate.SecureJsonData, err = secretsSrv.EncryptJsonData(ctx, decrypted, secrets.WithoutScope())
-			if err != nil {
-				logger.Warn("Could not re-encrypt secrets", "table", s.tableName, "id", row.Id, "error", err)
-				return err
-			}
-
-			if err := sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
-				_, err := sess.Table(s.tableName).Where("id = ?", row.Id).Update(toUpdate)
-				return err
-			}); err != nil {
-				logger.Warn("Could not update secrets while re-encrypting them", "table", s.tableName, "id", row.Id, "error", err)
-				return err
-			}
-
-			return nil
-		})
-
-		if err != nil {
+		if err := s.reEncryptRow(ctx, secretsSrv, sqlStore, row); err != nil {
 			anyFailure = true
 		}
 	}
@@ -206,15 +222,65 @@ func (s jsonSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsSe
 	return !anyFailure
 }
 
-func (s alertingSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB) bool {
-	var results []struct {
-		Id                        int
-		AlertmanagerConfiguration string
+func (s jsonSecret) secretColumn() (string, string) {
+	return s.tableName, "secure_json_data"
+}
+
+func (s jsonSecret) reEncryptRow(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB, row secretRow) error {
+	var secureJsonData map[string][]byte
+	if err := json.Unmarshal([]byte(row.Secret), &secureJsonData); err != nil {
+		logger.Warn("Could not decode secrets while re-encrypting them", "table", s.tableName, "id", row.Id, "error", err)
+		return err
 	}
 
-	selectSQL := "SELECT id, alertmanager_configuration FROM alert_configuration"
+	if len(secureJsonData) == 0 {
+		return nil
+	}
+
+	err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		decrypted, err := secretsSrv.DecryptJsonData(ctx, secureJsonData)
+		if err != nil {
+			logger.Warn("Could not decrypt secrets while re-encrypting them", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		reEncrypted, err := secretsSrv.EncryptJsonData(ctx, decrypted, secrets.WithoutScope())
+		if err != nil {
+			logger.Warn("Could not re-encrypt secrets", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		encoded, err := json.Marshal(reEncrypted)
+		if err != nil {
+			logger.Warn("Could not encode secrets while re-encrypting them", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		if err := sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			updateSQL := fmt.Sprintf("UPDATE %s SET secure_json_data = ?, updated = ? WHERE id = ? AND secure_json_data = ?", s.tableName)
+			res, err := sess.Exec(updateSQL, string(encoded), nowInUTC(), row.Id, row.Secret)
+			if err != nil {
+				return err
+			}
+			logIfUpdatedMeanwhile(res, s.tableName, row.Id)
+			return nil
+		}); err != nil {
+			logger.Warn("Could not update secrets while re-encrypting them", "table", s.tableName, "id", row.Id, "error", err)
+			return err
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (s alertingSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB) bool {
+	var rows []secretRow
+
+	selectSQL := "SELECT id, alertmanager_configuration AS secret FROM alert_configuration"
 	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
-		return sess.SQL(selectSQL).Find(&results)
+		return sess.SQL(selectSQL).Find(&rows)
 	}); err != nil {
 		logger.Warn("Could not find any alert_configuration secret to re-encrypt")
 		return false
@@ -222,70 +288,100 @@ func (s alertingSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.Secre
 
 	var anyFailure bool
 
-	for _, result := range results {
-		result := result
+	for _, row := range rows {
+		if err := s.reEncryptRow(ctx, secretsSrv, sqlStore, row); err != nil {
+			anyFailure = true
+		}
+	}
 
-		err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
-			postableUserConfig, err := notifier.Load([]byte(result.AlertmanagerConfiguration))
-			if err != nil {
-				logger.Warn("Could not load alert_configuration while re-encrypting it", "id", result.Id, "error", err)
-				return err
-			}
+	if anyFailure {
+		logger.Warn("Alerting configuration secrets have been re-encrypted with errors")
+	} else {
+		logger.Info("Alerting configuration secrets have been re-encrypted successfully")
+	}
+
+	return !anyFailure
+}
+
+func (s alertingSecret) secretColumn() (string, string) {
+	return "alert_configuration", "alertmanager_configuration"
+}
+
+func (s alertingSecret) reEncryptRow(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB, row secretRow) error {
+	err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		postableUserConfig, err := notifier.Load([]byte(row.Secret))
+		if err != nil {
+			logger.Warn("Could not load alert_configuration while re-encrypting it", "id", row.Id, "error", err)
+			return err
+		}
+
+		for _, receiver := range postableUserConfig.AlertmanagerConfig.Receivers {
+			for _, gmr := range receiver.GrafanaManagedReceivers {
+				for k, v := range gmr.SecureSettings {
+					decoded, err := base64.StdEncoding.DecodeString(v)
+					if err != nil {
+						logger.Warn("Could not decode base64-encoded alert_configuration secret", "id", row.Id, "key", k, "error", err)
+						return err
+					}
+
+					decrypted, err := secretsSrv.Decrypt(ctx, decoded)
+					if err != nil {
+						logger.Warn("Could not decrypt alert_configuration secret", "id", row.Id, "key", k, "error", err)
+						return err
+					}
 
-			for _, receiver := range postableUserConfig.AlertmanagerConfig.Receivers {
-				for _, gmr := range receiver.GrafanaManagedReceivers {
-					for k, v := range gmr.SecureSettings {
-						decoded, err := base64.StdEncoding.DecodeString(v)
-						if err != nil {
-							logger.Warn("Could not decode base64-encoded alert_configuration secret", "id", result.Id, "key", k, "error", err)
-							return err
-						}
-
-						decrypted, err := secretsSrv.Decrypt(ctx, decoded)
-						if err != nil {
-							logger.Warn("Could not decrypt alert_configuration secret", "id", result.Id, "key", k, "error", err)
-							return err
-						}
-
-						reencrypted, err := secretsSrv.Encrypt(ctx, decrypted, secrets.WithoutScope())
-						if err != nil {
-							logger.Warn("Could not re-encrypt alert_configuration secret", "id", result.Id, "key", k, "error", err)
-							return err
-						}
-
-						gmr.SecureSettings[k] = base64.StdEncoding.EncodeToString(reencrypted)
+					reencrypted, err := secretsSrv.Encrypt(ctx, decrypted, secrets.WithoutScope())
+					if err != nil {
+						logger.Warn("Could not re-encrypt alert_configuration secret", "id", row.Id, "key", k, "error", err)
+						return err
 					}
+
+					gmr.SecureSettings[k] = base64.StdEncoding.EncodeToString(reencrypted)
 				}
 			}
+		}
+
+		marshalled, err := json.Marshal(postableUserConfig)
+		if err != nil {
+			logger.Warn("Could not marshal alert_configuration while re-encrypting it", "id", row.Id, "error", err)
+			return err
+		}
 
-			marshalled, err := json.Marshal(postableUserConfig)
+		if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			updateSQL := "UPDATE alert_configuration SET alertmanager_configuration = ? WHERE id = ? AND alertmanager_configuration = ?"
+			res, err := sess.Exec(updateSQL, string(marshalled), row.Id, row.Secret)
 			if err != nil {
-				logger.Warn("Could not marshal alert_configuration while re-encrypting it", "id", result.Id, "error", err)
 				return err
 			}
+			logIfUpdatedMeanwhile(res, "alert_configuration", row.Id)
+			return nil
+		}); err != nil {
+			logger.Warn("Could not update alert_configuration secret while re-encrypting it", "id", row.Id, "error", err)
+			return err
+		}
 
-			result.AlertmanagerConfiguration = string(marshalled)
-			if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
-				_, err := sess.Table("alert_configuration").Where("id = ?", result.Id).Update(&result)
-				return err
-			}); err != nil {
-				logger.Warn("Could not update alert_configuration secret while re-encrypting it", "id", result.Id, "error", err)
-				return err
-			}
+		return nil
+	})
 
-			return nil
-		})
+	return err
+}
 
-		if err != nil {
-			anyFailure = true
-		}
+// logIfUpdatedMeanwhile reports a row that has been updated since it was read to be re-encrypted, and that
+// therefore has been left untouched: its secrets have been encrypted with the current encryption by the update.
+func logIfUpdatedMeanwhile(res sql.Result, table string, id int64) {
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		logger.Debug("Secret has been updated while re-encrypting it, skipping", "table", table, "id", id)
 	}
+}
 
-	if anyFailure {
-		logger.Warn("Alerting configuration secrets have been re-encrypted with errors")
-	} else {
-		logger.Info("Alerting configuration secrets have been re-encrypted successfully")
+// ReEncrypt re-encrypts the data keys with the current provider, which also moves them to the latest
+// version of its key encryption key after a key rotation.
+func (dataKeys) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsService, _ db.DB) bool {
+	if err := secretsSrv.ReEncryptDataKeys(ctx); err != nil {
+		logger.Warn("Could not re-encrypt data keys", "error", err)
+		return false
 	}
 
-	return !anyFailure
+	logger.Info("Data keys have been re-encrypted successfully")
+	return true
 }
@@ -294,3 +294,8 @@ func (s alertingSecret) Rollback(
 
 	return anyFailure
 }
+
+// Rollback has nothing to do for data keys, which are deleted once every secret has been rolled back.
+func (dataKeys) Rollback(context.Context, *manager.SecretsService, encryption.Internal, db.DB, string) (anyFailure bool) {
+	return false
+}
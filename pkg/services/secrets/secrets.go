@@ -26,6 +26,9 @@ type Service interface {
 
 	RotateDataKeys(ctx context.Context) error
 	ReEncryptDataKeys(ctx context.Context) error
+	// RotateKeyEncryptionKey rotates the key encryption key of the current provider
+	// and re-encrypts the data keys with its new version.
+	RotateKeyEncryptionKey(ctx context.Context) error
 }
 
 // Store defines methods to interact with secrets storage
@@ -67,6 +70,12 @@ type BackgroundProvider interface {
 	Run(ctx context.Context) error
 }
 
+// KeyRotator should be implemented for a provider whose key encryption key can be rotated, like the keys held by an
+// external key manager. Data keys encrypted with a previous version of the key must remain decryptable.
+type KeyRotator interface {
+	RotateKey(ctx context.Context) error
+}
+
 // Migrator is responsible for secrets migrations like re-encrypting or rolling back secrets.
 type Migrator interface {
 	// ReEncryptSecrets decrypts and re-encrypts the secrets with most recent
@@ -79,4 +88,10 @@ type Migrator interface {
 	// does not stop, but returns false as the first return (success or not)
 	// at the end of the process.
 	RollBackSecrets(ctx context.Context) (bool, error)
+	// StartReEncryption starts re-encrypting secrets in the background, by batches,
+	// so that Grafana keeps serving requests meanwhile. It returns ErrReEncryptionRunning
+	// if a background re-encryption is already running.
+	StartReEncryption() error
+	// ReEncryptionStatus returns the progress of the latest background re-encryption.
+	ReEncryptionStatus() ReEncryptionStatus
 }
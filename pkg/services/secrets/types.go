@@ -5,7 +5,11 @@ import (
 	"time"
 )
 
-var ErrDataKeyNotFound = errors.New("data key not found")
+var (
+	ErrDataKeyNotFound         = errors.New("data key not found")
+	ErrKeyRotationNotSupported = errors.New("current encryption provider does not support key rotation")
+	ErrReEncryptionRunning     = errors.New("secrets re-encryption is already running")
+)
 
 type DataKey struct {
 	Active        bool
@@ -35,3 +39,20 @@ func WithScope(scope string) EncryptionOptions {
 		return scope
 	}
 }
+
+// ReEncryptionStatus is the progress of a background re-encryption of secrets.
+type ReEncryptionStatus struct {
+	Running    bool                   `json:"running"`
+	StartedAt  *time.Time             `json:"startedAt,omitempty"`
+	FinishedAt *time.Time             `json:"finishedAt,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Secrets    []ReEncryptionProgress `json:"secrets"`
+}
+
+// ReEncryptionProgress is the progress of the re-encryption of the secrets stored in a table column.
+type ReEncryptionProgress struct {
+	Name      string `json:"name"`
+	Total     int64  `json:"total"`
+	Processed int64  `json:"processed"`
+	Failed    int64  `json:"failed"`
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/audit/audittest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -309,6 +310,7 @@ func setupTests(t *testing.T, opts ...func(a *ServiceAccountsAPI)) *webtest.Serv
 		RouterRegister:       routing.NewRouteRegister(),
 		log:                  log.NewNopLogger(),
 		permissionService:    &actest.FakePermissionsService{},
+		auditService:         audittest.NewFakeService(),
 	}
 
 	for _, o := range opts {
@@ -328,6 +330,8 @@ type fakeServiceAccountService struct {
 	ExpectedServiceAccount        *serviceaccounts.ServiceAccountDTO
 	ExpectedServiceAccountProfile *serviceaccounts.ServiceAccountProfileDTO
 	ExpectedMigrationResult       *serviceaccounts.MigrationResult
+	ExpectedTokenPolicy           *serviceaccounts.TokenPolicy
+	ExpectedSecondsToLive         int64
 }
 
 func (f *fakeServiceAccountService) CreateServiceAccount(ctx context.Context, orgID int64, saForm *serviceaccounts.CreateServiceAccountForm) (*serviceaccounts.ServiceAccountDTO, error) {
@@ -358,6 +362,23 @@ func (f *fakeServiceAccountService) DeleteServiceAccountToken(ctx context.Contex
 	return f.ExpectedErr
 }
 
+func (f *fakeServiceAccountService) RotateServiceAccountToken(ctx context.Context, id int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*serviceaccounts.RotateServiceAccountTokenResult, error) {
+	return &serviceaccounts.RotateServiceAccountTokenResult{Token: f.ExpectedAPIKey, RotatedToken: f.ExpectedAPIKey}, f.ExpectedErr
+}
+
+func (f *fakeServiceAccountService) RotationSecondsToLive(ctx context.Context, orgID, id, tokenID int64) (int64, error) {
+	return f.ExpectedSecondsToLive, f.ExpectedErr
+}
+
+func (f *fakeServiceAccountService) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	return f.ExpectedTokenPolicy, f.ExpectedErr
+}
+
+func (f *fakeServiceAccountService) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	f.ExpectedTokenPolicy = policy
+	return f.ExpectedErr
+}
+
 func (f *fakeServiceAccountService) MigrateApiKeysToServiceAccounts(ctx context.Context, orgID int64) (*serviceaccounts.MigrationResult, error) {
 	fmt.Printf("fake migration result: %v", f.ExpectedMigrationResult)
 	return f.ExpectedMigrationResult, f.ExpectedErr
@@ -8,6 +8,8 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/satokengen"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/web"
@@ -36,6 +38,8 @@ type TokenDTO struct {
 	HasExpired bool `json:"hasExpired"`
 	// example: false
 	IsRevoked *bool `json:"isRevoked"`
+	// Permissions the token is restricted to, the token has all the permissions of the service account when empty.
+	Permissions apikey.Permissions `json:"permissions,omitempty"`
 }
 
 func hasExpired(expiration *int64) bool {
@@ -79,35 +83,39 @@ func (api *ServiceAccountsAPI) ListTokens(ctx *contextmodel.ReqContext) response
 	}
 
 	result := make([]TokenDTO, len(saTokens))
-	for i, t := range saTokens {
-		var (
-			token                             = t // pin pointer
-			expiration             *time.Time = nil
-			secondsUntilExpiration float64    = 0
-		)
-
-		isExpired := hasExpired(t.Expires)
-		if t.Expires != nil {
-			v := time.Unix(*t.Expires, 0)
-			expiration = &v
-			if !isExpired && (*expiration).Before(time.Now().Add(sevenDaysAhead)) {
-				secondsUntilExpiration = time.Until(*expiration).Seconds()
-			}
-		}
+	for i := range saTokens {
+		result[i] = toTokenDTO(&saTokens[i])
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
 
-		result[i] = TokenDTO{
-			Id:                     token.ID,
-			Name:                   token.Name,
-			Created:                &token.Created,
-			Expiration:             expiration,
-			SecondsUntilExpiration: &secondsUntilExpiration,
-			HasExpired:             isExpired,
-			LastUsedAt:             token.LastUsedAt,
-			IsRevoked:              token.IsRevoked,
+func toTokenDTO(token *apikey.APIKey) TokenDTO {
+	var (
+		expiration             *time.Time = nil
+		secondsUntilExpiration float64    = 0
+	)
+
+	isExpired := hasExpired(token.Expires)
+	if token.Expires != nil {
+		v := time.Unix(*token.Expires, 0)
+		expiration = &v
+		if !isExpired && (*expiration).Before(time.Now().Add(sevenDaysAhead)) {
+			secondsUntilExpiration = time.Until(*expiration).Seconds()
 		}
 	}
 
-	return response.JSON(http.StatusOK, result)
+	return TokenDTO{
+		Id:                     token.ID,
+		Name:                   token.Name,
+		Created:                &token.Created,
+		Expiration:             expiration,
+		SecondsUntilExpiration: &secondsUntilExpiration,
+		HasExpired:             isExpired,
+		LastUsedAt:             token.LastUsedAt,
+		IsRevoked:              token.IsRevoked,
+		Permissions:            token.Permissions,
+	}
 }
 
 // swagger:route POST /serviceaccounts/{serviceAccountId}/tokens service_accounts createToken
@@ -144,21 +152,8 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 	// Force affected service account to be the one referenced in the URL
 	cmd.OrgId = c.SignedInUser.GetOrgID()
 
-	if api.cfg.ApiKeyMaxSecondsToLive != -1 {
-		if cmd.SecondsToLive == 0 {
-			return response.Error(http.StatusBadRequest, "Number of seconds before expiration should be set", nil)
-		}
-		if cmd.SecondsToLive > api.cfg.ApiKeyMaxSecondsToLive {
-			return response.Error(http.StatusBadRequest, "Number of seconds before expiration is greater than the global limit", nil)
-		}
-	}
-
-	if api.cfg.SATokenExpirationDayLimit > 0 {
-		dayExpireLimit := time.Now().Add(time.Duration(api.cfg.SATokenExpirationDayLimit) * time.Hour * 24).Truncate(24 * time.Hour)
-		expirationDate := time.Now().Add(time.Duration(cmd.SecondsToLive) * time.Second).Truncate(24 * time.Hour)
-		if expirationDate.After(dayExpireLimit) {
-			return response.Respond(http.StatusBadRequest, "The expiration date input exceeds the limit for service account access tokens expiration date")
-		}
+	if resp := api.validateSecondsToLive(cmd.SecondsToLive); resp != nil {
+		return resp
 	}
 
 	newKeyInfo, err := satokengen.New(ServiceID)
@@ -173,6 +168,13 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to add service account token", err)
 	}
 
+	api.auditService.Log(c.Req.Context(), tokenAuditEvent(c, audit.ActionCreate, apiKey.ID, nil, audit.Summary{
+		"name":             apiKey.Name,
+		"serviceAccountId": saID,
+		"expires":          apiKey.Expires,
+		"permissions":      apiKey.Permissions,
+	}))
+
 	result := &dtos.NewApiKeyResult{
 		ID:   apiKey.ID,
 		Name: apiKey.Name,
@@ -218,9 +220,175 @@ func (api *ServiceAccountsAPI) DeleteToken(c *contextmodel.ReqContext) response.
 		return response.ErrOrFallback(http.StatusInternalServerError, failedToDeleteMsg, err)
 	}
 
+	api.auditService.Log(c.Req.Context(), tokenAuditEvent(c, audit.ActionDelete, tokenID, audit.Summary{"serviceAccountId": saID}, nil))
+
 	return response.Success("Service account token deleted")
 }
 
+// validateSecondsToLive checks the lifetime of a new token against the global limits.
+func (api *ServiceAccountsAPI) validateSecondsToLive(secondsToLive int64) response.Response {
+	if api.cfg.ApiKeyMaxSecondsToLive != -1 {
+		if secondsToLive == 0 {
+			return response.Error(http.StatusBadRequest, "Number of seconds before expiration should be set", nil)
+		}
+		if secondsToLive > api.cfg.ApiKeyMaxSecondsToLive {
+			return response.Error(http.StatusBadRequest, "Number of seconds before expiration is greater than the global limit", nil)
+		}
+	}
+
+	if api.cfg.SATokenExpirationDayLimit > 0 {
+		if secondsToLive <= 0 {
+			return response.Error(http.StatusBadRequest, "Number of seconds before expiration should be set", nil)
+		}
+		dayExpireLimit := time.Now().Add(time.Duration(api.cfg.SATokenExpirationDayLimit) * time.Hour * 24).Truncate(24 * time.Hour)
+		expirationDate := time.Now().Add(time.Duration(secondsToLive) * time.Second).Truncate(24 * time.Hour)
+		if expirationDate.After(dayExpireLimit) {
+			return response.Respond(http.StatusBadRequest, "The expiration date input exceeds the limit for service account access tokens expiration date")
+		}
+	}
+
+	return nil
+}
+
+// swagger:route POST /serviceaccounts/{serviceAccountId}/tokens/{tokenId}/rotate service_accounts rotateToken
+//
+// # RotateToken replaces a service account token with a new token
+//
+// The rotated token is revoked, or stays valid for the overlap window when `overlapSeconds` is set. The new token
+// takes the name of the rotated token, which is renamed, unless another name is set.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:write` scope: `serviceaccounts:id:1` (single service account)
+//
+// Responses:
+// 200: rotateTokenResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (api *ServiceAccountsAPI) RotateToken(c *contextmodel.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+
+	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Token ID is invalid", err)
+	}
+
+	cmd := serviceaccounts.RotateServiceAccountTokenCommand{}
+	if err = web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	// Force affected token to be the one referenced in the URL
+	cmd.OrgId = c.SignedInUser.GetOrgID()
+	cmd.TokenId = tokenID
+
+	// The lifetime of the rotated token is reused when none is set, it is checked against the global limits too.
+	if cmd.SecondsToLive == 0 {
+		cmd.SecondsToLive, err = api.service.RotationSecondsToLive(c.Req.Context(), cmd.OrgId, saID, tokenID)
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to rotate service account token", err)
+		}
+	}
+	if resp := api.validateSecondsToLive(cmd.SecondsToLive); resp != nil {
+		return resp
+	}
+
+	newKeyInfo, err := satokengen.New(ServiceID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Generating service account token failed", err)
+	}
+
+	cmd.Key = newKeyInfo.HashedKey
+
+	result, err := api.service.RotateServiceAccountToken(c.Req.Context(), saID, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to rotate service account token", err)
+	}
+
+	api.auditService.Log(c.Req.Context(), tokenAuditEvent(c, audit.ActionCreate, result.Token.ID, nil, audit.Summary{
+		"name":             result.Token.Name,
+		"serviceAccountId": saID,
+		"expires":          result.Token.Expires,
+		"rotatedTokenId":   tokenID,
+	}))
+	api.auditService.Log(c.Req.Context(), tokenAuditEvent(c, audit.ActionUpdate, tokenID, audit.Summary{"serviceAccountId": saID}, audit.Summary{
+		"name":      result.RotatedToken.Name,
+		"expires":   result.RotatedToken.Expires,
+		"isRevoked": result.RotatedToken.IsRevoked,
+	}))
+
+	return response.JSON(http.StatusOK, &RotateTokenResult{
+		ID:           result.Token.ID,
+		Name:         result.Token.Name,
+		Key:          newKeyInfo.ClientSecret,
+		RotatedToken: toTokenDTO(result.RotatedToken),
+	})
+}
+
+// swagger:route GET /serviceaccounts/token-policy service_accounts getTokenPolicy
+//
+// # Get the service account token policy of the organization
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:read` scope: `serviceaccounts:*`
+//
+// Responses:
+// 200: tokenPolicyResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (api *ServiceAccountsAPI) GetTokenPolicy(c *contextmodel.ReqContext) response.Response {
+	policy, err := api.service.GetTokenPolicy(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get token policy", err)
+	}
+	if policy.WarningRecipients == nil {
+		policy.WarningRecipients = []string{}
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route PUT /serviceaccounts/token-policy service_accounts updateTokenPolicy
+//
+// # Update the service account token policy of the organization
+//
+// The maximum lifetime applies to the tokens that are added after the update.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:write` scope: `serviceaccounts:*`
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (api *ServiceAccountsAPI) UpdateTokenPolicy(c *contextmodel.ReqContext) response.Response {
+	policy := serviceaccounts.TokenPolicy{}
+	if err := web.Bind(c.Req, &policy); err != nil {
+		return response.Error(http.StatusBadRequest, "Bad request data", err)
+	}
+
+	if err := api.service.UpdateTokenPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), &policy); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update token policy", err)
+	}
+
+	return response.Success("Service account token policy updated")
+}
+
+func tokenAuditEvent(c *contextmodel.ReqContext, action audit.Action, tokenID int64, before, after audit.Summary) audit.Event {
+	resource := audit.Resource(c.SignedInUser.GetOrgID(), audit.KindServiceAccountToken, strconv.FormatInt(tokenID, 10))
+	event := audit.NewEvent(c.SignedInUser, c.Req, action, resource)
+	event.Before = before
+	event.After = after
+	return event
+}
+
 // swagger:parameters listTokens
 type ListTokensParams struct {
 	// in:path
@@ -243,6 +411,46 @@ type DeleteTokenParams struct {
 	ServiceAccountId int64 `json:"serviceAccountId"`
 }
 
+// swagger:parameters rotateToken
+type RotateTokenParams struct {
+	// in:path
+	TokenId int64 `json:"tokenId"`
+	// in:path
+	ServiceAccountId int64 `json:"serviceAccountId"`
+	// in:body
+	Body serviceaccounts.RotateServiceAccountTokenCommand
+}
+
+// swagger:parameters updateTokenPolicy
+type UpdateTokenPolicyParams struct {
+	// in:body
+	Body serviceaccounts.TokenPolicy
+}
+
+// swagger:model
+type RotateTokenResult struct {
+	// example: 2
+	ID int64 `json:"id"`
+	// example: grafana
+	Name string `json:"name"`
+	// example: glsa_yscW25imSKJIuav8zF37RZmnbiDvB05G_fcaaf58a
+	Key string `json:"key"`
+	// The rotated token as it is after the rotation.
+	RotatedToken TokenDTO `json:"rotatedToken"`
+}
+
+// swagger:response rotateTokenResponse
+type RotateTokenResponse struct {
+	// in:body
+	Body *RotateTokenResult
+}
+
+// swagger:response tokenPolicyResponse
+type TokenPolicyResponse struct {
+	// in:body
+	Body *serviceaccounts.TokenPolicy
+}
+
 // swagger:response listTokensResponse
 type ListTokensResponse struct {
 	// in:body
@@ -176,3 +176,165 @@ func TestServiceAccountsAPI_DeleteToken(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAccountsAPI_RotateToken(t *testing.T) {
+	type TestCase struct {
+		desc         string
+		saID         int64
+		body         string
+		permissions  []accesscontrol.Permission
+		tokenTTL     int64
+		dayLimit     int
+		rotatedTTL   int64
+		expectedErr  error
+		expectedCode int
+	}
+
+	tests := []TestCase{
+		{
+			desc:         "should be able to rotate service account token with correct permission",
+			saID:         1,
+			body:         `{"overlapSeconds": 3600}`,
+			tokenTTL:     -1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not be able to rotate service account token with wrong permission",
+			saID:         2,
+			body:         `{}`,
+			tokenTTL:     -1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not be able to rotate service account token if max ttl is configured but not set in body",
+			saID:         1,
+			body:         `{}`,
+			tokenTTL:     10 * int64(time.Hour),
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should be able to rotate service account token with the ttl of the rotated token if max ttl is configured",
+			saID:         1,
+			body:         `{}`,
+			tokenTTL:     7200,
+			rotatedTTL:   3600,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not be able to rotate service account token if the ttl of the rotated token is greater than max ttl",
+			saID:         1,
+			body:         `{}`,
+			tokenTTL:     1800,
+			rotatedTTL:   3600,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should not be able to rotate service account token if the expiration day limit is configured but no ttl is set in body",
+			saID:         1,
+			body:         `{}`,
+			tokenTTL:     -1,
+			dayLimit:     30,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should not be able to rotate service account token beyond the expiration day limit",
+			saID:         1,
+			body:         `{"secondsToLive": 8640000}`,
+			tokenTTL:     -1,
+			dayLimit:     30,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should be able to rotate service account token within the expiration day limit",
+			saID:         1,
+			body:         `{"secondsToLive": 86400}`,
+			tokenTTL:     -1,
+			dayLimit:     30,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not be able to rotate service account token that does not comply with the token policy",
+			saID:         1,
+			body:         `{"secondsToLive": 86400}`,
+			tokenTTL:     -1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedErr:  serviceaccounts.ErrTokenPolicyViolation.Errorf(""),
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := setupTests(t, func(a *ServiceAccountsAPI) {
+				a.cfg.ApiKeyMaxSecondsToLive = tt.tokenTTL
+				a.cfg.SATokenExpirationDayLimit = tt.dayLimit
+				a.service = &fakeServiceAccountService{ExpectedErr: tt.expectedErr, ExpectedAPIKey: &apikey.APIKey{ID: 1, Name: "test"}, ExpectedSecondsToLive: tt.rotatedTTL}
+			})
+
+			req := server.NewRequest(http.MethodPost, fmt.Sprintf("/api/serviceaccounts/%d/tokens/1/rotate", tt.saID), strings.NewReader(tt.body))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(tt.permissions)}})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestServiceAccountsAPI_TokenPolicy(t *testing.T) {
+	type TestCase struct {
+		desc         string
+		method       string
+		body         string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}
+
+	tests := []TestCase{
+		{
+			desc:         "should be able to get the token policy with correct permission",
+			method:       http.MethodGet,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionRead, Scope: serviceaccounts.ScopeAll}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should be able to update the token policy with correct permission",
+			method:       http.MethodPut,
+			body:         `{"maxSecondsToLive": 86400, "expiryWarningSeconds": 3600}`,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: serviceaccounts.ScopeAll}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not be able to update the token policy with a single service account permission",
+			method:       http.MethodPut,
+			body:         `{"maxSecondsToLive": 86400}`,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := setupTests(t, func(a *ServiceAccountsAPI) {
+				a.service = &fakeServiceAccountService{ExpectedTokenPolicy: &serviceaccounts.TokenPolicy{}}
+			})
+
+			req := server.NewRequest(tt.method, "/api/serviceaccounts/token-policy", strings.NewReader(tt.body))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(tt.permissions)}})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
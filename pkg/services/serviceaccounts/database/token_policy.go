@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+const (
+	kvNamespace          = "serviceaccounts"
+	kvTokenPolicyKey     = "token-policy"
+	kvExpiryWarningsKey  = "token-expiry-warnings"
+	maxTokenNameLength   = 190
+	rotatedTokenNameTime = "20060102150405"
+)
+
+// GetTokenPolicy returns the token policy of the organization, an empty policy if none is set.
+func (s *ServiceAccountsStoreImpl) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	policy := &serviceaccounts.TokenPolicy{}
+	value, ok, err := kvstore.WithNamespace(s.kvStore, orgID, kvNamespace).Get(ctx, kvTokenPolicyKey)
+	if err != nil || !ok {
+		return policy, err
+	}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("failed to decode token policy of organization %d: %w", orgID, err)
+	}
+	return policy, nil
+}
+
+// ListTokenPolicies returns the token policies of the organizations that have one.
+func (s *ServiceAccountsStoreImpl) ListTokenPolicies(ctx context.Context) (map[int64]*serviceaccounts.TokenPolicy, error) {
+	items, err := kvstore.WithNamespace(s.kvStore, kvstore.AllOrganizations, kvNamespace).GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[int64]*serviceaccounts.TokenPolicy, len(items))
+	for orgID, values := range items {
+		value, ok := values[kvTokenPolicyKey]
+		if !ok {
+			continue
+		}
+		policy := &serviceaccounts.TokenPolicy{}
+		if err := json.Unmarshal([]byte(value), policy); err != nil {
+			s.log.Warn("Skipping invalid token policy", "orgId", orgID, "error", err)
+			continue
+		}
+		policies[orgID] = policy
+	}
+	return policies, nil
+}
+
+func (s *ServiceAccountsStoreImpl) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return kvstore.WithNamespace(s.kvStore, orgID, kvNamespace).Set(ctx, kvTokenPolicyKey, string(value))
+}
+
+// GetExpiryWarnings returns the expiration of the tokens of the organization that a warning has been sent for, by token ID.
+func (s *ServiceAccountsStoreImpl) GetExpiryWarnings(ctx context.Context, orgID int64) (map[int64]int64, error) {
+	warnings := map[int64]int64{}
+	value, ok, err := kvstore.WithNamespace(s.kvStore, orgID, kvNamespace).Get(ctx, kvExpiryWarningsKey)
+	if err != nil || !ok {
+		return warnings, err
+	}
+
+	stored := map[string]int64{}
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode token expiry warnings of organization %d: %w", orgID, err)
+	}
+	for id, expires := range stored {
+		tokenID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+		warnings[tokenID] = expires
+	}
+	return warnings, nil
+}
+
+func (s *ServiceAccountsStoreImpl) UpdateExpiryWarnings(ctx context.Context, orgID int64, warnings map[int64]int64) error {
+	kv := kvstore.WithNamespace(s.kvStore, orgID, kvNamespace)
+	if len(warnings) == 0 {
+		return kv.Del(ctx, kvExpiryWarningsKey)
+	}
+
+	stored := make(map[string]int64, len(warnings))
+	for tokenID, expires := range warnings {
+		stored[strconv.FormatInt(tokenID, 10)] = expires
+	}
+	value, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return kv.Set(ctx, kvExpiryWarningsKey, string(value))
+}
+
+// ListExpiringTokens returns the valid service account tokens of the organization that expire before the given time.
+func (s *ServiceAccountsStoreImpl) ListExpiringTokens(ctx context.Context, orgID int64, before time.Time) ([]*serviceaccounts.ExpiringToken, error) {
+	result := make([]*serviceaccounts.ExpiringToken, 0)
+	err := s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		dialect := s.sqlStore.GetDialect()
+		quotedUser := dialect.Quote("user")
+		rawSQL := "SELECT api_key.id, api_key.org_id, api_key.name, api_key.expires, api_key.service_account_id, " +
+			quotedUser + ".name AS service_account_name FROM api_key" +
+			" INNER JOIN " + quotedUser + " ON " + quotedUser + ".id = api_key.service_account_id" +
+			" WHERE api_key.org_id = ? AND api_key.expires IS NOT NULL AND api_key.expires > ? AND api_key.expires <= ?" +
+			" AND (api_key.is_revoked IS NULL OR api_key.is_revoked = " + dialect.BooleanStr(false) + ")" +
+			" ORDER BY api_key.expires"
+		return sess.SQL(rawSQL, orgID, time.Now().Unix(), before.Unix()).Find(&result)
+	})
+	return result, err
+}
+
+// RetrieveServiceAccountToken returns a token of the service account.
+func (s *ServiceAccountsStoreImpl) RetrieveServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) (*apikey.APIKey, error) {
+	var token apikey.APIKey
+	err := s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("id=? AND org_id=? AND service_account_id=?", tokenID, orgID, serviceAccountID).Get(&token)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return serviceaccounts.ErrServiceAccountTokenNotFound.Errorf("service account token with id %d not found for service account with id %d", tokenID, serviceAccountID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateServiceAccountToken adds a token that replaces an existing token of the service account, and revokes the
+// replaced token or shortens its expiration to the overlap window. Both changes are made in a single transaction.
+func (s *ServiceAccountsStoreImpl) RotateServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*serviceaccounts.RotateServiceAccountTokenResult, error) {
+	var result *serviceaccounts.RotateServiceAccountTokenResult
+
+	err := s.sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+		rotated, err := s.RetrieveServiceAccountToken(ctx, cmd.OrgId, serviceAccountID, cmd.TokenId)
+		if err != nil {
+			return err
+		}
+		if rotated.IsRevoked != nil && *rotated.IsRevoked {
+			return serviceaccounts.ErrServiceAccountTokenRevoked.Errorf("service account token with id %d is revoked", cmd.TokenId)
+		}
+
+		now := time.Now()
+		name := cmd.Name
+		if name == "" {
+			name = rotated.Name
+		}
+		// The new token usually replaces the rotated token under the same name, and token names are unique.
+		if name == rotated.Name {
+			rotated.Name = rotatedTokenName(rotated.Name, now)
+		}
+
+		if cmd.OverlapSeconds == 0 {
+			isRevoked := true
+			rotated.IsRevoked = &isRevoked
+		} else if expires := now.Add(time.Duration(cmd.OverlapSeconds) * time.Second).Unix(); rotated.Expires == nil || *rotated.Expires > expires {
+			rotated.Expires = &expires
+		}
+		rotated.Updated = now
+
+		if err := s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.ID(rotated.ID).Cols("name", "expires", "is_revoked", "updated").Update(rotated)
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to update rotated token: %w", err)
+		}
+
+		token, err := s.AddServiceAccountToken(ctx, serviceAccountID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:          name,
+			OrgId:         cmd.OrgId,
+			Key:           cmd.Key,
+			SecondsToLive: cmd.SecondsToLive,
+			// The new token is restricted like the token it replaces.
+			Permissions: rotated.Permissions,
+		})
+		if err != nil {
+			return err
+		}
+
+		result = &serviceaccounts.RotateServiceAccountTokenResult{Token: token, RotatedToken: rotated}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func rotatedTokenName(name string, now time.Time) string {
+	suffix := "-rotated-" + now.UTC().Format(rotatedTokenNameTime)
+	if len(name)+len(suffix) > maxTokenNameLength {
+		name = name[:maxTokenNameLength-len(suffix)]
+	}
+	return name + suffix
+}
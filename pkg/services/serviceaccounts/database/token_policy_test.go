@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+)
+
+func TestIntegrationStore_RotateServiceAccountToken(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, tests.TestUser{Name: "Rotation", Login: "sa-rotation", IsServiceAccount: true})
+
+	addToken := func(t *testing.T, name string, secondsToLive int64) int64 {
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+		token, err := store.AddServiceAccountToken(ctx, sa.ID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name: name, OrgId: sa.OrgID, Key: key.HashedKey, SecondsToLive: secondsToLive,
+		})
+		require.NoError(t, err)
+		return token.ID
+	}
+
+	rotate := func(t *testing.T, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*serviceaccounts.RotateServiceAccountTokenResult, error) {
+		key, err := apikeygen.New(sa.OrgID, "rotated")
+		require.NoError(t, err)
+		cmd.OrgId = sa.OrgID
+		cmd.Key = key.HashedKey
+		return store.RotateServiceAccountToken(ctx, sa.ID, cmd)
+	}
+
+	t.Run("should revoke the rotated token", func(t *testing.T) {
+		tokenID := addToken(t, "ci", 0)
+
+		result, err := rotate(t, &serviceaccounts.RotateServiceAccountTokenCommand{TokenId: tokenID, SecondsToLive: 3600})
+		require.NoError(t, err)
+		assert.Equal(t, "ci", result.Token.Name)
+		assert.NotNil(t, result.Token.Expires)
+
+		rotated, err := store.RetrieveServiceAccountToken(ctx, sa.OrgID, sa.ID, tokenID)
+		require.NoError(t, err)
+		assert.True(t, *rotated.IsRevoked)
+		assert.Contains(t, rotated.Name, "ci-rotated-")
+
+		_, err = rotate(t, &serviceaccounts.RotateServiceAccountTokenCommand{TokenId: tokenID})
+		assert.ErrorIs(t, err, serviceaccounts.ErrServiceAccountTokenRevoked)
+	})
+
+	t.Run("should keep the rotated token valid during the overlap", func(t *testing.T) {
+		tokenID := addToken(t, "deploy", 0)
+
+		result, err := rotate(t, &serviceaccounts.RotateServiceAccountTokenCommand{TokenId: tokenID, Name: "deploy-v2", OverlapSeconds: 600})
+		require.NoError(t, err)
+		assert.Equal(t, "deploy-v2", result.Token.Name)
+
+		rotated, err := store.RetrieveServiceAccountToken(ctx, sa.OrgID, sa.ID, tokenID)
+		require.NoError(t, err)
+		assert.Equal(t, "deploy", rotated.Name)
+		assert.False(t, *rotated.IsRevoked)
+		require.NotNil(t, rotated.Expires)
+		assert.InDelta(t, time.Now().Add(10*time.Minute).Unix(), *rotated.Expires, 5)
+	})
+
+	t.Run("should leave the rotated token unchanged when the new token can't be added", func(t *testing.T) {
+		tokenID := addToken(t, "backup", 0)
+		addToken(t, "taken", 0)
+
+		_, err := rotate(t, &serviceaccounts.RotateServiceAccountTokenCommand{TokenId: tokenID, Name: "taken"})
+		assert.ErrorIs(t, err, serviceaccounts.ErrDuplicateToken)
+
+		rotated, err := store.RetrieveServiceAccountToken(ctx, sa.OrgID, sa.ID, tokenID)
+		require.NoError(t, err)
+		assert.False(t, *rotated.IsRevoked)
+	})
+
+	t.Run("should list the tokens that expire soon", func(t *testing.T) {
+		soonID := addToken(t, "soon", 3600)
+		addToken(t, "later", 7*24*3600)
+
+		tokens, err := store.ListExpiringTokens(ctx, sa.OrgID, time.Now().Add(24*time.Hour))
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(tokens))
+		for _, token := range tokens {
+			ids = append(ids, token.ID)
+		}
+		assert.Contains(t, ids, soonID)
+		assert.Len(t, tokens, 3, "the token in its overlap and the new token of the first rotation expire soon as well")
+		assert.Equal(t, "Rotation", tokens[0].ServiceAccountName)
+	})
+}
+
+func TestIntegrationStore_TokenPolicy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, store := setupTestDatabase(t)
+
+	policy, err := store.GetTokenPolicy(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, &serviceaccounts.TokenPolicy{}, policy)
+
+	expected := &serviceaccounts.TokenPolicy{MaxSecondsToLive: 86400, ExpiryWarningSeconds: 3600, WarningRecipients: []string{"ops@example.com"}}
+	require.NoError(t, store.UpdateTokenPolicy(ctx, 2, expected))
+
+	policies, err := store.ListTokenPolicies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]*serviceaccounts.TokenPolicy{2: expected}, policies)
+
+	require.NoError(t, store.UpdateExpiryWarnings(ctx, 2, map[int64]int64{5: 1700000000}))
+	warnings, err := store.GetExpiryWarnings(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int64{5: 1700000000}, warnings)
+
+	require.NoError(t, store.UpdateExpiryWarnings(ctx, 2, nil))
+	warnings, err = store.GetExpiryWarnings(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
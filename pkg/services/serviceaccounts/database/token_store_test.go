@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
 )
@@ -93,6 +94,37 @@ func TestStore_AddServiceAccountToken_WrongServiceAccount(t *testing.T) {
 	require.Error(t, err, "It should not be possible to add token to non-existing service account")
 }
 
+func TestStore_AddServiceAccountToken_Permissions(t *testing.T) {
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, saToCreate)
+
+	permissions := apikey.Permissions{
+		{Action: "dashboards:write", Scope: "folders:uid:ci"},
+		{Action: "dashboards:create", Scope: "folders:uid:ci"},
+	}
+	for _, name := range []string{"restricted", "unrestricted"} {
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+
+		cmd := serviceaccounts.AddServiceAccountTokenCommand{Name: name, OrgId: sa.OrgID, Key: key.HashedKey}
+		if name == "restricted" {
+			cmd.Permissions = permissions
+		}
+		_, err = store.AddServiceAccountToken(context.Background(), sa.ID, &cmd)
+		require.NoError(t, err)
+	}
+
+	keys, err := store.ListTokens(context.Background(), &serviceaccounts.GetSATokensQuery{
+		OrgID:            &sa.OrgID,
+		ServiceAccountID: &sa.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, permissions, keys[0].Permissions)
+	require.Nil(t, keys[1].Permissions)
+}
+
 func TestStore_RevokeServiceAccountToken(t *testing.T) {
 	userToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
 	db, store := setupTestDatabase(t)
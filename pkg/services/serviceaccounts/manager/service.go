@@ -9,9 +9,12 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/api"
@@ -32,6 +35,9 @@ type ServiceAccountsService struct {
 	log               log.Logger
 	backgroundLog     log.Logger
 	secretScanService secretscan.Checker
+	orgService        org.Service
+	emailSender       notifications.EmailSender
+	serverLock        *serverlock.ServerLockService
 
 	secretScanEnabled  bool
 	secretScanInterval time.Duration
@@ -49,6 +55,9 @@ func ProvideServiceAccountsService(
 	orgService org.Service,
 	permissionService accesscontrol.ServiceAccountPermissionsService,
 	accesscontrolService accesscontrol.Service,
+	auditService audit.Service,
+	emailSender notifications.EmailSender,
+	serverLock *serverlock.ServerLockService,
 ) (*ServiceAccountsService, error) {
 	serviceAccountsStore := database.ProvideServiceAccountsStore(
 		cfg,
@@ -62,6 +71,9 @@ func ProvideServiceAccountsService(
 		store:         serviceAccountsStore,
 		log:           log.New("serviceaccounts"),
 		backgroundLog: log.New("serviceaccounts.background"),
+		orgService:    orgService,
+		emailSender:   emailSender,
+		serverLock:    serverLock,
 	}
 
 	if err := RegisterRoles(accesscontrolService); err != nil {
@@ -70,7 +82,7 @@ func ProvideServiceAccountsService(
 
 	usageStats.RegisterMetricsFunc(s.getUsageMetrics)
 
-	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, accesscontrolService, routeRegister, permissionService)
+	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, accesscontrolService, routeRegister, permissionService, auditService)
 	serviceaccountsAPI.RegisterAPIEndpoints()
 
 	s.secretScanEnabled = cfg.SectionWithEnvOverrides("secretscan").Key("enabled").MustBool(false)
@@ -120,6 +132,9 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 		defer tokenCheckTicker.Stop()
 	}
 
+	tokenExpiryTicker := time.NewTicker(tokenExpiryCheckInterval)
+	defer tokenExpiryTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -142,6 +157,17 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 			if err := sa.secretScanService.CheckTokens(ctx); err != nil {
 				sa.backgroundLog.Warn("Failed to check for leaked tokens", "error", err.Error())
 			}
+		case <-tokenExpiryTicker.C:
+			sa.backgroundLog.Debug("checking for expiring tokens")
+
+			// Only one instance warns about the expiring tokens.
+			if err := sa.serverLock.LockAndExecute(ctx, "serviceaccounts token expiry check", tokenExpiryCheckInterval/2, func(ctx context.Context) {
+				if err := sa.checkExpiringTokens(ctx); err != nil {
+					sa.backgroundLog.Warn("Failed to check for expiring tokens", "error", err.Error())
+				}
+			}); err != nil {
+				sa.backgroundLog.Warn("Failed to lock the token expiry check", "error", err.Error())
+			}
 		}
 	}
 }
@@ -209,6 +235,12 @@ func (sa *ServiceAccountsService) AddServiceAccountToken(ctx context.Context, se
 	if err := validServiceAccountID(serviceAccountID); err != nil {
 		return nil, err
 	}
+	if err := validTokenPermissions(query.Permissions); err != nil {
+		return nil, err
+	}
+	if err := sa.checkTokenPolicy(ctx, query.OrgId, query.SecondsToLive); err != nil {
+		return nil, err
+	}
 	return sa.store.AddServiceAccountToken(ctx, serviceAccountID, query)
 }
 
@@ -259,6 +291,17 @@ func validServiceAccountTokenID(tokenID int64) error {
 	}
 	return nil
 }
+func validTokenPermissions(permissions apikey.Permissions) error {
+	for _, p := range permissions {
+		if p.Action == "" {
+			return serviceaccounts.ErrInvalidTokenPermissions.Errorf("token permissions must have an action")
+		}
+		if p.Scope != "" && !accesscontrol.ValidateScope(p.Scope) {
+			return serviceaccounts.ErrInvalidTokenPermissions.Errorf("invalid scope %s for action %s", p.Scope, p.Action)
+		}
+	}
+	return nil
+}
 func validAPIKeyID(apiKeyID int64) error {
 	if apiKeyID == 0 {
 		return serviceaccounts.ErrServiceAccountInvalidAPIKeyID.Errorf("invalid API key ID 0 has been specified")
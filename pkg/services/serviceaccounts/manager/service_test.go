@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -22,7 +23,14 @@ type FakeServiceAccountStore struct {
 	ExpectedAPIKeys                         []apikey.APIKey
 	ExpectedAPIKey                          *apikey.APIKey
 	ExpectedBoolean                         bool
+	ExpectedTokenPolicy                     *serviceaccounts.TokenPolicy
+	ExpectedExpiringTokens                  []*serviceaccounts.ExpiringToken
+	ExpectedExpiryWarnings                  map[int64]int64
 	ExpectedError                           error
+
+	RotateCmd       *serviceaccounts.RotateServiceAccountTokenCommand
+	AddTokenCmd     *serviceaccounts.AddServiceAccountTokenCommand
+	UpdatedWarnings map[int64]int64
 }
 
 func newServiceAccountStoreFake() *FakeServiceAccountStore {
@@ -87,9 +95,62 @@ func (f *FakeServiceAccountStore) RevokeServiceAccountToken(ctx context.Context,
 
 // AddServiceAccountToken is a fake adding a service account token.
 func (f *FakeServiceAccountStore) AddServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) (*apikey.APIKey, error) {
+	f.AddTokenCmd = cmd
+	return f.ExpectedAPIKey, f.ExpectedError
+}
+
+// RetrieveServiceAccountToken is a fake retrieving a service account token.
+func (f *FakeServiceAccountStore) RetrieveServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) (*apikey.APIKey, error) {
 	return f.ExpectedAPIKey, f.ExpectedError
 }
 
+// RotateServiceAccountToken is a fake rotating a service account token.
+func (f *FakeServiceAccountStore) RotateServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*serviceaccounts.RotateServiceAccountTokenResult, error) {
+	f.RotateCmd = cmd
+	return &serviceaccounts.RotateServiceAccountTokenResult{Token: &apikey.APIKey{}, RotatedToken: f.ExpectedAPIKey}, f.ExpectedError
+}
+
+// ListExpiringTokens is a fake listing expiring tokens.
+func (f *FakeServiceAccountStore) ListExpiringTokens(ctx context.Context, orgID int64, before time.Time) ([]*serviceaccounts.ExpiringToken, error) {
+	return f.ExpectedExpiringTokens, f.ExpectedError
+}
+
+// GetTokenPolicy is a fake getting the token policy of an organization.
+func (f *FakeServiceAccountStore) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	if f.ExpectedTokenPolicy == nil {
+		return &serviceaccounts.TokenPolicy{}, f.ExpectedError
+	}
+	return f.ExpectedTokenPolicy, f.ExpectedError
+}
+
+// ListTokenPolicies is a fake listing the token policies.
+func (f *FakeServiceAccountStore) ListTokenPolicies(ctx context.Context) (map[int64]*serviceaccounts.TokenPolicy, error) {
+	if f.ExpectedTokenPolicy == nil {
+		return map[int64]*serviceaccounts.TokenPolicy{}, f.ExpectedError
+	}
+	return map[int64]*serviceaccounts.TokenPolicy{1: f.ExpectedTokenPolicy}, f.ExpectedError
+}
+
+// UpdateTokenPolicy is a fake updating the token policy of an organization.
+func (f *FakeServiceAccountStore) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	f.ExpectedTokenPolicy = policy
+	return f.ExpectedError
+}
+
+// GetExpiryWarnings is a fake getting the sent expiry warnings.
+func (f *FakeServiceAccountStore) GetExpiryWarnings(ctx context.Context, orgID int64) (map[int64]int64, error) {
+	if f.ExpectedExpiryWarnings == nil {
+		return map[int64]int64{}, f.ExpectedError
+	}
+	return f.ExpectedExpiryWarnings, f.ExpectedError
+}
+
+// UpdateExpiryWarnings is a fake updating the sent expiry warnings.
+func (f *FakeServiceAccountStore) UpdateExpiryWarnings(ctx context.Context, orgID int64, warnings map[int64]int64) error {
+	f.UpdatedWarnings = warnings
+	return f.ExpectedError
+}
+
 // DeleteServiceAccountToken is a fake deleting a service account token.
 func (f *FakeServiceAccountStore) DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error {
 	return f.ExpectedError
@@ -110,7 +171,7 @@ func (f *SecretsCheckerFake) CheckTokens(ctx context.Context) error {
 
 func TestProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 	storeMock := newServiceAccountStoreFake()
-	svc := ServiceAccountsService{storeMock, log.New("test"), log.New("background.test"), &SecretsCheckerFake{}, false, 0}
+	svc := ServiceAccountsService{store: storeMock, log: log.New("test"), backgroundLog: log.New("background.test"), secretScanService: &SecretsCheckerFake{}}
 	testOrgId := 1
 
 	t.Run("should create service account", func(t *testing.T) {
@@ -137,3 +198,31 @@ func TestProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestProvideServiceAccount_AddServiceAccountTokenPermissions(t *testing.T) {
+	storeMock := newServiceAccountStoreFake()
+	svc := ServiceAccountsService{store: storeMock, log: log.New("test"), backgroundLog: log.New("background.test"), secretScanService: &SecretsCheckerFake{}}
+
+	t.Run("should add a token restricted to some permissions", func(t *testing.T) {
+		_, err := svc.AddServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:        "restricted",
+			OrgId:       1,
+			Permissions: apikey.Permissions{{Action: "dashboards:write", Scope: "folders:uid:ci"}, {Action: "serviceaccounts:create"}},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("should not add a token with invalid permissions", func(t *testing.T) {
+		for _, permissions := range []apikey.Permissions{
+			{{Scope: "folders:uid:ci"}},
+			{{Action: "dashboards:write", Scope: "folders:*:ci"}},
+		} {
+			_, err := svc.AddServiceAccountToken(context.Background(), 1, &serviceaccounts.AddServiceAccountTokenCommand{
+				Name:        "invalid",
+				OrgId:       1,
+				Permissions: permissions,
+			})
+			require.ErrorIs(t, err, serviceaccounts.ErrInvalidTokenPermissions)
+		}
+	})
+}
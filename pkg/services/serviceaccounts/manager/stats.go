@@ -20,6 +20,15 @@ var (
 	// MStatTotalServiceAccountTokens is a metric gauge for total number of service account tokens
 	MStatTotalServiceAccountTokens prometheus.Gauge
 
+	// MStatTotalServiceAccountTokensExpiring is a metric gauge for the number of service account tokens that expire within the warning period of their organization
+	MStatTotalServiceAccountTokensExpiring prometheus.Gauge
+
+	// MTokenExpiryWarnings is a metric counter for the service account tokens that an expiry warning has been sent for
+	MTokenExpiryWarnings prometheus.Counter
+
+	// MTokenRotations is a metric counter for service account token rotations
+	MTokenRotations prometheus.Counter
+
 	Initialised bool = false
 )
 
@@ -42,10 +51,31 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MStatTotalServiceAccountTokensExpiring = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "stat_total_service_account_tokens_expiring",
+		Help:      "total amount of service account tokens that expire within the warning period of their organization",
+		Namespace: ExporterName,
+	})
+
+	MTokenExpiryWarnings = prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "service_account_token_expiry_warnings_total",
+		Help:      "number of service account tokens that an expiry warning has been sent for",
+		Namespace: ExporterName,
+	})
+
+	MTokenRotations = prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "service_account_token_rotations_total",
+		Help:      "number of service account token rotations",
+		Namespace: ExporterName,
+	})
+
 	prometheus.MustRegister(
 		MStatTotalServiceAccounts,
 		MStatTotalServiceAccountTokens,
 		MStatTotalServiceAccountsNoRole,
+		MStatTotalServiceAccountTokensExpiring,
+		MTokenExpiryWarnings,
+		MTokenRotations,
 	)
 }
 
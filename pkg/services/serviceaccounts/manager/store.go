@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
@@ -21,5 +22,13 @@ type store interface {
 	RevokeServiceAccountToken(ctx context.Context, orgId, serviceAccountId, tokenId int64) error
 	AddServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) (*apikey.APIKey, error)
 	DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error
+	RetrieveServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) (*apikey.APIKey, error)
+	RotateServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*serviceaccounts.RotateServiceAccountTokenResult, error)
+	ListExpiringTokens(ctx context.Context, orgID int64, before time.Time) ([]*serviceaccounts.ExpiringToken, error)
+	GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error)
+	ListTokenPolicies(ctx context.Context) (map[int64]*serviceaccounts.TokenPolicy, error)
+	UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error
+	GetExpiryWarnings(ctx context.Context, orgID int64) (map[int64]int64, error)
+	UpdateExpiryWarnings(ctx context.Context, orgID int64, warnings map[int64]int64) error
 	GetUsageMetrics(ctx context.Context) (*serviceaccounts.Stats, error)
 }
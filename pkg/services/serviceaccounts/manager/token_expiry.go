@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	tokenExpiryCheckInterval = time.Hour
+	tokenExpiryEmailTemplate = "service_account_token_expiry"
+)
+
+// checkExpiringTokens warns the recipients of the token policy of each organization about the tokens that expire
+// within the warning period. A warning is sent once per token and expiration.
+func (sa *ServiceAccountsService) checkExpiringTokens(ctx context.Context) error {
+	policies, err := sa.store.ListTokenPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiring := 0
+	for orgID, policy := range policies {
+		if policy.ExpiryWarningSeconds <= 0 {
+			continue
+		}
+
+		tokens, err := sa.store.ListExpiringTokens(ctx, orgID, now.Add(time.Duration(policy.ExpiryWarningSeconds)*time.Second))
+		if err != nil {
+			return err
+		}
+		expiring += len(tokens)
+
+		warned, err := sa.store.GetExpiryWarnings(ctx, orgID)
+		if err != nil {
+			return err
+		}
+
+		// Only the tokens that still expire soon are kept, so that the warnings don't pile up.
+		sent := make(map[int64]int64, len(tokens))
+		pending := make([]*serviceaccounts.ExpiringToken, 0, len(tokens))
+		for _, token := range tokens {
+			if expires, ok := warned[token.ID]; ok && expires == token.Expires {
+				sent[token.ID] = token.Expires
+				continue
+			}
+			pending = append(pending, token)
+		}
+
+		if len(pending) > 0 {
+			if err := sa.sendExpiryWarning(ctx, orgID, policy, pending); err != nil {
+				if errors.Is(err, notifications.ErrSmtpNotEnabled) {
+					sa.backgroundLog.Debug("Skipping service account token expiry warning", "orgId", orgID, "error", err)
+				} else {
+					sa.backgroundLog.Warn("Failed to send service account token expiry warning", "orgId", orgID, "error", err)
+				}
+			} else {
+				for _, token := range pending {
+					sent[token.ID] = token.Expires
+				}
+				MTokenExpiryWarnings.Add(float64(len(pending)))
+			}
+		}
+
+		if err := sa.store.UpdateExpiryWarnings(ctx, orgID, sent); err != nil {
+			return err
+		}
+	}
+
+	MStatTotalServiceAccountTokensExpiring.Set(float64(expiring))
+	return nil
+}
+
+func (sa *ServiceAccountsService) sendExpiryWarning(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy, tokens []*serviceaccounts.ExpiringToken) error {
+	recipients, err := sa.warningRecipients(ctx, orgID, policy)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		sa.backgroundLog.Warn("No recipient for service account token expiry warning", "orgId", orgID)
+		return nil
+	}
+
+	o, err := sa.orgService.GetByID(ctx, &org.GetOrgByIDQuery{ID: orgID})
+	if err != nil {
+		return err
+	}
+
+	items := make([]map[string]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		items = append(items, map[string]interface{}{
+			"Name":               token.Name,
+			"ServiceAccountName": token.ServiceAccountName,
+			"Expires":            time.Unix(token.Expires, 0).UTC().Format(time.RFC1123),
+			"Url":                setting.ToAbsUrl(fmt.Sprintf("org/serviceaccounts/%d?orgId=%d", token.ServiceAccountID, orgID)),
+		})
+	}
+
+	return sa.emailSender.SendEmailCommandHandler(ctx, &notifications.SendEmailCommand{
+		To:       recipients,
+		Template: tokenExpiryEmailTemplate,
+		Data: map[string]interface{}{
+			"OrgName": o.Name,
+			"Tokens":  items,
+		},
+	})
+}
+
+// warningRecipients returns the recipients of the policy, or the email addresses of the organization administrators.
+func (sa *ServiceAccountsService) warningRecipients(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) ([]string, error) {
+	if len(policy.WarningRecipients) > 0 {
+		return policy.WarningRecipients, nil
+	}
+
+	orgUsers, err := sa.orgService.GetOrgUsers(ctx, &org.GetOrgUsersQuery{
+		OrgID:                    orgID,
+		DontEnforceAccessControl: true,
+		User:                     &user.SignedInUser{OrgID: orgID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]string, 0)
+	for _, orgUser := range orgUsers {
+		if orgUser.Role == string(org.RoleAdmin) && !orgUser.IsDisabled && orgUser.Email != "" {
+			recipients = append(recipients, orgUser.Email)
+		}
+	}
+	return recipients, nil
+}
@@ -0,0 +1,106 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func (sa *ServiceAccountsService) GetTokenPolicy(ctx context.Context, orgID int64) (*serviceaccounts.TokenPolicy, error) {
+	if err := validOrgID(orgID); err != nil {
+		return nil, err
+	}
+	return sa.store.GetTokenPolicy(ctx, orgID)
+}
+
+func (sa *ServiceAccountsService) UpdateTokenPolicy(ctx context.Context, orgID int64, policy *serviceaccounts.TokenPolicy) error {
+	if err := validOrgID(orgID); err != nil {
+		return err
+	}
+	if policy.MaxSecondsToLive < 0 {
+		return serviceaccounts.ErrInvalidTokenPolicy.Errorf("invalid maxSecondsToLive value %d", policy.MaxSecondsToLive)
+	}
+	if policy.ExpiryWarningSeconds < 0 {
+		return serviceaccounts.ErrInvalidTokenPolicy.Errorf("invalid expiryWarningSeconds value %d", policy.ExpiryWarningSeconds)
+	}
+	for _, recipient := range policy.WarningRecipients {
+		if !util.IsEmail(recipient) {
+			return serviceaccounts.ErrInvalidTokenPolicy.Errorf("invalid warning recipient %q", recipient)
+		}
+	}
+	return sa.store.UpdateTokenPolicy(ctx, orgID, policy)
+}
+
+func (sa *ServiceAccountsService) RotateServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.RotateServiceAccountTokenCommand) (*serviceaccounts.RotateServiceAccountTokenResult, error) {
+	if err := validOrgID(cmd.OrgId); err != nil {
+		return nil, err
+	}
+	if err := validServiceAccountID(serviceAccountID); err != nil {
+		return nil, err
+	}
+	if err := validServiceAccountTokenID(cmd.TokenId); err != nil {
+		return nil, err
+	}
+	if cmd.OverlapSeconds < 0 || cmd.OverlapSeconds > int64(serviceaccounts.MaxRotationOverlap.Seconds()) {
+		return nil, serviceaccounts.ErrInvalidRotationOverlap.Errorf("overlapSeconds must be between 0 and %d", int64(serviceaccounts.MaxRotationOverlap.Seconds()))
+	}
+
+	if cmd.SecondsToLive == 0 {
+		secondsToLive, err := sa.RotationSecondsToLive(ctx, cmd.OrgId, serviceAccountID, cmd.TokenId)
+		if err != nil {
+			return nil, err
+		}
+		cmd.SecondsToLive = secondsToLive
+	}
+
+	if err := sa.checkTokenPolicy(ctx, cmd.OrgId, cmd.SecondsToLive); err != nil {
+		return nil, err
+	}
+
+	result, err := sa.store.RotateServiceAccountToken(ctx, serviceAccountID, cmd)
+	if err != nil {
+		return nil, err
+	}
+	MTokenRotations.Inc()
+	return result, nil
+}
+
+// RotationSecondsToLive returns the lifetime of the token replacing a rotated token when none is set: the lifetime
+// of the token it replaces, within the longest lifetime allowed by the policy. It is 0 for tokens that do not expire.
+func (sa *ServiceAccountsService) RotationSecondsToLive(ctx context.Context, orgID, serviceAccountID, tokenID int64) (int64, error) {
+	rotated, err := sa.store.RetrieveServiceAccountToken(ctx, orgID, serviceAccountID, tokenID)
+	if err != nil {
+		return 0, err
+	}
+	var secondsToLive int64
+	if rotated.Expires != nil {
+		secondsToLive = *rotated.Expires - rotated.Created.Unix()
+	}
+	policy, err := sa.store.GetTokenPolicy(ctx, orgID)
+	if err != nil {
+		return 0, err
+	}
+	if policy.MaxSecondsToLive > 0 && (secondsToLive <= 0 || secondsToLive > policy.MaxSecondsToLive) {
+		secondsToLive = policy.MaxSecondsToLive
+	}
+	return secondsToLive, nil
+}
+
+// checkTokenPolicy returns an error if a token with the given lifetime is not allowed by the token policy of the organization.
+func (sa *ServiceAccountsService) checkTokenPolicy(ctx context.Context, orgID int64, secondsToLive int64) error {
+	policy, err := sa.store.GetTokenPolicy(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if policy.MaxSecondsToLive == 0 {
+		return nil
+	}
+	if secondsToLive <= 0 {
+		return serviceaccounts.ErrTokenPolicyViolation.Errorf("service account tokens of organization %d have to expire", orgID)
+	}
+	if secondsToLive > policy.MaxSecondsToLive {
+		return serviceaccounts.ErrTokenPolicyViolation.Errorf("service account tokens of organization %d can not live longer than %d seconds", orgID, policy.MaxSecondsToLive)
+	}
+	return nil
+}
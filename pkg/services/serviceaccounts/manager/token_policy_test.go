@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+func TestServiceAccountsService_TokenPolicy(t *testing.T) {
+	ctx := context.Background()
+	storeMock := newServiceAccountStoreFake()
+	svc := ServiceAccountsService{store: storeMock, log: log.New("test"), backgroundLog: log.New("background.test")}
+
+	t.Run("should not accept invalid policies", func(t *testing.T) {
+		err := svc.UpdateTokenPolicy(ctx, 1, &serviceaccounts.TokenPolicy{MaxSecondsToLive: -1})
+		assert.ErrorIs(t, err, serviceaccounts.ErrInvalidTokenPolicy)
+		err = svc.UpdateTokenPolicy(ctx, 1, &serviceaccounts.TokenPolicy{WarningRecipients: []string{"ops"}})
+		assert.ErrorIs(t, err, serviceaccounts.ErrInvalidTokenPolicy)
+	})
+
+	require.NoError(t, svc.UpdateTokenPolicy(ctx, 1, &serviceaccounts.TokenPolicy{MaxSecondsToLive: 3600}))
+
+	t.Run("should only add tokens that comply with the policy", func(t *testing.T) {
+		_, err := svc.AddServiceAccountToken(ctx, 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "no-expiry", OrgId: 1})
+		assert.ErrorIs(t, err, serviceaccounts.ErrTokenPolicyViolation)
+		_, err = svc.AddServiceAccountToken(ctx, 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "long", OrgId: 1, SecondsToLive: 3601})
+		assert.ErrorIs(t, err, serviceaccounts.ErrTokenPolicyViolation)
+		_, err = svc.AddServiceAccountToken(ctx, 1, &serviceaccounts.AddServiceAccountTokenCommand{Name: "short", OrgId: 1, SecondsToLive: 3600})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should give the rotated token lifetime to the new token", func(t *testing.T) {
+		created := time.Now().Add(-time.Hour)
+		expires := created.Add(30 * time.Minute).Unix()
+		storeMock.ExpectedAPIKey = &apikey.APIKey{ID: 2, Created: created, Expires: &expires}
+
+		_, err := svc.RotateServiceAccountToken(ctx, 1, &serviceaccounts.RotateServiceAccountTokenCommand{OrgId: 1, TokenId: 2})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1800, storeMock.RotateCmd.SecondsToLive)
+	})
+
+	t.Run("should limit the lifetime of the new token to the policy", func(t *testing.T) {
+		storeMock.ExpectedAPIKey = &apikey.APIKey{ID: 2, Created: time.Now()}
+
+		_, err := svc.RotateServiceAccountToken(ctx, 1, &serviceaccounts.RotateServiceAccountTokenCommand{OrgId: 1, TokenId: 2, OverlapSeconds: 60})
+		require.NoError(t, err)
+		assert.EqualValues(t, 3600, storeMock.RotateCmd.SecondsToLive)
+
+		_, err = svc.RotateServiceAccountToken(ctx, 1, &serviceaccounts.RotateServiceAccountTokenCommand{OrgId: 1, TokenId: 2, SecondsToLive: 7200})
+		assert.ErrorIs(t, err, serviceaccounts.ErrTokenPolicyViolation)
+	})
+
+	t.Run("should not accept overlaps longer than the maximum", func(t *testing.T) {
+		_, err := svc.RotateServiceAccountToken(ctx, 1, &serviceaccounts.RotateServiceAccountTokenCommand{OrgId: 1, TokenId: 2, OverlapSeconds: int64(serviceaccounts.MaxRotationOverlap.Seconds()) + 1})
+		assert.ErrorIs(t, err, serviceaccounts.ErrInvalidRotationOverlap)
+	})
+}
+
+func TestServiceAccountsService_CheckExpiringTokens(t *testing.T) {
+	ctx := context.Background()
+	storeMock := newServiceAccountStoreFake()
+	emailSender := notifications.MockNotificationService()
+	svc := ServiceAccountsService{
+		store:         storeMock,
+		log:           log.New("test"),
+		backgroundLog: log.New("background.test"),
+		emailSender:   emailSender,
+		orgService: &orgtest.FakeOrgService{
+			ExpectedOrg: &org.Org{ID: 1, Name: "Main Org."},
+			ExpectedOrgUsers: []*org.OrgUserDTO{
+				{Email: "admin@example.com", Role: string(org.RoleAdmin)},
+				{Email: "disabled@example.com", Role: string(org.RoleAdmin), IsDisabled: true},
+				{Email: "editor@example.com", Role: string(org.RoleEditor)},
+			},
+		},
+	}
+
+	expires := time.Now().Add(time.Hour).Unix()
+	storeMock.ExpectedTokenPolicy = &serviceaccounts.TokenPolicy{ExpiryWarningSeconds: 86400}
+	storeMock.ExpectedExpiringTokens = []*serviceaccounts.ExpiringToken{
+		{ID: 1, OrgID: 1, Name: "ci", Expires: expires, ServiceAccountID: 3, ServiceAccountName: "deploy"},
+	}
+
+	t.Run("should warn the organization administrators", func(t *testing.T) {
+		require.NoError(t, svc.checkExpiringTokens(ctx))
+		assert.Equal(t, []string{"admin@example.com"}, emailSender.Email.To)
+		assert.Equal(t, tokenExpiryEmailTemplate, emailSender.Email.Template)
+		assert.Equal(t, map[int64]int64{1: expires}, storeMock.UpdatedWarnings)
+	})
+
+	t.Run("should warn once per token expiration", func(t *testing.T) {
+		emailSender.Email = notifications.SendEmailCommand{}
+		storeMock.ExpectedExpiryWarnings = map[int64]int64{1: expires, 2: expires}
+
+		require.NoError(t, svc.checkExpiringTokens(ctx))
+		assert.Empty(t, emailSender.Email.To)
+		assert.Equal(t, map[int64]int64{1: expires}, storeMock.UpdatedWarnings)
+	})
+
+	t.Run("should warn the recipients of the policy", func(t *testing.T) {
+		storeMock.ExpectedTokenPolicy.WarningRecipients = []string{"ops@example.com"}
+		storeMock.ExpectedExpiryWarnings = map[int64]int64{1: expires - 60}
+
+		require.NoError(t, svc.checkExpiringTokens(ctx))
+		assert.Equal(t, []string{"ops@example.com"}, emailSender.Email.To)
+	})
+
+	t.Run("should retry warnings that could not be sent", func(t *testing.T) {
+		emailSender.ShouldError = notifications.ErrSmtpNotEnabled
+		storeMock.ExpectedExpiryWarnings = nil
+
+		require.NoError(t, svc.checkExpiringTokens(ctx))
+		assert.Empty(t, storeMock.UpdatedWarnings)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -35,6 +36,11 @@ var (
 	ErrServiceAccountTokenNotFound       = errutil.NotFound("serviceaccounts.ErrTokenNotFound", errutil.WithPublicMessage("service account token not found"))
 	ErrInvalidTokenExpiration            = errutil.ValidationFailed("serviceaccounts.ErrInvalidInput", errutil.WithPublicMessage("invalid SecondsToLive value"))
 	ErrDuplicateToken                    = errutil.BadRequest("serviceaccounts.ErrTokenAlreadyExists", errutil.WithPublicMessage("service account token with given name already exists in the organization"))
+	ErrTokenPolicyViolation              = errutil.BadRequest("serviceaccounts.ErrTokenPolicyViolation", errutil.WithPublicMessage("service account token expiration does not comply with the token policy of the organization"))
+	ErrInvalidTokenPolicy                = errutil.ValidationFailed("serviceaccounts.ErrInvalidTokenPolicy", errutil.WithPublicMessage("invalid service account token policy"))
+	ErrInvalidRotationOverlap            = errutil.ValidationFailed("serviceaccounts.ErrInvalidRotationOverlap", errutil.WithPublicMessage("invalid overlapSeconds value"))
+	ErrServiceAccountTokenRevoked        = errutil.BadRequest("serviceaccounts.ErrTokenRevoked", errutil.WithPublicMessage("service account token is revoked"))
+	ErrInvalidTokenPermissions           = errutil.ValidationFailed("serviceaccounts.ErrInvalidTokenPermissions", errutil.WithPublicMessage("invalid service account token permissions"))
 )
 
 type MigrationResult struct {
@@ -98,6 +104,54 @@ type AddServiceAccountTokenCommand struct {
 	OrgId         int64  `json:"-"`
 	Key           string `json:"-"`
 	SecondsToLive int64  `json:"secondsToLive"`
+	// Permissions restrict the token to a subset of the permissions of the service account.
+	// The token has all the permissions of the service account when empty.
+	Permissions apikey.Permissions `json:"permissions"`
+}
+
+// MaxRotationOverlap is the longest time a rotated token stays valid after the rotation.
+const MaxRotationOverlap = 7 * 24 * time.Hour
+
+type RotateServiceAccountTokenCommand struct {
+	// Name of the new token, the rotated token is renamed so that the new token can keep its name.
+	Name string `json:"name"`
+	// Lifetime of the new token, defaults to the lifetime of the rotated token.
+	SecondsToLive int64 `json:"secondsToLive"`
+	// Time the rotated token stays valid after the rotation, it is revoked immediately when 0.
+	OverlapSeconds int64  `json:"overlapSeconds"`
+	OrgId          int64  `json:"-"`
+	TokenId        int64  `json:"-"`
+	Key            string `json:"-"`
+}
+
+// RotateServiceAccountTokenResult holds the new token and the rotated token as they are after the rotation.
+type RotateServiceAccountTokenResult struct {
+	Token        *apikey.APIKey
+	RotatedToken *apikey.APIKey
+}
+
+// TokenPolicy is the policy that the service account tokens of an organization comply with.
+// swagger:model
+type TokenPolicy struct {
+	// Longest lifetime of new tokens, tokens have to expire when set. 0 means no limit.
+	// example: 7776000
+	MaxSecondsToLive int64 `json:"maxSecondsToLive"`
+	// How long before the expiration of a token a warning is sent. 0 disables the warnings.
+	// example: 604800
+	ExpiryWarningSeconds int64 `json:"expiryWarningSeconds"`
+	// Email addresses that receive the expiry warnings, the organization administrators receive them when empty.
+	// example: ["ops@example.com"]
+	WarningRecipients []string `json:"warningRecipients"`
+}
+
+// ExpiringToken is a service account token that expires within the warning period of its organization.
+type ExpiringToken struct {
+	ID                 int64  `xorm:"id"`
+	OrgID              int64  `xorm:"org_id"`
+	Name               string `xorm:"name"`
+	Expires            int64  `xorm:"expires"`
+	ServiceAccountID   int64  `xorm:"service_account_id"`
+	ServiceAccountName string `xorm:"service_account_name"`
 }
 
 type SearchOrgServiceAccountsQuery struct {
@@ -0,0 +1,34 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addAuditEventMigrations(mg *Migrator) {
+	auditEventV1 := Table{
+		Name: "audit_event",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_BigInt, Nullable: false},
+			{Name: "actor_namespace", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "actor_id", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "actor_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "action", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "resource_org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "resource_kind", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "resource_id", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "summary_before", Type: DB_Text, Nullable: true},
+			{Name: "summary_after", Type: DB_Text, Nullable: true},
+			{Name: "request", Type: DB_Text, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "created"}},
+			{Cols: []string{"created"}},
+			{Cols: []string{"resource_kind", "resource_id"}},
+		},
+	}
+
+	mg.AddMigration("create audit_event table", NewAddTableMigration(auditEventV1))
+	mg.AddMigration("add index audit_event.org_id-created", NewAddIndexMigration(auditEventV1, auditEventV1.Indices[0]))
+	mg.AddMigration("add index audit_event.created", NewAddIndexMigration(auditEventV1, auditEventV1.Indices[1]))
+	mg.AddMigration("add index audit_event.resource_kind-resource_id", NewAddIndexMigration(auditEventV1, auditEventV1.Indices[2]))
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardBulkMigrations(mg *Migrator) {
+	jobV1 := Table{
+		Name: "dashboard_bulk_job",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "operation", Type: DB_Text, Nullable: false},
+			{Name: "dry_run", Type: DB_Bool, Nullable: false},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "total", Type: DB_Int, Nullable: false},
+			{Name: "processed", Type: DB_Int, Nullable: false},
+			{Name: "succeeded", Type: DB_Int, Nullable: false},
+			{Name: "skipped", Type: DB_Int, Nullable: false},
+			{Name: "failed", Type: DB_Int, Nullable: false},
+			{Name: "error", Type: DB_Text, Nullable: true},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "finished", Type: DB_DateTime, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "created_by"}},
+			{Cols: []string{"status"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_bulk_job table", NewAddTableMigration(jobV1))
+	mg.AddMigration("add index dashboard_bulk_job.org_id-created_by", NewAddIndexMigration(jobV1, jobV1.Indices[0]))
+	mg.AddMigration("add index dashboard_bulk_job.status", NewAddIndexMigration(jobV1, jobV1.Indices[1]))
+
+	itemV1 := Table{
+		Name: "dashboard_bulk_job_item",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "job_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 189, Nullable: true},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "message", Type: DB_Text, Nullable: true},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "draft_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"job_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_bulk_job_item table", NewAddTableMigration(itemV1))
+	mg.AddMigration("add index dashboard_bulk_job_item.job_id", NewAddIndexMigration(itemV1, itemV1.Indices[0]))
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardDraftMigrations(mg *Migrator) {
+	approvalV1 := Table{
+		Name: "dashboard_approval",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid"}, Type: UniqueIndex},
+			{Cols: []string{"dashboard_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_approval table", NewAddTableMigration(approvalV1))
+	mg.AddMigration("add unique index dashboard_approval.org_id-dashboard_uid", NewAddIndexMigration(approvalV1, approvalV1.Indices[0]))
+	mg.AddMigration("add index dashboard_approval.dashboard_id", NewAddIndexMigration(approvalV1, approvalV1.Indices[1]))
+
+	draftV1 := Table{
+		Name: "dashboard_draft",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "folder_id", Type: DB_BigInt, Nullable: false},
+			{Name: "parent_version", Type: DB_Int, Nullable: false},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 189, Nullable: false},
+			{Name: "data", Type: DB_MediumText, Nullable: false},
+			{Name: "message", Type: DB_Text, Nullable: true},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "reviewed_by", Type: DB_BigInt, Nullable: false},
+			{Name: "reviewed", Type: DB_DateTime, Nullable: true},
+			{Name: "review_comment", Type: DB_Text, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid", "status"}},
+			{Cols: []string{"dashboard_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_draft table", NewAddTableMigration(draftV1))
+	mg.AddMigration("add index dashboard_draft.org_id-dashboard_uid-status", NewAddIndexMigration(draftV1, draftV1.Indices[0]))
+	mg.AddMigration("add index dashboard_draft.dashboard_id", NewAddIndexMigration(draftV1, draftV1.Indices[1]))
+}
@@ -96,4 +96,16 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add expires_at column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "expires_at",
+		Type:     DB_DateTime,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add variables column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "variables",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
 }
@@ -71,4 +71,16 @@ func addDashboardSnapshotMigrations(mg *Migrator) {
 
 	mg.AddMigration("Change dashboard_encrypted column to MEDIUMBLOB", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_snapshot MODIFY dashboard_encrypted MEDIUMBLOB;"))
+
+	mg.AddMigration("Add capture column to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "capture", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("Add next_capture_at column to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "next_capture_at", Type: DB_DateTime, Nullable: true,
+	}))
+
+	mg.AddMigration("Add index on dashboard_snapshot next_capture_at", NewAddIndexMigration(snapshotV5, &Index{
+		Cols: []string{"next_capture_at"},
+	}))
 }
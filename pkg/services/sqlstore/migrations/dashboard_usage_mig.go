@@ -0,0 +1,27 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardUsageMigrations(mg *Migrator) {
+	dailyV1 := Table{
+		Name: "dashboard_usage_daily",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: DB_BigInt, Nullable: false},
+			{Name: "panel_id", Type: DB_BigInt, Nullable: false},
+			{Name: "day", Type: DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "views", Type: DB_BigInt, Nullable: false},
+			{Name: "queries", Type: DB_BigInt, Nullable: false},
+			{Name: "last_viewed", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"dashboard_id", "panel_id", "day"}, Type: UniqueIndex},
+			{Cols: []string{"day"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_usage_daily table", NewAddTableMigration(dailyV1))
+	mg.AddMigration("add unique index dashboard_usage_daily.dashboard_id-panel_id-day", NewAddIndexMigration(dailyV1, dailyV1.Indices[0]))
+	mg.AddMigration("add index dashboard_usage_daily.day", NewAddIndexMigration(dailyV1, dailyV1.Indices[1]))
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addMFAMigrations(mg *Migrator) {
+	userMFAV1 := Table{
+		Name: "user_mfa",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "secret", Type: DB_Text, Nullable: true},
+			{Name: "pending_secret", Type: DB_Text, Nullable: true},
+			{Name: "recovery_codes", Type: DB_Text, Nullable: true},
+			{Name: "last_used_step", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_mfa table", NewAddTableMigration(userMFAV1))
+	mg.AddMigration("add unique index user_mfa.user_id", NewAddIndexMigration(userMFAV1, userMFAV1.Indices[0]))
+	mg.AddMigration("add pending_created column to user_mfa", NewAddColumnMigration(userMFAV1, &Column{
+		Name: "pending_created", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	orgMFAPolicyV1 := Table{
+		Name: "org_mfa_policy",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "enforced", Type: DB_Bool, Nullable: false},
+			{Name: "roles", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create org_mfa_policy table", NewAddTableMigration(orgMFAPolicyV1))
+	mg.AddMigration("add unique index org_mfa_policy.org_id", NewAddIndexMigration(orgMFAPolicyV1, orgMFAPolicyV1.Indices[0]))
+}
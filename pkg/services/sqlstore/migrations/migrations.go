@@ -91,6 +91,21 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 	AddExternalAlertmanagerToDatasourceMigration(mg)
 
 	addFolderMigrations(mg)
+
+	addAuditEventMigrations(mg)
+
+	addMFAMigrations(mg)
+
+	addSCIMMigrations(mg)
+
+	addReportMigrations(mg)
+
+	addDashboardDraftMigrations(mg)
+	addDashboardLintMigrations(mg)
+	addDashboardBulkMigrations(mg)
+	addDashboardUsageMigrations(mg)
+	addPlaylistScheduleMigrations(mg)
+
 	if mg.Cfg != nil && mg.Cfg.IsFeatureToggleEnabled != nil {
 		if mg.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagExternalServiceAuth) {
 			oauthserver.AddMigration(mg)
@@ -63,3 +63,20 @@ func playlistV2() Table {
 		},
 	}
 }
+
+func addPlaylistScheduleMigrations(mg *Migrator) {
+	mg.AddMigration("Add schedule column to playlist", NewAddColumnMigration(playlistV2(), &Column{
+		Name: "schedule", Type: DB_Text, Nullable: true,
+	}))
+
+	playlistItem := Table{Name: "playlist_item"}
+	mg.AddMigration("Add interval column to playlist_item", NewAddColumnMigration(playlistItem, &Column{
+		Name: "interval", Type: DB_NVarchar, Length: 255, Nullable: false, Default: "''",
+	}))
+	mg.AddMigration("Add time_range column to playlist_item", NewAddColumnMigration(playlistItem, &Column{
+		Name: "time_range", Type: DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("Add variables column to playlist_item", NewAddColumnMigration(playlistItem, &Column{
+		Name: "variables", Type: DB_Text, Nullable: true,
+	}))
+}
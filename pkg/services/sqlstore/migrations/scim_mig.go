@@ -0,0 +1,41 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addSCIMMigrations(mg *Migrator) {
+	scimResourceV1 := Table{
+		Name: "scim_resource",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "resource_type", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "resource_id", Type: DB_BigInt, Nullable: false},
+			{Name: "external_id", Type: DB_NVarchar, Length: 190, Nullable: false, Default: "''"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "resource_type", "resource_id"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "resource_type", "external_id"}},
+		},
+	}
+
+	mg.AddMigration("create scim_resource table", NewAddTableMigration(scimResourceV1))
+	mg.AddMigration("add unique index scim_resource.org_id_resource_type_resource_id", NewAddIndexMigration(scimResourceV1, scimResourceV1.Indices[0]))
+	mg.AddMigration("add index scim_resource.org_id_resource_type_external_id", NewAddIndexMigration(scimResourceV1, scimResourceV1.Indices[1]))
+
+	scimManagedUserV1 := Table{
+		Name: "scim_managed_user",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create scim_managed_user table", NewAddTableMigration(scimManagedUserV1))
+	mg.AddMigration("add unique index scim_managed_user.user_id", NewAddIndexMigration(scimManagedUserV1, scimManagedUserV1.Indices[0]))
+}
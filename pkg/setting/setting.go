@@ -231,6 +231,14 @@ type Cfg struct {
 	AngularSupportEnabled            bool
 	DisableFrontendSandboxForPlugins []string
 
+	// BruteForceLoginProtection* configure how failed login attempts are throttled
+	BruteForceLoginProtectionMaxAttempts        int64
+	BruteForceLoginProtectionMaxAttemptsPerUser int64
+	BruteForceLoginProtectionMaxAttemptsPerIP   int64
+	BruteForceLoginProtectionLockout            time.Duration
+	BruteForceLoginProtectionMaxLockout         time.Duration
+	BruteForceLoginProtectionAllowedCIDRs       []string
+
 	TempDataLifetime time.Duration
 
 	// Plugins
@@ -335,6 +343,13 @@ type Cfg struct {
 	ExtendedJWTExpectIssuer   string
 	ExtendedJWTExpectAudience string
 
+	// Client certificate Auth
+	ClientCertAuthEnabled         bool
+	ClientCertAuthCACertFile      string
+	ClientCertAuthRulesFile       string
+	ClientCertAuthAutoSignUp      bool
+	ClientCertAuthSkipOrgRoleSync bool
+
 	// Dataproxy
 	SendUserHeader                 bool
 	DataProxyLogging               bool
@@ -387,6 +402,9 @@ type Cfg struct {
 	// GrafanaJavascriptAgent config
 	GrafanaJavascriptAgent GrafanaJavascriptAgent
 
+	// Public dashboards
+	PublicDashboards PublicDashboardsSettings
+
 	// Data sources
 	DataSourceLimit int
 
@@ -394,6 +412,7 @@ type Cfg struct {
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	SqliteDatasourceAllowedPaths        []string
 
 	// Snapshots
 	SnapshotEnabled       bool
@@ -403,6 +422,8 @@ type Cfg struct {
 	SnapShotRemoveExpired bool
 
 	SnapshotPublicMode bool
+	// SnapshotRecaptureMinInterval is the shortest duration between two captures of a snapshot by the server.
+	SnapshotRecaptureMinInterval time.Duration
 
 	ErrTemplateName string
 
@@ -975,6 +996,13 @@ func NewCfg() *Cfg {
 		Raw:         ini.Empty(),
 		Azure:       &azsettings.AzureSettings{},
 		RBACEnabled: true,
+
+		// login attempts are throttled for configurations built in code too
+		BruteForceLoginProtectionMaxAttempts:        5,
+		BruteForceLoginProtectionMaxAttemptsPerUser: 20,
+		BruteForceLoginProtectionMaxAttemptsPerIP:   50,
+		BruteForceLoginProtectionLockout:            5 * time.Minute,
+		BruteForceLoginProtectionMaxLockout:         time.Hour,
 	}
 }
 
@@ -1237,6 +1265,7 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	cfg.readDateFormats()
 	cfg.readGrafanaJavascriptAgentConfig()
+	cfg.readPublicDashboardsSettings()
 
 	if err := cfg.readLiveSettings(iniFile); err != nil {
 		return err
@@ -1398,6 +1427,15 @@ func readSecuritySettings(iniFile *ini.File, cfg *Cfg) error {
 	cfg.SecretKey = SecretKey
 	DisableGravatar = security.Key("disable_gravatar").MustBool(true)
 	cfg.DisableBruteForceLoginProtection = security.Key("disable_brute_force_login_protection").MustBool(false)
+	cfg.BruteForceLoginProtectionMaxAttempts = security.Key("brute_force_login_protection_max_attempts").MustInt64(5)
+	cfg.BruteForceLoginProtectionMaxAttemptsPerUser = security.Key("brute_force_login_protection_max_attempts_per_user").MustInt64(20)
+	cfg.BruteForceLoginProtectionMaxAttemptsPerIP = security.Key("brute_force_login_protection_max_attempts_per_ip").MustInt64(50)
+	cfg.BruteForceLoginProtectionLockout = security.Key("brute_force_login_protection_lockout").MustDuration(5 * time.Minute)
+	cfg.BruteForceLoginProtectionMaxLockout = security.Key("brute_force_login_protection_max_lockout").MustDuration(time.Hour)
+	if cfg.BruteForceLoginProtectionMaxLockout < cfg.BruteForceLoginProtectionLockout {
+		cfg.BruteForceLoginProtectionMaxLockout = cfg.BruteForceLoginProtectionLockout
+	}
+	cfg.BruteForceLoginProtectionAllowedCIDRs = util.SplitString(security.Key("brute_force_login_protection_allowed_cidrs").MustString(""))
 
 	CookieSecure = security.Key("cookie_secure").MustBool(false)
 	cfg.CookieSecure = CookieSecure
@@ -1629,6 +1667,14 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.ExtendedJWTExpectAudience = authExtendedJWT.Key("expect_audience").MustString("")
 	cfg.ExtendedJWTExpectIssuer = authExtendedJWT.Key("expect_issuer").MustString("")
 
+	// Client certificate auth
+	authClientCert := iniFile.Section("auth.client_cert")
+	cfg.ClientCertAuthEnabled = authClientCert.Key("enabled").MustBool(false)
+	cfg.ClientCertAuthCACertFile = valueAsString(authClientCert, "ca_cert_file", "")
+	cfg.ClientCertAuthRulesFile = valueAsString(authClientCert, "rules_file", "")
+	cfg.ClientCertAuthAutoSignUp = authClientCert.Key("auto_sign_up").MustBool(false)
+	cfg.ClientCertAuthSkipOrgRoleSync = authClientCert.Key("skip_org_role_sync").MustBool(false)
+
 	// Auth Proxy
 	authProxy := iniFile.Section("auth.proxy")
 	cfg.AuthProxyEnabled = authProxy.Key("enabled").MustBool(false)
@@ -1859,6 +1905,7 @@ func readSnapshotsSettings(cfg *Cfg, iniFile *ini.File) error {
 	cfg.ExternalEnabled = snapshots.Key("external_enabled").MustBool(true)
 	cfg.SnapShotRemoveExpired = snapshots.Key("snapshot_remove_expired").MustBool(true)
 	cfg.SnapshotPublicMode = snapshots.Key("public_mode").MustBool(false)
+	cfg.SnapshotRecaptureMinInterval = snapshots.Key("recapture_min_interval").MustDuration(time.Hour)
 
 	return nil
 }
@@ -1960,6 +2007,7 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SqliteDatasourceAllowedPaths = util.SplitString(sqlDatasources.Key("sqlite_allowed_paths").MustString(""))
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
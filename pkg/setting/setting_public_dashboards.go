@@ -0,0 +1,16 @@
+package setting
+
+type PublicDashboardsSettings struct {
+	// QueryRateLimit is the number of queries per second allowed for each access token, 0 disables the limit.
+	QueryRateLimit float64
+	// QueryRateBurst is the number of queries allowed for an access token in a burst above the rate limit.
+	QueryRateBurst int
+}
+
+func (cfg *Cfg) readPublicDashboardsSettings() {
+	section := cfg.Raw.Section("public_dashboards")
+	cfg.PublicDashboards = PublicDashboardsSettings{
+		QueryRateLimit: section.Key("query_rate_limit").MustFloat64(0),
+		QueryRateBurst: section.Key("query_rate_limit_burst").MustInt(20),
+	}
+}
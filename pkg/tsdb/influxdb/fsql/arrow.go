@@ -20,7 +20,12 @@ import (
 // TODO: Make this configurable. This is an arbitrary value right
 // now. Grafana used to have a 1M row rowLimit established in open-source. I'll
 // let users hit that for now until we decide how to proceed.
-const rowLimit = 1_000_000
+var rowLimit int64 = 1_000_000
+
+// sizeLimit is the maximum size in bytes of the Arrow data copied into a
+// single frame. It protects Grafana from running out of memory on queries
+// with few but very wide rows, which the rowLimit does not catch.
+var sizeLimit int64 = 512 << 20
 
 type recordReader interface {
 	Next() bool
@@ -79,35 +84,87 @@ func newQueryDataResponse(reader recordReader, query sqlutil.Query, headers meta
 }
 
 // frameForRecords creates a [data.Frame] from a stream of [arrow.Record]s.
+//
+// Records are copied into the frame one at a time as they are read from the
+// stream, so only a single record is held in memory besides the frame. Reading
+// stops once either the rowLimit or the sizeLimit is reached, the record
+// reaching the limit is trimmed to the rows that fit and a notice is added to
+// the frame in that case.
 func frameForRecords(reader recordReader) (*data.Frame, error) {
 	var (
 		frame = newFrame(reader.Schema())
 		rows  int64
+		size  int64
 	)
 	for reader.Next() {
 		record := reader.Record()
+
+		var notice string
+		keep := record.NumRows()
+		recordSize := recordSize(record)
+		if size+recordSize > sizeLimit {
+			// the rows that fit are estimated from the average row size of the record
+			keep = (sizeLimit - size) * record.NumRows() / recordSize
+			if rows+keep == 0 {
+				return frame, fmt.Errorf("the first row of the results is larger than the SQL result size limit of %v bytes", sizeLimit)
+			}
+			notice = fmt.Sprintf("Results have been limited to %v rows because the SQL result size limit of %v bytes was reached", rows+keep, sizeLimit)
+		}
+		if rows+keep > rowLimit {
+			keep = rowLimit - rows
+			notice = fmt.Sprintf("Results have been limited to %v because the SQL row limit was reached", rowLimit)
+		}
+
+		if keep < record.NumRows() {
+			record = record.NewSlice(0, keep)
+			defer record.Release()
+		}
+
 		for i, col := range record.Columns() {
 			if err := copyData(frame.Fields[i], col); err != nil {
 				return frame, err
 			}
 		}
 
-		rows += record.NumRows()
-		if rows > rowLimit {
+		rows += keep
+		size += recordSize
+		if notice != "" {
 			frame.AppendNotices(data.Notice{
 				Severity: data.NoticeSeverityWarning,
-				Text:     fmt.Sprintf("Results have been limited to %v because the SQL row limit was reached", rowLimit),
+				Text:     notice,
 			})
 			return frame, nil
 		}
+	}
 
-		if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
-			return frame, err
-		}
+	if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return frame, err
 	}
 	return frame, nil
 }
 
+// recordSize returns the size in bytes of the buffers backing a record.
+func recordSize(record arrow.Record) int64 {
+	var size int64
+	for _, col := range record.Columns() {
+		size += arraySize(col.Data())
+	}
+	return size
+}
+
+func arraySize(d arrow.ArrayData) int64 {
+	var size int64
+	for _, buf := range d.Buffers() {
+		if buf != nil {
+			size += int64(buf.Len())
+		}
+	}
+	for _, child := range d.Children() {
+		size += arraySize(child)
+	}
+	return size
+}
+
 // newFrame builds a new Data Frame from an Arrow Schema.
 func newFrame(schema *arrow.Schema) *data.Frame {
 	fields := schema.Fields()
@@ -494,3 +494,85 @@ func TestCustomMetadata(t *testing.T) {
 		},
 	}, resp.Frames[0].Meta.Custom)
 }
+
+func TestFrameForRecords_RowLimit(t *testing.T) {
+	defer func(limit int64) { rowLimit = limit }(rowLimit)
+	rowLimit = 4
+
+	reader := newInt64RecordReader(t, `[1, 2, 3]`, `[4, 5, 6]`, `[7, 8, 9]`)
+	frame, err := frameForRecords(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, frame.Rows())
+	assert.Equal(t, []int64{1, 2, 3, 4}, extractFieldValues[int64](t, frame.Fields[0]))
+	assert.Len(t, frame.Meta.Notices, 1)
+	assert.Contains(t, frame.Meta.Notices[0].Text, "row limit")
+}
+
+func TestFrameForRecords_SizeLimit(t *testing.T) {
+	defer func(limit int64) { sizeLimit = limit }(sizeLimit)
+
+	reader := newInt64RecordReader(t, `[1, 2, 3]`, `[4, 5, 6]`, `[7, 8, 9]`)
+	reader.Next()
+	sizeLimit = recordSize(reader.Record()) * 2
+	reader.Release()
+
+	reader = newInt64RecordReader(t, `[1, 2, 3]`, `[4, 5, 6]`, `[7, 8, 9]`)
+	frame, err := frameForRecords(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, frame.Rows())
+	assert.Len(t, frame.Meta.Notices, 1)
+	assert.Contains(t, frame.Meta.Notices[0].Text, "size limit")
+}
+
+func TestFrameForRecords_SizeLimitInFirstRecord(t *testing.T) {
+	defer func(limit int64) { sizeLimit = limit }(sizeLimit)
+
+	reader := newInt64RecordReader(t, `[1, 2, 3, 4]`, `[5, 6, 7, 8]`)
+	reader.Next()
+	sizeLimit = recordSize(reader.Record()) / 2
+	reader.Release()
+
+	t.Run("keeps the rows of the first record that fit", func(t *testing.T) {
+		reader := newInt64RecordReader(t, `[1, 2, 3, 4]`, `[5, 6, 7, 8]`)
+		frame, err := frameForRecords(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, frame.Rows())
+		assert.Equal(t, int64(1), frame.Fields[0].At(0))
+		assert.Len(t, frame.Meta.Notices, 1)
+		assert.Equal(t, fmt.Sprintf("Results have been limited to 2 rows because the SQL result size limit of %v bytes was reached", sizeLimit), frame.Meta.Notices[0].Text)
+	})
+
+	t.Run("returns an error when a single row is over the limit", func(t *testing.T) {
+		sizeLimit = 1
+		reader := newInt64RecordReader(t, `[1, 2, 3, 4]`)
+		_, err := frameForRecords(reader)
+		assert.ErrorContains(t, err, "larger than the SQL result size limit")
+	})
+}
+
+func TestFrameForRecords_NoLimit(t *testing.T) {
+	reader := newInt64RecordReader(t, `[1, 2, 3]`, `[4, 5, 6]`)
+	frame, err := frameForRecords(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, frame.Rows())
+	assert.Empty(t, frame.Meta.Notices)
+}
+
+func newInt64RecordReader(t *testing.T, batches ...string) array.RecordReader {
+	t.Helper()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "value", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+
+	records := make([]arrow.Record, 0, len(batches))
+	for _, batch := range batches {
+		arr, _, err := array.FromJSON(memory.DefaultAllocator, arrow.PrimitiveTypes.Int64, strings.NewReader(batch))
+		assert.NoError(t, err)
+		records = append(records, array.NewRecord(schema, []arrow.Array{arr}, -1))
+	}
+
+	reader, err := array.NewRecordReader(schema, records)
+	assert.NoError(t, err)
+	return reader
+}
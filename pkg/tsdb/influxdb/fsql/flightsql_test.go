@@ -38,22 +38,24 @@ func TestIntegration_QueryData(t *testing.T) {
 	}()
 	defer server.Shutdown()
 
-	resp, err := Query(
-		context.Background(),
-		&models.DatasourceInfo{
-			HTTPClient: nil,
-			Token:      "secret",
-			URL:        "http://localhost:12345",
-			DbName:     "influxdb",
-			Version:    "test",
-			HTTPMode:   "proxy",
-			Metadata: []map[string]string{
-				{
-					"bucket": "bucket",
-				},
+	dsInfo := &models.DatasourceInfo{
+		HTTPClient: nil,
+		Token:      "secret",
+		URL:        "http://localhost:12345",
+		DbName:     "influxdb",
+		Version:    "test",
+		HTTPMode:   "proxy",
+		Metadata: []map[string]string{
+			{
+				"bucket": "bucket",
 			},
-			SecureGrpc: false,
 		},
+		SecureGrpc: false,
+	}
+
+	resp, err := Query(
+		context.Background(),
+		dsInfo,
 		backend.QueryDataRequest{
 			Queries: []backend.DataQuery{
 				{
@@ -81,6 +83,10 @@ func TestIntegration_QueryData(t *testing.T) {
 	for _, f := range frame.Fields {
 		assert.Equal(t, 4, f.Len())
 	}
+
+	found, err := CheckHealth(context.Background(), dsInfo)
+	require.NoError(t, err)
+	require.True(t, found)
 }
 
 func mustQueryJSON(t *testing.T, refID, sql string) []byte {
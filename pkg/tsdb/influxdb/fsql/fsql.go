@@ -2,11 +2,15 @@ package fsql
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/url"
 	"strings"
 
+	"github.com/apache/arrow/go/v12/arrow/flight"
+	"github.com/apache/arrow/go/v12/arrow/flight/flightsql"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"google.golang.org/grpc/metadata"
 
@@ -44,7 +48,7 @@ func Query(ctx context.Context, dsInfo *models.DatasourceInfo, req backend.Query
 	}
 
 	for _, q := range req.Queries {
-		qm, err := getQueryModel(q)
+		qm, err := getQueryModel(q, dsInfo)
 		if err != nil {
 			tRes.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusInternal, "bad request")
 			continue
@@ -78,6 +82,65 @@ func Query(ctx context.Context, dsInfo *models.DatasourceInfo, req backend.Query
 	return tRes, nil
 }
 
+// CheckHealth connects to the FlightSQL server and lists the tables visible
+// with the configured token and metadata. It returns whether there is at
+// least one table, and stops reading the list at the first one.
+func CheckHealth(ctx context.Context, dsInfo *models.DatasourceInfo) (bool, error) {
+	logger := glog.FromContext(ctx)
+	r, err := runnerFromDataSource(dsInfo)
+	if err != nil {
+		return false, err
+	}
+	defer func(client *client) {
+		err := client.Close()
+		if err != nil {
+			logger.Warn("Failed to close fsql client", "err", err)
+		}
+	}(r.client)
+
+	if r.client.md.Len() != 0 {
+		ctx = metadata.NewOutgoingContext(ctx, r.client.md)
+	}
+	// cancel the remaining streams once the first table is found
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	info, err := r.client.GetTables(ctx, &flightsql.GetTablesOpts{})
+	if err != nil {
+		return false, fmt.Errorf("flightsql: %w", err)
+	}
+
+	for _, endpoint := range info.Endpoint {
+		found, err := hasRows(ctx, r.client, endpoint.Ticket)
+		if err != nil {
+			return false, fmt.Errorf("flightsql: %w", err)
+		}
+		if found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasRows reads the stream of a ticket until the first non-empty record.
+func hasRows(ctx context.Context, c *client, ticket *flight.Ticket) (bool, error) {
+	reader, err := c.DoGet(ctx, ticket)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Release()
+
+	for reader.Next() {
+		if reader.Record().NumRows() > 0 {
+			return true, nil
+		}
+	}
+	if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	return false, nil
+}
+
 type runner struct {
 	client *client
 }
@@ -2,15 +2,19 @@ package fsql
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
 )
 
 var macros = sqlutil.Macros{
 	"dateBin":        macroDateBin(""),
 	"dateBinAlias":   macroDateBin("_binned"),
 	"interval":       macroInterval,
+	"interval_ms":    macroIntervalMs,
 	"timeGroup":      macroTimeGroup,
 	"timeGroupAlias": macroTimeGroupAlias,
 
@@ -24,6 +28,28 @@ var macros = sqlutil.Macros{
 	"timeFrom":      macroFrom,
 }
 
+// macrosForDatasource returns the macros available to a query, that is the
+// static macros above plus the ones depending on the datasource settings. These
+// match the bucket, defaultBucket and organization variables of Flux queries.
+func macrosForDatasource(dsInfo *models.DatasourceInfo) sqlutil.Macros {
+	m := make(sqlutil.Macros, len(macros)+4)
+	for name, macro := range macros {
+		m[name] = macro
+	}
+	m["database"] = macroString(dsInfo.DbName)
+	m["bucket"] = macroString(dsInfo.DbName)
+	m["defaultBucket"] = macroString(dsInfo.DefaultBucket)
+	m["organization"] = macroString(dsInfo.Organization)
+	return m
+}
+
+// macroString returns a macro that expands to value as a quoted SQL string literal.
+func macroString(value string) sqlutil.MacroFunc {
+	return func(_ *sqlutil.Query, _ []string) (string, error) {
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'", nil
+	}
+}
+
 func macroTimeGroup(query *sqlutil.Query, args []string) (string, error) {
 	if len(args) != 2 {
 		return "", fmt.Errorf("%w: expected 1 argument, received %d", sqlutil.ErrorBadArgumentCount, len(args))
@@ -84,6 +110,10 @@ func macroInterval(query *sqlutil.Query, _ []string) (string, error) {
 	return fmt.Sprintf("interval '%d second'", int64(query.Interval.Seconds())), nil
 }
 
+func macroIntervalMs(query *sqlutil.Query, _ []string) (string, error) {
+	return fmt.Sprintf("%d", query.Interval.Milliseconds()), nil
+}
+
 func macroFrom(query *sqlutil.Query, _ []string) (string, error) {
 	return fmt.Sprintf("cast('%s' as timestamp)", query.TimeRange.From.Format(time.RFC3339)), nil
 }
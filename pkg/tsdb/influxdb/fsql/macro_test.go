@@ -7,6 +7,8 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
 )
 
 func TestMacros(t *testing.T) {
@@ -20,6 +22,12 @@ func TestMacros(t *testing.T) {
 		Interval: 10 * time.Second,
 	}
 
+	dsInfo := &models.DatasourceInfo{
+		DbName:        "iox",
+		DefaultBucket: "default's",
+		Organization:  "org",
+	}
+
 	cs := []struct {
 		in  string
 		out string
@@ -32,6 +40,18 @@ func TestMacros(t *testing.T) {
 			in:  `select date_bin($__interval, time, timestamp '1970-01-01T00:00:00Z')`,
 			out: `select date_bin(interval '10 second', time, timestamp '1970-01-01T00:00:00Z')`,
 		},
+		{
+			in:  `select * from x where time > now() - interval '1 millisecond' * $__interval_ms`,
+			out: `select * from x where time > now() - interval '1 millisecond' * 10000`,
+		},
+		{
+			in:  `select * from information_schema.tables where table_catalog = $__database`,
+			out: `select * from information_schema.tables where table_catalog = 'iox'`,
+		},
+		{
+			in:  `select $__bucket, $__defaultBucket, $__organization`,
+			out: `select 'iox', 'default''s', 'org'`,
+		},
 		{
 			in:  `select $__dateBin(time)`,
 			out: `select date_bin(interval '10 second', time, timestamp '1970-01-01T00:00:00Z')`,
@@ -67,7 +87,7 @@ func TestMacros(t *testing.T) {
 	}
 	for _, c := range cs {
 		t.Run(c.in, func(t *testing.T) {
-			sql, err := sqlutil.Interpolate(query.WithSQL(c.in), macros)
+			sql, err := sqlutil.Interpolate(query.WithSQL(c.in), macrosForDatasource(dsInfo))
 			require.NoError(t, err)
 			require.Equal(t, c.out, sql)
 		})
@@ -7,6 +7,8 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
 )
 
 type queryModel struct {
@@ -23,7 +25,7 @@ type queryRequest struct {
 	Format               string `json:"format"`
 }
 
-func getQueryModel(dataQuery backend.DataQuery) (*queryModel, error) {
+func getQueryModel(dataQuery backend.DataQuery, dsInfo *models.DatasourceInfo) (*queryModel, error) {
 	var q queryRequest
 	if err := json.Unmarshal(dataQuery.JSON, &q); err != nil {
 		return nil, fmt.Errorf("unmarshal json: %w", err)
@@ -49,7 +51,7 @@ func getQueryModel(dataQuery backend.DataQuery) (*queryModel, error) {
 	}
 
 	// Process macros and execute the query.
-	sql, err := sqlutil.Interpolate(query, macros)
+	sql, err := sqlutil.Interpolate(query, macrosForDatasource(dsInfo))
 	if err != nil {
 		return nil, fmt.Errorf("macro interpolation: %w", err)
 	}
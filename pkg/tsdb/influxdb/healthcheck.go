@@ -119,38 +119,16 @@ func CheckInfluxQLHealth(ctx context.Context, dsInfo *models.DatasourceInfo, s *
 }
 
 func CheckSQLHealth(ctx context.Context, dsInfo *models.DatasourceInfo, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
-	ds, err := fsql.Query(ctx, dsInfo, backend.QueryDataRequest{
-		PluginContext: req.PluginContext,
-		Queries: []backend.DataQuery{
-			{
-				RefID:         refID,
-				JSON:          []byte(`{ "rawSql": "select 1", "format": "table" }`),
-				Interval:      1 * time.Minute,
-				MaxDataPoints: 423,
-				TimeRange: backend.TimeRange{
-					From: time.Now().AddDate(0, 0, -1),
-					To:   time.Now(),
-				},
-			},
-		},
-	})
-
+	logger := logger.FromContext(ctx)
+	found, err := fsql.CheckHealth(ctx, dsInfo)
 	if err != nil {
-		return getHealthCheckMessage(logger, "error performing sql query", err)
+		return getHealthCheckMessage(logger, "error connecting to FlightSQL", err)
 	}
-
-	res := ds.Responses[refID]
-	if res.Error != nil {
-		return &backend.CheckHealthResult{
-			Status:  backend.HealthStatusError,
-			Message: fmt.Sprintf("ERROR: %s", res.Error),
-		}, nil
+	if !found {
+		return getHealthCheckMessage(logger, "No tables found", nil)
 	}
 
-	return &backend.CheckHealthResult{
-		Status:  backend.HealthStatusOk,
-		Message: "OK",
-	}, nil
+	return getHealthCheckMessage(logger, "", nil)
 }
 
 func getHealthCheckMessage(logger log.Logger, message string, err error) (*backend.CheckHealthResult, error) {
@@ -66,6 +66,12 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		}
 
 		return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339), timeRange.To.UTC().Format(time.RFC3339)), nil
+	case "__timeFilterExclusive":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+
+		return fmt.Sprintf("%s >= '%s' AND %s < '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339), args[0], timeRange.To.UTC().Format(time.RFC3339)), nil
 	case "__timeFrom":
 		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339)), nil
 	case "__timeTo":
@@ -78,11 +84,14 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
-		if len(args) == 3 {
-			err := sqleng.SetupFillmode(query, interval, args[2])
-			if err != nil {
-				return "", err
-			}
+		tz, err := sqleng.SetupTimeGroupOptions(query, interval, args[2:])
+		if err != nil {
+			return "", err
+		}
+		if tz != "" {
+			// bucket the local wall clock time and convert the bucket start back to UTC
+			bucket := fmt.Sprintf("DATEADD(second, FLOOR(DATEDIFF(second, '1970-01-01', %s)/%.0f)*%.0f, '1970-01-01')", localTime(args[0], tz), interval.Seconds(), interval.Seconds())
+			return fmt.Sprintf("DATEDIFF(second, '1970-01-01', %s)", utcTime(bucket, tz)), nil
 		}
 		return fmt.Sprintf("FLOOR(DATEDIFF(second, '1970-01-01', %s)/%.0f)*%.0f", args[0], interval.Seconds(), interval.Seconds()), nil
 	case "__timeGroupAlias":
@@ -91,6 +100,30 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 			return tg + " AS [time]", nil
 		}
 		return "", err
+	case "__timeGroupDay", "__timeGroupWeek", "__timeGroupMonth":
+		tz, err := sqleng.ParseCalendarMacroArgs(name, args)
+		if err != nil {
+			return "", err
+		}
+		bucket, _ := sqleng.CalendarBucketFromMacro(name)
+		column := args[0]
+		if tz != "" {
+			column = localTime(column, tz)
+		}
+		var start string
+		switch bucket {
+		case sqleng.CalendarBucketWeek:
+			// weeks start on Monday regardless of the DATEFIRST setting
+			start = fmt.Sprintf("CAST(DATEADD(day, -((DATEPART(weekday, %s) + @@DATEFIRST - 2) %% 7), CAST(%s AS date)) AS datetime2)", column, column)
+		case sqleng.CalendarBucketMonth:
+			start = fmt.Sprintf("CAST(DATEFROMPARTS(YEAR(%s), MONTH(%s), 1) AS datetime2)", column, column)
+		default:
+			start = fmt.Sprintf("CAST(CAST(%s AS date) AS datetime2)", column)
+		}
+		if tz != "" {
+			start = utcTime(start, tz)
+		}
+		return fmt.Sprintf("DATEDIFF(second, '1970-01-01', %s)", start), nil
 	case "__unixEpochFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
@@ -130,3 +163,13 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		return "", fmt.Errorf("unknown macro %q", name)
 	}
 }
+
+// localTime converts a UTC time column to the wall clock time in tz.
+func localTime(column string, tz string) string {
+	return fmt.Sprintf("CAST(%s AT TIME ZONE 'UTC' AT TIME ZONE '%s' AS datetime2)", column, tz)
+}
+
+// utcTime converts a wall clock time in tz to UTC.
+func utcTime(column string, tz string) string {
+	return fmt.Sprintf("CAST(%s AT TIME ZONE '%s' AT TIME ZONE 'UTC' AS datetime2)", column, tz)
+}
@@ -147,6 +147,38 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "SELECT FLOOR(time_column/300)*300", sql)
 			require.Equal(t, sql+" AS [time]", sql2)
 		})
+
+		t.Run("interpolate __timeFilterExclusive function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilterExclusive(time_column)")
+			require.Nil(t, err)
+
+			require.Equal(t, "WHERE time_column >= '2018-04-12T18:00:00Z' AND time_column < '2018-04-12T18:05:00Z'", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with time zone", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'1h', , 'W. Europe Standard Time')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY DATEDIFF(second, '1970-01-01', CAST(DATEADD(second, FLOOR(DATEDIFF(second, '1970-01-01', CAST(time_column AT TIME ZONE 'UTC' AT TIME ZONE 'W. Europe Standard Time' AS datetime2))/3600)*3600, '1970-01-01') AT TIME ZONE 'W. Europe Standard Time' AT TIME ZONE 'UTC' AS datetime2))", sql)
+		})
+
+		t.Run("interpolate calendar group functions", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupDay(time_column)")
+			require.Nil(t, err)
+			require.Equal(t, "GROUP BY DATEDIFF(second, '1970-01-01', CAST(CAST(time_column AS date) AS datetime2))", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupWeek(time_column)")
+			require.Nil(t, err)
+			require.Equal(t, "GROUP BY DATEDIFF(second, '1970-01-01', CAST(DATEADD(day, -((DATEPART(weekday, time_column) + @@DATEFIRST - 2) % 7), CAST(time_column AS date)) AS datetime2))", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupWeek(time_column, 'W. Europe Standard Time')")
+			require.Nil(t, err)
+			require.Equal(t, "GROUP BY DATEDIFF(second, '1970-01-01', CAST(CAST(DATEADD(day, -((DATEPART(weekday, CAST(time_column AT TIME ZONE 'UTC' AT TIME ZONE 'W. Europe Standard Time' AS datetime2)) + @@DATEFIRST - 2) % 7), CAST(CAST(time_column AT TIME ZONE 'UTC' AT TIME ZONE 'W. Europe Standard Time' AS datetime2) AS date)) AS datetime2) AT TIME ZONE 'W. Europe Standard Time' AT TIME ZONE 'UTC' AS datetime2))", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupMonth(time_column)")
+			require.Nil(t, err)
+			require.Equal(t, "GROUP BY DATEDIFF(second, '1970-01-01', CAST(DATEFROMPARTS(YEAR(time_column), MONTH(time_column), 1) AS datetime2))", sql)
+		})
 	})
 
 	t.Run("Given a time range between 1960-02-01 07:00 and 1965-02-03 08:00", func(t *testing.T) {
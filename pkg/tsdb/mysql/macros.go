@@ -77,6 +77,14 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 			return fmt.Sprintf("%s BETWEEN DATE_ADD(FROM_UNIXTIME(0), INTERVAL %d SECOND) AND FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
 		}
 		return fmt.Sprintf("%s BETWEEN FROM_UNIXTIME(%d) AND FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
+	case "__timeFilterExclusive":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		if timeRange.From.UTC().Unix() < 0 {
+			return fmt.Sprintf("%s >= DATE_ADD(FROM_UNIXTIME(0), INTERVAL %d SECOND) AND %s < FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
+		}
+		return fmt.Sprintf("%s >= FROM_UNIXTIME(%d) AND %s < FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
 	case "__timeFrom":
 		return fmt.Sprintf("FROM_UNIXTIME(%d)", timeRange.From.UTC().Unix()), nil
 	case "__timeTo":
@@ -89,11 +97,15 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
-		if len(args) == 3 {
-			err := sqleng.SetupFillmode(query, interval, args[2])
-			if err != nil {
-				return "", err
-			}
+		tz, err := sqleng.SetupTimeGroupOptions(query, interval, args[2:])
+		if err != nil {
+			return "", err
+		}
+		if tz != "" {
+			// bucket the local wall clock time, without UNIX_TIMESTAMP and FROM_UNIXTIME which depend on the session time zone
+			bucket := fmt.Sprintf("DATE_ADD('%s', INTERVAL %s DIV %.0f * %.0f SECOND)",
+				epochDatetime, secondsSinceEpoch(localTime(args[0], tz)), interval.Seconds(), interval.Seconds())
+			return utcTimestamp(bucket, tz), nil
 		}
 		return fmt.Sprintf("UNIX_TIMESTAMP(%s) DIV %.0f * %.0f", args[0], interval.Seconds(), interval.Seconds()), nil
 	case "__timeGroupAlias":
@@ -102,6 +114,29 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 			return tg + " AS \"time\"", nil
 		}
 		return "", err
+	case "__timeGroupDay", "__timeGroupWeek", "__timeGroupMonth":
+		tz, err := sqleng.ParseCalendarMacroArgs(name, args)
+		if err != nil {
+			return "", err
+		}
+		bucket, _ := sqleng.CalendarBucketFromMacro(name)
+		column := args[0]
+		if tz != "" {
+			column = localTime(column, tz)
+		}
+		var start string
+		switch bucket {
+		case sqleng.CalendarBucketWeek:
+			start = fmt.Sprintf("DATE_SUB(DATE(%s), INTERVAL WEEKDAY(%s) DAY)", column, column)
+		case sqleng.CalendarBucketMonth:
+			start = fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-01')", column)
+		default:
+			start = fmt.Sprintf("DATE(%s)", column)
+		}
+		if tz != "" {
+			return utcTimestamp(start, tz), nil
+		}
+		return fmt.Sprintf("UNIX_TIMESTAMP(%s)", start), nil
 	case "__unixEpochFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
@@ -141,3 +176,19 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		return "", fmt.Errorf("unknown macro %v", name)
 	}
 }
+
+const epochDatetime = "1970-01-01 00:00:00"
+
+// localTime converts a time column holding UTC times to the wall clock time in tz.
+func localTime(column string, tz string) string {
+	return fmt.Sprintf("CONVERT_TZ(%s, '+00:00', '%s')", column, tz)
+}
+
+// utcTimestamp converts a wall clock time in tz to a Unix timestamp.
+func utcTimestamp(local string, tz string) string {
+	return secondsSinceEpoch(fmt.Sprintf("CONVERT_TZ(%s, '%s', '+00:00')", local, tz))
+}
+
+func secondsSinceEpoch(datetime string) string {
+	return fmt.Sprintf("TIMESTAMPDIFF(SECOND, '%s', %s)", epochDatetime, datetime)
+}
@@ -117,6 +117,34 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "SELECT time_column DIV 300 * 300", sql)
 			require.Equal(t, sql+" AS \"time\"", sql2)
 		})
+
+		t.Run("interpolate __timeFilterExclusive function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilterExclusive(time_column)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("WHERE time_column >= FROM_UNIXTIME(%d) AND time_column < FROM_UNIXTIME(%d)", from.Unix(), to.Unix()), sql)
+		})
+
+		t.Run("interpolate __timeGroup function with time zone", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'1h', , 'Europe/Berlin')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY TIMESTAMPDIFF(SECOND, '1970-01-01 00:00:00', CONVERT_TZ(DATE_ADD('1970-01-01 00:00:00', INTERVAL TIMESTAMPDIFF(SECOND, '1970-01-01 00:00:00', CONVERT_TZ(time_column, '+00:00', 'Europe/Berlin')) DIV 3600 * 3600 SECOND), 'Europe/Berlin', '+00:00'))", sql)
+		})
+
+		t.Run("interpolate calendar group functions", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupDay(time_column)")
+			require.Nil(t, err)
+			require.Equal(t, "GROUP BY UNIX_TIMESTAMP(DATE(time_column))", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupWeek(time_column)")
+			require.Nil(t, err)
+			require.Equal(t, "GROUP BY UNIX_TIMESTAMP(DATE_SUB(DATE(time_column), INTERVAL WEEKDAY(time_column) DAY))", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupMonth(time_column, 'Europe/Berlin')")
+			require.Nil(t, err)
+			require.Equal(t, "GROUP BY TIMESTAMPDIFF(SECOND, '1970-01-01 00:00:00', CONVERT_TZ(DATE_FORMAT(CONVERT_TZ(time_column, '+00:00', 'Europe/Berlin'), '%Y-%m-01'), 'Europe/Berlin', '+00:00'))", sql)
+		})
 	})
 
 	t.Run("Given a time range between 1960-02-01 07:00 and 1965-02-03 08:00", func(t *testing.T) {
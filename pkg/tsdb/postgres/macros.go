@@ -85,6 +85,12 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		}
 
 		return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339Nano), timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+	case "__timeFilterExclusive":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+
+		return fmt.Sprintf("%s >= '%s' AND %s < '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339Nano), args[0], timeRange.To.UTC().Format(time.RFC3339Nano)), nil
 	case "__timeFrom":
 		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339Nano)), nil
 	case "__timeTo":
@@ -97,11 +103,23 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
-		if len(args) == 3 {
-			err := sqleng.SetupFillmode(query, interval, args[2])
-			if err != nil {
-				return "", err
+		tz, err := sqleng.SetupTimeGroupOptions(query, interval, args[2:])
+		if err != nil {
+			return "", err
+		}
+
+		if tz != "" {
+			if m.timescaledb {
+				return fmt.Sprintf("time_bucket('%.3fs',%s,'%s')", interval.Seconds(), args[0], tz), nil
 			}
+			// bucket the local wall clock time and convert the bucket start back to an absolute timestamp
+			return fmt.Sprintf(
+				"(to_timestamp(floor(extract(epoch from %s AT TIME ZONE '%s')/%v)*%v) AT TIME ZONE 'UTC') AT TIME ZONE '%s'",
+				args[0], tz,
+				interval.Seconds(),
+				interval.Seconds(),
+				tz,
+			), nil
 		}
 
 		if m.timescaledb {
@@ -119,6 +137,16 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 			return tg + " AS \"time\"", nil
 		}
 		return "", err
+	case "__timeGroupDay", "__timeGroupWeek", "__timeGroupMonth":
+		tz, err := sqleng.ParseCalendarMacroArgs(name, args)
+		if err != nil {
+			return "", err
+		}
+		bucket, _ := sqleng.CalendarBucketFromMacro(name)
+		if tz == "" {
+			return fmt.Sprintf("date_trunc('%s', %s)", bucket, args[0]), nil
+		}
+		return fmt.Sprintf("date_trunc('%s', %s AT TIME ZONE '%s') AT TIME ZONE '%s'", bucket, args[0], tz, tz), nil
 	case "__unixEpochFilter":
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
@@ -144,6 +144,49 @@ func TestMacroEngine(t *testing.T) {
 			require.Equal(t, "SELECT floor((time_column+time_adjustment)/300)*300", sql)
 			require.Equal(t, sql2, sql+" AS \"time\"")
 		})
+
+		t.Run("interpolate __timeFilterExclusive function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilterExclusive(time_column)")
+			require.NoError(t, err)
+
+			require.Equal(t, "WHERE time_column >= '2018-04-12T18:00:00Z' AND time_column < '2018-04-12T18:05:00Z'", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with time zone", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "SELECT $__timeGroup(time_column,'1h', , 'Europe/Berlin')")
+			require.NoError(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "SELECT $__timeGroupAlias(time_column,'1h', , 'Europe/Berlin')")
+			require.NoError(t, err)
+
+			require.Equal(t, "SELECT (to_timestamp(floor(extract(epoch from time_column AT TIME ZONE 'Europe/Berlin')/3600)*3600) AT TIME ZONE 'UTC') AT TIME ZONE 'Europe/Berlin'", sql)
+			require.Equal(t, sql2, sql+" AS \"time\"")
+		})
+
+		t.Run("interpolate __timeGroup function with time zone and TimescaleDB enabled", func(t *testing.T) {
+			sql, err := engineTS.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'1h', , 'Europe/Berlin')")
+			require.NoError(t, err)
+
+			require.Equal(t, "GROUP BY time_bucket('3600.000s',time_column,'Europe/Berlin')", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with invalid time zone", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'1h', , 'UTC''; drop table x')")
+			require.Error(t, err)
+		})
+
+		t.Run("interpolate calendar group functions", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupDay(time_column)")
+			require.NoError(t, err)
+			require.Equal(t, "GROUP BY date_trunc('day', time_column)", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupWeek(time_column, 'America/New_York')")
+			require.NoError(t, err)
+			require.Equal(t, "GROUP BY date_trunc('week', time_column AT TIME ZONE 'America/New_York') AT TIME ZONE 'America/New_York'", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupMonth(time_column, 'UTC')")
+			require.NoError(t, err)
+			require.Equal(t, "GROUP BY date_trunc('month', time_column AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'", sql)
+		})
 	})
 
 	t.Run("Given a time range between 1960-02-01 07:00 and 1965-02-03 08:00", func(t *testing.T) {
@@ -9,6 +9,14 @@
 
 package dataquery
 
+// Defines values for ExemplarSamplingStrategy.
+const (
+	ExemplarSamplingStrategyNone    ExemplarSamplingStrategy = "none"
+	ExemplarSamplingStrategyOutlier ExemplarSamplingStrategy = "outlier"
+	ExemplarSamplingStrategyStddev  ExemplarSamplingStrategy = "stddev"
+	ExemplarSamplingStrategyUniform ExemplarSamplingStrategy = "uniform"
+)
+
 // Defines values for PromQueryFormat.
 const (
 	PromQueryFormatHeatmap    PromQueryFormat = "heatmap"
@@ -47,6 +55,19 @@ type DataQuery struct {
 	RefId string `json:"refId"`
 }
 
+// ExemplarSampling defines model for ExemplarSampling.
+type ExemplarSampling struct {
+	// Maximum number of exemplars returned for the query, no limit when not set
+	MaxExemplars *int64 `json:"maxExemplars,omitempty"`
+
+	// Strategy used to sample the exemplars, "stddev" keeps the exemplars at least two standard deviations apart,
+	// "uniform" keeps one exemplar per time interval, "outlier" keeps the exemplars furthest from the mean
+	Strategy *ExemplarSamplingStrategy `json:"strategy,omitempty"`
+}
+
+// ExemplarSamplingStrategy defines model for ExemplarSamplingStrategy.
+type ExemplarSamplingStrategy string
+
 // PromQueryFormat defines model for PromQueryFormat.
 type PromQueryFormat string
 
@@ -67,6 +88,9 @@ type PrometheusDataQuery struct {
 	// Execute an additional query to identify interesting raw samples relevant for the given expr
 	Exemplar *bool `json:"exemplar,omitempty"`
 
+	// Sampling applied to the exemplars of the query to reduce their density
+	ExemplarSampling *ExemplarSampling `json:"exemplarSampling,omitempty"`
+
 	// The actual expression/query that will be evaluated by Prometheus
 	Expr   string           `json:"expr"`
 	Format *PromQueryFormat `json:"format,omitempty"`
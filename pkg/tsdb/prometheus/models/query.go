@@ -74,6 +74,10 @@ type Query struct {
 	RangeQuery    bool
 	ExemplarQuery bool
 	UtcOffsetSec  int64
+	Format        dataquery.PromQueryFormat
+	// the data source default sampling strategy is used when empty
+	ExemplarSampling dataquery.ExemplarSamplingStrategy
+	MaxExemplars     int
 }
 
 func Parse(query backend.DataQuery, timeInterval string, intervalCalculator intervalv2.Calculator, fromAlert bool) (*Query, error) {
@@ -116,17 +120,36 @@ func Parse(query backend.DataQuery, timeInterval string, intervalCalculator inte
 		exemplarQuery = false
 	}
 
+	var exemplarSampling dataquery.ExemplarSamplingStrategy
+	var maxExemplars int
+	if model.ExemplarSampling != nil {
+		if model.ExemplarSampling.Strategy != nil {
+			exemplarSampling = *model.ExemplarSampling.Strategy
+		}
+		if model.ExemplarSampling.MaxExemplars != nil && *model.ExemplarSampling.MaxExemplars > 0 {
+			maxExemplars = int(*model.ExemplarSampling.MaxExemplars)
+		}
+	}
+
+	format := dataquery.PromQueryFormatTimeSeries
+	if model.Format != nil {
+		format = *model.Format
+	}
+
 	return &Query{
-		Expr:          expr,
-		Step:          interval,
-		LegendFormat:  model.LegendFormat,
-		Start:         query.TimeRange.From,
-		End:           query.TimeRange.To,
-		RefId:         query.RefID,
-		InstantQuery:  instantQuery,
-		RangeQuery:    rangeQuery,
-		ExemplarQuery: exemplarQuery,
-		UtcOffsetSec:  model.UtcOffsetSec,
+		Expr:             expr,
+		Step:             interval,
+		LegendFormat:     model.LegendFormat,
+		Start:            query.TimeRange.From,
+		End:              query.TimeRange.To,
+		RefId:            query.RefID,
+		InstantQuery:     instantQuery,
+		RangeQuery:       rangeQuery,
+		ExemplarQuery:    exemplarQuery,
+		UtcOffsetSec:     model.UtcOffsetSec,
+		Format:           format,
+		ExemplarSampling: exemplarSampling,
+		MaxExemplars:     maxExemplars,
 	}, nil
 }
 
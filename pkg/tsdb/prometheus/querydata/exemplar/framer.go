@@ -14,6 +14,7 @@ type Framer struct {
 	labelTracker LabelTracker
 	meta         *data.FrameMeta
 	refID        string
+	destinations []TraceIDDestination
 }
 
 func NewFramer(sampler Sampler, labelTracker LabelTracker) *Framer {
@@ -32,6 +33,11 @@ func (f *Framer) SetRefID(refID string) {
 	f.refID = refID
 }
 
+// SetTraceIDDestinations sets the exemplar labels that link to a tracing data source or URL.
+func (f *Framer) SetTraceIDDestinations(destinations []TraceIDDestination) {
+	f.destinations = destinations
+}
+
 func (f *Framer) AddFrame(frame *data.Frame) {
 	f.frames = append(f.frames, frame)
 }
@@ -57,7 +63,17 @@ func (f *Framer) Frames() (data.Frames, error) {
 	labelNames := f.labelTracker.GetNames()
 	exemplarLabels := make(map[string]string, len(labelNames))
 	for _, labelName := range labelNames {
-		exemplarFrame.Fields = append(exemplarFrame.Fields, data.NewField(labelName, nil, make([]string, 0, len(exemplars))))
+		labelField := data.NewField(labelName, nil, make([]string, 0, len(exemplars)))
+		for _, destination := range f.destinations {
+			if destination.Name != labelName {
+				continue
+			}
+			if labelField.Config == nil {
+				labelField.Config = &data.FieldConfig{}
+			}
+			labelField.Config.Links = append(labelField.Config.Links, destination.Links()...)
+		}
+		exemplarFrame.Fields = append(exemplarFrame.Fields, labelField)
 	}
 
 	// add the sampled exemplars to the new exemplar frame
@@ -0,0 +1,54 @@
+package exemplar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
+)
+
+func TestFramerTraceLinks(t *testing.T) {
+	sampler := exemplar.NewNoOpSampler()
+	labelTracker := exemplar.NewLabelTracker()
+	labelTracker.Add(map[string]string{"traceID": "", "job": ""})
+	sampler.Add(models.Exemplar{
+		SeriesLabels: map[string]string{"traceID": "abc", "job": "api"},
+		Value:        1,
+		Timestamp:    time.Unix(0, 0),
+	})
+
+	framer := exemplar.NewFramer(sampler, labelTracker)
+	framer.SetTraceIDDestinations([]exemplar.TraceIDDestination{
+		{Name: "traceID", DatasourceUID: "tempo"},
+		{Name: "traceID", URL: "http://localhost/trace/${__value.raw}", URLDisplayLabel: "Open trace"},
+		{Name: "missing", URL: "http://localhost"},
+	})
+
+	frames, err := framer.Frames()
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+
+	job, _ := frames[0].FieldByName("job")
+	require.NotNil(t, job)
+	require.Nil(t, job.Config)
+
+	traceID, _ := frames[0].FieldByName("traceID")
+	require.NotNil(t, traceID)
+	require.Equal(t, []data.DataLink{
+		{
+			Internal: &data.InternalDataLink{
+				Query:         map[string]string{"query": "${__value.raw}", "queryType": "traceql"},
+				DatasourceUID: "tempo",
+			},
+		},
+		{
+			Title:       "Open trace",
+			URL:         "http://localhost/trace/${__value.raw}",
+			TargetBlank: true,
+		},
+	}, traceID.Config.Links)
+}
@@ -0,0 +1,46 @@
+package exemplar
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// TraceIDDestination is an exemplar label linked to a tracing data source or
+// URL, as configured in the exemplarTraceIdDestinations data source setting.
+type TraceIDDestination struct {
+	Name            string `json:"name"`
+	URL             string `json:"url,omitempty"`
+	URLDisplayLabel string `json:"urlDisplayLabel,omitempty"`
+	DatasourceUID   string `json:"datasourceUid,omitempty"`
+}
+
+// Links returns the data links for an exemplar label field.
+func (d TraceIDDestination) Links() []data.DataLink {
+	links := []data.DataLink{}
+	if d.DatasourceUID != "" {
+		// the frontend names the link after the data source when there is no label
+		links = append(links, data.DataLink{
+			Title: d.URLDisplayLabel,
+			Internal: &data.InternalDataLink{
+				Query: map[string]string{
+					"query":     "${__value.raw}",
+					"queryType": "traceql",
+				},
+				DatasourceUID: d.DatasourceUID,
+			},
+		})
+	}
+	if d.URL != "" {
+		title := d.URLDisplayLabel
+		if title == "" {
+			title = fmt.Sprintf("Go to %s", d.URL)
+		}
+		links = append(links, data.DataLink{
+			Title:       title,
+			URL:         d.URL,
+			TargetBlank: true,
+		})
+	}
+	return links
+}
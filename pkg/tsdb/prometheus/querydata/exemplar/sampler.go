@@ -1,9 +1,11 @@
 package exemplar
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/kinds/dataquery"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
 )
 
@@ -14,6 +16,50 @@ type Sampler interface {
 	Reset()
 }
 
+// NewSampler returns a sampler for the strategy. When maxExemplars is above
+// zero, the sampler returns at most maxExemplars exemplars.
+func NewSampler(strategy dataquery.ExemplarSamplingStrategy, maxExemplars int) (Sampler, error) {
+	var sampler Sampler
+	switch strategy {
+	case dataquery.ExemplarSamplingStrategyStddev:
+		sampler = NewStandardDeviationSampler()
+	case dataquery.ExemplarSamplingStrategyUniform:
+		sampler = NewUniformSampler()
+	case dataquery.ExemplarSamplingStrategyOutlier:
+		// the outlier sampler picks the exemplars to drop itself
+		return NewOutlierSampler(maxExemplars), nil
+	case dataquery.ExemplarSamplingStrategyNone:
+		sampler = NewNoOpSampler()
+	default:
+		return nil, fmt.Errorf("unknown exemplar sampling strategy %q", strategy)
+	}
+
+	if maxExemplars > 0 {
+		return &limitSampler{Sampler: sampler, maxExemplars: maxExemplars}, nil
+	}
+	return sampler, nil
+}
+
+// limitSampler limits the number of exemplars returned by a sampler. The kept
+// exemplars are spread evenly over the sampled ones.
+type limitSampler struct {
+	Sampler
+	maxExemplars int
+}
+
+func (e *limitSampler) Sample() []models.Exemplar {
+	exemplars := e.Sampler.Sample()
+	if len(exemplars) <= e.maxExemplars {
+		return exemplars
+	}
+
+	limited := make([]models.Exemplar, 0, e.maxExemplars)
+	for i := 0; i < e.maxExemplars; i++ {
+		limited = append(limited, exemplars[i*len(exemplars)/e.maxExemplars])
+	}
+	return limited
+}
+
 var _ Sampler = (*NoOpSampler)(nil)
 
 type NoOpSampler struct {
@@ -0,0 +1,77 @@
+package exemplar
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+)
+
+// OutlierSampler keeps the exemplar furthest from the mean of all the
+// exemplar values in each step. When limited, the exemplars furthest from the
+// mean are kept rather than the ones spread evenly over the time range.
+type OutlierSampler struct {
+	step         time.Duration
+	maxExemplars int
+	buckets      map[time.Time][]models.Exemplar
+	count        int
+	mean         float64
+}
+
+func NewOutlierSampler(maxExemplars int) Sampler {
+	return &OutlierSampler{
+		maxExemplars: maxExemplars,
+		buckets:      map[time.Time][]models.Exemplar{},
+	}
+}
+
+func (e *OutlierSampler) SetStep(step time.Duration) {
+	e.step = step
+}
+
+func (e *OutlierSampler) Add(ex models.Exemplar) {
+	bucketTs := models.AlignTimeRange(ex.Timestamp, e.step, 0)
+	e.count++
+	e.mean += (ex.Value - e.mean) / float64(e.count)
+	e.buckets[bucketTs] = append(e.buckets[bucketTs], ex)
+}
+
+func (e *OutlierSampler) distance(ex models.Exemplar) float64 {
+	return math.Abs(ex.Value - e.mean)
+}
+
+func (e *OutlierSampler) Sample() []models.Exemplar {
+	exemplars := make([]models.Exemplar, 0, len(e.buckets))
+	for _, b := range e.buckets {
+		outlier := b[0]
+		for _, ex := range b[1:] {
+			if e.distance(ex) > e.distance(outlier) {
+				outlier = ex
+			}
+		}
+		exemplars = append(exemplars, outlier)
+	}
+
+	byTime := func(i, j int) bool {
+		return exemplars[i].Timestamp.Before(exemplars[j].Timestamp)
+	}
+	sort.SliceStable(exemplars, byTime)
+
+	if e.maxExemplars > 0 && len(exemplars) > e.maxExemplars {
+		// equally distant exemplars stay in time order, so the earliest ones are kept
+		sort.SliceStable(exemplars, func(i, j int) bool {
+			return e.distance(exemplars[i]) > e.distance(exemplars[j])
+		})
+		exemplars = exemplars[:e.maxExemplars]
+		sort.SliceStable(exemplars, byTime)
+	}
+	return exemplars
+}
+
+func (e *OutlierSampler) Reset() {
+	e.step = 0
+	e.buckets = map[time.Time][]models.Exemplar{}
+	e.count = 0
+	e.mean = 0
+}
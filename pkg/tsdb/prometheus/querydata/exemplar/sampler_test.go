@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/experimental"
+	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/kinds/dataquery"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
 )
@@ -28,6 +30,29 @@ func TestNoOpSampler(t *testing.T) {
 	})
 }
 
+func TestNewSampler(t *testing.T) {
+	tr := models.TimeRange{
+		Start: time.Unix(0, 0),
+		End:   time.Unix(2000, 0),
+	}
+
+	t.Run("limits the number of exemplars", func(t *testing.T) {
+		sampler, err := exemplar.NewSampler(dataquery.ExemplarSamplingStrategyNone, 4)
+		require.NoError(t, err)
+		for _, ex := range generateTestExemplars(tr) {
+			sampler.Add(ex)
+		}
+		sampled := sampler.Sample()
+		require.Len(t, sampled, 4)
+		require.Equal(t, []float64{0, 500, 1000, 1500}, []float64{sampled[0].Value, sampled[1].Value, sampled[2].Value, sampled[3].Value})
+	})
+
+	t.Run("returns an error for unknown strategies", func(t *testing.T) {
+		_, err := exemplar.NewSampler("random", 0)
+		require.Error(t, err)
+	})
+}
+
 func generateTestExemplars(tr models.TimeRange) []models.Exemplar {
 	exemplars := []models.Exemplar{}
 	next := tr.Start.UTC()
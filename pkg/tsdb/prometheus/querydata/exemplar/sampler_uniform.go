@@ -0,0 +1,49 @@
+package exemplar
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+)
+
+// UniformSampler keeps a single exemplar per step, the earliest one, so the
+// sampled exemplars are spread evenly over the time range.
+type UniformSampler struct {
+	step    time.Duration
+	buckets map[time.Time]models.Exemplar
+}
+
+func NewUniformSampler() Sampler {
+	return &UniformSampler{
+		buckets: map[time.Time]models.Exemplar{},
+	}
+}
+
+func (e *UniformSampler) SetStep(step time.Duration) {
+	e.step = step
+}
+
+func (e *UniformSampler) Add(ex models.Exemplar) {
+	bucketTs := models.AlignTimeRange(ex.Timestamp, e.step, 0)
+	if prev, exists := e.buckets[bucketTs]; exists && !ex.Timestamp.Before(prev.Timestamp) {
+		return
+	}
+	e.buckets[bucketTs] = ex
+}
+
+func (e *UniformSampler) Sample() []models.Exemplar {
+	exemplars := make([]models.Exemplar, 0, len(e.buckets))
+	for _, ex := range e.buckets {
+		exemplars = append(exemplars, ex)
+	}
+	sort.SliceStable(exemplars, func(i, j int) bool {
+		return exemplars[i].Timestamp.Before(exemplars[j].Timestamp)
+	})
+	return exemplars
+}
+
+func (e *UniformSampler) Reset() {
+	e.step = 0
+	e.buckets = map[time.Time]models.Exemplar{}
+}
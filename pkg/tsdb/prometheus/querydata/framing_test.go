@@ -32,6 +32,8 @@ func TestRangeResponses(t *testing.T) {
 		{name: "parse a matrix response with Infinity", filepath: "range_infinity"},
 		{name: "parse a matrix response with NaN", filepath: "range_nan"},
 		{name: "parse a response with legendFormat __auto", filepath: "range_auto"},
+		{name: "parse a native histogram response", filepath: "range_histogram"},
+		{name: "parse a native histogram response with the heatmap format", filepath: "range_histogram_heatmap"},
 	}
 
 	for _, test := range tt {
@@ -91,6 +93,7 @@ type storedPrometheusQuery struct {
 	Step          int64
 	Expr          string
 	LegendFormat  string
+	Format        string
 }
 
 func loadStoredQuery(fileName string) (*backend.QueryDataRequest, error) {
@@ -117,6 +120,10 @@ func loadStoredQuery(fileName string) (*backend.QueryDataRequest, error) {
 		IntervalMs:   sq.Step * 1000,
 		LegendFormat: sq.LegendFormat,
 	}
+	if sq.Format != "" {
+		format := dataquery.PromQueryFormat(sq.Format)
+		qm.Format = &format
+	}
 
 	data, err := json.Marshal(&qm)
 	if err != nil {
@@ -2,6 +2,7 @@ package querydata
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -16,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/client"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/kinds/dataquery"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/utils"
@@ -43,7 +45,8 @@ type QueryData struct {
 	URL                string
 	TimeInterval       string
 	enableDataplane    bool
-	exemplarSampler    func() exemplar.Sampler
+	exemplarSampling   dataquery.ExemplarSamplingStrategy
+	traceDestinations  []exemplar.TraceIDDestination
 }
 
 func New(
@@ -64,13 +67,20 @@ func New(
 		return nil, err
 	}
 
+	var destinations struct {
+		ExemplarTraceIDDestinations []exemplar.TraceIDDestination `json:"exemplarTraceIdDestinations"`
+	}
+	if err := json.Unmarshal(settings.JSONData, &destinations); err != nil {
+		return nil, fmt.Errorf("error reading exemplarTraceIdDestinations: %w", err)
+	}
+
 	promClient := client.NewClient(httpClient, httpMethod, settings.URL)
 
 	// standard deviation sampler is the default for backwards compatibility
-	exemplarSampler := exemplar.NewStandardDeviationSampler
+	exemplarSampling := dataquery.ExemplarSamplingStrategyStddev
 
 	if features.IsEnabled(featuremgmt.FlagDisablePrometheusExemplarSampling) {
-		exemplarSampler = exemplar.NewNoOpSampler
+		exemplarSampling = dataquery.ExemplarSamplingStrategyNone
 	}
 
 	return &QueryData{
@@ -82,7 +92,8 @@ func New(
 		ID:                 settings.ID,
 		URL:                settings.URL,
 		enableDataplane:    features.IsEnabled(featuremgmt.FlagPrometheusDataplane),
-		exemplarSampler:    exemplarSampler,
+		exemplarSampling:   exemplarSampling,
+		traceDestinations:  destinations.ExemplarTraceIDDestinations,
 	}, nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	jsoniter "github.com/json-iterator/go"
 
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/kinds/dataquery"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/utils"
@@ -33,6 +34,12 @@ func (s *QueryData) parseResponse(ctx context.Context, q *models.Query, res *htt
 		Dataplane: s.enableDataplane,
 	})
 
+	// Classic histograms are queried with the heatmap format, return native
+	// histograms the same way so the existing panels keep working.
+	if r.Error == nil && q.Format == dataquery.PromQueryFormatHeatmap {
+		r = histogramsToBuckets(r)
+	}
+
 	// Add frame to attach metadata
 	if len(r.Frames) == 0 && !q.ExemplarQuery {
 		r.Frames = append(r.Frames, data.NewFrame(""))
@@ -56,7 +63,14 @@ func (s *QueryData) parseResponse(ctx context.Context, q *models.Query, res *htt
 func (s *QueryData) processExemplars(ctx context.Context, q *models.Query, dr backend.DataResponse) backend.DataResponse {
 	_, endSpan := utils.StartTrace(ctx, s.tracer, "datasource.prometheus.processExemplars", []utils.Attribute{})
 	defer endSpan()
-	sampler := s.exemplarSampler()
+	strategy := q.ExemplarSampling
+	if strategy == "" {
+		strategy = s.exemplarSampling
+	}
+	sampler, err := exemplar.NewSampler(strategy, q.MaxExemplars)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
 	labelTracker := exemplar.NewLabelTracker()
 
 	// we are moving from a multi-frame response returned
@@ -64,6 +78,7 @@ func (s *QueryData) processExemplars(ctx context.Context, q *models.Query, dr ba
 	// so we need to build a new frame array with the
 	// old exemplar frames filtered out
 	framer := exemplar.NewFramer(sampler, labelTracker)
+	framer.SetTraceIDDestinations(s.traceDestinations)
 
 	for _, frame := range dr.Frames {
 		// we don't need to process non-exemplar frames
@@ -105,6 +120,26 @@ func (s *QueryData) processExemplars(ctx context.Context, q *models.Query, dr ba
 	}
 }
 
+// histogramsToBuckets replaces the heatmap-cells frames of native histograms
+// with one series per bucket, labeled with the bucket upper bound like classic
+// histograms.
+func histogramsToBuckets(dr backend.DataResponse) backend.DataResponse {
+	frames := make(data.Frames, 0, len(dr.Frames))
+	for _, frame := range dr.Frames {
+		if !converter.IsHeatmapCellsFrame(frame) {
+			frames = append(frames, frame)
+			continue
+		}
+		buckets, err := converter.HeatmapCellsToBuckets(frame)
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+		frames = append(frames, buckets...)
+	}
+	dr.Frames = frames
+	return dr
+}
+
 func addMetadataToMultiFrame(q *models.Query, frame *data.Frame, enableDataplane bool) {
 	if frame.Meta == nil {
 		frame.Meta = &data.FrameMeta{}
@@ -114,6 +149,15 @@ func addMetadataToMultiFrame(q *models.Query, frame *data.Frame, enableDataplane
 	}
 	frame.Fields[0].Config = &data.FieldConfig{Interval: float64(q.Step.Milliseconds())}
 
+	if converter.IsHeatmapCellsFrame(frame) {
+		// The series labels of native histograms are stored on the yMin field,
+		// the field names are part of the heatmap-cells format and must be kept.
+		if !enableDataplane {
+			frame.Name = getName(q, frame.Fields[1])
+		}
+		return
+	}
+
 	customName := getName(q, frame.Fields[1])
 	if customName != "" {
 		frame.Fields[1].Config = &data.FieldConfig{DisplayNameFromDS: customName}
@@ -0,0 +1,80 @@
+package sqleng
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// CalendarBucket is a calendar unit used by the $__timeGroupDay, $__timeGroupWeek and
+// $__timeGroupMonth macros. Unlike $__timeGroup, calendar buckets do not have a fixed length.
+type CalendarBucket string
+
+const (
+	CalendarBucketDay   CalendarBucket = "day"
+	CalendarBucketWeek  CalendarBucket = "week"
+	CalendarBucketMonth CalendarBucket = "month"
+)
+
+var calendarBucketMacros = map[string]CalendarBucket{
+	"__timeGroupDay":   CalendarBucketDay,
+	"__timeGroupWeek":  CalendarBucketWeek,
+	"__timeGroupMonth": CalendarBucketMonth,
+}
+
+// CalendarBucketFromMacro returns the calendar bucket for a calendar macro name, e.g. __timeGroupDay.
+func CalendarBucketFromMacro(name string) (CalendarBucket, bool) {
+	bucket, ok := calendarBucketMacros[name]
+	return bucket, ok
+}
+
+// timezoneRegExp matches IANA (Europe/Berlin, Etc/GMT+2) and Windows (W. Europe Standard Time)
+// time zone names. Anything else is rejected, since the name ends up quoted in the generated SQL.
+var timezoneRegExp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_/+\-. ]*$`)
+
+// ParseMacroTimezone validates a time zone macro argument and returns it without quotes.
+// An empty argument returns an empty string, which means the query is not time zone aware.
+func ParseMacroTimezone(arg string) (string, error) {
+	tz := strings.Trim(arg, `'"`)
+	if tz == "" {
+		return "", nil
+	}
+	if !timezoneRegExp.MatchString(tz) {
+		return "", fmt.Errorf("invalid time zone %v", arg)
+	}
+	return tz, nil
+}
+
+// SetupTimeGroupOptions handles the optional arguments of the $__timeGroup family of macros,
+// that is an optional fill value followed by an optional time zone, e.g.
+// $__timeGroup(time_column, '1h', NULL, 'Europe/Berlin'). The fill value can be left empty to only
+// set the time zone: $__timeGroup(time_column, '1h', , 'Europe/Berlin').
+func SetupTimeGroupOptions(query *backend.DataQuery, interval time.Duration, options []string) (string, error) {
+	if len(options) > 2 {
+		return "", fmt.Errorf("too many arguments, expected optional fill value and time zone")
+	}
+	if len(options) > 0 && options[0] != "" {
+		if err := SetupFillmode(query, interval, options[0]); err != nil {
+			return "", err
+		}
+	}
+	if len(options) == 2 {
+		return ParseMacroTimezone(options[1])
+	}
+	return "", nil
+}
+
+// ParseCalendarMacroArgs validates the arguments of the calendar macros, that is a time column followed
+// by an optional time zone, and returns the time zone.
+func ParseCalendarMacroArgs(name string, args []string) (string, error) {
+	if len(args) == 0 || args[0] == "" || len(args) > 2 {
+		return "", fmt.Errorf("macro %v needs time column and optional time zone", name)
+	}
+	if len(args) == 2 {
+		return ParseMacroTimezone(args[1])
+	}
+	return "", nil
+}
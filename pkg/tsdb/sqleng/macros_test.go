@@ -0,0 +1,75 @@
+package sqleng
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMacroTimezone(t *testing.T) {
+	for _, tz := range []string{"'Europe/Berlin'", "\"America/Argentina/Buenos_Aires\"", "Etc/GMT+2", "'W. Europe Standard Time'", "UTC"} {
+		parsed, err := ParseMacroTimezone(tz)
+		require.NoError(t, err)
+		require.NotContains(t, parsed, "'")
+	}
+
+	parsed, err := ParseMacroTimezone("''")
+	require.NoError(t, err)
+	require.Empty(t, parsed)
+
+	for _, tz := range []string{"'UTC'); drop table x; --'", "+01:00", "UTC\\"} {
+		_, err := ParseMacroTimezone(tz)
+		require.Error(t, err, tz)
+	}
+}
+
+func TestSetupTimeGroupOptions(t *testing.T) {
+	t.Run("time zone without fill value", func(t *testing.T) {
+		query := &backend.DataQuery{JSON: []byte("{}")}
+		tz, err := SetupTimeGroupOptions(query, time.Hour, []string{"", "'Europe/Berlin'"})
+		require.NoError(t, err)
+		require.Equal(t, "Europe/Berlin", tz)
+		require.JSONEq(t, "{}", string(query.JSON))
+	})
+
+	t.Run("fill value and time zone", func(t *testing.T) {
+		query := &backend.DataQuery{JSON: []byte("{}")}
+		tz, err := SetupTimeGroupOptions(query, time.Hour, []string{"NULL", "'Europe/Berlin'"})
+		require.NoError(t, err)
+		require.Equal(t, "Europe/Berlin", tz)
+
+		props := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(query.JSON, &props))
+		require.Equal(t, "null", props["fillMode"])
+		require.Equal(t, float64(3600), props["fillInterval"])
+	})
+
+	t.Run("too many arguments", func(t *testing.T) {
+		query := &backend.DataQuery{JSON: []byte("{}")}
+		_, err := SetupTimeGroupOptions(query, time.Hour, []string{"NULL", "'UTC'", "x"})
+		require.Error(t, err)
+	})
+}
+
+func TestParseCalendarMacroArgs(t *testing.T) {
+	tz, err := ParseCalendarMacroArgs("__timeGroupDay", []string{"time_column"})
+	require.NoError(t, err)
+	require.Empty(t, tz)
+
+	tz, err = ParseCalendarMacroArgs("__timeGroupDay", []string{"time_column", "'Asia/Tokyo'"})
+	require.NoError(t, err)
+	require.Equal(t, "Asia/Tokyo", tz)
+
+	_, err = ParseCalendarMacroArgs("__timeGroupDay", []string{""})
+	require.Error(t, err)
+
+	bucket, ok := CalendarBucketFromMacro("__timeGroupWeek")
+	require.True(t, ok)
+	require.Equal(t, CalendarBucketWeek, bucket)
+
+	_, ok = CalendarBucketFromMacro("__timeGroup")
+	require.False(t, ok)
+}
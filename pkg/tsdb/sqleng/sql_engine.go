@@ -101,6 +101,9 @@ type DataPluginConfiguration struct {
 	TimeColumnNames   []string
 	MetricColumnTypes []string
 	RowLimit          int64
+	// InferFieldTypes makes the frame field types depend on the returned values instead of the
+	// column types, for dynamically typed databases such as SQLite.
+	InferFieldTypes bool
 }
 
 type DataSourceHandler struct {
@@ -113,6 +116,7 @@ type DataSourceHandler struct {
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
 	userError              string
+	inferFieldTypes        bool
 }
 
 type QueryJson struct {
@@ -153,6 +157,7 @@ func NewQueryDataHandler(cfg *setting.Cfg, config DataPluginConfiguration, query
 		dsInfo:                 config.DSInfo,
 		rowLimit:               config.RowLimit,
 		userError:              cfg.UserFacingDefaultError,
+		inferFieldTypes:        config.InferFieldTypes,
 	}
 
 	if len(config.TimeColumnNames) > 0 {
@@ -306,7 +311,11 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 
 	// Convert row.Rows to dataframe
 	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	converters := sqlutil.ToConverters(stringConverters...)
+	if e.inferFieldTypes {
+		converters = append(converters, sqlutil.Converter{Dynamic: true})
+	}
+	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, converters...)
 	if err != nil {
 		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
 		return
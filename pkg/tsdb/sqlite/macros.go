@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+const rsIdentifier = `([_a-zA-Z0-9]+)`
+const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
+
+// dateTimeFormat is the format used by the SQLite date and time functions, e.g. datetime('now').
+const dateTimeFormat = "2006-01-02 15:04:05"
+
+// restrictedRegExp matches statements that could open other database files or change the
+// connection settings, like disabling the query_only pragma.
+var restrictedRegExp = regexp.MustCompile(`(?i)\b(attach|detach|pragma|vacuum|load_extension)\b`)
+
+type sqliteMacroEngine struct {
+	*sqleng.SQLMacroEngineBase
+	userError string
+}
+
+func newSqliteMacroEngine(userError string) sqleng.SQLMacroEngine {
+	return &sqliteMacroEngine{
+		SQLMacroEngineBase: sqleng.NewSQLMacroEngineBase(),
+		userError:          userError,
+	}
+}
+
+func (m *sqliteMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	if restrictedRegExp.MatchString(sql) {
+		logger.Error("attach, detach, pragma, vacuum or load_extension not allowed in query")
+		return "", fmt.Errorf("invalid query - %s", m.userError)
+	}
+
+	rExp, _ := regexp.Compile(sExpr)
+	var macroError error
+
+	sql = m.ReplaceAllStringSubmatchFunc(rExp, sql, func(groups []string) string {
+		args := strings.Split(groups[2], ",")
+		for i, arg := range args {
+			args[i] = strings.Trim(arg, " ")
+		}
+		res, err := m.evaluateMacro(timeRange, query, groups[1], args)
+		if err != nil && macroError == nil {
+			macroError = err
+			return "macro_error()"
+		}
+		return res
+	})
+
+	if macroError != nil {
+		return "", macroError
+	}
+
+	return sql, nil
+}
+
+func (m *sqliteMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string) (string, error) {
+	switch name {
+	case "__time":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s AS \"time\"", args[0]), nil
+	case "__timeEpoch":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s AS \"time\"", epoch(args[0])), nil
+	case "__timeFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.UTC().Format(dateTimeFormat), timeRange.To.UTC().Format(dateTimeFormat)), nil
+	case "__timeFilterExclusive":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= '%s' AND %s < '%s'", args[0], timeRange.From.UTC().Format(dateTimeFormat), args[0], timeRange.To.UTC().Format(dateTimeFormat)), nil
+	case "__timeFrom":
+		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(dateTimeFormat)), nil
+	case "__timeTo":
+		return fmt.Sprintf("'%s'", timeRange.To.UTC().Format(dateTimeFormat)), nil
+	case "__timeGroup":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'"`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		tz, err := sqleng.SetupTimeGroupOptions(query, interval, args[2:])
+		if err != nil {
+			return "", err
+		}
+		if tz != "" {
+			return "", fmt.Errorf("macro %v does not support time zones in SQLite", name)
+		}
+		return fmt.Sprintf("(%s / %.0f) * %.0f", epoch(args[0]), interval.Seconds(), interval.Seconds()), nil
+	case "__timeGroupAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	case "__timeGroupDay", "__timeGroupWeek", "__timeGroupMonth":
+		tz, err := sqleng.ParseCalendarMacroArgs(name, args)
+		if err != nil {
+			return "", err
+		}
+		if tz != "" {
+			return "", fmt.Errorf("macro %v does not support time zones in SQLite", name)
+		}
+		bucket, _ := sqleng.CalendarBucketFromMacro(name)
+		switch bucket {
+		case sqleng.CalendarBucketWeek:
+			// weeks start on Monday
+			return epoch(fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", args[0])), nil
+		case sqleng.CalendarBucketMonth:
+			return epoch(fmt.Sprintf("date(%s, 'start of month')", args[0])), nil
+		default:
+			return epoch(fmt.Sprintf("date(%s)", args[0])), nil
+		}
+	case "__unixEpochFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
+	case "__unixEpochNanoFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().UnixNano(), args[0], timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochNanoFrom":
+		return fmt.Sprintf("%d", timeRange.From.UTC().UnixNano()), nil
+	case "__unixEpochNanoTo":
+		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochGroup":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		if len(args) == 3 {
+			err := sqleng.SetupFillmode(query, interval, args[2])
+			if err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("CAST(%s / %v AS INTEGER) * %v", args[0], interval.Seconds(), interval.Seconds()), nil
+	case "__unixEpochGroupAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	default:
+		return "", fmt.Errorf("unknown macro %q", name)
+	}
+}
+
+// epoch converts a date and time value in one of the SQLite time formats to unix seconds.
+func epoch(column string) string {
+	return fmt.Sprintf("CAST(strftime('%%s', %s) AS INTEGER)", column)
+}
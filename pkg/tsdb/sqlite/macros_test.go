@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	engine := newSqliteMacroEngine("inspect Grafana server log for details")
+	query := &backend.DataQuery{JSON: []byte("{}")}
+
+	t.Run("Given a time range between 2018-04-12 00:00 and 2018-04-12 00:05", func(t *testing.T) {
+		from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+		to := from.Add(5 * time.Minute)
+		timeRange := backend.TimeRange{From: from, To: to}
+
+		t.Run("interpolate __time function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+			require.NoError(t, err)
+
+			require.Equal(t, "select time_column AS \"time\"", sql)
+		})
+
+		t.Run("interpolate __timeEpoch function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__timeEpoch(time_column)")
+			require.NoError(t, err)
+
+			require.Equal(t, "select CAST(strftime('%s', time_column) AS INTEGER) AS \"time\"", sql)
+		})
+
+		t.Run("interpolate __timeFilter function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+			require.NoError(t, err)
+
+			require.Equal(t, "WHERE time_column BETWEEN '2018-04-12 18:00:00' AND '2018-04-12 18:05:00'", sql)
+		})
+
+		t.Run("interpolate __timeFilterExclusive function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilterExclusive(time_column)")
+			require.NoError(t, err)
+
+			require.Equal(t, "WHERE time_column >= '2018-04-12 18:00:00' AND time_column < '2018-04-12 18:05:00'", sql)
+		})
+
+		t.Run("interpolate __timeFrom and __timeTo functions", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__timeFrom(), $__timeTo()")
+			require.NoError(t, err)
+
+			require.Equal(t, "select '2018-04-12 18:00:00', '2018-04-12 18:05:00'", sql)
+		})
+
+		t.Run("interpolate __timeGroup function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m')")
+			require.NoError(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupAlias(time_column,'5m')")
+			require.NoError(t, err)
+
+			require.Equal(t, "GROUP BY (CAST(strftime('%s', time_column) AS INTEGER) / 300) * 300", sql)
+			require.Equal(t, sql+" AS \"time\"", sql2)
+		})
+
+		t.Run("interpolate __timeGroup function with time zone", func(t *testing.T) {
+			_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m', , 'Europe/Berlin')")
+			require.Error(t, err)
+		})
+
+		t.Run("interpolate calendar group functions", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupDay(time_column)")
+			require.NoError(t, err)
+			require.Equal(t, "GROUP BY CAST(strftime('%s', date(time_column)) AS INTEGER)", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupWeek(time_column)")
+			require.NoError(t, err)
+			require.Equal(t, "GROUP BY CAST(strftime('%s', date(time_column, 'weekday 0', '-6 days')) AS INTEGER)", sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupMonth(time_column)")
+			require.NoError(t, err)
+			require.Equal(t, "GROUP BY CAST(strftime('%s', date(time_column, 'start of month')) AS INTEGER)", sql)
+		})
+
+		t.Run("interpolate __unixEpochFilter function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochFilter(time)")
+			require.NoError(t, err)
+
+			require.Equal(t, "select time >= 1523556000 AND time <= 1523556300", sql)
+		})
+
+		t.Run("interpolate __unixEpochGroup function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroupAlias(time_column,'5m')")
+			require.NoError(t, err)
+
+			require.Equal(t, "SELECT CAST(time_column / 300 AS INTEGER) * 300 AS \"time\"", sql)
+		})
+	})
+
+	t.Run("restricted statements are rejected", func(t *testing.T) {
+		for _, sql := range []string{
+			"ATTACH DATABASE '/etc/passwd' AS x",
+			"pragma query_only = false",
+			"VACUUM INTO '/tmp/copy.db'",
+			"select load_extension('x')",
+			"select 1; DETACH x",
+		} {
+			_, err := engine.Interpolate(query, backend.TimeRange{}, sql)
+			require.Error(t, err, sql)
+		}
+
+		sql, err := engine.Interpolate(query, backend.TimeRange{}, "SELECT name FROM pragma_table_info('metrics')")
+		require.NoError(t, err)
+		require.Equal(t, "SELECT name FROM pragma_table_info('metrics')", sql)
+	})
+}
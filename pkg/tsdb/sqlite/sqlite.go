@@ -0,0 +1,173 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/gobwas/glob"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+	// register the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+var logger = log.New("tsdb.sqlite")
+
+var (
+	errPathNotAbsolute = errors.New("database path must be absolute")
+	errPathNotAllowed  = errors.New("database path is not in the list of allowed SQLite paths, see sqlite_allowed_paths in the [sql_datasources] configuration section")
+)
+
+type Service struct {
+	im instancemgmt.InstanceManager
+}
+
+func ProvideService(cfg *setting.Cfg) (*Service, error) {
+	allowedPaths := make([]glob.Glob, 0, len(cfg.SqliteDatasourceAllowedPaths))
+	for _, pattern := range cfg.SqliteDatasourceAllowedPaths {
+		g, err := glob.Compile(filepath.Clean(pattern), filepath.Separator)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sqlite_allowed_paths pattern %q: %w", pattern, err)
+		}
+		allowedPaths = append(allowedPaths, g)
+	}
+
+	return &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(cfg, allowedPaths)),
+	}, nil
+}
+
+func newInstanceSettings(cfg *setting.Cfg, allowedPaths []glob.Glob) datasource.InstanceFactoryFunc {
+	return func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    cfg.SqlDatasourceMaxOpenConnsDefault,
+			MaxIdleConns:    cfg.SqlDatasourceMaxIdleConnsDefault,
+			ConnMaxLifetime: cfg.SqlDatasourceMaxConnLifetimeDefault,
+		}
+
+		err := json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+
+		database := jsonData.Database
+		if database == "" {
+			database = settings.Database
+		}
+
+		path, err := allowedPath(database, allowedPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData:                jsonData,
+			Database:                path,
+			ID:                      settings.ID,
+			Updated:                 settings.Updated,
+			UID:                     settings.UID,
+			DecryptedSecureJSONData: settings.DecryptedSecureJSONData,
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DriverName:        "sqlite3",
+			ConnectionString:  connectionString(path),
+			DSInfo:            dsInfo,
+			MetricColumnTypes: []string{"TEXT", "VARCHAR", "CHAR", "CLOB"},
+			RowLimit:          cfg.DataProxyRowLimit,
+			InferFieldTypes:   true,
+		}
+
+		return sqleng.NewQueryDataHandler(cfg, config, &sqliteQueryResultTransformer{}, newSqliteMacroEngine(cfg.UserFacingDefaultError), logger)
+	}
+}
+
+// allowedPath resolves the database path and checks it against the configured allow-list.
+// Symbolic links are resolved first, so a link inside an allowed directory cannot point outside of it.
+func allowedPath(path string, allowedPaths []glob.Glob) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", errPathNotAbsolute
+	}
+
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	for _, g := range allowedPaths {
+		if g.Match(path) {
+			return path, nil
+		}
+	}
+
+	return "", errPathNotAllowed
+}
+
+// connectionString opens the database file in read-only mode. The query_only pragma additionally
+// rejects statements that would modify the database, e.g. through a writable journal.
+func connectionString(path string) string {
+	u := url.URL{Scheme: "file", Path: path}
+	return u.String() + "?mode=ro&_query_only=true&cache=private"
+}
+
+func (s *Service) getDataSourceHandler(ctx context.Context, pluginCtx backend.PluginContext) (*sqleng.DataSourceHandler, error) {
+	i, err := s.im.Get(ctx, pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+	instance := i.(*sqleng.DataSourceHandler)
+	return instance, nil
+}
+
+// CheckHealth opens the SQLite database file
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		if errors.Is(err, errPathNotAbsolute) || errors.Is(err, errPathNotAllowed) {
+			return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}, nil
+		}
+		return nil, err
+	}
+
+	err = dsHandler.Ping()
+
+	if err != nil {
+		logger.Error("Check health failed", "error", err)
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: dsHandler.TransformQueryError(logger, err).Error()}, nil
+	}
+
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Database Connection OK"}, nil
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return dsHandler.QueryData(ctx, req)
+}
+
+type sqliteQueryResultTransformer struct{}
+
+func (t *sqliteQueryResultTransformer) TransformQueryError(_ log.Logger, err error) error {
+	return err
+}
+
+// GetConverterList returns no converters, SQLite columns are dynamically typed so the field types
+// are inferred from the returned values instead.
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	return nil
+}
@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+func TestAllowedPath(t *testing.T) {
+	dir := t.TempDir()
+	allowed := []glob.Glob{glob.MustCompile(filepath.Join(dir, "allowed", "*.db"), filepath.Separator)}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "allowed"), 0750))
+
+	t.Run("allowed path", func(t *testing.T) {
+		path, err := allowedPath(filepath.Join(dir, "allowed", "metrics.db"), allowed)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "allowed", "metrics.db"), path)
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		_, err := allowedPath("metrics.db", allowed)
+		require.ErrorIs(t, err, errPathNotAbsolute)
+	})
+
+	t.Run("path outside of the allowed directory", func(t *testing.T) {
+		_, err := allowedPath(filepath.Join(dir, "allowed", "..", "metrics.db"), allowed)
+		require.ErrorIs(t, err, errPathNotAllowed)
+
+		_, err = allowedPath(filepath.Join(dir, "allowed", "nested", "metrics.db"), allowed)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("symbolic link pointing outside of the allowed directory", func(t *testing.T) {
+		target := filepath.Join(dir, "secret.db")
+		require.NoError(t, os.WriteFile(target, nil, 0600))
+		link := filepath.Join(dir, "allowed", "link.db")
+		require.NoError(t, os.Symlink(target, link))
+
+		_, err := allowedPath(link, allowed)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("no allowed paths", func(t *testing.T) {
+		_, err := allowedPath(filepath.Join(dir, "allowed", "metrics.db"), nil)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.db")
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE metrics (ts DATETIME, host TEXT, value REAL);
+		INSERT INTO metrics VALUES ('2018-04-12 18:00:00', 'a', 1.5), ('2018-04-12 18:01:00', 'b', 2), ('2018-04-12 18:10:00', 'a', 3);`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	cfg := setting.NewCfg()
+	cfg.SqliteDatasourceAllowedPaths = []string{filepath.Join(dir, "*.db")}
+	cfg.DataProxyRowLimit = 1000
+	svc, err := ProvideService(cfg)
+	require.NoError(t, err)
+
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:       1,
+			JSONData: []byte(fmt.Sprintf(`{"database": %q}`, path)),
+		},
+	}
+
+	t.Run("check health", func(t *testing.T) {
+		res, err := svc.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginCtx})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("check health with a path that is not allowed", func(t *testing.T) {
+		res, err := svc.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				ID:       2,
+				JSONData: []byte(`{"database": "/etc/grafana/grafana.db"}`),
+			},
+		}})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+	})
+
+	query := func(t *testing.T, rawSQL string, format string) backend.DataResponse {
+		t.Helper()
+		from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+		res, err := svc.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx,
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      []byte(fmt.Sprintf(`{"rawSql": %q, "format": %q}`, rawSQL, format)),
+				TimeRange: backend.TimeRange{From: from, To: from.Add(5 * time.Minute)},
+			}},
+		})
+		require.NoError(t, err)
+		return res.Responses["A"]
+	}
+
+	t.Run("time series query", func(t *testing.T) {
+		res := query(t, `SELECT $__timeEpoch(ts), avg(value) AS value FROM metrics WHERE $__timeFilter(ts) GROUP BY 1 ORDER BY 1`, "time_series")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, time.Date(2018, 4, 12, 18, 1, 0, 0, time.UTC).Unix(), frame.Fields[0].At(1).(*time.Time).Unix())
+		require.Equal(t, 2.0, *frame.Fields[1].At(1).(*float64))
+	})
+
+	t.Run("table query", func(t *testing.T) {
+		res := query(t, `SELECT host, count(*) AS count FROM metrics GROUP BY host ORDER BY host`, "table")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "a", *frame.Fields[0].At(0).(*string))
+		require.Equal(t, 2.0, *frame.Fields[1].At(0).(*float64))
+	})
+
+	t.Run("writes are rejected", func(t *testing.T) {
+		query(t, `DELETE FROM metrics`, "table")
+
+		res := query(t, `SELECT count(*) AS count FROM metrics`, "table")
+		require.NoError(t, res.Error)
+		require.Equal(t, 3.0, *res.Frames[0].Fields[0].At(0).(*float64))
+	})
+}
+
+var _ sqleng.SQLMacroEngine = &sqliteMacroEngine{}
@@ -0,0 +1,147 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// FrameTypeHeatmapCells is the frame type of the frames created for native (sparse) histograms.
+// Each row is a single bucket of the histogram sample at xMax, see https://grafana.com/developers/dataplane/heatmap.
+const FrameTypeHeatmapCells data.FrameType = "heatmap-cells"
+
+// BucketLabel is the label holding the upper bound of a bucket, matching the classic Prometheus histograms.
+const BucketLabel = "le"
+
+// IsHeatmapCellsFrame returns true when the frame holds native histogram samples.
+func IsHeatmapCellsFrame(frame *data.Frame) bool {
+	return frame != nil && frame.Meta != nil && frame.Meta.Type == FrameTypeHeatmapCells
+}
+
+type histogramCell struct {
+	yMax  float64
+	count float64
+}
+
+// HeatmapCellsToBuckets converts a heatmap-cells frame into one time series frame per bucket upper bound,
+// holding the cumulative count of all the cells up to that bound, plus a "+Inf" bucket with the total count.
+// The series are labeled with "le" like classic Prometheus histogram buckets, so they can be used the same
+// way, e.g. with the heatmap format of the Prometheus query editor or in server side expressions.
+//
+// Native histograms do not have fixed bucket boundaries, so the buckets are the union of the upper bounds of
+// all the samples.
+func HeatmapCellsToBuckets(frame *data.Frame) ([]*data.Frame, error) {
+	xMax, err := heatmapField(frame, "xMax", data.FieldTypeTime)
+	if err != nil {
+		return nil, err
+	}
+	yMin, err := heatmapField(frame, "yMin", data.FieldTypeFloat64)
+	if err != nil {
+		return nil, err
+	}
+	yMax, err := heatmapField(frame, "yMax", data.FieldTypeFloat64)
+	if err != nil {
+		return nil, err
+	}
+	count, err := heatmapField(frame, "count", data.FieldTypeFloat64)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cells of a sample are stored in consecutive rows
+	var (
+		times  []time.Time
+		cells  [][]histogramCell
+		bounds = map[float64]struct{}{}
+	)
+	for i := 0; i < frame.Rows(); i++ {
+		t := xMax.At(i).(time.Time)
+		if len(times) == 0 || !times[len(times)-1].Equal(t) {
+			times = append(times, t)
+			cells = append(cells, nil)
+		}
+		cell := histogramCell{yMax: yMax.At(i).(float64), count: count.At(i).(float64)}
+		cells[len(cells)-1] = append(cells[len(cells)-1], cell)
+		bounds[cell.yMax] = struct{}{}
+	}
+
+	les := make([]float64, 0, len(bounds)+1)
+	for le := range bounds {
+		if !math.IsInf(le, 1) {
+			les = append(les, le)
+		}
+	}
+	sort.Float64s(les)
+	les = append(les, math.Inf(1))
+
+	values := make([][]float64, len(les))
+	for i := range values {
+		values[i] = make([]float64, len(times))
+	}
+	for ti, sample := range cells {
+		sort.Slice(sample, func(i, j int) bool { return sample[i].yMax < sample[j].yMax })
+		var (
+			cumulative float64
+			ci         int
+		)
+		for li, le := range les {
+			for ci < len(sample) && sample[ci].yMax <= le {
+				cumulative += sample[ci].count
+				ci++
+			}
+			values[li][ti] = cumulative
+		}
+	}
+
+	meta := frame.Meta
+	if meta == nil {
+		meta = &data.FrameMeta{}
+	}
+
+	frames := make([]*data.Frame, 0, len(les))
+	for li, le := range les {
+		timeField := data.NewField(data.TimeSeriesTimeFieldName, nil, times)
+		if xMax.Config != nil {
+			timeField.Config = &data.FieldConfig{Interval: xMax.Config.Interval}
+		}
+		labels := yMin.Labels.Copy()
+		if labels == nil {
+			labels = data.Labels{}
+		}
+		labels[BucketLabel] = formatBucketBound(le)
+		valueField := data.NewField(data.TimeSeriesValueFieldName, labels, values[li])
+
+		bucket := data.NewFrame(frame.Name, timeField, valueField)
+		bucket.RefID = frame.RefID
+		bucket.Meta = &data.FrameMeta{
+			Type:                data.FrameTypeTimeSeriesMulti,
+			ExecutedQueryString: meta.ExecutedQueryString,
+			Custom:              meta.Custom,
+		}
+		frames = append(frames, bucket)
+	}
+	return frames, nil
+}
+
+func heatmapField(frame *data.Frame, name string, fieldType data.FieldType) (*data.Field, error) {
+	field, _ := frame.FieldByName(name)
+	if field == nil {
+		return nil, fmt.Errorf("heatmap-cells frame is missing the %s field", name)
+	}
+	if field.Type() != fieldType {
+		return nil, fmt.Errorf("heatmap-cells frame field %s has type %s, expected %s", name, field.Type(), fieldType)
+	}
+	return field, nil
+}
+
+// formatBucketBound formats the bound the same way Prometheus formats the le label of classic histograms.
+func formatBucketBound(le float64) string {
+	if math.IsInf(le, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(le, 'g', -1, 64)
+}
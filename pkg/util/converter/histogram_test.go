@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeatmapCellsToBuckets(t *testing.T) {
+	t1 := time.Unix(1649963300, 0).UTC()
+	t2 := time.Unix(1649963310, 0).UTC()
+
+	yMin := data.NewField("yMin", data.Labels{"job": "api"}, []float64{0.5, 1, 2, 0.5, 2})
+	frame := data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{t1, t1, t1, t2, t2}),
+		yMin,
+		data.NewField("yMax", nil, []float64{1, 2, 4, 1, 4}),
+		data.NewField("count", nil, []float64{3, 5, 2, 1, 7}),
+		data.NewField("yLayout", nil, []int8{0, 0, 0, 0, 0}),
+	)
+	frame.Meta = &data.FrameMeta{Type: FrameTypeHeatmapCells}
+	require.True(t, IsHeatmapCellsFrame(frame))
+
+	buckets, err := HeatmapCellsToBuckets(frame)
+	require.NoError(t, err)
+	require.Len(t, buckets, 4)
+
+	expected := []struct {
+		le     string
+		values []float64
+	}{
+		{le: "1", values: []float64{3, 1}},
+		{le: "2", values: []float64{8, 1}},
+		{le: "4", values: []float64{10, 8}},
+		{le: "+Inf", values: []float64{10, 8}},
+	}
+	for i, e := range expected {
+		bucket := buckets[i]
+		require.Equal(t, data.FrameTypeTimeSeriesMulti, bucket.Meta.Type)
+		require.Equal(t, data.Labels{"job": "api", "le": e.le}, bucket.Fields[1].Labels)
+		require.Equal(t, []time.Time{t1, t2}, []time.Time{bucket.Fields[0].At(0).(time.Time), bucket.Fields[0].At(1).(time.Time)})
+		require.Equal(t, e.values, []float64{bucket.Fields[1].At(0).(float64), bucket.Fields[1].At(1).(float64)})
+	}
+}
+
+func TestHeatmapCellsToBuckets_FromPrometheus(t *testing.T) {
+	// nolint:gosec
+	f, err := os.Open(path.Join("testdata", "prom-matrix-histogram-no-labels.json"))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	rsp := ReadPrometheusStyleResult(jsoniter.Parse(jsoniter.ConfigDefault, f, 1024), Options{})
+	require.NoError(t, rsp.Error)
+	require.Len(t, rsp.Frames, 1)
+	require.True(t, IsHeatmapCellsFrame(rsp.Frames[0]))
+
+	buckets, err := HeatmapCellsToBuckets(rsp.Frames[0])
+	require.NoError(t, err)
+	require.NotEmpty(t, buckets)
+
+	// the buckets are cumulative, so the counts never decrease with the upper bound
+	inf := buckets[len(buckets)-1]
+	require.Equal(t, "+Inf", inf.Fields[1].Labels[BucketLabel])
+	for row := 0; row < inf.Rows(); row++ {
+		prev := 0.0
+		for _, bucket := range buckets {
+			v := bucket.Fields[1].At(row).(float64)
+			require.GreaterOrEqual(t, v, prev)
+			prev = v
+		}
+	}
+}
+
+func TestHeatmapCellsToBuckets_MissingField(t *testing.T) {
+	frame := data.NewFrame("", data.NewField("xMax", nil, []time.Time{}))
+	_, err := HeatmapCellsToBuckets(frame)
+	require.ErrorContains(t, err, "missing the yMin field")
+}
@@ -28,6 +28,12 @@ jest.mock('@grafana/runtime', () => ({
       getInstanceSettings: (ref?: DataSourceRef) => ({ type: ref?.type ?? '?', uid: ref?.uid ?? '?' }),
     };
   },
+  getTemplateSrv: () => ({
+    getVariables: () => [
+      { name: 'env', type: 'custom', current: { text: 'staging', value: 'staging' } },
+      { name: 'hosts', type: 'custom', current: { text: 'a + b', value: ['a', 'b'] } },
+    ],
+  }),
 }));
 
 describe('PublicDashboardDatasource', () => {
@@ -101,6 +107,7 @@ describe('PublicDashboardDatasource', () => {
 
     expect(mock.calls.length).toBe(1);
     expect(mock.lastCall[0].url).toEqual(`/api/public/dashboards/abc123/panels/${panelId}/query`);
+    expect(mock.lastCall[0].data.variables).toEqual({ env: 'staging' });
   });
 
   test('returns public datasource uid when datasource passed in is null', () => {
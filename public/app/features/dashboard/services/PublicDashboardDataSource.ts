@@ -12,7 +12,13 @@ import {
   DataSourceRef,
   toDataFrame,
 } from '@grafana/data';
-import { BackendDataSourceResponse, config, getBackendSrv, toDataQueryResponse } from '@grafana/runtime';
+import {
+  BackendDataSourceResponse,
+  config,
+  getBackendSrv,
+  getTemplateSrv,
+  toDataQueryResponse,
+} from '@grafana/runtime';
 
 import { GrafanaQueryType } from '../../../plugins/datasource/grafana/types';
 import { MIXED_DATASOURCE_NAME } from '../../../plugins/datasource/mixed/MixedDataSource';
@@ -115,6 +121,7 @@ export class PublicDashboardDataSource extends DataSourceApi<DataQuery, DataSour
           to: toRange.valueOf().toString(),
           timezone: this.getBrowserTimezone(),
         },
+        variables: this.getVariableValues(),
       };
 
       return getBackendSrv()
@@ -157,6 +164,18 @@ export class PublicDashboardDataSource extends DataSourceApi<DataQuery, DataSour
     return Promise.resolve({ message: '', status: '' });
   }
 
+  // Current values of the template variables, the values of the variables viewers cannot change are ignored
+  getVariableValues(): Record<string, string> {
+    const values: Record<string, string> = {};
+    for (const variable of getTemplateSrv().getVariables()) {
+      const value = 'current' in variable ? variable.current?.value : undefined;
+      if (typeof value === 'string') {
+        values[variable.name] = value;
+      }
+    }
+    return values;
+  }
+
   // Try to get the browser timezone otherwise return blank
   getBrowserTimezone(): string {
     return window.Intl?.DateTimeFormat().resolvedOptions()?.timeZone || '';
@@ -15,7 +15,13 @@ jest.mock('./api', () => ({
     uid: 'xyz',
     items: [
       { type: 'dashboard_by_uid', value: 'aaa' },
-      { type: 'dashboard_by_uid', value: 'bbb' },
+      {
+        type: 'dashboard_by_uid',
+        value: 'bbb',
+        interval: '2s',
+        timeRange: { from: 'now-24h', to: 'now' },
+        variables: { host: ['a', 'b'] },
+      },
     ],
   } as Playlist),
   loadDashboards: (items: PlaylistItem[]) => {
@@ -137,4 +143,18 @@ describe('PlaylistSrv', () => {
     expect((srv as any).validPlaylistUrl).toBe('/url/to/bbb');
     expect(srv.isPlaying).toBe(true);
   });
+
+  it('shows each dashboard with the settings of its item', async () => {
+    const setTimeoutSpy = jest.spyOn(global, 'setTimeout');
+    await srv.start('foo');
+
+    expect(setTimeoutSpy).toHaveBeenLastCalledWith(expect.any(Function), 1000);
+    expect(locationService.getSearchObject()).toEqual({});
+
+    srv.next();
+
+    expect(setTimeoutSpy).toHaveBeenLastCalledWith(expect.any(Function), 2000);
+    expect(locationService.getSearchObject()).toEqual({ from: 'now-24h', to: 'now', 'var-host': ['a', 'b'] });
+    setTimeoutSpy.mockRestore();
+  });
 });
@@ -1,10 +1,11 @@
 import { Location } from 'history';
 import { pickBy } from 'lodash';
 
-import { locationUtil, urlUtil, rangeUtil } from '@grafana/data';
+import { locationUtil, urlUtil, rangeUtil, UrlQueryMap } from '@grafana/data';
 import { locationService } from '@grafana/runtime';
 
 import { getPlaylist, loadDashboards } from './api';
+import { PlaylistItem } from './types';
 
 export const queryParamsToPreserve: { [key: string]: boolean } = {
   kiosk: true,
@@ -12,11 +13,17 @@ export const queryParamsToPreserve: { [key: string]: boolean } = {
   orgId: true,
 };
 
+// A dashboard of the playlist, with the interval, the time range and the variables of the item that lists it
+interface PlaylistEntry {
+  url: string;
+  interval: number;
+  params: UrlQueryMap;
+}
+
 export class PlaylistSrv {
   private nextTimeoutId: ReturnType<typeof setTimeout> | undefined;
-  private urls: string[] = []; // the URLs we need to load
+  private entries: PlaylistEntry[] = []; // the dashboards we need to load
   private index = 0;
-  private declare interval: number;
   private declare startUrl: string;
   private numberOfLoops = 0;
   private declare validPlaylistUrl: string;
@@ -31,7 +38,7 @@ export class PlaylistSrv {
   next() {
     clearTimeout(this.nextTimeoutId);
 
-    const playedAllDashboards = this.index > this.urls.length - 1;
+    const playedAllDashboards = this.index > this.entries.length - 1;
     if (playedAllDashboards) {
       this.numberOfLoops++;
 
@@ -44,16 +51,16 @@ export class PlaylistSrv {
       this.index = 0;
     }
 
-    const url = this.urls[this.index];
+    const entry = this.entries[this.index];
     const queryParams = locationService.getSearchObject();
     const filteredParams = pickBy(queryParams, (value: unknown, key: string) => queryParamsToPreserve[key]);
-    const nextDashboardUrl = locationUtil.stripBaseFromUrl(url);
+    const nextDashboardUrl = locationUtil.stripBaseFromUrl(entry.url);
 
     this.index++;
     this.validPlaylistUrl = nextDashboardUrl;
-    this.nextTimeoutId = setTimeout(() => this.next(), this.interval);
+    this.nextTimeoutId = setTimeout(() => this.next(), entry.interval);
 
-    locationService.push(nextDashboardUrl + '?' + urlUtil.toUrlParams(filteredParams));
+    locationService.push(nextDashboardUrl + '?' + urlUtil.toUrlParams({ ...filteredParams, ...entry.params }));
   }
 
   prev() {
@@ -78,28 +85,30 @@ export class PlaylistSrv {
     // setup location tracking
     this.locationListenerUnsub = locationService.getHistory().listen(this.locationUpdated);
 
-    const urls: string[] = [];
+    const entries: PlaylistEntry[] = [];
     let playlist = await getPlaylist(playlistUid);
     if (!playlist.items?.length) {
       // alert
       return;
     }
-    this.interval = rangeUtil.intervalToMs(playlist.interval);
 
+    // Each item is handled on its own, so that a dashboard listed by several items is shown with the settings of each
     const items = await loadDashboards(playlist.items);
     for (const item of items) {
       if (item.dashboards) {
+        const interval = rangeUtil.intervalToMs(item.interval || playlist.interval);
+        const params = getItemParams(item);
         for (const dash of item.dashboards) {
-          urls.push(dash.url);
+          entries.push({ url: dash.url, interval, params });
         }
       }
     }
 
-    if (!urls.length) {
+    if (!entries.length) {
       // alert... not found, etc
       return;
     }
-    this.urls = urls;
+    this.entries = entries;
     this.isPlaying = true;
     this.next();
     return;
@@ -127,4 +136,17 @@ export class PlaylistSrv {
   }
 }
 
+// Returns the URL parameters of the time range and the variables of a playlist item
+function getItemParams(item: PlaylistItem): UrlQueryMap {
+  const params: UrlQueryMap = {};
+  if (item.timeRange) {
+    params.from = item.timeRange.from;
+    params.to = item.timeRange.to;
+  }
+  for (const [name, values] of Object.entries(item.variables ?? {})) {
+    params[`var-${name}`] = values;
+  }
+  return params;
+}
+
 export const playlistSrv = new PlaylistSrv();
@@ -35,6 +35,8 @@ composableKinds: DataQuery: {
 				range?: bool
 				// Execute an additional query to identify interesting raw samples relevant for the given expr
 				exemplar?: bool
+				// Sampling applied to the exemplars of the query to reduce their density
+				exemplarSampling?: #ExemplarSampling
 				// Specifies which editor is being used to prepare the query. It can be "code" or "builder"
 				editorMode?: #QueryEditorMode
 				// Query format to determine how to display data points in panel. It can be "time_series", "table", "heatmap"
@@ -47,6 +49,15 @@ composableKinds: DataQuery: {
 
 				#QueryEditorMode: "code" | "builder"                  @cuetsy(kind="enum")
 				#PromQueryFormat: "time_series" | "table" | "heatmap" @cuetsy(kind="type")
+				#ExemplarSamplingStrategy: "stddev" | "uniform" | "outlier" | "none" @cuetsy(kind="enum")
+
+				#ExemplarSampling: {
+					// Strategy used to sample the exemplars, "stddev" keeps the exemplars at least two standard deviations apart,
+					// "uniform" keeps one exemplar per time interval, "outlier" keeps the exemplars furthest from the mean
+					strategy?: #ExemplarSamplingStrategy
+					// Maximum number of exemplars returned for the query, no limit when not set
+					maxExemplars?: int64
+				} @cuetsy(kind="interface")
 			}
 		}]
 		lenses: []
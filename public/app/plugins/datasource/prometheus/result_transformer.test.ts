@@ -942,6 +942,63 @@ describe('Prometheus Result Transformer', () => {
       expect(traceField).toBeDefined();
       expect(traceField!.config.links?.length).toBe(0);
     });
+
+    it('should add the data source name to exemplar links computed by the backend', () => {
+      const response = {
+        state: 'Done',
+        data: [
+          createDataFrame({
+            refId: 'A',
+            name: 'exemplar',
+            meta: {
+              custom: {
+                resultType: 'exemplar',
+              },
+            },
+            fields: [
+              { name: 'Time', type: FieldType.time, values: [6, 5] },
+              { name: 'Value', type: FieldType.number, values: [30, 10] },
+              {
+                name: 'traceID',
+                type: FieldType.string,
+                values: ['abc', 'def'],
+                config: {
+                  links: [
+                    {
+                      title: '',
+                      url: '',
+                      internal: { query: { query: '${__value.raw}', queryType: 'traceql' }, datasourceUid: 'Tempo' },
+                    },
+                  ],
+                },
+              },
+            ],
+          }),
+        ],
+      } as unknown as DataQueryResponse;
+      const request = {
+        targets: [
+          {
+            format: 'time_series',
+            refId: 'A',
+          },
+        ],
+      } as unknown as DataQueryRequest<PromQuery>;
+      const testOptions: any = {
+        exemplarTraceIdDestinations: [
+          {
+            name: 'traceID',
+            datasourceUid: 'Tempo',
+          },
+        ],
+      };
+
+      const series = transformV2(response, request, testOptions);
+      const traceField = series.data[0].fields.find((f) => f.name === 'traceID');
+      expect(traceField!.config.links).toHaveLength(1);
+      expect(traceField!.config.links![0].title).toBe('Query with Tempo');
+      expect(traceField!.config.links![0].internal?.datasourceName).toBe('Tempo');
+    });
   });
 
   describe('transformDFToTable', () => {
@@ -99,8 +99,16 @@ export function transformV2(
   const { exemplarTraceIdDestinations: destinations } = options;
   const processedExemplarFrames = exemplarFrames.map((dataFrame) => {
     if (destinations?.length) {
+      // Links computed by the backend only need the name of the linked data source
+      const fieldsWithLinks = new Set(
+        dataFrame.fields.filter((field) => field.config.links?.length).map((field) => field.name)
+      );
       for (const exemplarTraceIdDestination of destinations) {
         const traceIDField = dataFrame.fields.find((field) => field.name === exemplarTraceIdDestination.name);
+        if (traceIDField && fieldsWithLinks.has(traceIDField.name)) {
+          traceIDField.config.links = traceIDField.config.links?.map(withDataSourceName);
+          continue;
+        }
         if (traceIDField) {
           const links = getDataLinks(exemplarTraceIdDestination);
           traceIDField.config.links = traceIDField.config.links?.length
@@ -314,6 +322,11 @@ export function transform(
     if (transformOptions.exemplarTraceIdDestinations?.length) {
       for (const exemplarTraceIdDestination of transformOptions.exemplarTraceIdDestinations) {
         const traceIDField = dataFrame.fields.find((field) => field.name === exemplarTraceIdDestination.name);
+        // Links computed by the backend only need the name of the linked data source
+        if (traceIDField?.config.links?.length && dataFrame.meta?.custom?.traceLinks) {
+          traceIDField.config.links = traceIDField.config.links.map(withDataSourceName);
+          continue;
+        }
         if (traceIDField) {
           const links = getDataLinks(exemplarTraceIdDestination);
           traceIDField.config.links = traceIDField.config.links?.length
@@ -359,6 +372,18 @@ export function transform(
   return dataFrame;
 }
 
+function withDataSourceName(link: DataLink): DataLink {
+  if (!link.internal || link.internal.datasourceName) {
+    return link;
+  }
+  const dsSettings = getDataSourceSrv().getInstanceSettings(link.internal.datasourceUid);
+  return {
+    ...link,
+    title: link.title || `Query with ${dsSettings?.name}`,
+    internal: { ...link.internal, datasourceName: dsSettings?.name ?? 'Data source not found' },
+  };
+}
+
 function getDataLinks(options: ExemplarTraceIdDestination): DataLink[] {
   const dataLinks: DataLink[] = [];
 
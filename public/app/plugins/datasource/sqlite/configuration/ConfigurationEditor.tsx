@@ -0,0 +1,66 @@
+import React from 'react';
+
+import { DataSourcePluginOptionsEditorProps, onUpdateDatasourceJsonDataOption } from '@grafana/data';
+import { Alert, FieldSet, InlineField, Input, Link } from '@grafana/ui';
+import { ConnectionLimits } from 'app/features/plugins/sql/components/configuration/ConnectionLimits';
+
+import { SQLiteOptions } from '../types';
+
+export const ConfigurationEditor = (props: DataSourcePluginOptionsEditorProps<SQLiteOptions>) => {
+  const { options, onOptionsChange } = props;
+  const jsonData = options.jsonData;
+
+  const WIDTH_SHORT = 15;
+  const WIDTH_MEDIUM = 25;
+  const WIDTH_LONG = 40;
+
+  return (
+    <>
+      <FieldSet label="SQLite Connection" width={400}>
+        <InlineField
+          labelWidth={WIDTH_SHORT}
+          label="Path"
+          tooltip="Absolute path of the database file on the Grafana server. The path must be allowed by the sqlite_allowed_paths setting."
+        >
+          <Input
+            width={WIDTH_LONG}
+            name="database"
+            value={jsonData.database || ''}
+            placeholder="/var/lib/grafana/sqlite/metrics.db"
+            onChange={onUpdateDatasourceJsonDataOption(props, 'database')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <ConnectionLimits labelWidth={WIDTH_SHORT} options={options} onOptionsChange={onOptionsChange} />
+
+      <FieldSet label="SQLite details">
+        <InlineField
+          tooltip={
+            <span>
+              A lower limit for the auto group by time interval. Recommended to be set to write frequency, for example
+              <code>1m</code> if your data is written every minute.
+            </span>
+          }
+          labelWidth={WIDTH_MEDIUM}
+          label="Min time interval"
+        >
+          <Input
+            placeholder="1m"
+            value={jsonData.timeInterval || ''}
+            onChange={onUpdateDatasourceJsonDataOption(props, 'timeInterval')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <Alert title="Read-only access" severity="info">
+        The database file is opened in read-only mode, and statements that open other database files or change the
+        connection settings, like <code>ATTACH</code> and <code>PRAGMA</code>, are rejected. Check out the{' '}
+        <Link rel="noreferrer" target="_blank" href="http://docs.grafana.org/features/datasources/sqlite/">
+          SQLite Data Source Docs
+        </Link>{' '}
+        for more information.
+      </Alert>
+    </>
+  );
+};
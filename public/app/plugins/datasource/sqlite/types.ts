@@ -0,0 +1,5 @@
+import { SQLOptions, SQLQuery } from 'app/features/plugins/sql/types';
+
+export interface SQLiteOptions extends SQLOptions {}
+
+export interface SQLiteQuery extends SQLQuery {}